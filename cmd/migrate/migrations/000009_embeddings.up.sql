@@ -0,0 +1,8 @@
+CREATE TABLE embeddings (
+  agent_id UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+  model TEXT NOT NULL,
+  input_hash TEXT NOT NULL,
+  vector JSONB NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  PRIMARY KEY (agent_id, model, input_hash)
+);
@@ -0,0 +1,10 @@
+ALTER TABLE images DROP CONSTRAINT IF EXISTS images_render_options_key;
+
+ALTER TABLE images
+  DROP COLUMN IF EXISTS threshold,
+  DROP COLUMN IF EXISTS grayscale;
+
+ALTER TABLE images
+  ADD CONSTRAINT images_render_options_key
+  UNIQUE(document_id, page_number, format, dpi, quality,
+         brightness, contrast, saturation, rotation, background);
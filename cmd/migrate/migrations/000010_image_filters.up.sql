@@ -0,0 +1,24 @@
+DO $$
+DECLARE
+  existing TEXT;
+BEGIN
+  SELECT conname INTO existing
+  FROM pg_constraint
+  WHERE conrelid = 'images'::regclass
+    AND contype = 'u'
+    AND array_length(conkey, 1) > 1;
+
+  IF existing IS NOT NULL THEN
+    EXECUTE format('ALTER TABLE images DROP CONSTRAINT %I', existing);
+  END IF;
+END $$;
+
+ALTER TABLE images
+  ADD COLUMN grayscale BOOLEAN,
+  ADD COLUMN threshold INTEGER;
+
+ALTER TABLE images
+  ADD CONSTRAINT images_render_options_key
+  UNIQUE(document_id, page_number, format, dpi, quality,
+         brightness, contrast, saturation, rotation, background,
+         grayscale, threshold);
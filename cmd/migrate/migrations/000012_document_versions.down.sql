@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS document_versions;
+
+ALTER TABLE documents DROP COLUMN IF EXISTS version;
@@ -0,0 +1,18 @@
+ALTER TABLE documents
+  ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE document_versions (
+  document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+  version INTEGER NOT NULL,
+  filename TEXT NOT NULL,
+  content_type TEXT NOT NULL,
+  size_bytes BIGINT NOT NULL,
+  page_count INTEGER,
+  storage_key TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  PRIMARY KEY (document_id, version)
+);
+
+INSERT INTO document_versions (document_id, version, filename, content_type, size_bytes, page_count, storage_key, created_at)
+SELECT id, version, filename, content_type, size_bytes, page_count, storage_key, created_at
+FROM documents;
@@ -0,0 +1,11 @@
+CREATE TABLE checkpoint_history (
+  run_id TEXT NOT NULL,
+  checkpoint_node TEXT NOT NULL,
+  state_data JSONB NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  PRIMARY KEY (run_id, checkpoint_node)
+);
+
+INSERT INTO checkpoint_history (run_id, checkpoint_node, state_data, created_at, updated_at)
+SELECT run_id, checkpoint_node, state_data, created_at, updated_at FROM checkpoints;
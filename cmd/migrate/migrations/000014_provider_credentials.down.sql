@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_agents_provider_id;
+
+ALTER TABLE agents DROP COLUMN IF EXISTS provider_id;
+
+ALTER TABLE providers DROP COLUMN IF EXISTS credentials;
@@ -0,0 +1,7 @@
+ALTER TABLE providers
+  ADD COLUMN credentials JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+ALTER TABLE agents
+  ADD COLUMN provider_id UUID REFERENCES providers(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_agents_provider_id ON agents(provider_id);
@@ -0,0 +1,3 @@
+ALTER TABLE images
+  DROP COLUMN IF EXISTS notes,
+  DROP COLUMN IF EXISTS label;
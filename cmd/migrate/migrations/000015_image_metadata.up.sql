@@ -0,0 +1,3 @@
+ALTER TABLE images
+  ADD COLUMN label TEXT,
+  ADD COLUMN notes TEXT;
@@ -0,0 +1,2 @@
+ALTER TABLE runs
+  ADD COLUMN cancel_reason TEXT;
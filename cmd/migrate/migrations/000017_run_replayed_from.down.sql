@@ -0,0 +1,2 @@
+ALTER TABLE runs
+  DROP COLUMN IF EXISTS replayed_from;
@@ -0,0 +1,2 @@
+ALTER TABLE runs
+  ADD COLUMN replayed_from UUID REFERENCES runs(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE documents
+  ADD COLUMN page_count_pending BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+ALTER TABLE images
+  DROP COLUMN IF EXISTS render_error,
+  DROP COLUMN IF EXISTS status;
@@ -0,0 +1,3 @@
+ALTER TABLE images
+  ADD COLUMN status TEXT NOT NULL DEFAULT 'ready',
+  ADD COLUMN render_error TEXT;
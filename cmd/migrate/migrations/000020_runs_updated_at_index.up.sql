@@ -0,0 +1 @@
+CREATE INDEX idx_runs_updated_at ON runs(updated_at);
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/JaimeStill/agent-lab/internal/seed"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -15,11 +16,15 @@ const EnvDatabaseDSN = "DATABASE_DSN"
 
 func main() {
 	var (
-		dsn      = flag.String("dsn", "", "Database connection string")
-		all      = flag.Bool("all", false, "Run all seeders")
-		profiles = flag.Bool("profiles", false, "Seed profiles")
-		file     = flag.String("file", "", "External seed file (overrides embedded)")
-		list     = flag.Bool("list", false, "List available seeders")
+		dsn       = flag.String("dsn", "", "Database connection string")
+		all       = flag.Bool("all", false, "Run all seeders")
+		profiles  = flag.Bool("profiles", false, "Seed profiles")
+		agents    = flag.Bool("agents", false, "Seed agents")
+		providers = flag.Bool("providers", false, "Seed providers")
+		file      = flag.String("file", "", "External seed file (overrides embedded)")
+		dump      = flag.String("dump", "", "Export agents, providers, and profiles to seed files in the given directory")
+		dryRun    = flag.Bool("dry-run", false, "Report what would change without writing")
+		list      = flag.Bool("list", false, "List available seeders")
 	)
 	flag.Parse()
 
@@ -52,24 +57,42 @@ func main() {
 
 	switch {
 	case *all:
-		if err := runAllSeeders(ctx, db); err != nil {
+		if err := runAllSeeders(ctx, db, *dryRun); err != nil {
 			log.Fatalf("seeding failed: %v", err)
 		}
 		fmt.Println("all seeders completed successfully")
 
-	case *profiles:
-		if *file != "" {
-			if seeder, ok := getSeeder("profiles"); ok {
-				seeder.(*ProfileSeeder).SetFile(*file)
-			}
-		}
-		if err := runSeeder(ctx, db, "profiles"); err != nil {
-			log.Fatalf("seeding failed: %v", err)
+	case *dump != "":
+		if err := seed.Dump(ctx, db, *dump); err != nil {
+			log.Fatalf("dump failed: %v", err)
 		}
-		fmt.Println("profiles seeded successfully")
+		fmt.Printf("seed files written to %s\n", *dump)
+
+	case *profiles:
+		seedOne(ctx, db, "profiles", *file, *dryRun)
+
+	case *agents:
+		seedOne(ctx, db, "agents", *file, *dryRun)
+
+	case *providers:
+		seedOne(ctx, db, "providers", *file, *dryRun)
 
 	default:
-		fmt.Println("usage: seed -dsn <connection-string> [-all|-profiles] [-file <path>] [-list]")
+		fmt.Println("usage: seed -dsn <connection-string> [-all|-profiles|-agents|-providers] [-file <path>] [-dry-run] [-dump <dir>] [-list]")
 		flag.PrintDefaults()
 	}
 }
+
+// seedOne runs a single seeder, loading from file when provided.
+func seedOne(ctx context.Context, db *sql.DB, name, file string, dryRun bool) {
+	if file != "" {
+		if seeder, ok := getSeeder(name); ok {
+			if fileSeeder, ok := seeder.(seed.FileSeeder); ok {
+				fileSeeder.SetFile(file)
+			}
+		}
+	}
+	if err := runSeeder(ctx, db, name, dryRun); err != nil {
+		log.Fatalf("seeding failed: %v", err)
+	}
+}
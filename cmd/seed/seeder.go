@@ -1,89 +1,83 @@
 // Package main provides the seed command for populating the database with
 // initial or test data. It supports multiple seeders that can be run
-// individually or together within a single transaction.
+// individually or together within a single transaction, and a dump mode
+// that exports the current database state to seed files.
 package main
 
 import (
 	"context"
 	"database/sql"
 	"fmt"
-)
-
-// Seeder defines the interface for database seeders.
-// Each seeder is responsible for populating a specific domain's data.
-type Seeder interface {
-	// Name returns the unique identifier for this seeder.
-	Name() string
 
-	// Description returns a human-readable description of what this seeder does.
-	Description() string
-
-	// Seed executes the seeding logic within the provided transaction.
-	// The transaction allows all-or-nothing semantics across multiple seeders.
-	Seed(ctx context.Context, tx *sql.Tx) error
-}
+	"github.com/JaimeStill/agent-lab/internal/seed"
+)
 
-var seeders = map[string]Seeder{}
+var seeders []seed.Seeder
 
 // registerSeeder adds a seeder to the global registry.
-// Seeders self-register via init() functions.
-func registerSeeder(s Seeder) {
-	seeders[s.Name()] = s
+// Seeders run in registration order when executed together.
+func registerSeeder(s seed.Seeder) {
+	seeders = append(seeders, s)
 }
 
 // getSeeder retrieves a seeder by name from the registry.
-func getSeeder(name string) (Seeder, bool) {
-	s, ok := seeders[name]
-	return s, ok
+func getSeeder(name string) (seed.Seeder, bool) {
+	for _, s := range seeders {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	return nil, false
 }
 
 // listSeeders returns all registered seeders.
-func listSeeders() []Seeder {
-	result := make([]Seeder, 0, len(seeders))
-	for _, s := range seeders {
-		result = append(result, s)
-	}
-	return result
+func listSeeders() []seed.Seeder {
+	return seeders
 }
 
 // runSeeder executes a single seeder by name within a transaction.
+// In dry-run mode the transaction is rolled back after reporting.
 // Returns an error if the seeder is not found or if seeding fails.
-func runSeeder(ctx context.Context, db *sql.DB, name string) error {
+func runSeeder(ctx context.Context, db *sql.DB, name string, dryRun bool) error {
 	seeder, ok := getSeeder(name)
 	if !ok {
 		return fmt.Errorf("seeder not found: %s", name)
 	}
 
-	tx, err := db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
-	}
-
-	if err := seeder.Seed(ctx, tx); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("seed %s: %w", name, err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
-	}
-
-	return nil
+	return runSeeders(ctx, db, []seed.Seeder{seeder}, dryRun)
 }
 
 // runAllSeeders executes all registered seeders within a single transaction.
 // If any seeder fails, the entire transaction is rolled back.
-func runAllSeeders(ctx context.Context, db *sql.DB) error {
+func runAllSeeders(ctx context.Context, db *sql.DB, dryRun bool) error {
+	return runSeeders(ctx, db, seeders, dryRun)
+}
+
+// runSeeders applies each seeder in order within a single transaction,
+// printing created and updated counts per seeder. The transaction is
+// committed only when dryRun is false and every seeder succeeds.
+func runSeeders(ctx context.Context, db *sql.DB, list []seed.Seeder, dryRun bool) error {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
 
-	for name, seeder := range seeders {
-		if err := seeder.Seed(ctx, tx); err != nil {
+	for _, seeder := range list {
+		result, err := seeder.Apply(ctx, tx, dryRun)
+		if err != nil {
 			tx.Rollback()
-			return fmt.Errorf("seed %s: %w", name, err)
+			return fmt.Errorf("seed %s: %w", seeder.Name(), err)
 		}
+
+		if dryRun {
+			fmt.Printf("%s: %s (dry run)\n", seeder.Name(), result)
+		} else {
+			fmt.Printf("%s: %s\n", seeder.Name(), result)
+		}
+	}
+
+	if dryRun {
+		return tx.Rollback()
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -1,6 +1,26 @@
 package main
 
-import "embed"
+import (
+	"embed"
+
+	"github.com/JaimeStill/agent-lab/internal/seed"
+)
 
 //go:embed seeds/*.json
 var seedFiles embed.FS
+
+// Seeders are registered in dependency order: profile stages reference
+// agents, so providers and agents are seeded before profiles.
+func init() {
+	registerSeeder(seed.NewProviderSeeder(mustReadSeed("seeds/providers.json")))
+	registerSeeder(seed.NewAgentSeeder(mustReadSeed("seeds/agents.json")))
+	registerSeeder(seed.NewProfileSeeder(mustReadSeed("seeds/classify_profiles.json")))
+}
+
+func mustReadSeed(name string) []byte {
+	data, err := seedFiles.ReadFile(name)
+	if err != nil {
+		panic("failed to read embedded seed file: " + err.Error())
+	}
+	return data
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/infrastructure"
+)
+
+// runCheck validates the configuration and the database and storage it points
+// to, writes a summary to w, and returns the process exit code. loadErr is the
+// result of loading the configuration; the server is never started.
+func runCheck(w io.Writer, cfg *config.Config, loadErr error) int {
+	if loadErr != nil {
+		report := &infrastructure.CheckReport{}
+		report.Add("config", "load", loadErr)
+		report.Write(w)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Database.ConnTimeoutDuration())
+	defer cancel()
+
+	report := infrastructure.CheckConfig(ctx, cfg)
+	report.Write(w)
+
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -10,7 +11,13 @@ import (
 )
 
 func main() {
+	check := flag.Bool("check", false, "validate config, database, and storage, then exit without serving")
+	flag.Parse()
+
 	cfg, err := config.Load()
+	if *check {
+		os.Exit(runCheck(os.Stdout, cfg, err))
+	}
 	if err != nil {
 		log.Fatal("config load failed:", err)
 	}
@@ -30,6 +30,7 @@ func NewModules(infra *infrastructure.Infrastructure, cfg *config.Config) (*Modu
 	if err != nil {
 		return nil, err
 	}
+	appModule.Use(middleware.RealIP(&cfg.Server.Proxy))
 	appModule.Use(middleware.Logger(infra.Logger))
 
 	scalarModule := scalar.NewModule("/scalar")
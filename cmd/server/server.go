@@ -56,7 +56,10 @@ func (s *Server) Start() error {
 	}
 
 	go func() {
-		s.infra.Lifecycle.WaitForStartup()
+		if err := s.infra.Lifecycle.WaitForStartup(); err != nil {
+			s.infra.Logger.Error("subsystem startup failed", "error", err)
+			return
+		}
 		s.infra.Logger.Info("all subsystems ready")
 	}()
 
@@ -4,28 +4,88 @@ package agents
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	agtconfig "github.com/JaimeStill/go-agents/pkg/config"
 	"github.com/google/uuid"
 )
 
+// Domain event types published to the event bus. Created and updated events
+// carry the Agent as data; deleted events carry only the agent ID as subject.
+const (
+	EventCreated = "agent.created"
+	EventUpdated = "agent.updated"
+	EventDeleted = "agent.deleted"
+)
+
 // Agent represents an AI agent configuration stored in the database.
+// When ProviderID is set, the referenced provider record supplies the provider
+// config and credentials at call time, overriding the provider in Config.
 type Agent struct {
-	ID        uuid.UUID       `json:"id"`
-	Name      string          `json:"name"`
-	Config    json.RawMessage `json:"config"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID         uuid.UUID       `json:"id"`
+	Name       string          `json:"name"`
+	ProviderID *uuid.UUID      `json:"provider_id,omitempty"`
+	Config     json.RawMessage `json:"config"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
 }
 
 // CreateCommand contains the data required to create a new agent.
 type CreateCommand struct {
-	Name   string          `json:"name"`
-	Config json.RawMessage `json:"config"`
+	Name       string          `json:"name"`
+	ProviderID *uuid.UUID      `json:"provider_id,omitempty"`
+	Config     json.RawMessage `json:"config"`
+}
+
+// Validate checks each field of the command and returns a
+// *handlers.ValidationError listing every invalid field, or nil.
+// When ProviderID is set, config is only checked to parse; the complete
+// config is validated against the referenced provider on create.
+func (c CreateCommand) Validate() error {
+	var v handlers.ValidationError
+
+	if strings.TrimSpace(c.Name) == "" {
+		v.Add("name", "name is required")
+	}
+
+	switch {
+	case len(c.Config) == 0 || string(c.Config) == "null":
+		v.Add("config", "config is required")
+	case c.ProviderID != nil:
+		var cfg agtconfig.AgentConfig
+		if err := json.Unmarshal(c.Config, &cfg); err != nil {
+			v.Add("config", "config must be a valid go-agents AgentConfig: "+err.Error())
+		}
+	default:
+		if err := checkConfig(c.Config, nil); err != nil {
+			v.Add("config", "config must be a valid go-agents AgentConfig: "+err.Error())
+		}
+	}
+
+	return v.Err()
 }
 
 // UpdateCommand contains the data required to update an existing agent.
 type UpdateCommand struct {
-	Name   string          `json:"name"`
-	Config json.RawMessage `json:"config"`
+	Name       string          `json:"name"`
+	ProviderID *uuid.UUID      `json:"provider_id,omitempty"`
+	Config     json.RawMessage `json:"config"`
+}
+
+// CloneCommand contains the name for an agent cloned from an existing one.
+type CloneCommand struct {
+	Name string `json:"name"`
+}
+
+// Validate checks that the clone name is present.
+func (c CloneCommand) Validate() error {
+	var v handlers.ValidationError
+
+	if strings.TrimSpace(c.Name) == "" {
+		v.Add("name", "name is required")
+	}
+
+	return v.Err()
 }
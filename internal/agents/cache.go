@@ -0,0 +1,212 @@
+package agents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/google/uuid"
+)
+
+// DefaultCacheTTL is the lifetime of cached agent responses.
+const DefaultCacheTTL = 15 * time.Minute
+
+// DefaultCacheMaxEntries is the number of responses the cache holds before
+// evicting the oldest.
+const DefaultCacheMaxEntries = 1000
+
+// CacheOption is the request option key that opts a chat or vision call into
+// response caching. Streaming and tool calls are never cached.
+const CacheOption = "cache"
+
+// Cache status values reported through the X-Cache response header.
+const (
+	CacheHit  = "HIT"
+	CacheMiss = "MISS"
+)
+
+type cacheEntry struct {
+	agentID uuid.UUID
+	resp    *response.ChatResponse
+	expires time.Time
+}
+
+// ResponseCache is an in-memory, TTL-bound cache of chat and vision responses.
+// It holds at most maxEntries responses, evicting the oldest to make room.
+// Entries are grouped by agent so Update and Delete can invalidate an
+// agent's responses when its config changes.
+type ResponseCache struct {
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]cacheEntry
+	epoch      uint64
+	mu         sync.Mutex
+}
+
+// NewResponseCache creates a response cache whose entries expire after ttl
+// and that holds at most maxEntries responses. A non-positive ttl falls back
+// to DefaultCacheTTL and a non-positive maxEntries to DefaultCacheMaxEntries.
+func NewResponseCache(ttl time.Duration, maxEntries int) *ResponseCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+	return &ResponseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+// Fetch returns the cached response for key if present and unexpired.
+// Otherwise it invokes fn, caches a successful result under agentID, and
+// returns it. A result whose call overlaps an Invalidate is returned but not
+// cached, so a response from a stale config never outlives an update. The
+// boolean result reports whether the response was served from cache.
+func (c *ResponseCache) Fetch(agentID uuid.UUID, key string, fn func() (*response.ChatResponse, error)) (*response.ChatResponse, bool, error) {
+	resp, epoch, ok := c.get(key)
+	if ok {
+		return resp, true, nil
+	}
+
+	resp, err := fn()
+	if err != nil {
+		return nil, false, err
+	}
+
+	c.set(agentID, key, resp, epoch)
+	return resp, false, nil
+}
+
+// Invalidate removes every cached response for agentID. Calls already in
+// progress are not cached when they complete.
+func (c *ResponseCache) Invalidate(agentID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, e := range c.entries {
+		if e.agentID == agentID {
+			delete(c.entries, k)
+		}
+	}
+	c.epoch++
+}
+
+// Len returns the number of unexpired entries in the cache.
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune(time.Now())
+	return len(c.entries)
+}
+
+// get returns the cached response for key along with the current epoch, which
+// set uses to detect an Invalidate during the call that fills a miss.
+func (c *ResponseCache) get(key string) (*response.ChatResponse, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, c.epoch, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, c.epoch, false
+	}
+
+	return entry.resp, c.epoch, true
+}
+
+func (c *ResponseCache) set(agentID uuid.UUID, key string, resp *response.ChatResponse, epoch uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.epoch != epoch {
+		return
+	}
+
+	now := time.Now()
+	c.prune(now)
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evictOldest()
+	}
+	c.entries[key] = cacheEntry{agentID: agentID, resp: resp, expires: now.Add(c.ttl)}
+}
+
+// evictOldest removes the entry closest to expiry, which with a fixed TTL is
+// the one cached first.
+func (c *ResponseCache) evictOldest() {
+	var oldest string
+	var expires time.Time
+	for k, e := range c.entries {
+		if oldest == "" || e.expires.Before(expires) {
+			oldest, expires = k, e.expires
+		}
+	}
+	delete(c.entries, oldest)
+}
+
+func (c *ResponseCache) prune(now time.Time) {
+	for k, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// CacheKey derives a stable cache key from the agent, token, prompt, images, and options.
+// The token is hashed into the key so a response fetched with one credential is never
+// served to a request made with another. The cache option itself is excluded so it
+// does not fragment the cache.
+func CacheKey(agentID uuid.UUID, token, prompt string, images []string, opts map[string]any) string {
+	keyOpts := make(map[string]any, len(opts))
+	for k, v := range opts {
+		if k == CacheOption {
+			continue
+		}
+		keyOpts[k] = v
+	}
+
+	data, _ := json.Marshal(struct {
+		AgentID uuid.UUID      `json:"agent_id"`
+		Token   string         `json:"token,omitempty"`
+		Prompt  string         `json:"prompt"`
+		Images  []string       `json:"images,omitempty"`
+		Options map[string]any `json:"options,omitempty"`
+	}{agentID, token, prompt, images, keyOpts})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func cacheRequested(opts map[string]any) bool {
+	enabled, _ := opts[CacheOption].(bool)
+	return enabled
+}
+
+type cacheStatusKey struct{}
+
+func withCacheStatus(ctx context.Context) (context.Context, *string) {
+	status := new(string)
+	return context.WithValue(ctx, cacheStatusKey{}, status), status
+}
+
+func setCacheStatus(ctx context.Context, hit bool) {
+	status, ok := ctx.Value(cacheStatusKey{}).(*string)
+	if !ok {
+		return
+	}
+	if hit {
+		*status = CacheHit
+	} else {
+		*status = CacheMiss
+	}
+}
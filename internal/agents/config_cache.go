@@ -0,0 +1,141 @@
+package agents
+
+import (
+	"maps"
+	"sync"
+
+	agtconfig "github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/google/uuid"
+)
+
+// ParsedConfig is a stored agent config parsed and merged over the go-agents
+// defaults, ready for per-call overrides. Provider references are kept as an
+// ID so referenced credentials are resolved fresh on every call.
+type ParsedConfig struct {
+	ProviderID *uuid.UUID
+	Config     agtconfig.AgentConfig
+}
+
+// configLoad is a parse in progress, shared by concurrent callers.
+type configLoad struct {
+	done   chan struct{}
+	result ParsedConfig
+	err    error
+}
+
+// ConfigCache is an in-memory cache of parsed agent configs keyed by agent ID.
+// Concurrent misses for the same agent share a single load. Entries live until
+// invalidated; Update and Delete invalidate the affected agent so changes take
+// effect on the next call.
+type ConfigCache struct {
+	entries  map[uuid.UUID]ParsedConfig
+	inflight map[uuid.UUID]*configLoad
+	epoch    uint64
+	mu       sync.RWMutex
+}
+
+// NewConfigCache creates an empty agent config cache.
+func NewConfigCache() *ConfigCache {
+	return &ConfigCache{
+		entries:  make(map[uuid.UUID]ParsedConfig),
+		inflight: make(map[uuid.UUID]*configLoad),
+	}
+}
+
+// Get returns a copy of the cached config for id, calling load on a miss.
+// Callers that miss while a load for id is in progress wait for its result
+// rather than loading again. Failed loads are not cached, and a load that
+// overlaps an Invalidate is returned but not cached, so a stale config never
+// outlives an update. The returned config is safe for the caller to modify.
+func (c *ConfigCache) Get(id uuid.UUID, load func() (ParsedConfig, error)) (ParsedConfig, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[id]
+	c.mu.RUnlock()
+	if ok {
+		return entry.clone(), nil
+	}
+
+	c.mu.Lock()
+	if entry, ok := c.entries[id]; ok {
+		c.mu.Unlock()
+		return entry.clone(), nil
+	}
+	if call, ok := c.inflight[id]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.result.clone(), call.err
+	}
+
+	call := &configLoad{done: make(chan struct{})}
+	c.inflight[id] = call
+	epoch := c.epoch
+	c.mu.Unlock()
+
+	call.result, call.err = load()
+
+	c.mu.Lock()
+	if c.inflight[id] == call {
+		delete(c.inflight, id)
+	}
+	if call.err == nil && c.epoch == epoch {
+		c.entries[id] = call.result
+	}
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.result.clone(), call.err
+}
+
+// Invalidate removes the cached config for id. Loads already in progress
+// are not cached when they complete.
+func (c *ConfigCache) Invalidate(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, id)
+	delete(c.inflight, id)
+	c.epoch++
+}
+
+// Len returns the number of cached configs.
+func (c *ConfigCache) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries)
+}
+
+// clone deep-copies the config so per-call overrides never reach the cache.
+func (p ParsedConfig) clone() ParsedConfig {
+	out := p
+	cfg := &out.Config
+
+	if cfg.Client != nil {
+		client := *cfg.Client
+		cfg.Client = &client
+	}
+
+	if cfg.Provider != nil {
+		provider := *cfg.Provider
+		provider.Options = maps.Clone(provider.Options)
+		cfg.Provider = &provider
+	}
+
+	if cfg.Model != nil {
+		model := *cfg.Model
+		if model.Capabilities != nil {
+			caps := make(map[string]map[string]any, len(model.Capabilities))
+			for k, v := range model.Capabilities {
+				caps[k] = maps.Clone(v)
+			}
+			model.Capabilities = caps
+		}
+		cfg.Model = &model
+	}
+
+	if p.ProviderID != nil {
+		id := *p.ProviderID
+		out.ProviderID = &id
+	}
+
+	return out
+}
@@ -0,0 +1,211 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/pkg/redact"
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/google/uuid"
+)
+
+const redacted = "[REDACTED]"
+
+var dataURIPattern = regexp.MustCompile(`data:[\w.+-]+/[\w.+-]+(;[\w-]+=[\w-]+)*;base64,[A-Za-z0-9+/=]+`)
+
+// DebugRequest describes an outbound provider call for debug logging.
+type DebugRequest struct {
+	Capability string
+	AgentID    uuid.UUID
+	Prompt     string
+	Inputs     []string
+	Images     []string
+	Tools      []agent.Tool
+	Options    map[string]any
+	Token      string
+}
+
+// RequestLogger writes provider requests and responses at DEBUG level,
+// pairing them with a correlation ID. Tokens, credential keys, and the
+// configured redact keys are replaced with "[REDACTED]", and image data is
+// summarized by count and size rather than logged.
+//
+// A nil *RequestLogger is valid and logs nothing.
+type RequestLogger struct {
+	logger      *slog.Logger
+	redactKeys  map[string]bool
+	maxResponse int
+}
+
+// NewRequestLogger creates a RequestLogger from cfg.
+// Returns nil when cfg.Enabled is false.
+func NewRequestLogger(logger *slog.Logger, cfg config.AgentDebugConfig) *RequestLogger {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	keys := make(map[string]bool, len(cfg.RedactKeys))
+	for _, k := range cfg.RedactKeys {
+		keys[strings.ToLower(k)] = true
+	}
+
+	maxResponse := cfg.MaxResponseLength
+	if maxResponse <= 0 {
+		maxResponse = config.DefaultAgentDebugResponseLength
+	}
+
+	return &RequestLogger{
+		logger:      logger,
+		redactKeys:  keys,
+		maxResponse: maxResponse,
+	}
+}
+
+// Request logs req and returns the correlation ID to pass to Response.
+// Returns an empty string when debug logging is disabled.
+func (l *RequestLogger) Request(ctx context.Context, req DebugRequest) string {
+	if l == nil || !l.logger.Enabled(ctx, slog.LevelDebug) {
+		return ""
+	}
+
+	correlationID := uuid.NewString()
+
+	attrs := []any{
+		"correlation_id", correlationID,
+		"capability", req.Capability,
+		"agent_id", req.AgentID,
+		"prompt", l.scrub(req.Prompt),
+	}
+
+	if len(req.Inputs) > 0 {
+		attrs = append(attrs, "input_count", len(req.Inputs))
+	}
+	if len(req.Images) > 0 {
+		attrs = append(attrs, "images", SummarizeImages(req.Images))
+	}
+	if len(req.Tools) > 0 {
+		names := make([]string, len(req.Tools))
+		for i, t := range req.Tools {
+			names[i] = t.Name
+		}
+		attrs = append(attrs, "tools", names)
+	}
+	if len(req.Options) > 0 {
+		attrs = append(attrs, "options", l.RedactOptions(req.Options))
+	}
+	if req.Token != "" {
+		attrs = append(attrs, "token", redacted)
+	}
+
+	l.logger.DebugContext(ctx, "provider request", attrs...)
+	return correlationID
+}
+
+// Response logs the outcome of the request identified by correlationID.
+// The response is encoded as JSON and truncated to the configured length.
+func (l *RequestLogger) Response(ctx context.Context, correlationID string, resp any, err error) {
+	if l == nil || correlationID == "" {
+		return
+	}
+
+	if err != nil {
+		l.logger.DebugContext(ctx, "provider response", "correlation_id", correlationID, "error", err)
+		return
+	}
+
+	body, mErr := json.Marshal(resp)
+	if mErr != nil {
+		body = []byte(fmt.Sprintf("%+v", resp))
+	}
+
+	l.logger.DebugContext(ctx, "provider response",
+		"correlation_id", correlationID,
+		"response", l.truncate(l.scrub(string(body))),
+		"response_bytes", len(body),
+	)
+}
+
+// RedactOptions returns a copy of opts with credential keys, the configured
+// redact keys, and embedded image data replaced at any depth.
+func (l *RequestLogger) RedactOptions(opts map[string]any) map[string]any {
+	out, _ := l.redactValue(opts).(map[string]any)
+	return out
+}
+
+func (l *RequestLogger) redactValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			if redact.IsSecret(k) || l.redactKeys[strings.ToLower(k)] {
+				out[k] = redacted
+				continue
+			}
+			out[k] = l.redactValue(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = l.redactValue(val)
+		}
+		return out
+	case string:
+		return l.scrub(t)
+	default:
+		return v
+	}
+}
+
+// scrub replaces every base64 data URI within s with its summary.
+func (l *RequestLogger) scrub(s string) string {
+	if !strings.Contains(s, ";base64,") {
+		return s
+	}
+	return dataURIPattern.ReplaceAllStringFunc(s, summarizeImage)
+}
+
+func (l *RequestLogger) truncate(s string) string {
+	if len(s) <= l.maxResponse {
+		return s
+	}
+	return s[:l.maxResponse] + fmt.Sprintf("...(%d bytes truncated)", len(s)-l.maxResponse)
+}
+
+// ImageSummary describes image inputs without their data.
+type ImageSummary struct {
+	Count      int      `json:"count"`
+	TotalBytes int      `json:"total_bytes"`
+	Types      []string `json:"types"`
+}
+
+// SummarizeImages reports the count, encoded size, and media types of
+// base64 data URI images.
+func SummarizeImages(images []string) ImageSummary {
+	summary := ImageSummary{Count: len(images), Types: make([]string, 0, len(images))}
+	for _, img := range images {
+		summary.TotalBytes += len(img)
+		summary.Types = append(summary.Types, imageMediaType(img))
+	}
+	return summary
+}
+
+func summarizeImage(uri string) string {
+	return fmt.Sprintf("[%s image, %d bytes]", imageMediaType(uri), len(uri))
+}
+
+func imageMediaType(uri string) string {
+	if !strings.HasPrefix(uri, "data:") {
+		return "unknown"
+	}
+	mediaType, _, _ := strings.Cut(strings.TrimPrefix(uri, "data:"), ";")
+	if mediaType == "" {
+		return "unknown"
+	}
+	return mediaType
+}
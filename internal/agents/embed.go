@@ -0,0 +1,224 @@
+package agents
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/request"
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// MaxBatchSize is the maximum number of inputs accepted by a single batch embedding request.
+const MaxBatchSize = 256
+
+// StoreOption is the request option key that opts a batch embedding call into
+// persisting vectors so identical inputs are not re-embedded.
+const StoreOption = "store"
+
+// embedConcurrency bounds parallel single-input calls when a provider
+// does not support native batch embedding.
+const embedConcurrency = 4
+
+// EmbedBatchResponse contains the vectors for a batch embedding request in input order.
+// Reused counts inputs served from stored vectors rather than the provider.
+type EmbedBatchResponse struct {
+	Model      string               `json:"model"`
+	Embeddings [][]float64          `json:"embeddings"`
+	Reused     int                  `json:"reused"`
+	Usage      *response.TokenUsage `json:"usage,omitempty"`
+}
+
+// Embedder produces one vector per input, in input order.
+type Embedder func(ctx context.Context, inputs []string) ([][]float64, *response.TokenUsage, error)
+
+// HashInput returns the hex-encoded SHA-256 hash used to identify stored embeddings.
+func HashInput(input string) string {
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateBatch checks that inputs is non-empty and within MaxBatchSize.
+func ValidateBatch(inputs []string) error {
+	if len(inputs) == 0 {
+		return fmt.Errorf("%w: inputs are required", ErrInvalidBatch)
+	}
+	if len(inputs) > MaxBatchSize {
+		return fmt.Errorf("%w: %d inputs exceeds max batch size %d", ErrInvalidBatch, len(inputs), MaxBatchSize)
+	}
+	return nil
+}
+
+// EmbedBatch returns a vector for each input in order. Identical inputs are
+// embedded once, and inputs whose hash appears in known are reused without
+// calling embed. Newly embedded vectors are returned keyed by input hash so
+// callers can persist them.
+func EmbedBatch(ctx context.Context, inputs []string, known map[string][]float64, embed Embedder) (*EmbedBatchResponse, map[string][]float64, error) {
+	if err := ValidateBatch(inputs); err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]string, len(inputs))
+	var pending []string
+	var pendingHashes []string
+	seen := make(map[string]bool)
+
+	for i, input := range inputs {
+		h := HashInput(input)
+		hashes[i] = h
+
+		if _, ok := known[h]; ok || seen[h] {
+			continue
+		}
+		seen[h] = true
+		pending = append(pending, input)
+		pendingHashes = append(pendingHashes, h)
+	}
+
+	fresh := make(map[string][]float64, len(pending))
+	result := &EmbedBatchResponse{Embeddings: make([][]float64, len(inputs))}
+
+	if len(pending) > 0 {
+		vectors, usage, err := embed(ctx, pending)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(vectors) != len(pending) {
+			return nil, nil, fmt.Errorf("%w: expected %d embeddings, got %d", ErrExecution, len(pending), len(vectors))
+		}
+
+		for i, h := range pendingHashes {
+			fresh[h] = vectors[i]
+		}
+		result.Usage = usage
+	}
+
+	for i, h := range hashes {
+		if v, ok := known[h]; ok {
+			result.Embeddings[i] = v
+			result.Reused++
+			continue
+		}
+		result.Embeddings[i] = fresh[h]
+	}
+
+	return result, fresh, nil
+}
+
+var errBatchUnsupported = errors.New("provider returned an incomplete batch embedding response")
+
+// nativeEmbedder sends all inputs in a single embeddings request, which
+// OpenAI-compatible providers accept as an array input.
+func nativeEmbedder(agt agent.Agent) Embedder {
+	return func(ctx context.Context, inputs []string) ([][]float64, *response.TokenUsage, error) {
+		opts := maps.Clone(agt.Model().Options[protocol.Embeddings])
+		req := request.NewEmbeddings(agt.Provider(), agt.Model(), inputs, opts)
+
+		result, err := agt.Client().Execute(ctx, req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resp, ok := result.(*response.EmbeddingsResponse)
+		if !ok || len(resp.Data) != len(inputs) {
+			return nil, nil, errBatchUnsupported
+		}
+
+		vectors := make([][]float64, len(inputs))
+		for _, d := range resp.Data {
+			if d.Index < 0 || d.Index >= len(inputs) || vectors[d.Index] != nil {
+				return nil, nil, errBatchUnsupported
+			}
+			vectors[d.Index] = d.Embedding
+		}
+
+		return vectors, resp.Usage, nil
+	}
+}
+
+// parallelEmbedder adapts a single-input embed function into an Embedder
+// that runs at most concurrency calls at once.
+func parallelEmbedder(single func(ctx context.Context, input string) (*response.EmbeddingsResponse, error), concurrency int) Embedder {
+	return func(ctx context.Context, inputs []string) ([][]float64, *response.TokenUsage, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		vectors := make([][]float64, len(inputs))
+		usages := make([]*response.TokenUsage, len(inputs))
+		tasks := make(chan int, len(inputs))
+
+		var once sync.Once
+		var firstErr error
+		fail := func(err error) {
+			once.Do(func() {
+				firstErr = err
+				cancel()
+			})
+		}
+
+		for i := range inputs {
+			tasks <- i
+		}
+		close(tasks)
+
+		var wg sync.WaitGroup
+		for range min(concurrency, len(inputs)) {
+			wg.Go(func() {
+				for i := range tasks {
+					if ctx.Err() != nil {
+						return
+					}
+
+					resp, err := single(ctx, inputs[i])
+					if err == nil && len(resp.Data) == 0 {
+						err = fmt.Errorf("%w: empty embeddings response", ErrExecution)
+					}
+					if err != nil {
+						fail(err)
+						return
+					}
+
+					vectors[i] = resp.Data[0].Embedding
+					usages[i] = resp.Usage
+				}
+			})
+		}
+		wg.Wait()
+
+		if firstErr != nil {
+			return nil, nil, firstErr
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		return vectors, sumUsage(usages), nil
+	}
+}
+
+func sumUsage(usages []*response.TokenUsage) *response.TokenUsage {
+	var total *response.TokenUsage
+	for _, u := range usages {
+		if u == nil {
+			continue
+		}
+		if total == nil {
+			total = &response.TokenUsage{}
+		}
+		total.PromptTokens += u.PromptTokens
+		total.CompletionTokens += u.CompletionTokens
+		total.TotalTokens += u.TotalTokens
+	}
+	return total
+}
+
+func storeRequested(opts map[string]any) bool {
+	enabled, _ := opts[StoreOption].(bool)
+	return enabled
+}
@@ -2,7 +2,16 @@ package agents
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/go-agents/pkg/client"
 )
 
 // Domain errors for agent operations.
@@ -11,8 +20,34 @@ var (
 	ErrDuplicate     = errors.New("agent name already exists")
 	ErrInvalidConfig = errors.New("invalid agent config")
 	ErrExecution     = errors.New("agent execution failed")
+
+	ErrInvalidUsageRange = errors.New("invalid usage range")
+	ErrInvalidBatch      = errors.New("invalid embedding batch")
+
+	ErrImageURLNotAllowed = errors.New("image url not allowed")
+	ErrImageUnavailable   = errors.New("image unavailable")
+
+	ErrProviderAuth        = errors.New("provider authentication failed")
+	ErrProviderRateLimited = errors.New("provider rate limit exceeded")
+	ErrModelNotFound       = errors.New("model not found")
+	ErrProviderUnavailable = errors.New("provider unavailable")
 )
 
+func init() {
+	handlers.RegisterErrorCode("not_found", ErrNotFound)
+	handlers.RegisterErrorCode("duplicate", ErrDuplicate)
+	handlers.RegisterErrorCode("invalid_config", ErrInvalidConfig)
+	handlers.RegisterErrorCode("execution_failed", ErrExecution)
+	handlers.RegisterErrorCode("invalid_usage_range", ErrInvalidUsageRange)
+	handlers.RegisterErrorCode("invalid_batch", ErrInvalidBatch)
+	handlers.RegisterErrorCode("image_url_not_allowed", ErrImageURLNotAllowed)
+	handlers.RegisterErrorCode("image_unavailable", ErrImageUnavailable)
+	handlers.RegisterErrorCode("provider_auth", ErrProviderAuth)
+	handlers.RegisterErrorCode("rate_limited", ErrProviderRateLimited)
+	handlers.RegisterErrorCode("model_not_found", ErrModelNotFound)
+	handlers.RegisterErrorCode("provider_unavailable", ErrProviderUnavailable)
+}
+
 // MapHTTPStatus maps domain errors to appropriate HTTP status codes.
 func MapHTTPStatus(err error) int {
 	if errors.Is(err, ErrNotFound) {
@@ -21,11 +56,143 @@ func MapHTTPStatus(err error) int {
 	if errors.Is(err, ErrDuplicate) {
 		return http.StatusConflict
 	}
-	if errors.Is(err, ErrInvalidConfig) {
+	if errors.Is(err, ErrInvalidConfig) || errors.Is(err, handlers.ErrValidation) {
 		return http.StatusBadRequest
 	}
+	if errors.Is(err, ErrProviderAuth) {
+		return http.StatusUnauthorized
+	}
+	if errors.Is(err, ErrProviderRateLimited) {
+		return http.StatusTooManyRequests
+	}
+	if errors.Is(err, ErrModelNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, ErrProviderUnavailable) {
+		return http.StatusServiceUnavailable
+	}
 	if errors.Is(err, ErrExecution) {
 		return http.StatusBadGateway
 	}
+	if errors.Is(err, ErrInvalidUsageRange) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, ErrInvalidBatch) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, ErrImageURLNotAllowed) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, ErrImageUnavailable) {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, query.ErrInvalidSort) {
+		return http.StatusBadRequest
+	}
 	return http.StatusInternalServerError
 }
+
+// ProviderError wraps a failure returned by an agent provider with its
+// classification. Kind is one of the provider sentinels, or ErrExecution when
+// the failure could not be classified.
+type ProviderError struct {
+	Kind       error
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+func (e *ProviderError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+var (
+	streamStatusPattern = regexp.MustCompile(`status (\d{3})`)
+	retryAfterPattern   = regexp.MustCompile(`(?i)(?:retry after|try again in)\s+(\d+(?:\.\d+)?)\s*(ms|s)?`)
+)
+
+// ClassifyProviderError infers the provider failure category from an error
+// returned by go-agents. HTTP status errors are classified by status code;
+// network errors are treated as the provider being unavailable.
+func ClassifyProviderError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	pe := &ProviderError{Kind: ErrExecution, Err: err}
+
+	status, body := providerStatus(err)
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		pe.Kind = ErrProviderAuth
+	case status == http.StatusTooManyRequests:
+		pe.Kind = ErrProviderRateLimited
+		pe.RetryAfter = parseRetryAfter(body)
+	case status == http.StatusNotFound:
+		pe.Kind = ErrModelNotFound
+	case status == http.StatusBadGateway,
+		status == http.StatusServiceUnavailable,
+		status == http.StatusGatewayTimeout:
+		pe.Kind = ErrProviderUnavailable
+	case status == 0 && isNetworkError(err):
+		pe.Kind = ErrProviderUnavailable
+	}
+
+	return pe
+}
+
+// RetryAfter returns the retry delay reported by a rate-limited provider, if any.
+func RetryAfter(err error) (time.Duration, bool) {
+	var pe *ProviderError
+	if errors.As(err, &pe) && pe.RetryAfter > 0 {
+		return pe.RetryAfter, true
+	}
+	return 0, false
+}
+
+func providerStatus(err error) (int, string) {
+	var httpErr *client.HTTPStatusError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode, string(httpErr.Body)
+	}
+
+	// Streaming requests report failures as formatted errors rather than
+	// HTTPStatusError, so recover the status from the message.
+	msg := err.Error()
+	if m := streamStatusPattern.FindStringSubmatch(msg); m != nil {
+		status, _ := strconv.Atoi(m[1])
+		return status, msg
+	}
+
+	return 0, msg
+}
+
+func parseRetryAfter(body string) time.Duration {
+	m := retryAfterPattern.FindStringSubmatch(body)
+	if m == nil {
+		return 0
+	}
+
+	v, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0
+	}
+
+	if m[2] == "ms" {
+		return time.Duration(v * float64(time.Millisecond))
+	}
+	return time.Duration(v * float64(time.Second))
+}
+
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
@@ -13,7 +16,10 @@ import (
 	"github.com/google/uuid"
 )
 
-const visionSize int64 = 32 << 20
+const (
+	visionSize     int64 = 32 << 20
+	embedBatchSize int64 = 8 << 20
+)
 
 // Handler provides HTTP handlers for agent CRUD operations and execution endpoints.
 type Handler struct {
@@ -44,28 +50,50 @@ func (h *Handler) Routes() routes.Group {
 			{Method: "POST", Pattern: "", Handler: h.Create, OpenAPI: Spec.Create},
 			{Method: "PUT", Pattern: "/{id}", Handler: h.Update, OpenAPI: Spec.Update},
 			{Method: "DELETE", Pattern: "/{id}", Handler: h.Delete, OpenAPI: Spec.Delete},
+			{Method: "POST", Pattern: "/{id}/clone", Handler: h.Clone, OpenAPI: Spec.Clone},
 			{Method: "POST", Pattern: "/{id}/chat", Handler: h.Chat, OpenAPI: Spec.Chat},
 			{Method: "POST", Pattern: "/{id}/chat/stream", Handler: h.ChatStream, OpenAPI: Spec.ChatStream},
 			{Method: "POST", Pattern: "/{id}/vision", Handler: h.Vision, OpenAPI: Spec.Vision},
+			{Method: "POST", Pattern: "/{id}/vision/json", Handler: h.VisionJSON, OpenAPI: Spec.VisionJSON},
 			{Method: "POST", Pattern: "/{id}/vision/stream", Handler: h.VisionStream, OpenAPI: Spec.VisionStream},
 			{Method: "POST", Pattern: "/{id}/tools", Handler: h.Tools, OpenAPI: Spec.Tools},
+			{Method: "POST", Pattern: "/{id}/tools/stream", Handler: h.ToolsStream, OpenAPI: Spec.ToolsStream},
 			{Method: "POST", Pattern: "/{id}/embed", Handler: h.Embed, OpenAPI: Spec.Embed},
+			{Method: "POST", Pattern: "/{id}/embed/batch", Handler: h.EmbedBatch, OpenAPI: Spec.EmbedBatch},
+			{Method: "GET", Pattern: "/{id}/usage", Handler: h.Usage, OpenAPI: Spec.Usage},
+		},
+	}
+}
+
+// UsageRoutes returns the route group for usage reporting across all agents.
+func (h *Handler) UsageRoutes() routes.Group {
+	return routes.Group{
+		Prefix:      "/usage",
+		Tags:        []string{"Usage"},
+		Description: "Agent usage reporting",
+		Routes: []routes.Route{
+			{Method: "GET", Pattern: "", Handler: h.GlobalUsage, OpenAPI: Spec.GlobalUsage},
 		},
 	}
 }
 
 // List handles GET /api/agents to retrieve a paginated list of agents.
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	page := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
 	filters := FiltersFromQuery(r.URL.Query())
 
 	result, err := h.sys.List(r.Context(), page, filters)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	pagination.Respond(w, r, result)
 }
 
 // Find handles GET /api/agents/{id} to retrieve a single agent.
@@ -82,13 +110,18 @@ func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	handlers.RespondFields(w, r, http.StatusOK, result)
 }
 
 // Search handles POST /api/agents/search to search agents with request body parameters.
 func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 	var page pagination.PageRequest
-	if err := json.NewDecoder(r.Body).Decode(&page); err != nil {
+	if err := handlers.DecodeJSON(w, r, &page, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	if err := page.Normalize(h.pagination); err != nil {
 		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
 		return
 	}
@@ -97,18 +130,18 @@ func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.sys.List(r.Context(), page, filters)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	pagination.Respond(w, r, result)
 }
 
 // Create handles POST /api/agents to create a new agent.
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var cmd CreateCommand
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -121,6 +154,29 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	handlers.RespondJSON(w, http.StatusCreated, result)
 }
 
+// Clone handles POST /api/agents/{id}/clone to copy an agent under a new name.
+func (h *Handler) Clone(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var cmd CloneCommand
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	result, err := h.sys.Clone(r.Context(), id, cmd)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusCreated, result)
+}
+
 // Update handles PUT /api/agents/{id} to update an existing agent.
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
@@ -130,8 +186,8 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var cmd UpdateCommand
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -169,17 +225,25 @@ func (h *Handler) Chat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req ChatRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := handlers.DecodeJSON(w, r, &req, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
 		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
 		return
 	}
 
-	resp, err := h.sys.Chat(r.Context(), id, req.Prompt, req.Options, req.Token)
+	ctx, cacheStatus := withCacheStatus(r.Context())
+
+	resp, err := h.sys.Chat(ctx, id, req.Prompt, req.Options, req.Token)
 	if err != nil {
-		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		h.respondExecError(w, err)
 		return
 	}
 
+	writeCacheHeader(w, *cacheStatus)
 	handlers.RespondJSON(w, http.StatusOK, resp)
 }
 
@@ -192,18 +256,23 @@ func (h *Handler) ChatStream(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req ChatRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := handlers.DecodeJSON(w, r, &req, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
 		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
 		return
 	}
 
 	stream, err := h.sys.ChatStream(r.Context(), id, req.Prompt, req.Options, req.Token)
 	if err != nil {
-		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		h.respondExecError(w, err)
 		return
 	}
 
-	h.writeSSEStream(w, r, stream)
+	writeSSEStream(w, r, h.logger, stream, chunkFrame)
 }
 
 // Vision handles POST /api/agents/{id}/vision to execute vision analysis on uploaded images.
@@ -220,12 +289,53 @@ func (h *Handler) Vision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.sys.Vision(r.Context(), id, form.Prompt, form.Images, form.Options, form.Token)
+	ctx, cacheStatus := withCacheStatus(r.Context())
+
+	resp, err := h.sys.Vision(ctx, id, form.Prompt, form.Images, form.Options, form.Token)
+	if err != nil {
+		h.respondExecError(w, err)
+		return
+	}
+
+	writeCacheHeader(w, *cacheStatus)
+	handlers.RespondJSON(w, http.StatusOK, resp)
+}
+
+// VisionJSON handles POST /api/agents/{id}/vision/json to execute vision analysis
+// on stored images and public image URLs referenced in a JSON body.
+func (h *Handler) VisionJSON(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var req VisionRequest
+	if err := handlers.DecodeJSON(w, r, &req, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	images, err := h.sys.ResolveImages(r.Context(), req.ImageIDs, req.ImageURLs)
 	if err != nil {
 		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
+	ctx, cacheStatus := withCacheStatus(r.Context())
+
+	resp, err := h.sys.Vision(ctx, id, req.Prompt, images, req.Options, req.Token)
+	if err != nil {
+		h.respondExecError(w, err)
+		return
+	}
+
+	writeCacheHeader(w, *cacheStatus)
 	handlers.RespondJSON(w, http.StatusOK, resp)
 }
 
@@ -245,11 +355,11 @@ func (h *Handler) VisionStream(w http.ResponseWriter, r *http.Request) {
 
 	stream, err := h.sys.VisionStream(r.Context(), id, form.Prompt, form.Images, form.Options, form.Token)
 	if err != nil {
-		handlers.RespondError(w, h.logger, MapHTTPStatus(fmt.Errorf("%w: %v", ErrExecution, err)), err)
+		h.respondExecError(w, err)
 		return
 	}
 
-	h.writeSSEStream(w, r, stream)
+	writeSSEStream(w, r, h.logger, stream, chunkFrame)
 }
 
 // Tools handles POST /api/agents/{id}/tools to execute tool-calling with provided tool definitions.
@@ -261,20 +371,43 @@ func (h *Handler) Tools(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req ToolsRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &req, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
 	resp, err := h.sys.Tools(r.Context(), id, req.Prompt, req.Tools, req.Options, req.Token)
 	if err != nil {
-		handlers.RespondError(w, h.logger, MapHTTPStatus(fmt.Errorf("%w: %v", ErrExecution, err)), err)
+		h.respondExecError(w, err)
 		return
 	}
 
 	handlers.RespondJSON(w, http.StatusOK, resp)
 }
 
+// ToolsStream handles POST /api/agents/{id}/tools/stream to execute tool-calling with SSE events.
+func (h *Handler) ToolsStream(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var req ToolsRequest
+	if err := handlers.DecodeJSON(w, r, &req, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	events, err := h.sys.ToolsStream(r.Context(), id, req.Prompt, req.Tools, req.Options, req.Token)
+	if err != nil {
+		h.respondExecError(w, err)
+		return
+	}
+
+	writeSSEStream(w, r, h.logger, events, toolsFrame)
+}
+
 // Embed handles POST /api/agents/{id}/embed to generate text embeddings.
 func (h *Handler) Embed(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
@@ -284,59 +417,190 @@ func (h *Handler) Embed(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req EmbedRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &req, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
 	resp, err := h.sys.Embed(r.Context(), id, req.Input, req.Options, req.Token)
 	if err != nil {
-		handlers.RespondError(w, h.logger, MapHTTPStatus(fmt.Errorf("%w: %v", ErrExecution, err)), err)
+		h.respondExecError(w, err)
 		return
 	}
 
 	handlers.RespondJSON(w, http.StatusOK, resp)
 }
 
-func (h *Handler) writeSSEStream(w http.ResponseWriter, r *http.Request, stream <-chan *response.StreamingChunk) {
+// EmbedBatch handles POST /api/agents/{id}/embed/batch to generate embeddings for multiple inputs.
+func (h *Handler) EmbedBatch(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var req EmbedBatchRequest
+	if err := handlers.DecodeJSON(w, r, &req, embedBatchSize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	resp, err := h.sys.EmbedBatch(r.Context(), id, req.Inputs, req.Options, req.Token)
+	if err != nil {
+		h.respondExecError(w, err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, resp)
+}
+
+// Usage handles GET /api/agents/{id}/usage to summarize usage for a single agent.
+func (h *Handler) Usage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	h.respondUsage(w, r, &id)
+}
+
+// GlobalUsage handles GET /api/usage to summarize usage across all agents.
+func (h *Handler) GlobalUsage(w http.ResponseWriter, r *http.Request) {
+	h.respondUsage(w, r, nil)
+}
+
+func (h *Handler) respondUsage(w http.ResponseWriter, r *http.Request, id *uuid.UUID) {
+	from, to, err := UsageRangeFromQuery(r.URL.Query())
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	summary, err := h.sys.UsageSummary(r.Context(), id, from, to)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	if handlers.WantsCSV(r) {
+		handlers.RespondCSV(w, h.logger, "usage.csv", func(enc *handlers.CSVEncoder[UsageDay]) error {
+			for _, day := range summary.Days {
+				if err := enc.Encode(day); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, summary)
+}
+
+func writeCacheHeader(w http.ResponseWriter, status string) {
+	if status != "" {
+		w.Header().Set("X-Cache", status)
+	}
+}
+
+// respondExecError writes an agent execution failure, adding a Retry-After
+// header when a rate-limited provider reported one.
+func (h *Handler) respondExecError(w http.ResponseWriter, err error) {
+	if d, ok := RetryAfter(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+	handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+}
+
+func startSSE(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.WriteHeader(http.StatusOK)
+	flushSSE(w)
+}
 
-	if f, ok := w.(http.Flusher); ok {
-		f.Flush()
+// sseKeepalive is how long a stream may stay idle before a keepalive comment
+// is written, so clients and proxies do not drop a connection that is waiting
+// on a slow provider.
+const sseKeepalive = 15 * time.Second
+
+// sseFrame is a single SSE frame. An empty Event writes an unnamed data frame.
+// End marks the last frame of the stream.
+type sseFrame struct {
+	Event string
+	Data  any
+	End   bool
+}
+
+// chunkFrame frames a streaming chunk. A chunk error becomes an error event
+// that ends the stream.
+func chunkFrame(chunk *response.StreamingChunk) sseFrame {
+	if chunk.Error != nil {
+		return sseFrame{Event: "error", Data: map[string]string{"error": chunk.Error.Error()}, End: true}
 	}
+	return sseFrame{Data: chunk}
+}
 
-	for chunk := range stream {
-		if chunk.Error != nil {
-			data, _ := json.Marshal(map[string]string{"error": chunk.Error.Error()})
-			fmt.Fprintf(w, "data: %s\n\n", data)
-			if f, ok := w.(http.Flusher); ok {
-				f.Flush()
-			}
-			return
-		}
+// toolsFrame frames a tool event as a named event. An error event ends the stream.
+func toolsFrame(event ToolsEvent) sseFrame {
+	return sseFrame{Event: string(event.Type), Data: event, End: event.Type == ToolsEventError}
+}
+
+// writeSSEStream writes stream values as SSE frames built by frame. While the
+// stream is idle a keepalive comment is written every sseKeepalive. A frame
+// marked End stops the stream; every stream the client is still connected to
+// ends with the [DONE] sentinel, so an errored stream is distinguishable from
+// a dropped connection.
+func writeSSEStream[T any](w http.ResponseWriter, r *http.Request, logger *slog.Logger, stream <-chan T, frame func(T) sseFrame) {
+	startSSE(w)
 
+	keepalive := time.NewTicker(sseKeepalive)
+	defer keepalive.Stop()
+
+	for {
 		select {
 		case <-r.Context().Done():
 			return
-		default:
-		}
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flushSSE(w)
+		case value, ok := <-stream:
+			if !ok {
+				writeSSEDone(w)
+				return
+			}
 
-		data, err := json.Marshal(chunk)
-		if err != nil {
-			h.logger.Error("failed to marshal chunk", "error", err)
-			continue
-		}
+			f := frame(value)
+			data, err := json.Marshal(f.Data)
+			if err != nil {
+				logger.Error("failed to marshal stream frame", "error", err)
+				continue
+			}
+
+			if f.Event != "" {
+				fmt.Fprintf(w, "event: %s\n", f.Event)
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flushSSE(w)
 
-		fmt.Fprintf(w, "data: %s\n\n", data)
-		if f, ok := w.(http.Flusher); ok {
-			f.Flush()
+			if f.End {
+				writeSSEDone(w)
+				return
+			}
+			keepalive.Reset(sseKeepalive)
 		}
 	}
+}
 
+// writeSSEDone writes the [DONE] sentinel that terminates an SSE stream.
+func writeSSEDone(w http.ResponseWriter) {
 	fmt.Fprintf(w, "data: [DONE]\n\n")
+	flushSSE(w)
+}
+
+func flushSSE(w http.ResponseWriter) {
 	if f, ok := w.(http.Flusher); ok {
 		f.Flush()
 	}
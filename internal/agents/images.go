@@ -0,0 +1,219 @@
+package agents
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limits applied when fetching vision images by URL.
+const (
+	DefaultImageFetchTimeout       = 15 * time.Second
+	DefaultMaxImageBytes     int64 = 20 << 20
+)
+
+// ImageSource loads stored image bytes and their content type by image ID.
+// images.System satisfies it.
+type ImageSource interface {
+	Data(ctx context.Context, id uuid.UUID) ([]byte, string, error)
+}
+
+// deniedPrefixes are address ranges that image URLs may never reach in
+// addition to loopback, private, link-local, multicast, and unspecified
+// addresses.
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// PublicAddr reports whether addr is a publicly routable address that image
+// URLs are allowed to reach.
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+	for _, p := range deniedPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// ImageFetcher downloads vision images from public http and https URLs.
+// Every connection, including those made for redirects, is checked after
+// DNS resolution, so hostnames that resolve to loopback, private, or
+// link-local addresses are refused.
+type ImageFetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+// NewImageFetcher creates an ImageFetcher with the given request timeout and
+// response size limit.
+func NewImageFetcher(timeout time.Duration, maxBytes int64) *ImageFetcher {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrImageURLNotAllowed, address)
+			}
+			if !PublicAddr(ap.Addr()) {
+				return fmt.Errorf("%w: %s is not a public address", ErrImageURLNotAllowed, ap.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+	}
+
+	return &ImageFetcher{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				return ValidateImageURL(req.URL)
+			},
+		},
+		maxBytes: maxBytes,
+	}
+}
+
+// ValidateImageURL checks that u is an absolute http or https URL whose host
+// is not a literal non-public address or localhost. Hostnames are resolved
+// and checked again when the fetcher connects.
+func ValidateImageURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be http or https", ErrImageURLNotAllowed)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: missing host", ErrImageURLNotAllowed)
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrImageURLNotAllowed, host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !PublicAddr(addr) {
+		return fmt.Errorf("%w: %s is not a public address", ErrImageURLNotAllowed, addr)
+	}
+
+	return nil
+}
+
+// Fetch downloads the image at rawURL and returns it as a base64 data URI.
+// Returns ErrImageURLNotAllowed if the URL or any address it reaches is not
+// permitted, and ErrImageUnavailable if the download fails or the response
+// is not an image within the size limit.
+func (f *ImageFetcher) Fetch(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrImageURLNotAllowed, err)
+	}
+	if err := ValidateImageURL(u); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrImageURLNotAllowed, err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrImageURLNotAllowed) {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: fetch %s: %v", ErrImageUnavailable, u.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: fetch %s: status %d", ErrImageUnavailable, u.Redacted(), resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("%w: read %s: %v", ErrImageUnavailable, u.Redacted(), err)
+	}
+	if int64(len(data)) > f.maxBytes {
+		return "", fmt.Errorf("%w: %s exceeds %d bytes", ErrImageUnavailable, u.Redacted(), f.maxBytes)
+	}
+
+	return dataURI(data, "")
+}
+
+// ResolveImages converts stored image IDs and image URLs into base64 data
+// URIs for Vision, IDs first and then URLs, each in request order.
+func ResolveImages(ctx context.Context, src ImageSource, fetcher *ImageFetcher, ids []uuid.UUID, urls []string) ([]string, error) {
+	images := make([]string, 0, len(ids)+len(urls))
+
+	for _, id := range ids {
+		if src == nil {
+			return nil, fmt.Errorf("%w: no image store configured", ErrImageUnavailable)
+		}
+		data, contentType, err := src.Data(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: image %s: %v", ErrImageUnavailable, id, err)
+		}
+		uri, err := dataURI(data, contentType)
+		if err != nil {
+			return nil, fmt.Errorf("image %s: %w", id, err)
+		}
+		images = append(images, uri)
+	}
+
+	for _, u := range urls {
+		uri, err := fetcher.Fetch(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, uri)
+	}
+
+	return images, nil
+}
+
+// dataURI encodes data as a base64 data URI. The content type is sniffed when
+// contentType is empty, and the result must be an image type.
+func dataURI(data []byte, contentType string) (string, error) {
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("%w: content is not an image (detected: %s)", ErrImageUnavailable, contentType)
+	}
+	return fmt.Sprintf("data:%s;base64,%s", contentType, base64.StdEncoding.EncodeToString(data)), nil
+}
@@ -1,6 +1,7 @@
 package agents
 
 import (
+	"encoding/json"
 	"net/url"
 
 	"github.com/JaimeStill/agent-lab/pkg/query"
@@ -10,22 +11,46 @@ import (
 var projection = query.
 	NewProjectionMap("public", "agents", "a").
 	Project("id", "ID").
-	Project("name", "Name").
+	ProjectText("name", "Name").
+	Project("provider_id", "ProviderID").
 	Project("config", "Config").
 	Project("created_at", "CreatedAt").
-	Project("updated_at", "UpdatedAt")
+	Project("updated_at", "UpdatedAt").
+	Sortable("ID", "Name", "ProviderID", "CreatedAt", "UpdatedAt")
 
 var defaultSort = query.SortField{Field: "Name"}
 
 func scanAgent(s repository.Scanner) (Agent, error) {
 	var a Agent
-	err := s.Scan(&a.ID, &a.Name, &a.Config, &a.CreatedAt, &a.UpdatedAt)
+	err := s.Scan(&a.ID, &a.Name, &a.ProviderID, &a.Config, &a.CreatedAt, &a.UpdatedAt)
 	return a, err
 }
 
+func scanUsageRow(s repository.Scanner) (UsageRow, error) {
+	var u UsageRow
+	err := s.Scan(&u.Day, &u.Model, &u.Calls, &u.PromptTokens, &u.CompletionTokens, &u.TotalTokens)
+	return u, err
+}
+
+type storedEmbedding struct {
+	hash   string
+	vector []float64
+}
+
+func scanStoredEmbedding(s repository.Scanner) (storedEmbedding, error) {
+	var e storedEmbedding
+	var raw []byte
+	if err := s.Scan(&e.hash, &raw); err != nil {
+		return e, err
+	}
+	err := json.Unmarshal(raw, &e.vector)
+	return e, err
+}
+
 // Filters contains optional filtering criteria for agent queries.
 type Filters struct {
-	Name *string
+	Name     *string
+	Provider *string
 }
 
 // FiltersFromQuery extracts filter values from URL query parameters.
@@ -35,12 +60,21 @@ func FiltersFromQuery(values url.Values) Filters {
 		name = &n
 	}
 
+	var provider *string
+	if p := values.Get("provider"); p != "" {
+		provider = &p
+	}
+
 	return Filters{
-		Name: name,
+		Name:     name,
+		Provider: provider,
 	}
 }
 
 // Apply adds filter conditions to a query builder.
+// Provider matches the provider name stored in the agent config.
 func (f Filters) Apply(b *query.Builder) *query.Builder {
-	return b.WhereContains("Name", f.Name)
+	return b.
+		WhereContains("Name", f.Name).
+		WhereJSONEquals("Config", "provider.name", f.Provider)
 }
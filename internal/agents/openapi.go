@@ -9,16 +9,47 @@ type spec struct {
 	Search       *openapi.Operation
 	Create       *openapi.Operation
 	Update       *openapi.Operation
+	Clone        *openapi.Operation
 	Delete       *openapi.Operation
 	Chat         *openapi.Operation
 	ChatStream   *openapi.Operation
 	Vision       *openapi.Operation
+	VisionJSON   *openapi.Operation
 	VisionStream *openapi.Operation
 	Tools        *openapi.Operation
+	ToolsStream  *openapi.Operation
 	Embed        *openapi.Operation
+	EmbedBatch   *openapi.Operation
+	Usage        *openapi.Operation
+	GlobalUsage  *openapi.Operation
 }
 
 // Spec contains OpenAPI operation definitions for all agent endpoints.
+// Sample request payloads rendered by the API docs.
+var (
+	createAgentExample = map[string]any{
+		"name": "ollama-agent",
+		"config": map[string]any{
+			"name": "ollama-agent",
+			"provider": map[string]any{
+				"name":     "ollama",
+				"base_url": "http://localhost:11434",
+			},
+			"model": map[string]any{
+				"name": "gemma3:4b",
+				"capabilities": map[string]any{
+					"chat": map[string]any{"max_tokens": 4096, "temperature": 0.7},
+				},
+			},
+		},
+	}
+
+	chatExample = map[string]any{
+		"prompt":  "Summarize the key risks in two sentences.",
+		"options": map[string]any{"temperature": 0.2},
+	}
+)
+
 var Spec = spec{
 	List: &openapi.Operation{
 		Summary:     "List agents",
@@ -27,11 +58,14 @@ var Spec = spec{
 			openapi.QueryParam("page", "integer", "Page number (1-indexed)", false),
 			openapi.QueryParam("page_size", "integer", "Results per page", false),
 			openapi.QueryParam("search", "string", "Search query (matches name)", false),
-			openapi.QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending", false),
+			openapi.QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending, suffix text fields with :ci for case-insensitive. Unknown fields return 400 listing the sortable fields", false),
 			openapi.QueryParam("name", "string", "Filter by agent name (contains)", false),
+			openapi.QueryParam("provider", "string", "Filter by configured provider name (exact, e.g. azure)", false),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Paginated list of agents", "AgentPageResult"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated list of agents", "AgentPageResult"), openapi.PageHeaders(true)),
+			400: openapi.ResponseRef("BadRequest"),
 		},
 	},
 	Find: &openapi.Operation{
@@ -39,6 +73,7 @@ var Spec = spec{
 		Description: "Retrieves a single agent configuration",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Agent UUID"),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
 			200: openapi.ResponseJSON("Agent configuration", "Agent"),
@@ -51,17 +86,19 @@ var Spec = spec{
 		Description: "Search agents with filters and pagination via POST body",
 		Parameters: []*openapi.Parameter{
 			openapi.QueryParam("name", "string", "Filter by agent name (contains)", false),
+			openapi.QueryParam("provider", "string", "Filter by configured provider name (exact, e.g. azure)", false),
+			openapi.FieldsParam(),
 		},
 		RequestBody: openapi.RequestBodyJSON("PageRequest", false),
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Paginated search results", "AgentPageResult"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated search results", "AgentPageResult"), openapi.PageHeaders(false)),
 			400: openapi.ResponseRef("BadRequest"),
 		},
 	},
 	Create: &openapi.Operation{
 		Summary:     "Create agent",
 		Description: "Validates and stores a new agent configuration",
-		RequestBody: openapi.RequestBodyJSON("CreateAgentCommand", true),
+		RequestBody: openapi.RequestBodyJSON("CreateAgentCommand", true).WithExample(createAgentExample),
 		Responses: map[int]*openapi.Response{
 			201: openapi.ResponseJSON("Agent created", "Agent"),
 			400: openapi.ResponseRef("BadRequest"),
@@ -82,6 +119,22 @@ var Spec = spec{
 			409: openapi.ResponseRef("Conflict"),
 		},
 	},
+	Clone: &openapi.Operation{
+		Summary: "Clone agent",
+		Description: "Creates a new agent with a copy of an existing agent's config. " +
+			"All config fields are copied except credentials (token, api_key, secret, and similar keys), " +
+			"which must be supplied at request time. A provider reference is kept, so the clone uses the provider's credentials.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Source agent UUID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("CloneAgentCommand", true),
+		Responses: map[int]*openapi.Response{
+			201: openapi.ResponseJSON("Agent cloned", "Agent"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			409: openapi.ResponseRef("Conflict"),
+		},
+	},
 	Delete: &openapi.Operation{
 		Summary:     "Delete agent",
 		Description: "Removes an agent configuration",
@@ -99,7 +152,7 @@ var Spec = spec{
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Agent UUID"),
 		},
-		RequestBody: openapi.RequestBodyJSON("ChatRequest", true),
+		RequestBody: openapi.RequestBodyJSON("ChatRequest", true).WithExample(chatExample),
 		Responses: map[int]*openapi.Response{
 			200: openapi.ResponseJSON("Chat response", "ChatResponse"),
 			400: openapi.ResponseRef("BadRequest"),
@@ -112,9 +165,9 @@ var Spec = spec{
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Agent UUID"),
 		},
-		RequestBody: openapi.RequestBodyJSON("ChatRequest", true),
+		RequestBody: openapi.RequestBodyJSON("ChatRequest", true).WithExample(chatExample),
 		Responses: map[int]*openapi.Response{
-			200: {Description: "SSE stream of chat response chunks"},
+			200: {Description: "SSE stream of chat response chunks, ending with data: [DONE]; failures arrive as an error event before it"},
 			400: openapi.ResponseRef("BadRequest"),
 			404: openapi.ResponseRef("NotFound"),
 		},
@@ -146,6 +199,20 @@ var Spec = spec{
 			404: openapi.ResponseRef("NotFound"),
 		},
 	},
+	VisionJSON: &openapi.Operation{
+		Summary:     "Vision analysis (JSON)",
+		Description: "Execute agent vision analysis on stored images and public image URLs. URLs must be http or https and may not resolve to loopback, private, or link-local addresses.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Agent UUID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("VisionRequest", true),
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Vision analysis response", "ChatResponse"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			422: {Description: "A referenced image could not be loaded or is not an image"},
+		},
+	},
 	VisionStream: &openapi.Operation{
 		Summary:     "Vision analysis (streaming)",
 		Description: "Execute agent vision analysis with SSE streaming",
@@ -168,7 +235,7 @@ var Spec = spec{
 			},
 		},
 		Responses: map[int]*openapi.Response{
-			200: {Description: "SSE stream of vision analysis chunks"},
+			200: {Description: "SSE stream of vision analysis chunks, ending with data: [DONE]; failures arrive as an error event before it"},
 			400: openapi.ResponseRef("BadRequest"),
 			404: openapi.ResponseRef("NotFound"),
 		},
@@ -186,6 +253,19 @@ var Spec = spec{
 			404: openapi.ResponseRef("NotFound"),
 		},
 	},
+	ToolsStream: &openapi.Operation{
+		Summary:     "Execute with tools (streaming)",
+		Description: "Execute agent with tool calling capabilities as SSE events. A start event is sent when the provider call begins and keepalive comments while it runs; the tool_call, content, and done events are replayed from the complete provider response, or an error event on failure. Tools are not executed, so no tool results are sent.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Agent UUID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("ToolsRequest", true),
+		Responses: map[int]*openapi.Response{
+			200: {Description: "SSE stream of tool execution events"},
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
 	Embed: &openapi.Operation{
 		Summary:     "Generate embeddings",
 		Description: "Generate text embeddings using agent",
@@ -199,6 +279,46 @@ var Spec = spec{
 			404: openapi.ResponseRef("NotFound"),
 		},
 	},
+	EmbedBatch: &openapi.Operation{
+		Summary:     "Generate embeddings (batch)",
+		Description: "Generate text embeddings for up to 256 inputs, returned in input order. Set options.store to reuse stored vectors for identical inputs.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Agent UUID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("EmbedBatchRequest", true),
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Embedding vectors", "EmbedBatchResponse"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
+	Usage: &openapi.Operation{
+		Summary:     "Agent usage summary",
+		Description: "Aggregates call counts, token usage, and estimated cost for an agent, grouped by day. Request CSV with format=csv or Accept: text/csv for one row per day",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Agent UUID"),
+			openapi.QueryParam("from", "string", "Range start (RFC 3339 or YYYY-MM-DD, inclusive). Defaults to 30 days before to", false),
+			openapi.QueryParam("to", "string", "Range end (RFC 3339 or YYYY-MM-DD, exclusive). Defaults to now", false),
+			openapi.QueryParam("format", "string", "Set to csv to stream the daily rows as CSV", false),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseWithCSV(openapi.ResponseJSON("Usage summary", "UsageSummary")),
+			400: openapi.ResponseRef("BadRequest"),
+		},
+	},
+	GlobalUsage: &openapi.Operation{
+		Summary:     "Global usage summary",
+		Description: "Aggregates call counts, token usage, and estimated cost across all agents, grouped by day. Request CSV with format=csv or Accept: text/csv for one row per day",
+		Parameters: []*openapi.Parameter{
+			openapi.QueryParam("from", "string", "Range start (RFC 3339 or YYYY-MM-DD, inclusive). Defaults to 30 days before to", false),
+			openapi.QueryParam("to", "string", "Range end (RFC 3339 or YYYY-MM-DD, exclusive). Defaults to now", false),
+			openapi.QueryParam("format", "string", "Set to csv to stream the daily rows as CSV", false),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseWithCSV(openapi.ResponseJSON("Usage summary", "UsageSummary")),
+			400: openapi.ResponseRef("BadRequest"),
+		},
+	},
 }
 
 // Schemas returns the agent domain schemas for OpenAPI components.
@@ -207,27 +327,37 @@ func (spec) Schemas() map[string]*openapi.Schema {
 		"Agent": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
-				"id":         {Type: "string", Format: "uuid"},
-				"name":       {Type: "string"},
-				"config":     {Type: "object", Description: "go-agents AgentConfig as JSON (includes embedded provider config)"},
-				"created_at": {Type: "string", Format: "date-time"},
-				"updated_at": {Type: "string", Format: "date-time"},
+				"id":          {Type: "string", Format: "uuid"},
+				"name":        {Type: "string"},
+				"provider_id": {Type: "string", Format: "uuid", Description: "Referenced provider record supplying provider config and credentials"},
+				"config":      {Type: "object", Description: "go-agents AgentConfig as JSON (includes embedded provider config)"},
+				"created_at":  {Type: "string", Format: "date-time"},
+				"updated_at":  {Type: "string", Format: "date-time"},
 			},
 		},
 		"CreateAgentCommand": {
 			Type:     "object",
 			Required: []string{"name", "config"},
 			Properties: map[string]*openapi.Schema{
-				"name":   {Type: "string", Example: "gpt-4o"},
-				"config": {Type: "object", Description: "go-agents AgentConfig as JSON"},
+				"name":        {Type: "string", Example: "gpt-4o"},
+				"provider_id": {Type: "string", Format: "uuid", Description: "Optional provider record whose config and credentials override the provider in config at call time"},
+				"config":      {Type: "object", Description: "go-agents AgentConfig as JSON"},
 			},
 		},
 		"UpdateAgentCommand": {
 			Type:     "object",
 			Required: []string{"name", "config"},
 			Properties: map[string]*openapi.Schema{
-				"name":   {Type: "string"},
-				"config": {Type: "object", Description: "go-agents AgentConfig as JSON"},
+				"name":        {Type: "string"},
+				"provider_id": {Type: "string", Format: "uuid", Description: "Optional provider record whose config and credentials override the provider in config at call time"},
+				"config":      {Type: "object", Description: "go-agents AgentConfig as JSON"},
+			},
+		},
+		"CloneAgentCommand": {
+			Type:     "object",
+			Required: []string{"name"},
+			Properties: map[string]*openapi.Schema{
+				"name": {Type: "string", Example: "gpt-4o-copy"},
 			},
 		},
 		"AgentPageResult": {
@@ -246,7 +376,7 @@ func (spec) Schemas() map[string]*openapi.Schema {
 			Properties: map[string]*openapi.Schema{
 				"prompt":  {Type: "string", Description: "User prompt"},
 				"token":   {Type: "string", Description: "Optional authentication token (for Azure providers)"},
-				"options": {Type: "object", Description: "Optional agent options override"},
+				"options": {Type: "object", Description: "Optional agent options override. Well-known keys are validated: temperature (0-2), top_p (0-1), and max_tokens (positive integer); other keys pass through to the provider"},
 			},
 		},
 		"ChatResponse": {
@@ -255,6 +385,17 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"response": {Type: "string", Description: "Agent response text"},
 			},
 		},
+		"VisionRequest": {
+			Type:     "object",
+			Required: []string{"prompt"},
+			Properties: map[string]*openapi.Schema{
+				"prompt":     {Type: "string", Description: "Analysis prompt"},
+				"image_ids":  {Type: "array", Items: &openapi.Schema{Type: "string", Format: "uuid"}, Description: "Stored image IDs"},
+				"image_urls": {Type: "array", Items: &openapi.Schema{Type: "string", Format: "uri"}, Description: "Public http(s) image URLs; at least one image ID or URL is required, up to 10 in total"},
+				"token":      {Type: "string", Description: "Optional authentication token"},
+				"options":    {Type: "object", Description: "Optional agent options override"},
+			},
+		},
 		"ToolsRequest": {
 			Type:     "object",
 			Required: []string{"prompt", "tools"},
@@ -280,6 +421,50 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"embedding": {Type: "array", Description: "Embedding vector"},
 			},
 		},
+		"EmbedBatchRequest": {
+			Type:     "object",
+			Required: []string{"inputs"},
+			Properties: map[string]*openapi.Schema{
+				"inputs":  {Type: "array", Description: "Texts to embed", Items: &openapi.Schema{Type: "string"}},
+				"token":   {Type: "string", Description: "Optional authentication token"},
+				"options": {Type: "object", Description: "Optional embedding options; store persists vectors for reuse"},
+			},
+		},
+		"EmbedBatchResponse": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"model":      {Type: "string"},
+				"embeddings": {Type: "array", Description: "Embedding vectors in input order"},
+				"reused":     {Type: "integer", Description: "Inputs served from stored vectors"},
+				"usage":      {Type: "object"},
+			},
+		},
+		"UsageDay": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"date":              {Type: "string", Format: "date"},
+				"calls":             {Type: "integer"},
+				"prompt_tokens":     {Type: "integer"},
+				"completion_tokens": {Type: "integer"},
+				"total_tokens":      {Type: "integer"},
+				"estimated_cost":    {Type: "number", Description: "Estimated cost in USD"},
+			},
+		},
+		"UsageSummary": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"agent_id":          {Type: "string", Format: "uuid", Description: "Omitted for global summaries"},
+				"from":              {Type: "string", Format: "date-time"},
+				"to":                {Type: "string", Format: "date-time"},
+				"calls":             {Type: "integer"},
+				"prompt_tokens":     {Type: "integer"},
+				"completion_tokens": {Type: "integer"},
+				"total_tokens":      {Type: "integer"},
+				"estimated_cost":    {Type: "number", Description: "Estimated cost in USD"},
+				"days":              {Type: "array", Items: openapi.SchemaRef("UsageDay")},
+				"unpriced_models":   {Type: "array", Items: &openapi.Schema{Type: "string"}, Description: "Models without a configured price (cost counted as zero)"},
+			},
+		},
 	}
 }
 
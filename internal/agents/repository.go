@@ -4,11 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/providers"
+	"github.com/JaimeStill/agent-lab/pkg/events"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/agent-lab/pkg/redact"
 	"github.com/JaimeStill/agent-lab/pkg/repository"
 	"github.com/JaimeStill/go-agents/pkg/agent"
 	agtconfig "github.com/JaimeStill/go-agents/pkg/config"
@@ -18,16 +24,43 @@ import (
 
 type repo struct {
 	db         *sql.DB
+	providers  providers.System
+	images     ImageSource
+	fetcher    *ImageFetcher
+	events     *events.Bus
 	logger     *slog.Logger
 	pagination pagination.Config
+	prices     config.PriceTable
+	cache      *ResponseCache
+	configs    *ConfigCache
+	debug      *RequestLogger
+	client     config.ProviderHTTPConfig
 }
 
 // New creates a new agents repository implementing the System interface.
-func New(db *sql.DB, logger *slog.Logger, pagination pagination.Config) System {
+// Agents that reference a provider record resolve its config and credentials
+// through providers on every call, so rotated credentials apply immediately.
+// Images resolves stored image IDs for JSON vision requests and may be nil.
+// Prices are used to estimate cost in usage summaries.
+// Agent lifecycle events are published to bus, which may be nil.
+// When debug is enabled, provider requests and responses are logged at DEBUG level.
+// Client sets the default request timeout and connection pool of agent
+// executors; an agent's own client config overrides it.
+func New(providers providers.System, images ImageSource, db *sql.DB, bus *events.Bus, logger *slog.Logger, pagination pagination.Config, prices config.PriceTable, debug config.AgentDebugConfig, client config.ProviderHTTPConfig) System {
+	logger = logger.With("system", "agent")
 	return &repo{
 		db:         db,
-		logger:     logger.With("system", "agent"),
+		providers:  providers,
+		images:     images,
+		fetcher:    NewImageFetcher(DefaultImageFetchTimeout, DefaultMaxImageBytes),
+		events:     bus,
+		logger:     logger,
 		pagination: pagination,
+		prices:     prices,
+		cache:      NewResponseCache(DefaultCacheTTL, DefaultCacheMaxEntries),
+		configs:    NewConfigCache(),
+		debug:      NewRequestLogger(logger, debug),
+		client:     client,
 	}
 }
 
@@ -44,8 +77,8 @@ func (r *repo) List(ctx context.Context, page pagination.PageRequest, filters Fi
 
 	filters.Apply(qb)
 
-	if len(page.Sort) > 0 {
-		qb.OrderByFields(page.Sort)
+	if err := qb.SortBy(page.Sort); err != nil {
+		return nil, err
 	}
 
 	countSql, countArgs := qb.BuildCount()
@@ -75,17 +108,23 @@ func (r *repo) Find(ctx context.Context, id uuid.UUID) (*Agent, error) {
 }
 
 func (r *repo) Create(ctx context.Context, cmd CreateCommand) (*Agent, error) {
-	if err := r.validateConfig(cmd.Config); err != nil {
+	if err := cmd.Validate(); err != nil {
 		return nil, err
 	}
 
+	if cmd.ProviderID != nil {
+		if err := r.validateConfig(ctx, cmd.Config, cmd.ProviderID); err != nil {
+			return nil, err
+		}
+	}
+
 	q := `
-		INSERT INTO agents (name, config)
-		VALUES ($1, $2)
-		RETURNING id, name, config, created_at, updated_at`
+		INSERT INTO agents (name, config, provider_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, provider_id, config, created_at, updated_at`
 
 	a, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Agent, error) {
-		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, cmd.Config}, scanAgent)
+		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, cmd.Config, cmd.ProviderID}, scanAgent)
 	})
 
 	if err != nil {
@@ -93,29 +132,66 @@ func (r *repo) Create(ctx context.Context, cmd CreateCommand) (*Agent, error) {
 	}
 
 	r.logger.Info("agent created", "id", a.ID, "name", a.Name)
+	r.events.Publish(ctx, events.Event{Type: EventCreated, Subject: a.ID.String(), Data: a})
 	return &a, nil
 }
 
 func (r *repo) Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Agent, error) {
-	if err := r.validateConfig(cmd.Config); err != nil {
+	if err := r.validateConfig(ctx, cmd.Config, cmd.ProviderID); err != nil {
 		return nil, err
 	}
 
 	q := `
 		UPDATE agents
-		SET name = $1, config = $2, updated_at = NOW()
-		WHERE id = $3
-		RETURNING id, name, config, created_at, updated_at`
+		SET name = $1, config = $2, provider_id = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING id, name, provider_id, config, created_at, updated_at`
 
 	a, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Agent, error) {
-		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, cmd.Config, id}, scanAgent)
+		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, cmd.Config, cmd.ProviderID, id}, scanAgent)
 	})
 
 	if err != nil {
 		return nil, repository.MapError(err, ErrNotFound, ErrDuplicate)
 	}
 
+	r.configs.Invalidate(id)
+	r.cache.Invalidate(id)
 	r.logger.Info("agent updated", "id", a.ID, "name", a.Name)
+	r.events.Publish(ctx, events.Event{Type: EventUpdated, Subject: a.ID.String(), Data: a})
+	return &a, nil
+}
+
+func (r *repo) Clone(ctx context.Context, id uuid.UUID, cmd CloneCommand) (*Agent, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	source, err := r.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := redact.Config(source.Config)
+	if err != nil {
+		return nil, fmt.Errorf("redact agent config: %w", err)
+	}
+
+	q := `
+		INSERT INTO agents (name, config, provider_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, provider_id, config, created_at, updated_at`
+
+	a, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Agent, error) {
+		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, config, source.ProviderID}, scanAgent)
+	})
+
+	if err != nil {
+		return nil, repository.MapError(err, ErrNotFound, ErrDuplicate)
+	}
+
+	r.logger.Info("agent cloned", "id", a.ID, "name", a.Name, "source", id)
+	r.events.Publish(ctx, events.Event{Type: EventCreated, Subject: a.ID.String(), Data: a})
 	return &a, nil
 }
 
@@ -129,108 +205,365 @@ func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
 		return repository.MapError(err, ErrNotFound, ErrDuplicate)
 	}
 
+	r.configs.Invalidate(id)
+	r.cache.Invalidate(id)
 	r.logger.Info("agent deleted", "id", id)
+	r.events.Publish(ctx, events.Event{Type: EventDeleted, Subject: id.String()})
 	return nil
 }
 
 func (r *repo) Chat(ctx context.Context, id uuid.UUID, prompt string, opts map[string]any, token string) (*response.ChatResponse, error) {
+	return r.cached(ctx, id, token, prompt, nil, opts, func() (*response.ChatResponse, error) {
+		agt, err := r.constructAgent(ctx, id, token, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		cid := r.debug.Request(ctx, DebugRequest{Capability: "chat", AgentID: id, Prompt: prompt, Options: opts, Token: token})
+		resp, err := agt.Chat(ctx, prompt)
+		r.debug.Response(ctx, cid, resp, err)
+		if err != nil {
+			return nil, ClassifyProviderError(err)
+		}
+
+		r.recordUsage(ctx, id, "chat", resp.Model, resp.Usage)
+		return resp, nil
+	})
+}
+
+func (r *repo) ChatStream(ctx context.Context, id uuid.UUID, prompt string, opts map[string]any, token string) (<-chan *response.StreamingChunk, error) {
 	agt, err := r.constructAgent(ctx, id, token, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := agt.Chat(ctx, prompt)
+	r.debug.Request(ctx, DebugRequest{Capability: "chat_stream", AgentID: id, Prompt: prompt, Options: opts, Token: token})
+	stream, err := agt.ChatStream(ctx, prompt)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrExecution, err)
+		return nil, ClassifyProviderError(err)
 	}
 
-	return resp, nil
+	return stream, nil
 }
 
-func (r *repo) ChatStream(ctx context.Context, id uuid.UUID, prompt string, opts map[string]any, token string) (<-chan *response.StreamingChunk, error) {
+func (r *repo) Vision(ctx context.Context, id uuid.UUID, prompt string, images []string, opts map[string]any, token string) (*response.ChatResponse, error) {
+	return r.cached(ctx, id, token, prompt, images, opts, func() (*response.ChatResponse, error) {
+		agt, err := r.constructAgent(ctx, id, token, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		cid := r.debug.Request(ctx, DebugRequest{Capability: "vision", AgentID: id, Prompt: prompt, Images: images, Options: opts, Token: token})
+		resp, err := agt.Vision(ctx, prompt, images)
+		r.debug.Response(ctx, cid, resp, err)
+		if err != nil {
+			return nil, ClassifyProviderError(err)
+		}
+
+		r.recordUsage(ctx, id, "vision", resp.Model, resp.Usage)
+		return resp, nil
+	})
+}
+
+func (r *repo) ResolveImages(ctx context.Context, ids []uuid.UUID, urls []string) ([]string, error) {
+	return ResolveImages(ctx, r.images, r.fetcher, ids, urls)
+}
+
+func (r *repo) VisionStream(ctx context.Context, id uuid.UUID, prompt string, images []string, opts map[string]any, token string) (<-chan *response.StreamingChunk, error) {
 	agt, err := r.constructAgent(ctx, id, token, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	stream, err := agt.ChatStream(ctx, prompt)
+	r.debug.Request(ctx, DebugRequest{Capability: "vision_stream", AgentID: id, Prompt: prompt, Images: images, Options: opts, Token: token})
+	stream, err := agt.VisionStream(ctx, prompt, images)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrExecution, err)
+		return nil, ClassifyProviderError(err)
 	}
 
 	return stream, nil
 }
 
-func (r *repo) Vision(ctx context.Context, id uuid.UUID, prompt string, images []string, opts map[string]any, token string) (*response.ChatResponse, error) {
+func (r *repo) Tools(ctx context.Context, id uuid.UUID, prompt string, tools []agent.Tool, opts map[string]any, token string) (*response.ToolsResponse, error) {
 	agt, err := r.constructAgent(ctx, id, token, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := agt.Vision(ctx, prompt, images)
+	cid := r.debug.Request(ctx, DebugRequest{Capability: "tools", AgentID: id, Prompt: prompt, Tools: tools, Options: opts, Token: token})
+	resp, err := agt.Tools(ctx, prompt, tools)
+	r.debug.Response(ctx, cid, resp, err)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrExecution, err)
+		return nil, ClassifyProviderError(err)
 	}
 
+	r.recordUsage(ctx, id, "tools", resp.Model, resp.Usage)
 	return resp, nil
 }
 
-func (r *repo) VisionStream(ctx context.Context, id uuid.UUID, prompt string, images []string, opts map[string]any, token string) (<-chan *response.StreamingChunk, error) {
+func (r *repo) ToolsStream(ctx context.Context, id uuid.UUID, prompt string, tools []agent.Tool, opts map[string]any, token string) (<-chan ToolsEvent, error) {
 	agt, err := r.constructAgent(ctx, id, token, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	stream, err := agt.VisionStream(ctx, prompt, images)
+	events := make(chan ToolsEvent)
+	go func() {
+		defer close(events)
+
+		send := func(event ToolsEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if !send(ToolsEvent{Type: ToolsEventStart}) {
+			return
+		}
+
+		cid := r.debug.Request(ctx, DebugRequest{Capability: "tools_stream", AgentID: id, Prompt: prompt, Tools: tools, Options: opts, Token: token})
+		resp, err := agt.Tools(ctx, prompt, tools)
+		r.debug.Response(ctx, cid, resp, err)
+		if err != nil {
+			send(ToolsEvent{Type: ToolsEventError, Error: ClassifyProviderError(err).Error()})
+			return
+		}
+
+		r.recordUsage(ctx, id, "tools", resp.Model, resp.Usage)
+
+		for _, event := range ToolsEvents(resp) {
+			if !send(event) {
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (r *repo) Embed(ctx context.Context, id uuid.UUID, input string, opts map[string]any, token string) (*response.EmbeddingsResponse, error) {
+	agt, err := r.constructAgent(ctx, id, token, opts)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrExecution, err)
+		return nil, err
 	}
 
-	return stream, nil
+	cid := r.debug.Request(ctx, DebugRequest{Capability: "embed", AgentID: id, Prompt: input, Options: opts, Token: token})
+	resp, err := agt.Embed(ctx, input)
+	r.debug.Response(ctx, cid, resp, err)
+	if err != nil {
+		return nil, ClassifyProviderError(err)
+	}
+
+	r.recordUsage(ctx, id, "embed", resp.Model, resp.Usage)
+	return resp, nil
 }
 
-func (r *repo) Tools(ctx context.Context, id uuid.UUID, prompt string, tools []agent.Tool, opts map[string]any, token string) (*response.ToolsResponse, error) {
+func (r *repo) EmbedBatch(ctx context.Context, id uuid.UUID, inputs []string, opts map[string]any, token string) (*EmbedBatchResponse, error) {
+	if err := ValidateBatch(inputs); err != nil {
+		return nil, err
+	}
+
 	agt, err := r.constructAgent(ctx, id, token, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := agt.Tools(ctx, prompt, tools)
+	model := agt.Model().Name
+	store := storeRequested(opts)
+
+	var known map[string][]float64
+	if store {
+		known, err = r.findEmbeddings(ctx, id, model, inputs)
+		if err != nil {
+			return nil, fmt.Errorf("find embeddings: %w", err)
+		}
+	}
+
+	cid := r.debug.Request(ctx, DebugRequest{Capability: "embed_batch", AgentID: id, Inputs: inputs, Options: opts, Token: token})
+	resp, fresh, err := EmbedBatch(ctx, inputs, known, r.batchEmbedder(agt))
+	r.debug.Response(ctx, cid, resp, err)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrExecution, err)
+		return nil, err
+	}
+	resp.Model = model
+
+	if len(fresh) == 0 {
+		return resp, nil
+	}
+
+	r.recordUsage(ctx, id, "embed", model, resp.Usage)
+
+	if store {
+		if err := r.storeEmbeddings(ctx, id, model, fresh); err != nil {
+			r.logger.Error("failed to store embeddings", "id", id, "error", err)
+		}
 	}
 
 	return resp, nil
 }
 
-func (r *repo) Embed(ctx context.Context, id uuid.UUID, input string, opts map[string]any, token string) (*response.EmbeddingsResponse, error) {
-	agt, err := r.constructAgent(ctx, id, token, opts)
+func (r *repo) UsageSummary(ctx context.Context, agentID *uuid.UUID, from, to time.Time) (*UsageSummary, error) {
+	q := `
+		SELECT date_trunc('day', created_at AT TIME ZONE 'UTC'), model,
+			COUNT(*), SUM(prompt_tokens), SUM(completion_tokens), SUM(total_tokens)
+		FROM agent_usage
+		WHERE created_at >= $1 AND created_at < $2`
+	args := []any{from, to}
+
+	if agentID != nil {
+		q += " AND agent_id = $3"
+		args = append(args, *agentID)
+	}
+
+	q += " GROUP BY 1, 2 ORDER BY 1, 2"
+
+	rows, err := repository.QueryMany(ctx, r.db, q, args, scanUsageRow)
+	if err != nil {
+		return nil, fmt.Errorf("query usage: %w", err)
+	}
+
+	summary := SummarizeUsage(rows, r.prices)
+	summary.AgentID = agentID
+	summary.From = from
+	summary.To = to
+
+	if len(summary.UnpricedModels) > 0 {
+		r.logger.Warn("no price configured for models; cost estimated as zero", "models", summary.UnpricedModels)
+	}
+
+	return &summary, nil
+}
+
+// batchEmbedder prefers a single native batch request and falls back to
+// bounded parallel single-input calls when the provider rejects batch input.
+// Auth, rate-limit, and availability failures are returned without fallback.
+func (r *repo) batchEmbedder(agt agent.Agent) Embedder {
+	native := nativeEmbedder(agt)
+	parallel := parallelEmbedder(func(ctx context.Context, input string) (*response.EmbeddingsResponse, error) {
+		return agt.Embed(ctx, input)
+	}, embedConcurrency)
+
+	return func(ctx context.Context, inputs []string) ([][]float64, *response.TokenUsage, error) {
+		vectors, usage, err := native(ctx, inputs)
+		if err == nil {
+			return vectors, usage, nil
+		}
+
+		classified := ClassifyProviderError(err)
+		if ctx.Err() != nil ||
+			errors.Is(classified, ErrProviderAuth) ||
+			errors.Is(classified, ErrProviderRateLimited) ||
+			errors.Is(classified, ErrProviderUnavailable) {
+			return nil, nil, classified
+		}
+
+		r.logger.Debug("native batch embedding unavailable, embedding inputs individually", "error", err)
+
+		vectors, usage, err = parallel(ctx, inputs)
+		if err != nil {
+			return nil, nil, ClassifyProviderError(err)
+		}
+		return vectors, usage, nil
+	}
+}
+
+func (r *repo) findEmbeddings(ctx context.Context, id uuid.UUID, model string, inputs []string) (map[string][]float64, error) {
+	hashes := make([]string, len(inputs))
+	for i, input := range inputs {
+		hashes[i] = HashInput(input)
+	}
+
+	q := `
+		SELECT input_hash, vector
+		FROM embeddings
+		WHERE agent_id = $1 AND model = $2 AND input_hash = ANY($3)`
+
+	stored, err := repository.QueryMany(ctx, r.db, q, []any{id, model, hashes}, scanStoredEmbedding)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := agt.Embed(ctx, input)
+	known := make(map[string][]float64, len(stored))
+	for _, e := range stored {
+		known[e.hash] = e.vector
+	}
+	return known, nil
+}
+
+func (r *repo) storeEmbeddings(ctx context.Context, id uuid.UUID, model string, vectors map[string][]float64) error {
+	q := `
+		INSERT INTO embeddings (agent_id, model, input_hash, vector)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (agent_id, model, input_hash) DO NOTHING`
+
+	_, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (struct{}, error) {
+		for hash, vector := range vectors {
+			data, err := json.Marshal(vector)
+			if err != nil {
+				return struct{}{}, err
+			}
+			if _, err := tx.ExecContext(ctx, q, id, model, hash, data); err != nil {
+				return struct{}{}, err
+			}
+		}
+		return struct{}{}, nil
+	})
+	return err
+}
+
+func (r *repo) recordUsage(ctx context.Context, id uuid.UUID, operation, model string, usage *response.TokenUsage) {
+	var prompt, completion, total int
+	if usage != nil {
+		prompt, completion, total = usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens
+	}
+
+	q := `
+		INSERT INTO agent_usage (agent_id, operation, model, prompt_tokens, completion_tokens, total_tokens)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	if _, err := r.db.ExecContext(ctx, q, id, operation, model, prompt, completion, total); err != nil {
+		r.logger.Error("failed to record usage", "id", id, "operation", operation, "error", err)
+	}
+}
+
+func (r *repo) cached(ctx context.Context, id uuid.UUID, token, prompt string, images []string, opts map[string]any, fn func() (*response.ChatResponse, error)) (*response.ChatResponse, error) {
+	if !cacheRequested(opts) {
+		return fn()
+	}
+
+	resp, hit, err := r.cache.Fetch(id, CacheKey(id, token, prompt, images, opts), fn)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrExecution, err)
+		return nil, err
+	}
+
+	setCacheStatus(ctx, hit)
+	if hit {
+		r.logger.Debug("agent response cache hit", "id", id)
 	}
 
 	return resp, nil
 }
 
 func (r *repo) constructAgent(ctx context.Context, id uuid.UUID, token string, opts map[string]any) (agent.Agent, error) {
-	record, err := r.Find(ctx, id)
+	parsed, err := r.configs.Get(id, func() (ParsedConfig, error) {
+		return r.parseConfig(ctx, id)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	cfg := agtconfig.DefaultAgentConfig()
+	cfg := parsed.Config
 
-	var storedCfg agtconfig.AgentConfig
-	if err := json.Unmarshal(record.Config, &storedCfg); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	provider, err := r.resolveProvider(ctx, parsed.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+	if provider != nil {
+		cfg.Merge(&agtconfig.AgentConfig{Provider: provider})
 	}
-
-	cfg.Merge(&storedCfg)
 
 	if systemPrompt, ok := opts["system_prompt"].(string); ok && systemPrompt != "" {
 		cfg.SystemPrompt = systemPrompt
@@ -251,18 +584,87 @@ func (r *repo) constructAgent(ctx context.Context, id uuid.UUID, token string, o
 	return agt, nil
 }
 
-func (r *repo) validateConfig(config json.RawMessage) error {
+// parseConfig loads the stored agent and merges its config over the go-agents
+// defaults, with the client defaults taken from the provider HTTP config.
+func (r *repo) parseConfig(ctx context.Context, id uuid.UUID) (ParsedConfig, error) {
+	record, err := r.Find(ctx, id)
+	if err != nil {
+		return ParsedConfig{}, err
+	}
+
+	cfg := agtconfig.DefaultAgentConfig()
+	applyClientConfig(cfg.Client, r.client)
+
+	var storedCfg agtconfig.AgentConfig
+	if err := json.Unmarshal(record.Config, &storedCfg); err != nil {
+		return ParsedConfig{}, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	cfg.Merge(&storedCfg)
+
+	return ParsedConfig{ProviderID: record.ProviderID, Config: cfg}, nil
+}
+
+// resolveProvider returns the config and credentials of the referenced
+// provider record, or nil when the agent does not reference one.
+// A missing provider is reported as ErrInvalidConfig.
+func (r *repo) resolveProvider(ctx context.Context, providerID *uuid.UUID) (*agtconfig.ProviderConfig, error) {
+	if providerID == nil {
+		return nil, nil
+	}
+
+	if r.providers == nil {
+		return nil, fmt.Errorf("%w: provider references are not supported", ErrInvalidConfig)
+	}
+
+	provider, err := r.providers.Resolve(ctx, *providerID)
+	if errors.Is(err, providers.ErrNotFound) {
+		return nil, fmt.Errorf("%w: provider %s not found", ErrInvalidConfig, providerID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve provider: %w", err)
+	}
+
+	return provider, nil
+}
+
+func (r *repo) validateConfig(ctx context.Context, config json.RawMessage, providerID *uuid.UUID) error {
+	provider, err := r.resolveProvider(ctx, providerID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkConfig(config, provider); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// checkConfig verifies that config parses as a go-agents AgentConfig and,
+// merged over the defaults and then provider when non-nil, constructs a valid agent.
+func checkConfig(config json.RawMessage, provider *agtconfig.ProviderConfig) error {
 	cfg := agtconfig.DefaultAgentConfig()
 
 	var userCfg agtconfig.AgentConfig
 	if err := json.Unmarshal(config, &userCfg); err != nil {
-		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+		return err
 	}
 
 	cfg.Merge(&userCfg)
 
-	if _, err := agent.New(&cfg); err != nil {
-		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	if provider != nil {
+		cfg.Merge(&agtconfig.AgentConfig{Provider: provider})
 	}
-	return nil
+
+	_, err := agent.New(&cfg)
+	return err
+}
+
+// applyClientConfig sets the request timeout and connection pool of dst from
+// cfg. go-agents builds its own transport per request, so the dial and TLS
+// handshake timeouts of cfg cannot be applied to agent executors.
+func applyClientConfig(dst *agtconfig.ClientConfig, cfg config.ProviderHTTPConfig) {
+	dst.Timeout = agtconfig.Duration(cfg.RequestTimeoutDuration())
+	dst.ConnectionPoolSize = cfg.MaxIdleConns
+	dst.ConnectionTimeout = agtconfig.Duration(cfg.IdleConnTimeoutDuration())
 }
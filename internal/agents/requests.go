@@ -3,13 +3,17 @@ package agents
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"strings"
 
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/google/uuid"
 )
 
 // ChatRequest contains the data for chat execution requests.
@@ -19,6 +23,57 @@ type ChatRequest struct {
 	Token   string         `json:"token,omitempty"`
 }
 
+// Validate checks the well-known keys of Options. See ParseChatOptions.
+func (r ChatRequest) Validate() error {
+	_, err := ParseChatOptions(r.Options)
+	return err
+}
+
+// ChatOptions holds the well-known execution options shared by providers.
+// A nil field means the option was not set.
+type ChatOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	MaxTokens   *int     `json:"max_tokens,omitempty"`
+}
+
+// ParseChatOptions extracts and validates the well-known keys of options:
+// temperature must be a number in [0, 2], top_p a number in [0, 1], and
+// max_tokens a positive integer. It returns a *handlers.ValidationError
+// listing every invalid option. Unknown keys are ignored so they pass
+// through to the provider untouched.
+func ParseChatOptions(options map[string]any) (ChatOptions, error) {
+	var opts ChatOptions
+	var v handlers.ValidationError
+
+	if raw, ok := options["temperature"]; ok {
+		if f, ok := raw.(float64); !ok || f < 0 || f > 2 {
+			v.Add("options.temperature", "temperature must be a number between 0 and 2")
+		} else {
+			opts.Temperature = &f
+		}
+	}
+
+	if raw, ok := options["top_p"]; ok {
+		if f, ok := raw.(float64); !ok || f < 0 || f > 1 {
+			v.Add("options.top_p", "top_p must be a number between 0 and 1")
+		} else {
+			opts.TopP = &f
+		}
+	}
+
+	if raw, ok := options["max_tokens"]; ok {
+		if f, ok := raw.(float64); !ok || f < 1 || f != math.Trunc(f) {
+			v.Add("options.max_tokens", "max_tokens must be a positive integer")
+		} else {
+			n := int(f)
+			opts.MaxTokens = &n
+		}
+	}
+
+	return opts, v.Err()
+}
+
 // ToolsRequest contains the data for tool-calling execution requests.
 type ToolsRequest struct {
 	Prompt  string         `json:"prompt"`
@@ -34,6 +89,55 @@ type EmbedRequest struct {
 	Token   string         `json:"token,omitempty"`
 }
 
+// EmbedBatchRequest contains the data for batch embedding requests.
+// Set the "store" option to persist vectors for reuse across requests.
+type EmbedBatchRequest struct {
+	Inputs  []string       `json:"inputs"`
+	Options map[string]any `json:"options,omitempty"`
+	Token   string         `json:"token,omitempty"`
+}
+
+// MaxVisionImages is the maximum number of images accepted by a single JSON vision request.
+const MaxVisionImages = 10
+
+// VisionRequest contains the data for JSON vision requests. Images are
+// referenced by stored image ID or fetched from public http(s) URLs rather
+// than uploaded.
+type VisionRequest struct {
+	Prompt    string         `json:"prompt"`
+	ImageIDs  []uuid.UUID    `json:"image_ids,omitempty"`
+	ImageURLs []string       `json:"image_urls,omitempty"`
+	Options   map[string]any `json:"options,omitempty"`
+	Token     string         `json:"token,omitempty"`
+}
+
+// Validate checks that a prompt and between one and MaxVisionImages images
+// are provided, along with the well-known keys of Options. It returns a
+// *handlers.ValidationError listing every invalid field, or nil.
+func (r VisionRequest) Validate() error {
+	var v handlers.ValidationError
+
+	if strings.TrimSpace(r.Prompt) == "" {
+		v.Add("prompt", "prompt is required")
+	}
+
+	switch n := len(r.ImageIDs) + len(r.ImageURLs); {
+	case n == 0:
+		v.Add("image_ids", "at least one image_id or image_url is required")
+	case n > MaxVisionImages:
+		v.Add("image_ids", fmt.Sprintf("at most %d images are allowed per request", MaxVisionImages))
+	}
+
+	var opts *handlers.ValidationError
+	if _, err := ParseChatOptions(r.Options); errors.As(err, &opts) {
+		for field, msg := range opts.Fields {
+			v.Add(field, msg)
+		}
+	}
+
+	return v.Err()
+}
+
 // VisionForm contains the parsed multipart form data for vision requests.
 type VisionForm struct {
 	Prompt  string
@@ -0,0 +1,66 @@
+package agents
+
+import (
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// ToolsEventType identifies the kind of event emitted by ToolsStream.
+type ToolsEventType string
+
+const (
+	// ToolsEventStart marks that the provider call has begun. Providers do not
+	// stream tool calls, so the remaining events are replayed from the complete
+	// response once the call returns.
+	ToolsEventStart ToolsEventType = "start"
+	// ToolsEventToolCall carries a single function call requested by the model.
+	ToolsEventToolCall ToolsEventType = "tool_call"
+	// ToolsEventContent carries assistant text produced alongside or after tool calls.
+	ToolsEventContent ToolsEventType = "content"
+	// ToolsEventDone marks the end of a successful run with its finish reason and usage.
+	ToolsEventDone ToolsEventType = "done"
+	// ToolsEventError reports a provider failure; no further events follow.
+	ToolsEventError ToolsEventType = "error"
+)
+
+// ToolsEvent is a single event in a streaming tool-call execution.
+type ToolsEvent struct {
+	Type         ToolsEventType       `json:"type"`
+	ToolCall     *response.ToolCall   `json:"tool_call,omitempty"`
+	Content      string               `json:"content,omitempty"`
+	FinishReason string               `json:"finish_reason,omitempty"`
+	Usage        *response.TokenUsage `json:"usage,omitempty"`
+	Error        string               `json:"error,omitempty"`
+}
+
+// ToolsEvents converts a tools response into its ordered event sequence:
+// each requested tool call, then any assistant content, then a done event.
+func ToolsEvents(resp *response.ToolsResponse) []ToolsEvent {
+	var events []ToolsEvent
+	var finish string
+
+	for _, choice := range resp.Choices {
+		for i := range choice.Message.ToolCalls {
+			events = append(events, ToolsEvent{
+				Type:     ToolsEventToolCall,
+				ToolCall: &choice.Message.ToolCalls[i],
+			})
+		}
+
+		if choice.Message.Content != "" {
+			events = append(events, ToolsEvent{
+				Type:    ToolsEventContent,
+				Content: choice.Message.Content,
+			})
+		}
+
+		if choice.FinishReason != "" {
+			finish = choice.FinishReason
+		}
+	}
+
+	return append(events, ToolsEvent{
+		Type:         ToolsEventDone,
+		FinishReason: finish,
+		Usage:        resp.Usage,
+	})
+}
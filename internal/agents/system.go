@@ -2,6 +2,7 @@ package agents
 
 import (
 	"context"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/go-agents/pkg/agent"
@@ -32,12 +33,21 @@ type System interface {
 	// Returns ErrInvalidConfig if the configuration fails go-agents validation.
 	Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Agent, error)
 
+	// Clone creates a new agent named cmd.Name with a copy of the source agent's config.
+	// Every config field is copied except credentials (token, api_key, secret, and
+	// similar keys at any depth), which must be supplied at request time.
+	// A provider reference is kept, so the clone uses the provider's credentials.
+	// Returns ErrNotFound if the source agent does not exist.
+	// Returns ErrDuplicate if an agent with the new name exists.
+	Clone(ctx context.Context, id uuid.UUID, cmd CloneCommand) (*Agent, error)
+
 	// Delete deletes an agent configuration by ID.
 	// Returns ErrNotFound if the agent does not exist.
 	Delete(ctx context.Context, id uuid.UUID) error
 
 	// Chat executes a chat completion using the agent configuration.
-	// The opts map supports "system_prompt" to override the stored prompt.
+	// The opts map supports "system_prompt" to override the stored prompt
+	// and "cache": true to serve repeated identical requests from the response cache.
 	// Token overrides the stored API token if provided.
 	Chat(ctx context.Context, id uuid.UUID, prompt string, opts map[string]any, token string) (*response.ChatResponse, error)
 
@@ -47,14 +57,39 @@ type System interface {
 
 	// Vision executes a vision completion with image analysis.
 	// Images should be base64-encoded data URIs.
+	// Supports the same "cache" option as Chat.
 	Vision(ctx context.Context, id uuid.UUID, prompt string, images []string, opts map[string]any, token string) (*response.ChatResponse, error)
 
+	// ResolveImages converts stored image IDs and public image URLs into
+	// base64 data URIs for Vision, IDs first and then URLs.
+	// Returns ErrImageURLNotAllowed if a URL is not http(s) or reaches a
+	// loopback, private, or link-local address.
+	// Returns ErrImageUnavailable if an image cannot be loaded or is not an image.
+	ResolveImages(ctx context.Context, ids []uuid.UUID, urls []string) ([]string, error)
+
 	// VisionStream executes a streaming vision completion.
 	VisionStream(ctx context.Context, id uuid.UUID, prompt string, images []string, opts map[string]any, token string) (<-chan *response.StreamingChunk, error)
 
 	// Tools executes a tool-use completion with function calling.
 	Tools(ctx context.Context, id uuid.UUID, prompt string, tools []agent.Tool, opts map[string]any, token string) (*response.ToolsResponse, error)
 
+	// ToolsStream executes a tool-use completion as typed events. A start event is
+	// sent when the provider call begins; providers return tool calls in a single
+	// response, so the tool calls, assistant content, and completion are replayed
+	// together once it arrives. Tools are executed by the caller, so no tool results
+	// are emitted. Cancelling ctx aborts the upstream provider call and closes the channel.
+	ToolsStream(ctx context.Context, id uuid.UUID, prompt string, tools []agent.Tool, opts map[string]any, token string) (<-chan ToolsEvent, error)
+
 	// Embed generates embeddings for the input text.
 	Embed(ctx context.Context, id uuid.UUID, input string, opts map[string]any, token string) (*response.EmbeddingsResponse, error)
+
+	// EmbedBatch generates embeddings for up to MaxBatchSize inputs, returned in input order.
+	// Identical inputs are embedded once. The "store" option persists vectors keyed by
+	// input hash so later requests reuse them instead of calling the provider.
+	// Returns ErrInvalidBatch if inputs is empty or exceeds MaxBatchSize.
+	EmbedBatch(ctx context.Context, id uuid.UUID, inputs []string, opts map[string]any, token string) (*EmbedBatchResponse, error)
+
+	// UsageSummary aggregates recorded call usage between from (inclusive) and to (exclusive),
+	// grouped by day. A nil agentID summarizes usage across all agents.
+	UsageSummary(ctx context.Context, agentID *uuid.UUID, from, to time.Time) (*UsageSummary, error)
 }
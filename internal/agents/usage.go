@@ -0,0 +1,138 @@
+package agents
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultUsageWindow = 30 * 24 * time.Hour
+	usageDateLayout    = "2006-01-02"
+)
+
+// UsageRow is a per-day, per-model aggregate of recorded agent calls.
+type UsageRow struct {
+	Day              time.Time
+	Model            string
+	Calls            int
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+}
+
+// UsageTotals contains aggregated call counts, token counts, and estimated cost.
+type UsageTotals struct {
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	EstimatedCost    float64 `json:"estimated_cost"`
+}
+
+// UsageDay contains usage totals for a single UTC day.
+type UsageDay struct {
+	Date string `json:"date"`
+	UsageTotals
+}
+
+// UsageSummary reports agent usage over a time range, grouped by day.
+// AgentID is nil for summaries spanning all agents. UnpricedModels lists
+// models that contributed usage but had no configured price.
+type UsageSummary struct {
+	AgentID *uuid.UUID `json:"agent_id,omitempty"`
+	From    time.Time  `json:"from"`
+	To      time.Time  `json:"to"`
+	UsageTotals
+	Days           []UsageDay `json:"days"`
+	UnpricedModels []string   `json:"unpriced_models,omitempty"`
+}
+
+// SummarizeUsage totals usage rows overall and by day, estimating cost from prices.
+// Rows for models without a configured price contribute zero cost and are
+// reported in UnpricedModels.
+func SummarizeUsage(rows []UsageRow, prices config.PriceTable) UsageSummary {
+	summary := UsageSummary{Days: []UsageDay{}}
+	dayIndex := make(map[string]int)
+	unpriced := make(map[string]bool)
+
+	for _, row := range rows {
+		cost, ok := prices.Cost(row.Model, row.PromptTokens, row.CompletionTokens)
+		if !ok && !unpriced[row.Model] {
+			unpriced[row.Model] = true
+			summary.UnpricedModels = append(summary.UnpricedModels, row.Model)
+		}
+
+		date := row.Day.UTC().Format(usageDateLayout)
+		i, exists := dayIndex[date]
+		if !exists {
+			i = len(summary.Days)
+			dayIndex[date] = i
+			summary.Days = append(summary.Days, UsageDay{Date: date})
+		}
+
+		summary.Days[i].add(row, cost)
+		summary.add(row, cost)
+	}
+
+	slices.SortFunc(summary.Days, func(a, b UsageDay) int {
+		if a.Date < b.Date {
+			return -1
+		}
+		if a.Date > b.Date {
+			return 1
+		}
+		return 0
+	})
+	slices.Sort(summary.UnpricedModels)
+
+	return summary
+}
+
+// UsageRangeFromQuery parses the from and to query parameters.
+// Values may be RFC 3339 timestamps or YYYY-MM-DD dates. The range defaults
+// to the 30 days ending now.
+func UsageRangeFromQuery(values url.Values) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if v := values.Get("to"); v != "" {
+		t, err := parseUsageTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: to: %v", ErrInvalidUsageRange, err)
+		}
+		to = t
+	}
+
+	from := to.Add(-defaultUsageWindow)
+	if v := values.Get("from"); v != "" {
+		t, err := parseUsageTime(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: from: %v", ErrInvalidUsageRange, err)
+		}
+		from = t
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be before to", ErrInvalidUsageRange)
+	}
+
+	return from, to, nil
+}
+
+func (t *UsageTotals) add(row UsageRow, cost float64) {
+	t.Calls += row.Calls
+	t.PromptTokens += row.PromptTokens
+	t.CompletionTokens += row.CompletionTokens
+	t.TotalTokens += row.TotalTokens
+	t.EstimatedCost += cost
+}
+
+func parseUsageTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(usageDateLayout, v)
+}
@@ -2,6 +2,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/JaimeStill/agent-lab/internal/config"
@@ -9,16 +10,26 @@ import (
 	"github.com/JaimeStill/agent-lab/pkg/middleware"
 	"github.com/JaimeStill/agent-lab/pkg/module"
 	"github.com/JaimeStill/agent-lab/pkg/openapi"
+	"github.com/JaimeStill/agent-lab/pkg/routes"
 )
 
 // NewModule creates the API module with all domain handlers and middleware.
 func NewModule(cfg *config.Config, infra *infrastructure.Infrastructure) (*module.Module, error) {
-	runtime := NewRuntime(cfg, infra)
+	signer, err := NewSigner(cfg, infra.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	runtime := NewRuntime(cfg, infra, signer)
 	domain := NewDomain(runtime)
 
-	spec := openapi.NewSpec(cfg.API.OpenAPI.Title, cfg.Version)
-	spec.SetDescription(cfg.API.OpenAPI.Description)
-	spec.AddServer(cfg.Domain)
+	runtime.Lifecycle.OnDrain(func(ctx context.Context) {
+		if err := domain.Workflows.Drain(ctx); err != nil {
+			runtime.Logger.Warn("workflow drain incomplete", "error", err)
+		}
+	})
+
+	spec := NewSpec(cfg)
 
 	mux := http.NewServeMux()
 	registerRoutes(mux, spec, domain, cfg)
@@ -29,9 +40,30 @@ func NewModule(cfg *config.Config, infra *infrastructure.Infrastructure) (*modul
 	}
 	mux.HandleFunc("GET /openapi.json", openapi.ServeSpec(specBytes))
 
-	m := module.New(cfg.API.BasePath, mux)
+	m := module.New(cfg.API.BasePath, routes.New(mux, routes.Options{}))
+	m.Use(middleware.RealIP(&cfg.Server.Proxy))
 	m.Use(middleware.CORS(&cfg.API.CORS))
+	m.Use(middleware.Actor(middleware.DefaultActorHeader))
+	m.Use(middleware.SignedURL(signer))
 	m.Use(middleware.Logger(runtime.Infrastructure.Logger))
+	m.Use(middleware.Timeout(cfg.API.RequestTimeoutDuration()))
 
 	return m, nil
 }
+
+// NewSpec creates the OpenAPI spec document for the API module.
+// Servers come from the openapi configuration in order; when none are
+// configured, the service domain is advertised as the only server.
+func NewSpec(cfg *config.Config) *openapi.Spec {
+	spec := openapi.NewSpec(cfg.API.OpenAPI.Title, cfg.Version)
+	spec.SetDescription(cfg.API.OpenAPI.Description)
+
+	switch {
+	case len(cfg.API.OpenAPI.Servers) > 0:
+		spec.AddServers(cfg.API.OpenAPI.Servers...)
+	case cfg.Domain != "":
+		spec.AddServer(cfg.Domain)
+	}
+
+	return spec
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/internal/audit"
 	"github.com/JaimeStill/agent-lab/internal/documents"
 	"github.com/JaimeStill/agent-lab/internal/images"
 	"github.com/JaimeStill/agent-lab/internal/profiles"
@@ -13,6 +14,7 @@ import (
 type Domain struct {
 	Providers providers.System
 	Agents    agents.System
+	Audit     audit.System
 	Documents documents.System
 	Images    images.System
 	Profiles  profiles.System
@@ -25,33 +27,47 @@ func NewDomain(runtime *Runtime) *Domain {
 		runtime.Database.Connection(),
 		runtime.Logger,
 		runtime.Pagination,
+		runtime.ProviderHTTP,
 	)
 
-	agentsSys := agents.New(
+	documentsSys := documents.New(
 		runtime.Database.Connection(),
+		runtime.Storage,
+		runtime.Events,
 		runtime.Logger,
 		runtime.Pagination,
+		runtime.Documents,
 	)
 
-	documentsSys := documents.New(
+	imagesSys := images.New(
+		documentsSys,
 		runtime.Database.Connection(),
 		runtime.Storage,
+		runtime.Events,
 		runtime.Logger,
 		runtime.Pagination,
+		runtime.Render,
+		runtime.Signer,
 	)
 
-	imagesSys := images.New(
-		documentsSys,
+	agentsSys := agents.New(
+		providersSys,
+		imagesSys,
 		runtime.Database.Connection(),
-		runtime.Storage,
+		runtime.Events,
 		runtime.Logger,
 		runtime.Pagination,
+		runtime.Pricing,
+		runtime.AgentDebug,
+		runtime.ProviderHTTP,
 	)
 
 	profilesSys := profiles.New(
 		runtime.Database.Connection(),
+		runtime.Events,
 		runtime.Logger,
 		runtime.Pagination,
+		workflows.DefaultProfile,
 	)
 
 	workflowRuntime := workflows.NewRuntime(
@@ -66,13 +82,30 @@ func NewDomain(runtime *Runtime) *Domain {
 	workflowsSys := workflows.NewSystem(
 		workflowRuntime,
 		runtime.Database.Connection(),
+		runtime.Events,
 		runtime.Logger,
 		runtime.Pagination,
+		runtime.Workflows.MaxConcurrent,
+		runtime.Workflows.StreamRetryDuration(),
+	)
+
+	auditSys := audit.New(
+		runtime.Database.Connection(),
+		runtime.Logger,
+		runtime.Pagination,
+	)
+
+	auditSys.Subscribe(
+		runtime.Events,
+		agents.EventCreated, agents.EventUpdated, agents.EventDeleted,
+		profiles.EventCreated, profiles.EventUpdated, profiles.EventDeleted,
+		documents.EventCreated, documents.EventUpdated, documents.EventDeleted,
 	)
 
 	return &Domain{
 		Providers: providersSys,
 		Agents:    agentsSys,
+		Audit:     auditSys,
 		Documents: documentsSys,
 		Images:    imagesSys,
 		Profiles:  profilesSys,
@@ -19,8 +19,11 @@ func registerRoutes(
 		cfg.API.BasePath,
 		spec,
 		domain.Agents.Handler().Routes(),
+		domain.Agents.Handler().UsageRoutes(),
+		domain.Audit.Handler().Routes(),
 		domain.Documents.Handler(cfg.Storage.MaxUploadSizeBytes()).Routes(),
 		domain.Images.Handler().Routes(),
+		domain.Images.Handler().DocumentRoutes(),
 		domain.Profiles.Handler().Routes(),
 		domain.Providers.Handler().Routes(),
 		domain.Workflows.Handler().Routes(),
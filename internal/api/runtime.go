@@ -1,21 +1,32 @@
 package api
 
 import (
+	"log/slog"
+
 	"github.com/JaimeStill/agent-lab/internal/config"
 	"github.com/JaimeStill/agent-lab/internal/infrastructure"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/signedurl"
 )
 
 // Runtime extends Infrastructure with API-specific configuration.
 type Runtime struct {
 	*infrastructure.Infrastructure
-	Pagination pagination.Config
+	Pagination   pagination.Config
+	Pricing      config.PriceTable
+	AgentDebug   config.AgentDebugConfig
+	Render       config.RenderConfig
+	ProviderHTTP config.ProviderHTTPConfig
+	Workflows    config.WorkflowsConfig
+	Documents    config.DocumentsConfig
+	Signer       *signedurl.Signer
 }
 
 // NewRuntime creates an API runtime with a module-scoped logger.
 func NewRuntime(
 	cfg *config.Config,
 	infra *infrastructure.Infrastructure,
+	signer *signedurl.Signer,
 ) *Runtime {
 	return &Runtime{
 		Infrastructure: &infrastructure.Infrastructure{
@@ -23,7 +34,31 @@ func NewRuntime(
 			Logger:    infra.Logger.With("module", "api"),
 			Database:  infra.Database,
 			Storage:   infra.Storage,
+			Events:    infra.Events,
 		},
-		Pagination: cfg.API.Pagination,
+		Pagination:   cfg.API.Pagination,
+		Pricing:      cfg.API.Pricing,
+		AgentDebug:   cfg.API.AgentDebug,
+		Render:       cfg.API.Render,
+		ProviderHTTP: cfg.API.ProviderHTTP,
+		Workflows:    cfg.API.Workflows,
+		Documents:    cfg.API.Documents,
+		Signer:       signer,
+	}
+}
+
+// NewSigner creates the signer for expiring resource URLs served by the API
+// module. Without a configured key a random one is generated, so signed URLs
+// do not survive a restart.
+func NewSigner(cfg *config.Config, logger *slog.Logger) (*signedurl.Signer, error) {
+	key := []byte(cfg.API.SignedURLs.Key)
+	if len(key) == 0 {
+		logger.Warn("no signed url key configured, using a random key; signed urls will not survive a restart")
+
+		var err error
+		if key, err = signedurl.GenerateKey(); err != nil {
+			return nil, err
+		}
 	}
+	return signedurl.New(key, cfg.API.BasePath)
 }
@@ -0,0 +1,75 @@
+// Package audit records write operations performed against domain resources.
+// Entries are derived from domain events published to the event bus, so
+// recording never blocks the request that performed the write.
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/events"
+	"github.com/JaimeStill/agent-lab/pkg/middleware"
+	"github.com/google/uuid"
+)
+
+// AnonymousActor is recorded when the originating request carried no actor.
+const AnonymousActor = "anonymous"
+
+// Entry is a single audited write operation.
+type Entry struct {
+	ID           uuid.UUID `json:"id"`
+	Actor        string    `json:"actor"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// EntryFromEvent builds an audit entry from a domain event whose type has the
+// form "<resource_type>.<action>" (e.g. "agent.created"). The actor is read
+// from ctx. Returns false if the event type is not in that form.
+func EntryFromEvent(ctx context.Context, event events.Event) (Entry, bool) {
+	resourceType, action, ok := strings.Cut(event.Type, ".")
+	if !ok || resourceType == "" || action == "" {
+		return Entry{}, false
+	}
+
+	actor := middleware.ActorFrom(ctx)
+	if actor == "" {
+		actor = AnonymousActor
+	}
+
+	createdAt := event.Time
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	return Entry{
+		Actor:        actor,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   event.Subject,
+		CreatedAt:    createdAt,
+	}, true
+}
+
+// RecordFunc persists an audit entry.
+type RecordFunc func(ctx context.Context, entry Entry) error
+
+// Recorder returns an event handler that converts events to entries via
+// EntryFromEvent and persists them with record. Failures are logged through
+// logf rather than surfaced, since the originating write has already completed.
+func Recorder(record RecordFunc, logf func(msg string, args ...any)) events.Handler {
+	return func(ctx context.Context, event events.Event) {
+		entry, ok := EntryFromEvent(ctx, event)
+		if !ok {
+			logf("unrecognized audit event type", "type", event.Type)
+			return
+		}
+
+		if err := record(ctx, entry); err != nil {
+			logf("failed to record audit entry", "type", event.Type, "subject", event.Subject, "error", err)
+		}
+	}
+}
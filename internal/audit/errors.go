@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/JaimeStill/agent-lab/pkg/query"
+)
+
+// MapHTTPStatus maps audit query errors to HTTP status codes.
+func MapHTTPStatus(err error) int {
+	if errors.Is(err, query.ErrInvalidSort) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
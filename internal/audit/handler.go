@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/routes"
+)
+
+// Handler provides HTTP endpoints for querying the audit log.
+type Handler struct {
+	sys        System
+	logger     *slog.Logger
+	pagination pagination.Config
+}
+
+// NewHandler creates a new audit HTTP handler.
+func NewHandler(sys System, logger *slog.Logger, pagination pagination.Config) *Handler {
+	return &Handler{
+		sys:        sys,
+		logger:     logger,
+		pagination: pagination,
+	}
+}
+
+// Routes returns the route configuration for audit endpoints.
+func (h *Handler) Routes() routes.Group {
+	return routes.Group{
+		Prefix:      "/audit",
+		Tags:        []string{"Audit"},
+		Description: "Audit log of write operations",
+		Routes: []routes.Route{
+			{Method: "GET", Pattern: "", Handler: h.List, OpenAPI: Spec.List},
+		},
+	}
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	filters := FiltersFromQuery(r.URL.Query())
+
+	result, err := h.sys.List(r.Context(), page, filters)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	pagination.Respond(w, r, result)
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"net/url"
+
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/agent-lab/pkg/repository"
+)
+
+var projection = query.NewProjectionMap("public", "audit_log", "a").
+	Project("id", "ID").
+	ProjectText("actor", "Actor").
+	ProjectText("action", "Action").
+	ProjectText("resource_type", "ResourceType").
+	ProjectText("resource_id", "ResourceID").
+	Project("created_at", "CreatedAt").
+	Sortable("ID", "Actor", "Action", "ResourceType", "ResourceID", "CreatedAt")
+
+var defaultSort = query.SortField{Field: "CreatedAt", Descending: true}
+
+func scanEntry(s repository.Scanner) (Entry, error) {
+	var e Entry
+	err := s.Scan(
+		&e.ID,
+		&e.Actor,
+		&e.Action,
+		&e.ResourceType,
+		&e.ResourceID,
+		&e.CreatedAt,
+	)
+	return e, err
+}
+
+// Filters contains optional criteria for filtering audit queries.
+// All filters match exactly.
+type Filters struct {
+	ResourceType *string
+	ResourceID   *string
+	Actor        *string
+	Action       *string
+}
+
+// FiltersFromQuery extracts audit filters from URL query parameters.
+func FiltersFromQuery(values url.Values) Filters {
+	var f Filters
+
+	if rt := values.Get("resource_type"); rt != "" {
+		f.ResourceType = &rt
+	}
+
+	if id := values.Get("resource_id"); id != "" {
+		f.ResourceID = &id
+	}
+
+	if a := values.Get("actor"); a != "" {
+		f.Actor = &a
+	}
+
+	if a := values.Get("action"); a != "" {
+		f.Action = &a
+	}
+
+	return f
+}
+
+// Apply adds filter conditions to the query builder.
+func (f Filters) Apply(b *query.Builder) *query.Builder {
+	return b.
+		WhereEquals("ResourceType", f.ResourceType).
+		WhereEquals("ResourceID", f.ResourceID).
+		WhereEquals("Actor", f.Actor).
+		WhereEquals("Action", f.Action)
+}
@@ -0,0 +1,57 @@
+package audit
+
+import "github.com/JaimeStill/agent-lab/pkg/openapi"
+
+// spec holds OpenAPI operation definitions for the audit domain.
+type spec struct {
+	List *openapi.Operation
+}
+
+// Spec contains OpenAPI operation definitions for all audit endpoints.
+var Spec = spec{
+	List: &openapi.Operation{
+		Summary:     "List audit entries",
+		Description: "Returns a paginated list of recorded write operations, newest first",
+		Parameters: []*openapi.Parameter{
+			openapi.QueryParam("page", "integer", "Page number (1-indexed)", false),
+			openapi.QueryParam("page_size", "integer", "Results per page", false),
+			openapi.QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending. Unknown fields return 400 listing the sortable fields", false),
+			openapi.QueryParam("resource_type", "string", "Filter by resource type (e.g. agent, profile, document)", false),
+			openapi.QueryParam("resource_id", "string", "Filter by resource ID", false),
+			openapi.QueryParam("actor", "string", "Filter by acting identity", false),
+			openapi.QueryParam("action", "string", "Filter by action (created, updated, deleted)", false),
+			openapi.FieldsParam(),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated list of audit entries", "AuditEntryPageResult"), openapi.PageHeaders(true)),
+			400: openapi.ResponseRef("BadRequest"),
+		},
+	},
+}
+
+// Schemas returns the audit domain schemas for OpenAPI components.
+func (spec) Schemas() map[string]*openapi.Schema {
+	return map[string]*openapi.Schema{
+		"AuditEntry": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"id":            {Type: "string", Format: "uuid"},
+				"actor":         {Type: "string", Description: "Acting identity, or anonymous"},
+				"action":        {Type: "string", Description: "Operation performed (created, updated, deleted)"},
+				"resource_type": {Type: "string", Description: "Type of the affected resource"},
+				"resource_id":   {Type: "string", Description: "ID of the affected resource"},
+				"created_at":    {Type: "string", Format: "date-time"},
+			},
+		},
+		"AuditEntryPageResult": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"data":        {Type: "array", Items: openapi.SchemaRef("AuditEntry")},
+				"total":       {Type: "integer", Description: "Total number of results"},
+				"page":        {Type: "integer", Description: "Current page number"},
+				"page_size":   {Type: "integer", Description: "Results per page"},
+				"total_pages": {Type: "integer", Description: "Total number of pages"},
+			},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/JaimeStill/agent-lab/pkg/events"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/agent-lab/pkg/repository"
+	"github.com/google/uuid"
+)
+
+type repo struct {
+	db         *sql.DB
+	logger     *slog.Logger
+	pagination pagination.Config
+}
+
+// New creates an audit repository.
+func New(db *sql.DB, logger *slog.Logger, pagination pagination.Config) System {
+	return &repo{
+		db:         db,
+		logger:     logger.With("system", "audit"),
+		pagination: pagination,
+	}
+}
+
+func (r *repo) Handler() *Handler {
+	return NewHandler(r, r.logger, r.pagination)
+}
+
+func (r *repo) List(ctx context.Context, page pagination.PageRequest, filters Filters) (*pagination.PageResult[Entry], error) {
+	qb := query.NewBuilder(projection, defaultSort)
+	filters.Apply(qb)
+
+	result, err := repository.Paginate(ctx, r.db, qb, page, r.pagination, scanEntry)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *repo) Record(ctx context.Context, entry Entry) error {
+	const q = `
+		INSERT INTO audit_log (id, actor, action, resource_type, resource_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	_, err := r.db.ExecContext(ctx, q,
+		entry.ID, entry.Actor, entry.Action, entry.ResourceType, entry.ResourceID, entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) Subscribe(bus *events.Bus, eventTypes ...string) {
+	handler := Recorder(r.Record, r.logger.Error)
+	for _, eventType := range eventTypes {
+		bus.Subscribe(eventType, handler)
+	}
+}
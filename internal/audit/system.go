@@ -0,0 +1,23 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/JaimeStill/agent-lab/pkg/events"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+)
+
+// System defines the interface for recording and querying audit entries.
+type System interface {
+	Handler() *Handler
+
+	// List returns a paginated list of audit entries matching the filter criteria,
+	// newest first by default.
+	List(ctx context.Context, page pagination.PageRequest, filters Filters) (*pagination.PageResult[Entry], error)
+
+	// Record persists an audit entry, assigning its ID.
+	Record(ctx context.Context, entry Entry) error
+
+	// Subscribe records an entry for every event of the given types published to bus.
+	Subscribe(bus *events.Bus, eventTypes ...string)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultAgentDebugResponseLength is the number of response bytes logged when
+// AgentDebugConfig.MaxResponseLength is unset.
+const DefaultAgentDebugResponseLength = 2000
+
+// AgentDebugConfig controls DEBUG-level logging of outbound provider requests
+// and their responses. Logging is off unless Enabled is set.
+type AgentDebugConfig struct {
+	Enabled           bool     `toml:"enabled"`
+	RedactKeys        []string `toml:"redact_keys"`
+	MaxResponseLength int      `toml:"max_response_length"`
+}
+
+// AgentDebugConfigEnv maps environment variable names for debug logging configuration.
+type AgentDebugConfigEnv struct {
+	Enabled           string
+	RedactKeys        string
+	MaxResponseLength string
+}
+
+// Finalize applies defaults and environment variable overrides, then validates.
+func (c *AgentDebugConfig) Finalize(env *AgentDebugConfigEnv) error {
+	if env != nil {
+		c.loadEnv(env)
+	}
+	if c.MaxResponseLength < 0 {
+		return fmt.Errorf("max_response_length cannot be negative, got %d", c.MaxResponseLength)
+	}
+	if c.MaxResponseLength == 0 {
+		c.MaxResponseLength = DefaultAgentDebugResponseLength
+	}
+	return nil
+}
+
+// Merge applies non-zero values from the overlay configuration.
+// Redact keys from the overlay are added to the base keys.
+func (c *AgentDebugConfig) Merge(overlay *AgentDebugConfig) {
+	if overlay.Enabled {
+		c.Enabled = true
+	}
+	c.RedactKeys = append(c.RedactKeys, overlay.RedactKeys...)
+	if overlay.MaxResponseLength != 0 {
+		c.MaxResponseLength = overlay.MaxResponseLength
+	}
+}
+
+func (c *AgentDebugConfig) loadEnv(env *AgentDebugConfigEnv) {
+	if env.Enabled != "" {
+		if v := os.Getenv(env.Enabled); v != "" {
+			if b, err := strconv.ParseBool(v); err == nil {
+				c.Enabled = b
+			}
+		}
+	}
+	if env.RedactKeys != "" {
+		if v := os.Getenv(env.RedactKeys); v != "" {
+			for key := range strings.SplitSeq(v, ",") {
+				if key = strings.TrimSpace(key); key != "" {
+					c.RedactKeys = append(c.RedactKeys, key)
+				}
+			}
+		}
+	}
+	if env.MaxResponseLength != "" {
+		if v := os.Getenv(env.MaxResponseLength); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				c.MaxResponseLength = n
+			}
+		}
+	}
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"os"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/pkg/middleware"
 	"github.com/JaimeStill/agent-lab/pkg/openapi"
@@ -21,36 +23,93 @@ var corsEnv = &middleware.CORSEnv{
 var openAPIEnv = &openapi.ConfigEnv{
 	Title:       "API_OPENAPI_TITLE",
 	Description: "API_OPENAPI_DESCRIPTION",
+	Servers:     "API_OPENAPI_SERVERS",
+}
+
+var agentDebugEnv = &AgentDebugConfigEnv{
+	Enabled:           "API_AGENT_DEBUG_ENABLED",
+	RedactKeys:        "API_AGENT_DEBUG_REDACT_KEYS",
+	MaxResponseLength: "API_AGENT_DEBUG_MAX_RESPONSE_LENGTH",
+}
+
+var renderEnv = &RenderConfigEnv{
+	PageTimeout:   "API_RENDER_PAGE_TIMEOUT",
+	MemoryLimit:   "API_RENDER_MEMORY_LIMIT",
+	AreaLimit:     "API_RENDER_AREA_LIMIT",
+	MaxWorkers:    "API_RENDER_MAX_WORKERS",
+	MaxConcurrent: "API_RENDER_MAX_CONCURRENT",
+}
+
+var providerHTTPEnv = &ProviderHTTPConfigEnv{
+	DialTimeout:         "API_PROVIDER_HTTP_DIAL_TIMEOUT",
+	TLSHandshakeTimeout: "API_PROVIDER_HTTP_TLS_HANDSHAKE_TIMEOUT",
+	MaxIdleConns:        "API_PROVIDER_HTTP_MAX_IDLE_CONNS",
+	IdleConnTimeout:     "API_PROVIDER_HTTP_IDLE_CONN_TIMEOUT",
+	RequestTimeout:      "API_PROVIDER_HTTP_REQUEST_TIMEOUT",
+}
+
+var workflowsEnv = &WorkflowsConfigEnv{
+	MaxConcurrent: "API_WORKFLOWS_MAX_CONCURRENT",
+	StreamRetry:   "API_WORKFLOWS_STREAM_RETRY",
+}
+
+var documentsEnv = &DocumentsConfigEnv{
+	AsyncPageCountSize: "API_DOCUMENTS_ASYNC_PAGE_COUNT_SIZE",
+}
+
+var signedURLsEnv = &SignedURLsConfigEnv{
+	Key: "API_SIGNED_URLS_KEY",
 }
 
 var paginationEnv = &pagination.ConfigEnv{
 	DefaultPageSize: "API_PAGINATION_DEFAULT_PAGE_SIZE",
 	MaxPageSize:     "API_PAGINATION_MAX_PAGE_SIZE",
+	MaxOffset:       "API_PAGINATION_MAX_OFFSET",
 }
 
 // APIConfig contains API module configuration.
 type APIConfig struct {
-	BasePath   string                `toml:"base_path"`
-	CORS       middleware.CORSConfig `toml:"cors"`
-	Pagination pagination.Config     `toml:"pagination"`
-	OpenAPI    openapi.Config        `toml:"openapi"`
+	BasePath       string                `toml:"base_path"`
+	RequestTimeout string                `toml:"request_timeout"`
+	CORS           middleware.CORSConfig `toml:"cors"`
+	Pagination     pagination.Config     `toml:"pagination"`
+	OpenAPI        openapi.Config        `toml:"openapi"`
+	Pricing        PriceTable            `toml:"pricing"`
+	AgentDebug     AgentDebugConfig      `toml:"agent_debug"`
+	Render         RenderConfig          `toml:"render"`
+	ProviderHTTP   ProviderHTTPConfig    `toml:"provider_http"`
+	Workflows      WorkflowsConfig       `toml:"workflows"`
+	Documents      DocumentsConfig       `toml:"documents"`
+	SignedURLs     SignedURLsConfig      `toml:"signed_urls"`
 }
 
 // Finalize applies defaults, loads environment overrides, and validates nested configurations.
+// Every nested configuration is validated; the returned *ValidationError lists all problems found.
 func (c *APIConfig) Finalize() error {
 	c.loadDefaults()
 	c.loadEnv()
 
-	if err := c.CORS.Finalize(corsEnv); err != nil {
-		return fmt.Errorf("cors: %w", err)
-	}
-	if err := c.Pagination.Finalize(paginationEnv); err != nil {
-		return fmt.Errorf("pagination: %w", err)
-	}
-	if err := c.OpenAPI.Finalize(openAPIEnv); err != nil {
-		return fmt.Errorf("openapi: %w", err)
+	var p problems
+	if _, err := time.ParseDuration(c.RequestTimeout); err != nil {
+		p.add("", fmt.Errorf("invalid request_timeout: %w", err))
 	}
-	return nil
+	p.add("cors", c.CORS.Finalize(corsEnv))
+	p.add("pagination", c.Pagination.Finalize(paginationEnv))
+	p.add("openapi", c.OpenAPI.Finalize(openAPIEnv))
+	p.add("agent_debug", c.AgentDebug.Finalize(agentDebugEnv))
+	p.add("render", c.Render.Finalize(renderEnv))
+	p.add("provider_http", c.ProviderHTTP.Finalize(providerHTTPEnv))
+	p.add("workflows", c.Workflows.Finalize(workflowsEnv))
+	p.add("documents", c.Documents.Finalize(documentsEnv))
+	p.add("signed_urls", c.SignedURLs.Finalize(signedURLsEnv))
+	return p.err()
+}
+
+// RequestTimeoutDuration parses and returns the request timeout as a time.Duration.
+// A zero duration disables the timeout.
+func (c *APIConfig) RequestTimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.RequestTimeout)
+	return d
 }
 
 // Merge applies non-zero values from the overlay configuration.
@@ -58,19 +117,43 @@ func (c *APIConfig) Merge(overlay *APIConfig) {
 	if overlay.BasePath != "" {
 		c.BasePath = overlay.BasePath
 	}
+	if overlay.RequestTimeout != "" {
+		c.RequestTimeout = overlay.RequestTimeout
+	}
 	c.CORS.Merge(&overlay.CORS)
 	c.Pagination.Merge(&overlay.Pagination)
 	c.OpenAPI.Merge(&overlay.OpenAPI)
+	c.AgentDebug.Merge(&overlay.AgentDebug)
+	c.Render.Merge(&overlay.Render)
+	c.ProviderHTTP.Merge(&overlay.ProviderHTTP)
+	c.Workflows.Merge(&overlay.Workflows)
+	c.Documents.Merge(&overlay.Documents)
+	c.SignedURLs.Merge(&overlay.SignedURLs)
+	if len(overlay.Pricing) > 0 {
+		if c.Pricing == nil {
+			c.Pricing = make(PriceTable, len(overlay.Pricing))
+		}
+		maps.Copy(c.Pricing, overlay.Pricing)
+	}
 }
 
 func (c *APIConfig) loadDefaults() {
 	if c.BasePath == "" {
 		c.BasePath = "/api"
 	}
+	if c.RequestTimeout == "" {
+		c.RequestTimeout = "5m"
+	}
+	if c.Pricing == nil {
+		c.Pricing = PriceTable{}
+	}
 }
 
 func (c *APIConfig) loadEnv() {
 	if v := os.Getenv("API_BASE_PATH"); v != "" {
 		c.BasePath = v
 	}
+	if v := os.Getenv("API_REQUEST_TIMEOUT"); v != "" {
+		c.RequestTimeout = v
+	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/JaimeStill/agent-lab/pkg/database"
 	"github.com/JaimeStill/agent-lab/pkg/logging"
 	"github.com/JaimeStill/agent-lab/pkg/storage"
+	"github.com/JaimeStill/agent-lab/pkg/sweeper"
 	"github.com/pelletier/go-toml/v2"
 )
 
@@ -49,8 +50,16 @@ var loggingEnv = &logging.Env{
 }
 
 var storageEnv = &storage.Env{
-	BasePath:      "STORAGE_BASE_PATH",
-	MaxUploadSize: "STORAGE_MAX_UPLOAD_SIZE",
+	Backend:             "STORAGE_BACKEND",
+	BasePath:            "STORAGE_BASE_PATH",
+	MaxUploadSize:       "STORAGE_MAX_UPLOAD_SIZE",
+	MaxTotalSize:        "STORAGE_MAX_TOTAL_SIZE",
+	CompressStoredBlobs: "STORAGE_COMPRESS_STORED_BLOBS",
+}
+
+var sweeperEnv = &sweeper.Env{
+	Interval: "SWEEPER_INTERVAL",
+	TempTTL:  "SWEEPER_TEMP_TTL",
 }
 
 // Config represents the root service configuration.
@@ -59,6 +68,7 @@ type Config struct {
 	Database        database.Config `toml:"database"`
 	Logging         logging.Config  `toml:"logging"`
 	Storage         storage.Config  `toml:"storage"`
+	Sweeper         sweeper.Config  `toml:"sweeper"`
 	API             APIConfig       `toml:"api"`
 	Domain          string          `toml:"version"`
 	ShutdownTimeout string          `toml:"shutdown_timeout"`
@@ -94,7 +104,7 @@ func Load() (*Config, error) {
 		cfg.Merge(overlay)
 	}
 
-	if err := cfg.finalize(); err != nil {
+	if err := cfg.Finalize(); err != nil {
 		return nil, fmt.Errorf("finalize config: %w", err)
 	}
 
@@ -102,29 +112,20 @@ func Load() (*Config, error) {
 }
 
 // Finalize applies defaults, loads environment overrides, and validates the configuration.
-func (c *Config) finalize() error {
+// Every section is validated; the returned *ValidationError lists all problems found.
+func (c *Config) Finalize() error {
 	c.loadDefaults()
 	c.loadEnv()
 
-	if err := c.validate(); err != nil {
-		return err
-	}
-	if err := c.Server.Finalize(); err != nil {
-		return fmt.Errorf("server: %w", err)
-	}
-	if err := c.Database.Finalize(databaseEnv); err != nil {
-		return fmt.Errorf("database: %w", err)
-	}
-	if err := c.Logging.Finalize(loggingEnv); err != nil {
-		return fmt.Errorf("logging: %w", err)
-	}
-	if err := c.Storage.Finalize(storageEnv); err != nil {
-		return fmt.Errorf("storage: %w", err)
-	}
-	if err := c.API.Finalize(); err != nil {
-		return fmt.Errorf("api: %w", err)
-	}
-	return nil
+	var p problems
+	p.add("", c.validate())
+	p.add("server", c.Server.Finalize())
+	p.add("database", c.Database.Finalize(databaseEnv))
+	p.add("logging", c.Logging.Finalize(loggingEnv))
+	p.add("storage", c.Storage.Finalize(storageEnv))
+	p.add("sweeper", c.Sweeper.Finalize(sweeperEnv))
+	p.add("api", c.API.Finalize())
+	return p.err()
 }
 
 // Merge applies values from overlay configuration that differ from zero values.
@@ -142,6 +143,7 @@ func (c *Config) Merge(overlay *Config) {
 	c.Database.Merge(&overlay.Database)
 	c.Logging.Merge(&overlay.Logging)
 	c.Storage.Merge(&overlay.Storage)
+	c.Sweeper.Merge(&overlay.Sweeper)
 	c.API.Merge(&overlay.API)
 }
 
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/go-units"
+)
+
+// DocumentsConfig controls document uploads. PDFs larger than
+// AsyncPageCountSize (e.g. "25MB") are stored without waiting for their page
+// count, which is extracted in the background; "0" always counts pages
+// before the upload responds.
+type DocumentsConfig struct {
+	AsyncPageCountSize string `toml:"async_page_count_size"`
+
+	asyncPageCountSizeVal int64
+}
+
+// DocumentsConfigEnv maps environment variable names for document configuration.
+type DocumentsConfigEnv struct {
+	AsyncPageCountSize string
+}
+
+// Finalize applies defaults and environment variable overrides, then validates.
+func (c *DocumentsConfig) Finalize(env *DocumentsConfigEnv) error {
+	c.loadDefaults()
+	if env != nil {
+		c.loadEnv(env)
+	}
+
+	size, err := units.FromHumanSize(c.AsyncPageCountSize)
+	if err != nil {
+		return fmt.Errorf("invalid async_page_count_size: %w", err)
+	}
+	if size < 0 {
+		return fmt.Errorf("async_page_count_size cannot be negative, got %s", c.AsyncPageCountSize)
+	}
+	c.asyncPageCountSizeVal = size
+	return nil
+}
+
+// Merge applies non-zero values from the overlay configuration.
+func (c *DocumentsConfig) Merge(overlay *DocumentsConfig) {
+	if overlay.AsyncPageCountSize != "" {
+		c.AsyncPageCountSize = overlay.AsyncPageCountSize
+	}
+}
+
+// AsyncPageCountSizeBytes returns the size in bytes above which PDF page
+// counts are extracted in the background, or 0 when counting is always
+// synchronous.
+func (c DocumentsConfig) AsyncPageCountSizeBytes() int64 {
+	return c.asyncPageCountSizeVal
+}
+
+func (c *DocumentsConfig) loadDefaults() {
+	if c.AsyncPageCountSize == "" {
+		c.AsyncPageCountSize = "25MB"
+	}
+}
+
+func (c *DocumentsConfig) loadEnv(env *DocumentsConfigEnv) {
+	if env.AsyncPageCountSize != "" {
+		if v := os.Getenv(env.AsyncPageCountSize); v != "" {
+			c.AsyncPageCountSize = v
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+// ModelPrice defines the estimated cost of a model in USD per one million tokens.
+type ModelPrice struct {
+	Prompt     float64 `toml:"prompt" json:"prompt"`
+	Completion float64 `toml:"completion" json:"completion"`
+}
+
+// PriceTable maps model names to their per-token prices.
+type PriceTable map[string]ModelPrice
+
+// Cost estimates the cost of the given token counts for model.
+// Returns false if the model has no configured price, in which case the cost is zero.
+func (t PriceTable) Cost(model string, promptTokens, completionTokens int) (float64, bool) {
+	price, ok := t[model]
+	if !ok {
+		return 0, false
+	}
+
+	cost := float64(promptTokens)*price.Prompt/1_000_000 +
+		float64(completionTokens)*price.Completion/1_000_000
+
+	return cost, true
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ProviderHTTPConfig configures the HTTP client used for calls to LLM
+// providers. DialTimeout and TLSHandshakeTimeout bound connection setup,
+// MaxIdleConns and IdleConnTimeout size the pool of kept-alive connections,
+// and RequestTimeout caps each provider call end to end, including reading
+// a streamed response. A "0" duration disables that limit.
+type ProviderHTTPConfig struct {
+	DialTimeout         string `toml:"dial_timeout"`
+	TLSHandshakeTimeout string `toml:"tls_handshake_timeout"`
+	MaxIdleConns        int    `toml:"max_idle_conns"`
+	IdleConnTimeout     string `toml:"idle_conn_timeout"`
+	RequestTimeout      string `toml:"request_timeout"`
+}
+
+// ProviderHTTPConfigEnv maps environment variable names for provider HTTP configuration.
+type ProviderHTTPConfigEnv struct {
+	DialTimeout         string
+	TLSHandshakeTimeout string
+	MaxIdleConns        string
+	IdleConnTimeout     string
+	RequestTimeout      string
+}
+
+// Finalize applies defaults and environment variable overrides, then validates.
+func (c *ProviderHTTPConfig) Finalize(env *ProviderHTTPConfigEnv) error {
+	c.loadDefaults()
+	if env != nil {
+		c.loadEnv(env)
+	}
+
+	durations := []struct {
+		name  string
+		value string
+	}{
+		{"dial_timeout", c.DialTimeout},
+		{"tls_handshake_timeout", c.TLSHandshakeTimeout},
+		{"idle_conn_timeout", c.IdleConnTimeout},
+		{"request_timeout", c.RequestTimeout},
+	}
+	for _, d := range durations {
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", d.name, err)
+		}
+		if v < 0 {
+			return fmt.Errorf("%s cannot be negative, got %s", d.name, d.value)
+		}
+	}
+	if c.MaxIdleConns < 0 {
+		return fmt.Errorf("max_idle_conns cannot be negative, got %d", c.MaxIdleConns)
+	}
+	return nil
+}
+
+// Merge applies non-zero values from the overlay configuration.
+func (c *ProviderHTTPConfig) Merge(overlay *ProviderHTTPConfig) {
+	if overlay.DialTimeout != "" {
+		c.DialTimeout = overlay.DialTimeout
+	}
+	if overlay.TLSHandshakeTimeout != "" {
+		c.TLSHandshakeTimeout = overlay.TLSHandshakeTimeout
+	}
+	if overlay.MaxIdleConns != 0 {
+		c.MaxIdleConns = overlay.MaxIdleConns
+	}
+	if overlay.IdleConnTimeout != "" {
+		c.IdleConnTimeout = overlay.IdleConnTimeout
+	}
+	if overlay.RequestTimeout != "" {
+		c.RequestTimeout = overlay.RequestTimeout
+	}
+}
+
+// DialTimeoutDuration parses and returns the connection dial timeout.
+func (c ProviderHTTPConfig) DialTimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.DialTimeout)
+	return d
+}
+
+// TLSHandshakeTimeoutDuration parses and returns the TLS handshake timeout.
+func (c ProviderHTTPConfig) TLSHandshakeTimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.TLSHandshakeTimeout)
+	return d
+}
+
+// IdleConnTimeoutDuration parses and returns how long an idle connection
+// stays in the pool.
+func (c ProviderHTTPConfig) IdleConnTimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.IdleConnTimeout)
+	return d
+}
+
+// RequestTimeoutDuration parses and returns the per-request timeout.
+func (c ProviderHTTPConfig) RequestTimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.RequestTimeout)
+	return d
+}
+
+func (c *ProviderHTTPConfig) loadDefaults() {
+	if c.DialTimeout == "" {
+		c.DialTimeout = "10s"
+	}
+	if c.TLSHandshakeTimeout == "" {
+		c.TLSHandshakeTimeout = "10s"
+	}
+	if c.MaxIdleConns == 0 {
+		c.MaxIdleConns = 10
+	}
+	if c.IdleConnTimeout == "" {
+		c.IdleConnTimeout = "30s"
+	}
+	if c.RequestTimeout == "" {
+		c.RequestTimeout = "2m"
+	}
+}
+
+func (c *ProviderHTTPConfig) loadEnv(env *ProviderHTTPConfigEnv) {
+	if env.DialTimeout != "" {
+		if v := os.Getenv(env.DialTimeout); v != "" {
+			c.DialTimeout = v
+		}
+	}
+	if env.TLSHandshakeTimeout != "" {
+		if v := os.Getenv(env.TLSHandshakeTimeout); v != "" {
+			c.TLSHandshakeTimeout = v
+		}
+	}
+	if env.MaxIdleConns != "" {
+		if v := os.Getenv(env.MaxIdleConns); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				c.MaxIdleConns = n
+			}
+		}
+	}
+	if env.IdleConnTimeout != "" {
+		if v := os.Getenv(env.IdleConnTimeout); v != "" {
+			c.IdleConnTimeout = v
+		}
+	}
+	if env.RequestTimeout != "" {
+		if v := os.Getenv(env.RequestTimeout); v != "" {
+			c.RequestTimeout = v
+		}
+	}
+}
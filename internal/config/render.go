@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// RenderConfig bounds the cost of rendering document pages.
+// PageTimeout caps the wall-clock time of each page render ("0" disables it).
+// MemoryLimit and AreaLimit are forwarded to ImageMagick as resource limits
+// using its -limit syntax (e.g. "256MiB", "128MP"); empty values leave
+// ImageMagick's own defaults in place. MaxWorkers caps how many pages render
+// concurrently per request; 0 uses the number of CPUs. MaxConcurrent caps how
+// many ImageMagick invocations run at once across all requests; 0 uses the
+// number of CPUs.
+type RenderConfig struct {
+	PageTimeout   string `toml:"page_timeout"`
+	MemoryLimit   string `toml:"memory_limit"`
+	AreaLimit     string `toml:"area_limit"`
+	MaxWorkers    int    `toml:"max_workers"`
+	MaxConcurrent int    `toml:"max_concurrent"`
+}
+
+// RenderConfigEnv maps environment variable names for render configuration.
+type RenderConfigEnv struct {
+	PageTimeout   string
+	MemoryLimit   string
+	AreaLimit     string
+	MaxWorkers    string
+	MaxConcurrent string
+}
+
+// Finalize applies defaults and environment variable overrides, then validates.
+func (c *RenderConfig) Finalize(env *RenderConfigEnv) error {
+	c.loadDefaults()
+	if env != nil {
+		c.loadEnv(env)
+	}
+
+	d, err := time.ParseDuration(c.PageTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid page_timeout: %w", err)
+	}
+	if d < 0 {
+		return fmt.Errorf("page_timeout cannot be negative, got %s", c.PageTimeout)
+	}
+	if c.MaxWorkers < 0 {
+		return fmt.Errorf("max_workers cannot be negative, got %d", c.MaxWorkers)
+	}
+	if c.MaxConcurrent < 0 {
+		return fmt.Errorf("max_concurrent cannot be negative, got %d", c.MaxConcurrent)
+	}
+	return nil
+}
+
+// Merge applies non-zero values from the overlay configuration.
+func (c *RenderConfig) Merge(overlay *RenderConfig) {
+	if overlay.PageTimeout != "" {
+		c.PageTimeout = overlay.PageTimeout
+	}
+	if overlay.MemoryLimit != "" {
+		c.MemoryLimit = overlay.MemoryLimit
+	}
+	if overlay.AreaLimit != "" {
+		c.AreaLimit = overlay.AreaLimit
+	}
+	if overlay.MaxWorkers != 0 {
+		c.MaxWorkers = overlay.MaxWorkers
+	}
+	if overlay.MaxConcurrent != 0 {
+		c.MaxConcurrent = overlay.MaxConcurrent
+	}
+}
+
+// PageTimeoutDuration parses and returns the per-page render timeout.
+// A zero duration disables the timeout.
+func (c RenderConfig) PageTimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(c.PageTimeout)
+	return d
+}
+
+func (c *RenderConfig) loadDefaults() {
+	if c.PageTimeout == "" {
+		c.PageTimeout = "2m"
+	}
+}
+
+func (c *RenderConfig) loadEnv(env *RenderConfigEnv) {
+	if env.PageTimeout != "" {
+		if v := os.Getenv(env.PageTimeout); v != "" {
+			c.PageTimeout = v
+		}
+	}
+	if env.MemoryLimit != "" {
+		if v := os.Getenv(env.MemoryLimit); v != "" {
+			c.MemoryLimit = v
+		}
+	}
+	if env.AreaLimit != "" {
+		if v := os.Getenv(env.AreaLimit); v != "" {
+			c.AreaLimit = v
+		}
+	}
+	if env.MaxWorkers != "" {
+		if v := os.Getenv(env.MaxWorkers); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				c.MaxWorkers = n
+			}
+		}
+	}
+	if env.MaxConcurrent != "" {
+		if v := os.Getenv(env.MaxConcurrent); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				c.MaxConcurrent = n
+			}
+		}
+	}
+}
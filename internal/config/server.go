@@ -1,10 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/middleware"
 )
 
 const (
@@ -24,13 +27,19 @@ const (
 	EnvServerShutdownTimeout = "SERVER_SHUTDOWN_TIMEOUT"
 )
 
+var proxyEnv = &middleware.ProxyEnv{
+	TrustedProxies: "SERVER_PROXY_TRUSTED_PROXIES",
+}
+
 // ServerConfig contains HTTP server configuration.
+// Proxy lists the reverse proxies trusted to report client addresses.
 type ServerConfig struct {
-	Host            string `toml:"host"`
-	Port            int    `toml:"port"`
-	ReadTimeout     string `toml:"read_timeout"`
-	WriteTimeout    string `toml:"write_timeout"`
-	ShutdownTimeout string `toml:"shutdown_timeout"`
+	Host            string                 `toml:"host"`
+	Port            int                    `toml:"port"`
+	ReadTimeout     string                 `toml:"read_timeout"`
+	WriteTimeout    string                 `toml:"write_timeout"`
+	ShutdownTimeout string                 `toml:"shutdown_timeout"`
+	Proxy           middleware.ProxyConfig `toml:"proxy"`
 }
 
 // Addr returns the server address in host:port format.
@@ -57,10 +66,11 @@ func (c *ServerConfig) ShutdownTimeoutDuration() time.Duration {
 }
 
 // Finalize applies defaults, loads environment overrides, and validates the server configuration.
+// All validation problems are reported together as a joined error.
 func (c *ServerConfig) Finalize() error {
 	c.loadDefaults()
 	c.loadEnv()
-	return c.validate()
+	return errors.Join(c.validate(), c.Proxy.Finalize(proxyEnv))
 }
 
 // Merge applies values from overlay configuration that differ from zero values.
@@ -80,6 +90,7 @@ func (c *ServerConfig) Merge(overlay *ServerConfig) {
 	if overlay.ShutdownTimeout != "" {
 		c.ShutdownTimeout = overlay.ShutdownTimeout
 	}
+	c.Proxy.Merge(&overlay.Proxy)
 }
 
 func (c *ServerConfig) loadEnv() {
@@ -121,17 +132,18 @@ func (c *ServerConfig) loadDefaults() {
 }
 
 func (c *ServerConfig) validate() error {
+	var errs []error
 	if c.Port < 1 || c.Port > 65535 {
-		return fmt.Errorf("invalid port: %d", c.Port)
+		errs = append(errs, fmt.Errorf("invalid port: %d (must be between 1 and 65535)", c.Port))
 	}
 	if _, err := time.ParseDuration(c.ReadTimeout); err != nil {
-		return fmt.Errorf("invalid read_timeout: %w", err)
+		errs = append(errs, fmt.Errorf("invalid read_timeout: %w", err))
 	}
 	if _, err := time.ParseDuration(c.WriteTimeout); err != nil {
-		return fmt.Errorf("invalid write_timeout: %w", err)
+		errs = append(errs, fmt.Errorf("invalid write_timeout: %w", err))
 	}
 	if _, err := time.ParseDuration(c.ShutdownTimeout); err != nil {
-		return fmt.Errorf("invalid shutdown_timeout: %w", err)
+		errs = append(errs, fmt.Errorf("invalid shutdown_timeout: %w", err))
 	}
-	return nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/JaimeStill/agent-lab/pkg/signedurl"
+)
+
+// SignedURLsConfig holds the HMAC key used to sign expiring resource URLs.
+// An empty Key makes the server generate a random key at startup, so signed
+// URLs stop working after a restart and are not shared between instances.
+type SignedURLsConfig struct {
+	Key string `toml:"key"`
+}
+
+// SignedURLsConfigEnv maps environment variable names for signed URL configuration.
+type SignedURLsConfigEnv struct {
+	Key string
+}
+
+// Finalize applies environment variable overrides, then validates.
+func (c *SignedURLsConfig) Finalize(env *SignedURLsConfigEnv) error {
+	if env != nil {
+		c.loadEnv(env)
+	}
+	if c.Key != "" && len(c.Key) < signedurl.KeySize {
+		return fmt.Errorf("key must be at least %d bytes, got %d", signedurl.KeySize, len(c.Key))
+	}
+	return nil
+}
+
+// Merge applies non-zero values from the overlay configuration.
+func (c *SignedURLsConfig) Merge(overlay *SignedURLsConfig) {
+	if overlay.Key != "" {
+		c.Key = overlay.Key
+	}
+}
+
+func (c *SignedURLsConfig) loadEnv(env *SignedURLsConfigEnv) {
+	if env.Key != "" {
+		if v := os.Getenv(env.Key); v != "" {
+			c.Key = v
+		}
+	}
+}
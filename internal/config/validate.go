@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError reports every problem found while finalizing the
+// configuration, so operators can fix all of them in one pass.
+// Each problem is prefixed with the section it belongs to.
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = "  - " + p.Error()
+	}
+	return fmt.Sprintf("%d configuration problem(s):\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
+
+// Unwrap returns the individual problems so errors.Is and errors.As
+// match any of them.
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// problems accumulates validation errors across configuration sections.
+type problems []error
+
+// add records err under section. Joined errors, including nested
+// ValidationErrors, are flattened so each problem is reported separately.
+func (p *problems) add(section string, err error) {
+	if err == nil {
+		return
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			p.add(section, e)
+		}
+		return
+	}
+
+	if section != "" {
+		err = fmt.Errorf("%s: %w", section, err)
+	}
+	*p = append(*p, err)
+}
+
+// err returns a ValidationError listing the recorded problems, or nil if none.
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
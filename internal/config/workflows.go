@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultWorkflowMaxConcurrent is the number of workflow runs that execute at
+// once when WorkflowsConfig.MaxConcurrent is unset.
+const DefaultWorkflowMaxConcurrent = 4
+
+// DefaultWorkflowStreamRetry is the reconnection delay advertised to SSE
+// clients when WorkflowsConfig.StreamRetry is unset.
+const DefaultWorkflowStreamRetry = "3s"
+
+// WorkflowsConfig controls workflow execution. MaxConcurrent caps how many
+// runs execute at once; further runs wait in a priority queue until a slot
+// frees. StreamRetry is the delay sent to run event stream clients as the
+// SSE retry hint; "0" omits the hint.
+type WorkflowsConfig struct {
+	MaxConcurrent int    `toml:"max_concurrent"`
+	StreamRetry   string `toml:"stream_retry"`
+}
+
+// WorkflowsConfigEnv maps environment variable names for workflow configuration.
+type WorkflowsConfigEnv struct {
+	MaxConcurrent string
+	StreamRetry   string
+}
+
+// Finalize applies defaults and environment variable overrides, then validates.
+func (c *WorkflowsConfig) Finalize(env *WorkflowsConfigEnv) error {
+	if env != nil {
+		c.loadEnv(env)
+	}
+	if c.MaxConcurrent < 0 {
+		return fmt.Errorf("max_concurrent cannot be negative, got %d", c.MaxConcurrent)
+	}
+	if c.MaxConcurrent == 0 {
+		c.MaxConcurrent = DefaultWorkflowMaxConcurrent
+	}
+	if c.StreamRetry == "" {
+		c.StreamRetry = DefaultWorkflowStreamRetry
+	}
+	d, err := time.ParseDuration(c.StreamRetry)
+	if err != nil {
+		return fmt.Errorf("invalid stream_retry: %w", err)
+	}
+	if d < 0 {
+		return fmt.Errorf("stream_retry cannot be negative, got %s", c.StreamRetry)
+	}
+	return nil
+}
+
+// StreamRetryDuration parses and returns the SSE retry hint.
+func (c WorkflowsConfig) StreamRetryDuration() time.Duration {
+	d, _ := time.ParseDuration(c.StreamRetry)
+	return d
+}
+
+// Merge applies non-zero values from the overlay configuration.
+func (c *WorkflowsConfig) Merge(overlay *WorkflowsConfig) {
+	if overlay.MaxConcurrent != 0 {
+		c.MaxConcurrent = overlay.MaxConcurrent
+	}
+	if overlay.StreamRetry != "" {
+		c.StreamRetry = overlay.StreamRetry
+	}
+}
+
+func (c *WorkflowsConfig) loadEnv(env *WorkflowsConfigEnv) {
+	if env.MaxConcurrent != "" {
+		if v := os.Getenv(env.MaxConcurrent); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				c.MaxConcurrent = n
+			}
+		}
+	}
+	if env.StreamRetry != "" {
+		if v := os.Getenv(env.StreamRetry); v != "" {
+			c.StreamRetry = v
+		}
+	}
+}
@@ -8,32 +8,74 @@ import (
 	"github.com/google/uuid"
 )
 
+// Domain event types published to the event bus. Event data is the affected Document.
+const (
+	EventCreated = "document.created"
+	EventUpdated = "document.updated"
+	EventDeleted = "document.deleted"
+)
+
 // Document represents a stored document with metadata.
+// The file fields describe the latest version, numbered by Version.
+// PageCountPending is set while the page count of a large PDF is still
+// being extracted in the background; PageCount is nil until it completes.
 type Document struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	Filename         string    `json:"filename"`
+	ContentType      string    `json:"content_type"`
+	SizeBytes        int64     `json:"size_bytes"`
+	PageCount        *int      `json:"page_count,omitempty"`
+	PageCountPending bool      `json:"page_count_pending,omitempty"`
+	StorageKey       string    `json:"storage_key"`
+	Version          int       `json:"version"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Version represents one stored file of a document. Versions are numbered
+// from 1 and prior versions remain retrievable after a replacement upload.
+type Version struct {
+	DocumentID  uuid.UUID `json:"document_id"`
+	Version     int       `json:"version"`
 	Filename    string    `json:"filename"`
 	ContentType string    `json:"content_type"`
 	SizeBytes   int64     `json:"size_bytes"`
 	PageCount   *int      `json:"page_count,omitempty"`
 	StorageKey  string    `json:"storage_key"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 // CreateCommand contains the data required to create a new document.
-// Data holds the raw file bytes to be stored.
+// Data holds the raw file bytes to be stored. When PageCountPending is set
+// the page count is extracted in the background after the document is
+// stored, opening Data with Password, which is never persisted.
 type CreateCommand struct {
-	Name        string
-	Filename    string
-	ContentType string
-	SizeBytes   int64
-	PageCount   *int
-	Data        []byte
+	Name             string
+	Filename         string
+	ContentType      string
+	SizeBytes        int64
+	PageCount        *int
+	PageCountPending bool
+	Password         string
+	Data             []byte
+}
+
+// CreateVersionCommand contains the file data for a new version of an
+// existing document. The display name is unchanged. PageCountPending and
+// Password behave as in CreateCommand.
+type CreateVersionCommand struct {
+	Filename         string
+	ContentType      string
+	SizeBytes        int64
+	PageCount        *int
+	PageCountPending bool
+	Password         string
+	Data             []byte
 }
 
 // UpdateCommand contains the fields that can be modified on an existing document.
-// Only the display name can be changed; the stored file is immutable.
+// Only the display name can be changed; files are replaced by creating a new version.
 type UpdateCommand struct {
 	Name string
 }
@@ -0,0 +1,53 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// pdfConfig returns a pdfcpu configuration that tries password as both the
+// user and owner password.
+func pdfConfig(password string) *model.Configuration {
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = password
+	conf.OwnerPW = password
+	return conf
+}
+
+// EncryptionError wraps err with ErrEncrypted if it reports a missing or
+// incorrect PDF password. Other errors, including nil, are returned unchanged.
+func EncryptionError(err error) error {
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return fmt.Errorf("%w: %v", ErrEncrypted, err)
+	}
+	return err
+}
+
+// DecryptPDF writes a copy of the encrypted PDF at path, decrypted with
+// password, to a temporary file and returns its path. The caller must remove
+// the file. Returns an error wrapping ErrEncrypted if password is empty or
+// incorrect.
+func DecryptPDF(path, password string) (string, error) {
+	if password == "" {
+		return "", ErrEncrypted
+	}
+
+	f, err := os.CreateTemp("", "agent-lab-decrypted-*.pdf")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := f.Name()
+	f.Close()
+
+	if err := api.DecryptFile(path, tmpPath, pdfConfig(password)); err != nil {
+		os.Remove(tmpPath)
+		return "", EncryptionError(err)
+	}
+
+	return tmpPath, nil
+}
@@ -3,21 +3,39 @@ package documents
 import (
 	"errors"
 	"net/http"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
 )
 
 // Domain errors for document operations.
 var (
-	ErrNotFound     = errors.New("document not found")
-	ErrDuplicate    = errors.New("document storage key already exists")
-	ErrFileTooLarge = errors.New("file exceeds maximum upload size")
-	ErrInvalidFile  = errors.New("invalid file")
+	ErrNotFound        = errors.New("document not found")
+	ErrDuplicate       = errors.New("document storage key already exists")
+	ErrFileTooLarge    = errors.New("file exceeds maximum upload size")
+	ErrInvalidFile     = errors.New("invalid file")
+	ErrVersionNotFound = errors.New("document version not found")
+	ErrEncrypted       = errors.New("document is encrypted and requires a valid password")
 )
 
+func init() {
+	handlers.RegisterErrorCode("not_found", ErrNotFound, ErrVersionNotFound)
+	handlers.RegisterErrorCode("duplicate", ErrDuplicate)
+	handlers.RegisterErrorCode("file_too_large", ErrFileTooLarge)
+	handlers.RegisterErrorCode("invalid_file", ErrInvalidFile)
+	handlers.RegisterErrorCode("document_encrypted", ErrEncrypted)
+	handlers.RegisterErrorCode("quota_exceeded", storage.ErrQuotaExceeded)
+}
+
 // MapHTTPStatus converts domain errors to appropriate HTTP status codes.
 func MapHTTPStatus(err error) int {
 	if errors.Is(err, ErrNotFound) {
 		return http.StatusNotFound
 	}
+	if errors.Is(err, ErrVersionNotFound) {
+		return http.StatusNotFound
+	}
 	if errors.Is(err, ErrDuplicate) {
 		return http.StatusConflict
 	}
@@ -27,5 +45,14 @@ func MapHTTPStatus(err error) int {
 	if errors.Is(err, ErrInvalidFile) {
 		return http.StatusBadRequest
 	}
+	if errors.Is(err, ErrEncrypted) {
+		return http.StatusUnprocessableEntity
+	}
+	if errors.Is(err, storage.ErrQuotaExceeded) {
+		return http.StatusInsufficientStorage
+	}
+	if errors.Is(err, query.ErrInvalidSort) {
+		return http.StatusBadRequest
+	}
 	return http.StatusInternalServerError
 }
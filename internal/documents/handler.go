@@ -2,33 +2,44 @@ package documents
 
 import (
 	"bytes"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"strconv"
 
 	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/agent-lab/pkg/routes"
 	"github.com/google/uuid"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
-	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 )
 
+// multipartOverhead allows for multipart boundaries and form fields beyond
+// the file content when limiting the upload request body.
+const multipartOverhead = 1 << 20
+
 // Handler provides HTTP endpoints for document operations.
 type Handler struct {
-	sys           System
-	logger        *slog.Logger
-	pagination    pagination.Config
-	maxUploadSize int64
+	sys                System
+	logger             *slog.Logger
+	pagination         pagination.Config
+	maxUploadSize      int64
+	asyncPageCountSize int64
 }
 
 // NewHandler creates a document handler with the specified configuration.
-func NewHandler(sys System, logger *slog.Logger, pagination pagination.Config, maxUploadSize int64) *Handler {
+// PDFs larger than asyncPageCountSize are handed to the system to count in
+// the background; 0 counts every PDF before responding.
+func NewHandler(sys System, logger *slog.Logger, pagination pagination.Config, maxUploadSize, asyncPageCountSize int64) *Handler {
 	return &Handler{
-		sys:           sys,
-		logger:        logger.With("handler", "documents"),
-		pagination:    pagination,
-		maxUploadSize: maxUploadSize,
+		sys:                sys,
+		logger:             logger.With("handler", "documents"),
+		pagination:         pagination,
+		maxUploadSize:      maxUploadSize,
+		asyncPageCountSize: asyncPageCountSize,
 	}
 }
 
@@ -45,21 +56,30 @@ func (h *Handler) Routes() routes.Group {
 			{Method: "POST", Pattern: "", Handler: h.Upload, OpenAPI: Spec.Upload},
 			{Method: "PUT", Pattern: "/{id}", Handler: h.Update, OpenAPI: Spec.Update},
 			{Method: "DELETE", Pattern: "/{id}", Handler: h.Delete, OpenAPI: Spec.Delete},
+			{Method: "GET", Pattern: "/{id}/download", Handler: h.Download, OpenAPI: Spec.Download},
+			{Method: "GET", Pattern: "/{id}/versions", Handler: h.ListVersions, OpenAPI: Spec.ListVersions},
+			{Method: "POST", Pattern: "/{id}/versions", Handler: h.CreateVersion, OpenAPI: Spec.CreateVersion},
+			{Method: "GET", Pattern: "/{id}/versions/{version}/download", Handler: h.DownloadVersion, OpenAPI: Spec.DownloadVersion},
 		},
 	}
 }
 
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	page := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
 	filters := FiltersFromQuery(r.URL.Query())
 
 	result, err := h.sys.List(r.Context(), page, filters)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	pagination.Respond(w, r, result)
 }
 
 func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
@@ -75,12 +95,17 @@ func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, doc)
+	handlers.RespondFields(w, r, http.StatusOK, doc)
 }
 
 func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 	var page pagination.PageRequest
-	if err := json.NewDecoder(r.Body).Decode(&page); err != nil {
+	if err := handlers.DecodeJSON(w, r, &page, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	if err := page.Normalize(h.pagination); err != nil {
 		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
 		return
 	}
@@ -89,70 +114,121 @@ func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.sys.List(r.Context(), page, filters)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	pagination.Respond(w, r, result)
 }
 
 func (h *Handler) Upload(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusRequestEntityTooLarge, ErrFileTooLarge)
+	file, status, err := h.readUpload(w, r)
+	if err != nil {
+		handlers.RespondError(w, h.logger, status, err)
 		return
 	}
 
-	file, header, err := r.FormFile("file")
+	name := r.FormValue("name")
+	if name == "" {
+		name = file.Filename
+	}
+
+	cmd := CreateCommand{
+		Name:             name,
+		Filename:         file.Filename,
+		ContentType:      file.ContentType,
+		SizeBytes:        file.SizeBytes,
+		PageCount:        file.PageCount,
+		PageCountPending: file.PageCountPending,
+		Password:         file.Password,
+		Data:             file.Data,
+	}
+
+	doc, err := h.sys.Create(r.Context(), cmd)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, ErrInvalidFile)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
-	defer file.Close()
 
-	if header.Size > h.maxUploadSize {
-		handlers.RespondError(w, h.logger, http.StatusRequestEntityTooLarge, ErrFileTooLarge)
+	handlers.RespondJSON(w, http.StatusCreated, doc)
+}
+
+// CreateVersion handles POST /{id}/versions - uploads a replacement file as
+// the document's next version.
+func (h *Handler) CreateVersion(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
 		return
 	}
 
-	data := make([]byte, header.Size)
-	if _, err := file.Read(data); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, ErrInvalidFile)
+	file, status, err := h.readUpload(w, r)
+	if err != nil {
+		handlers.RespondError(w, h.logger, status, err)
 		return
 	}
 
-	contentType := detectContentType(header.Header.Get("Content-Type"), data)
+	doc, err := h.sys.CreateVersion(r.Context(), id, file)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
 
-	name := r.FormValue("name")
-	if name == "" {
-		name = header.Filename
+	handlers.RespondJSON(w, http.StatusCreated, doc)
+}
+
+// ListVersions handles GET /{id}/versions - lists all versions, newest first.
+func (h *Handler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
 	}
 
-	var pageCount *int
-	if contentType == "application/pdf" {
-		pc, err := extractPDFPageCount(data)
-		if err != nil {
-			h.logger.Warn("failed to extract pdf page count", "error", err)
-		} else {
-			pageCount = pc
-		}
+	versions, err := h.sys.ListVersions(r.Context(), id)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
 	}
 
-	cmd := CreateCommand{
-		Name:        name,
-		Filename:    header.Filename,
-		ContentType: contentType,
-		SizeBytes:   header.Size,
-		PageCount:   pageCount,
-		Data:        data,
+	handlers.RespondJSON(w, http.StatusOK, versions)
+}
+
+// Download handles GET /{id}/download - returns the latest version's file.
+func (h *Handler) Download(w http.ResponseWriter, r *http.Request) {
+	h.download(w, r, 0)
+}
+
+// DownloadVersion handles GET /{id}/versions/{version}/download - returns
+// the file stored for a specific version.
+func (h *Handler) DownloadVersion(w http.ResponseWriter, r *http.Request) {
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version < 1 {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("invalid version %q: must be a positive integer", r.PathValue("version")))
+		return
 	}
 
-	doc, err := h.sys.Create(r.Context(), cmd)
+	h.download(w, r, version)
+}
+
+func (h *Handler) download(w http.ResponseWriter, r *http.Request, version int) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	data, v, err := h.sys.Download(r.Context(), id, version)
 	if err != nil {
 		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusCreated, doc)
+	w.Header().Set("Content-Type", v.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": v.Filename}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
 }
 
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
@@ -163,8 +239,8 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var cmd UpdateCommand
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -192,6 +268,77 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// readUpload reads the multipart "file" field, enforcing the upload size
+// limit, detecting the content type, and extracting the PDF page count.
+// Encrypted PDFs are opened with the optional "password" field, which is
+// used only for page counting and never stored. PDFs above the async page
+// count size are not counted here; the command is marked pending instead.
+// On failure it returns the HTTP status to respond with.
+func (h *Handler) readUpload(w http.ResponseWriter, r *http.Request) (CreateVersionCommand, int, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize+multipartOverhead)
+
+	if err := r.ParseMultipartForm(h.maxUploadSize); err != nil {
+		return CreateVersionCommand{}, http.StatusRequestEntityTooLarge, ErrFileTooLarge
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return CreateVersionCommand{}, http.StatusBadRequest, ErrInvalidFile
+	}
+	defer file.Close()
+
+	if header.Size > h.maxUploadSize {
+		return CreateVersionCommand{}, http.StatusRequestEntityTooLarge, ErrFileTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxUploadSize+1))
+	if err != nil {
+		return CreateVersionCommand{}, http.StatusBadRequest, ErrInvalidFile
+	}
+
+	if int64(len(data)) > h.maxUploadSize {
+		return CreateVersionCommand{}, http.StatusRequestEntityTooLarge, ErrFileTooLarge
+	}
+
+	if int64(len(data)) != header.Size {
+		return CreateVersionCommand{}, http.StatusBadRequest, ErrInvalidFile
+	}
+
+	contentType := detectContentType(header.Header.Get("Content-Type"), data)
+
+	if contentType == "application/pdf" && h.asyncPageCountSize > 0 && int64(len(data)) > h.asyncPageCountSize {
+		return CreateVersionCommand{
+			Filename:         header.Filename,
+			ContentType:      contentType,
+			SizeBytes:        header.Size,
+			PageCountPending: true,
+			Password:         r.FormValue("password"),
+			Data:             data,
+		}, http.StatusOK, nil
+	}
+
+	var pageCount *int
+	if contentType == "application/pdf" {
+		pc, err := extractPDFPageCount(data, r.FormValue("password"))
+		if errors.Is(err, ErrEncrypted) {
+			return CreateVersionCommand{}, MapHTTPStatus(err), err
+		}
+		if err != nil {
+			h.logger.Warn("failed to extract pdf page count", "error", err)
+		} else {
+			pageCount = pc
+		}
+	}
+
+	return CreateVersionCommand{
+		Filename:    header.Filename,
+		ContentType: contentType,
+		SizeBytes:   header.Size,
+		PageCount:   pageCount,
+		Data:        data,
+	}, http.StatusOK, nil
+}
+
 func detectContentType(header string, data []byte) string {
 	if header != "" && header != "application/octet-stream" {
 		return header
@@ -199,10 +346,10 @@ func detectContentType(header string, data []byte) string {
 	return http.DetectContentType(data)
 }
 
-func extractPDFPageCount(data []byte) (*int, error) {
-	count, err := api.PageCount(bytes.NewReader(data), model.NewDefaultConfiguration())
+func extractPDFPageCount(data []byte, password string) (*int, error) {
+	count, err := api.PageCount(bytes.NewReader(data), pdfConfig(password))
 	if err != nil {
-		return nil, err
+		return nil, EncryptionError(err)
 	}
 	return &count, nil
 }
@@ -2,6 +2,7 @@ package documents
 
 import (
 	"net/url"
+	"strconv"
 
 	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/agent-lab/pkg/repository"
@@ -9,14 +10,34 @@ import (
 
 var projection = query.NewProjectionMap("public", "documents", "d").
 	Project("id", "ID").
-	Project("name", "Name").
-	Project("filename", "Filename").
-	Project("content_type", "ContentType").
+	ProjectText("name", "Name").
+	ProjectText("filename", "Filename").
+	ProjectText("content_type", "ContentType").
 	Project("size_bytes", "SizeBytes").
 	Project("page_count", "PageCount").
-	Project("storage_key", "StorageKey").
+	Project("page_count_pending", "PageCountPending").
+	ProjectText("storage_key", "StorageKey").
+	Project("version", "Version").
 	Project("created_at", "CreatedAt").
-	Project("updated_at", "UpdatedAt")
+	Project("updated_at", "UpdatedAt").
+	Sortable("ID", "Name", "Filename", "ContentType", "SizeBytes", "PageCount", "Version", "CreatedAt", "UpdatedAt")
+
+var versionProjection = query.NewProjectionMap("public", "document_versions", "v").
+	Project("document_id", "DocumentID").
+	Project("version", "Version").
+	ProjectText("filename", "Filename").
+	ProjectText("content_type", "ContentType").
+	Project("size_bytes", "SizeBytes").
+	Project("page_count", "PageCount").
+	ProjectText("storage_key", "StorageKey").
+	Project("created_at", "CreatedAt")
+
+var versionSort = query.SortField{Field: "Version", Descending: true}
+
+// imageProjection maps the images columns used to correlate documents with
+// their rendered pages.
+var imageProjection = query.NewProjectionMap("public", "images", "i").
+	Project("document_id", "DocumentID")
 
 var defaultSort = query.SortField{Field: "CreatedAt", Descending: true}
 
@@ -29,17 +50,37 @@ func scanDocument(s repository.Scanner) (Document, error) {
 		&d.ContentType,
 		&d.SizeBytes,
 		&d.PageCount,
+		&d.PageCountPending,
 		&d.StorageKey,
+		&d.Version,
 		&d.CreatedAt,
 		&d.UpdatedAt,
 	)
 	return d, err
 }
 
+func scanVersion(s repository.Scanner) (Version, error) {
+	var v Version
+	err := s.Scan(
+		&v.DocumentID,
+		&v.Version,
+		&v.Filename,
+		&v.ContentType,
+		&v.SizeBytes,
+		&v.PageCount,
+		&v.StorageKey,
+		&v.CreatedAt,
+	)
+	return v, err
+}
+
 // Filters contains optional criteria for filtering document queries.
+// HasImages restricts results to documents with (true) or without (false)
+// at least one rendered image.
 type Filters struct {
 	Name        *string
 	ContentType *string
+	HasImages   *bool
 }
 
 // FiltersFromQuery extracts document filters from URL query parameters.
@@ -54,12 +95,29 @@ func FiltersFromQuery(values url.Values) Filters {
 		f.ContentType = &ct
 	}
 
+	if hi := values.Get("has_images"); hi != "" {
+		if parsed, err := strconv.ParseBool(hi); err == nil {
+			f.HasImages = &parsed
+		}
+	}
+
 	return f
 }
 
 // Apply adds filter conditions to the query builder.
 func (f Filters) Apply(b *query.Builder) *query.Builder {
-	return b.
+	b.
 		WhereContains("Name", f.Name).
 		WhereContains("ContentType", f.ContentType)
+
+	if f.HasImages != nil {
+		images := query.NewBuilder(imageProjection)
+		if *f.HasImages {
+			b.WhereExistsIn("ID", images, "DocumentID")
+		} else {
+			b.WhereNotExistsIn("ID", images, "DocumentID")
+		}
+	}
+
+	return b
 }
@@ -9,6 +9,11 @@ type spec struct {
 	Upload *openapi.Operation
 	Update *openapi.Operation
 	Delete *openapi.Operation
+
+	Download        *openapi.Operation
+	ListVersions    *openapi.Operation
+	CreateVersion   *openapi.Operation
+	DownloadVersion *openapi.Operation
 }
 
 var Spec = spec{
@@ -21,9 +26,11 @@ var Spec = spec{
 			openapi.QueryParam("search", "string", "Search in name and filename", false),
 			openapi.QueryParam("name", "string", "Filter by name (contains)", false),
 			openapi.QueryParam("content_type", "string", "Filter by content type (contains)", false),
+			openapi.QueryParam("has_images", "boolean", "Filter by whether the document has rendered images", false),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Documents list", "DocumentPageResult"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Documents list", "DocumentPageResult"), openapi.PageHeaders(true)),
 		},
 	},
 	Find: &openapi.Operation{
@@ -31,6 +38,7 @@ var Spec = spec{
 		Description: "Find document by ID",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Document ID"),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
 			200: openapi.ResponseJSON("Document details", "Document"),
@@ -43,16 +51,18 @@ var Spec = spec{
 		Parameters: []*openapi.Parameter{
 			openapi.QueryParam("name", "string", "Filter by name (contains)", false),
 			openapi.QueryParam("content_type", "string", "Filter by content type (contains)", false),
+			openapi.QueryParam("has_images", "boolean", "Filter by whether the document has rendered images", false),
+			openapi.FieldsParam(),
 		},
 		RequestBody: openapi.RequestBodyJSON("PageRequest", true),
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Search results", "DocumentPageResult"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Search results", "DocumentPageResult"), openapi.PageHeaders(false)),
 			400: openapi.ResponseRef("BadRequest"),
 		},
 	},
 	Upload: &openapi.Operation{
 		Summary:     "Upload document",
-		Description: "Upload a document file with optional display name. PDFs have page count extracted automatically.",
+		Description: "Upload a document file with optional display name. PDFs have page count extracted automatically; for PDFs above the configured async size the upload returns immediately with page_count_pending set and the count is filled in once background extraction completes.",
 		RequestBody: &openapi.RequestBody{
 			Required: true,
 			Content: map[string]*openapi.MediaType{
@@ -60,8 +70,9 @@ var Spec = spec{
 					Schema: &openapi.Schema{
 						Type: "object",
 						Properties: map[string]*openapi.Schema{
-							"file": {Type: "string", Description: "Document file to upload"},
-							"name": {Type: "string", Description: "Optional display name (defaults to filename)"},
+							"file":     {Type: "string", Description: "Document file to upload"},
+							"name":     {Type: "string", Description: "Optional display name (defaults to filename)"},
+							"password": {Type: "string", Format: "password", Description: "Password for encrypted PDFs, used only to extract the page count and never stored"},
 						},
 						Required: []string{"file"},
 					},
@@ -72,6 +83,8 @@ var Spec = spec{
 			201: openapi.ResponseJSON("Document uploaded", "Document"),
 			400: openapi.ResponseRef("BadRequest"),
 			413: {Description: "File too large"},
+			422: {Description: "PDF is encrypted and the password is absent or wrong"},
+			507: {Description: "Storage quota exceeded"},
 		},
 	},
 	Update: &openapi.Operation{
@@ -98,6 +111,90 @@ var Spec = spec{
 			404: openapi.ResponseRef("NotFound"),
 		},
 	},
+	Download: &openapi.Operation{
+		Summary:     "Download document",
+		Description: "Download the file of the document's latest version",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Document ID"),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseWithHeaders(fileResponse, fileHeaders),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
+	ListVersions: &openapi.Operation{
+		Summary:     "List document versions",
+		Description: "List every stored version of a document, newest first",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Document ID"),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Document versions", "DocumentVersionArray"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
+	CreateVersion: &openapi.Operation{
+		Summary:     "Upload document version",
+		Description: "Upload a replacement file as the document's next version. Prior versions remain downloadable. PDFs have page count extracted automatically, in the background for PDFs above the configured async size.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Document ID"),
+		},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]*openapi.MediaType{
+				"multipart/form-data": {
+					Schema: &openapi.Schema{
+						Type: "object",
+						Properties: map[string]*openapi.Schema{
+							"file":     {Type: "string", Description: "Replacement document file"},
+							"password": {Type: "string", Format: "password", Description: "Password for encrypted PDFs, used only to extract the page count and never stored"},
+						},
+						Required: []string{"file"},
+					},
+				},
+			},
+		},
+		Responses: map[int]*openapi.Response{
+			201: openapi.ResponseJSON("Version uploaded", "Document"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			413: {Description: "File too large"},
+			422: {Description: "PDF is encrypted and the password is absent or wrong"},
+			507: {Description: "Storage quota exceeded"},
+		},
+	},
+	DownloadVersion: &openapi.Operation{
+		Summary:     "Download document version",
+		Description: "Download the file stored for a specific document version",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Document ID"),
+			{
+				Name:        "version",
+				In:          "path",
+				Required:    true,
+				Description: "Version number, starting at 1",
+				Schema:      &openapi.Schema{Type: "integer"},
+			},
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseWithHeaders(fileResponse, fileHeaders),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
+}
+
+var fileResponse = &openapi.Response{
+	Description: "Document file",
+	Content: map[string]*openapi.MediaType{
+		"application/octet-stream": {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+	},
+}
+
+var fileHeaders = map[string]*openapi.Header{
+	"Content-Disposition": openapi.NewHeader("string", "Attachment with the version's original filename"),
 }
 
 func (spec) Schemas() map[string]*openapi.Schema {
@@ -105,17 +202,36 @@ func (spec) Schemas() map[string]*openapi.Schema {
 		"Document": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
-				"id":           {Type: "string", Format: "uuid"},
-				"name":         {Type: "string", Description: "Display name"},
+				"id":                 {Type: "string", Format: "uuid"},
+				"name":               {Type: "string", Description: "Display name"},
+				"filename":           {Type: "string", Description: "Original filename"},
+				"content_type":       {Type: "string", Description: "MIME type"},
+				"size_bytes":         {Type: "integer", Format: "int64", Description: "File size in bytes"},
+				"page_count":         {Type: "integer", Description: "Page count (PDFs only)"},
+				"page_count_pending": {Type: "boolean", Description: "Page count is still being extracted in the background"},
+				"storage_key":        {Type: "string", Description: "Storage location key"},
+				"version":            {Type: "integer", Description: "Latest version number"},
+				"created_at":         {Type: "string", Format: "date-time"},
+				"updated_at":         {Type: "string", Format: "date-time"},
+			},
+		},
+		"DocumentVersion": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"document_id":  {Type: "string", Format: "uuid"},
+				"version":      {Type: "integer", Description: "Version number, starting at 1"},
 				"filename":     {Type: "string", Description: "Original filename"},
 				"content_type": {Type: "string", Description: "MIME type"},
 				"size_bytes":   {Type: "integer", Format: "int64", Description: "File size in bytes"},
 				"page_count":   {Type: "integer", Description: "Page count (PDFs only)"},
 				"storage_key":  {Type: "string", Description: "Storage location key"},
 				"created_at":   {Type: "string", Format: "date-time"},
-				"updated_at":   {Type: "string", Format: "date-time"},
 			},
 		},
+		"DocumentVersionArray": {
+			Type:  "array",
+			Items: openapi.SchemaRef("DocumentVersion"),
+		},
 		"UpdateDocumentCommand": {
 			Type:     "object",
 			Required: []string{"name"},
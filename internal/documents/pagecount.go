@@ -0,0 +1,44 @@
+package documents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// PageCountKey returns the key identifying a page count extraction: a digest
+// of the file content and the password it is opened with.
+func PageCountKey(data []byte, password string) string {
+	h := sha256.New()
+	h.Write(data)
+	h.Write([]byte{0})
+	h.Write([]byte(password))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// PageCounter extracts PDF page counts in the background. Concurrent counts
+// of the same key share one extraction, so re-uploading a large file while
+// its count is still running does not parse it twice.
+type PageCounter struct {
+	group singleflight.Group
+}
+
+// Start counts pages for key in a new goroutine and calls done with the
+// result. extract runs once per key no matter how many callers are waiting;
+// each caller's done receives its own copy of the count.
+func (c *PageCounter) Start(key string, extract func() (*int, error), done func(*int, error)) {
+	go func() {
+		v, err, _ := c.group.Do(key, func() (any, error) {
+			return extract()
+		})
+		n, _ := v.(*int)
+		if err != nil || n == nil {
+			done(nil, err)
+			return
+		}
+
+		count := *n
+		done(&count, nil)
+	}()
+}
@@ -3,11 +3,15 @@ package documents
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/pkg/events"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/agent-lab/pkg/repository"
@@ -18,22 +22,28 @@ import (
 type repo struct {
 	db         *sql.DB
 	storage    storage.System
+	events     *events.Bus
 	logger     *slog.Logger
 	pagination pagination.Config
+	cfg        config.DocumentsConfig
+	pages      PageCounter
 }
 
 // New creates a document repository with database and blob storage integration.
-func New(db *sql.DB, storage storage.System, logger *slog.Logger, pagination pagination.Config) System {
+// Document lifecycle events are published to bus, which may be nil.
+func New(db *sql.DB, storage storage.System, bus *events.Bus, logger *slog.Logger, pagination pagination.Config, cfg config.DocumentsConfig) System {
 	return &repo{
 		db:         db,
 		storage:    storage,
+		events:     bus,
 		logger:     logger.With("system", "documents"),
 		pagination: pagination,
+		cfg:        cfg,
 	}
 }
 
 func (r *repo) Handler(maxUploadSize int64) *Handler {
-	return NewHandler(r, r.logger, r.pagination, maxUploadSize)
+	return NewHandler(r, r.logger, r.pagination, maxUploadSize, r.cfg.AsyncPageCountSizeBytes())
 }
 
 func (r *repo) List(ctx context.Context, page pagination.PageRequest, filters Filters) (*pagination.PageResult[Document], error) {
@@ -45,8 +55,8 @@ func (r *repo) List(ctx context.Context, page pagination.PageRequest, filters Fi
 
 	filters.Apply(qb)
 
-	if len(page.Sort) > 0 {
-		qb.OrderByFields(page.Sort)
+	if err := qb.SortBy(page.Sort); err != nil {
+		return nil, err
 	}
 
 	countSQL, countArgs := qb.BuildCount()
@@ -85,14 +95,18 @@ func (r *repo) Create(ctx context.Context, cmd CreateCommand) (*Document, error)
 		return nil, fmt.Errorf("store file: %w", err)
 	}
 
-	q := `INSERT INTO documents(id, name, filename, content_type, size_bytes, page_count, storage_key)
-		Values($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, name, filename, content_type, size_bytes, page_count, storage_key, created_at, updated_at`
+	q := `INSERT INTO documents(id, name, filename, content_type, size_bytes, page_count, page_count_pending, storage_key)
+		Values($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, name, filename, content_type, size_bytes, page_count, page_count_pending, storage_key, version, created_at, updated_at`
 
 	doc, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Document, error) {
-		return repository.QueryOne(ctx, tx, q, []any{
-			id, cmd.Name, cmd.Filename, cmd.ContentType, cmd.SizeBytes, cmd.PageCount, storageKey,
+		doc, err := repository.QueryOne(ctx, tx, q, []any{
+			id, cmd.Name, cmd.Filename, cmd.ContentType, cmd.SizeBytes, cmd.PageCount, cmd.PageCountPending, storageKey,
 		}, scanDocument)
+		if err != nil {
+			return doc, err
+		}
+		return doc, insertVersion(ctx, tx, doc)
 	})
 
 	if err != nil {
@@ -103,13 +117,121 @@ func (r *repo) Create(ctx context.Context, cmd CreateCommand) (*Document, error)
 	}
 
 	r.logger.Info("document created", "id", doc.ID, "name", doc.Name, "storage_key", storageKey)
+	r.events.Publish(ctx, events.Event{Type: EventCreated, Subject: doc.ID.String(), Data: doc})
+
+	if doc.PageCountPending {
+		r.countPages(ctx, doc, cmd.Data, cmd.Password)
+	}
 	return &doc, nil
 }
 
+func (r *repo) CreateVersion(ctx context.Context, id uuid.UUID, cmd CreateVersionCommand) (*Document, error) {
+	var storageKey string
+
+	doc, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Document, error) {
+		var current int
+		lock := `SELECT version FROM documents WHERE id = $1 FOR UPDATE`
+		if err := tx.QueryRowContext(ctx, lock, id).Scan(&current); err != nil {
+			return Document{}, err
+		}
+
+		next := current + 1
+		storageKey = buildVersionStorageKey(id, next, cmd.Filename)
+
+		if err := r.storage.Store(ctx, storageKey, cmd.Data); err != nil {
+			return Document{}, fmt.Errorf("store file: %w", err)
+		}
+
+		q := `UPDATE documents
+			SET filename = $1, content_type = $2, size_bytes = $3, page_count = $4,
+				page_count_pending = $5, storage_key = $6, version = $7, updated_at = NOW()
+			WHERE id = $8
+			RETURNING id, name, filename, content_type, size_bytes, page_count, page_count_pending, storage_key, version, created_at, updated_at`
+
+		doc, err := repository.QueryOne(ctx, tx, q, []any{
+			cmd.Filename, cmd.ContentType, cmd.SizeBytes, cmd.PageCount, cmd.PageCountPending, storageKey, next, id,
+		}, scanDocument)
+		if err != nil {
+			return doc, err
+		}
+		return doc, insertVersion(ctx, tx, doc)
+	})
+
+	if err != nil {
+		if storageKey != "" {
+			if delErr := r.storage.Delete(ctx, storageKey); delErr != nil {
+				r.logger.Error("cleanup failed after db error", "storage_key", storageKey, "error", delErr)
+			}
+		}
+		return nil, repository.MapError(err, ErrNotFound, ErrDuplicate)
+	}
+
+	r.logger.Info("document version created", "id", doc.ID, "version", doc.Version, "storage_key", storageKey)
+	r.events.Publish(ctx, events.Event{Type: EventUpdated, Subject: doc.ID.String(), Data: doc})
+
+	if doc.PageCountPending {
+		r.countPages(ctx, doc, cmd.Data, cmd.Password)
+	}
+	return &doc, nil
+}
+
+func (r *repo) ListVersions(ctx context.Context, id uuid.UUID) ([]Version, error) {
+	if _, err := r.Find(ctx, id); err != nil {
+		return nil, err
+	}
+
+	q, args := query.
+		NewBuilder(versionProjection, versionSort).
+		WhereEquals("DocumentID", id).
+		Build()
+
+	versions, err := repository.QueryMany(ctx, r.db, q, args, scanVersion)
+	if err != nil {
+		return nil, fmt.Errorf("query document versions: %w", err)
+	}
+	return versions, nil
+}
+
+func (r *repo) FindVersion(ctx context.Context, id uuid.UUID, version int) (*Version, error) {
+	q, args := query.
+		NewBuilder(versionProjection).
+		WhereEquals("DocumentID", id).
+		WhereEquals("Version", version).
+		BuildSingleOrNull()
+
+	v, err := repository.QueryOne(ctx, r.db, q, args, scanVersion)
+	if err != nil {
+		return nil, repository.MapError(err, ErrVersionNotFound, ErrDuplicate)
+	}
+	return &v, nil
+}
+
+func (r *repo) Download(ctx context.Context, id uuid.UUID, version int) ([]byte, *Version, error) {
+	if version <= 0 {
+		doc, err := r.Find(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		version = doc.Version
+	}
+
+	v, err := r.FindVersion(ctx, id, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := r.storage.Retrieve(ctx, v.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("retrieve file: %w", err)
+	}
+
+	return data, v, nil
+}
+
 func (r *repo) Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Document, error) {
 	q := `UPDATE documents SET name = $1, updated_at = NOW()
 		WHERE id = $2
-		RETURNING id, name, filename, content_type, size_bytes, page_count, storage_key, created_at, updated_at`
+		RETURNING id, name, filename, content_type, size_bytes, page_count, page_count_pending, storage_key, version, created_at, updated_at`
 
 	doc, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Document, error) {
 		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, id}, scanDocument)
@@ -120,6 +242,7 @@ func (r *repo) Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Do
 	}
 
 	r.logger.Info("document updated", "id", doc.ID, "name", doc.Name)
+	r.events.Publish(ctx, events.Event{Type: EventUpdated, Subject: doc.ID.String(), Data: doc})
 	return &doc, nil
 }
 
@@ -132,6 +255,19 @@ func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	keysQ := `SELECT storage_key FROM document_versions WHERE document_id = $1 ORDER BY version DESC`
+	keys, err := repository.QueryMany(ctx, r.db, keysQ, []any{id}, func(s repository.Scanner) (string, error) {
+		var key string
+		err := s.Scan(&key)
+		return key, err
+	})
+	if err != nil {
+		return fmt.Errorf("query document versions: %w", err)
+	}
+	if !slices.Contains(keys, doc.StorageKey) {
+		keys = append(keys, doc.StorageKey)
+	}
+
 	q := `DELETE FROM documents WHERE id = $1`
 	_, err = repository.WithTx(ctx, r.db, func(tx *sql.Tx) (struct{}, error) {
 		return struct{}{}, repository.ExecExpectOne(ctx, tx, q, id)
@@ -141,18 +277,92 @@ func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
 		return repository.MapError(err, ErrNotFound, ErrDuplicate)
 	}
 
-	if err := r.storage.Delete(ctx, doc.StorageKey); err != nil {
-		r.logger.Error("storage cleanup failed", "storage_key", doc.StorageKey, "error", err)
+	for _, key := range keys {
+		if err := r.storage.Delete(ctx, key); err != nil {
+			r.logger.Error("storage cleanup failed", "storage_key", key, "error", err)
+		}
 	}
 
 	r.logger.Info("document deleted", "id", id)
+	r.events.Publish(ctx, events.Event{Type: EventDeleted, Subject: id.String(), Data: *doc})
 	return nil
 }
 
+// countPages extracts the page count of doc's current version in the
+// background, detached from the request, and records it once done. A failed
+// extraction clears the pending flag and leaves the count unset.
+func (r *repo) countPages(ctx context.Context, doc Document, data []byte, password string) {
+	ctx = context.WithoutCancel(ctx)
+
+	extract := func() (*int, error) {
+		return extractPDFPageCount(data, password)
+	}
+
+	r.pages.Start(PageCountKey(data, password), extract, func(count *int, err error) {
+		if err != nil {
+			r.logger.Warn("failed to extract pdf page count", "id", doc.ID, "version", doc.Version, "error", err)
+		}
+		r.recordPageCount(ctx, doc.ID, doc.Version, count)
+	})
+}
+
+// recordPageCount stores count on a version and, if it is still the
+// document's current version, on the document, clearing its pending flag.
+func (r *repo) recordPageCount(ctx context.Context, id uuid.UUID, version int, count *int) {
+	versionQ := `UPDATE document_versions SET page_count = $1 WHERE document_id = $2 AND version = $3`
+	q := `UPDATE documents SET page_count = $1, page_count_pending = false
+		WHERE id = $2 AND version = $3
+		RETURNING id, name, filename, content_type, size_bytes, page_count, page_count_pending, storage_key, version, created_at, updated_at`
+
+	doc, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (*Document, error) {
+		if _, err := tx.ExecContext(ctx, versionQ, count, id, version); err != nil {
+			return nil, err
+		}
+
+		doc, err := repository.QueryOne(ctx, tx, q, []any{count, id, version}, scanDocument)
+		if errors.Is(err, sql.ErrNoRows) {
+			// The document was deleted or replaced by a newer version.
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &doc, nil
+	})
+
+	if err != nil {
+		r.logger.Error("failed to record pdf page count", "id", id, "version", version, "error", err)
+		return
+	}
+	if doc == nil {
+		return
+	}
+
+	r.logger.Info("document page count recorded", "id", id, "version", version)
+	r.events.Publish(ctx, events.Event{Type: EventUpdated, Subject: id.String(), Data: *doc})
+}
+
+// insertVersion records the file fields of doc as version doc.Version.
+func insertVersion(ctx context.Context, tx *sql.Tx, doc Document) error {
+	q := `INSERT INTO document_versions(document_id, version, filename, content_type, size_bytes, page_count, storage_key, created_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := tx.ExecContext(ctx, q,
+		doc.ID, doc.Version, doc.Filename, doc.ContentType, doc.SizeBytes, doc.PageCount, doc.StorageKey, doc.UpdatedAt,
+	)
+	return err
+}
+
 func buildStorageKey(id uuid.UUID, filename string) string {
 	return fmt.Sprintf("documents/%s/%s", id.String(), sanitizeFilename(filename))
 }
 
+// buildVersionStorageKey nests versions after the first under their number
+// so replacement uploads with the same filename do not collide.
+func buildVersionStorageKey(id uuid.UUID, version int, filename string) string {
+	return fmt.Sprintf("documents/%s/v%d/%s", id.String(), version, sanitizeFilename(filename))
+}
+
 func sanitizeFilename(name string) string {
 	name = filepath.Base(name)
 	replacer := strings.NewReplacer(
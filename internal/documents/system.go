@@ -16,4 +16,8 @@ type System interface {
 	Create(ctx context.Context, cmd CreateCommand) (*Document, error)
 	Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Document, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	CreateVersion(ctx context.Context, id uuid.UUID, cmd CreateVersionCommand) (*Document, error)
+	ListVersions(ctx context.Context, id uuid.UUID) ([]Version, error)
+	FindVersion(ctx context.Context, id uuid.UUID, version int) (*Version, error)
+	Download(ctx context.Context, id uuid.UUID, version int) ([]byte, *Version, error)
 }
@@ -0,0 +1,142 @@
+package images
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/JaimeStill/agent-lab/pkg/repository"
+	"github.com/google/uuid"
+)
+
+// AsyncRenderFunc renders the pages of pending images in the background,
+// writing each page's data to the image's StorageKey. It returns the images
+// it rendered with SizeBytes set, and the first error if any page failed.
+type AsyncRenderFunc func(ctx context.Context, pending []Image) ([]Image, error)
+
+// RenderAsync records the images a render of pages at opts will produce and
+// returns them without waiting for the render.
+//
+// Pages with a ready image are returned as is unless opts.Force is set, and
+// pages whose image is already pending are left to the render in progress.
+// Every other page gets a pending image, either a new record or its failed
+// or forced record reset to pending, all in one transaction. render then runs
+// for those images in the background, detached from ctx's cancellation. When
+// it returns, each pending image is marked ready or failed and done receives
+// the final records along with the first error hit while recording them.
+func RenderAsync(
+	ctx context.Context,
+	db *sql.DB,
+	documentID uuid.UUID,
+	pages []int,
+	opts RenderOptions,
+	render AsyncRenderFunc,
+	done func(final []Image, err error),
+) ([]Image, error) {
+	var pending []Image
+
+	images, err := repository.WithTx(ctx, db, func(tx *sql.Tx) ([]Image, error) {
+		images := make([]Image, 0, len(pages))
+		for _, pageNum := range pages {
+			img, err := findImage(ctx, tx, documentID, pageNum, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case img == nil:
+				img = opts.ToImage(uuid.New(), documentID, pageNum, imageStorageKey(documentID, opts), 0)
+				img.Status = StatusPending
+				if err := insertImage(ctx, tx, img); err != nil {
+					return nil, err
+				}
+				if img, err = findImage(ctx, tx, documentID, pageNum, opts); err != nil {
+					return nil, err
+				}
+				pending = append(pending, *img)
+			case img.Status == StatusPending:
+			case img.Status == StatusReady && !opts.Force:
+			default:
+				img.Status = StatusPending
+				img.SizeBytes = 0
+				img.RenderError = nil
+				if img, err = setRenderState(ctx, tx, *img); err != nil {
+					return nil, err
+				}
+				pending = append(pending, *img)
+			}
+
+			images = append(images, *img)
+		}
+		return images, nil
+	})
+
+	if err != nil {
+		return nil, repository.MapError(err, ErrNotFound, ErrDuplicate)
+	}
+
+	if len(pending) > 0 {
+		go finishAsyncRender(context.WithoutCancel(ctx), db, pending, render, done)
+	}
+
+	return images, nil
+}
+
+// finishAsyncRender runs render for pending and records each image as ready
+// or failed, then passes the results to done.
+func finishAsyncRender(ctx context.Context, db *sql.DB, pending []Image, render AsyncRenderFunc, done func([]Image, error)) {
+	rendered, renderErr := render(ctx, pending)
+
+	sizes := make(map[uuid.UUID]int64, len(rendered))
+	for _, img := range rendered {
+		sizes[img.ID] = img.SizeBytes
+	}
+
+	reason := "page was not rendered"
+	if renderErr != nil {
+		reason = renderErr.Error()
+	}
+
+	var firstErr error
+	final := make([]Image, 0, len(pending))
+	for _, img := range pending {
+		if size, ok := sizes[img.ID]; ok {
+			img.Status = StatusReady
+			img.SizeBytes = size
+		} else {
+			img.Status = StatusFailed
+			img.RenderError = &reason
+		}
+
+		updated, err := setRenderState(ctx, db, img)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("record render of page %d: %w", img.PageNumber, err)
+			}
+			final = append(final, img)
+			continue
+		}
+		final = append(final, *updated)
+	}
+
+	done(final, firstErr)
+}
+
+// setRenderState writes img's storage key, size, status, and render error
+// and returns the updated record.
+func setRenderState(ctx context.Context, q repository.Querier, img Image) (*Image, error) {
+	query := fmt.Sprintf(
+		`UPDATE %s SET storage_key = $2, size_bytes = $3, status = $4, render_error = $5 WHERE %s.id = $1 RETURNING %s`,
+		projection.Table(), projection.Alias(), projection.Columns(),
+	)
+
+	updated, err := repository.QueryOne(ctx, q, query, []any{img.ID, img.StorageKey, img.SizeBytes, img.Status, img.RenderError}, scanImage)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
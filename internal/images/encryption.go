@@ -0,0 +1,40 @@
+package images
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/JaimeStill/document-context/pkg/document"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+// OpenablePath returns a path document.Open can read for the document at
+// path. Documents that open without a password are returned unchanged, as
+// are open failures unrelated to encryption so render workers report them.
+// PDFs that require a password are decrypted with password into a temporary
+// file that release removes.
+// Returns an error wrapping ErrDocumentEncrypted if password is absent or wrong.
+func OpenablePath(path, contentType, password string) (resolved string, release func(), err error) {
+	release = func() {}
+
+	doc, err := document.Open(path, contentType)
+	if err == nil {
+		doc.Close()
+		return path, release, nil
+	}
+	if !errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return path, release, nil
+	}
+
+	decrypted, err := documents.DecryptPDF(path, password)
+	if err != nil {
+		if errors.Is(err, ErrDocumentEncrypted) {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
+	}
+
+	return decrypted, func() { os.Remove(decrypted) }, nil
+}
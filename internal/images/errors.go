@@ -6,20 +6,49 @@ package images
 import (
 	"errors"
 	"net/http"
+
+	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
 )
 
 // Domain errors for image operations.
 var (
-	ErrNotFound            = errors.New("image not found")
-	ErrDuplicate           = errors.New("image already exists")
-	ErrDocumentNotFound    = errors.New("document not found")
-	ErrUnsupportedFormat   = errors.New("document format is not supported for rendering")
-	ErrInvalidPageRange    = errors.New("invalid page range")
-	ErrPageOutOfRange      = errors.New("page number out of range")
-	ErrInvalidRenderOption = errors.New("invalid render option")
-	ErrRenderFailed        = errors.New("render failed")
+	ErrNotFound             = errors.New("image not found")
+	ErrDuplicate            = errors.New("image already exists")
+	ErrDocumentNotFound     = errors.New("document not found")
+	ErrUnsupportedFormat    = errors.New("document format is not supported for rendering")
+	ErrInvalidPageRange     = errors.New("invalid page range")
+	ErrPageOutOfRange       = errors.New("page number out of range")
+	ErrInvalidRenderOption  = errors.New("invalid render option")
+	ErrRenderFailed         = errors.New("render failed")
+	ErrRenderTimeout        = errors.New("page render timed out")
+	ErrInvalidThumbnailSize = errors.New("invalid thumbnail size")
+	ErrInvalidSignedURLTTL  = errors.New("invalid signed url ttl")
+	ErrSigningUnavailable   = errors.New("url signing is not configured")
+	ErrNotReady             = errors.New("image is not rendered")
+
+	// ErrDocumentEncrypted is documents.ErrEncrypted, returned when a render
+	// password is needed but absent or wrong.
+	ErrDocumentEncrypted = documents.ErrEncrypted
 )
 
+func init() {
+	handlers.RegisterErrorCode("not_found", ErrNotFound, ErrDocumentNotFound)
+	handlers.RegisterErrorCode("duplicate", ErrDuplicate)
+	handlers.RegisterErrorCode("unsupported_format", ErrUnsupportedFormat)
+	handlers.RegisterErrorCode("invalid_page_range", ErrInvalidPageRange)
+	handlers.RegisterErrorCode("page_out_of_range", ErrPageOutOfRange)
+	handlers.RegisterErrorCode("invalid_render_option", ErrInvalidRenderOption)
+	handlers.RegisterErrorCode("render_timeout", ErrRenderTimeout)
+	handlers.RegisterErrorCode("render_failed", ErrRenderFailed)
+	handlers.RegisterErrorCode("invalid_thumbnail_size", ErrInvalidThumbnailSize)
+	handlers.RegisterErrorCode("invalid_signed_url_ttl", ErrInvalidSignedURLTTL)
+	handlers.RegisterErrorCode("signing_unavailable", ErrSigningUnavailable)
+	handlers.RegisterErrorCode("not_ready", ErrNotReady)
+}
+
 // MapHTTPStatus maps domain errors to appropriate HTTP status codes.
 func MapHTTPStatus(err error) int {
 	switch {
@@ -27,6 +56,8 @@ func MapHTTPStatus(err error) int {
 		return http.StatusNotFound
 	case errors.Is(err, ErrDuplicate):
 		return http.StatusConflict
+	case errors.Is(err, ErrNotReady):
+		return http.StatusConflict
 	case errors.Is(err, ErrDocumentNotFound):
 		return http.StatusNotFound
 	case errors.Is(err, ErrUnsupportedFormat):
@@ -37,8 +68,24 @@ func MapHTTPStatus(err error) int {
 		return http.StatusBadRequest
 	case errors.Is(err, ErrInvalidRenderOption):
 		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidThumbnailSize):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidSignedURLTTL):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrSigningUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, handlers.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, storage.ErrQuotaExceeded):
+		return http.StatusInsufficientStorage
+	case errors.Is(err, ErrDocumentEncrypted):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrRenderTimeout):
+		return http.StatusUnprocessableEntity
 	case errors.Is(err, ErrRenderFailed):
 		return http.StatusInternalServerError
+	case errors.Is(err, query.ErrInvalidSort):
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
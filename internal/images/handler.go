@@ -1,14 +1,16 @@
 package images
 
 import (
-	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/agent-lab/pkg/routes"
+	"github.com/JaimeStill/document-context/pkg/document"
 	"github.com/google/uuid"
 )
 
@@ -38,24 +40,45 @@ func (h *Handler) Routes() routes.Group {
 			{Method: "GET", Pattern: "", Handler: h.List, OpenAPI: Spec.List},
 			{Method: "GET", Pattern: "/{id}", Handler: h.Find, OpenAPI: Spec.Find},
 			{Method: "GET", Pattern: "/{id}/data", Handler: h.Data, OpenAPI: Spec.Data},
+			{Method: "GET", Pattern: "/{id}/thumbnail", Handler: h.Thumbnail, OpenAPI: Spec.Thumbnail},
+			{Method: "POST", Pattern: "/{id}/signed-url", Handler: h.SignedURL, OpenAPI: Spec.SignedURL},
+			{Method: "PATCH", Pattern: "/{id}", Handler: h.Patch, OpenAPI: Spec.Patch},
 			{Method: "POST", Pattern: "/{documentId}/render", Handler: h.Render, OpenAPI: Spec.Render},
 			{Method: "DELETE", Pattern: "/{id}", Handler: h.Delete, OpenAPI: Spec.Delete},
 		},
 	}
 }
 
+// DocumentRoutes returns the route group for image operations scoped to a document.
+func (h *Handler) DocumentRoutes() routes.Group {
+	return routes.Group{
+		Prefix:      "/documents",
+		Tags:        []string{"Images"},
+		Description: "Document page image rendering and management",
+		Routes: []routes.Route{
+			{Method: "GET", Pattern: "/{id}/pages/{n}/image", Handler: h.PageImage, OpenAPI: Spec.PageImage},
+			{Method: "POST", Pattern: "/{id}/images/rerender", Handler: h.Rerender, OpenAPI: Spec.Rerender},
+		},
+	}
+}
+
 // List handles GET / - returns paginated images with optional filters.
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	page := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
 	filters := FiltersFromQuery(r.URL.Query())
 
 	result, err := h.sys.List(r.Context(), page, filters)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	pagination.Respond(w, r, result)
 }
 
 // Find handles GET /{id} - returns image metadata.
@@ -72,7 +95,7 @@ func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, img)
+	handlers.RespondFields(w, r, http.StatusOK, img)
 }
 
 // Data handles GET /{id}/data - returns raw image bytes.
@@ -95,7 +118,114 @@ func (h *Handler) Data(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
+// SignedURL handles POST /{id}/signed-url - returns an expiring signed link to
+// the image data. The optional ttl query parameter is a Go duration.
+func (h *Handler) SignedURL(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	ttl := DefaultSignedURLTTL
+	if s := r.URL.Query().Get("ttl"); s != "" {
+		ttl, err = time.ParseDuration(s)
+		if err != nil {
+			handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: %v", ErrInvalidSignedURLTTL, err))
+			return
+		}
+	}
+
+	signed, err := h.sys.SignedURL(r.Context(), id, ttl)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, signed)
+}
+
+// Thumbnail handles GET /{id}/thumbnail - returns a downscaled image.
+// The optional size query parameter sets the maximum dimension.
+func (h *Handler) Thumbnail(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	size := DefaultThumbnailSize
+	if s := r.URL.Query().Get("size"); s != "" {
+		size, err = strconv.Atoi(s)
+		if err != nil {
+			handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: size must be an integer", ErrInvalidThumbnailSize))
+			return
+		}
+	}
+
+	if err := ValidateThumbnailSize(size); err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	data, contentType, err := h.sys.Thumbnail(r.Context(), id, size)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// PageImage handles GET /documents/{id}/pages/{n}/image - returns the raw
+// bytes of one page, rendering it on first request. The optional dpi and
+// format query parameters select the render.
+func (h *Handler) PageImage(w http.ResponseWriter, r *http.Request) {
+	documentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	pageNum, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: page must be an integer", ErrInvalidPageRange))
+		return
+	}
+
+	q := r.URL.Query()
+	opts := RenderOptions{Format: document.ImageFormat(q.Get("format"))}
+	if s := q.Get("dpi"); s != "" {
+		opts.DPI, err = strconv.Atoi(s)
+		if err != nil {
+			handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: dpi must be an integer", ErrInvalidRenderOption))
+			return
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	data, contentType, err := h.sys.PageImage(r.Context(), documentID, pageNum, opts)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 // Render handles POST /{documentId}/render - renders document pages to images.
+// With async=true the images are returned pending with status 202 and
+// rendered in the background.
 func (h *Handler) Render(w http.ResponseWriter, r *http.Request) {
 	documentID, err := uuid.Parse(r.PathValue("documentId"))
 	if err != nil {
@@ -103,9 +233,17 @@ func (h *Handler) Render(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var async bool
+	if v := r.URL.Query().Get("async"); v != "" {
+		if async, err = strconv.ParseBool(v); err != nil {
+			handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("%w: async must be a boolean", ErrInvalidRenderOption))
+			return
+		}
+	}
+
 	var opts RenderOptions
-	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &opts, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -114,6 +252,17 @@ func (h *Handler) Render(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if async {
+		images, err := h.sys.RenderAsync(r.Context(), documentID, opts)
+		if err != nil {
+			handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+			return
+		}
+
+		handlers.RespondJSON(w, http.StatusAccepted, images)
+		return
+	}
+
 	images, err := h.sys.Render(r.Context(), documentID, opts)
 	if err != nil {
 		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
@@ -123,6 +272,58 @@ func (h *Handler) Render(w http.ResponseWriter, r *http.Request) {
 	handlers.RespondJSON(w, http.StatusCreated, images)
 }
 
+// Rerender handles POST /documents/{id}/images/rerender - replaces all of a
+// document's images with a fresh render at the requested options.
+func (h *Handler) Rerender(w http.ResponseWriter, r *http.Request) {
+	documentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var opts RenderOptions
+	if err := handlers.DecodeJSON(w, r, &opts, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	if err := opts.Validate(); err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	images, err := h.sys.Rerender(r.Context(), documentID, opts)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusCreated, images)
+}
+
+// Patch handles PATCH /{id} - updates the provided image metadata fields.
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var cmd PatchImageCommand
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	img, err := h.sys.Patch(r.Context(), id, cmd)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, img)
+}
+
 // Delete handles DELETE /{id} - deletes an image.
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
@@ -3,28 +3,122 @@ package images
 import (
 	"fmt"
 	"time"
+	"unicode/utf8"
 
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/document-context/pkg/config"
 	"github.com/JaimeStill/document-context/pkg/document"
 	"github.com/google/uuid"
 )
 
+// Domain event types published to the event bus.
+// EventRendered carries the []Image returned by Render, or the final records
+// of an async render once it finishes, with the document ID as subject;
+// EventUpdated and EventDeleted carry the patched or deleted Image.
+const (
+	EventRendered = "images.rendered"
+	EventUpdated  = "image.updated"
+	EventDeleted  = "image.deleted"
+)
+
+// RenderStatus is the render state of an image. Synchronous renders record
+// images as ready; async renders record them as pending and move them to
+// ready or failed once the background render finishes.
+type RenderStatus string
+
+const (
+	StatusPending RenderStatus = "pending"
+	StatusReady   RenderStatus = "ready"
+	StatusFailed  RenderStatus = "failed"
+)
+
 // Image represents a rendered document page stored in the system.
+// The binary and its render parameters are immutable; only the analyst
+// metadata in Label and Notes can change after rendering. Data is only
+// available once Status is ready; RenderError holds the reason a failed
+// render did not complete.
 type Image struct {
-	ID         uuid.UUID            `json:"id"`
-	DocumentID uuid.UUID            `json:"document_id"`
-	PageNumber int                  `json:"page_number"`
-	Format     document.ImageFormat `json:"format"`
-	DPI        int                  `json:"dpi"`
-	Quality    *int                 `json:"quality,omitempty"`
-	Brightness *int                 `json:"brightness,omitempty"`
-	Contrast   *int                 `json:"contrast,omitempty"`
-	Saturation *int                 `json:"saturation,omitempty"`
-	Rotation   *int                 `json:"rotation,omitempty"`
-	Background *string              `json:"background,omitempty"`
-	StorageKey string               `json:"storage_key"`
-	SizeBytes  int64                `json:"size_bytes"`
-	CreatedAt  time.Time            `json:"created_at"`
+	ID          uuid.UUID            `json:"id"`
+	DocumentID  uuid.UUID            `json:"document_id"`
+	PageNumber  int                  `json:"page_number"`
+	Format      document.ImageFormat `json:"format"`
+	DPI         int                  `json:"dpi"`
+	Quality     *int                 `json:"quality,omitempty"`
+	Brightness  *int                 `json:"brightness,omitempty"`
+	Contrast    *int                 `json:"contrast,omitempty"`
+	Saturation  *int                 `json:"saturation,omitempty"`
+	Rotation    *int                 `json:"rotation,omitempty"`
+	Background  *string              `json:"background,omitempty"`
+	Grayscale   *bool                `json:"grayscale,omitempty"`
+	Threshold   *int                 `json:"threshold,omitempty"`
+	StorageKey  string               `json:"storage_key"`
+	SizeBytes   int64                `json:"size_bytes"`
+	Status      RenderStatus         `json:"status"`
+	RenderError *string              `json:"render_error,omitempty"`
+	Label       *string              `json:"label,omitempty"`
+	Notes       *string              `json:"notes,omitempty"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+// Signed URL lifetimes. DefaultSignedURLTTL applies when no ttl is requested.
+const (
+	DefaultSignedURLTTL = time.Hour
+	MaxSignedURLTTL     = 7 * 24 * time.Hour
+)
+
+// SignedURL is an expiring link to an image's data that requires no
+// authentication while it is valid.
+type SignedURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ValidateSignedURLTTL checks that ttl is positive and within MaxSignedURLTTL.
+func ValidateSignedURLTTL(ttl time.Duration) error {
+	if ttl <= 0 || ttl > MaxSignedURLTTL {
+		return fmt.Errorf("%w: ttl must be greater than 0 and at most %s", ErrInvalidSignedURLTTL, MaxSignedURLTTL)
+	}
+	return nil
+}
+
+// maxLabelLength bounds the length of an image label in characters.
+const maxLabelLength = 200
+
+// PatchImageCommand contains a partial update to image metadata.
+// Only non-nil fields are applied. Format and DPI describe the rendered
+// binary and cannot change; they are accepted only so Validate can reject
+// them by name.
+type PatchImageCommand struct {
+	Label *string `json:"label,omitempty"`
+	Notes *string `json:"notes,omitempty"`
+
+	Format *string `json:"format,omitempty"`
+	DPI    *int    `json:"dpi,omitempty"`
+}
+
+// Validate checks each set field of the command and returns a
+// *handlers.ValidationError listing every invalid field, or nil.
+func (c PatchImageCommand) Validate() error {
+	var v handlers.ValidationError
+
+	if c.Label != nil && utf8.RuneCountInString(*c.Label) > maxLabelLength {
+		v.Add("label", fmt.Sprintf("label cannot exceed %d characters", maxLabelLength))
+	}
+	if c.Format != nil {
+		v.Add("format", "format is immutable; re-render the document to change it")
+	}
+	if c.DPI != nil {
+		v.Add("dpi", "dpi is immutable; re-render the document to change it")
+	}
+
+	return v.Err()
+}
+
+// imageMetadata is the subset of PatchImageCommand written to the database,
+// so render parameters can never reach the UPDATE statement.
+type imageMetadata struct {
+	Label *string
+	Notes *string
 }
 
 // RenderOptions specifies parameters for rendering document pages to images.
@@ -38,7 +132,18 @@ type RenderOptions struct {
 	Saturation *int                 `json:"saturation,omitempty"`
 	Rotation   *int                 `json:"rotation,omitempty"`
 	Background *string              `json:"background,omitempty"`
+	Grayscale  *bool                `json:"grayscale,omitempty"`
+	Threshold  *int                 `json:"threshold,omitempty"`
 	Force      bool                 `json:"force"`
+
+	// Password opens encrypted PDFs. It applies only to this render and is
+	// never stored on the resulting images or logged.
+	Password string `json:"password,omitempty"`
+
+	// Server-side render limits, populated by WithLimits rather than the request.
+	PageTimeout time.Duration `json:"-"`
+	MemoryLimit string        `json:"-"`
+	AreaLimit   string        `json:"-"`
 }
 
 // Validate validates and applies defaults to render options.
@@ -77,6 +182,10 @@ func (o *RenderOptions) Validate() error {
 		return fmt.Errorf("%w: rotation must be between 0 and 360", ErrInvalidRenderOption)
 	}
 
+	if o.Threshold != nil && (*o.Threshold < 0 || *o.Threshold > 100) {
+		return fmt.Errorf("%w: threshold must be between 0 and 100", ErrInvalidRenderOption)
+	}
+
 	if o.Background == nil {
 		bg := "white"
 		o.Background = &bg
@@ -104,8 +213,11 @@ func (o RenderOptions) ToImage(
 		Saturation: o.Saturation,
 		Rotation:   o.Rotation,
 		Background: o.Background,
+		Grayscale:  o.Grayscale,
+		Threshold:  o.Threshold,
 		StorageKey: storageKey,
 		SizeBytes:  sizeBytes,
+		Status:     StatusReady,
 	}
 }
 
@@ -138,6 +250,18 @@ func (o RenderOptions) ToImageConfig() config.ImageConfig {
 	if o.Background != nil {
 		cfg.Options["background"] = *o.Background
 	}
+	if o.Grayscale != nil {
+		cfg.Options["grayscale"] = *o.Grayscale
+	}
+	if o.Threshold != nil {
+		cfg.Options["threshold"] = *o.Threshold
+	}
+	if o.MemoryLimit != "" {
+		cfg.Options["memory_limit"] = o.MemoryLimit
+	}
+	if o.AreaLimit != "" {
+		cfg.Options["area_limit"] = o.AreaLimit
+	}
 
 	return cfg
 }
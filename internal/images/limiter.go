@@ -0,0 +1,45 @@
+package images
+
+import (
+	"context"
+	"runtime"
+)
+
+// RenderLimiter bounds how many ImageMagick invocations run at once across
+// all requests. Per-request worker pools still apply; the limiter caps their
+// combined concurrency so simultaneous requests cannot oversubscribe the
+// host.
+type RenderLimiter struct {
+	slots chan struct{}
+}
+
+// NewRenderLimiter creates a limiter admitting up to size concurrent
+// renders. A size of 0 or less uses the number of CPUs.
+func NewRenderLimiter(size int) *RenderLimiter {
+	if size <= 0 {
+		size = runtime.NumCPU()
+	}
+	return &RenderLimiter{slots: make(chan struct{}, size)}
+}
+
+// Acquire blocks until a render slot is free or ctx is done, returning the
+// context error in the latter case. Each successful Acquire must be paired
+// with a Release.
+func (l *RenderLimiter) Acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (l *RenderLimiter) Release() {
+	<-l.slots
+}
+
+// Size returns the maximum number of concurrent renders.
+func (l *RenderLimiter) Size() int {
+	return cap(l.slots)
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/agent-lab/pkg/repository"
@@ -18,17 +19,28 @@ var projection = query.NewProjectionMap("public", "images", "i").
 	Project("id", "ID").
 	Project("document_id", "DocumentID").
 	Project("page_number", "PageNumber").
-	Project("format", "Format").
+	ProjectText("format", "Format").
 	Project("dpi", "DPI").
 	Project("quality", "Quality").
 	Project("brightness", "Brightness").
 	Project("contrast", "Contrast").
 	Project("saturation", "Saturation").
 	Project("rotation", "Rotation").
-	Project("background", "Background").
-	Project("storage_key", "StorageKey").
+	ProjectText("background", "Background").
+	Project("grayscale", "Grayscale").
+	Project("threshold", "Threshold").
+	ProjectText("storage_key", "StorageKey").
 	Project("size_bytes", "SizeBytes").
-	Project("created_at", "CreatedAt")
+	ProjectText("status", "Status").
+	ProjectText("render_error", "RenderError").
+	ProjectText("label", "Label").
+	ProjectText("notes", "Notes").
+	Project("created_at", "CreatedAt").
+	Sortable(
+		"ID", "DocumentID", "PageNumber", "Format", "DPI", "Quality",
+		"Brightness", "Contrast", "Saturation", "Rotation", "Background",
+		"Grayscale", "Threshold", "SizeBytes", "Status", "Label", "CreatedAt",
+	)
 
 // defaultSort orders images by creation time, newest first.
 var defaultSort = query.SortField{Field: "CreatedAt", Descending: true}
@@ -48,43 +60,68 @@ func scanImage(s repository.Scanner) (Image, error) {
 		&img.Saturation,
 		&img.Rotation,
 		&img.Background,
+		&img.Grayscale,
+		&img.Threshold,
 		&img.StorageKey,
 		&img.SizeBytes,
+		&img.Status,
+		&img.RenderError,
+		&img.Label,
+		&img.Notes,
 		&img.CreatedAt,
 	)
 	return img, err
 }
 
 // Filters defines optional criteria for querying images.
+// Size bounds are inclusive byte counts; CreatedAfter and CreatedBefore
+// are exclusive bounds on the creation timestamp.
 type Filters struct {
-	DocumentID *uuid.UUID
-	Format     *document.ImageFormat
-	PageNumber *int
+	DocumentID    *uuid.UUID
+	Format        *document.ImageFormat
+	PageNumber    *int
+	MinSize       *int64
+	MaxSize       *int64
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
 }
 
+// filterSpec declares the query parameters that populate Filters.
+var filterSpec = query.NewFilterSpec(
+	query.UUIDFilter("document_id", func(f *Filters, v uuid.UUID) { f.DocumentID = &v }),
+	query.EnumFilter("format", document.ParseImageFormat, func(f *Filters, v document.ImageFormat) { f.Format = &v }),
+	query.IntFilter("page_number", func(f *Filters, v int) { f.PageNumber = &v }),
+	query.Filter("min_size", parseSize, func(f *Filters, v int64) { f.MinSize = &v }),
+	query.Filter("max_size", parseSize, func(f *Filters, v int64) { f.MaxSize = &v }),
+	query.Filter("created_after", parseTime, func(f *Filters, v time.Time) { f.CreatedAfter = &v }),
+	query.Filter("created_before", parseTime, func(f *Filters, v time.Time) { f.CreatedBefore = &v }),
+)
+
 // FiltersFromQuery extracts image filters from URL query parameters.
+// Invalid values are ignored.
 func FiltersFromQuery(values url.Values) Filters {
-	var f Filters
+	return filterSpec.Parse(values)
+}
 
-	if docID := values.Get("document_id"); docID != "" {
-		if parsed, err := uuid.Parse(docID); err == nil {
-			f.DocumentID = &parsed
-		}
+// parseSize parses a non-negative byte count.
+func parseSize(value string) (int64, error) {
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
 	}
-
-	if format := values.Get("format"); format != "" {
-		if parsed, err := document.ParseImageFormat(format); err == nil {
-			f.Format = &parsed
-		}
+	if size < 0 {
+		return 0, fmt.Errorf("size must not be negative, got %d", size)
 	}
+	return size, nil
+}
 
-	if pg := values.Get("page_number"); pg != "" {
-		if page, err := strconv.Atoi(pg); err == nil {
-			f.PageNumber = &page
-		}
+// parseTime parses an RFC 3339 timestamp or a YYYY-MM-DD date (midnight UTC).
+func parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
 	}
-
-	return f
+	return time.Parse(time.DateOnly, value)
 }
 
 // Apply adds filter conditions to a query builder.
@@ -101,6 +138,22 @@ func (f Filters) Apply(b *query.Builder) *query.Builder {
 		b.WhereEquals("PageNumber", *f.PageNumber)
 	}
 
+	if f.MinSize != nil {
+		b.WhereGreaterOrEqual("SizeBytes", *f.MinSize)
+	}
+
+	if f.MaxSize != nil {
+		b.WhereLessOrEqual("SizeBytes", *f.MaxSize)
+	}
+
+	if f.CreatedAfter != nil {
+		b.WhereGreaterThan("CreatedAt", *f.CreatedAfter)
+	}
+
+	if f.CreatedBefore != nil {
+		b.WhereLessThan("CreatedAt", *f.CreatedBefore)
+	}
+
 	return b
 }
 
@@ -4,11 +4,16 @@ import "github.com/JaimeStill/agent-lab/pkg/openapi"
 
 // spec defines OpenAPI operations for image endpoints.
 type spec struct {
-	List   *openapi.Operation
-	Find   *openapi.Operation
-	Data   *openapi.Operation
-	Render *openapi.Operation
-	Delete *openapi.Operation
+	List      *openapi.Operation
+	Find      *openapi.Operation
+	Data      *openapi.Operation
+	Thumbnail *openapi.Operation
+	SignedURL *openapi.Operation
+	PageImage *openapi.Operation
+	Render    *openapi.Operation
+	Rerender  *openapi.Operation
+	Patch     *openapi.Operation
+	Delete    *openapi.Operation
 }
 
 // Spec provides OpenAPI specifications for all image endpoints.
@@ -22,9 +27,14 @@ var Spec = spec{
 			openapi.QueryParam("page_size", "integer", "Items per page", false),
 			openapi.QueryParam("format", "string", "Filter by format (png or jpg)", false),
 			openapi.QueryParam("page_number", "integer", "Filter by page number", false),
+			openapi.QueryParam("min_size", "integer", "Minimum image size in bytes (inclusive)", false),
+			openapi.QueryParam("max_size", "integer", "Maximum image size in bytes (inclusive)", false),
+			openapi.QueryParam("created_after", "string", "Only images created after this time (RFC 3339 or YYYY-MM-DD)", false),
+			openapi.QueryParam("created_before", "string", "Only images created before this time (RFC 3339 or YYYY-MM-DD)", false),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Image list", "ImagePageResult"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Image list", "ImagePageResult"), openapi.PageHeaders(true)),
 		},
 	},
 	Find: &openapi.Operation{
@@ -32,6 +42,7 @@ var Spec = spec{
 		Description: "Find metadata for a rendered image",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Image ID"),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
 			200: openapi.ResponseJSON("Image metadata", "Image"),
@@ -40,9 +51,11 @@ var Spec = spec{
 	},
 	Data: &openapi.Operation{
 		Summary:     "Get image binary",
-		Description: "Get the raw binary data for a rendered image",
+		Description: "Get the raw binary data for a rendered image. A signed URL from the signed-url endpoint adds exp and sig parameters that authorize the request on their own.",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Image ID"),
+			openapi.QueryParam("exp", "integer", "Signed URL expiry (Unix seconds)", false),
+			openapi.QueryParam("sig", "string", "Signed URL signature", false),
 		},
 		Responses: map[int]*openapi.Response{
 			200: {
@@ -52,21 +65,121 @@ var Spec = spec{
 					"image/jpeg": {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
 				},
 			},
+			403: {Description: "Signed URL signature is invalid or expired"},
 			404: openapi.ResponseRef("NotFound"),
+			409: {Description: "Image is pending or its render failed"},
+		},
+	},
+	Thumbnail: &openapi.Operation{
+		Summary:     "Get image thumbnail",
+		Description: "Get a downscaled copy of a rendered image. Thumbnails are generated on first request and cached per size.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Image ID"),
+			openapi.QueryParam("size", "integer", "Maximum thumbnail dimension in pixels (16-1024, default 256)", false),
+		},
+		Responses: map[int]*openapi.Response{
+			200: {
+				Description: "Thumbnail binary data",
+				Content: map[string]*openapi.MediaType{
+					"image/png":  {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+					"image/jpeg": {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+				},
+			},
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			409: {Description: "Image is pending or its render failed"},
+			500: {Description: "Thumbnail generation failed"},
+			507: {Description: "Storage quota exceeded"},
+		},
+	},
+	SignedURL: &openapi.Operation{
+		Summary:     "Create signed image URL",
+		Description: "Create an HMAC-signed link to the image data that can be fetched without authentication until it expires. Tampered or expired links are rejected with 403.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Image ID"),
+			openapi.QueryParam("ttl", "string", "Link lifetime as a duration (e.g. 30m, 24h; default 1h, max 168h)", false),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Signed URL", "SignedURL"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
+	PageImage: &openapi.Operation{
+		Summary:     "Get document page image",
+		Description: "Get the image binary for one document page, rendering it on first request. An existing image with the same options is reused, and concurrent requests for the same page share one render.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Document ID"),
+			{
+				Name:        "n",
+				In:          "path",
+				Required:    true,
+				Description: "Page number (1-indexed)",
+				Schema:      &openapi.Schema{Type: "integer"},
+			},
+			openapi.QueryParam("dpi", "integer", "Resolution in DPI (72-1200, default 300)", false),
+			openapi.QueryParam("format", "string", "Output format (png or jpg, default png)", false),
+		},
+		Responses: map[int]*openapi.Response{
+			200: {
+				Description: "Page image binary data",
+				Content: map[string]*openapi.MediaType{
+					"image/png":  {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+					"image/jpeg": {Schema: &openapi.Schema{Type: "string", Format: "binary"}},
+				},
+			},
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			422: {Description: "The page exceeded the render timeout, or the document is encrypted"},
+			500: {Description: "Render failed"},
+			507: {Description: "Storage quota exceeded"},
 		},
 	},
 	Render: &openapi.Operation{
 		Summary:     "Render document pages",
-		Description: "Render document pages to images. Supports batch rendering with page range expressions (e.g., '1-5,10,15-20'). Currently supports PDF files.",
+		Description: "Render document pages to images. Supports batch rendering with page range expressions (e.g., '1-5,10,15-20'). Currently supports PDF files. With async=true the images are returned immediately in the pending status and rendered in the background; poll each image or listen for the images.rendered event to see them become ready or failed.",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("documentId", "Document ID"),
+			openapi.QueryParam("async", "boolean", "Queue the render and return pending images without waiting (default false)", false),
 		},
 		RequestBody: openapi.RequestBodyJSON("RenderRequest", false),
 		Responses: map[int]*openapi.Response{
 			201: openapi.ResponseJSON("Images rendered", "ImageArray"),
+			202: openapi.ResponseJSON("Images queued for rendering", "ImageArray"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			422: {Description: "A page exceeded the render timeout, or the document is encrypted and the password is absent or wrong"},
+			500: {Description: "Render failed"},
+			507: {Description: "Storage quota exceeded"},
+		},
+	},
+	Rerender: &openapi.Operation{
+		Summary:     "Re-render document images",
+		Description: "Replace all of a document's images with a fresh render of every page at the given options. The pages and force fields are ignored. Existing images are removed only after all pages render successfully.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Document ID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("RenderRequest", false),
+		Responses: map[int]*openapi.Response{
+			201: openapi.ResponseJSON("Images re-rendered", "ImageArray"),
 			400: openapi.ResponseRef("BadRequest"),
 			404: openapi.ResponseRef("NotFound"),
+			422: {Description: "A page exceeded the render timeout, or the document is encrypted and the password is absent or wrong"},
 			500: {Description: "Render failed"},
+			507: {Description: "Storage quota exceeded"},
+		},
+	},
+	Patch: &openapi.Operation{
+		Summary:     "Patch image metadata",
+		Description: "Update only the image metadata fields present in the request body. The binary and its render parameters are immutable; requests that set format or dpi are rejected.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Image ID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("PatchImageCommand", true),
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Updated image metadata", "Image"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
 		},
 	},
 	Delete: &openapi.Operation{
@@ -88,20 +201,26 @@ func (spec) Schemas() map[string]*openapi.Schema {
 		"Image": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
-				"id":          {Type: "string", Format: "uuid"},
-				"document_id": {Type: "string", Format: "uuid"},
-				"page_number": {Type: "integer", Description: "Page number (1-indexed)"},
-				"format":      {Type: "string", Description: "Image format (png or jpg)"},
-				"dpi":         {Type: "integer", Description: "Resolution in DPI"},
-				"quality":     {Type: "integer", Description: "JPEG quality (1-100)"},
-				"brightness":  {Type: "integer", Description: "Brightness adjustment (0-200)"},
-				"contrast":    {Type: "integer", Description: "Contrast adjustment (-100 to 100)"},
-				"saturation":  {Type: "integer", Description: "Saturation adjustment (0-200)"},
-				"rotation":    {Type: "integer", Description: "Rotation in degrees (0-360)"},
-				"background":  {Type: "string", Description: "Background color name"},
-				"storage_key": {Type: "string", Description: "Storage location key"},
-				"size_bytes":  {Type: "integer", Format: "int64", Description: "File size in bytes"},
-				"created_at":  {Type: "string", Format: "date-time"},
+				"id":           {Type: "string", Format: "uuid"},
+				"document_id":  {Type: "string", Format: "uuid"},
+				"page_number":  {Type: "integer", Description: "Page number (1-indexed)"},
+				"format":       {Type: "string", Description: "Image format (png or jpg)"},
+				"dpi":          {Type: "integer", Description: "Resolution in DPI"},
+				"quality":      {Type: "integer", Description: "JPEG quality (1-100)"},
+				"brightness":   {Type: "integer", Description: "Brightness adjustment (0-200)"},
+				"contrast":     {Type: "integer", Description: "Contrast adjustment (-100 to 100)"},
+				"saturation":   {Type: "integer", Description: "Saturation adjustment (0-200)"},
+				"rotation":     {Type: "integer", Description: "Rotation in degrees (0-360)"},
+				"background":   {Type: "string", Description: "Background color name"},
+				"grayscale":    {Type: "boolean", Description: "Whether the page was rendered in grayscale"},
+				"threshold":    {Type: "integer", Description: "Binarization threshold percentage (0-100)"},
+				"storage_key":  {Type: "string", Description: "Storage location key"},
+				"size_bytes":   {Type: "integer", Format: "int64", Description: "File size in bytes"},
+				"status":       {Type: "string", Enum: []any{"pending", "ready", "failed"}, Description: "Render state; data is available only when ready"},
+				"render_error": {Type: "string", Description: "Why the render failed, when status is failed"},
+				"label":        {Type: "string", Description: "Optional analyst label"},
+				"notes":        {Type: "string", Description: "Optional analyst notes"},
+				"created_at":   {Type: "string", Format: "date-time"},
 			},
 		},
 		"ImageArray": {
@@ -118,6 +237,21 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"total_pages": {Type: "integer"},
 			},
 		},
+		"SignedURL": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"url":        {Type: "string", Description: "Signed path to the image data, relative to the server"},
+				"expires_at": {Type: "string", Format: "date-time"},
+			},
+		},
+		"PatchImageCommand": {
+			Type:        "object",
+			Description: "Partial update of image metadata. Omitted fields are left unchanged.",
+			Properties: map[string]*openapi.Schema{
+				"label": {Type: "string", Description: "Analyst label (max 200 characters)"},
+				"notes": {Type: "string", Description: "Analyst notes"},
+			},
+		},
 		"RenderRequest": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
@@ -130,7 +264,10 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"saturation": {Type: "integer", Description: "Saturation adjustment (0-200, 100 is neutral)", Minimum: floatPtr(0), Maximum: floatPtr(200), Default: 100},
 				"rotation":   {Type: "integer", Description: "Rotation in degrees (0-360)", Minimum: floatPtr(0), Maximum: floatPtr(360), Default: 0},
 				"background": {Type: "string", Description: "Background color name", Default: "white"},
+				"grayscale":  {Type: "boolean", Description: "Render in grayscale"},
+				"threshold":  {Type: "integer", Description: "Binarization threshold percentage (0-100)", Minimum: floatPtr(0), Maximum: floatPtr(100)},
 				"force":      {Type: "boolean", Description: "Re-render even if matching image exists", Default: false},
+				"password":   {Type: "string", Format: "password", Description: "Password for encrypted PDFs. Used only for this render and never stored"},
 			},
 		},
 	}
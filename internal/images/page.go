@@ -0,0 +1,71 @@
+package images
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+// PageImageKey returns the key identifying a rendered page: the document,
+// page number, and every render option that findExisting deduplicates on.
+func PageImageKey(documentID uuid.UUID, pageNum int, opts RenderOptions) string {
+	return fmt.Sprintf(
+		"%s/%d/%s/%d/%s/%s/%s/%s/%s/%s/%s/%s",
+		documentID, pageNum, opts.Format, opts.DPI,
+		optionKey(opts.Quality),
+		optionKey(opts.Brightness),
+		optionKey(opts.Contrast),
+		optionKey(opts.Saturation),
+		optionKey(opts.Rotation),
+		optionKey(opts.Background),
+		optionKey(opts.Grayscale),
+		optionKey(opts.Threshold),
+	)
+}
+
+func optionKey[T any](v *T) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprint(*v)
+}
+
+// PageLoader resolves single page images on demand. Concurrent loads of the
+// same key share one call, so a page is never rendered twice at once.
+type PageLoader struct {
+	group singleflight.Group
+}
+
+// Load returns the image for key. find is tried first and render is called
+// only when find reports no image. Both run once per key no matter how many
+// callers are waiting; they run detached from the caller's cancellation so
+// one caller leaving does not fail the others, while each caller still
+// returns as soon as its own ctx is done.
+func (l *PageLoader) Load(
+	ctx context.Context,
+	key string,
+	find func(context.Context) (*Image, error),
+	render func(context.Context) (*Image, error),
+) (*Image, error) {
+	ch := l.group.DoChan(key, func() (any, error) {
+		loadCtx := context.WithoutCancel(ctx)
+
+		img, err := find(loadCtx)
+		if err != nil || img != nil {
+			return img, err
+		}
+		return render(loadCtx)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*Image), nil
+	}
+}
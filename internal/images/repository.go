@@ -9,11 +9,15 @@ import (
 	"net/http"
 	"runtime"
 	"sync"
+	"time"
 
+	"github.com/JaimeStill/agent-lab/internal/config"
 	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/JaimeStill/agent-lab/pkg/events"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/agent-lab/pkg/repository"
+	"github.com/JaimeStill/agent-lab/pkg/signedurl"
 	"github.com/JaimeStill/agent-lab/pkg/storage"
 	"github.com/JaimeStill/document-context/pkg/document"
 	"github.com/JaimeStill/document-context/pkg/image"
@@ -26,12 +30,21 @@ type renderTask struct {
 	err     error
 }
 
+// pageRenderFunc renders a single page of an open document.
+type pageRenderFunc func(ctx context.Context, doc document.Document, renderer image.Renderer, pageNum int) (*Image, error)
+
 type repo struct {
 	db         *sql.DB
 	documents  documents.System
 	storage    storage.System
+	events     *events.Bus
 	logger     *slog.Logger
 	pagination pagination.Config
+	render     config.RenderConfig
+	limiter    *RenderLimiter
+	scale      Scaler
+	pages      PageLoader
+	signer     *signedurl.Signer
 }
 
 // New creates a new image management system.
@@ -39,15 +52,23 @@ func New(
 	docs documents.System,
 	db *sql.DB,
 	storage storage.System,
+	bus *events.Bus,
 	logger *slog.Logger,
 	pagination pagination.Config,
+	render config.RenderConfig,
+	signer *signedurl.Signer,
 ) System {
 	return &repo{
 		db:         db,
 		documents:  docs,
 		storage:    storage,
+		events:     bus,
 		logger:     logger.With("system", "images"),
 		pagination: pagination,
+		render:     render,
+		limiter:    NewRenderLimiter(render.MaxConcurrent),
+		scale:      magickScale,
+		signer:     signer,
 	}
 }
 
@@ -56,29 +77,15 @@ func (r *repo) Handler() *Handler {
 }
 
 func (r *repo) List(ctx context.Context, page pagination.PageRequest, filters Filters) (*pagination.PageResult[Image], error) {
-	page.Normalize(r.pagination)
-
 	qb := query.NewBuilder(projection, defaultSort)
 	filters.Apply(qb)
 
-	if len(page.Sort) > 0 {
-		qb.OrderByFields(page.Sort)
-	}
-
-	countSQL, countArgs := qb.BuildCount()
-	var total int
-	if err := r.db.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); err != nil {
-		return nil, fmt.Errorf("count images: %w", err)
-	}
-
-	pageSQL, pageArgs := qb.BuildPage(page.Page, page.PageSize)
-	imgs, err := repository.QueryMany(ctx, r.db, pageSQL, pageArgs, scanImage)
+	result, err := repository.Paginate(ctx, r.db, qb, page, r.pagination, scanImage)
 	if err != nil {
-		return nil, fmt.Errorf("query images: %w", err)
+		return nil, fmt.Errorf("list images: %w", err)
 	}
 
-	result := pagination.NewPageResult(imgs, total, page.Page, page.PageSize)
-	return &result, nil
+	return result, nil
 }
 
 func (r *repo) Find(ctx context.Context, id uuid.UUID) (*Image, error) {
@@ -90,8 +97,113 @@ func (r *repo) Find(ctx context.Context, id uuid.UUID) (*Image, error) {
 	return &img, nil
 }
 
+func (r *repo) Patch(ctx context.Context, id uuid.UUID, cmd PatchImageCommand) (*Image, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	img, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Image, error) {
+		meta := imageMetadata{Label: cmd.Label, Notes: cmd.Notes}
+		return repository.UpdatePartial(ctx, tx, projection, "ID", id, meta, scanImage)
+	})
+
+	if err != nil {
+		return nil, repository.MapError(err, ErrNotFound, ErrDuplicate)
+	}
+
+	r.logger.Info("image patched", "id", img.ID)
+	r.events.Publish(ctx, events.Event{Type: EventUpdated, Subject: img.ID.String(), Data: img})
+	return &img, nil
+}
+
 func (r *repo) Data(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
-	img, err := r.Find(ctx, id)
+	img, err := r.findReady(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := r.storage.Retrieve(ctx, img.StorageKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("retrieve image: %w", err)
+	}
+
+	contentType, err := img.Format.MimeType()
+	if err != nil {
+		contentType = http.DetectContentType(data)
+	}
+
+	return data, contentType, nil
+}
+
+func (r *repo) SignedURL(ctx context.Context, id uuid.UUID, ttl time.Duration) (*SignedURL, error) {
+	if err := ValidateSignedURLTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	if r.signer == nil {
+		return nil, ErrSigningUnavailable
+	}
+
+	if _, err := r.Find(ctx, id); err != nil {
+		return nil, err
+	}
+
+	url, expires := r.signer.Sign(fmt.Sprintf("/images/%s/data", id), ttl)
+	return &SignedURL{URL: url, ExpiresAt: expires}, nil
+}
+
+func (r *repo) Thumbnail(ctx context.Context, id uuid.UUID, maxDim int) ([]byte, string, error) {
+	img, err := r.findReady(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := LoadThumbnail(ctx, r.storage, *img, maxDim, r.limitedScale)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType, err := img.Format.MimeType()
+	if err != nil {
+		contentType = http.DetectContentType(data)
+	}
+
+	return data, contentType, nil
+}
+
+func (r *repo) PageImage(ctx context.Context, documentID uuid.UUID, pageNum int, opts RenderOptions) ([]byte, string, error) {
+	doc, err := r.renderableDocument(ctx, documentID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if pageNum < 1 || pageNum > *doc.PageCount {
+		return nil, "", fmt.Errorf("%w: page %d out of range [1-%d]", ErrPageOutOfRange, pageNum, *doc.PageCount)
+	}
+
+	opts.Pages = ""
+	opts.Force = false
+
+	img, err := r.pages.Load(ctx, PageImageKey(documentID, pageNum, opts),
+		func(ctx context.Context) (*Image, error) {
+			img, err := r.findExisting(ctx, documentID, pageNum, opts)
+			if err != nil || img == nil || img.Status == StatusReady {
+				return img, err
+			}
+			return nil, nil
+		},
+		func(ctx context.Context) (*Image, error) {
+			images, err := r.renderPages(ctx, doc, opts, []int{pageNum}, func(pageCtx context.Context, openDoc document.Document, renderer image.Renderer, pageNum int) (*Image, error) {
+				return r.renderPage(pageCtx, documentID, openDoc, renderer, pageNum, opts)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			r.events.Publish(ctx, events.Event{Type: EventRendered, Subject: documentID.String(), Data: images})
+			return &images[0], nil
+		},
+	)
 	if err != nil {
 		return nil, "", err
 	}
@@ -110,42 +222,212 @@ func (r *repo) Data(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
 }
 
 func (r *repo) Render(ctx context.Context, documentID uuid.UUID, opts RenderOptions) ([]Image, error) {
-	doc, err := r.documents.Find(ctx, documentID)
+	doc, err := r.renderableDocument(ctx, documentID)
 	if err != nil {
 		return nil, err
 	}
 
-	if !document.IsSupported(doc.ContentType) {
-		return nil, ErrUnsupportedFormat
+	pages, err := renderRange(doc, opts.Pages)
+	if err != nil {
+		return nil, err
 	}
 
-	if doc.PageCount == nil || *doc.PageCount < 1 {
-		return nil, fmt.Errorf("%w: document has no pages to render", ErrRenderFailed)
+	images, err := r.renderPages(ctx, doc, opts, pages, func(pageCtx context.Context, openDoc document.Document, renderer image.Renderer, pageNum int) (*Image, error) {
+		return r.renderPage(pageCtx, documentID, openDoc, renderer, pageNum, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.events.Publish(ctx, events.Event{Type: EventRendered, Subject: documentID.String(), Data: images})
+	return images, nil
+}
+
+func (r *repo) RenderAsync(ctx context.Context, documentID uuid.UUID, opts RenderOptions) ([]Image, error) {
+	doc, err := r.renderableDocument(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := renderRange(doc, opts.Pages)
+	if err != nil {
+		return nil, err
+	}
+
+	render := func(ctx context.Context, pending []Image) ([]Image, error) {
+		byPage := make(map[int]Image, len(pending))
+		pageNums := make([]int, 0, len(pending))
+		for _, img := range pending {
+			byPage[img.PageNumber] = img
+			pageNums = append(pageNums, img.PageNumber)
+		}
+
+		return r.renderPages(ctx, doc, opts, pageNums, func(pageCtx context.Context, openDoc document.Document, renderer image.Renderer, pageNum int) (*Image, error) {
+			img := byPage[pageNum]
+			size, err := r.storePage(pageCtx, openDoc, renderer, pageNum, img.StorageKey)
+			if err != nil {
+				return nil, err
+			}
+			img.SizeBytes = size
+			return &img, nil
+		})
+	}
+
+	bgCtx := context.WithoutCancel(ctx)
+	images, err := RenderAsync(ctx, r.db, documentID, pages, opts, render, func(final []Image, err error) {
+		if err != nil {
+			r.logger.Error("failed to record async render", "document_id", documentID, "error", err)
+		}
+		r.logger.Info("async render finished", "document_id", documentID, "pages", len(final))
+		r.events.Publish(bgCtx, events.Event{Type: EventRendered, Subject: documentID.String(), Data: final})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Info("async render queued", "document_id", documentID, "pages", len(images))
+	return images, nil
+}
+
+func (r *repo) Rerender(ctx context.Context, documentID uuid.UUID, opts RenderOptions) ([]Image, error) {
+	doc, err := r.renderableDocument(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := ParsePageRange(fmt.Sprintf("1-%d", *doc.PageCount), *doc.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	staged, err := r.renderPages(ctx, doc, opts, pages, func(pageCtx context.Context, openDoc document.Document, renderer image.Renderer, pageNum int) (*Image, error) {
+		return r.stagePage(pageCtx, documentID, openDoc, renderer, pageNum, opts)
+	})
+	if err != nil {
+		for _, img := range staged {
+			r.storage.Delete(ctx, img.StorageKey)
+		}
+		return nil, err
 	}
 
-	pageExpr := opts.Pages
+	removed, err := ReplaceDocumentImages(ctx, r.db, r.storage, documentID, staged)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, img := range removed {
+		r.events.Publish(ctx, events.Event{Type: EventDeleted, Subject: img.ID.String(), Data: img})
+	}
+
+	images, err := DocumentImages(ctx, r.db, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Info("document images re-rendered", "document_id", documentID, "removed", len(removed), "rendered", len(images))
+	r.events.Publish(ctx, events.Event{Type: EventRendered, Subject: documentID.String(), Data: images})
+	return images, nil
+}
+
+func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
+	img, err := r.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	q := `DELETE FROM images WHERE id = $1`
+	_, err = repository.WithTx(ctx, r.db, func(tx *sql.Tx) (struct{}, error) {
+		return struct{}{}, repository.ExecExpectOne(ctx, tx, q, id)
+	})
+
+	if err != nil {
+		return repository.MapError(err, ErrNotFound, ErrDuplicate)
+	}
+
+	if err := r.storage.Delete(ctx, img.StorageKey); err != nil {
+		r.logger.Warn("failed to delete image file", "key", img.StorageKey, "error", err)
+	}
+
+	r.events.Publish(ctx, events.Event{Type: EventDeleted, Subject: id.String(), Data: *img})
+	return nil
+}
+
+// findReady returns the image with id, or ErrNotReady if it has not been
+// rendered.
+func (r *repo) findReady(ctx context.Context, id uuid.UUID) (*Image, error) {
+	img, err := r.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if img.Status != StatusReady {
+		return nil, fmt.Errorf("%w: image %s is %s", ErrNotReady, id, img.Status)
+	}
+	return img, nil
+}
+
+// renderRange resolves a page range expression against doc, defaulting to
+// every page when pageExpr is empty.
+func renderRange(doc *documents.Document, pageExpr string) ([]int, error) {
 	if pageExpr == "" {
 		pageExpr = fmt.Sprintf("1-%d", *doc.PageCount)
 	}
+	return ParsePageRange(pageExpr, *doc.PageCount)
+}
 
-	pages, err := ParsePageRange(pageExpr, *doc.PageCount)
+// renderableDocument returns the document if it exists, has a supported
+// content type, and has at least one page.
+func (r *repo) renderableDocument(ctx context.Context, documentID uuid.UUID) (*documents.Document, error) {
+	doc, err := r.documents.Find(ctx, documentID)
 	if err != nil {
 		return nil, err
 	}
 
+	if !document.IsSupported(doc.ContentType) {
+		return nil, ErrUnsupportedFormat
+	}
+
+	if doc.PageCount == nil || *doc.PageCount < 1 {
+		return nil, fmt.Errorf("%w: document has no pages to render", ErrRenderFailed)
+	}
+
+	return doc, nil
+}
+
+// WithLimits returns a copy of o with the server-side limits from cfg applied.
+func (o RenderOptions) WithLimits(cfg config.RenderConfig) RenderOptions {
+	o.PageTimeout = cfg.PageTimeoutDuration()
+	o.MemoryLimit = cfg.MemoryLimit
+	o.AreaLimit = cfg.AreaLimit
+	return o
+}
+
+// renderPages renders pages concurrently using renderFn and returns the
+// resulting images in page order. If any page fails, the first error is
+// returned along with the images that did render, so callers can clean up.
+// Each page is bounded by the configured page timeout.
+func (r *repo) renderPages(ctx context.Context, doc *documents.Document, opts RenderOptions, pages []int, renderFn pageRenderFunc) ([]Image, error) {
+	opts = opts.WithLimits(r.render)
+
 	docPath, err := r.storage.Path(ctx, doc.StorageKey)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
 	}
 
-	workerCount := renderWorkerCount(len(pages))
+	docPath, release, err := OpenablePath(docPath, doc.ContentType, opts.Password)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	workerCount := RenderWorkerCount(len(pages), r.render.MaxWorkers)
 	tasks := make(chan int, len(pages))
 	results := make(chan renderTask, len(pages))
 
 	var wg sync.WaitGroup
 	for range workerCount {
 		wg.Go(func() {
-			r.renderWorker(ctx, documentID, docPath, doc.ContentType, opts, tasks, results)
+			r.renderWorker(ctx, docPath, doc.ContentType, opts, renderFn, tasks, results)
 		})
 	}
 
@@ -159,10 +441,14 @@ func (r *repo) Render(ctx context.Context, documentID uuid.UUID, opts RenderOpti
 		close(results)
 	}()
 
+	var firstErr error
 	resultMap := make(map[int]*Image)
 	for task := range results {
 		if task.err != nil {
-			return nil, task.err
+			if firstErr == nil {
+				firstErr = task.err
+			}
+			continue
 		}
 		resultMap[task.pageNum] = task.result
 	}
@@ -174,29 +460,40 @@ func (r *repo) Render(ctx context.Context, documentID uuid.UUID, opts RenderOpti
 		}
 	}
 
-	return images, nil
+	return images, firstErr
 }
 
-func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
-	img, err := r.Find(ctx, id)
+// stagePage renders and stores a page image without recording it in the
+// database. The returned Image is ready to be inserted by ReplaceDocumentImages.
+func (r *repo) stagePage(ctx context.Context, documentID uuid.UUID, doc document.Document, renderer image.Renderer, pageNum int, opts RenderOptions) (*Image, error) {
+	storageKey := imageStorageKey(documentID, opts)
+
+	size, err := r.storePage(ctx, doc, renderer, pageNum, storageKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	q := `DELETE FROM images WHERE id = $1`
-	_, err = repository.WithTx(ctx, r.db, func(tx *sql.Tx) (struct{}, error) {
-		return struct{}{}, repository.ExecExpectOne(ctx, tx, q, id)
-	})
+	return opts.ToImage(uuid.New(), documentID, pageNum, storageKey, size), nil
+}
 
+// storePage renders pageNum and stores the data under storageKey, returning
+// its size in bytes.
+func (r *repo) storePage(ctx context.Context, doc document.Document, renderer image.Renderer, pageNum int, storageKey string) (int64, error) {
+	page, err := doc.ExtractPage(pageNum)
 	if err != nil {
-		return repository.MapError(err, ErrNotFound, ErrDuplicate)
+		return 0, fmt.Errorf("%w: %v", ErrRenderFailed, err)
 	}
 
-	if err := r.storage.Delete(ctx, img.StorageKey); err != nil {
-		r.logger.Warn("failed to delete image file", "key", img.StorageKey, "error", err)
+	data, err := page.ToImage(renderer, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrRenderFailed, err)
 	}
 
-	return nil
+	if err := r.storage.Store(ctx, storageKey, data); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrRenderFailed, err)
+	}
+
+	return int64(len(data)), nil
 }
 
 func (r *repo) renderPage(ctx context.Context, documentID uuid.UUID, doc document.Document, renderer image.Renderer, pageNum int, opts RenderOptions) (*Image, error) {
@@ -205,37 +502,34 @@ func (r *repo) renderPage(ctx context.Context, documentID uuid.UUID, doc documen
 		return nil, err
 	}
 
-	if existing != nil && !opts.Force {
+	if existing != nil && existing.Status == StatusReady && !opts.Force {
 		return existing, nil
 	}
 
-	page, err := doc.ExtractPage(pageNum)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
-	}
+	storageKey := imageStorageKey(documentID, opts)
 
-	data, err := page.ToImage(renderer, nil)
+	size, err := r.storePage(ctx, doc, renderer, pageNum, storageKey)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
-	}
-
-	storageKey := fmt.Sprintf("images/%s/%s.%s", documentID, uuid.New(), opts.Format)
-
-	if err := r.storage.Store(ctx, storageKey, data); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
+		return nil, err
 	}
 
 	if existing != nil {
-		if err := r.update(ctx, existing.ID, storageKey, int64(len(data))); err != nil {
+		existing.StorageKey = storageKey
+		existing.SizeBytes = size
+		existing.Status = StatusReady
+		existing.RenderError = nil
+
+		img, err := setRenderState(ctx, r.db, *existing)
+		if err != nil {
 			r.storage.Delete(ctx, storageKey)
 			return nil, err
 		}
-		return r.Find(ctx, existing.ID)
+		return img, nil
 	}
 
-	img := opts.ToImage(uuid.New(), documentID, pageNum, storageKey, int64(len(data)))
+	img := opts.ToImage(uuid.New(), documentID, pageNum, storageKey, size)
 
-	if err := r.create(ctx, img); err != nil {
+	if err := insertImage(ctx, r.db, img); err != nil {
 		r.storage.Delete(ctx, storageKey)
 		return nil, err
 	}
@@ -245,10 +539,10 @@ func (r *repo) renderPage(ctx context.Context, documentID uuid.UUID, doc documen
 
 func (r *repo) renderWorker(
 	ctx context.Context,
-	documentID uuid.UUID,
 	docPath string,
 	contentType string,
 	opts RenderOptions,
+	renderFn pageRenderFunc,
 	tasks <-chan int,
 	results chan<- renderTask,
 ) {
@@ -283,12 +577,38 @@ func (r *repo) renderWorker(
 		default:
 		}
 
-		img, err := r.renderPage(ctx, documentID, openDoc, renderer, pageNum, opts)
+		if err := r.limiter.Acquire(ctx); err != nil {
+			results <- renderTask{pageNum: pageNum, err: err}
+			return
+		}
+
+		// The slot is released when renderFn returns rather than when
+		// RenderPageWithin does, so a render abandoned on timeout keeps its
+		// slot until ImageMagick actually exits.
+		img, err := RenderPageWithin(ctx, pageNum, opts.PageTimeout, func(pageCtx context.Context) (*Image, error) {
+			defer r.limiter.Release()
+			return renderFn(pageCtx, openDoc, renderer, pageNum)
+		})
 		results <- renderTask{pageNum: pageNum, result: img, err: err}
 	}
 }
 
+// limitedScale runs the configured Scaler under the render limiter.
+func (r *repo) limitedScale(ctx context.Context, data []byte, format document.ImageFormat, maxDim int) ([]byte, error) {
+	if err := r.limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer r.limiter.Release()
+	return r.scale(ctx, data, format, maxDim)
+}
+
 func (r *repo) findExisting(ctx context.Context, documentID uuid.UUID, pageNum int, opts RenderOptions) (*Image, error) {
+	return findImage(ctx, r.db, documentID, pageNum, opts)
+}
+
+// findImage returns the image of pageNum rendered with opts, or nil if there
+// is none.
+func findImage(ctx context.Context, db repository.Querier, documentID uuid.UUID, pageNum int, opts RenderOptions) (*Image, error) {
 	q, args := query.NewBuilder(projection).
 		WhereEquals("DocumentID", documentID).
 		WhereEquals("PageNumber", pageNum).
@@ -300,9 +620,11 @@ func (r *repo) findExisting(ctx context.Context, documentID uuid.UUID, pageNum i
 		WhereNullable("Saturation", opts.Saturation).
 		WhereNullable("Rotation", opts.Rotation).
 		WhereNullable("Background", opts.Background).
+		WhereNullable("Grayscale", opts.Grayscale).
+		WhereNullable("Threshold", opts.Threshold).
 		BuildSingleOrNull()
 
-	img, err := repository.QueryOne(ctx, r.db, q, args, scanImage)
+	img, err := repository.QueryOne(ctx, db, q, args, scanImage)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -312,29 +634,63 @@ func (r *repo) findExisting(ctx context.Context, documentID uuid.UUID, pageNum i
 	return &img, nil
 }
 
-func (r *repo) create(ctx context.Context, img *Image) error {
-	_, err := r.db.ExecContext(
+func insertImage(ctx context.Context, e repository.Executor, img *Image) error {
+	_, err := e.ExecContext(
 		ctx,
 		`INSERT INTO images (id, document_id, page_number, format, dpi, quality,
-			brightness, contrast, saturation, rotation, background, storage_key, size_bytes)
-		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+			brightness, contrast, saturation, rotation, background, grayscale, threshold,
+			storage_key, size_bytes, status)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
 		img.ID, img.DocumentID, img.PageNumber, img.Format, img.DPI, img.Quality,
 		img.Brightness, img.Contrast, img.Saturation, img.Rotation, img.Background,
-		img.StorageKey, img.SizeBytes,
+		img.Grayscale, img.Threshold, img.StorageKey, img.SizeBytes, img.Status,
 	)
 	return err
 }
 
-func (r *repo) update(ctx context.Context, id uuid.UUID, storageKey string, sizeBytes int64) error {
-	_, err := r.db.ExecContext(
-		ctx,
-		`UPDATE images SET storage_key = $1, size_bytes = $2 WHERE id = $3`,
-		storageKey, sizeBytes, id,
-	)
-	return err
+// imageStorageKey returns a new storage key for a page image of documentID.
+func imageStorageKey(documentID uuid.UUID, opts RenderOptions) string {
+	return fmt.Sprintf("images/%s/%s.%s", documentID, uuid.New(), opts.Format)
 }
 
-func renderWorkerCount(pageCount int) int {
-	workers := max(min(runtime.NumCPU(), pageCount), 1)
-	return workers
+// RenderPageWithin runs render for pageNum under a child context that expires
+// after timeout. If the deadline passes first, it returns an error wrapping
+// ErrRenderTimeout without waiting for render to finish, so one stuck page
+// cannot stall the rest of a batch. The abandoned render observes its context
+// as cancelled with ErrRenderTimeout as the cause. A zero timeout calls render
+// directly with ctx.
+func RenderPageWithin(ctx context.Context, pageNum int, timeout time.Duration, render func(context.Context) (*Image, error)) (*Image, error) {
+	if timeout <= 0 {
+		return render(ctx)
+	}
+
+	pageCtx, cancel := context.WithTimeoutCause(ctx, timeout, ErrRenderTimeout)
+	defer cancel()
+
+	done := make(chan renderTask, 1)
+	go func() {
+		img, err := render(pageCtx)
+		done <- renderTask{pageNum: pageNum, result: img, err: err}
+	}()
+
+	select {
+	case task := <-done:
+		return task.result, task.err
+	case <-pageCtx.Done():
+		if errors.Is(context.Cause(pageCtx), ErrRenderTimeout) {
+			return nil, fmt.Errorf("%w: %w: page %d exceeded %s", ErrRenderFailed, ErrRenderTimeout, pageNum, timeout)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// RenderWorkerCount returns how many workers render pageCount pages: the
+// smaller of pageCount and the CPU count, further capped by maxWorkers when
+// it is positive. The result is never less than 1.
+func RenderWorkerCount(pageCount, maxWorkers int) int {
+	limit := runtime.NumCPU()
+	if maxWorkers > 0 {
+		limit = min(limit, maxWorkers)
+	}
+	return max(min(limit, pageCount), 1)
 }
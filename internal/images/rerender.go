@@ -0,0 +1,65 @@
+package images
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/agent-lab/pkg/repository"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+	"github.com/google/uuid"
+)
+
+// DocumentImages returns every image recorded for a document, ordered by page number.
+func DocumentImages(ctx context.Context, db repository.Querier, documentID uuid.UUID) ([]Image, error) {
+	q, args := query.NewBuilder(projection, query.SortField{Field: "PageNumber"}).
+		WhereEquals("DocumentID", documentID).
+		Build()
+
+	images, err := repository.QueryMany(ctx, db, q, args, scanImage)
+	if err != nil {
+		return nil, fmt.Errorf("query document images: %w", err)
+	}
+	return images, nil
+}
+
+// ReplaceDocumentImages swaps a document's image set for staged, whose data
+// must already be in store. Old records are deleted and staged records
+// inserted in a single transaction, so readers see either the old set or
+// the new one. If the transaction fails, the staged files are deleted and
+// the old set is left intact; once it commits, the old files are deleted.
+// Returns the images that were replaced.
+func ReplaceDocumentImages(ctx context.Context, db *sql.DB, store storage.System, documentID uuid.UUID, staged []Image) ([]Image, error) {
+	removed, err := repository.WithTx(ctx, db, func(tx *sql.Tx) ([]Image, error) {
+		existing, err := DocumentImages(ctx, tx, documentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM images WHERE document_id = $1`, documentID); err != nil {
+			return nil, fmt.Errorf("delete document images: %w", err)
+		}
+
+		for i := range staged {
+			if err := insertImage(ctx, tx, &staged[i]); err != nil {
+				return nil, fmt.Errorf("insert image for page %d: %w", staged[i].PageNumber, err)
+			}
+		}
+
+		return existing, nil
+	})
+
+	if err != nil {
+		for _, img := range staged {
+			store.Delete(ctx, img.StorageKey)
+		}
+		return nil, repository.MapError(err, ErrNotFound, ErrDuplicate)
+	}
+
+	for _, img := range removed {
+		store.Delete(ctx, img.StorageKey)
+	}
+
+	return removed, nil
+}
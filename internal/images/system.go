@@ -2,6 +2,7 @@ package images
 
 import (
 	"context"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/google/uuid"
@@ -18,12 +19,47 @@ type System interface {
 	Find(ctx context.Context, id uuid.UUID) (*Image, error)
 
 	// Data retrieves the raw image bytes and content type for an image.
+	// Returns ErrNotReady while the image is pending or after its render failed.
 	Data(ctx context.Context, id uuid.UUID) ([]byte, string, error)
 
+	// SignedURL returns an HMAC-signed URL for the image's data that grants
+	// access without authentication until it expires after ttl.
+	SignedURL(ctx context.Context, id uuid.UUID, ttl time.Duration) (*SignedURL, error)
+
+	// Thumbnail retrieves a downscaled copy of an image whose largest dimension
+	// does not exceed maxDim, generating and caching it on first request.
+	// Returns the thumbnail bytes and content type.
+	Thumbnail(ctx context.Context, id uuid.UUID, maxDim int) ([]byte, string, error)
+
+	// PageImage returns the bytes and content type of one document page
+	// rendered at opts, rendering and recording it first if no image with the
+	// same options exists. Concurrent calls for the same page and options
+	// share a single render. opts.Pages and opts.Force are ignored.
+	PageImage(ctx context.Context, documentID uuid.UUID, pageNum int, opts RenderOptions) ([]byte, string, error)
+
 	// Render creates images from document pages based on the provided options.
 	// Returns the created Image records for all rendered pages.
 	Render(ctx context.Context, documentID uuid.UUID, cmd RenderOptions) ([]Image, error)
 
+	// RenderAsync records an image for each requested page and returns the
+	// records without waiting for the render. Pages that still need rendering
+	// are returned pending and rendered in the background, after which each
+	// moves to ready or failed and EventRendered is published with the final
+	// records. Pages with a ready image are returned as is unless opts.Force
+	// is set.
+	RenderAsync(ctx context.Context, documentID uuid.UUID, opts RenderOptions) ([]Image, error)
+
+	// Rerender replaces every image of a document with a fresh render of all
+	// pages using opts; opts.Pages and opts.Force are ignored. Pages are
+	// rendered before any existing image is touched and the records are
+	// swapped in one transaction, so a failure leaves the previous images intact.
+	// Returns the new Image records ordered by page number.
+	Rerender(ctx context.Context, documentID uuid.UUID, opts RenderOptions) ([]Image, error)
+
+	// Patch updates only the image metadata fields set in cmd. Render
+	// parameters are immutable; setting one fails validation.
+	Patch(ctx context.Context, id uuid.UUID, cmd PatchImageCommand) (*Image, error)
+
 	// Delete deletes an image from storage and the database.
 	Delete(ctx context.Context, id uuid.UUID) error
 }
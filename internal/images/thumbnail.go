@@ -0,0 +1,98 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+	"github.com/JaimeStill/document-context/pkg/document"
+)
+
+const (
+	// DefaultThumbnailSize is the maximum thumbnail dimension used when none is requested.
+	DefaultThumbnailSize = 256
+
+	// MaxThumbnailSize is the largest maximum dimension accepted for thumbnails.
+	MaxThumbnailSize = 1024
+
+	minThumbnailSize = 16
+)
+
+// Scaler downscales encoded image data so neither dimension exceeds maxDim,
+// returning data encoded in the same format.
+type Scaler func(ctx context.Context, data []byte, format document.ImageFormat, maxDim int) ([]byte, error)
+
+// ValidateThumbnailSize checks that size is within the accepted range.
+func ValidateThumbnailSize(size int) error {
+	if size < minThumbnailSize || size > MaxThumbnailSize {
+		return fmt.Errorf("%w: size must be between %d and %d", ErrInvalidThumbnailSize, minThumbnailSize, MaxThumbnailSize)
+	}
+	return nil
+}
+
+// ThumbnailKey returns the storage key for a thumbnail of img at maxDim.
+// Keys derive from the source storage key, so re-rendering an image
+// produces fresh thumbnails rather than serving stale ones.
+func ThumbnailKey(img Image, maxDim int) string {
+	base := strings.TrimSuffix(img.StorageKey, "."+string(img.Format))
+	return fmt.Sprintf("thumbnails/%s/%d.%s", strings.TrimPrefix(base, "images/"), maxDim, img.Format)
+}
+
+// LoadThumbnail returns the thumbnail for img at maxDim. A stored thumbnail
+// is reused when present; otherwise the source image is scaled and the
+// result stored under ThumbnailKey for subsequent requests.
+func LoadThumbnail(ctx context.Context, store storage.System, img Image, maxDim int, scale Scaler) ([]byte, error) {
+	if err := ValidateThumbnailSize(maxDim); err != nil {
+		return nil, err
+	}
+
+	key := ThumbnailKey(img, maxDim)
+
+	data, err := store.Retrieve(ctx, key)
+	if err == nil {
+		return data, nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("retrieve thumbnail: %w", err)
+	}
+
+	source, err := store.Retrieve(ctx, img.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve image: %w", err)
+	}
+
+	data, err = scale(ctx, source, img.Format, maxDim)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err)
+	}
+
+	if err := store.Store(ctx, key, data); err != nil {
+		return nil, fmt.Errorf("store thumbnail: %w", err)
+	}
+
+	return data, nil
+}
+
+// magickScale downscales images with the ImageMagick CLI, preserving aspect
+// ratio and never enlarging images already within maxDim.
+func magickScale(ctx context.Context, data []byte, format document.ImageFormat, maxDim int) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "magick", "-",
+		"-thumbnail", fmt.Sprintf("%dx%d>", maxDim, maxDim),
+		fmt.Sprintf("%s:-", format),
+	)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("imagemagick: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
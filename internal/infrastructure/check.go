@@ -0,0 +1,109 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/google/uuid"
+)
+
+// CheckResult is the outcome of a single preflight check.
+// Err is nil when the check passed.
+type CheckResult struct {
+	Name   string
+	Detail string
+	Err    error
+}
+
+// CheckReport collects the results of a preflight check run.
+type CheckReport struct {
+	Results []CheckResult
+}
+
+// Add records the outcome of a named check.
+func (r *CheckReport) Add(name, detail string, err error) {
+	r.Results = append(r.Results, CheckResult{Name: name, Detail: detail, Err: err})
+}
+
+// OK reports whether every recorded check passed.
+func (r *CheckReport) OK() bool {
+	for _, res := range r.Results {
+		if res.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Write prints one line per check followed by an overall verdict.
+func (r *CheckReport) Write(w io.Writer) {
+	failed := 0
+	for _, res := range r.Results {
+		status := "ok"
+		if res.Err != nil {
+			status = "FAIL"
+			failed++
+		}
+
+		line := fmt.Sprintf("%-4s  %-8s  %s", status, res.Name, res.Detail)
+		if res.Err != nil {
+			line += ": " + res.Err.Error()
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(w, "check failed: %d of %d checks failed\n", failed, len(r.Results))
+		return
+	}
+	fmt.Fprintf(w, "check passed: %d checks\n", len(r.Results))
+}
+
+// Check verifies that the service's dependencies are usable without starting
+// the lifecycle or binding the listen port: the database must answer a ping
+// and the storage base path must accept a write. The ping honors ctx's
+// deadline. A probe blob is written and removed to test storage.
+func (i *Infrastructure) Check(ctx context.Context) *CheckReport {
+	report := &CheckReport{}
+
+	start := time.Now()
+	if err := i.Database.Connection().PingContext(ctx); err != nil {
+		report.Add("database", "ping", err)
+	} else {
+		report.Add("database", fmt.Sprintf("ping (%s)", time.Since(start).Round(time.Millisecond)), nil)
+	}
+
+	report.Add("storage", "write", i.checkStorage(ctx))
+
+	return report
+}
+
+func (i *Infrastructure) checkStorage(ctx context.Context) error {
+	key := ".check/" + uuid.NewString()
+
+	if err := i.Storage.Store(ctx, key, []byte("agent-lab check")); err != nil {
+		return err
+	}
+	return i.Storage.Delete(ctx, key)
+}
+
+// CheckConfig initializes infrastructure from a finalized configuration and
+// runs Check against it, prefixed with a summary of the loaded config. The
+// database connection is closed before returning.
+func CheckConfig(ctx context.Context, cfg *config.Config) *CheckReport {
+	report := &CheckReport{}
+	report.Add("config", fmt.Sprintf("env=%s version=%s addr=%s", cfg.Env(), cfg.Version, cfg.Server.Addr()), nil)
+
+	infra, err := New(cfg)
+	if err != nil {
+		report.Add("init", "infrastructure", err)
+		return report
+	}
+	defer infra.Database.Connection().Close()
+
+	report.Results = append(report.Results, infra.Check(ctx).Results...)
+	return report
+}
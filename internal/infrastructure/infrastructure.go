@@ -1,26 +1,32 @@
 // Package infrastructure provides core service initialization for application startup.
-// It assembles common dependencies (logging, database, storage) that domain systems require.
+// It assembles common dependencies (logging, database, storage, events) that domain systems require.
 package infrastructure
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 
 	"github.com/JaimeStill/agent-lab/internal/config"
 	"github.com/JaimeStill/agent-lab/pkg/database"
+	"github.com/JaimeStill/agent-lab/pkg/events"
 	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
 	"github.com/JaimeStill/agent-lab/pkg/logging"
 	"github.com/JaimeStill/agent-lab/pkg/storage"
+	"github.com/JaimeStill/agent-lab/pkg/sweeper"
 )
 
 // Infrastructure holds the core systems required by all domain modules.
 // It provides a single point of initialization for lifecycle coordination,
-// logging, database access, and file storage.
+// logging, database access, file storage, domain event delivery, and
+// background cleanup of expired artifacts.
 type Infrastructure struct {
 	Lifecycle *lifecycle.Coordinator
 	Logger    *slog.Logger
 	Database  database.System
 	Storage   storage.System
+	Events    *events.Bus
+	Sweeper   *sweeper.Sweeper
 }
 
 // New creates an Infrastructure from the application configuration.
@@ -39,11 +45,21 @@ func New(cfg *config.Config) (*Infrastructure, error) {
 		return nil, fmt.Errorf("storage init failed: %w", err)
 	}
 
+	sweep := sweeper.New(&cfg.Sweeper, logger)
+	if ts, ok := store.(storage.TempSweeper); ok {
+		ttl := cfg.Sweeper.TempTTLDuration()
+		sweep.Add("storage_temp", func(ctx context.Context) (int, error) {
+			return ts.SweepTemp(ctx, ttl)
+		})
+	}
+
 	return &Infrastructure{
 		Lifecycle: lc,
 		Logger:    logger,
 		Database:  db,
 		Storage:   store,
+		Events:    events.New(logger),
+		Sweeper:   sweep,
 	}, nil
 }
 
@@ -55,5 +71,15 @@ func (i *Infrastructure) Start() error {
 	if err := i.Storage.Start(i.Lifecycle); err != nil {
 		return fmt.Errorf("storage start failed: %w", err)
 	}
+	if err := i.Sweeper.Start(i.Lifecycle, "storage"); err != nil {
+		return fmt.Errorf("sweeper start failed: %w", err)
+	}
+
+	i.Lifecycle.OnDrain(func(ctx context.Context) {
+		if err := i.Events.Wait(ctx); err != nil {
+			i.Logger.Warn("event handlers did not finish before shutdown", "error", err)
+		}
+	})
+
 	return nil
 }
@@ -3,6 +3,9 @@ package profiles
 import (
 	"errors"
 	"net/http"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/query"
 )
 
 // Domain errors for profile operations.
@@ -12,6 +15,12 @@ var (
 	ErrStageNotFound = errors.New("stage not found")
 )
 
+func init() {
+	handlers.RegisterErrorCode("not_found", ErrNotFound)
+	handlers.RegisterErrorCode("duplicate", ErrDuplicate)
+	handlers.RegisterErrorCode("stage_not_found", ErrStageNotFound)
+}
+
 // MapHTTPStatus maps domain errors to appropriate HTTP status codes.
 func MapHTTPStatus(err error) int {
 	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrStageNotFound) {
@@ -20,5 +29,11 @@ func MapHTTPStatus(err error) int {
 	if errors.Is(err, ErrDuplicate) {
 		return http.StatusConflict
 	}
+	if errors.Is(err, handlers.ErrValidation) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, query.ErrInvalidSort) {
+		return http.StatusBadRequest
+	}
 	return http.StatusInternalServerError
 }
@@ -1,7 +1,7 @@
 package profiles
 
 import (
-	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
 
@@ -38,24 +38,33 @@ func (h *Handler) Routes() routes.Group {
 			{Method: "GET", Pattern: "/{id}", Handler: h.Find, OpenAPI: Spec.Find},
 			{Method: "POST", Pattern: "", Handler: h.Create, OpenAPI: Spec.Create},
 			{Method: "PUT", Pattern: "/{id}", Handler: h.Update, OpenAPI: Spec.Update},
+			{Method: "PATCH", Pattern: "/{id}", Handler: h.Patch, OpenAPI: Spec.Patch},
 			{Method: "DELETE", Pattern: "/{id}", Handler: h.Delete, OpenAPI: Spec.Delete},
+			{Method: "GET", Pattern: "/{id}/stages", Handler: h.ListStages, OpenAPI: Spec.ListStages},
+			{Method: "GET", Pattern: "/{id}/stages/{stage}", Handler: h.FindStage, OpenAPI: Spec.FindStage},
 			{Method: "POST", Pattern: "/{id}/stages", Handler: h.SetStage, OpenAPI: Spec.SetStage},
 			{Method: "DELETE", Pattern: "/{id}/stages/{stage}", Handler: h.DeleteStage, OpenAPI: Spec.DeleteStage},
+			{Method: "GET", Pattern: "/{id}/stages/{stage}/preview", Handler: h.PreviewStage, OpenAPI: Spec.PreviewStage},
 		},
 	}
 }
 
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	page := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
 	filters := FiltersFromQuery(r.URL.Query())
 
 	result, err := h.sys.List(r.Context(), page, filters)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	pagination.Respond(w, r, result)
 }
 
 func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
@@ -71,13 +80,13 @@ func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	handlers.RespondFields(w, r, http.StatusOK, result)
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var cmd CreateProfileCommand
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -98,8 +107,8 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var cmd UpdateProfileCommand
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -112,6 +121,28 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	handlers.RespondJSON(w, http.StatusOK, result)
 }
 
+func (h *Handler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var cmd PatchProfileCommand
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	result, err := h.sys.Patch(r.Context(), id, cmd)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, result)
+}
+
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
@@ -127,6 +158,58 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ListStages handles GET /api/profiles/{id}/stages.
+func (h *Handler) ListStages(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	profile, err := h.sys.Find(r.Context(), id)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	stages := profile.Stages
+	if stages == nil {
+		stages = []ProfileStage{}
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, stages)
+}
+
+// FindStage handles GET /api/profiles/{id}/stages/{stage}.
+func (h *Handler) FindStage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	stageName := r.PathValue("stage")
+	if stageName == "" {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, ErrStageNotFound)
+		return
+	}
+
+	profile, err := h.sys.Find(r.Context(), id)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	stage := profile.Stage(stageName)
+	if stage == nil {
+		err := fmt.Errorf("%w: %s", ErrStageNotFound, stageName)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, stage)
+}
+
 func (h *Handler) SetStage(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
@@ -135,8 +218,8 @@ func (h *Handler) SetStage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var cmd SetProfileStageCommand
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -169,3 +252,26 @@ func (h *Handler) DeleteStage(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// PreviewStage handles GET /api/profiles/{id}/stages/{stage}/preview.
+func (h *Handler) PreviewStage(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	stageName := r.PathValue("stage")
+	if stageName == "" {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, ErrStageNotFound)
+		return
+	}
+
+	result, err := h.sys.PreviewStage(r.Context(), id, stageName)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, result)
+}
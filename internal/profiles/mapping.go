@@ -11,18 +11,19 @@ import (
 var profileProjection = query.
 	NewProjectionMap("public", "profiles", "p").
 	Project("id", "ID").
-	Project("workflow_name", "WorkflowName").
-	Project("name", "Name").
-	Project("description", "Description").
+	ProjectText("workflow_name", "WorkflowName").
+	ProjectText("name", "Name").
+	ProjectText("description", "Description").
 	Project("created_at", "CreatedAt").
-	Project("updated_at", "UpdatedAt")
+	Project("updated_at", "UpdatedAt").
+	Sortable("ID", "WorkflowName", "Name", "CreatedAt", "UpdatedAt")
 
 var stageProjection = query.
 	NewProjectionMap("public", "profile_stages", "ps").
 	Project("profile_id", "ProfileID").
-	Project("stage_name", "StageName").
+	ProjectText("stage_name", "StageName").
 	Project("agent_id", "AgentID").
-	Project("system_prompt", "SystemPrompt").
+	ProjectText("system_prompt", "SystemPrompt").
 	Project("options", "Options")
 
 var defaultSort = query.SortField{Field: "Name"}
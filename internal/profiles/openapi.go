@@ -3,13 +3,17 @@ package profiles
 import "github.com/JaimeStill/agent-lab/pkg/openapi"
 
 type spec struct {
-	List        *openapi.Operation
-	Create      *openapi.Operation
-	Find        *openapi.Operation
-	Update      *openapi.Operation
-	Delete      *openapi.Operation
-	SetStage    *openapi.Operation
-	DeleteStage *openapi.Operation
+	List         *openapi.Operation
+	Create       *openapi.Operation
+	Find         *openapi.Operation
+	Update       *openapi.Operation
+	Patch        *openapi.Operation
+	Delete       *openapi.Operation
+	SetStage     *openapi.Operation
+	ListStages   *openapi.Operation
+	FindStage    *openapi.Operation
+	DeleteStage  *openapi.Operation
+	PreviewStage *openapi.Operation
 }
 
 var Spec = spec{
@@ -20,11 +24,13 @@ var Spec = spec{
 			openapi.QueryParam("page", "integer", "Page number (1-indexed)", false),
 			openapi.QueryParam("page_size", "integer", "Results per page", false),
 			openapi.QueryParam("search", "string", "Search query (matches name)", false),
-			openapi.QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending", false),
+			openapi.QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending, suffix text fields with :ci for case-insensitive. Unknown fields return 400 listing the sortable fields", false),
 			openapi.QueryParam("workflow_name", "string", "Filter by workflow name", false),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Paginated list of profiles", "ProfilePageResult"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated list of profiles", "ProfilePageResult"), openapi.PageHeaders(true)),
+			400: openapi.ResponseRef("BadRequest"),
 		},
 	},
 	Create: &openapi.Operation{
@@ -42,6 +48,7 @@ var Spec = spec{
 		Description: "Returns a profile with all its stage configurations",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Profile UUID"),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
 			200: openapi.ResponseJSON("Profile with stages", "ProfileWithStages"),
@@ -63,6 +70,20 @@ var Spec = spec{
 			409: openapi.ResponseRef("Conflict"),
 		},
 	},
+	Patch: &openapi.Operation{
+		Summary:     "Patch profile",
+		Description: "Updates only the profile metadata fields present in the request body",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Profile UUID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("PatchProfileCommand", true),
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Updated profile", "Profile"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			409: openapi.ResponseRef("Conflict"),
+		},
+	},
 	Delete: &openapi.Operation{
 		Summary:     "Delete profile",
 		Description: "Deletes a profile and all its stage configurations",
@@ -75,6 +96,31 @@ var Spec = spec{
 			404: openapi.ResponseRef("NotFound"),
 		},
 	},
+	ListStages: &openapi.Operation{
+		Summary:     "List stage configurations",
+		Description: "Returns the stage configurations stored on a profile without the profile itself",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Profile UUID"),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Stage configurations", "ProfileStageArray"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
+	FindStage: &openapi.Operation{
+		Summary:     "Find stage configuration",
+		Description: "Returns a single stage configuration stored on a profile. Unlike preview, workflow defaults are not merged in",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Profile UUID"),
+			openapi.PathParam("stage", "Stage name"),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Stage configuration", "ProfileStage"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
 	SetStage: &openapi.Operation{
 		Summary:     "Set stage configuration",
 		Description: "Creates or updates a stage configuration for a profile (save)",
@@ -101,6 +147,19 @@ var Spec = spec{
 			404: openapi.ResponseRef("NotFound"),
 		},
 	},
+	PreviewStage: &openapi.Operation{
+		Summary:     "Preview resolved stage configuration",
+		Description: "Returns the stage configuration a workflow execution would use: the profile merged over the workflow's default profile, including the effective agent, system prompt, and options. Nothing is executed",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Profile UUID"),
+			openapi.PathParam("stage", "Stage name"),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Resolved stage configuration", "StagePreview"),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
 }
 
 func (spec) Schemas() map[string]*openapi.Schema {
@@ -126,6 +185,22 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"options":       {Type: "object", Description: "Stage-specific options (JSON)"},
 			},
 		},
+		"ProfileStageArray": {
+			Type:  "array",
+			Items: openapi.SchemaRef("ProfileStage"),
+		},
+		"StagePreview": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"profile_id":    {Type: "string", Format: "uuid"},
+				"workflow_name": {Type: "string"},
+				"stage_name":    {Type: "string"},
+				"source":        {Type: "string", Enum: []any{"profile", "default"}, Description: "Whether the stage comes from the profile or the workflow's default profile"},
+				"agent_id":      {Type: "string", Format: "uuid", Description: "Effective agent; absent when the agent_id execution parameter supplies it"},
+				"system_prompt": {Type: "string", Description: "Effective system prompt"},
+				"options":       {Type: "object", Description: "Effective stage-specific options (JSON)"},
+			},
+		},
 		"ProfileWithStages": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
@@ -135,7 +210,7 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"description":   {Type: "string"},
 				"created_at":    {Type: "string", Format: "date-time"},
 				"updated_at":    {Type: "string", Format: "date-time"},
-				"stages":        {Type: "array", Items: openapi.SchemaRef("ProfileStage")},
+				"stages":        {Type: "array", Description: "Stages in the order the workflow declares them; undeclared stages follow by name", Items: openapi.SchemaRef("ProfileStage")},
 			},
 		},
 		"CreateProfileCommand": {
@@ -155,6 +230,13 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"description": {Type: "string"},
 			},
 		},
+		"PatchProfileCommand": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"name":        {Type: "string"},
+				"description": {Type: "string"},
+			},
+		},
 		"SetProfileStageCommand": {
 			Type:     "object",
 			Required: []string{"stage_name"},
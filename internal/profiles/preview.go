@@ -0,0 +1,63 @@
+package profiles
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// DefaultsFunc returns the default profile that stored profiles of a workflow
+// are merged over at execution, or nil when the workflow registers none.
+type DefaultsFunc func(workflowName string) *ProfileWithStages
+
+// Stage preview sources, identifying which profile supplied a resolved stage.
+const (
+	PreviewSourceProfile = "profile"
+	PreviewSourceDefault = "default"
+)
+
+// StagePreview is the resolved configuration of a single stage as a workflow
+// execution would see it: the stored profile merged over the workflow's
+// default profile. A nil AgentID means the agent comes from the agent_id
+// execution parameter.
+type StagePreview struct {
+	ProfileID    uuid.UUID       `json:"profile_id"`
+	WorkflowName string          `json:"workflow_name"`
+	StageName    string          `json:"stage_name"`
+	Source       string          `json:"source"`
+	AgentID      *uuid.UUID      `json:"agent_id,omitempty"`
+	SystemPrompt *string         `json:"system_prompt,omitempty"`
+	Options      json.RawMessage `json:"options,omitempty"`
+}
+
+// PreviewStage resolves the named stage of profile merged over defaults using
+// Merge, the same resolution workflow executions apply. A nil defaults
+// resolves the stage from profile alone.
+// Returns ErrStageNotFound if neither profile defines the stage.
+func PreviewStage(profile, defaults *ProfileWithStages, stage string) (*StagePreview, error) {
+	base := defaults
+	if base == nil {
+		base = &ProfileWithStages{}
+	}
+
+	resolved := base.Merge(profile).Stage(stage)
+	if resolved == nil {
+		return nil, fmt.Errorf("%w: %s", ErrStageNotFound, stage)
+	}
+
+	source := PreviewSourceDefault
+	if profile.Stage(stage) != nil {
+		source = PreviewSourceProfile
+	}
+
+	return &StagePreview{
+		ProfileID:    profile.ID,
+		WorkflowName: profile.WorkflowName,
+		StageName:    resolved.StageName,
+		Source:       source,
+		AgentID:      resolved.AgentID,
+		SystemPrompt: resolved.SystemPrompt,
+		Options:      resolved.Options,
+	}, nil
+}
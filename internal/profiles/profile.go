@@ -4,12 +4,24 @@
 package profiles
 
 import (
+	"cmp"
 	"encoding/json"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/google/uuid"
 )
 
+// Domain event types published to the event bus. Created and updated events
+// carry the Profile as data; deleted events carry only the profile ID as subject.
+const (
+	EventCreated = "profile.created"
+	EventUpdated = "profile.updated"
+	EventDeleted = "profile.deleted"
+)
+
 // Profile represents a named configuration set for a workflow.
 // Multiple profiles can exist for the same workflow, enabling
 // experimentation with different agent and prompt configurations.
@@ -96,6 +108,40 @@ func (p *ProfileWithStages) Stage(name string) *ProfileStage {
 	return nil
 }
 
+// StageNames returns the names of the profile's stages in order.
+func (p *ProfileWithStages) StageNames() []string {
+	names := make([]string, len(p.Stages))
+	for i, s := range p.Stages {
+		names[i] = s.StageName
+	}
+	return names
+}
+
+// SortStages orders the profile's stages by their position in order,
+// typically the stage sequence the workflow declares in its default profile.
+// Stages absent from order follow the listed ones, sorted by name.
+func (p *ProfileWithStages) SortStages(order []string) {
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		rank[name] = i
+	}
+
+	slices.SortStableFunc(p.Stages, func(a, b ProfileStage) int {
+		ra, aListed := rank[a.StageName]
+		rb, bListed := rank[b.StageName]
+		switch {
+		case aListed && bListed:
+			return cmp.Compare(ra, rb)
+		case aListed:
+			return -1
+		case bListed:
+			return 1
+		default:
+			return strings.Compare(a.StageName, b.StageName)
+		}
+	})
+}
+
 // CreateProfileCommand contains the data needed to create a new profile.
 type CreateProfileCommand struct {
 	WorkflowName string  `json:"workflow_name"`
@@ -103,12 +149,46 @@ type CreateProfileCommand struct {
 	Description  *string `json:"description,omitempty"`
 }
 
+// Validate checks each field of the command and returns a
+// *handlers.ValidationError listing every invalid field, or nil.
+func (c CreateProfileCommand) Validate() error {
+	var v handlers.ValidationError
+
+	if strings.TrimSpace(c.WorkflowName) == "" {
+		v.Add("workflow_name", "workflow_name is required")
+	}
+	if strings.TrimSpace(c.Name) == "" {
+		v.Add("name", "name is required")
+	}
+
+	return v.Err()
+}
+
 // UpdateProfileCommand contains the data needed to update profile metadata.
 type UpdateProfileCommand struct {
 	Name        string  `json:"name"`
 	Description *string `json:"description,omitempty"`
 }
 
+// PatchProfileCommand contains a partial update to profile metadata.
+// Only non-nil fields are applied.
+type PatchProfileCommand struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// Validate checks each set field of the command and returns a
+// *handlers.ValidationError listing every invalid field, or nil.
+func (c PatchProfileCommand) Validate() error {
+	var v handlers.ValidationError
+
+	if c.Name != nil && strings.TrimSpace(*c.Name) == "" {
+		v.Add("name", "name cannot be empty")
+	}
+
+	return v.Err()
+}
+
 // SetProfileStageCommand contains the data needed to create or update a stage configuration.
 // Uses save semantics - creates if not exists, updates if exists.
 type SetProfileStageCommand struct {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/JaimeStill/agent-lab/pkg/events"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/agent-lab/pkg/repository"
@@ -14,15 +15,23 @@ import (
 
 type repo struct {
 	db         *sql.DB
+	events     *events.Bus
 	logger     *slog.Logger
 	pagination pagination.Config
+	defaults   DefaultsFunc
 }
 
-func New(db *sql.DB, logger *slog.Logger, pagination pagination.Config) System {
+// New creates a profiles repository.
+// Profile lifecycle events are published to bus, which may be nil.
+// Stage previews merge profiles over the workflow defaults returned by
+// defaults, which may be nil.
+func New(db *sql.DB, bus *events.Bus, logger *slog.Logger, pagination pagination.Config, defaults DefaultsFunc) System {
 	return &repo{
 		db:         db,
+		events:     bus,
 		logger:     logger.With("system", "profiles"),
 		pagination: pagination,
+		defaults:   defaults,
 	}
 }
 
@@ -39,8 +48,8 @@ func (r *repo) List(ctx context.Context, page pagination.PageRequest, filters Fi
 
 	filters.Apply(qb)
 
-	if len(page.Sort) > 0 {
-		qb.OrderByFields(page.Sort)
+	if err := qb.SortBy(page.Sort); err != nil {
+		return nil, err
 	}
 
 	countSQL, countArgs := qb.BuildCount()
@@ -78,13 +87,32 @@ func (r *repo) Find(ctx context.Context, id uuid.UUID) (*ProfileWithStages, erro
 		return nil, fmt.Errorf("query stages: %w", err)
 	}
 
-	return &ProfileWithStages{
+	result := &ProfileWithStages{
 		Profile: profile,
 		Stages:  stages,
-	}, nil
+	}
+	result.SortStages(r.stageOrder(profile.WorkflowName))
+	return result, nil
+}
+
+// stageOrder returns the stage sequence declared by the default profile of
+// workflowName, or nil when there is none.
+func (r *repo) stageOrder(workflowName string) []string {
+	if r.defaults == nil {
+		return nil
+	}
+	defaults := r.defaults(workflowName)
+	if defaults == nil {
+		return nil
+	}
+	return defaults.StageNames()
 }
 
 func (r *repo) Create(ctx context.Context, cmd CreateProfileCommand) (*Profile, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
 	q := `
 		INSERT INTO profiles(workflow_name, name, description)
 		VALUES ($1, $2, $3)
@@ -99,6 +127,7 @@ func (r *repo) Create(ctx context.Context, cmd CreateProfileCommand) (*Profile,
 	}
 
 	r.logger.Info("profile created", "id", profile.ID, "workflow", profile.WorkflowName, "name", profile.Name)
+	r.events.Publish(ctx, events.Event{Type: EventCreated, Subject: profile.ID.String(), Data: profile})
 	return &profile, nil
 }
 
@@ -118,6 +147,25 @@ func (r *repo) Update(ctx context.Context, id uuid.UUID, cmd UpdateProfileComman
 	}
 
 	r.logger.Info("profile updated", "id", profile.ID, "name", profile.Name)
+	r.events.Publish(ctx, events.Event{Type: EventUpdated, Subject: profile.ID.String(), Data: profile})
+	return &profile, nil
+}
+
+func (r *repo) Patch(ctx context.Context, id uuid.UUID, cmd PatchProfileCommand) (*Profile, error) {
+	if err := cmd.Validate(); err != nil {
+		return nil, err
+	}
+
+	profile, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Profile, error) {
+		return repository.UpdatePartial(ctx, tx, profileProjection, "ID", id, cmd, scanProfile)
+	})
+
+	if err != nil {
+		return nil, repository.MapError(err, ErrNotFound, ErrDuplicate)
+	}
+
+	r.logger.Info("profile patched", "id", profile.ID, "name", profile.Name)
+	r.events.Publish(ctx, events.Event{Type: EventUpdated, Subject: profile.ID.String(), Data: profile})
 	return &profile, nil
 }
 
@@ -129,6 +177,7 @@ func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	r.logger.Info("profile deleted", "id", id)
+	r.events.Publish(ctx, events.Event{Type: EventDeleted, Subject: id.String()})
 	return nil
 }
 
@@ -168,3 +217,17 @@ func (r *repo) DeleteStage(ctx context.Context, profileID uuid.UUID, stageName s
 	r.logger.Info("stage deleted", "profile_id", profileID, "stage", stageName)
 	return nil
 }
+
+func (r *repo) PreviewStage(ctx context.Context, id uuid.UUID, stage string) (*StagePreview, error) {
+	profile, err := r.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults *ProfileWithStages
+	if r.defaults != nil {
+		defaults = r.defaults(profile.WorkflowName)
+	}
+
+	return PreviewStage(profile, defaults, stage)
+}
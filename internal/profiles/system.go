@@ -14,7 +14,9 @@ type System interface {
 	// List returns a paginated list of profiles with optional filtering.
 	List(ctx context.Context, page pagination.PageRequest, filters Filters) (*pagination.PageResult[Profile], error)
 
-	// Find returns a profile with all its stage configurations.
+	// Find returns a profile with all its stage configurations, ordered by
+	// the stage sequence of the workflow's default profile. Stages the
+	// workflow does not declare follow, sorted by name.
 	Find(ctx context.Context, id uuid.UUID) (*ProfileWithStages, error)
 
 	// Create creates a new profile.
@@ -23,6 +25,10 @@ type System interface {
 	// Update updates profile metadata (name, description).
 	Update(ctx context.Context, id uuid.UUID, cmd UpdateProfileCommand) (*Profile, error)
 
+	// Patch updates only the profile metadata fields set in cmd.
+	// A patch with no fields set returns the profile unchanged.
+	Patch(ctx context.Context, id uuid.UUID, cmd PatchProfileCommand) (*Profile, error)
+
 	// Delete deletes a profile and all its stage configurations.
 	Delete(ctx context.Context, id uuid.UUID) error
 
@@ -31,4 +37,10 @@ type System interface {
 
 	// DeleteStage deletes a stage configuration from a profile.
 	DeleteStage(ctx context.Context, profileID uuid.UUID, stageName string) error
+
+	// PreviewStage resolves a stage of the profile merged over its workflow's
+	// default profile, without executing anything.
+	// Returns ErrNotFound if the profile does not exist and ErrStageNotFound
+	// if neither the profile nor the defaults define the stage.
+	PreviewStage(ctx context.Context, id uuid.UUID, stage string) (*StagePreview, error)
 }
@@ -0,0 +1,28 @@
+package providers
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+)
+
+// NewHTTPClient returns an HTTP client for calls to providers with the dial,
+// TLS handshake, idle pool, and per-request timeouts from cfg. The client
+// keeps its connections pooled, so callers should reuse it.
+func NewHTTPClient(cfg config.ProviderHTTPConfig) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.DialTimeoutDuration()}
+
+	return &http.Client{
+		Timeout: cfg.RequestTimeoutDuration(),
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: cfg.TLSHandshakeTimeoutDuration(),
+			MaxIdleConns:        cfg.MaxIdleConns,
+			MaxIdleConnsPerHost: cfg.MaxIdleConns,
+			IdleConnTimeout:     cfg.IdleConnTimeoutDuration(),
+			ForceAttemptHTTP2:   true,
+		},
+	}
+}
@@ -3,6 +3,9 @@ package providers
 import (
 	"errors"
 	"net/http"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/query"
 )
 
 // Domain errors for the providers system.
@@ -15,8 +18,18 @@ var (
 
 	// ErrInvalidConfig indicates the provider configuration failed validation.
 	ErrInvalidConfig = errors.New("invalid provider config")
+
+	// ErrInUse indicates the provider is referenced by agents and cannot be deleted.
+	ErrInUse = errors.New("provider is referenced by agents")
 )
 
+func init() {
+	handlers.RegisterErrorCode("not_found", ErrNotFound)
+	handlers.RegisterErrorCode("duplicate", ErrDuplicate)
+	handlers.RegisterErrorCode("invalid_config", ErrInvalidConfig)
+	handlers.RegisterErrorCode("in_use", ErrInUse)
+}
+
 func MapHTTPStatus(err error) int {
 	if errors.Is(err, ErrNotFound) {
 		return http.StatusNotFound
@@ -24,7 +37,13 @@ func MapHTTPStatus(err error) int {
 	if errors.Is(err, ErrDuplicate) {
 		return http.StatusConflict
 	}
-	if errors.Is(err, ErrInvalidConfig) {
+	if errors.Is(err, ErrInvalidConfig) || errors.Is(err, handlers.ErrValidation) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, ErrInUse) {
+		return http.StatusConflict
+	}
+	if errors.Is(err, query.ErrInvalidSort) {
 		return http.StatusBadRequest
 	}
 	return http.StatusInternalServerError
@@ -1,9 +1,10 @@
 package providers
 
 import (
-	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
@@ -35,26 +36,53 @@ func (h *Handler) Routes() routes.Group {
 		Description: "Provider configuration management",
 		Routes: []routes.Route{
 			{Method: "GET", Pattern: "", Handler: h.List, OpenAPI: Spec.List},
+			{Method: "GET", Pattern: "/health", Handler: h.Health, OpenAPI: Spec.Health},
 			{Method: "GET", Pattern: "/{id}", Handler: h.Find, OpenAPI: Spec.Find},
 			{Method: "POST", Pattern: "/search", Handler: h.Search, OpenAPI: Spec.Search},
 			{Method: "POST", Pattern: "", Handler: h.Create, OpenAPI: Spec.Create},
 			{Method: "PUT", Pattern: "/{id}", Handler: h.Update, OpenAPI: Spec.Update},
+			{Method: "PUT", Pattern: "/{id}/credentials", Handler: h.RotateCredentials, OpenAPI: Spec.Rotate},
 			{Method: "DELETE", Pattern: "/{id}", Handler: h.Delete, OpenAPI: Spec.Delete},
 		},
 	}
 }
 
 func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	page := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
 	filters := FiltersFromQuery(r.URL.Query())
 
 	result, err := h.sys.List(r.Context(), page, filters)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	pagination.Respond(w, r, result)
+}
+
+func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
+	var refresh bool
+	if v := r.URL.Query().Get("refresh"); v != "" {
+		var err error
+		refresh, err = strconv.ParseBool(v)
+		if err != nil {
+			handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("invalid refresh value %q: must be a boolean", v))
+			return
+		}
+	}
+
+	report, err := h.sys.Health(r.Context(), refresh)
 	if err != nil {
 		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	handlers.RespondJSON(w, http.StatusOK, report)
 }
 
 func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
@@ -70,12 +98,17 @@ func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	handlers.RespondFields(w, r, http.StatusOK, result)
 }
 
 func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 	var page pagination.PageRequest
-	if err := json.NewDecoder(r.Body).Decode(&page); err != nil {
+	if err := handlers.DecodeJSON(w, r, &page, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	if err := page.Normalize(h.pagination); err != nil {
 		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
 		return
 	}
@@ -84,17 +117,17 @@ func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.sys.List(r.Context(), page, filters)
 	if err != nil {
-		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	pagination.Respond(w, r, result)
 }
 
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var cmd CreateCommand
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -115,8 +148,8 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var cmd UpdateCommand
-	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
-		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
 		return
 	}
 
@@ -129,6 +162,29 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	handlers.RespondJSON(w, http.StatusOK, result)
 }
 
+// RotateCredentials handles PUT /api/providers/{id}/credentials.
+// The response carries no body so credentials are never echoed back.
+func (h *Handler) RotateCredentials(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var cmd CredentialsCommand
+	if err := handlers.DecodeJSON(w, r, &cmd, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	if err := h.sys.RotateCredentials(r.Context(), id, cmd); err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
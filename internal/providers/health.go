@@ -0,0 +1,222 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	agtproviders "github.com/JaimeStill/go-agents/pkg/providers"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultHealthTimeout bounds each provider probe.
+	DefaultHealthTimeout = 5 * time.Second
+
+	// DefaultHealthTTL is how long a probe result is reused before the
+	// provider is probed again.
+	DefaultHealthTTL = 30 * time.Second
+)
+
+// HealthStatus is the reachability outcome of a provider probe.
+type HealthStatus string
+
+const (
+	HealthOK          HealthStatus = "ok"
+	HealthUnreachable HealthStatus = "unreachable"
+	HealthAuthFailed  HealthStatus = "auth_failed"
+)
+
+// ProviderHealth is the probe result for a single provider.
+type ProviderHealth struct {
+	ID        uuid.UUID    `json:"id"`
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	LatencyMs int64        `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
+	CheckedAt time.Time    `json:"checked_at"`
+}
+
+// HealthReport aggregates probe results across all configured providers.
+// Healthy is true only when every provider reports HealthOK.
+type HealthReport struct {
+	Healthy   bool             `json:"healthy"`
+	Providers []ProviderHealth `json:"providers"`
+}
+
+// ProbeFunc tests connectivity to a single provider.
+// Implementations must honor ctx cancellation.
+type ProbeFunc func(ctx context.Context, p Provider) ProviderHealth
+
+// TestConnection probes a provider by building its go-agents provider from
+// the stored config and credentials and issuing an authenticated GET against its base URL.
+// Transport failures and 5xx responses report HealthUnreachable,
+// 401 and 403 report HealthAuthFailed, and any other response reports HealthOK.
+func TestConnection(ctx context.Context, client *http.Client, p Provider) (result ProviderHealth) {
+	result = ProviderHealth{ID: p.ID, Name: p.Name}
+	start := time.Now()
+	defer func() {
+		result.LatencyMs = time.Since(start).Milliseconds()
+		result.CheckedAt = time.Now()
+	}()
+
+	fail := func(status HealthStatus, err error) ProviderHealth {
+		result.Status = status
+		result.Error = err.Error()
+		return result
+	}
+
+	cfg, err := p.ProviderConfig()
+	if err != nil {
+		return fail(HealthUnreachable, err)
+	}
+
+	provider, err := agtproviders.Create(cfg)
+	if err != nil {
+		return fail(HealthUnreachable, fmt.Errorf("%w: %v", ErrInvalidConfig, err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.BaseURL(), nil)
+	if err != nil {
+		return fail(HealthUnreachable, err)
+	}
+	provider.SetHeaders(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fail(HealthUnreachable, err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fail(HealthAuthFailed, fmt.Errorf("provider returned %s", resp.Status))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return fail(HealthUnreachable, fmt.Errorf("provider returned %s", resp.Status))
+	}
+
+	result.Status = HealthOK
+	return result
+}
+
+type healthEntry struct {
+	result    ProviderHealth
+	updatedAt time.Time
+	expires   time.Time
+}
+
+// HealthChecker probes providers concurrently and caches results briefly
+// so repeated health requests do not probe on every call. A cached result
+// is discarded early if the provider's configuration has been updated.
+type HealthChecker struct {
+	probe   ProbeFunc
+	timeout time.Duration
+	ttl     time.Duration
+	entries map[uuid.UUID]healthEntry
+	mu      sync.Mutex
+}
+
+// NewHealthChecker creates a HealthChecker that bounds each probe by timeout
+// and reuses results for ttl. Non-positive values fall back to
+// DefaultHealthTimeout and DefaultHealthTTL.
+func NewHealthChecker(probe ProbeFunc, timeout, ttl time.Duration) *HealthChecker {
+	if timeout <= 0 {
+		timeout = DefaultHealthTimeout
+	}
+	if ttl <= 0 {
+		ttl = DefaultHealthTTL
+	}
+	return &HealthChecker{
+		probe:   probe,
+		timeout: timeout,
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]healthEntry),
+	}
+}
+
+// Check returns the health of each provider, probing those without a fresh
+// cached result concurrently. Results are ordered by provider name.
+func (c *HealthChecker) Check(ctx context.Context, providers []Provider) *HealthReport {
+	return c.check(ctx, providers, true)
+}
+
+// Refresh probes every provider regardless of cached results and stores the
+// new results, so subsequent Check calls reuse them.
+func (c *HealthChecker) Refresh(ctx context.Context, providers []Provider) *HealthReport {
+	return c.check(ctx, providers, false)
+}
+
+func (c *HealthChecker) check(ctx context.Context, providers []Provider, useCache bool) *HealthReport {
+	results := make([]ProviderHealth, len(providers))
+
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		if useCache {
+			if cached, ok := c.get(p); ok {
+				results[i] = cached
+				continue
+			}
+		}
+
+		wg.Go(func() {
+			probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			result := c.probe(probeCtx, p)
+			results[i] = result
+			c.set(p, result)
+		})
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	report := &HealthReport{Healthy: true, Providers: results}
+	for _, r := range results {
+		if r.Status != HealthOK {
+			report.Healthy = false
+			break
+		}
+	}
+
+	return report
+}
+
+func (c *HealthChecker) get(p Provider) (ProviderHealth, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[p.ID]
+	if !ok {
+		return ProviderHealth{}, false
+	}
+
+	if time.Now().After(entry.expires) || !entry.updatedAt.Equal(p.UpdatedAt) {
+		delete(c.entries, p.ID)
+		return ProviderHealth{}, false
+	}
+
+	return entry.result, true
+}
+
+func (c *HealthChecker) set(p Provider, result ProviderHealth) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[p.ID] = healthEntry{
+		result:    result,
+		updatedAt: p.UpdatedAt,
+		expires:   now.Add(c.ttl),
+	}
+}
@@ -1,6 +1,8 @@
 package providers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/url"
 
 	"github.com/JaimeStill/agent-lab/pkg/query"
@@ -9,17 +11,27 @@ import (
 
 var projection = query.NewProjectionMap("public", "providers", "p").
 	Project("id", "ID").
-	Project("name", "Name").
+	ProjectText("name", "Name").
 	Project("config", "Config").
+	Project("credentials", "Credentials").
 	Project("created_at", "CreatedAt").
-	Project("updated_at", "UpdatedAt")
+	Project("updated_at", "UpdatedAt").
+	Sortable("ID", "Name", "CreatedAt", "UpdatedAt")
 
 var defaultSort = query.SortField{Field: "Name"}
 
 func scanProvider(s repository.Scanner) (Provider, error) {
 	var p Provider
-	err := s.Scan(&p.ID, &p.Name, &p.Config, &p.CreatedAt, &p.UpdatedAt)
-	return p, err
+	var credentials []byte
+	if err := s.Scan(&p.ID, &p.Name, &p.Config, &credentials, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return p, err
+	}
+	if len(credentials) > 0 {
+		if err := json.Unmarshal(credentials, &p.Credentials); err != nil {
+			return p, fmt.Errorf("decode provider credentials: %w", err)
+		}
+	}
+	return p, nil
 }
 
 type Filters struct {
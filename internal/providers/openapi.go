@@ -5,10 +5,12 @@ import "github.com/JaimeStill/agent-lab/pkg/openapi"
 // spec holds OpenAPI operation definitions for the providers domain.
 type spec struct {
 	List   *openapi.Operation
+	Health *openapi.Operation
 	Find   *openapi.Operation
 	Search *openapi.Operation
 	Create *openapi.Operation
 	Update *openapi.Operation
+	Rotate *openapi.Operation
 	Delete *openapi.Operation
 }
 
@@ -21,11 +23,24 @@ var Spec = spec{
 			openapi.QueryParam("page", "integer", "Page number (1-indexed)", false),
 			openapi.QueryParam("page_size", "integer", "Results per page", false),
 			openapi.QueryParam("search", "string", "Search query (matches name)", false),
-			openapi.QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending", false),
+			openapi.QueryParam("sort", "string", "Comma-separated sort fields. Prefix with - for descending, suffix text fields with :ci for case-insensitive. Unknown fields return 400 listing the sortable fields", false),
 			openapi.QueryParam("name", "string", "Filter by provider name (contains)", false),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Paginated list of providers", "ProviderPageResult"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated list of providers", "ProviderPageResult"), openapi.PageHeaders(true)),
+			400: openapi.ResponseRef("BadRequest"),
+		},
+	},
+	Health: &openapi.Operation{
+		Summary:     "Probe provider health",
+		Description: "Probes every configured provider concurrently with a short timeout and reports per-provider status (ok, unreachable, auth_failed) and latency. Results are cached briefly; refresh=true bypasses the cache and re-probes every provider",
+		Parameters: []*openapi.Parameter{
+			openapi.QueryParam("refresh", "boolean", "Bypass cached results and re-probe every provider", false),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Provider health report", "ProviderHealthReport"),
+			400: openapi.ResponseRef("BadRequest"),
 		},
 	},
 	Find: &openapi.Operation{
@@ -33,6 +48,7 @@ var Spec = spec{
 		Description: "Retrieves a single provider configuration",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Provider UUID"),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
 			200: openapi.ResponseJSON("Provider configuration", "Provider"),
@@ -45,10 +61,11 @@ var Spec = spec{
 		Description: "Search providers with filters and pagination via POST body",
 		Parameters: []*openapi.Parameter{
 			openapi.QueryParam("name", "string", "Filter by provider name (contains)", false),
+			openapi.FieldsParam(),
 		},
 		RequestBody: openapi.RequestBodyJSON("PageRequest", false),
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Paginated search results", "ProviderPageResult"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated search results", "ProviderPageResult"), openapi.PageHeaders(false)),
 			400: openapi.ResponseRef("BadRequest"),
 		},
 	},
@@ -76,15 +93,29 @@ var Spec = spec{
 			409: openapi.ResponseRef("Conflict"),
 		},
 	},
+	Rotate: &openapi.Operation{
+		Summary:     "Rotate provider credentials",
+		Description: "Replaces the stored credentials of a provider. Agents referencing the provider use the new credentials on their next call. Credentials are write-only and never returned",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Provider UUID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("ProviderCredentialsCommand", true),
+		Responses: map[int]*openapi.Response{
+			204: {Description: "Credentials rotated"},
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
 	Delete: &openapi.Operation{
 		Summary:     "Delete provider",
-		Description: "Removes a provider configuration",
+		Description: "Removes a provider configuration. Fails with 409 while agents reference the provider",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Provider UUID"),
 		},
 		Responses: map[int]*openapi.Response{
 			204: {Description: "Provider deleted"},
 			404: openapi.ResponseRef("NotFound"),
+			409: openapi.ResponseRef("Conflict"),
 		},
 	},
 }
@@ -106,8 +137,9 @@ func (spec) Schemas() map[string]*openapi.Schema {
 			Type:     "object",
 			Required: []string{"name", "config"},
 			Properties: map[string]*openapi.Schema{
-				"name":   {Type: "string", Example: "ollama"},
-				"config": {Type: "object", Description: "go-agents ProviderConfig as JSON"},
+				"name":        {Type: "string", Example: "ollama"},
+				"config":      {Type: "object", Description: "go-agents ProviderConfig as JSON"},
+				"credentials": {Type: "object", Description: "Write-only secret provider options (token, api_key) merged into config options at call time"},
 			},
 		},
 		"UpdateProviderCommand": {
@@ -118,6 +150,31 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"config": {Type: "object", Description: "go-agents ProviderConfig as JSON"},
 			},
 		},
+		"ProviderCredentialsCommand": {
+			Type:     "object",
+			Required: []string{"credentials"},
+			Properties: map[string]*openapi.Schema{
+				"credentials": {Type: "object", Description: "Secret provider options keyed by option name, e.g. {\"token\": \"...\"}. Replaces all stored credentials"},
+			},
+		},
+		"ProviderHealth": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"id":         {Type: "string", Format: "uuid"},
+				"name":       {Type: "string"},
+				"status":     {Type: "string", Enum: []any{"ok", "unreachable", "auth_failed"}},
+				"latency_ms": {Type: "integer", Description: "Probe round-trip time in milliseconds"},
+				"error":      {Type: "string", Description: "Probe failure detail"},
+				"checked_at": {Type: "string", Format: "date-time"},
+			},
+		},
+		"ProviderHealthReport": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"healthy":   {Type: "boolean", Description: "True when every provider reports ok"},
+				"providers": {Type: "array", Items: openapi.SchemaRef("ProviderHealth")},
+			},
+		},
 		"ProviderPageResult": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
@@ -2,29 +2,96 @@ package providers
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/redact"
+	agtconfig "github.com/JaimeStill/go-agents/pkg/config"
 	"github.com/google/uuid"
 )
 
 // Provider represents an LLM provider configuration stored in the database.
 // The Config field contains the go-agents ProviderConfig structure as JSON.
+//
+// Credentials holds secret provider options (token, api_key, and similar)
+// stored separately from Config so they can be rotated in one place.
+// Credentials are write-only: they are never serialized in responses.
 type Provider struct {
-	ID        uuid.UUID       `json:"id"`
-	Name      string          `json:"name"`
-	Config    json.RawMessage `json:"config"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID          uuid.UUID         `json:"id"`
+	Name        string            `json:"name"`
+	Config      json.RawMessage   `json:"config"`
+	Credentials map[string]string `json:"-"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// ProviderConfig returns the go-agents provider config with the stored
+// credentials merged into its options. Credentials take precedence over
+// options of the same name in Config. The result contains secrets and must
+// not be serialized in responses.
+func (p Provider) ProviderConfig() (*agtconfig.ProviderConfig, error) {
+	return mergeCredentials(p.Config, p.Credentials)
+}
+
+func mergeCredentials(config json.RawMessage, credentials map[string]string) (*agtconfig.ProviderConfig, error) {
+	var cfg agtconfig.ProviderConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+
+	if len(credentials) > 0 {
+		if cfg.Options == nil {
+			cfg.Options = make(map[string]any, len(credentials))
+		}
+		for k, v := range credentials {
+			cfg.Options[k] = v
+		}
+	}
+
+	return &cfg, nil
 }
 
 // CreateCommand contains the data required to create a new provider.
+// Credentials are optional and write-only.
 type CreateCommand struct {
-	Name   string          `json:"name"`
-	Config json.RawMessage `json:"config"`
+	Name        string            `json:"name"`
+	Config      json.RawMessage   `json:"config"`
+	Credentials map[string]string `json:"credentials,omitempty"`
 }
 
 // UpdateCommand contains the data required to update an existing provider.
+// Stored credentials are kept; use CredentialsCommand to rotate them.
 type UpdateCommand struct {
 	Name   string          `json:"name"`
 	Config json.RawMessage `json:"config"`
 }
+
+// CredentialsCommand replaces the stored credentials of a provider.
+// Keys are provider option names that hold secrets, such as "token" or "api_key".
+type CredentialsCommand struct {
+	Credentials map[string]string `json:"credentials"`
+}
+
+// Validate checks that at least one credential is supplied and that every
+// key names a secret option with a non-empty value.
+func (c CredentialsCommand) Validate() error {
+	var v handlers.ValidationError
+
+	if len(c.Credentials) == 0 {
+		v.Add("credentials", "credentials are required")
+	}
+
+	for k, val := range c.Credentials {
+		if !redact.IsSecret(k) {
+			v.Add("credentials."+k, "not a credential option; set it in config instead")
+			continue
+		}
+		if strings.TrimSpace(val) == "" {
+			v.Add("credentials."+k, "value is required")
+		}
+	}
+
+	return v.Err()
+}
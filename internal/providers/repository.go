@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/JaimeStill/agent-lab/internal/config"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/agent-lab/pkg/repository"
@@ -19,14 +20,22 @@ type repo struct {
 	db         *sql.DB
 	logger     *slog.Logger
 	pagination pagination.Config
+	health     *HealthChecker
 }
 
 // New creates a new providers repository with the given dependencies.
-func New(db *sql.DB, logger *slog.Logger, pagination pagination.Config) System {
+// Health probes use an HTTP client built from httpCfg.
+func New(db *sql.DB, logger *slog.Logger, pagination pagination.Config, httpCfg config.ProviderHTTPConfig) System {
+	client := NewHTTPClient(httpCfg)
+	probe := func(ctx context.Context, p Provider) ProviderHealth {
+		return TestConnection(ctx, client, p)
+	}
+
 	return &repo{
 		db:         db,
 		logger:     logger.With("system", "provider"),
 		pagination: pagination,
+		health:     NewHealthChecker(probe, DefaultHealthTimeout, DefaultHealthTTL),
 	}
 }
 
@@ -42,8 +51,8 @@ func (r *repo) List(ctx context.Context, page pagination.PageRequest, filters Fi
 
 	filters.Apply(qb)
 
-	if len(page.Sort) > 0 {
-		qb.OrderByFields(page.Sort)
+	if err := qb.SortBy(page.Sort); err != nil {
+		return nil, err
 	}
 
 	countSql, countArgs := qb.BuildCount()
@@ -74,17 +83,28 @@ func (r *repo) Find(ctx context.Context, id uuid.UUID) (*Provider, error) {
 }
 
 func (r *repo) Create(ctx context.Context, cmd CreateCommand) (*Provider, error) {
-	if err := r.validateConfig(cmd.Config); err != nil {
+	if len(cmd.Credentials) > 0 {
+		if err := (CredentialsCommand{Credentials: cmd.Credentials}).Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.validateConfig(cmd.Config, cmd.Credentials); err != nil {
+		return nil, err
+	}
+
+	credentials, err := marshalCredentials(cmd.Credentials)
+	if err != nil {
 		return nil, err
 	}
 
 	q := `
-		INSERT INTO providers (name, config)
-		VALUES ($1, $2)
-		RETURNING id, name, config, created_at, updated_at`
+		INSERT INTO providers (name, config, credentials)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, config, credentials, created_at, updated_at`
 
 	p, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Provider, error) {
-		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, cmd.Config}, scanProvider)
+		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, cmd.Config, credentials}, scanProvider)
 	})
 
 	if err != nil {
@@ -96,7 +116,12 @@ func (r *repo) Create(ctx context.Context, cmd CreateCommand) (*Provider, error)
 }
 
 func (r *repo) Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Provider, error) {
-	if err := r.validateConfig(cmd.Config); err != nil {
+	existing, err := r.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.validateConfig(cmd.Config, existing.Credentials); err != nil {
 		return nil, err
 	}
 
@@ -104,7 +129,7 @@ func (r *repo) Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Pr
 		UPDATE providers
 		SET name = $1, config = $2, updated_at = NOW()
 		WHERE id = $3
-		RETURNING id, name, config, created_at, updated_at`
+		RETURNING id, name, config, credentials, created_at, updated_at`
 
 	p, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Provider, error) {
 		return repository.QueryOne(ctx, tx, q, []any{cmd.Name, cmd.Config, id}, scanProvider)
@@ -118,12 +143,57 @@ func (r *repo) Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Pr
 	return &p, nil
 }
 
+func (r *repo) RotateCredentials(ctx context.Context, id uuid.UUID, cmd CredentialsCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := r.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.validateConfig(existing.Config, cmd.Credentials); err != nil {
+		return err
+	}
+
+	credentials, err := marshalCredentials(cmd.Credentials)
+	if err != nil {
+		return err
+	}
+
+	_, err = repository.WithTx(ctx, r.db, func(tx *sql.Tx) (struct{}, error) {
+		err := repository.ExecExpectOne(ctx, tx,
+			"UPDATE providers SET credentials = $1, updated_at = NOW() WHERE id = $2",
+			credentials, id)
+		return struct{}{}, err
+	})
+
+	if err != nil {
+		return repository.MapError(err, ErrNotFound, ErrDuplicate)
+	}
+
+	r.logger.Info("provider credentials rotated", "id", id, "name", existing.Name)
+	return nil
+}
+
+func (r *repo) Resolve(ctx context.Context, id uuid.UUID) (*agtconfig.ProviderConfig, error) {
+	p, err := r.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return p.ProviderConfig()
+}
+
 func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
 	_, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (struct{}, error) {
 		err := repository.ExecExpectOne(ctx, tx, "DELETE FROM providers WHERE id = $1", id)
 		return struct{}{}, err
 	})
 
+	if repository.IsForeignKeyViolation(err) {
+		return ErrInUse
+	}
 	if err != nil {
 		return repository.MapError(err, ErrNotFound, ErrDuplicate)
 	}
@@ -132,14 +202,51 @@ func (r *repo) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (r *repo) validateConfig(config json.RawMessage) error {
-	var cfg agtconfig.ProviderConfig
-	if err := json.Unmarshal(config, &cfg); err != nil {
-		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+func (r *repo) Health(ctx context.Context, refresh bool) (*HealthReport, error) {
+	q, args := query.NewBuilder(projection, defaultSort).Build()
+
+	providers, err := repository.QueryMany(ctx, r.db, q, args, scanProvider)
+	if err != nil {
+		return nil, fmt.Errorf("query providers: %w", err)
 	}
 
-	if _, err := agtproviders.Create(&cfg); err != nil {
+	var report *HealthReport
+	if refresh {
+		report = r.health.Refresh(ctx, providers)
+	} else {
+		report = r.health.Check(ctx, providers)
+	}
+	for _, p := range report.Providers {
+		if p.Status != HealthOK {
+			r.logger.Warn("provider unhealthy", "id", p.ID, "name", p.Name, "status", p.Status, "error", p.Error)
+		}
+	}
+
+	return report, nil
+}
+
+// validateConfig checks that config, with credentials applied, constructs
+// a valid go-agents provider.
+func (r *repo) validateConfig(config json.RawMessage, credentials map[string]string) error {
+	cfg, err := mergeCredentials(config, credentials)
+	if err != nil {
+		return err
+	}
+
+	if _, err := agtproviders.Create(cfg); err != nil {
 		return fmt.Errorf("%w: %v", ErrInvalidConfig, err)
 	}
 	return nil
 }
+
+func marshalCredentials(credentials map[string]string) ([]byte, error) {
+	if credentials == nil {
+		credentials = map[string]string{}
+	}
+
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, fmt.Errorf("encode provider credentials: %w", err)
+	}
+	return data, nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	agtconfig "github.com/JaimeStill/go-agents/pkg/config"
 	"github.com/google/uuid"
 )
 
@@ -33,7 +34,25 @@ type System interface {
 	// Returns ErrInvalidConfig if the configuration fails go-agents validation.
 	Update(ctx context.Context, id uuid.UUID, cmd UpdateCommand) (*Provider, error)
 
+	// RotateCredentials replaces the stored credentials of a provider.
+	// Agents referencing the provider pick up the new credentials on their next call.
+	// Returns ErrNotFound if the provider does not exist.
+	// Returns a validation error if a key is not a credential option or a value is empty.
+	// Returns ErrInvalidConfig if the config with the new credentials fails go-agents validation.
+	RotateCredentials(ctx context.Context, id uuid.UUID, cmd CredentialsCommand) error
+
+	// Resolve returns the go-agents config of a provider with its credentials applied,
+	// for constructing agents that reference it. The result contains secrets.
+	// Returns ErrNotFound if the provider does not exist.
+	Resolve(ctx context.Context, id uuid.UUID) (*agtconfig.ProviderConfig, error)
+
 	// Delete deletes a provider configuration by ID.
 	// Returns ErrNotFound if the provider does not exist.
+	// Returns ErrInUse if agents reference the provider.
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Health probes every configured provider concurrently and reports
+	// per-provider reachability and latency. Results are cached briefly;
+	// refresh bypasses the cache and replaces its entries with new results.
+	Health(ctx context.Context, refresh bool) (*HealthReport, error)
 }
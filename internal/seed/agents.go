@@ -0,0 +1,139 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// AgentSeed is a single agent record in a seed file.
+// ID is optional; when present it is preserved so profile stages that
+// reference the agent remain valid after re-seeding. Provider optionally
+// names the provider record the agent draws rotated credentials from; it is
+// resolved to agents.provider_id by name so the reference survives seeding
+// into a database where the provider has a different ID.
+type AgentSeed struct {
+	ID       *uuid.UUID      `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Provider string          `json:"provider,omitempty"`
+	Config   json.RawMessage `json:"config"`
+}
+
+// AgentSeedData represents the JSON structure for agent seed files.
+type AgentSeedData struct {
+	Agents []AgentSeed `json:"agents"`
+}
+
+// AgentSeeder implements Seeder for agents.
+// It loads seed data from embedded defaults or an external file path.
+type AgentSeeder struct {
+	defaults []byte
+	file     string
+}
+
+// NewAgentSeeder creates a AgentSeeder that falls back to defaults
+// when no external seed file is configured.
+func NewAgentSeeder(defaults []byte) *AgentSeeder {
+	return &AgentSeeder{defaults: defaults}
+}
+
+// Name returns "agents" as the seeder identifier.
+func (s *AgentSeeder) Name() string {
+	return "agents"
+}
+
+// Description returns a human-readable description of this seeder.
+func (s *AgentSeeder) Description() string {
+	return "Seeds agent configurations"
+}
+
+// SetFile configures an external seed file path, overriding the embedded default.
+func (s *AgentSeeder) SetFile(path string) {
+	s.file = path
+}
+
+// Load reads and parses the configured agent seed data.
+func (s *AgentSeeder) Load() (*AgentSeedData, error) {
+	content, err := readSeed(s.file, s.defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	var data AgentSeedData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("parse seed data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// Apply loads agent data and upserts each agent by name.
+func (s *AgentSeeder) Apply(ctx context.Context, db DB, dryRun bool) (Result, error) {
+	var result Result
+
+	data, err := s.Load()
+	if err != nil {
+		return result, err
+	}
+
+	for _, a := range data.Agents {
+		existed, err := exists(ctx, db, `SELECT EXISTS (SELECT 1 FROM agents WHERE name = $1)`, a.Name)
+		if err != nil {
+			return result, fmt.Errorf("check agent %s: %w", a.Name, err)
+		}
+
+		if !dryRun {
+			if err := s.save(ctx, db, a); err != nil {
+				return result, fmt.Errorf("save agent %s: %w", a.Name, err)
+			}
+		}
+
+		result.record(existed)
+	}
+
+	return result, nil
+}
+
+func (s *AgentSeeder) save(ctx context.Context, db DB, a AgentSeed) error {
+	const query = `
+		INSERT INTO agents (id, name, config, provider_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (name) DO UPDATE SET
+			config = EXCLUDED.config,
+			provider_id = EXCLUDED.provider_id,
+			updated_at = NOW()`
+
+	id := uuid.New()
+	if a.ID != nil {
+		id = *a.ID
+	}
+
+	providerID, err := resolveProvider(ctx, db, a.Provider)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, query, id, a.Name, []byte(a.Config), providerID)
+	return err
+}
+
+// resolveProvider returns the ID of the provider named name, or nil when name is empty.
+func resolveProvider(ctx context.Context, db DB, name string) (*uuid.UUID, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	var id uuid.UUID
+	err := db.QueryRowContext(ctx, `SELECT id FROM providers WHERE name = $1`, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("provider %s not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve provider %s: %w", name, err)
+	}
+	return &id, nil
+}
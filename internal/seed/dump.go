@@ -0,0 +1,219 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/google/uuid"
+)
+
+// Seed file names written by Dump, each consumable by the matching seeder's SetFile.
+const (
+	AgentsFile    = "agents.json"
+	ProvidersFile = "providers.json"
+	ProfilesFile  = "profiles.json"
+)
+
+// Snapshot holds the agents, providers, and profiles exported by Dump.
+type Snapshot struct {
+	Agents    []AgentSeed
+	Providers []ProviderSeed
+	Profiles  []profiles.ProfileWithStages
+}
+
+// Dump reads the current database state and writes it to seed files in dir.
+func Dump(ctx context.Context, db *sql.DB, dir string) error {
+	snap, err := ReadSnapshot(ctx, db)
+	if err != nil {
+		return err
+	}
+	return WriteSnapshot(dir, snap)
+}
+
+// ReadSnapshot loads all agents, providers, and profiles with their stages.
+func ReadSnapshot(ctx context.Context, db *sql.DB) (*Snapshot, error) {
+	agents, err := readAgents(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("read agents: %w", err)
+	}
+
+	providers, err := readProviders(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("read providers: %w", err)
+	}
+
+	profileList, err := readProfiles(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("read profiles: %w", err)
+	}
+
+	return &Snapshot{Agents: agents, Providers: providers, Profiles: profileList}, nil
+}
+
+// WriteSnapshot writes snap to seed files in dir, creating dir if needed.
+// Credentials are removed from agent and provider configs before writing.
+// Stored provider credentials are never read into a Snapshot, so rotated
+// credentials must be set again after seeding from a dump.
+func WriteSnapshot(dir string, snap *Snapshot) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create dump directory: %w", err)
+	}
+
+	agents := AgentSeedData{Agents: make([]AgentSeed, len(snap.Agents))}
+	for i, a := range snap.Agents {
+		config, err := RedactConfig(a.Config)
+		if err != nil {
+			return fmt.Errorf("redact agent %s: %w", a.Name, err)
+		}
+		a.Config = config
+		agents.Agents[i] = a
+	}
+
+	providers := ProviderSeedData{Providers: make([]ProviderSeed, len(snap.Providers))}
+	for i, p := range snap.Providers {
+		config, err := RedactConfig(p.Config)
+		if err != nil {
+			return fmt.Errorf("redact provider %s: %w", p.Name, err)
+		}
+		p.Config = config
+		providers.Providers[i] = p
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{AgentsFile, agents},
+		{ProvidersFile, providers},
+		{ProfilesFile, ProfileSeedData{Profiles: snap.Profiles}},
+	}
+
+	for _, f := range files {
+		if err := writeJSON(filepath.Join(dir, f.name), f.data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readProviders loads providers without their stored credentials.
+func readProviders(ctx context.Context, db *sql.DB) ([]ProviderSeed, error) {
+	rows, err := db.QueryContext(ctx, `SELECT id, name, config FROM providers ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var providers []ProviderSeed
+	for rows.Next() {
+		var id uuid.UUID
+		var p ProviderSeed
+		if err := rows.Scan(&id, &p.Name, &p.Config); err != nil {
+			return nil, err
+		}
+		p.ID = &id
+		providers = append(providers, p)
+	}
+	return providers, rows.Err()
+}
+
+// readAgents loads agents with the name of the provider each references.
+func readAgents(ctx context.Context, db *sql.DB) ([]AgentSeed, error) {
+	const query = `
+		SELECT a.id, a.name, p.name, a.config
+		FROM agents a
+		LEFT JOIN providers p ON p.id = a.provider_id
+		ORDER BY a.name`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var agents []AgentSeed
+	for rows.Next() {
+		var id uuid.UUID
+		var provider sql.NullString
+		var a AgentSeed
+		if err := rows.Scan(&id, &a.Name, &provider, &a.Config); err != nil {
+			return nil, err
+		}
+		a.ID = &id
+		a.Provider = provider.String
+		agents = append(agents, a)
+	}
+	return agents, rows.Err()
+}
+
+func readProfiles(ctx context.Context, db *sql.DB) ([]profiles.ProfileWithStages, error) {
+	const profileQuery = `
+		SELECT id, workflow_name, name, description, created_at, updated_at
+		FROM profiles
+		ORDER BY workflow_name, name`
+
+	rows, err := db.QueryContext(ctx, profileQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []profiles.ProfileWithStages
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		var p profiles.ProfileWithStages
+		if err := rows.Scan(&p.ID, &p.WorkflowName, &p.Name, &p.Description, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		p.Stages = []profiles.ProfileStage{}
+		index[p.ID] = len(result)
+		result = append(result, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	const stageQuery = `
+		SELECT profile_id, stage_name, agent_id, system_prompt, options
+		FROM profile_stages
+		ORDER BY profile_id, stage_name`
+
+	stageRows, err := db.QueryContext(ctx, stageQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer stageRows.Close()
+
+	for stageRows.Next() {
+		var s profiles.ProfileStage
+		var opts []byte
+		if err := stageRows.Scan(&s.ProfileID, &s.StageName, &s.AgentID, &s.SystemPrompt, &opts); err != nil {
+			return nil, err
+		}
+		if len(opts) > 0 {
+			s.Options = json.RawMessage(opts)
+		}
+		if i, ok := index[s.ProfileID]; ok {
+			result[i].Stages = append(result[i].Stages, s)
+		}
+	}
+	return result, stageRows.Err()
+}
+
+func writeJSON(path string, data any) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -1,29 +1,30 @@
-package main
+package seed
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/JaimeStill/agent-lab/internal/profiles"
 	"github.com/google/uuid"
 )
 
-func init() {
-	registerSeeder(&ProfileSeeder{})
-}
-
 // ProfileSeedData represents the JSON structure for profile seed files.
 type ProfileSeedData struct {
 	Profiles []profiles.ProfileWithStages `json:"profiles"`
 }
 
 // ProfileSeeder implements Seeder for workflow profiles and their stages.
-// It loads seed data from an embedded file or an external file path.
+// It loads seed data from embedded defaults or an external file path.
 type ProfileSeeder struct {
-	file string
+	defaults []byte
+	file     string
+}
+
+// NewProfileSeeder creates a ProfileSeeder that falls back to defaults
+// when no external seed file is configured.
+func NewProfileSeeder(defaults []byte) *ProfileSeeder {
+	return &ProfileSeeder{defaults: defaults}
 }
 
 // Name returns "profiles" as the seeder identifier.
@@ -41,55 +42,57 @@ func (s *ProfileSeeder) SetFile(path string) {
 	s.file = path
 }
 
-// Seed loads profile data and saves profiles and stages to the database.
-// Uses save semantics (insert or update) for idempotent execution.
-func (s *ProfileSeeder) Seed(ctx context.Context, tx *sql.Tx) error {
-	data, err := s.loadSeedData()
+// Load reads and parses the configured profile seed data.
+func (s *ProfileSeeder) Load() (*ProfileSeedData, error) {
+	content, err := readSeed(s.file, s.defaults)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, p := range data.Profiles {
-		profileID, err := s.saveProfile(ctx, tx, p.Profile)
-		if err != nil {
-			return fmt.Errorf("save profile %s/%s: %w", p.WorkflowName, p.Name, err)
-		}
-
-		for _, stage := range p.Stages {
-			if err := s.saveStage(ctx, tx, profileID, stage); err != nil {
-				return fmt.Errorf("save stage %s for profile %s: %w", stage.StageName, p.Name, err)
-			}
-		}
+	var data ProfileSeedData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("parse seed data: %w", err)
 	}
 
-	return nil
+	return &data, nil
 }
 
-func (s *ProfileSeeder) loadSeedData() (*ProfileSeedData, error) {
-	var content []byte
-	var err error
+// Apply loads profile data and upserts profiles by workflow name and name,
+// saving each profile's stages alongside it. Counts reflect profiles only.
+func (s *ProfileSeeder) Apply(ctx context.Context, db DB, dryRun bool) (Result, error) {
+	var result Result
 
-	if s.file != "" {
-		content, err = os.ReadFile(s.file)
+	data, err := s.Load()
+	if err != nil {
+		return result, err
+	}
+
+	for _, p := range data.Profiles {
+		existed, err := exists(ctx, db, `SELECT EXISTS (SELECT 1 FROM profiles WHERE workflow_name = $1 AND name = $2)`, p.WorkflowName, p.Name)
 		if err != nil {
-			return nil, fmt.Errorf("read seed file: %w", err)
+			return result, fmt.Errorf("check profile %s/%s: %w", p.WorkflowName, p.Name, err)
 		}
-	} else {
-		content, err = seedFiles.ReadFile("seeds/classify_profiles.json")
-		if err != nil {
-			return nil, fmt.Errorf("read embedded seed file: %w", err)
+
+		if !dryRun {
+			profileID, err := s.saveProfile(ctx, db, p.Profile)
+			if err != nil {
+				return result, fmt.Errorf("save profile %s/%s: %w", p.WorkflowName, p.Name, err)
+			}
+
+			for _, stage := range p.Stages {
+				if err := s.saveStage(ctx, db, profileID, stage); err != nil {
+					return result, fmt.Errorf("save stage %s for profile %s: %w", stage.StageName, p.Name, err)
+				}
+			}
 		}
-	}
 
-	var data ProfileSeedData
-	if err := json.Unmarshal(content, &data); err != nil {
-		return nil, fmt.Errorf("parse seed data: %w", err)
+		result.record(existed)
 	}
 
-	return &data, nil
+	return result, nil
 }
 
-func (s *ProfileSeeder) saveProfile(ctx context.Context, tx *sql.Tx, p profiles.Profile) (uuid.UUID, error) {
+func (s *ProfileSeeder) saveProfile(ctx context.Context, db DB, p profiles.Profile) (uuid.UUID, error) {
 	const query = `
 		INSERT INTO profiles (id, workflow_name, name, description, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, NOW(), NOW())
@@ -100,7 +103,7 @@ func (s *ProfileSeeder) saveProfile(ctx context.Context, tx *sql.Tx, p profiles.
 
 	id := uuid.New()
 	var returnedID uuid.UUID
-	err := tx.QueryRowContext(ctx, query, id, p.WorkflowName, p.Name, p.Description).Scan(&returnedID)
+	err := db.QueryRowContext(ctx, query, id, p.WorkflowName, p.Name, p.Description).Scan(&returnedID)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -108,7 +111,7 @@ func (s *ProfileSeeder) saveProfile(ctx context.Context, tx *sql.Tx, p profiles.
 	return returnedID, nil
 }
 
-func (s *ProfileSeeder) saveStage(ctx context.Context, tx *sql.Tx, profileID uuid.UUID, stage profiles.ProfileStage) error {
+func (s *ProfileSeeder) saveStage(ctx context.Context, db DB, profileID uuid.UUID, stage profiles.ProfileStage) error {
 	const query = `
 		INSERT INTO profile_stages (profile_id, stage_name, agent_id, system_prompt, options)
 		VALUES ($1, $2, $3, $4, $5)
@@ -117,6 +120,6 @@ func (s *ProfileSeeder) saveStage(ctx context.Context, tx *sql.Tx, profileID uui
 			system_prompt = EXCLUDED.system_prompt,
 			options = EXCLUDED.options`
 
-	_, err := tx.ExecContext(ctx, query, profileID, stage.StageName, stage.AgentID, stage.SystemPrompt, stage.Options)
+	_, err := db.ExecContext(ctx, query, profileID, stage.StageName, stage.AgentID, stage.SystemPrompt, stage.Options)
 	return err
 }
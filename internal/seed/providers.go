@@ -0,0 +1,109 @@
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ProviderSeed is a single provider record in a seed file.
+// ID is optional; when present it is preserved on insert.
+type ProviderSeed struct {
+	ID     *uuid.UUID      `json:"id,omitempty"`
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config"`
+}
+
+// ProviderSeedData represents the JSON structure for provider seed files.
+type ProviderSeedData struct {
+	Providers []ProviderSeed `json:"providers"`
+}
+
+// ProviderSeeder implements Seeder for providers.
+// It loads seed data from embedded defaults or an external file path.
+type ProviderSeeder struct {
+	defaults []byte
+	file     string
+}
+
+// NewProviderSeeder creates a ProviderSeeder that falls back to defaults
+// when no external seed file is configured.
+func NewProviderSeeder(defaults []byte) *ProviderSeeder {
+	return &ProviderSeeder{defaults: defaults}
+}
+
+// Name returns "providers" as the seeder identifier.
+func (s *ProviderSeeder) Name() string {
+	return "providers"
+}
+
+// Description returns a human-readable description of this seeder.
+func (s *ProviderSeeder) Description() string {
+	return "Seeds LLM provider configurations"
+}
+
+// SetFile configures an external seed file path, overriding the embedded default.
+func (s *ProviderSeeder) SetFile(path string) {
+	s.file = path
+}
+
+// Load reads and parses the configured provider seed data.
+func (s *ProviderSeeder) Load() (*ProviderSeedData, error) {
+	content, err := readSeed(s.file, s.defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	var data ProviderSeedData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("parse seed data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// Apply loads provider data and upserts each provider by name.
+func (s *ProviderSeeder) Apply(ctx context.Context, db DB, dryRun bool) (Result, error) {
+	var result Result
+
+	data, err := s.Load()
+	if err != nil {
+		return result, err
+	}
+
+	for _, p := range data.Providers {
+		existed, err := exists(ctx, db, `SELECT EXISTS (SELECT 1 FROM providers WHERE name = $1)`, p.Name)
+		if err != nil {
+			return result, fmt.Errorf("check provider %s: %w", p.Name, err)
+		}
+
+		if !dryRun {
+			if err := s.save(ctx, db, p); err != nil {
+				return result, fmt.Errorf("save provider %s: %w", p.Name, err)
+			}
+		}
+
+		result.record(existed)
+	}
+
+	return result, nil
+}
+
+func (s *ProviderSeeder) save(ctx context.Context, db DB, p ProviderSeed) error {
+	const query = `
+		INSERT INTO providers (id, name, config, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (name) DO UPDATE SET
+			config = EXCLUDED.config,
+			updated_at = NOW()`
+
+	id := uuid.New()
+	if p.ID != nil {
+		id = *p.ID
+	}
+
+	_, err := db.ExecContext(ctx, query, id, p.Name, []byte(p.Config))
+	return err
+}
@@ -0,0 +1,14 @@
+package seed
+
+import (
+	"encoding/json"
+
+	"github.com/JaimeStill/agent-lab/pkg/redact"
+)
+
+// RedactConfig removes credential fields at any depth from a JSON config.
+// Removing rather than masking keeps dumped files loadable without
+// persisting placeholder credentials.
+func RedactConfig(config json.RawMessage) (json.RawMessage, error) {
+	return redact.Config(config)
+}
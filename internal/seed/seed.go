@@ -0,0 +1,82 @@
+// Package seed provides database seeders for populating agents, providers,
+// and workflow profiles, along with a dump mode that exports the current
+// database state back into seed files.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/JaimeStill/agent-lab/pkg/repository"
+)
+
+// Seeder defines the interface for database seeders.
+// Each seeder is responsible for populating a specific domain's data.
+type Seeder interface {
+	// Name returns the unique identifier for this seeder.
+	Name() string
+
+	// Description returns a human-readable description of what this seeder does.
+	Description() string
+
+	// Apply upserts the seeder's records by natural key and reports how many
+	// were created or updated. When dryRun is true, no writes are made and the
+	// result reports what would change. Callers typically pass a transaction
+	// to get all-or-nothing semantics across multiple seeders.
+	Apply(ctx context.Context, db DB, dryRun bool) (Result, error)
+}
+
+// DB is implemented by *sql.DB and *sql.Tx.
+type DB interface {
+	repository.Querier
+	repository.Executor
+}
+
+// Result reports the records a seeder created and updated.
+type Result struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// String formats the result as "N created, M updated".
+func (r Result) String() string {
+	return fmt.Sprintf("%d created, %d updated", r.Created, r.Updated)
+}
+
+// record counts a single upsert as created or updated based on whether it existed.
+func (r *Result) record(existed bool) {
+	if existed {
+		r.Updated++
+	} else {
+		r.Created++
+	}
+}
+
+// exists reports whether query, a SELECT EXISTS statement, matches a row.
+func exists(ctx context.Context, db DB, query string, args ...any) (bool, error) {
+	var found bool
+	err := db.QueryRowContext(ctx, query, args...).Scan(&found)
+	return found, err
+}
+
+// FileSeeder is a Seeder that can load its data from an external seed file.
+type FileSeeder interface {
+	Seeder
+
+	// SetFile configures an external seed file path, overriding the embedded default.
+	SetFile(path string)
+}
+
+// readSeed returns the contents of file when set, otherwise the embedded defaults.
+func readSeed(file string, defaults []byte) ([]byte, error) {
+	if file == "" {
+		return defaults, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read seed file: %w", err)
+	}
+	return content, nil
+}
@@ -0,0 +1,65 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CancelReason records why a run was cancelled.
+type CancelReason string
+
+// Cancel reason constants.
+const (
+	CancelUser     CancelReason = "user"
+	CancelShutdown CancelReason = "shutdown"
+	CancelTimeout  CancelReason = "timeout"
+)
+
+// ParseCancelReason validates a cancel reason query value.
+// An empty value defaults to CancelUser.
+func ParseCancelReason(value string) (CancelReason, error) {
+	switch CancelReason(value) {
+	case "", CancelUser:
+		return CancelUser, nil
+	case CancelShutdown:
+		return CancelShutdown, nil
+	case CancelTimeout:
+		return CancelTimeout, nil
+	default:
+		return "", fmt.Errorf("%w: %q (expected user, shutdown, or timeout)", ErrInvalidCancelReason, value)
+	}
+}
+
+// cancelCause is the cause attached to a run's context when the executor
+// cancels it, carrying the reason to the code that records the outcome.
+type cancelCause struct {
+	reason CancelReason
+}
+
+func (c *cancelCause) Error() string {
+	return fmt.Sprintf("run cancelled: %s", c.reason)
+}
+
+// cancelRun cancels a run's context with reason as the cause.
+func cancelRun(cancel context.CancelCauseFunc, reason CancelReason) {
+	cancel(&cancelCause{reason: reason})
+}
+
+// cancelReason reports why a run's execution context ended. An explicit
+// cancellation carries its reason as the cause; otherwise an expired
+// deadline is a timeout, the lifecycle context ending is a shutdown, and
+// anything else means the caller went away.
+func (e *executor) cancelReason(ctx context.Context) CancelReason {
+	var cause *cancelCause
+	if errors.As(context.Cause(ctx), &cause) {
+		return cause.reason
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return CancelTimeout
+	}
+	if lc := e.runtime.Lifecycle(); lc != nil && lc.Context().Err() != nil {
+		return CancelShutdown
+	}
+	return CancelUser
+}
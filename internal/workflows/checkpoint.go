@@ -10,6 +10,7 @@ import (
 	"log/slog"
 
 	"github.com/JaimeStill/agent-lab/pkg/repository"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/observability"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
 )
 
@@ -30,6 +31,8 @@ func NewPostgresCheckpointStore(db *sql.DB, logger *slog.Logger) *PostgresCheckp
 
 // Save persists workflow state to the database. It uses save semantics,
 // creating a new checkpoint or updating an existing one for the same run_id.
+// The state is also recorded as the checkpoint for its node, replacing any
+// earlier checkpoint of that node, so runs can later resume from it.
 func (s *PostgresCheckpointStore) Save(st state.State) error {
 	stateData, err := json.Marshal(st)
 	if err != nil {
@@ -45,13 +48,22 @@ func (s *PostgresCheckpointStore) Save(st state.State) error {
 			updated_at = NOW()
 	`
 
-	_, err = s.db.ExecContext(
-		context.Background(),
-		query,
-		st.RunID,
-		stateData,
-		st.CheckpointNode,
-	)
+	const historyQuery = `
+		INSERT INTO checkpoint_history (run_id, checkpoint_node, state_data, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (run_id, checkpoint_node) DO UPDATE SET
+			state_data = EXCLUDED.state_data,
+			updated_at = NOW()
+	`
+
+	ctx := context.Background()
+	_, err = repository.WithTx(ctx, s.db, func(tx *sql.Tx) (struct{}, error) {
+		if _, err := tx.ExecContext(ctx, query, st.RunID, stateData, st.CheckpointNode); err != nil {
+			return struct{}{}, err
+		}
+		_, err := tx.ExecContext(ctx, historyQuery, st.RunID, st.CheckpointNode, stateData)
+		return struct{}{}, err
+	})
 
 	if err != nil {
 		return fmt.Errorf("save checkpoint: %w", err)
@@ -64,7 +76,7 @@ func (s *PostgresCheckpointStore) Save(st state.State) error {
 // Load retrieves workflow state from the database by run ID.
 // Returns an error if the checkpoint is not found.
 func (s *PostgresCheckpointStore) Load(runID string) (state.State, error) {
-	const query = `SELECT state_data FROM checkpoints WHERE run_id = $1`
+	const query = `SELECT c.state_data FROM checkpoints c WHERE c.run_id = $1`
 
 	var stateData []byte
 	err := s.db.QueryRowContext(context.Background(), query, runID).Scan(&stateData)
@@ -75,20 +87,48 @@ func (s *PostgresCheckpointStore) Load(runID string) (state.State, error) {
 		return state.State{}, fmt.Errorf("query checkpoint: %w", err)
 	}
 
-	var st state.State
-	if err := json.Unmarshal(stateData, &st); err != nil {
-		return state.State{}, fmt.Errorf("unmarshal state: %w", err)
+	st, err := decodeState(stateData)
+	if err != nil {
+		return state.State{}, err
 	}
 
 	s.logger.Debug("checkpoint loaded", "run_id", runID)
 	return st, nil
 }
 
-// Delete deletes a checkpoint from the database by run ID.
-func (s *PostgresCheckpointStore) Delete(runID string) error {
-	const query = `DELETE FROM checkpoints WHERE run_id = $1`
+// LoadNode retrieves the most recent state checkpointed after node completed
+// for the given run. Returns ErrCheckpointNotFound if the node has no checkpoint.
+func (s *PostgresCheckpointStore) LoadNode(runID, node string) (state.State, error) {
+	const query = `SELECT h.state_data FROM checkpoint_history h WHERE h.run_id = $1 AND h.checkpoint_node = $2`
 
-	_, err := s.db.ExecContext(context.Background(), query, runID)
+	var stateData []byte
+	err := s.db.QueryRowContext(context.Background(), query, runID, node).Scan(&stateData)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return state.State{}, fmt.Errorf("%w: %s", ErrCheckpointNotFound, node)
+		}
+		return state.State{}, fmt.Errorf("query checkpoint: %w", err)
+	}
+
+	st, err := decodeState(stateData)
+	if err != nil {
+		return state.State{}, err
+	}
+
+	s.logger.Debug("node checkpoint loaded", "run_id", runID, "node", node)
+	return st, nil
+}
+
+// Delete deletes a checkpoint and its per-node history from the database by run ID.
+func (s *PostgresCheckpointStore) Delete(runID string) error {
+	ctx := context.Background()
+	_, err := repository.WithTx(ctx, s.db, func(tx *sql.Tx) (struct{}, error) {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM checkpoint_history WHERE run_id = $1`, runID); err != nil {
+			return struct{}{}, err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM checkpoints WHERE run_id = $1`, runID)
+		return struct{}{}, err
+	})
 	if err != nil {
 		return fmt.Errorf("delete checkpoint: %w", err)
 	}
@@ -112,3 +152,25 @@ func (s *PostgresCheckpointStore) List() ([]string, error) {
 
 	return ids, nil
 }
+
+// decodeState unmarshals checkpointed state. The observer is not persisted,
+// so a no-op observer is attached to keep the restored state usable.
+func decodeState(data []byte) (state.State, error) {
+	var st state.State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return state.State{}, fmt.Errorf("unmarshal state: %w", err)
+	}
+	st.Observer = observability.NoOpObserver{}
+	return st, nil
+}
+
+// nodeCheckpointStore resumes from a preloaded checkpoint instead of the
+// run's latest one. Saves and deletes pass through to the underlying store.
+type nodeCheckpointStore struct {
+	*PostgresCheckpointStore
+	from state.State
+}
+
+func (s *nodeCheckpointStore) Load(runID string) (state.State, error) {
+	return s.from, nil
+}
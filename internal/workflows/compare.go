@@ -0,0 +1,73 @@
+package workflows
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Comparer diffs the stored results of two completed runs of the same
+// workflow. Workflows register a Comparer via RegisterComparer to provide
+// a type-aware comparison.
+type Comparer interface {
+	Compare(a, b *Run) (any, error)
+}
+
+// RunComparison is the generic comparison returned for workflows without a
+// registered Comparer. ResultsEqual reports whether the two stored results
+// are semantically equal JSON.
+type RunComparison struct {
+	WorkflowName string          `json:"workflow_name"`
+	RunA         uuid.UUID       `json:"run_a"`
+	RunB         uuid.UUID       `json:"run_b"`
+	ResultsEqual bool            `json:"results_equal"`
+	ResultA      json.RawMessage `json:"result_a,omitempty"`
+	ResultB      json.RawMessage `json:"result_b,omitempty"`
+}
+
+// CompareRuns compares two completed runs of the same workflow using the
+// Comparer registered for it, or a RunComparison if none is registered.
+// Returns ErrRunsNotComparable if the runs belong to different workflows
+// and ErrRunNotCompleted if either run has not completed.
+func CompareRuns(a, b *Run) (any, error) {
+	if a.WorkflowName != b.WorkflowName {
+		return nil, fmt.Errorf("%w: run %s is %s but run %s is %s",
+			ErrRunsNotComparable, a.ID, a.WorkflowName, b.ID, b.WorkflowName)
+	}
+
+	for _, run := range []*Run{a, b} {
+		if run.Status != StatusCompleted {
+			return nil, fmt.Errorf("%w: run %s is %s", ErrRunNotCompleted, run.ID, run.Status)
+		}
+	}
+
+	if comparer, ok := GetComparer(a.WorkflowName); ok {
+		return comparer.Compare(a, b)
+	}
+
+	return RunComparison{
+		WorkflowName: a.WorkflowName,
+		RunA:         a.ID,
+		RunB:         b.ID,
+		ResultsEqual: jsonEqual(a.Result, b.Result),
+		ResultA:      a.Result,
+		ResultB:      b.Result,
+	}, nil
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+
+	ca, _ := json.Marshal(va)
+	cb, _ := json.Marshal(vb)
+	return bytes.Equal(ca, cb)
+}
@@ -0,0 +1,121 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+)
+
+// errDescribeOnly is returned by a describing graph asked to run.
+var errDescribeOnly = errors.New("graph records structure only and cannot execute")
+
+// WorkflowGraph describes the structure of a registered workflow: its nodes
+// and edges in the order the factory adds them, its entry and exit points,
+// and the schema of the parameters it accepts.
+type WorkflowGraph struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Nodes       []string        `json:"nodes"`
+	Edges       []GraphEdge     `json:"edges"`
+	EntryPoint  string          `json:"entry_point"`
+	ExitPoints  []string        `json:"exit_points"`
+	Params      *openapi.Schema `json:"params,omitempty"`
+}
+
+// GraphEdge is a transition between two nodes. Conditional edges are taken
+// only when their predicate holds; Label and Reason come from
+// AddLabeledEdge when the workflow labels the edge.
+type GraphEdge struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Conditional bool   `json:"conditional"`
+	Label       string `json:"label,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Describe builds the graph of a registered workflow by running its factory
+// against a graph that records structure instead of executing. The factory
+// receives runtime and no params, so it sees the workflow's default profile.
+// Returns ErrWorkflowNotFound for unknown names.
+func Describe(ctx context.Context, name string, runtime *Runtime) (*WorkflowGraph, error) {
+	factory, exists := Get(name)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowNotFound, name)
+	}
+
+	g := &describeGraph{name: name}
+	if _, err := factory(ctx, g, runtime, map[string]any{}); err != nil {
+		return nil, fmt.Errorf("describe workflow %s: %w", name, err)
+	}
+
+	registry.mu.RLock()
+	info := registry.info[name]
+	params := registry.params[name]
+	registry.mu.RUnlock()
+
+	return &WorkflowGraph{
+		Name:        name,
+		Description: info.Description,
+		Nodes:       g.nodes,
+		Edges:       g.edges,
+		EntryPoint:  g.entry,
+		ExitPoints:  g.exits,
+		Params:      params,
+	}, nil
+}
+
+// describeGraph is a state.StateGraph that records the nodes, edges, and
+// entry and exit points a workflow factory declares.
+type describeGraph struct {
+	name  string
+	nodes []string
+	edges []GraphEdge
+	entry string
+	exits []string
+}
+
+func (g *describeGraph) Name() string {
+	return g.name
+}
+
+func (g *describeGraph) AddNode(name string, node state.StateNode) error {
+	g.nodes = append(g.nodes, name)
+	return nil
+}
+
+func (g *describeGraph) AddEdge(from, to string, predicate state.TransitionPredicate) error {
+	g.edges = append(g.edges, GraphEdge{From: from, To: to, Conditional: predicate != nil})
+	return nil
+}
+
+func (g *describeGraph) SetEntryPoint(node string) error {
+	g.entry = node
+	return nil
+}
+
+func (g *describeGraph) SetExitPoint(node string) error {
+	g.exits = append(g.exits, node)
+	return nil
+}
+
+func (g *describeGraph) Execute(ctx context.Context, initialState state.State) (state.State, error) {
+	return initialState, errDescribeOnly
+}
+
+func (g *describeGraph) Resume(ctx context.Context, runID string) (state.State, error) {
+	return state.State{}, errDescribeOnly
+}
+
+// LabelEdge attaches label to the most recently added edge from -> to.
+func (g *describeGraph) LabelEdge(from, to string, label EdgeLabel) {
+	for i := len(g.edges) - 1; i >= 0; i-- {
+		if g.edges[i].From == from && g.edges[i].To == to {
+			g.edges[i].Label = label.Name
+			g.edges[i].Reason = label.Reason
+			return
+		}
+	}
+}
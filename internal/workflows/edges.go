@@ -0,0 +1,39 @@
+package workflows
+
+import "github.com/JaimeStill/go-agents-orchestration/pkg/state"
+
+// EdgeLabel names an edge's predicate and explains in plain terms why the
+// transition is taken. Labels are recorded on the Decision for each traversal.
+type EdgeLabel struct {
+	Name   string
+	Reason string
+}
+
+// EdgeLabeler is implemented by graphs that record edge labels.
+type EdgeLabeler interface {
+	LabelEdge(from, to string, label EdgeLabel)
+}
+
+// AddLabeledEdge adds an edge to graph and attaches label to it when graph
+// records labels. On graphs that do not, it behaves like graph.AddEdge.
+func AddLabeledEdge(graph state.StateGraph, from, to string, predicate state.TransitionPredicate, label EdgeLabel) error {
+	if err := graph.AddEdge(from, to, predicate); err != nil {
+		return err
+	}
+
+	if labeler, ok := graph.(EdgeLabeler); ok {
+		labeler.LabelEdge(from, to, label)
+	}
+	return nil
+}
+
+// labeledGraph forwards edge labels added through AddLabeledEdge to the
+// observer that persists decisions for the run.
+type labeledGraph struct {
+	state.StateGraph
+	labeler EdgeLabeler
+}
+
+func (g *labeledGraph) LabelEdge(from, to string, label EdgeLabel) {
+	g.labeler.LabelEdge(from, to, label)
+}
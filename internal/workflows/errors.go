@@ -3,6 +3,9 @@ package workflows
 import (
 	"errors"
 	"net/http"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/query"
 )
 
 // Domain errors for the workflows package.
@@ -10,8 +13,48 @@ var (
 	ErrNotFound         = errors.New("not found")
 	ErrWorkflowNotFound = errors.New("workflow not registered")
 	ErrInvalidStatus    = errors.New("invalid status transition")
+	ErrDraining         = errors.New("workflow system is draining")
+
+	ErrInvalidCancelReason = errors.New("invalid cancel reason")
+
+	ErrIncompleteProfile = errors.New("incomplete profile")
+	ErrInvalidProfile    = errors.New("invalid profile")
+
+	ErrInvalidNode        = errors.New("invalid workflow node")
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+	ErrInvalidReportFormat = errors.New("invalid report format")
+	ErrReportUnsupported   = errors.New("report format not supported for workflow")
+	ErrReportUnavailable   = errors.New("run has no reportable result")
+
+	ErrInvalidTraceFormat = errors.New("invalid trace format")
+	ErrInvalidFacet       = errors.New("invalid facet field")
+
+	ErrInvalidComparison = errors.New("invalid run comparison")
+	ErrRunsNotComparable = errors.New("runs are not comparable")
+	ErrRunNotCompleted   = errors.New("run has not completed")
 )
 
+func init() {
+	handlers.RegisterErrorCode("not_found", ErrNotFound)
+	handlers.RegisterErrorCode("workflow_not_found", ErrWorkflowNotFound)
+	handlers.RegisterErrorCode("invalid_status", ErrInvalidStatus)
+	handlers.RegisterErrorCode("draining", ErrDraining)
+	handlers.RegisterErrorCode("invalid_cancel_reason", ErrInvalidCancelReason)
+	handlers.RegisterErrorCode("incomplete_profile", ErrIncompleteProfile)
+	handlers.RegisterErrorCode("invalid_profile", ErrInvalidProfile)
+	handlers.RegisterErrorCode("invalid_node", ErrInvalidNode)
+	handlers.RegisterErrorCode("checkpoint_not_found", ErrCheckpointNotFound)
+	handlers.RegisterErrorCode("invalid_report_format", ErrInvalidReportFormat)
+	handlers.RegisterErrorCode("report_unsupported", ErrReportUnsupported)
+	handlers.RegisterErrorCode("report_unavailable", ErrReportUnavailable)
+	handlers.RegisterErrorCode("invalid_trace_format", ErrInvalidTraceFormat)
+	handlers.RegisterErrorCode("invalid_facet", ErrInvalidFacet)
+	handlers.RegisterErrorCode("invalid_comparison", ErrInvalidComparison)
+	handlers.RegisterErrorCode("runs_not_comparable", ErrRunsNotComparable)
+	handlers.RegisterErrorCode("run_not_completed", ErrRunNotCompleted)
+}
+
 // MapHTTPStatus maps domain errors to HTTP status codes.
 func MapHTTPStatus(err error) int {
 	switch {
@@ -21,6 +64,36 @@ func MapHTTPStatus(err error) int {
 		return http.StatusNotFound
 	case errors.Is(err, ErrInvalidStatus):
 		return http.StatusBadRequest
+	case errors.Is(err, ErrDraining):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrInvalidCancelReason):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrIncompleteProfile):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidProfile):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidNode):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrCheckpointNotFound):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidReportFormat):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrReportUnsupported):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, ErrReportUnavailable):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidTraceFormat):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidFacet):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidComparison):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrRunsNotComparable):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrRunNotCompleted):
+		return http.StatusConflict
+	case errors.Is(err, query.ErrInvalidSort):
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
@@ -4,11 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/pkg/events"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/config"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/observability"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
@@ -17,46 +24,110 @@ import (
 
 const defaultStreamBufferSize = 100
 
+// cancelledMessage is the error message recorded on cancelled runs; the
+// reason is recorded separately in CancelReason.
+const cancelledMessage = "execution cancelled"
+
 type executor struct {
-	repo       *repo
-	runtime    *Runtime
-	db         *sql.DB
-	logger     *slog.Logger
-	activeRuns map[uuid.UUID]context.CancelFunc
-	mu         sync.RWMutex
+	repo          *repo
+	runtime       *Runtime
+	db            *sql.DB
+	events        *events.Bus
+	logger        *slog.Logger
+	maxConcurrent int
+	activeRuns    map[uuid.UUID]context.CancelCauseFunc
+	streams       map[uuid.UUID]*StreamingObserver
+	streamRetry   time.Duration
+	queue         runQueue
+	queueSeq      uint64
+	running       int
+	runsWg        sync.WaitGroup
+	draining      bool
+	mu            sync.RWMutex
 }
 
 // NewSystem creates a new workflows System with the provided dependencies.
 // The System handles workflow execution, cancellation, and resumption.
+// Run lifecycle events are published to bus, which may be nil.
+// Executions beyond maxConcurrent wait in a priority queue; a non-positive
+// maxConcurrent runs every execution immediately. streamRetry is sent to
+// run event stream clients as the SSE retry hint; zero omits it.
 func NewSystem(
 	runtime *Runtime,
 	db *sql.DB,
+	bus *events.Bus,
 	logger *slog.Logger,
 	pagination pagination.Config,
+	maxConcurrent int,
+	streamRetry time.Duration,
 ) System {
 	return &executor{
-		repo:       New(db, logger, pagination),
-		runtime:    runtime,
-		db:         db,
-		logger:     logger.With("system", "workflows"),
-		activeRuns: make(map[uuid.UUID]context.CancelFunc),
+		repo:          New(db, logger, pagination),
+		runtime:       runtime,
+		db:            db,
+		events:        bus,
+		logger:        logger.With("system", "workflows"),
+		maxConcurrent: maxConcurrent,
+		activeRuns:    make(map[uuid.UUID]context.CancelCauseFunc),
+		streams:       make(map[uuid.UUID]*StreamingObserver),
+		streamRetry:   streamRetry,
 	}
 }
 
 func (e *executor) Handler() *Handler {
-	return NewHandler(e, e.logger, e.repo.pagination)
+	return NewHandler(e, e.logger, e.repo.pagination, e.streamRetry)
 }
 
 func (e *executor) ListRuns(ctx context.Context, page pagination.PageRequest, filters RunFilters) (*pagination.PageResult[Run], error) {
 	return e.repo.ListRuns(ctx, page, filters)
 }
 
+func (e *executor) EachRun(ctx context.Context, filters RunFilters, sort []query.SortField, fn func(Run) error) error {
+	return e.repo.EachRun(ctx, filters, sort, fn)
+}
+
+func (e *executor) RunFacets(ctx context.Context, field string, filters RunFilters) (*RunFacets, error) {
+	return e.repo.RunFacets(ctx, field, filters)
+}
+
 func (e *executor) FindRun(ctx context.Context, id uuid.UUID) (*Run, error) {
 	return e.repo.FindRun(ctx, id)
 }
 
-func (e *executor) GetStages(ctx context.Context, runID uuid.UUID) ([]Stage, error) {
-	return e.repo.GetStages(ctx, runID)
+func (e *executor) ListStages(ctx context.Context, runID uuid.UUID, page pagination.PageRequest, filters StageFilters) (*pagination.PageResult[Stage], error) {
+	return e.repo.ListStages(ctx, runID, page, filters)
+}
+
+func (e *executor) GetStages(ctx context.Context, runID uuid.UUID, filters StageFilters) ([]Stage, error) {
+	return e.repo.GetStages(ctx, runID, filters)
+}
+
+func (e *executor) ListDecisions(ctx context.Context, runID uuid.UUID, page pagination.PageRequest) (*pagination.PageResult[Decision], error) {
+	return e.repo.ListDecisions(ctx, runID, page)
+}
+
+// ActiveRuns returns the IDs of runs currently executing in this process.
+func (e *executor) ActiveRuns() []uuid.UUID {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	ids := make([]uuid.UUID, 0, len(e.activeRuns))
+	for id := range e.activeRuns {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// ListActiveRuns returns the run records for runs currently executing in this process.
+func (e *executor) ListActiveRuns(ctx context.Context) ([]Run, error) {
+	return e.repo.FindRuns(ctx, e.ActiveRuns())
+}
+
+// QueuedRuns returns the runs waiting for an execution slot in dispatch order.
+func (e *executor) QueuedRuns() []QueuedRun {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.queue.snapshot()
 }
 
 func (e *executor) GetDecisions(ctx context.Context, runID uuid.UUID) ([]Decision, error) {
@@ -71,30 +142,102 @@ func (e *executor) ListWorkflows() []WorkflowInfo {
 	return List()
 }
 
-func (e *executor) Execute(name string, params map[string]any, token string) (<-chan ExecutionEvent, *Run, error) {
+func (e *executor) DescribeWorkflow(ctx context.Context, name string) (*WorkflowGraph, error) {
+	return Describe(ctx, name, e.runtime)
+}
+
+// Execute creates a run and queues it for execution. Queued runs dispatch as
+// execution slots free, highest priority first and in arrival order within a
+// priority.
+func (e *executor) Execute(name string, params map[string]any, token string, priority int) (<-chan ExecutionEvent, *Run, error) {
+	return e.execute(name, params, token, priority, nil)
+}
+
+// Replay queues a new run of a finished run's workflow with the same params,
+// starting from the beginning rather than a checkpoint. The new run records
+// the original in ReplayedFrom. Runs still pending or running return
+// ErrInvalidStatus.
+func (e *executor) Replay(ctx context.Context, runID uuid.UUID, token string, priority int) (<-chan ExecutionEvent, *Run, error) {
+	run, err := e.repo.FindRun(ctx, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if run.Status == StatusPending || run.Status == StatusRunning {
+		return nil, nil, ErrInvalidStatus
+	}
+
+	var params map[string]any
+	if run.Params != nil {
+		if err := json.Unmarshal(run.Params, &params); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal params: %w", err)
+		}
+	}
+
+	return e.execute(run.WorkflowName, params, token, priority, &run.ID)
+}
+
+func (e *executor) execute(name string, params map[string]any, token string, priority int, replayedFrom *uuid.UUID) (<-chan ExecutionEvent, *Run, error) {
+	if err := e.acquire(); err != nil {
+		return nil, nil, err
+	}
+
 	factory, exists := Get(name)
 	if !exists {
+		e.runsWg.Done()
 		return nil, nil, ErrWorkflowNotFound
 	}
 
 	ctx := e.runtime.Lifecycle().Context()
 
-	run, err := e.repo.CreateRun(ctx, name, params)
+	if err := e.checkProfile(ctx, name, params); err != nil {
+		e.runsWg.Done()
+		return nil, nil, err
+	}
+
+	run, err := e.repo.CreateRun(ctx, name, params, replayedFrom)
 	if err != nil {
+		e.runsWg.Done()
 		return nil, nil, fmt.Errorf("create run: %w", err)
 	}
 
 	streamingObs := NewStreamingObserver(defaultStreamBufferSize)
 
-	go e.executeAsync(ctx, run.ID, factory, params, token, streamingObs)
+	e.mu.Lock()
+	e.streams[run.ID] = streamingObs
+	e.mu.Unlock()
+
+	e.enqueue(&pendingRun{
+		QueuedRun: QueuedRun{
+			RunID:        run.ID,
+			WorkflowName: name,
+			Priority:     priority,
+			QueuedAt:     time.Now(),
+		},
+		factory:  factory,
+		params:   params,
+		token:    token,
+		observer: streamingObs,
+	})
 
 	return streamingObs.Events(), run, nil
 }
 
-func (e *executor) Cancel(ctx context.Context, runID uuid.UUID) error {
-	e.mu.RLock()
+// Cancel stops an executing run, or removes a queued run, and records it as
+// cancelled for reason.
+func (e *executor) Cancel(ctx context.Context, runID uuid.UUID, reason CancelReason) error {
+	e.mu.Lock()
 	cancel, exists := e.activeRuns[runID]
-	e.mu.RUnlock()
+	var queued *pendingRun
+	if !exists {
+		queued = e.queue.remove(runID)
+	}
+	e.mu.Unlock()
+
+	if queued != nil {
+		e.cancelPending(queued, reason)
+		return nil
+	}
 
 	if !exists {
 		run, err := e.repo.FindRun(ctx, runID)
@@ -107,11 +250,44 @@ func (e *executor) Cancel(ctx context.Context, runID uuid.UUID) error {
 		return ErrNotFound
 	}
 
-	cancel()
+	cancelRun(cancel, reason)
 	return nil
 }
 
-func (e *executor) Resume(ctx context.Context, runID uuid.UUID) (*Run, error) {
+// CancelAll cancels every run executing or queued in this process and returns
+// how many were cancelled. Runs are cancelled under the lock that guards
+// tracking, so a run that finishes concurrently is either cancelled while
+// still executing or already untracked and skipped; it is never counted after
+// completing. The queue is emptied under the same lock, so no queued run is
+// dispatched once CancelAll begins. Runs are recorded with CancelUser.
+func (e *executor) CancelAll() int {
+	e.mu.Lock()
+	queued := e.queue.drain()
+	for id, cancel := range e.activeRuns {
+		e.logger.Warn("cancelling run", "id", id, "reason", "cancel all")
+		cancelRun(cancel, CancelUser)
+	}
+	n := len(e.activeRuns)
+	e.mu.Unlock()
+
+	for _, item := range queued {
+		e.logger.Warn("cancelling queued run", "id", item.RunID, "reason", "cancel all")
+		e.cancelPending(item, CancelUser)
+	}
+	return n + len(queued)
+}
+
+// Resume restarts a failed or cancelled run from its latest checkpoint, or,
+// when fromNode is set, from the checkpoint saved after that node completed.
+// An unknown fromNode returns ErrInvalidNode listing the graph's nodes; a node
+// that never checkpointed returns ErrCheckpointNotFound. Both are checked
+// before the run is marked running.
+func (e *executor) Resume(ctx context.Context, runID uuid.UUID, fromNode string) (*Run, error) {
+	if err := e.acquire(); err != nil {
+		return nil, err
+	}
+	defer e.runsWg.Done()
+
 	run, err := e.repo.FindRun(ctx, runID)
 	if err != nil {
 		return nil, err
@@ -133,17 +309,31 @@ func (e *executor) Resume(ctx context.Context, runID uuid.UUID) (*Run, error) {
 		}
 	}
 
-	execCtx, cancel := context.WithCancel(ctx)
+	if err := e.checkProfile(ctx, run.WorkflowName, params); err != nil {
+		return nil, err
+	}
+
+	postgresStore := NewPostgresCheckpointStore(e.db, e.logger)
+	var checkpointStore state.CheckpointStore = postgresStore
+
+	if fromNode != "" {
+		from, err := e.loadNodeCheckpoint(ctx, run, factory, params, postgresStore, fromNode)
+		if err != nil {
+			return nil, err
+		}
+		checkpointStore = &nodeCheckpointStore{PostgresCheckpointStore: postgresStore, from: from}
+	}
+
+	execCtx, cancel := context.WithCancelCause(ctx)
 	e.trackRun(run.ID, cancel)
 	defer e.untrackRun(run.ID)
 
-	run, err = e.repo.UpdateRunStarted(execCtx, run.ID)
+	run, err = e.startRun(execCtx, run.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	observer := NewPostgresObserver(e.db, run.ID, e.logger)
-	checkpointStore := NewPostgresCheckpointStore(e.db, e.logger)
 
 	cfg := workflowGraphConfig(run.WorkflowName)
 
@@ -152,32 +342,91 @@ func (e *executor) Resume(ctx context.Context, runID uuid.UUID) (*Run, error) {
 		return e.finalizeRun(ctx, run.ID, StatusFailed, nil, err)
 	}
 
-	_, err = factory(execCtx, graph, e.runtime, params)
+	_, err = factory(execCtx, &labeledGraph{StateGraph: graph, labeler: observer}, e.runtime, params)
 	if err != nil {
 		return e.finalizeRun(ctx, run.ID, StatusFailed, nil, err)
 	}
 
 	finalState, err := graph.Resume(execCtx, run.ID.String())
+	e.untrackRun(run.ID)
 	if err != nil {
 		if execCtx.Err() != nil {
-			errMsg := "execution cancelled"
-			return e.repo.UpdateRunCompleted(ctx, run.ID, StatusCancelled, nil, &errMsg)
+			return e.cancelledRun(context.WithoutCancel(ctx), run.ID, e.cancelReason(execCtx))
 		}
 		errMsg := err.Error()
-		return e.repo.UpdateRunCompleted(ctx, run.ID, StatusFailed, nil, &errMsg)
+		return e.completeRun(ctx, run.ID, StatusFailed, nil, &errMsg)
+	}
+
+	return e.completeRun(ctx, run.ID, StatusCompleted, finalState.Data, nil)
+}
+
+// checkProfile resolves the profile a run of the named workflow will use and
+// verifies every registered agent stage has an agent. Workflows that did not
+// register their stages are not checked. A profile_id that does not resolve
+// to a stored profile is left for the workflow factory to report when the run
+// executes; any other load failure is returned.
+func (e *executor) checkProfile(ctx context.Context, name string, params map[string]any) error {
+	stages, defaultProfile, ok := GetAgentStages(name)
+	if !ok {
+		return nil
+	}
+
+	profile, err := LoadProfile(ctx, e.runtime, params, defaultProfile())
+	if err != nil {
+		if errors.Is(err, profiles.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("load profile: %w", err)
 	}
 
-	return e.repo.UpdateRunCompleted(ctx, run.ID, StatusCompleted, finalState.Data, nil)
+	return ValidateProfile(profile, params, stages)
+}
+
+// loadNodeCheckpoint validates node against the run's workflow graph and
+// returns the state checkpointed after it. The factory is invoked against a
+// throwaway graph to discover the node names.
+func (e *executor) loadNodeCheckpoint(ctx context.Context, run *Run, factory WorkflowFactory, params map[string]any, store *PostgresCheckpointStore, node string) (state.State, error) {
+	graph, err := state.NewGraphWithDeps(workflowGraphConfig(run.WorkflowName), observability.NoOpObserver{}, nil)
+	if err != nil {
+		return state.State{}, err
+	}
+
+	recorder := &nodeRecorder{StateGraph: graph}
+	if _, err := factory(ctx, recorder, e.runtime, params); err != nil {
+		return state.State{}, err
+	}
+
+	if !slices.Contains(recorder.nodes, node) {
+		return state.State{}, fmt.Errorf("%w: %q (available: %s)", ErrInvalidNode, node, strings.Join(recorder.nodes, ", "))
+	}
+
+	return store.LoadNode(run.ID.String(), node)
+}
+
+// nodeRecorder records the names of nodes added to the wrapped graph.
+type nodeRecorder struct {
+	state.StateGraph
+	nodes []string
+}
+
+func (g *nodeRecorder) AddNode(name string, node state.StateNode) error {
+	if err := g.StateGraph.AddNode(name, node); err != nil {
+		return err
+	}
+	g.nodes = append(g.nodes, name)
+	return nil
 }
 
 func (e *executor) executeAsync(ctx context.Context, runID uuid.UUID, factory WorkflowFactory, params map[string]any, token string, streamingObs *StreamingObserver) {
-	defer streamingObs.Close()
+	defer e.closeStream(runID, streamingObs)
 
-	execCtx, cancel := context.WithCancel(ctx)
+	execCtx, cancel := context.WithCancelCause(ctx)
 	e.trackRun(runID, cancel)
 	defer e.untrackRun(runID)
 
-	_, err := e.repo.UpdateRunStarted(execCtx, runID)
+	execCtx = WithProgress(execCtx, streamingObs.SendProgress)
+
+	_, err := e.startRun(execCtx, runID)
 	if err != nil {
 		streamingObs.SendError(err, "")
 		e.finalizeRun(execCtx, runID, StatusFailed, nil, err)
@@ -197,7 +446,7 @@ func (e *executor) executeAsync(ctx context.Context, runID uuid.UUID, factory Wo
 		return
 	}
 
-	initialState, err := factory(execCtx, graph, e.runtime, params)
+	initialState, err := factory(execCtx, &labeledGraph{StateGraph: graph, labeler: postgresObs}, e.runtime, params)
 	if err != nil {
 		streamingObs.SendError(err, "")
 		e.finalizeRun(execCtx, runID, StatusFailed, nil, err)
@@ -210,24 +459,133 @@ func (e *executor) executeAsync(ctx context.Context, runID uuid.UUID, factory Wo
 	}
 
 	finalState, err := graph.Execute(execCtx, initialState)
+	e.untrackRun(runID)
 	if err != nil {
 		if execCtx.Err() != nil {
-			errMsg := "execution cancelled"
-			streamingObs.SendError(fmt.Errorf("%s", errMsg), "")
-			e.repo.UpdateRunCompleted(execCtx, runID, StatusCancelled, nil, &errMsg)
+			reason := e.cancelReason(execCtx)
+			streamingObs.SendError(fmt.Errorf("%s: %s", cancelledMessage, reason), "")
+			e.cancelledRun(context.WithoutCancel(execCtx), runID, reason)
 			return
 		}
 		streamingObs.SendError(err, "")
 		errMsg := err.Error()
-		e.repo.UpdateRunCompleted(execCtx, runID, StatusFailed, nil, &errMsg)
+		e.completeRun(execCtx, runID, StatusFailed, nil, &errMsg)
 		return
 	}
 
 	streamingObs.SendComplete(finalState.Data)
-	e.repo.UpdateRunCompleted(execCtx, runID, StatusCompleted, finalState.Data, nil)
+	e.completeRun(context.WithoutCancel(execCtx), runID, StatusCompleted, finalState.Data, nil)
+}
+
+// Drain stops accepting new executions and waits for active and queued runs
+// to finish. Runs still active or queued when ctx expires are cancelled and
+// recorded as cancelled with CancelShutdown; Drain returns once those records
+// are written, so the database must stay open until it does.
+func (e *executor) Drain(ctx context.Context) error {
+	e.mu.Lock()
+	e.draining = true
+	active := len(e.activeRuns)
+	queued := e.queue.Len()
+	e.mu.Unlock()
+
+	e.logger.Info("draining workflow runs", "active", active, "queued", queued)
+
+	done := make(chan struct{})
+	go func() {
+		e.runsWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		e.logger.Info("workflow runs drained")
+		return nil
+	case <-ctx.Done():
+	}
+
+	e.mu.Lock()
+	pending := e.queue.drain()
+	for id, cancel := range e.activeRuns {
+		e.logger.Warn("cancelling run exceeding drain deadline", "id", id)
+		cancelRun(cancel, CancelShutdown)
+	}
+	e.mu.Unlock()
+
+	for _, item := range pending {
+		e.logger.Warn("cancelling queued run exceeding drain deadline", "id", item.RunID)
+		e.cancelPending(item, CancelShutdown)
+	}
+
+	<-done
+	return ctx.Err()
+}
+
+// enqueue adds a run to the queue and dispatches any runs that fit.
+func (e *executor) enqueue(item *pendingRun) {
+	e.mu.Lock()
+	item.seq = e.queueSeq
+	e.queueSeq++
+	e.queue.push(item)
+	e.mu.Unlock()
+
+	e.dispatch()
+}
+
+// dispatch starts queued runs, highest priority first, while execution slots
+// are free.
+func (e *executor) dispatch() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for e.queue.Len() > 0 && (e.maxConcurrent <= 0 || e.running < e.maxConcurrent) {
+		item := e.queue.pop()
+		e.running++
+		go e.runPending(item)
+	}
+}
+
+// runPending executes a dispatched run, then frees its slot for the next
+// queued run.
+func (e *executor) runPending(item *pendingRun) {
+	defer e.runsWg.Done()
+	defer func() {
+		e.mu.Lock()
+		e.running--
+		e.mu.Unlock()
+		e.dispatch()
+	}()
+
+	ctx := e.runtime.Lifecycle().Context()
+	e.executeAsync(ctx, item.RunID, item.factory, item.params, item.token, item.observer)
+}
+
+// cancelPending records a run removed from the queue as cancelled for reason
+// and ends its event stream.
+func (e *executor) cancelPending(item *pendingRun, reason CancelReason) {
+	defer e.runsWg.Done()
+	defer e.closeStream(item.RunID, item.observer)
+
+	item.observer.SendError(fmt.Errorf("%s: %s", cancelledMessage, reason), "")
+
+	ctx := context.WithoutCancel(e.runtime.Lifecycle().Context())
+	if _, err := e.cancelledRun(ctx, item.RunID, reason); err != nil {
+		e.logger.Error("failed to cancel queued run", "id", item.RunID, "error", err)
+	}
 }
 
-func (e *executor) trackRun(id uuid.UUID, cancel context.CancelFunc) {
+func (e *executor) acquire() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.draining {
+		return ErrDraining
+	}
+
+	e.runsWg.Add(1)
+	return nil
+}
+
+func (e *executor) trackRun(id uuid.UUID, cancel context.CancelCauseFunc) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.activeRuns[id] = cancel
@@ -239,15 +597,66 @@ func (e *executor) untrackRun(id uuid.UUID) {
 	delete(e.activeRuns, id)
 }
 
+// closeStream stops tracking a run's event stream and closes it.
+func (e *executor) closeStream(id uuid.UUID, obs *StreamingObserver) {
+	e.mu.Lock()
+	delete(e.streams, id)
+	e.mu.Unlock()
+	obs.Close()
+}
+
 func (e *executor) finalizeRun(ctx context.Context, id uuid.UUID, status RunStatus, result map[string]any, err error) (*Run, error) {
 	errMsg := err.Error()
-	run, updateErr := e.repo.UpdateRunCompleted(ctx, id, status, result, &errMsg)
+	run, updateErr := e.completeRun(ctx, id, status, result, &errMsg)
 	if updateErr != nil {
 		e.logger.Error("failed to finalize run", "id", id, "error", updateErr)
 	}
 	return run, err
 }
 
+// startRun marks a run as running and publishes RunEventStarted.
+func (e *executor) startRun(ctx context.Context, id uuid.UUID) (*Run, error) {
+	run, err := e.repo.UpdateRunStarted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	e.events.Publish(ctx, events.Event{Type: RunEventStarted, Subject: id.String(), Data: *run})
+	return run, nil
+}
+
+// completeRun records a run's final status and publishes the matching run event.
+func (e *executor) completeRun(ctx context.Context, id uuid.UUID, status RunStatus, result map[string]any, errorMsg *string) (*Run, error) {
+	run, err := e.repo.UpdateRunCompleted(ctx, id, status, result, errorMsg, nil)
+	if err != nil {
+		return nil, err
+	}
+	e.events.Publish(ctx, events.Event{Type: runEventType(status), Subject: id.String(), Data: *run})
+	return run, nil
+}
+
+// cancelledRun records a run as cancelled for reason and publishes
+// RunEventCancelled.
+func (e *executor) cancelledRun(ctx context.Context, id uuid.UUID, reason CancelReason) (*Run, error) {
+	errMsg := cancelledMessage
+	run, err := e.repo.UpdateRunCompleted(ctx, id, StatusCancelled, nil, &errMsg, &reason)
+	if err != nil {
+		return nil, err
+	}
+	e.events.Publish(ctx, events.Event{Type: RunEventCancelled, Subject: id.String(), Data: *run})
+	return run, nil
+}
+
+func runEventType(status RunStatus) string {
+	switch status {
+	case StatusCompleted:
+		return RunEventCompleted
+	case StatusCancelled:
+		return RunEventCancelled
+	default:
+		return RunEventFailed
+	}
+}
+
 func workflowGraphConfig(name string) config.GraphConfig {
 	cfg := config.DefaultGraphConfig(name)
 	cfg.Checkpoint.Interval = 1
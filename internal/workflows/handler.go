@@ -5,32 +5,52 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/agent-lab/pkg/routes"
 	"github.com/google/uuid"
 )
 
+// ServerTimeHeader carries the server time of a runs list response as an
+// RFC 3339 timestamp, for use as the updated_after cursor of the next poll.
+const ServerTimeHeader = "X-Server-Time"
+
 // ExecuteRequest represents the request body for workflow execution.
+// Runs with a higher Priority dispatch first when executions are queued.
 type ExecuteRequest struct {
-	Params map[string]any `json:"params,omitempty"`
-	Token  string         `json:"token,omitempty"`
+	Params   map[string]any `json:"params,omitempty"`
+	Token    string         `json:"token,omitempty"`
+	Priority int            `json:"priority,omitempty"`
+}
+
+// ReplayRequest represents the request body for replaying a run. Params are
+// taken from the original run; the token is not persisted, so it must be
+// supplied again.
+type ReplayRequest struct {
+	Token    string `json:"token,omitempty"`
+	Priority int    `json:"priority,omitempty"`
 }
 
 // Handler provides HTTP handlers for workflow operations.
 type Handler struct {
-	sys        System
-	logger     *slog.Logger
-	pagination pagination.Config
+	sys         System
+	logger      *slog.Logger
+	pagination  pagination.Config
+	streamRetry time.Duration
 }
 
-// NewHandler creates a Handler with the provided dependencies.
-func NewHandler(sys System, logger *slog.Logger, pagination pagination.Config) *Handler {
+// NewHandler creates a Handler with the provided dependencies. Event streams
+// advertise streamRetry as the SSE reconnection delay; zero omits it.
+func NewHandler(sys System, logger *slog.Logger, pagination pagination.Config, streamRetry time.Duration) *Handler {
 	return &Handler{
-		sys:        sys,
-		logger:     logger,
-		pagination: pagination,
+		sys:         sys,
+		logger:      logger,
+		pagination:  pagination,
+		streamRetry: streamRetry,
 	}
 }
 
@@ -42,6 +62,7 @@ func (h *Handler) Routes() routes.Group {
 		Description: "Workflow execution and management",
 		Routes: []routes.Route{
 			{Method: "GET", Pattern: "", Handler: h.ListWorkflows, OpenAPI: Spec.ListWorkflows},
+			{Method: "GET", Pattern: "/{name}", Handler: h.DescribeWorkflow, OpenAPI: Spec.DescribeWorkflow},
 			{Method: "POST", Pattern: "/{name}/execute", Handler: h.Execute, OpenAPI: Spec.Execute},
 		},
 		Children: []routes.Group{
@@ -51,12 +72,21 @@ func (h *Handler) Routes() routes.Group {
 				Description: "Workflow run inspection and control",
 				Routes: []routes.Route{
 					{Method: "GET", Pattern: "", Handler: h.ListRuns, OpenAPI: Spec.ListRuns},
+					{Method: "GET", Pattern: "/active", Handler: h.ListActiveRuns, OpenAPI: Spec.ListActiveRuns},
+					{Method: "GET", Pattern: "/queued", Handler: h.ListQueued, OpenAPI: Spec.ListQueued},
+					{Method: "GET", Pattern: "/facets", Handler: h.RunFacets, OpenAPI: Spec.RunFacets},
+					{Method: "GET", Pattern: "/compare", Handler: h.CompareRuns, OpenAPI: Spec.CompareRuns},
+					{Method: "POST", Pattern: "/cancel-all", Handler: h.CancelAll, OpenAPI: Spec.CancelAll},
 					{Method: "GET", Pattern: "/{id}", Handler: h.FindRun, OpenAPI: Spec.FindRun},
 					{Method: "GET", Pattern: "/{id}/stages", Handler: h.GetStages, OpenAPI: Spec.GetStages},
 					{Method: "GET", Pattern: "/{id}/decisions", Handler: h.GetDecisions, OpenAPI: Spec.GetDecisions},
+					{Method: "GET", Pattern: "/{id}/report", Handler: h.GetReport, OpenAPI: Spec.GetReport},
+					{Method: "GET", Pattern: "/{id}/trace", Handler: h.GetTrace, OpenAPI: Spec.GetTrace},
+					{Method: "GET", Pattern: "/{id}/events", Handler: h.StreamRun, OpenAPI: Spec.StreamRun},
 					{Method: "DELETE", Pattern: "/{id}", Handler: h.DeleteRun, OpenAPI: Spec.DeleteRun},
 					{Method: "POST", Pattern: "/{id}/cancel", Handler: h.Cancel, OpenAPI: Spec.Cancel},
 					{Method: "POST", Pattern: "/{id}/resume", Handler: h.Resume, OpenAPI: Spec.Resume},
+					{Method: "POST", Pattern: "/{id}/replay", Handler: h.Replay, OpenAPI: Spec.Replay},
 				},
 			},
 		},
@@ -68,27 +98,107 @@ func (h *Handler) ListWorkflows(w http.ResponseWriter, r *http.Request) {
 	handlers.RespondJSON(w, http.StatusOK, workflows)
 }
 
+// DescribeWorkflow handles GET /{name} - returns the workflow's stage graph
+// and parameter schema.
+func (h *Handler) DescribeWorkflow(w http.ResponseWriter, r *http.Request) {
+	graph, err := h.sys.DescribeWorkflow(r.Context(), r.PathValue("name"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, graph)
+}
+
+// Execute starts a workflow run and streams its events as named SSE events.
+// Whether the run completes or fails, the stream ends with a [DONE] sentinel
+// after the final complete or error event.
 func (h *Handler) Execute(w http.ResponseWriter, r *http.Request) {
 	name := r.PathValue("name")
 
 	var req ExecuteRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := handlers.DecodeJSON(w, r, &req, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	events, run, err := h.sys.Execute(name, req.Params, req.Token, req.Priority)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	h.streamEvents(w, r, run, events)
+}
+
+// Replay handles POST /runs/{id}/replay - queues a new run with the original
+// run's workflow and params and streams its events like Execute.
+func (h *Handler) Replay(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
 		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
 		return
 	}
 
-	events, run, err := h.sys.Execute(name, req.Params, req.Token)
+	var req ReplayRequest
+	if err := handlers.DecodeJSON(w, r, &req, handlers.DefaultMaxBodySize); err != nil {
+		handlers.RespondError(w, h.logger, handlers.DecodeStatus(err), err)
+		return
+	}
+
+	events, run, err := h.sys.Replay(r.Context(), id, req.Token, req.Priority)
 	if err != nil {
 		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
+	h.streamEvents(w, r, run, events)
+}
+
+// StreamRun handles GET /runs/{id}/events - streams a run's events like
+// Execute for clients reconnecting to it. Events up to the ID in the
+// Last-Event-ID header are skipped; the rest are replayed from the run's
+// stages and decisions, followed by its live events while it runs in this
+// process.
+func (h *Handler) StreamRun(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	var lastEventID int
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		lastEventID, err = strconv.Atoi(v)
+		if err != nil {
+			handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("invalid Last-Event-ID: %w", err))
+			return
+		}
+	}
+
+	events, run, err := h.sys.StreamRun(r.Context(), id, lastEventID)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	h.streamEvents(w, r, run, events)
+}
+
+// streamEvents writes a run's events as named SSE events, ending with a
+// [DONE] sentinel once the stream closes. Numbered events carry their ID in
+// the id field, and the stream opens with the configured retry hint.
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request, run *Run, events <-chan ExecutionEvent) {
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Run-ID", run.ID.String())
 	w.WriteHeader(http.StatusOK)
 
+	if h.streamRetry > 0 {
+		fmt.Fprintf(w, "retry: %d\n\n", h.streamRetry.Milliseconds())
+	}
+
 	if f, ok := w.(http.Flusher); ok {
 		f.Flush()
 	}
@@ -106,6 +216,9 @@ func (h *Handler) Execute(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
+		if event.ID != 0 {
+			fmt.Fprintf(w, "id: %d\n", event.ID)
+		}
 		fmt.Fprintf(w, "event: %s\n", event.Type)
 		fmt.Fprintf(w, "data: %s\n\n", data)
 
@@ -113,19 +226,85 @@ func (h *Handler) Execute(w http.ResponseWriter, r *http.Request) {
 			f.Flush()
 		}
 	}
+
+	fmt.Fprintf(w, "data: [DONE]\n\n")
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
 }
 
+// ListRuns handles GET /runs. Requests for CSV (format=csv or Accept:
+// text/csv) or NDJSON (format=ndjson or Accept: application/x-ndjson)
+// stream every matching run instead of a single page.
+//
+// Every response carries the server time taken before the query in the
+// ServerTimeHeader, which polling clients pass back as updated_after to
+// fetch only the runs changed since.
 func (h *Handler) ListRuns(w http.ResponseWriter, r *http.Request) {
-	page := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	w.Header().Set(ServerTimeHeader, time.Now().UTC().Format(time.RFC3339Nano))
+
+	if handlers.WantsNDJSON(r) {
+		filters := RunFiltersFromQuery(r.URL.Query())
+		sort := query.ParseSortFields(r.URL.Query().Get("sort"))
+
+		handlers.RespondNDJSON(w, h.logger, func(enc *handlers.NDJSONEncoder[Run]) error {
+			return h.sys.EachRun(r.Context(), filters, sort, enc.Encode)
+		})
+		return
+	}
+
+	if handlers.WantsCSV(r) {
+		filters := RunFiltersFromQuery(r.URL.Query())
+		sort := query.ParseSortFields(r.URL.Query().Get("sort"))
+
+		handlers.RespondCSV(w, h.logger, "workflow-runs.csv", func(enc *handlers.CSVEncoder[Run]) error {
+			return h.sys.EachRun(r.Context(), filters, sort, enc.Encode)
+		})
+		return
+	}
+
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
 	filters := RunFiltersFromQuery(r.URL.Query())
 
 	result, err := h.sys.ListRuns(r.Context(), page, filters)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	pagination.Respond(w, r, result)
+}
+
+func (h *Handler) ListActiveRuns(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.sys.ListActiveRuns(r.Context())
 	if err != nil {
 		handlers.RespondError(w, h.logger, http.StatusInternalServerError, err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, result)
+	handlers.RespondJSON(w, http.StatusOK, runs)
+}
+
+func (h *Handler) ListQueued(w http.ResponseWriter, r *http.Request) {
+	handlers.RespondJSON(w, http.StatusOK, h.sys.QueuedRuns())
+}
+
+func (h *Handler) RunFacets(w http.ResponseWriter, r *http.Request) {
+	field := r.URL.Query().Get("field")
+	filters := RunFiltersFromQuery(r.URL.Query())
+
+	facets, err := h.sys.RunFacets(r.Context(), field, filters)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, facets)
 }
 
 func (h *Handler) FindRun(w http.ResponseWriter, r *http.Request) {
@@ -151,13 +330,32 @@ func (h *Handler) GetStages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	stages, err := h.sys.GetStages(r.Context(), id)
+	filters := StageFiltersFromQuery(r.URL.Query())
+
+	if allRequested(r) {
+		stages, err := h.sys.GetStages(r.Context(), id, filters)
+		if err != nil {
+			handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+			return
+		}
+
+		pagination.Respond(w, r, singlePage(stages))
+		return
+	}
+
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.sys.ListStages(r.Context(), id, page, filters)
 	if err != nil {
 		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, stages)
+	pagination.Respond(w, r, result)
 }
 
 func (h *Handler) GetDecisions(w http.ResponseWriter, r *http.Request) {
@@ -167,13 +365,162 @@ func (h *Handler) GetDecisions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if allRequested(r) {
+		decisions, err := h.sys.GetDecisions(r.Context(), id)
+		if err != nil {
+			handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+			return
+		}
+
+		pagination.Respond(w, r, singlePage(decisions))
+		return
+	}
+
+	page, err := pagination.PageRequestFromQuery(r.URL.Query(), h.pagination)
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := h.sys.ListDecisions(r.Context(), id, page)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	pagination.Respond(w, r, result)
+}
+
+func (h *Handler) GetReport(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	format, err := ParseReportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	run, err := h.sys.FindRun(r.Context(), id)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	report, err := RenderReport(run, format)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	if md, ok := report.(string); ok {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(md))
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, report)
+}
+
+// GetTrace returns a run with all of its stages and decisions in chronological
+// order, as one JSON document or, with format=ndjson, one entry per line.
+// snapshots=false omits stage input and output snapshots.
+func (h *Handler) GetTrace(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, http.StatusBadRequest, err)
+		return
+	}
+
+	query := r.URL.Query()
+
+	format, err := ParseTraceFormat(query.Get("format"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	snapshots := true
+	if v := query.Get("snapshots"); v != "" {
+		snapshots, err = strconv.ParseBool(v)
+		if err != nil {
+			handlers.RespondError(w, h.logger, http.StatusBadRequest, fmt.Errorf("invalid snapshots value %q: must be a boolean", v))
+			return
+		}
+	}
+
+	run, err := h.sys.FindRun(r.Context(), id)
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	stages, err := h.sys.GetStages(r.Context(), id, StageFilters{})
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
 	decisions, err := h.sys.GetDecisions(r.Context(), id)
 	if err != nil {
 		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
 
-	handlers.RespondJSON(w, http.StatusOK, decisions)
+	trace := BuildTrace(run, stages, decisions, snapshots)
+
+	if format == TraceJSON {
+		handlers.RespondJSON(w, http.StatusOK, trace)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+	for _, entry := range trace.Entries() {
+		if err := enc.Encode(entry); err != nil {
+			h.logger.Error("failed to write trace entry", "run_id", id, "error", err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *Handler) CompareRuns(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var runs [2]*Run
+	for i, param := range []string{"a", "b"} {
+		id, err := uuid.Parse(query.Get(param))
+		if err != nil {
+			err = fmt.Errorf("%w: query parameter %q must be a run ID", ErrInvalidComparison, param)
+			handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+			return
+		}
+
+		run, err := h.sys.FindRun(r.Context(), id)
+		if err != nil {
+			handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+			return
+		}
+		runs[i] = run
+	}
+
+	comparison, err := CompareRuns(runs[0], runs[1])
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	handlers.RespondJSON(w, http.StatusOK, comparison)
 }
 
 func (h *Handler) Cancel(w http.ResponseWriter, r *http.Request) {
@@ -183,7 +530,13 @@ func (h *Handler) Cancel(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.sys.Cancel(r.Context(), id); err != nil {
+	reason, err := ParseCancelReason(r.URL.Query().Get("reason"))
+	if err != nil {
+		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
+		return
+	}
+
+	if err := h.sys.Cancel(r.Context(), id, reason); err != nil {
 		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
 	}
@@ -191,6 +544,14 @@ func (h *Handler) Cancel(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// CancelAll cancels every run executing in this server process.
+func (h *Handler) CancelAll(w http.ResponseWriter, r *http.Request) {
+	cancelled := h.sys.CancelAll()
+
+	h.logger.Warn("cancelled all active runs", "cancelled", cancelled)
+	handlers.RespondJSON(w, http.StatusOK, CancelAllResult{Cancelled: cancelled})
+}
+
 func (h *Handler) Resume(w http.ResponseWriter, r *http.Request) {
 	id, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
@@ -198,7 +559,7 @@ func (h *Handler) Resume(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	run, err := h.sys.Resume(r.Context(), id)
+	run, err := h.sys.Resume(r.Context(), id, r.URL.Query().Get("from_node"))
 	if err != nil {
 		handlers.RespondError(w, h.logger, MapHTTPStatus(err), err)
 		return
@@ -222,3 +583,15 @@ func (h *Handler) DeleteRun(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// allRequested reports whether the client asked to bypass pagination with ?all=true.
+func allRequested(r *http.Request) bool {
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	return all
+}
+
+// singlePage wraps an unpaginated result set in the paginated response shape.
+func singlePage[T any](items []T) *pagination.PageResult[T] {
+	result := pagination.NewPageResult(items, len(items), 1, max(len(items), 1))
+	return &result
+}
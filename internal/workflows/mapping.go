@@ -1,7 +1,9 @@
 package workflows
 
 import (
+	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/JaimeStill/agent-lab/pkg/repository"
@@ -9,20 +11,66 @@ import (
 
 var runProjection = query.NewProjectionMap("public", "runs", "r").
 	Project("id", "ID").
-	Project("workflow_name", "WorkflowName").
-	Project("status", "Status").
+	ProjectText("workflow_name", "WorkflowName").
+	ProjectText("status", "Status").
 	Project("params", "Params").
 	Project("result", "Result").
-	Project("error_message", "ErrorMessage").
+	ProjectText("error_message", "ErrorMessage").
+	ProjectText("cancel_reason", "CancelReason").
+	Project("replayed_from", "ReplayedFrom").
 	Project("started_at", "StartedAt").
 	Project("completed_at", "CompletedAt").
 	Project("created_at", "CreatedAt").
-	Project("updated_at", "UpdatedAt")
+	Project("updated_at", "UpdatedAt").
+	Sortable("ID", "WorkflowName", "Status", "CancelReason", "StartedAt", "CompletedAt", "CreatedAt", "UpdatedAt").
+	Filterable("WorkflowName", "Status")
+
+// runFacetFields allowlists the run fields exposed for faceting, keyed by
+// the query value and mapped to the projection view name. Each must also be
+// filterable in runProjection.
+var runFacetFields = map[string]string{
+	"workflow_name": "WorkflowName",
+	"status":        "Status",
+}
 
 var runDefaultSort = query.SortField{Field: "CreatedAt", Descending: true}
 var stageDefaultSort = query.SortField{Field: "CreatedAt", Descending: false}
 var decisionDefaultSort = query.SortField{Field: "CreatedAt", Descending: false}
 
+// runSortValue returns run's value for a sortable runProjection field, used
+// as the keyset cursor when EachRun resumes after run. Unset fields are nil.
+func runSortValue(run Run, field string) any {
+	switch field {
+	case "ID":
+		return run.ID
+	case "WorkflowName":
+		return run.WorkflowName
+	case "Status":
+		return string(run.Status)
+	case "CancelReason":
+		if run.CancelReason == nil {
+			return nil
+		}
+		return string(*run.CancelReason)
+	case "StartedAt":
+		if run.StartedAt == nil {
+			return nil
+		}
+		return *run.StartedAt
+	case "CompletedAt":
+		if run.CompletedAt == nil {
+			return nil
+		}
+		return *run.CompletedAt
+	case "CreatedAt":
+		return run.CreatedAt
+	case "UpdatedAt":
+		return run.UpdatedAt
+	default:
+		panic(fmt.Sprintf("workflows: no sort value for run field %q", field))
+	}
+}
+
 func scanRun(s repository.Scanner) (Run, error) {
 	var r Run
 	var params, result *[]byte
@@ -33,6 +81,8 @@ func scanRun(s repository.Scanner) (Run, error) {
 		&params,
 		&result,
 		&r.ErrorMessage,
+		&r.CancelReason,
+		&r.ReplayedFrom,
 		&r.StartedAt,
 		&r.CompletedAt,
 		&r.CreatedAt,
@@ -53,14 +103,15 @@ func scanRun(s repository.Scanner) (Run, error) {
 var stageProjection = query.NewProjectionMap("public", "stages", "s").
 	Project("id", "ID").
 	Project("run_id", "RunID").
-	Project("node_name", "NodeName").
+	ProjectText("node_name", "NodeName").
 	Project("iteration", "Iteration").
-	Project("status", "Status").
+	ProjectText("status", "Status").
 	Project("input_snapshot", "InputSnapshot").
 	Project("output_snapshot", "OutputSnapshot").
 	Project("duration_ms", "DurationMs").
-	Project("error_message", "ErrorMessage").
-	Project("created_at", "CreatedAt")
+	ProjectText("error_message", "ErrorMessage").
+	Project("created_at", "CreatedAt").
+	Sortable("ID", "NodeName", "Iteration", "Status", "DurationMs", "CreatedAt")
 
 func scanStage(s repository.Scanner) (Stage, error) {
 	var st Stage
@@ -93,12 +144,13 @@ func scanStage(s repository.Scanner) (Stage, error) {
 var decisionProjection = query.NewProjectionMap("public", "decisions", "d").
 	Project("id", "ID").
 	Project("run_id", "RunID").
-	Project("from_node", "FromNode").
-	Project("to_node", "ToNode").
-	Project("predicate_name", "PredicateName").
+	ProjectText("from_node", "FromNode").
+	ProjectText("to_node", "ToNode").
+	ProjectText("predicate_name", "PredicateName").
 	Project("predicate_result", "PredicateResult").
-	Project("reason", "Reason").
-	Project("created_at", "CreatedAt")
+	ProjectText("reason", "Reason").
+	Project("created_at", "CreatedAt").
+	Sortable("ID", "FromNode", "ToNode", "PredicateName", "PredicateResult", "CreatedAt")
 
 func scanDecision(s repository.Scanner) (Decision, error) {
 	var d Decision
@@ -116,9 +168,12 @@ func scanDecision(s repository.Scanner) (Decision, error) {
 }
 
 // RunFilters contains optional criteria for filtering run queries.
+// UpdatedAfter is an exclusive bound on the last update timestamp.
 type RunFilters struct {
 	WorkflowName *string
 	Status       *string
+	StatusNot    *string
+	UpdatedAfter *time.Time
 }
 
 // RunFiltersFromQuery extracts run filters from URL query parameters.
@@ -133,6 +188,16 @@ func RunFiltersFromQuery(values url.Values) RunFilters {
 		f.Status = &s
 	}
 
+	if s := values.Get("status_not"); s != "" {
+		f.StatusNot = &s
+	}
+
+	if v := values.Get("updated_after"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			f.UpdatedAfter = &t
+		}
+	}
+
 	return f
 }
 
@@ -140,5 +205,35 @@ func RunFiltersFromQuery(values url.Values) RunFilters {
 func (f RunFilters) Apply(b *query.Builder) *query.Builder {
 	return b.
 		WhereEquals("WorkflowName", f.WorkflowName).
+		WhereEquals("Status", f.Status).
+		WhereNotEquals("Status", f.StatusNot).
+		WhereGreaterThan("UpdatedAt", f.UpdatedAfter)
+}
+
+// StageFilters contains optional criteria for filtering stage queries.
+type StageFilters struct {
+	NodeName *string
+	Status   *string
+}
+
+// StageFiltersFromQuery extracts stage filters from URL query parameters.
+func StageFiltersFromQuery(values url.Values) StageFilters {
+	var f StageFilters
+
+	if n := values.Get("node_name"); n != "" {
+		f.NodeName = &n
+	}
+
+	if s := values.Get("status"); s != "" {
+		f.Status = &s
+	}
+
+	return f
+}
+
+// Apply adds filter conditions to the query builder.
+func (f StageFilters) Apply(b *query.Builder) *query.Builder {
+	return b.
+		WhereEquals("NodeName", f.NodeName).
 		WhereEquals("Status", f.Status)
 }
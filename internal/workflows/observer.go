@@ -25,6 +25,7 @@ type PostgresObserver struct {
 	logger     *slog.Logger
 	mu         sync.Mutex
 	startTimes map[string]time.Time
+	labels     map[string]EdgeLabel
 }
 
 // NewPostgresObserver creates a new PostgreSQL-backed observer for a specific workflow run.
@@ -34,9 +35,23 @@ func NewPostgresObserver(db *sql.DB, runID uuid.UUID, logger *slog.Logger) *Post
 		runID:      runID,
 		logger:     logger,
 		startTimes: make(map[string]time.Time),
+		labels:     make(map[string]EdgeLabel),
 	}
 }
 
+// LabelEdge records the label for the edge from one node to another. Decisions
+// for that transition carry the label's name (unless the event supplies its
+// own predicate name) and reason.
+func (o *PostgresObserver) LabelEdge(from, to string, label EdgeLabel) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.labels[edgeKey(from, to)] = label
+}
+
+func edgeKey(from, to string) string {
+	return from + "->" + to
+}
+
 // OnEvent handles workflow events, persisting relevant ones to the database.
 // It handles EventNodeStart, EventNodeComplete, and EventEdgeTransition events.
 func (o *PostgresObserver) OnEvent(ctx context.Context, event observability.Event) {
@@ -131,17 +146,29 @@ func (o *PostgresObserver) handleEdgeTransition(ctx context.Context, event obser
 		return
 	}
 
+	label := o.labels[edgeKey(data.From, data.To)]
+
+	predName := data.PredicateName
+	if predName == "" {
+		predName = label.Name
+	}
+
 	var predNamePtr *string
-	if data.PredicateName != "" {
-		predNamePtr = &data.PredicateName
+	if predName != "" {
+		predNamePtr = &predName
+	}
+
+	var reasonPtr *string
+	if label.Reason != "" {
+		reasonPtr = &label.Reason
 	}
 
 	const query = `
-		INSERT INTO decisions (run_id, from_node, to_node, predicate_name, predicate_result, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO decisions (run_id, from_node, to_node, predicate_name, predicate_result, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err = o.db.ExecContext(ctx, query, o.runID, data.From, data.To, predNamePtr, data.PredicateResult, event.Timestamp)
+	_, err = o.db.ExecContext(ctx, query, o.runID, data.From, data.To, predNamePtr, data.PredicateResult, reasonPtr, event.Timestamp)
 	if err != nil {
 		o.logger.Error("failed to insert decision", "error", err, "from", data.From, "to", data.To)
 	}
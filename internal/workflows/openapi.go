@@ -3,15 +3,32 @@ package workflows
 import "github.com/JaimeStill/agent-lab/pkg/openapi"
 
 type spec struct {
-	ListWorkflows *openapi.Operation
-	Execute       *openapi.Operation
-	ListRuns      *openapi.Operation
-	FindRun       *openapi.Operation
-	GetStages     *openapi.Operation
-	GetDecisions  *openapi.Operation
-	DeleteRun     *openapi.Operation
-	Cancel        *openapi.Operation
-	Resume        *openapi.Operation
+	ListWorkflows    *openapi.Operation
+	DescribeWorkflow *openapi.Operation
+	Execute          *openapi.Operation
+	ListRuns         *openapi.Operation
+	FindRun          *openapi.Operation
+	ListActiveRuns   *openapi.Operation
+	ListQueued       *openapi.Operation
+	RunFacets        *openapi.Operation
+	GetStages        *openapi.Operation
+	GetDecisions     *openapi.Operation
+	GetReport        *openapi.Operation
+	GetTrace         *openapi.Operation
+	CompareRuns      *openapi.Operation
+	DeleteRun        *openapi.Operation
+	Cancel           *openapi.Operation
+	CancelAll        *openapi.Operation
+	Resume           *openapi.Operation
+	Replay           *openapi.Operation
+	StreamRun        *openapi.Operation
+}
+
+// executeExample is a sample classify-docs execution request rendered by the API docs.
+var executeExample = map[string]any{
+	"params": map[string]any{
+		"document_id": "9b2d7f4e-5c1a-4e8b-a3d6-2f71c0e84b19",
+	},
 }
 
 var Spec = spec{
@@ -22,9 +39,20 @@ var Spec = spec{
 			200: openapi.ResponseJSON("List of workflows", "WorkflowInfoList"),
 		},
 	},
+	DescribeWorkflow: &openapi.Operation{
+		Summary:     "Describe workflow",
+		Description: "Returns the workflow's stage graph as built with its default profile: nodes, edges with their labels, entry and exit points, and the schema of the parameters it accepts.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("name", "Workflow name"),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Workflow graph", "WorkflowGraph"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
 	Execute: &openapi.Operation{
 		Summary:     "Execute workflow",
-		Description: "Executes a workflow and streams progress events via SSE",
+		Description: "Queues a workflow run and streams its progress events via SSE. Runs beyond the server's concurrency limit wait in a queue, dispatched by descending priority and in arrival order within a priority. The stream ends with a complete or error event followed by a data: [DONE] sentinel. Stage, decision, complete, and error events carry an SSE id; clients that lose the connection resume from GET /workflows/runs/{id}/events with Last-Event-ID.",
 		Parameters: []*openapi.Parameter{
 			{
 				Name:        "name",
@@ -34,10 +62,16 @@ var Spec = spec{
 				Schema:      &openapi.Schema{Type: "string"},
 			},
 		},
-		RequestBody: openapi.RequestBodyJSON("ExecuteRequest", false),
+		RequestBody: openapi.RequestBodyJSON("ExecuteRequest", false).WithExample(executeExample),
 		Responses: map[int]*openapi.Response{
 			200: {
 				Description: "SSE event stream",
+				Headers: map[string]*openapi.Header{
+					"X-Run-ID": {
+						Description: "ID of the run created for this execution",
+						Schema:      &openapi.Schema{Type: "string", Format: "uuid"},
+					},
+				},
 				Content: map[string]*openapi.MediaType{
 					"text/event-stream": {
 						Schema: openapi.SchemaRef("ExecutionEvent"),
@@ -50,15 +84,47 @@ var Spec = spec{
 	},
 	ListRuns: &openapi.Operation{
 		Summary:     "List workflow runs",
-		Description: "Returns paginated list of workflow runs with optional filters",
+		Description: "Returns paginated list of workflow runs with optional filters. Polling clients pass the X-Server-Time header of the previous response as updated_after to fetch only runs changed since. Request CSV with format=csv or Accept: text/csv to stream every matching run; params and result are JSON-encoded per cell and pagination is ignored. Request NDJSON with format=ndjson or Accept: application/x-ndjson to stream every matching run as one JSON object per line; filters and sort apply and pagination is ignored.",
 		Parameters: []*openapi.Parameter{
 			openapi.QueryParam("page", "integer", "Page number", false),
 			openapi.QueryParam("page_size", "integer", "Items per page", false),
 			openapi.QueryParam("workflow_name", "string", "Filter by workflow name", false),
 			openapi.QueryParam("status", "string", "Filter by status", false),
+			openapi.QueryParam("status_not", "string", "Exclude runs with status", false),
+			openapi.QueryParam("updated_after", "string", "Only runs updated after this RFC 3339 timestamp", false),
+			openapi.QueryParam("format", "string", "Set to csv or ndjson to stream all matching runs", false),
+			openapi.FieldsParam(),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseWithNDJSON(openapi.ResponseWithCSV(openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated runs", "RunPageResult"), runListHeaders())), "Run"),
+		},
+	},
+	ListActiveRuns: &openapi.Operation{
+		Summary:     "List active workflow runs",
+		Description: "Returns runs currently executing in this server process. Runs with running status that are absent here are stale.",
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Active runs", "RunList"),
+		},
+	},
+	ListQueued: &openapi.Operation{
+		Summary:     "List queued workflow runs",
+		Description: "Returns runs waiting for an execution slot in this server process, in the order they will dispatch: highest priority first, then by arrival.",
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Queued runs", "QueuedRunList"),
+		},
+	},
+	RunFacets: &openapi.Operation{
+		Summary:     "List run facet values",
+		Description: "Returns the distinct values of a run field in ascending order, e.g. every workflow name with recorded runs. Only allowlisted fields are accepted; the run filters narrow the runs considered.",
+		Parameters: []*openapi.Parameter{
+			openapi.QueryParam("field", "string", "Field to facet: workflow_name or status", true),
+			openapi.QueryParam("workflow_name", "string", "Filter by workflow name", false),
+			openapi.QueryParam("status", "string", "Filter by status", false),
+			openapi.QueryParam("status_not", "string", "Exclude runs with status", false),
 		},
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Paginated runs", "RunPageResult"),
+			200: openapi.ResponseJSON("Distinct field values", "RunFacets"),
+			400: openapi.ResponseRef("BadRequest"),
 		},
 	},
 	FindRun: &openapi.Operation{
@@ -75,26 +141,111 @@ var Spec = spec{
 	},
 	GetStages: &openapi.Operation{
 		Summary:     "Get run stages",
-		Description: "Returns execution stages for a workflow run",
+		Description: "Returns a paginated list of execution stages for a workflow run ordered by creation time",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Run ID"),
+			openapi.QueryParam("page", "integer", "Page number", false),
+			openapi.QueryParam("page_size", "integer", "Items per page", false),
+			openapi.QueryParam("node_name", "string", "Filter by node name", false),
+			openapi.QueryParam("status", "string", "Filter by stage status", false),
+			openapi.QueryParam("all", "boolean", "Return all matching stages in a single page", false),
+			openapi.FieldsParam(),
 		},
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Stage list", "StageList"),
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated stages", "StagePageResult"), openapi.PageHeaders(true)),
 			400: openapi.ResponseRef("BadRequest"),
 			404: openapi.ResponseRef("NotFound"),
 		},
 	},
 	GetDecisions: &openapi.Operation{
 		Summary:     "Get run decisions",
-		Description: "Returns routing decisions for a workflow run",
+		Description: "Returns a paginated list of routing decisions for a workflow run ordered by creation time",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Run ID"),
+			openapi.QueryParam("page", "integer", "Page number", false),
+			openapi.QueryParam("page_size", "integer", "Items per page", false),
+			openapi.QueryParam("all", "boolean", "Return all decisions in a single page", false),
+			openapi.FieldsParam(),
+		},
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseWithHeaders(openapi.ResponseJSON("Paginated decisions", "DecisionPageResult"), openapi.PageHeaders(true)),
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
+	GetReport: &openapi.Operation{
+		Summary:     "Export run report",
+		Description: "Renders the stored result of a workflow run as a shareable report. Workflows with a registered reporter (e.g. classify-docs) produce a workflow-specific report; other workflows return a generic run report for format=json and 415 for format=md",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Run ID"),
+			{
+				Name:        "format",
+				In:          "query",
+				Description: "Report format",
+				Schema:      &openapi.Schema{Type: "string", Enum: []any{"json", "md"}, Default: "json"},
+			},
+		},
+		Responses: map[int]*openapi.Response{
+			200: {
+				Description: "Run report",
+				Content: map[string]*openapi.MediaType{
+					"application/json": {Schema: openapi.SchemaRef("RunReport")},
+					"text/markdown":    {Schema: &openapi.Schema{Type: "string"}},
+				},
+			},
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			409: openapi.ResponseRef("Conflict"),
+			415: {
+				Description: "Report format not supported for this workflow",
+				Content: map[string]*openapi.MediaType{
+					"application/json": {Schema: openapi.SchemaRef("Error")},
+				},
+			},
+		},
+	},
+	GetTrace: &openapi.Operation{
+		Summary:     "Download run execution trace",
+		Description: "Returns the run with all of its stages and decisions in chronological order as a single audit artifact. Use format=ndjson to stream large traces one entry per line: a run entry followed by stage and decision entries.",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Run ID"),
+			{
+				Name:        "format",
+				In:          "query",
+				Description: "Trace format",
+				Schema:      &openapi.Schema{Type: "string", Enum: []any{"json", "ndjson"}, Default: "json"},
+			},
+			{
+				Name:        "snapshots",
+				In:          "query",
+				Description: "Include stage input and output snapshots",
+				Schema:      &openapi.Schema{Type: "boolean", Default: true},
+			},
+		},
+		Responses: map[int]*openapi.Response{
+			200: {
+				Description: "Run trace",
+				Content: map[string]*openapi.MediaType{
+					"application/json":     {Schema: openapi.SchemaRef("RunTrace")},
+					"application/x-ndjson": {Schema: openapi.SchemaRef("TraceEntry")},
+				},
+			},
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
+	CompareRuns: &openapi.Operation{
+		Summary:     "Compare workflow runs",
+		Description: "Diffs the results of two completed runs of the same workflow, e.g. the same document run through two profiles. Workflows with a registered comparer (e.g. classify-docs) return a workflow-specific diff; other workflows return a generic run comparison",
+		Parameters: []*openapi.Parameter{
+			openapi.QueryParam("a", "string", "First run ID", true),
+			openapi.QueryParam("b", "string", "Second run ID", true),
 		},
 		Responses: map[int]*openapi.Response{
-			200: openapi.ResponseJSON("Decision list", "DecisionList"),
+			200: openapi.ResponseJSON("Run comparison", "RunComparison"),
 			400: openapi.ResponseRef("BadRequest"),
 			404: openapi.ResponseRef("NotFound"),
+			409: openapi.ResponseRef("Conflict"),
 		},
 	},
 	DeleteRun: &openapi.Operation{
@@ -111,9 +262,10 @@ var Spec = spec{
 	},
 	Cancel: &openapi.Operation{
 		Summary:     "Cancel workflow run",
-		Description: "Cancels an active workflow run",
+		Description: "Cancels an active workflow run, or removes a queued run from the queue. Either way the run is recorded with cancelled status and the cancel reason.",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Run ID"),
+			openapi.QueryParam("reason", "string", "Cancel reason recorded on the run: user (default), shutdown, or timeout", false),
 		},
 		Responses: map[int]*openapi.Response{
 			204: {Description: "Run cancelled"},
@@ -122,11 +274,19 @@ var Spec = spec{
 			409: openapi.ResponseRef("Conflict"),
 		},
 	},
+	CancelAll: &openapi.Operation{
+		Summary:     "Cancel all active workflow runs",
+		Description: "Emergency stop: cancels every run currently executing or queued in this server process and returns how many were cancelled. Cancelled runs are recorded with cancelled status.",
+		Responses: map[int]*openapi.Response{
+			200: openapi.ResponseJSON("Cancelled run count", "CancelAllResult"),
+		},
+	},
 	Resume: &openapi.Operation{
 		Summary:     "Resume workflow run",
-		Description: "Resumes a failed or cancelled workflow run from checkpoint",
+		Description: "Resumes a failed or cancelled workflow run from its latest checkpoint. Set from_node to resume from the checkpoint saved after that node instead, re-running every node that follows it.",
 		Parameters: []*openapi.Parameter{
 			openapi.PathParam("id", "Run ID"),
+			openapi.QueryParam("from_node", "string", "Resume from the checkpoint saved after this node. Unknown nodes return 400 listing the available nodes; nodes without a checkpoint return 409.", false),
 		},
 		Responses: map[int]*openapi.Response{
 			200: openapi.ResponseJSON("Resumed run", "Run"),
@@ -135,6 +295,64 @@ var Spec = spec{
 			409: openapi.ResponseRef("Conflict"),
 		},
 	},
+	Replay: &openapi.Operation{
+		Summary:     "Replay workflow run",
+		Description: "Queues a new run of the original run's workflow with the same params, starting from the beginning rather than a checkpoint, and streams its progress events via SSE like execute. The new run records the original in replayed_from. The token is not stored with runs and must be supplied again. Runs still pending or running return 409.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Run ID"),
+		},
+		RequestBody: openapi.RequestBodyJSON("ReplayRequest", false),
+		Responses: map[int]*openapi.Response{
+			200: {
+				Description: "SSE event stream",
+				Headers: map[string]*openapi.Header{
+					"X-Run-ID": {
+						Description: "ID of the run created by the replay",
+						Schema:      &openapi.Schema{Type: "string", Format: "uuid"},
+					},
+				},
+				Content: map[string]*openapi.MediaType{
+					"text/event-stream": {
+						Schema: openapi.SchemaRef("ExecutionEvent"),
+					},
+				},
+			},
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+			409: openapi.ResponseRef("Conflict"),
+		},
+	},
+	StreamRun: &openapi.Operation{
+		Summary:     "Stream workflow run events",
+		Description: "Reconnects to a run's SSE event stream. Stage, decision, complete, and error events carry an id numbering them within the run; events up to the Last-Event-ID header are skipped. The rest are replayed from the run's persisted stages and decisions, then live events follow while the run is queued or executing on this server. Progress events are not persisted, so those sent before the reconnect are not replayed. The stream ends with data: [DONE] once the run finishes, or after the replay when it is not executing.",
+		Parameters: []*openapi.Parameter{
+			openapi.PathParam("id", "Run ID"),
+			{
+				Name:        "Last-Event-ID",
+				In:          "header",
+				Description: "ID of the last event received; only later events are sent",
+				Schema:      &openapi.Schema{Type: "integer"},
+			},
+		},
+		Responses: map[int]*openapi.Response{
+			200: {
+				Description: "SSE event stream",
+				Headers: map[string]*openapi.Header{
+					"X-Run-ID": {
+						Description: "ID of the streamed run",
+						Schema:      &openapi.Schema{Type: "string", Format: "uuid"},
+					},
+				},
+				Content: map[string]*openapi.MediaType{
+					"text/event-stream": {
+						Schema: openapi.SchemaRef("ExecutionEvent"),
+					},
+				},
+			},
+			400: openapi.ResponseRef("BadRequest"),
+			404: openapi.ResponseRef("NotFound"),
+		},
+	},
 }
 
 func (spec) Schemas() map[string]*openapi.Schema {
@@ -150,6 +368,28 @@ func (spec) Schemas() map[string]*openapi.Schema {
 			Type:  "array",
 			Items: openapi.SchemaRef("WorkflowInfo"),
 		},
+		"WorkflowGraph": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"name":        {Type: "string"},
+				"description": {Type: "string"},
+				"nodes":       {Type: "array", Items: &openapi.Schema{Type: "string"}, Description: "Node names in the order they are added"},
+				"edges":       {Type: "array", Items: openapi.SchemaRef("GraphEdge")},
+				"entry_point": {Type: "string"},
+				"exit_points": {Type: "array", Items: &openapi.Schema{Type: "string"}},
+				"params":      {Type: "object", Description: "JSON schema of the accepted execution params, when declared"},
+			},
+		},
+		"GraphEdge": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"from":        {Type: "string"},
+				"to":          {Type: "string"},
+				"conditional": {Type: "boolean", Description: "Edge is taken only when its predicate holds"},
+				"label":       {Type: "string", Description: "Predicate name, when the edge is labeled"},
+				"reason":      {Type: "string", Description: "Why the transition is taken, when the edge is labeled"},
+			},
+		},
 		"Run": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
@@ -159,12 +399,44 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"params":        {Type: "object"},
 				"result":        {Type: "object"},
 				"error_message": {Type: "string"},
+				"cancel_reason": {Type: "string", Description: "Why a cancelled run was cancelled", Enum: []any{"user", "shutdown", "timeout"}},
+				"replayed_from": {Type: "string", Format: "uuid", Description: "Run whose workflow and params this run replayed"},
 				"started_at":    {Type: "string", Format: "date-time"},
 				"completed_at":  {Type: "string", Format: "date-time"},
 				"created_at":    {Type: "string", Format: "date-time"},
 				"updated_at":    {Type: "string", Format: "date-time"},
 			},
 		},
+		"CancelAllResult": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"cancelled": {Type: "integer"},
+			},
+		},
+		"RunFacets": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"field":  {Type: "string"},
+				"values": {Type: "array", Items: &openapi.Schema{Type: "string"}},
+			},
+		},
+		"RunList": {
+			Type:  "array",
+			Items: openapi.SchemaRef("Run"),
+		},
+		"QueuedRun": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"run_id":        {Type: "string", Format: "uuid"},
+				"workflow_name": {Type: "string"},
+				"priority":      {Type: "integer"},
+				"queued_at":     {Type: "string", Format: "date-time"},
+			},
+		},
+		"QueuedRunList": {
+			Type:  "array",
+			Items: openapi.SchemaRef("QueuedRun"),
+		},
 		"RunPageResult": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
@@ -190,9 +462,15 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"created_at":      {Type: "string", Format: "date-time"},
 			},
 		},
-		"StageList": {
-			Type:  "array",
-			Items: openapi.SchemaRef("Stage"),
+		"StagePageResult": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"data":        {Type: "array", Items: openapi.SchemaRef("Stage")},
+				"total":       {Type: "integer"},
+				"page":        {Type: "integer"},
+				"page_size":   {Type: "integer"},
+				"total_pages": {Type: "integer"},
+			},
 		},
 		"Decision": {
 			Type: "object",
@@ -207,24 +485,94 @@ func (spec) Schemas() map[string]*openapi.Schema {
 				"created_at":       {Type: "string", Format: "date-time"},
 			},
 		},
-		"DecisionList": {
-			Type:  "array",
-			Items: openapi.SchemaRef("Decision"),
+		"DecisionPageResult": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"data":        {Type: "array", Items: openapi.SchemaRef("Decision")},
+				"total":       {Type: "integer"},
+				"page":        {Type: "integer"},
+				"page_size":   {Type: "integer"},
+				"total_pages": {Type: "integer"},
+			},
+		},
+		"TraceEntry": {
+			Type:        "object",
+			Description: "Trace record; exactly one of run, stage, or decision is set, matching type",
+			Properties: map[string]*openapi.Schema{
+				"type":     {Type: "string", Enum: []any{"run", "stage", "decision"}},
+				"time":     {Type: "string", Format: "date-time"},
+				"run":      openapi.SchemaRef("Run"),
+				"stage":    openapi.SchemaRef("Stage"),
+				"decision": openapi.SchemaRef("Decision"),
+			},
+		},
+		"RunTrace": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"run":    openapi.SchemaRef("Run"),
+				"events": {Type: "array", Items: openapi.SchemaRef("TraceEntry")},
+			},
+		},
+		"RunReport": {
+			Type:        "object",
+			Description: "Generic run report; workflows with a registered reporter return their own structure",
+			Properties: map[string]*openapi.Schema{
+				"run_id":        {Type: "string", Format: "uuid"},
+				"workflow_name": {Type: "string"},
+				"status":        {Type: "string", Enum: []any{"pending", "running", "completed", "failed", "cancelled"}},
+				"started_at":    {Type: "string", Format: "date-time"},
+				"completed_at":  {Type: "string", Format: "date-time"},
+				"duration_ms":   {Type: "integer"},
+				"error_message": {Type: "string"},
+				"result":        {Type: "object"},
+			},
+		},
+		"RunComparison": {
+			Type:        "object",
+			Description: "Generic run comparison; workflows with a registered comparer return their own structure",
+			Properties: map[string]*openapi.Schema{
+				"workflow_name": {Type: "string"},
+				"run_a":         {Type: "string", Format: "uuid"},
+				"run_b":         {Type: "string", Format: "uuid"},
+				"results_equal": {Type: "boolean"},
+				"result_a":      {Type: "object"},
+				"result_b":      {Type: "object"},
+			},
 		},
 		"ExecuteRequest": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
-				"params": {Type: "object", Description: "Workflow parameters"},
-				"token":  {Type: "string", Description: "Auth token for agent API calls (not persisted)"},
+				"params":   {Type: "object", Description: "Workflow parameters"},
+				"token":    {Type: "string", Description: "Auth token for agent API calls (not persisted)"},
+				"priority": {Type: "integer", Description: "Queue priority; higher values dispatch first. Defaults to 0"},
+			},
+		},
+		"ReplayRequest": {
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"token":    {Type: "string", Description: "Auth token for agent API calls (not persisted)"},
+				"priority": {Type: "integer", Description: "Queue priority; higher values dispatch first. Defaults to 0"},
 			},
 		},
 		"ExecutionEvent": {
 			Type: "object",
 			Properties: map[string]*openapi.Schema{
-				"type":      {Type: "string", Enum: []any{"stage.start", "stage.complete", "decision", "error", "complete"}},
+				"id":        {Type: "integer", Description: "Event number within the run; absent on progress events"},
+				"type":      {Type: "string", Enum: []any{"stage.start", "stage.complete", "page.complete", "decision", "error", "complete"}},
 				"timestamp": {Type: "string", Format: "date-time"},
 				"data":      {Type: "object"},
 			},
 		},
 	}
 }
+
+// runListHeaders returns the page headers of the runs list plus the server
+// time header polling clients use as their next updated_after cursor.
+func runListHeaders() map[string]*openapi.Header {
+	headers := openapi.PageHeaders(true)
+	headers[ServerTimeHeader] = &openapi.Header{
+		Description: "Server time before the query ran; pass as updated_after on the next poll",
+		Schema:      &openapi.Schema{Type: "string", Format: "date-time"},
+	}
+	return headers
+}
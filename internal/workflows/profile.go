@@ -3,8 +3,10 @@ package workflows
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
 	"github.com/google/uuid"
 )
@@ -38,6 +40,20 @@ func ExtractAgentParams(s state.State, stage *profiles.ProfileStage) (uuid.UUID,
 	return agentID, token, nil
 }
 
+// ProfileParams returns a params schema with the given properties plus the
+// profile_id and agent_id params every workflow accepts through LoadProfile
+// and ExtractAgentParams. Used with RegisterParams.
+func ProfileParams(properties map[string]*openapi.Schema, required ...string) *openapi.Schema {
+	props := map[string]*openapi.Schema{
+		"profile_id": {Type: "string", Format: "uuid", Description: "Profile whose stages override the workflow defaults"},
+		"agent_id":   {Type: "string", Format: "uuid", Description: "Agent for stages the profile leaves without one"},
+	}
+	for name, schema := range properties {
+		props[name] = schema
+	}
+	return &openapi.Schema{Type: "object", Properties: props, Required: required}
+}
+
 // LoadProfile resolves the profile configuration for a workflow execution.
 // If profile_id is provided in params, loads from database and merges with
 // the default profile (DB stages override matching default stages).
@@ -46,7 +62,7 @@ func LoadProfile(ctx context.Context, rt *Runtime, params map[string]any, defaul
 	if profileIDStr, ok := params["profile_id"].(string); ok {
 		profileID, err := uuid.Parse(profileIDStr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid profile_id: %w", err)
+			return nil, fmt.Errorf("%w: invalid profile_id: %v", ErrInvalidProfile, err)
 		}
 		dbProfile, err := rt.Profiles().Find(ctx, profileID)
 		if err != nil {
@@ -56,3 +72,44 @@ func LoadProfile(ctx context.Context, rt *Runtime, params map[string]any, defaul
 	}
 	return defaultProfile, nil
 }
+
+// AgentStage declares a workflow stage that calls an agent.
+// HasAgents, when set, reports whether the stage configuration supplies its
+// agents by other means, such as a consensus agent list in its options.
+type AgentStage struct {
+	Name      string
+	HasAgents func(stage *profiles.ProfileStage) bool
+}
+
+// ValidateProfile verifies that every agent stage can resolve an agent from
+// the profile stage, the agent_id param, or the stage's HasAgents check.
+// Returns ErrIncompleteProfile listing the stages without an agent.
+func ValidateProfile(profile *profiles.ProfileWithStages, params map[string]any, stages []AgentStage) error {
+	var paramAgent bool
+	if id, ok := params["agent_id"].(string); ok {
+		_, err := uuid.Parse(id)
+		paramAgent = err == nil
+	}
+
+	var missing []string
+	for _, as := range stages {
+		if !paramAgent && !as.resolves(profile.Stage(as.Name)) {
+			missing = append(missing, as.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: no agent for stages: %s", ErrIncompleteProfile, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (a AgentStage) resolves(stage *profiles.ProfileStage) bool {
+	if stage == nil {
+		return false
+	}
+	if stage.AgentID != nil {
+		return true
+	}
+	return a.HasAgents != nil && a.HasAgents(stage)
+}
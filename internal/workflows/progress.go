@@ -0,0 +1,23 @@
+package workflows
+
+import "context"
+
+// ProgressFunc receives progress events reported by workflow nodes while a
+// run executes.
+type ProgressFunc func(eventType ExecutionEventType, data map[string]any)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose nodes report progress to fn.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress sends a progress event to the function registered on ctx
+// with WithProgress. It does nothing when ctx carries no progress function,
+// so nodes can report unconditionally.
+func ReportProgress(ctx context.Context, eventType ExecutionEventType, data map[string]any) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(eventType, data)
+	}
+}
@@ -0,0 +1,87 @@
+package workflows
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueuedRun describes a run waiting for an execution slot.
+type QueuedRun struct {
+	RunID        uuid.UUID `json:"run_id"`
+	WorkflowName string    `json:"workflow_name"`
+	Priority     int       `json:"priority"`
+	QueuedAt     time.Time `json:"queued_at"`
+}
+
+// pendingRun is a queued run together with what it needs to execute.
+type pendingRun struct {
+	QueuedRun
+	seq      uint64
+	factory  WorkflowFactory
+	params   map[string]any
+	token    string
+	observer *StreamingObserver
+}
+
+// runQueue orders pending runs by descending priority, then by arrival.
+// It implements heap.Interface; use push, pop, and remove rather than the
+// heap methods directly.
+type runQueue []*pendingRun
+
+func (q runQueue) Len() int { return len(q) }
+
+func (q runQueue) Less(i, j int) bool {
+	if q[i].Priority != q[j].Priority {
+		return q[i].Priority > q[j].Priority
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q runQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *runQueue) Push(x any) { *q = append(*q, x.(*pendingRun)) }
+
+func (q *runQueue) Pop() any {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
+
+func (q *runQueue) push(item *pendingRun) { heap.Push(q, item) }
+
+func (q *runQueue) pop() *pendingRun { return heap.Pop(q).(*pendingRun) }
+
+// remove drops the run with the given ID and returns it, or nil if it is not queued.
+func (q *runQueue) remove(id uuid.UUID) *pendingRun {
+	for i, item := range *q {
+		if item.RunID == id {
+			return heap.Remove(q, i).(*pendingRun)
+		}
+	}
+	return nil
+}
+
+// drain empties the queue and returns its runs in dispatch order.
+func (q *runQueue) drain() []*pendingRun {
+	items := make([]*pendingRun, 0, q.Len())
+	for q.Len() > 0 {
+		items = append(items, q.pop())
+	}
+	return items
+}
+
+// snapshot returns the queued runs in dispatch order without modifying the queue.
+func (q runQueue) snapshot() []QueuedRun {
+	c := make(runQueue, len(q))
+	copy(c, q)
+	runs := make([]QueuedRun, 0, len(c))
+	for c.Len() > 0 {
+		runs = append(runs, heap.Pop(&c).(*pendingRun).QueuedRun)
+	}
+	return runs
+}
@@ -0,0 +1,213 @@
+package workflows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StreamRun streams the events of a run for a client reconnecting to it,
+// skipping events numbered at or below lastEventID. Events are first replayed
+// from the run's persisted stages and decisions, ending with a complete or
+// error event once the run has finished. While the run is queued or executing
+// in this process, its live events follow, skipping any already replayed,
+// and the stream closes when the run ends. Progress events sent before the
+// reconnect are not persisted and are not replayed.
+func (e *executor) StreamRun(ctx context.Context, runID uuid.UUID, lastEventID int) (<-chan ExecutionEvent, *Run, error) {
+	var live <-chan ExecutionEvent
+	stop := func() {}
+
+	e.mu.RLock()
+	if obs, ok := e.streams[runID]; ok {
+		live, stop = obs.Subscribe(defaultStreamBufferSize)
+	}
+	e.mu.RUnlock()
+
+	run, replay, err := e.replayRun(ctx, runID)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	out := make(chan ExecutionEvent, defaultStreamBufferSize)
+
+	go func() {
+		defer close(out)
+		defer stop()
+
+		last := lastEventID
+		forward := func(events ...ExecutionEvent) bool {
+			for _, event := range events {
+				if event.ID != 0 {
+					if event.ID <= last {
+						continue
+					}
+					last = event.ID
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		if !forward(replay...) || live == nil {
+			return
+		}
+
+		for event := range live {
+			if !forward(event) {
+				return
+			}
+		}
+
+		// The run is recorded as finished before its live stream closes, so
+		// a final replay supplies any events dropped from a full buffer and
+		// the final event when it was sent before the subscription opened.
+		_, replay, err := e.replayRun(ctx, runID)
+		if err != nil {
+			e.logger.Error("failed to replay run events", "id", runID, "error", err)
+			return
+		}
+		forward(replay...)
+	}()
+
+	return out, run, nil
+}
+
+// replayRun loads a run with its stages and decisions and rebuilds the
+// events they record.
+func (e *executor) replayRun(ctx context.Context, runID uuid.UUID) (*Run, []ExecutionEvent, error) {
+	run, err := e.repo.FindRun(ctx, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stages, err := e.repo.GetStages(ctx, runID, StageFilters{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decisions, err := e.repo.GetDecisions(ctx, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return run, replayEvents(run, stages, decisions), nil
+}
+
+// replayEvents rebuilds the numbered events a StreamingObserver sent for a
+// run from its persisted record. Nodes execute one at a time, so each stage
+// contributes its start and, once finished, its completion or error, followed
+// by the decisions recorded before the next stage started. A finished run
+// ends with its complete or error event.
+func replayEvents(run *Run, stages []Stage, decisions []Decision) []ExecutionEvent {
+	var events []ExecutionEvent
+	add := func(eventType ExecutionEventType, at time.Time, data map[string]any) {
+		events = append(events, ExecutionEvent{
+			ID:        len(events) + 1,
+			Type:      eventType,
+			Timestamp: at,
+			Data:      data,
+		})
+	}
+
+	next := 0
+	for i, stage := range stages {
+		add(EventStageStart, stage.CreatedAt, map[string]any{
+			"node_name": stage.NodeName,
+			"iteration": stage.Iteration,
+		})
+
+		finishedAt := stage.CreatedAt
+		if stage.DurationMs != nil {
+			finishedAt = finishedAt.Add(time.Duration(*stage.DurationMs) * time.Millisecond)
+		}
+
+		switch stage.Status {
+		case StageCompleted:
+			add(EventStageComplete, finishedAt, map[string]any{
+				"node_name":       stage.NodeName,
+				"iteration":       stage.Iteration,
+				"output_snapshot": decodeSnapshot(stage.OutputSnapshot),
+			})
+		case StageFailed:
+			message := ""
+			if stage.ErrorMessage != nil {
+				message = *stage.ErrorMessage
+			}
+			add(EventError, finishedAt, map[string]any{
+				"node_name": stage.NodeName,
+				"message":   message,
+			})
+		}
+
+		for ; next < len(decisions); next++ {
+			if i+1 < len(stages) && decisions[next].CreatedAt.After(stages[i+1].CreatedAt) {
+				break
+			}
+			add(EventDecision, decisions[next].CreatedAt, decisionEventData(decisions[next]))
+		}
+	}
+
+	for ; next < len(decisions); next++ {
+		add(EventDecision, decisions[next].CreatedAt, decisionEventData(decisions[next]))
+	}
+
+	at := run.UpdatedAt
+	if run.CompletedAt != nil {
+		at = *run.CompletedAt
+	}
+
+	switch run.Status {
+	case StatusCompleted:
+		add(EventComplete, at, map[string]any{"result": decodeSnapshot(run.Result)})
+	case StatusFailed:
+		message := ""
+		if run.ErrorMessage != nil {
+			message = *run.ErrorMessage
+		}
+		add(EventError, at, map[string]any{"message": message})
+	case StatusCancelled:
+		message := cancelledMessage
+		if run.CancelReason != nil {
+			message = fmt.Sprintf("%s: %s", cancelledMessage, *run.CancelReason)
+		}
+		add(EventError, at, map[string]any{"message": message})
+	}
+
+	return events
+}
+
+func decisionEventData(d Decision) map[string]any {
+	data := map[string]any{
+		"from_node":        d.FromNode,
+		"to_node":          "",
+		"predicate_result": false,
+	}
+	if d.ToNode != nil {
+		data["to_node"] = *d.ToNode
+	}
+	if d.PredicateResult != nil {
+		data["predicate_result"] = *d.PredicateResult
+	}
+	return data
+}
+
+// decodeSnapshot decodes a persisted JSON object, returning nil when it is
+// absent or not an object.
+func decodeSnapshot(raw json.RawMessage) map[string]any {
+	if len(raw) == 0 {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"sync"
 
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
 )
 
@@ -13,12 +15,26 @@ type WorkflowFactory func(ctx context.Context, graph state.StateGraph, runtime *
 type workflowRegistry struct {
 	factories map[string]WorkflowFactory
 	info      map[string]WorkflowInfo
+	reporters map[string]Reporter
+	comparers map[string]Comparer
+	stages    map[string]stageRegistration
+	params    map[string]*openapi.Schema
 	mu        sync.RWMutex
 }
 
+// stageRegistration holds a workflow's default profile and agent stages.
+type stageRegistration struct {
+	defaultProfile func() *profiles.ProfileWithStages
+	stages         []AgentStage
+}
+
 var registry = &workflowRegistry{
 	factories: make(map[string]WorkflowFactory),
 	info:      make(map[string]WorkflowInfo),
+	reporters: make(map[string]Reporter),
+	comparers: make(map[string]Comparer),
+	stages:    make(map[string]stageRegistration),
+	params:    make(map[string]*openapi.Schema),
 }
 
 // Register adds a workflow factory to the global registry.
@@ -48,3 +64,72 @@ func List() []WorkflowInfo {
 	}
 	return result
 }
+
+// RegisterReporter associates a Reporter with a registered workflow name.
+// Runs of workflows without a Reporter fall back to the generic RunReport.
+func RegisterReporter(name string, reporter Reporter) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.reporters[name] = reporter
+}
+
+// GetReporter retrieves the Reporter registered for a workflow name.
+func GetReporter(name string) (Reporter, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	reporter, exists := registry.reporters[name]
+	return reporter, exists
+}
+
+// RegisterComparer associates a Comparer with a registered workflow name.
+// Runs of workflows without a Comparer fall back to the generic RunComparison.
+func RegisterComparer(name string, comparer Comparer) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.comparers[name] = comparer
+}
+
+// GetComparer retrieves the Comparer registered for a workflow name.
+func GetComparer(name string) (Comparer, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	comparer, exists := registry.comparers[name]
+	return comparer, exists
+}
+
+// RegisterStages declares the stages of a registered workflow that call an
+// agent, along with the default profile its factory loads. Executions are
+// checked against them before the run starts so an incomplete profile fails
+// fast with ErrIncompleteProfile.
+func RegisterStages(name string, defaultProfile func() *profiles.ProfileWithStages, stages ...AgentStage) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.stages[name] = stageRegistration{defaultProfile: defaultProfile, stages: stages}
+}
+
+// GetAgentStages retrieves the agent stages and default profile registered
+// for a workflow name.
+func GetAgentStages(name string) ([]AgentStage, func() *profiles.ProfileWithStages, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	reg, exists := registry.stages[name]
+	return reg.stages, reg.defaultProfile, exists
+}
+
+// RegisterParams declares the schema of the parameters a workflow accepts.
+// It is reported by Describe and does not validate execution params.
+func RegisterParams(name string, schema *openapi.Schema) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.params[name] = schema
+}
+
+// DefaultProfile returns the default profile registered for a workflow name,
+// or nil if the workflow registers none. It satisfies profiles.DefaultsFunc.
+func DefaultProfile(name string) *profiles.ProfileWithStages {
+	_, defaultProfile, exists := GetAgentStages(name)
+	if !exists || defaultProfile == nil {
+		return nil
+	}
+	return defaultProfile()
+}
@@ -0,0 +1,95 @@
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportFormat identifies the output format of a run report.
+type ReportFormat string
+
+const (
+	ReportJSON     ReportFormat = "json"
+	ReportMarkdown ReportFormat = "md"
+)
+
+// ParseReportFormat validates a report format query value.
+// An empty value defaults to ReportJSON.
+func ParseReportFormat(value string) (ReportFormat, error) {
+	switch ReportFormat(value) {
+	case "", ReportJSON:
+		return ReportJSON, nil
+	case ReportMarkdown:
+		return ReportMarkdown, nil
+	default:
+		return "", fmt.Errorf("%w: %q (expected json or md)", ErrInvalidReportFormat, value)
+	}
+}
+
+// Reporter renders the stored result of a workflow run as a shareable report.
+// Workflows register a Reporter via RegisterReporter to provide a
+// workflow-specific structure and Markdown rendering.
+type Reporter interface {
+	JSON(run *Run) (any, error)
+	Markdown(run *Run) (string, error)
+}
+
+// RunReport is the generic structured report returned for workflows
+// without a registered Reporter.
+type RunReport struct {
+	RunID        uuid.UUID       `json:"run_id"`
+	WorkflowName string          `json:"workflow_name"`
+	Status       RunStatus       `json:"status"`
+	StartedAt    *time.Time      `json:"started_at,omitempty"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
+	DurationMs   *int64          `json:"duration_ms,omitempty"`
+	ErrorMessage *string         `json:"error_message,omitempty"`
+	Result       json.RawMessage `json:"result,omitempty"`
+}
+
+// NewRunReport builds the generic report for a run.
+func NewRunReport(run *Run) RunReport {
+	report := RunReport{
+		RunID:        run.ID,
+		WorkflowName: run.WorkflowName,
+		Status:       run.Status,
+		StartedAt:    run.StartedAt,
+		CompletedAt:  run.CompletedAt,
+		ErrorMessage: run.ErrorMessage,
+		Result:       run.Result,
+	}
+
+	if run.StartedAt != nil && run.CompletedAt != nil {
+		ms := run.CompletedAt.Sub(*run.StartedAt).Milliseconds()
+		report.DurationMs = &ms
+	}
+
+	return report
+}
+
+// RenderReport renders run in the requested format using the Reporter
+// registered for its workflow. Workflows without a Reporter produce a
+// RunReport for ReportJSON and ErrReportUnsupported for other formats.
+// The returned value is a string for ReportMarkdown and a JSON-encodable
+// value for ReportJSON.
+func RenderReport(run *Run, format ReportFormat) (any, error) {
+	reporter, ok := GetReporter(run.WorkflowName)
+
+	switch format {
+	case ReportJSON:
+		if !ok {
+			return NewRunReport(run), nil
+		}
+		return reporter.JSON(run)
+	case ReportMarkdown:
+		if !ok {
+			return nil, fmt.Errorf("%w: %s for %s", ErrReportUnsupported, format, run.WorkflowName)
+		}
+		return reporter.Markdown(run)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidReportFormat, format)
+	}
+}
@@ -13,6 +13,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// RunStreamBatchSize is the number of runs EachRun reads per query.
+const RunStreamBatchSize = 500
+
+// statusUpdateAttempts bounds retries of run status transitions, which can hit
+// serialization failures when many runs execute concurrently.
+const statusUpdateAttempts = 3
+
 type repo struct {
 	db         *sql.DB
 	logger     *slog.Logger
@@ -28,6 +35,48 @@ func New(db *sql.DB, logger *slog.Logger, pagination pagination.Config) *repo {
 	}
 }
 
+// EachRun passes every workflow run matching filters to fn in sort order,
+// with ID breaking ties. Runs are read in keyset batches of
+// RunStreamBatchSize, each resuming after the last run of the previous one,
+// and the connection is released between batches so a slow consumer never
+// holds it. Runs that change sort values while the stream is read may be
+// skipped or repeated.
+func (r *repo) EachRun(ctx context.Context, filters RunFilters, sort []query.SortField, fn func(Run) error) error {
+	qb := query.NewBuilder(runProjection, runDefaultSort)
+	filters.Apply(qb)
+
+	if err := qb.SortBy(sort); err != nil {
+		return err
+	}
+
+	order := qb.KeysetOrder("ID")
+	var after []any
+
+	for {
+		q, args := qb.BuildKeyset("ID", after, RunStreamBatchSize)
+		runs, err := repository.QueryMany(ctx, r.db, q, args, scanRun)
+		if err != nil {
+			return fmt.Errorf("query runs: %w", err)
+		}
+
+		for _, run := range runs {
+			if err := fn(run); err != nil {
+				return err
+			}
+		}
+
+		if len(runs) < RunStreamBatchSize {
+			return nil
+		}
+
+		last := runs[len(runs)-1]
+		after = make([]any, len(order))
+		for i, f := range order {
+			after[i] = runSortValue(last, f.Field)
+		}
+	}
+}
+
 // ListRuns returns a paginated list of workflow runs.
 func (r *repo) ListRuns(ctx context.Context, page pagination.PageRequest, filters RunFilters) (*pagination.PageResult[Run], error) {
 	page.Normalize(r.pagination)
@@ -35,8 +84,8 @@ func (r *repo) ListRuns(ctx context.Context, page pagination.PageRequest, filter
 	qb := query.NewBuilder(runProjection, runDefaultSort)
 	filters.Apply(qb)
 
-	if len(page.Sort) > 0 {
-		qb.OrderByFields(page.Sort)
+	if err := qb.SortBy(page.Sort); err != nil {
+		return nil, err
 	}
 
 	countSql, countArgs := qb.BuildCount()
@@ -55,6 +104,35 @@ func (r *repo) ListRuns(ctx context.Context, page pagination.PageRequest, filter
 	return &result, nil
 }
 
+// RunFacets returns the distinct values of an allowlisted run field among
+// runs matching filters, in ascending order. Fields outside runFacetFields
+// return ErrInvalidFacet.
+func (r *repo) RunFacets(ctx context.Context, field string, filters RunFilters) (*RunFacets, error) {
+	view, ok := runFacetFields[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFacet, field)
+	}
+
+	qb := query.NewBuilder(runProjection)
+	filters.Apply(qb)
+
+	q, args, err := qb.BuildDistinct(view)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFacet, err)
+	}
+
+	values, err := repository.QueryMany(ctx, r.db, q, args, func(s repository.Scanner) (string, error) {
+		var v string
+		err := s.Scan(&v)
+		return v, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query run facets: %w", err)
+	}
+
+	return &RunFacets{Field: field, Values: values}, nil
+}
+
 // FindRun retrieves a single run by ID.
 func (r *repo) FindRun(ctx context.Context, id uuid.UUID) (*Run, error) {
 	q, args := query.NewBuilder(runProjection).BuildSingle("ID", id)
@@ -67,8 +145,32 @@ func (r *repo) FindRun(ctx context.Context, id uuid.UUID) (*Run, error) {
 	return &run, nil
 }
 
+// FindRuns retrieves the runs with the given IDs, ordered by creation time descending.
+// IDs without a matching run are skipped.
+func (r *repo) FindRuns(ctx context.Context, ids []uuid.UUID) ([]Run, error) {
+	if len(ids) == 0 {
+		return []Run{}, nil
+	}
+
+	values := make([]any, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+
+	q, args := query.NewBuilder(runProjection, runDefaultSort).
+		WhereIn("ID", values).
+		Build()
+
+	runs, err := repository.QueryMany(ctx, r.db, q, args, scanRun)
+	if err != nil {
+		return nil, fmt.Errorf("query runs: %w", err)
+	}
+
+	return runs, nil
+}
+
 // CreateRun inserts a new workflow run with pending status.
-func (r *repo) CreateRun(ctx context.Context, workflowName string, params map[string]any) (*Run, error) {
+func (r *repo) CreateRun(ctx context.Context, workflowName string, params map[string]any, replayedFrom *uuid.UUID) (*Run, error) {
 	var paramsJSON json.RawMessage
 	if params != nil {
 		data, err := json.Marshal(params)
@@ -79,14 +181,14 @@ func (r *repo) CreateRun(ctx context.Context, workflowName string, params map[st
 	}
 
 	const q = `
-		INSERT INTO runs (workflow_name, status, params)
-		VALUES ($1, $2, $3)
-		RETURNING id, workflow_name, status, params, result, error_message, started_at, completed_at, created_at, updated_at
+		INSERT INTO runs (workflow_name, status, params, replayed_from)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, workflow_name, status, params, result, error_message, cancel_reason, replayed_from, started_at, completed_at, created_at, updated_at
 	`
 
 	run, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Run, error) {
 		return repository.QueryOne(ctx, tx, q, []any{
-			workflowName, StatusPending, paramsJSON,
+			workflowName, StatusPending, paramsJSON, replayedFrom,
 		}, scanRun)
 	})
 
@@ -103,11 +205,14 @@ func (r *repo) UpdateRunStarted(ctx context.Context, id uuid.UUID) (*Run, error)
 		UPDATE runs
 		SET status = $1, started_at = NOW(), updated_at = NOW()
 		WHERE id = $2
-		RETURNING id, workflow_name, status, params, result, error_message, started_at, completed_at, created_at, updated_at
+		RETURNING id, workflow_name, status, params, result, error_message, cancel_reason, replayed_from, started_at, completed_at, created_at, updated_at
 	`
 
-	run, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Run, error) {
-		return repository.QueryOne(ctx, tx, q, []any{StatusRunning, id}, scanRun)
+	var run Run
+	err := repository.WithRetry(ctx, r.db, statusUpdateAttempts, func(tx *sql.Tx) error {
+		var err error
+		run, err = repository.QueryOne(ctx, tx, q, []any{StatusRunning, id}, scanRun)
+		return err
 	})
 
 	if err != nil {
@@ -117,8 +222,9 @@ func (r *repo) UpdateRunStarted(ctx context.Context, id uuid.UUID) (*Run, error)
 	return &run, nil
 }
 
-// UpdateRunCompleted sets the final status, result, and completion time for a run.
-func (r *repo) UpdateRunCompleted(ctx context.Context, id uuid.UUID, status RunStatus, result map[string]any, errorMsg *string) (*Run, error) {
+// UpdateRunCompleted sets the final status, result, and completion time for a
+// run. cancelReason is recorded for cancelled runs and nil otherwise.
+func (r *repo) UpdateRunCompleted(ctx context.Context, id uuid.UUID, status RunStatus, result map[string]any, errorMsg *string, cancelReason *CancelReason) (*Run, error) {
 	var resultJSON json.RawMessage
 	if result != nil {
 		data, err := json.Marshal(result)
@@ -130,15 +236,18 @@ func (r *repo) UpdateRunCompleted(ctx context.Context, id uuid.UUID, status RunS
 
 	const q = `
 		UPDATE runs
-		SET status = $1, result = $2, error_message = $3, completed_at = NOW(), updated_at = NOW()
-		WHERE id = $4
-		RETURNING id, workflow_name, status, params, result, error_message, started_at, completed_at, created_at, updated_at
+		SET status = $1, result = $2, error_message = $3, cancel_reason = $4, completed_at = NOW(), updated_at = NOW()
+		WHERE id = $5
+		RETURNING id, workflow_name, status, params, result, error_message, cancel_reason, replayed_from, started_at, completed_at, created_at, updated_at
 	`
 
-	run, err := repository.WithTx(ctx, r.db, func(tx *sql.Tx) (Run, error) {
-		return repository.QueryOne(ctx, tx, q, []any{
-			status, resultJSON, errorMsg, id,
+	var run Run
+	err := repository.WithRetry(ctx, r.db, statusUpdateAttempts, func(tx *sql.Tx) error {
+		var err error
+		run, err = repository.QueryOne(ctx, tx, q, []any{
+			status, resultJSON, errorMsg, cancelReason, id,
 		}, scanRun)
+		return err
 	})
 
 	if err != nil {
@@ -148,10 +257,25 @@ func (r *repo) UpdateRunCompleted(ctx context.Context, id uuid.UUID, status RunS
 	return &run, nil
 }
 
-// GetStages retrieves all stages for a workflow run.
-func (r *repo) GetStages(ctx context.Context, runID uuid.UUID) ([]Stage, error) {
-	qb := query.NewBuilder(stageProjection, stageDefaultSort)
-	qb.WhereEquals("RunID", &runID)
+// ListStages returns a page of stages for a workflow run ordered by creation time.
+func (r *repo) ListStages(ctx context.Context, runID uuid.UUID, page pagination.PageRequest, filters StageFilters) (*pagination.PageResult[Stage], error) {
+	qb := query.NewBuilder(stageProjection, stageDefaultSort).
+		WhereEquals("RunID", &runID)
+	filters.Apply(qb)
+
+	result, err := repository.Paginate(ctx, r.db, qb, page, r.pagination, scanStage)
+	if err != nil {
+		return nil, fmt.Errorf("list stages: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetStages retrieves all stages for a workflow run matching filters.
+func (r *repo) GetStages(ctx context.Context, runID uuid.UUID, filters StageFilters) ([]Stage, error) {
+	qb := query.NewBuilder(stageProjection, stageDefaultSort).
+		WhereEquals("RunID", &runID)
+	filters.Apply(qb)
 
 	q, args := qb.Build()
 
@@ -163,6 +287,19 @@ func (r *repo) GetStages(ctx context.Context, runID uuid.UUID) ([]Stage, error)
 	return stages, nil
 }
 
+// ListDecisions returns a page of routing decisions for a workflow run ordered by creation time.
+func (r *repo) ListDecisions(ctx context.Context, runID uuid.UUID, page pagination.PageRequest) (*pagination.PageResult[Decision], error) {
+	qb := query.NewBuilder(decisionProjection, decisionDefaultSort).
+		WhereEquals("RunID", &runID)
+
+	result, err := repository.Paginate(ctx, r.db, qb, page, r.pagination, scanDecision)
+	if err != nil {
+		return nil, fmt.Errorf("list decisions: %w", err)
+	}
+
+	return result, nil
+}
+
 // GetDecisions retrieves all routing decisions for a workflow run.
 func (r *repo) GetDecisions(ctx context.Context, runID uuid.UUID) ([]Decision, error) {
 	qb := query.NewBuilder(decisionProjection, decisionDefaultSort)
@@ -17,9 +17,15 @@ const (
 	EventDecision      ExecutionEventType = "decision"
 	EventError         ExecutionEventType = "error"
 	EventComplete      ExecutionEventType = "complete"
+	EventPageComplete  ExecutionEventType = "page.complete"
 )
 
+// ExecutionEvent is an event streamed while a run executes. Stage, decision,
+// and final complete or error events carry an ID numbering them in the order
+// the run produced them, which clients send back as Last-Event-ID to resume
+// the stream. Progress events are not persisted and carry no ID.
 type ExecutionEvent struct {
+	ID        int                `json:"id,omitempty"`
 	Type      ExecutionEventType `json:"type"`
 	Timestamp time.Time          `json:"timestamp"`
 	Data      map[string]any     `json:"data"`
@@ -37,6 +43,15 @@ const (
 	StatusCancelled RunStatus = "cancelled"
 )
 
+// Domain event types published to the event bus when a run changes status.
+// Event data is the updated Run.
+const (
+	RunEventStarted   = "run.started"
+	RunEventCompleted = "run.completed"
+	RunEventFailed    = "run.failed"
+	RunEventCancelled = "run.cancelled"
+)
+
 // StageStatus represents the execution state of a workflow stage.
 type StageStatus string
 
@@ -47,7 +62,8 @@ const (
 	StageFailed    StageStatus = "failed"
 )
 
-// Run represents a workflow execution record.
+// Run represents a workflow execution record. ReplayedFrom links a run
+// created by replay to the run whose params it reused.
 type Run struct {
 	ID           uuid.UUID       `json:"id"`
 	WorkflowName string          `json:"workflow_name"`
@@ -55,6 +71,8 @@ type Run struct {
 	Params       json.RawMessage `json:"params,omitempty"`
 	Result       json.RawMessage `json:"result,omitempty"`
 	ErrorMessage *string         `json:"error_message,omitempty"`
+	CancelReason *CancelReason   `json:"cancel_reason,omitempty"`
+	ReplayedFrom *uuid.UUID      `json:"replayed_from,omitempty"`
 	StartedAt    *time.Time      `json:"started_at,omitempty"`
 	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
 	CreatedAt    time.Time       `json:"created_at"`
@@ -93,6 +111,18 @@ type WorkflowInfo struct {
 	Description string `json:"description"`
 }
 
+// CancelAllResult reports how many active runs a cancel-all request cancelled.
+type CancelAllResult struct {
+	Cancelled int `json:"cancelled"`
+}
+
+// RunFacets lists the distinct values of a run field, e.g. every workflow
+// name that has at least one run.
+type RunFacets struct {
+	Field  string   `json:"field"`
+	Values []string `json:"values"`
+}
+
 // NodeStartData represents the data payload for node start events
 // from go-agents-orchestration's observability.Event.
 type NodeStartData struct {
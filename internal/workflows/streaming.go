@@ -11,16 +11,24 @@ import (
 
 // StreamingObserver converts graph execution events to ExecutionEvents
 // and sends them to a buffered channel for SSE streaming.
+//
+// Stage, decision, complete, and error events are numbered in the order they
+// are sent, matching the numbering replayEvents rebuilds from the run's
+// persisted stages and decisions. Progress events are not numbered. Events
+// are also copied to any channels opened with Subscribe.
 type StreamingObserver struct {
-	events chan ExecutionEvent
-	mu     sync.Mutex
-	closed bool
+	events      chan ExecutionEvent
+	subscribers map[chan ExecutionEvent]struct{}
+	seq         int
+	mu          sync.Mutex
+	closed      bool
 }
 
 // NewStreamingObserver creates a StreamingObserver with the specified buffer size.
 func NewStreamingObserver(bufferSize int) *StreamingObserver {
 	return &StreamingObserver{
-		events: make(chan ExecutionEvent, bufferSize),
+		events:      make(chan ExecutionEvent, bufferSize),
+		subscribers: make(map[chan ExecutionEvent]struct{}),
 	}
 }
 
@@ -29,13 +37,64 @@ func (o *StreamingObserver) Events() <-chan ExecutionEvent {
 	return o.events
 }
 
-// Close closes the event channel. Safe to call multiple times.
+// Subscribe returns a channel receiving the events sent after it is opened,
+// and a function that closes the channel and stops delivery. Closing the
+// observer closes every subscribed channel. Subscribing to a closed observer
+// returns a closed channel.
+func (o *StreamingObserver) Subscribe(bufferSize int) (<-chan ExecutionEvent, func()) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ch := make(chan ExecutionEvent, bufferSize)
+	if o.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	o.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if _, ok := o.subscribers[ch]; ok {
+			delete(o.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close closes the event channel and every subscribed channel. Safe to call
+// multiple times.
 func (o *StreamingObserver) Close() {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	if !o.closed {
 		o.closed = true
 		close(o.events)
+		for ch := range o.subscribers {
+			delete(o.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// send delivers event to the event channel and every subscriber, dropping it
+// for any that are full. Numbered events take the next ID even when dropped,
+// so IDs stay aligned with the persisted record. Callers hold o.mu.
+func (o *StreamingObserver) send(event ExecutionEvent, numbered bool) {
+	if numbered {
+		o.seq++
+		event.ID = o.seq
+	}
+
+	select {
+	case o.events <- event:
+	default:
+	}
+	for ch := range o.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
 	}
 }
 
@@ -59,10 +118,7 @@ func (o *StreamingObserver) OnEvent(ctx context.Context, event observability.Eve
 	}
 
 	if execEvent != nil {
-		select {
-		case o.events <- *execEvent:
-		default:
-		}
+		o.send(*execEvent, true)
 	}
 }
 
@@ -73,14 +129,26 @@ func (o *StreamingObserver) SendComplete(result map[string]any) {
 	if o.closed {
 		return
 	}
-	select {
-	case o.events <- ExecutionEvent{
+	o.send(ExecutionEvent{
 		Type:      EventComplete,
 		Timestamp: time.Now(),
 		Data:      map[string]any{"result": result},
-	}:
-	default:
+	}, true)
+}
+
+// SendProgress sends a progress event reported by a node during execution.
+// It satisfies ProgressFunc.
+func (o *StreamingObserver) SendProgress(eventType ExecutionEventType, data map[string]any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return
 	}
+	o.send(ExecutionEvent{
+		Type:      eventType,
+		Timestamp: time.Now(),
+		Data:      data,
+	}, false)
 }
 
 // SendError sends an error event with the error message and optional node name.
@@ -94,14 +162,11 @@ func (o *StreamingObserver) SendError(err error, nodeName string) {
 	if nodeName != "" {
 		data["node_name"] = nodeName
 	}
-	select {
-	case o.events <- ExecutionEvent{
+	o.send(ExecutionEvent{
 		Type:      EventError,
 		Timestamp: time.Now(),
 		Data:      data,
-	}:
-	default:
-	}
+	}, true)
 }
 
 func (o *StreamingObserver) handleNodeStart(event observability.Event) *ExecutionEvent {
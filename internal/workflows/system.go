@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/query"
 	"github.com/google/uuid"
 )
 
@@ -12,12 +13,24 @@ import (
 type System interface {
 	Handler() *Handler
 	ListRuns(ctx context.Context, page pagination.PageRequest, filters RunFilters) (*pagination.PageResult[Run], error)
+	EachRun(ctx context.Context, filters RunFilters, sort []query.SortField, fn func(Run) error) error
+	RunFacets(ctx context.Context, field string, filters RunFilters) (*RunFacets, error)
 	FindRun(ctx context.Context, id uuid.UUID) (*Run, error)
-	GetStages(ctx context.Context, runID uuid.UUID) ([]Stage, error)
+	ActiveRuns() []uuid.UUID
+	ListActiveRuns(ctx context.Context) ([]Run, error)
+	QueuedRuns() []QueuedRun
+	ListStages(ctx context.Context, runID uuid.UUID, page pagination.PageRequest, filters StageFilters) (*pagination.PageResult[Stage], error)
+	GetStages(ctx context.Context, runID uuid.UUID, filters StageFilters) ([]Stage, error)
+	ListDecisions(ctx context.Context, runID uuid.UUID, page pagination.PageRequest) (*pagination.PageResult[Decision], error)
 	GetDecisions(ctx context.Context, runID uuid.UUID) ([]Decision, error)
 	DeleteRun(ctx context.Context, id uuid.UUID) error
 	ListWorkflows() []WorkflowInfo
-	Execute(name string, params map[string]any, token string) (<-chan ExecutionEvent, *Run, error)
-	Cancel(ctx context.Context, runID uuid.UUID) error
-	Resume(ctx context.Context, runID uuid.UUID) (*Run, error)
+	DescribeWorkflow(ctx context.Context, name string) (*WorkflowGraph, error)
+	Execute(name string, params map[string]any, token string, priority int) (<-chan ExecutionEvent, *Run, error)
+	Cancel(ctx context.Context, runID uuid.UUID, reason CancelReason) error
+	CancelAll() int
+	Resume(ctx context.Context, runID uuid.UUID, fromNode string) (*Run, error)
+	Replay(ctx context.Context, runID uuid.UUID, token string, priority int) (<-chan ExecutionEvent, *Run, error)
+	StreamRun(ctx context.Context, runID uuid.UUID, lastEventID int) (<-chan ExecutionEvent, *Run, error)
+	Drain(ctx context.Context) error
 }
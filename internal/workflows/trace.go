@@ -0,0 +1,88 @@
+package workflows
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// TraceFormat identifies the output format of a run trace.
+type TraceFormat string
+
+const (
+	TraceJSON   TraceFormat = "json"
+	TraceNDJSON TraceFormat = "ndjson"
+)
+
+// ParseTraceFormat validates a trace format query value.
+// An empty value defaults to TraceJSON.
+func ParseTraceFormat(value string) (TraceFormat, error) {
+	switch TraceFormat(value) {
+	case "", TraceJSON:
+		return TraceJSON, nil
+	case TraceNDJSON:
+		return TraceNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %q (expected json or ndjson)", ErrInvalidTraceFormat, value)
+	}
+}
+
+// Trace entry types.
+const (
+	TraceEntryRun      = "run"
+	TraceEntryStage    = "stage"
+	TraceEntryDecision = "decision"
+)
+
+// TraceEntry is a single record of a run trace. Exactly one of Run, Stage,
+// or Decision is set, matching Type. Time is the record's creation time.
+type TraceEntry struct {
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	Run      *Run      `json:"run,omitempty"`
+	Stage    *Stage    `json:"stage,omitempty"`
+	Decision *Decision `json:"decision,omitempty"`
+}
+
+// RunTrace is the complete execution record of a run: the run itself and
+// its stages and decisions interleaved in chronological order.
+type RunTrace struct {
+	Run    Run          `json:"run"`
+	Events []TraceEntry `json:"events"`
+}
+
+// BuildTrace assembles the trace of run from its stages and decisions.
+// Events are ordered by creation time; a stage and decision recorded at the
+// same instant keep stages first, since a routing decision follows the stage
+// it leaves. When snapshots is false, stage input and output snapshots are
+// omitted.
+func BuildTrace(run *Run, stages []Stage, decisions []Decision, snapshots bool) RunTrace {
+	events := make([]TraceEntry, 0, len(stages)+len(decisions))
+
+	for _, s := range stages {
+		if !snapshots {
+			s.InputSnapshot = nil
+			s.OutputSnapshot = nil
+		}
+		events = append(events, TraceEntry{Type: TraceEntryStage, Time: s.CreatedAt, Stage: &s})
+	}
+
+	for _, d := range decisions {
+		events = append(events, TraceEntry{Type: TraceEntryDecision, Time: d.CreatedAt, Decision: &d})
+	}
+
+	slices.SortStableFunc(events, func(a, b TraceEntry) int {
+		return cmp.Compare(a.Time.UnixNano(), b.Time.UnixNano())
+	})
+
+	return RunTrace{Run: *run, Events: events}
+}
+
+// Entries returns the trace as a flat sequence: a run entry followed by the
+// chronological events. This is the line order of the NDJSON format.
+func (t RunTrace) Entries() []TraceEntry {
+	entries := make([]TraceEntry, 0, len(t.Events)+1)
+	entries = append(entries, TraceEntry{Type: TraceEntryRun, Time: t.Run.CreatedAt, Run: &t.Run})
+	return append(entries, t.Events...)
+}
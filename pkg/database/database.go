@@ -52,20 +52,20 @@ func (d *database) Connection() *sql.DB {
 func (d *database) Start(lc *lifecycle.Coordinator) error {
 	d.logger.Info("starting database connection")
 
-	lc.OnStartup(func() {
+	lc.Register("database", func() error {
 		pingCtx, cancel := context.WithTimeout(lc.Context(), d.connTimeout)
 		defer cancel()
 
 		if err := d.conn.PingContext(pingCtx); err != nil {
 			d.logger.Error("database ping failed", "error", err)
-			return
+			return fmt.Errorf("ping database: %w", err)
 		}
 
 		d.logger.Info("database connection established")
+		return nil
 	})
 
-	lc.OnShutdown(func() {
-		<-lc.Context().Done()
+	lc.OnStop("database", func() {
 		d.logger.Info("closing database connection")
 
 		if err := d.conn.Close(); err != nil {
@@ -0,0 +1,111 @@
+// Package events provides an in-process publish/subscribe bus for domain events.
+// Handlers run asynchronously so a slow or failing subscriber never blocks
+// or crashes the publisher.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// All subscribes a handler to every event type.
+const All = "*"
+
+// Event is a domain occurrence published to the bus.
+// Type identifies the event (e.g. "run.completed"), Subject identifies
+// the affected resource (typically its ID), and Data carries the domain
+// payload, typically the affected entity.
+type Event struct {
+	Type    string    `json:"type"`
+	Subject string    `json:"subject,omitempty"`
+	Time    time.Time `json:"time"`
+	Data    any       `json:"data,omitempty"`
+}
+
+// Handler processes a published event.
+type Handler func(ctx context.Context, event Event)
+
+// Bus dispatches published events to subscribed handlers.
+// A nil *Bus is valid and discards all events, so systems can be
+// constructed without a bus in tests.
+type Bus struct {
+	handlers map[string][]Handler
+	mu       sync.RWMutex
+	inflight sync.WaitGroup
+	logger   *slog.Logger
+}
+
+// New creates an empty event bus.
+func New(logger *slog.Logger) *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+		logger:   logger.With("system", "events"),
+	}
+}
+
+// Subscribe registers handler for events of eventType.
+// Use All to receive every event.
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish delivers event to all matching subscribers without waiting for them.
+// Each handler runs in its own goroutine with a context detached from ctx's
+// cancellation, and panics are recovered and logged.
+// Event.Time is set to the current time if zero.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[event.Type])+len(b.handlers[All]))
+	handlers = append(handlers, b.handlers[event.Type]...)
+	handlers = append(handlers, b.handlers[All]...)
+	b.mu.RUnlock()
+
+	handlerCtx := context.WithoutCancel(ctx)
+	for _, handler := range handlers {
+		b.inflight.Go(func() {
+			b.dispatch(handlerCtx, handler, event)
+		})
+	}
+}
+
+// Wait blocks until all in-flight handlers finish or ctx expires.
+func (b *Bus) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Bus) dispatch(ctx context.Context, handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("event handler panicked", "type", event.Type, "panic", r)
+		}
+	}()
+
+	handler(ctx, event)
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVFlushInterval is the number of rows a CSVEncoder writes between flushes
+// to the client.
+const CSVFlushInterval = 100
+
+// WantsCSV reports whether the request asks for a CSV response, either with
+// a format=csv query parameter or an Accept header listing text/csv.
+func WantsCSV(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "csv" {
+		return true
+	}
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mt, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && mt == "text/csv" {
+			return true
+		}
+	}
+	return false
+}
+
+// CSVEncoder writes values of a struct type T as CSV rows.
+//
+// Columns follow T's exported fields in declaration order and are named by
+// their json tags; fields tagged "-" are skipped and embedded structs are
+// flattened into the parent. Values that implement encoding.TextMarshaler
+// (times, UUIDs) use their text form, with times in RFC 3339. Structs, maps,
+// slices, and json.RawMessage values are JSON-encoded into a single cell. Nil
+// pointers and empty collections produce an empty cell.
+//
+// When the underlying writer is an http.Flusher, rows are flushed to it every
+// CSVFlushInterval rows so a long export reaches the client while it is
+// produced rather than when it ends.
+type CSVEncoder[T any] struct {
+	out     io.Writer
+	w       *csv.Writer
+	columns []csvColumn
+	rows    int
+}
+
+type csvColumn struct {
+	name  string
+	index []int
+}
+
+// NewCSVEncoder creates an encoder writing to w. T must be a struct type.
+func NewCSVEncoder[T any](w io.Writer) *CSVEncoder[T] {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("handlers: CSVEncoder requires a struct type, got %s", t))
+	}
+
+	return &CSVEncoder[T]{
+		out:     w,
+		w:       csv.NewWriter(w),
+		columns: csvColumns(t, nil),
+	}
+}
+
+// Header returns the column names in output order.
+func (e *CSVEncoder[T]) Header() []string {
+	names := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+// WriteHeader writes the header row.
+func (e *CSVEncoder[T]) WriteHeader() error {
+	return e.w.Write(e.Header())
+}
+
+// Encode writes v as a single row.
+func (e *CSVEncoder[T]) Encode(v T) error {
+	rv := reflect.ValueOf(v)
+	record := make([]string, len(e.columns))
+
+	for i, c := range e.columns {
+		field, err := rv.FieldByIndexErr(c.index)
+		if err != nil {
+			continue
+		}
+		cell, err := csvCell(field)
+		if err != nil {
+			return fmt.Errorf("encode column %s: %w", c.name, err)
+		}
+		record[i] = cell
+	}
+
+	if err := e.w.Write(record); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%CSVFlushInterval == 0 {
+		return e.Flush()
+	}
+	return nil
+}
+
+// Flush writes any buffered rows to the underlying writer, then flushes it
+// if it is an http.Flusher.
+func (e *CSVEncoder[T]) Flush() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	if f, ok := e.out.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// RespondCSV streams rows as a CSV attachment named filename. The header row
+// is written first, then write is called to encode each row.
+//
+// Rows are flushed to the client every CSVFlushInterval rows; write should
+// read its source incrementally so memory use stays constant regardless of
+// how many rows are exported. If write fails before any output is sent the
+// failure is reported as a normal JSON error with status 500. Once output
+// has been sent the status can no longer change; the error is logged and the
+// response ends early.
+func RespondCSV[T any](w http.ResponseWriter, logger *slog.Logger, filename string, write func(enc *CSVEncoder[T]) error) {
+	out := &countingWriter{w: w}
+	enc := NewCSVEncoder[T](out)
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	err := enc.WriteHeader()
+	if err == nil {
+		err = write(enc)
+	}
+
+	if err != nil {
+		if out.n == 0 {
+			w.Header().Del("Content-Disposition")
+			RespondError(w, logger, http.StatusInternalServerError, err)
+			return
+		}
+		logger.Error("csv stream aborted", "error", err, "bytes", out.n)
+		return
+	}
+
+	if err := enc.Flush(); err != nil {
+		logger.Error("csv stream aborted", "error", err, "bytes", out.n)
+	}
+}
+
+// countingWriter counts the bytes written through to w.
+type countingWriter struct {
+	w http.ResponseWriter
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Flush sends written bytes to the client. Writers that cannot flush deliver
+// their output when the response ends.
+func (c *countingWriter) Flush() {
+	http.NewResponseController(c.w).Flush()
+}
+
+var (
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	timeType          = reflect.TypeFor[time.Time]()
+)
+
+func csvColumns(t reflect.Type, parent []int) []csvColumn {
+	var columns []csvColumn
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct && !ft.Implements(textMarshalerType) {
+			columns = append(columns, csvColumns(ft, index)...)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		columns = append(columns, csvColumn{name: name, index: index})
+	}
+
+	return columns
+}
+
+func csvCell(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(time.RFC3339), nil
+	case v.Type() == rawMessageType:
+		return string(v.Bytes()), nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	case reflect.Slice, reflect.Map:
+		if v.Len() == 0 {
+			return "", nil
+		}
+	}
+
+	data, err := json.Marshal(v.Interface())
+	return string(data), err
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodySize is the request body limit applied by DecodeJSON when
+// maxBytes is not positive.
+const DefaultMaxBodySize int64 = 1 << 20
+
+// Errors returned by DecodeJSON.
+var (
+	ErrEmptyBody     = errors.New("request body is empty")
+	ErrBodyTooLarge  = errors.New("request body too large")
+	ErrMalformedJSON = errors.New("malformed JSON")
+	ErrUnknownField  = errors.New("unknown field")
+)
+
+func init() {
+	RegisterErrorCode("empty_body", ErrEmptyBody)
+	RegisterErrorCode("body_too_large", ErrBodyTooLarge)
+	RegisterErrorCode("malformed_json", ErrMalformedJSON)
+	RegisterErrorCode("unknown_field", ErrUnknownField)
+}
+
+// DecodeJSON decodes a single JSON value from the request body into dst.
+// The body is limited to maxBytes via http.MaxBytesReader (DefaultMaxBodySize
+// if maxBytes <= 0), and unknown fields are rejected. Returned errors wrap
+// ErrEmptyBody, ErrBodyTooLarge, ErrMalformedJSON, or ErrUnknownField with a
+// client-friendly message; use DecodeStatus to map them to an HTTP status.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any, maxBytes int64) error {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBodySize
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return decodeError(err)
+		}
+		return fmt.Errorf("%w: body must contain a single JSON value", ErrMalformedJSON)
+	}
+
+	return nil
+}
+
+// DecodeStatus maps DecodeJSON errors to HTTP status codes.
+// ErrBodyTooLarge maps to 413; all other decode failures map to 400.
+func DecodeStatus(err error) int {
+	if errors.Is(err, ErrBodyTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+func decodeError(err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		maxErr    *http.MaxBytesError
+	)
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrEmptyBody
+	case errors.As(err, &maxErr):
+		return fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, maxErr.Limit)
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("%w: syntax error at offset %d", ErrMalformedJSON, syntaxErr.Offset)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: unexpected end of body", ErrMalformedJSON)
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return fmt.Errorf("%w: invalid value for field %q", ErrMalformedJSON, typeErr.Field)
+		}
+		return fmt.Errorf("%w: invalid value at offset %d", ErrMalformedJSON, typeErr.Offset)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return fmt.Errorf("%w %s", ErrUnknownField, field)
+	default:
+		return fmt.Errorf("%w: %v", ErrMalformedJSON, err)
+	}
+}
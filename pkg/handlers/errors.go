@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrorBody is the machine-readable error payload written by RespondError.
+// Code is a stable identifier clients can branch on; Details carries optional
+// context such as field-level validation failures.
+type ErrorBody struct {
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// ErrorResponse is the JSON envelope for all error responses.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+type errorCode struct {
+	err  error
+	code string
+}
+
+var (
+	codesMu sync.RWMutex
+	codes   []errorCode
+)
+
+// RegisterErrorCode associates a stable error code with one or more sentinel errors.
+// Domain packages register their sentinels at init so RespondError can resolve
+// codes for wrapped errors via errors.Is.
+func RegisterErrorCode(code string, errs ...error) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+
+	for _, err := range errs {
+		codes = append(codes, errorCode{err: err, code: code})
+	}
+}
+
+// ErrorCode resolves the stable code for err. Registered sentinel errors take
+// precedence; otherwise the code is derived from the HTTP status.
+func ErrorCode(err error, status int) string {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+
+	return StatusCode(status)
+}
+
+// StatusCode derives a snake_case error code from an HTTP status.
+// For example, 404 becomes "not_found" and 413 becomes "request_entity_too_large".
+func StatusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+
+	text = strings.ToLower(text)
+	text = strings.ReplaceAll(text, "'", "")
+	text = strings.ReplaceAll(text, "-", "_")
+	return strings.ReplaceAll(text, " ", "_")
+}
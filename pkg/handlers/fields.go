@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// FieldsParam is the query parameter that restricts a JSON response to a
+// comma-separated list of top-level fields, e.g. ?fields=id,name.
+const FieldsParam = "fields"
+
+// JSONFields returns the top-level JSON field names of struct type T in
+// declaration order, following the same rules as CSVEncoder columns. It
+// returns nil when T is not a struct.
+func JSONFields[T any]() []string {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	columns := csvColumns(t, nil)
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+// RequestedFields returns the fields named in r's fields query parameter that
+// T serializes, in request order without duplicates. Unknown names are
+// ignored. A nil result means the full object should be returned, either
+// because the parameter is absent or none of its fields are known.
+func RequestedFields[T any](r *http.Request) []string {
+	raw := r.URL.Query().Get(FieldsParam)
+	if raw == "" {
+		return nil
+	}
+
+	allowed := make(map[string]bool)
+	for _, name := range JSONFields[T]() {
+		allowed[name] = true
+	}
+
+	var fields []string
+	for name := range strings.SplitSeq(raw, ",") {
+		name = strings.TrimSpace(name)
+		if allowed[name] {
+			fields = append(fields, name)
+			delete(allowed, name)
+		}
+	}
+	return fields
+}
+
+// SelectFields encodes v as a JSON object and keeps only the listed fields.
+// Field values are kept as encoded, so numbers and nested objects round-trip
+// unchanged. Fields absent from the encoded object, such as empty omitempty
+// fields, are left out.
+func SelectFields(v any, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, name := range fields {
+		if value, ok := all[name]; ok {
+			selected[name] = value
+		}
+	}
+	return selected, nil
+}
+
+// RespondFields writes v as a JSON response like RespondJSON, restricted to
+// the fields requested with the fields query parameter. The allowlist is the
+// set of fields T serializes; without a usable fields parameter the full
+// object is written.
+func RespondFields[T any](w http.ResponseWriter, r *http.Request, status int, v *T) {
+	fields := RequestedFields[T](r)
+	if fields == nil {
+		RespondJSON(w, status, v)
+		return
+	}
+
+	selected, err := SelectFields(v, fields)
+	if err != nil {
+		// v cannot be encoded; respond as RespondJSON would.
+		RespondJSON(w, status, v)
+		return
+	}
+	RespondJSON(w, status, selected)
+}
@@ -4,7 +4,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
+	"maps"
 	"net/http"
 )
 
@@ -16,11 +18,41 @@ func RespondJSON(w http.ResponseWriter, status int, data any) {
 	json.NewEncoder(w).Encode(data)
 }
 
-// RespondError logs the error and writes a JSON error response.
-// The response body contains {"error": "<error message>"}.
-func RespondError(w http.ResponseWriter, logger *slog.Logger, status int, err error) {
+// detailedError is an error that carries structured details for the
+// response envelope.
+type detailedError interface {
+	error
+	Details() map[string]any
+}
+
+// RespondError logs the error and writes a JSON error envelope.
+// The response body contains {"error": {"code": "...", "message": "...", "details": {...}}}
+// where code is resolved by ErrorCode. Optional details are merged into the
+// details object, typically to report field-level validation failures.
+// An error in err's chain with a Details method, such as *ValidationError,
+// contributes its details automatically.
+func RespondError(w http.ResponseWriter, logger *slog.Logger, status int, err error, details ...map[string]any) {
 	logger.Error("handler error", "error", err, "status", status)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+
+	body := ErrorBody{
+		Code:    ErrorCode(err, status),
+		Message: err.Error(),
+	}
+
+	var derr detailedError
+	if errors.As(err, &derr) {
+		details = append([]map[string]any{derr.Details()}, details...)
+	}
+
+	for _, d := range details {
+		if len(d) == 0 {
+			continue
+		}
+		if body.Details == nil {
+			body.Details = make(map[string]any, len(d))
+		}
+		maps.Copy(body.Details, d)
+	}
+
+	RespondJSON(w, status, ErrorResponse{Error: body})
 }
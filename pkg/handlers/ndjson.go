@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// NDJSONContentType is the media type of newline-delimited JSON responses.
+const NDJSONContentType = "application/x-ndjson"
+
+// NDJSONFlushInterval is the number of lines an NDJSONEncoder writes between
+// flushes to the client.
+const NDJSONFlushInterval = 100
+
+// WantsNDJSON reports whether the request asks for a newline-delimited JSON
+// response, either with a format=ndjson query parameter or an Accept header
+// listing application/x-ndjson.
+func WantsNDJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "ndjson" {
+		return true
+	}
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mt, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && mt == NDJSONContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// NDJSONEncoder writes values of type T as one JSON object per line,
+// flushing to the client every NDJSONFlushInterval lines so a long stream
+// reaches the client while it is produced rather than when it ends.
+type NDJSONEncoder[T any] struct {
+	w       http.ResponseWriter
+	started bool
+	lines   int
+}
+
+// Encode writes v as a single line. The first line commits the response
+// with status 200.
+func (e *NDJSONEncoder[T]) Encode(v T) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", NDJSONContentType)
+		e.w.WriteHeader(http.StatusOK)
+	}
+
+	if _, err := e.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	e.lines++
+	if e.lines%NDJSONFlushInterval == 0 {
+		e.Flush()
+	}
+	return nil
+}
+
+// Flush sends any buffered lines to the client. Writers that cannot flush
+// deliver their output when the response ends.
+func (e *NDJSONEncoder[T]) Flush() {
+	http.NewResponseController(e.w).Flush()
+}
+
+// RespondNDJSON streams values as newline-delimited JSON. write is called to
+// encode each value; it should read its source incrementally so memory use
+// stays constant regardless of how many values are streamed.
+//
+// If write fails before the first line is written the failure is reported
+// as a normal JSON error with status 500. Once lines have been sent the
+// status can no longer change; the error is logged and the response ends
+// early.
+func RespondNDJSON[T any](w http.ResponseWriter, logger *slog.Logger, write func(enc *NDJSONEncoder[T]) error) {
+	enc := &NDJSONEncoder[T]{w: w}
+
+	if err := write(enc); err != nil {
+		if !enc.started {
+			RespondError(w, logger, http.StatusInternalServerError, err)
+			return
+		}
+		logger.Error("ndjson stream aborted", "error", err, "lines", enc.lines)
+		return
+	}
+
+	if !enc.started {
+		w.Header().Set("Content-Type", NDJSONContentType)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	enc.Flush()
+}
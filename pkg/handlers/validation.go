@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrValidation is the sentinel matched by every ValidationError.
+var ErrValidation = errors.New("validation failed")
+
+func init() {
+	RegisterErrorCode("validation_failed", ErrValidation)
+}
+
+// ValidationError collects per-field validation messages for a command.
+// RespondError reports the messages under details.fields.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+// Add records msg for field. The first message recorded for a field is kept.
+func (e *ValidationError) Add(field, msg string) {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = msg
+	}
+}
+
+// Err returns e if any field failed validation, otherwise nil.
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	fields := slices.Sorted(maps.Keys(e.Fields))
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = fmt.Sprintf("%s: %s", f, e.Fields[f])
+	}
+	return fmt.Sprintf("%v: %s", ErrValidation, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// Details returns the error details reported in the response envelope.
+func (e *ValidationError) Details() map[string]any {
+	return map[string]any{"fields": e.Fields}
+}
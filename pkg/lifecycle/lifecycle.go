@@ -3,33 +3,48 @@ package lifecycle
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 )
 
+// drainGraceDivisor sets the share of the Shutdown timeout held back from the
+// drain deadline: drain hooks get the timeout less timeout/drainGraceDivisor.
+const drainGraceDivisor = 4
+
 // ReadinessChecker provides a simple interface for checking if a system is ready.
 type ReadinessChecker interface {
 	Ready() bool
 }
 
 // Coordinator manages application lifecycle including startup hooks, shutdown hooks,
-// and readiness state. It provides a shared context that is cancelled during shutdown.
+// ordered subsystem startup, and readiness state. It provides a shared context
+// that is cancelled during shutdown.
 type Coordinator struct {
-	ctx        context.Context
-	cancel     context.CancelFunc
-	startupWg  sync.WaitGroup
-	shutdownWg sync.WaitGroup
-	ready      bool
-	readyMu    sync.RWMutex
+	ctx         context.Context
+	cancel      context.CancelFunc
+	startupWg   sync.WaitGroup
+	shutdownWg  sync.WaitGroup
+	drainFns    []func(context.Context)
+	drainMu     sync.Mutex
+	subsystems  []subsystem
+	stopFns     map[string]func()
+	started     []string
+	subsystemMu sync.Mutex
+	startOnce   sync.Once
+	startErr    error
+	ready       bool
+	readyMu     sync.RWMutex
 }
 
 // New creates a new Coordinator with an active context.
 func New() *Coordinator {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Coordinator{
-		ctx:    ctx,
-		cancel: cancel,
+		ctx:     ctx,
+		cancel:  cancel,
+		stopFns: make(map[string]func()),
 	}
 }
 
@@ -50,6 +65,18 @@ func (c *Coordinator) OnShutdown(fn func()) {
 	c.shutdownWg.Go(fn)
 }
 
+// OnDrain registers a function to run during the drain phase of shutdown.
+// Drain functions run concurrently before the context is cancelled, allowing
+// in-flight work to finish while dependencies such as the database remain open.
+// The provided context expires a grace period before the shutdown deadline;
+// work still running then should be stopped and its final state recorded
+// before the function returns, which Shutdown waits for until the deadline.
+func (c *Coordinator) OnDrain(fn func(ctx context.Context)) {
+	c.drainMu.Lock()
+	defer c.drainMu.Unlock()
+	c.drainFns = append(c.drainFns, fn)
+}
+
 // Ready returns true after WaitForStartup has completed.
 func (c *Coordinator) Ready() bool {
 	c.readyMu.RLock()
@@ -57,29 +84,91 @@ func (c *Coordinator) Ready() bool {
 	return c.ready
 }
 
-// WaitForStartup blocks until all startup hooks complete, then marks the coordinator as ready.
-func (c *Coordinator) WaitForStartup() {
+// WaitForStartup blocks until all startup hooks complete, then starts registered
+// subsystems in dependency order. It returns the first startup error, after
+// stopping any subsystems that had already started; otherwise it marks the
+// coordinator as ready. Subsystems are started at most once.
+func (c *Coordinator) WaitForStartup() error {
 	c.startupWg.Wait()
+
+	c.startOnce.Do(func() {
+		c.startErr = c.startSubsystems()
+	})
+	if c.startErr != nil {
+		return c.startErr
+	}
+
 	c.readyMu.Lock()
 	c.ready = true
 	c.readyMu.Unlock()
+	return nil
 }
 
-// Shutdown cancels the context and waits for all shutdown hooks to complete.
-// Returns an error if shutdown does not complete within the timeout.
+// Shutdown drains registered drain hooks, cancels the context, stops started
+// subsystems in reverse start order, and waits for all shutdown hooks to complete.
+// Both phases share a single deadline timeout from the call. The drain hooks'
+// context expires a quarter of timeout before that deadline, holding back a
+// grace period in which they stop unfinished work and record it, and in which
+// the shutdown hooks run once the drain hooks return. Returns an error if the
+// drain deadline passes or the shutdown hooks do not complete in time.
 func (c *Coordinator) Shutdown(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	drainErr := c.drain(ctx, timeout-timeout/drainGraceDivisor)
+
 	c.cancel()
 
 	done := make(chan struct{})
 	go func() {
+		c.stopSubsystems()
 		c.shutdownWg.Wait()
 		close(done)
 	}()
 
 	select {
 	case <-done:
+		return drainErr
+	case <-ctx.Done():
+		return errors.Join(drainErr, fmt.Errorf("shutdown timeout after %v", timeout))
+	}
+}
+
+// drain runs the drain hooks with a context that expires after budget, then
+// waits for them to return until ctx expires.
+func (c *Coordinator) drain(ctx context.Context, budget time.Duration) error {
+	c.drainMu.Lock()
+	fns := c.drainFns
+	c.drainMu.Unlock()
+
+	if len(fns) == 0 {
 		return nil
-	case <-time.After(timeout):
-		return fmt.Errorf("shutdown timeout after %v", timeout)
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, fn := range fns {
+		wg.Go(func() { fn(drainCtx) })
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-drainCtx.Done():
+	}
+
+	select {
+	case <-done:
+		return fmt.Errorf("drain timeout after %v", budget)
+	case <-ctx.Done():
+		return fmt.Errorf("drain timeout after %v; drain hooks still running at shutdown deadline", budget)
 	}
 }
@@ -0,0 +1,130 @@
+package lifecycle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// subsystem is a named startup step with optional dependencies and stop hook.
+type subsystem struct {
+	name  string
+	start func() error
+	deps  []string
+}
+
+// Register adds a named subsystem whose start function runs during
+// WaitForStartup after every subsystem named in deps has started.
+// Subsystems without a dependency relationship start in registration order.
+func (c *Coordinator) Register(name string, start func() error, deps ...string) {
+	c.subsystemMu.Lock()
+	defer c.subsystemMu.Unlock()
+	c.subsystems = append(c.subsystems, subsystem{name: name, start: start, deps: deps})
+}
+
+// OnStop registers a function that stops the named subsystem. Stop functions
+// run in reverse start order during Shutdown, or immediately for subsystems
+// that already started when a later subsystem fails to start.
+func (c *Coordinator) OnStop(name string, fn func()) {
+	c.subsystemMu.Lock()
+	defer c.subsystemMu.Unlock()
+	c.stopFns[name] = fn
+}
+
+// startSubsystems starts registered subsystems in dependency order.
+// If a subsystem fails, those already started are stopped in reverse order.
+func (c *Coordinator) startSubsystems() error {
+	c.subsystemMu.Lock()
+	order, err := startupOrder(c.subsystems)
+	c.subsystemMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range order {
+		if err := s.start(); err != nil {
+			c.stopSubsystems()
+			return fmt.Errorf("start %s: %w", s.name, err)
+		}
+
+		c.subsystemMu.Lock()
+		c.started = append(c.started, s.name)
+		c.subsystemMu.Unlock()
+	}
+
+	return nil
+}
+
+// stopSubsystems runs stop functions for started subsystems in reverse start order.
+func (c *Coordinator) stopSubsystems() {
+	c.subsystemMu.Lock()
+	started := c.started
+	c.started = nil
+	c.subsystemMu.Unlock()
+
+	for _, name := range slices.Backward(started) {
+		c.subsystemMu.Lock()
+		fn := c.stopFns[name]
+		c.subsystemMu.Unlock()
+
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// startupOrder topologically sorts subsystems so each follows its dependencies.
+// It returns an error for duplicate names, unknown dependencies, or cycles.
+func startupOrder(subsystems []subsystem) ([]subsystem, error) {
+	index := make(map[string]int, len(subsystems))
+	for i, s := range subsystems {
+		if _, ok := index[s.name]; ok {
+			return nil, fmt.Errorf("subsystem %s registered more than once", s.name)
+		}
+		index[s.name] = i
+	}
+
+	pending := make([]int, len(subsystems))
+	dependents := make([][]int, len(subsystems))
+	for i, s := range subsystems {
+		for _, dep := range s.deps {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("subsystem %s depends on unknown subsystem %s", s.name, dep)
+			}
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	order := make([]subsystem, 0, len(subsystems))
+	done := make([]bool, len(subsystems))
+
+	for len(order) < len(subsystems) {
+		next := -1
+		for i := range subsystems {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			var cycle []string
+			for i, s := range subsystems {
+				if !done[i] {
+					cycle = append(cycle, s.name)
+				}
+			}
+			return nil, fmt.Errorf("subsystem dependency cycle among: %s", strings.Join(cycle, ", "))
+		}
+
+		done[next] = true
+		order = append(order, subsystems[next])
+		for _, d := range dependents[next] {
+			pending[d]--
+		}
+	}
+
+	return order, nil
+}
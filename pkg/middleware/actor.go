@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// DefaultActorHeader is the request header that identifies the caller
+// when no authentication layer populates the actor.
+const DefaultActorHeader = "X-Actor"
+
+type actorKey struct{}
+
+// Actor returns middleware that stores the value of header in the request
+// context as the acting identity. Requests without the header carry no actor.
+func Actor(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if actor := r.Header.Get(header); actor != "" {
+				r = r.WithContext(WithActor(r.Context(), actor))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithActor returns a copy of ctx carrying actor as the acting identity.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the acting identity stored in ctx, or "" if none is set.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"fmt"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
@@ -26,13 +28,17 @@ type CORSEnv struct {
 	MaxAge           string
 }
 
-// Finalize applies defaults and loads environment variable overrides.
+// MaxCORSMaxAge is the largest accepted max_age in seconds. Browsers cap
+// preflight caching at or below this value.
+const MaxCORSMaxAge = 86400
+
+// Finalize applies defaults, loads environment variable overrides, and validates.
 func (c *CORSConfig) Finalize(env *CORSEnv) error {
 	c.loadDefaults()
 	if env != nil {
 		c.loadEnv(env)
 	}
-	return nil
+	return c.validate()
 }
 
 // Merge applies non-zero values from the overlay configuration.
@@ -54,9 +60,16 @@ func (c *CORSConfig) Merge(overlay *CORSConfig) {
 	}
 }
 
+func (c *CORSConfig) validate() error {
+	if c.MaxAge < 0 || c.MaxAge > MaxCORSMaxAge {
+		return fmt.Errorf("invalid max_age: %d (must be between 0 and %d seconds)", c.MaxAge, MaxCORSMaxAge)
+	}
+	return nil
+}
+
 func (c *CORSConfig) loadDefaults() {
 	if len(c.AllowedMethods) == 0 {
-		c.AllowedMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+		c.AllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
 	}
 	if len(c.AllowedHeaders) == 0 {
 		c.AllowedHeaders = []string{"Content-Type", "Authorization"}
@@ -127,3 +140,68 @@ func (c *CORSConfig) loadEnv(env *CORSEnv) {
 		}
 	}
 }
+
+// ProxyConfig lists the reverse proxies whose forwarding headers are trusted.
+// Entries are IP addresses or CIDR ranges; an empty list trusts no proxy.
+type ProxyConfig struct {
+	TrustedProxies []string `toml:"trusted_proxies"`
+}
+
+// ProxyEnv maps environment variable names for proxy configuration.
+type ProxyEnv struct {
+	TrustedProxies string
+}
+
+// Finalize loads environment variable overrides and validates every entry.
+func (c *ProxyConfig) Finalize(env *ProxyEnv) error {
+	if env != nil {
+		c.loadEnv(env)
+	}
+	_, err := parseTrustedProxies(c.TrustedProxies)
+	return err
+}
+
+// Merge applies non-nil values from the overlay configuration.
+func (c *ProxyConfig) Merge(overlay *ProxyConfig) {
+	if overlay.TrustedProxies != nil {
+		c.TrustedProxies = overlay.TrustedProxies
+	}
+}
+
+func (c *ProxyConfig) loadEnv(env *ProxyEnv) {
+	if env.TrustedProxies != "" {
+		if v := os.Getenv(env.TrustedProxies); v != "" {
+			proxies := strings.Split(v, ",")
+			c.TrustedProxies = make([]string, 0, len(proxies))
+			for _, proxy := range proxies {
+				if trimmed := strings.TrimSpace(proxy); trimmed != "" {
+					c.TrustedProxies = append(c.TrustedProxies, trimmed)
+				}
+			}
+		}
+	}
+}
+
+// parseTrustedProxies converts IP and CIDR entries to prefixes; a bare IP
+// matches only itself.
+func parseTrustedProxies(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
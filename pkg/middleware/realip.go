@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type clientIPKey struct{}
+
+// RealIP returns middleware that resolves the client IP of each request and
+// stores it in the request context, also replacing r.RemoteAddr with it.
+//
+// Forwarding headers are honored only when the immediate peer is one of
+// cfg's trusted proxies; from any other peer they are ignored, so clients
+// cannot spoof their address. X-Forwarded-For is read right to left,
+// skipping trusted proxies, and the first untrusted hop is the client.
+// X-Real-IP is used when X-Forwarded-For is absent. Invalid entries in cfg
+// are skipped; validate them with ProxyConfig.Finalize.
+func RealIP(cfg *ProxyConfig) func(http.Handler) http.Handler {
+	trusted, _ := parseTrustedProxies(cfg.TrustedProxies)
+
+	isTrusted := func(addr netip.Addr) bool {
+		for _, prefix := range trusted {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, ok := parseRemoteAddr(r.RemoteAddr)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := peer
+			if isTrusted(peer) {
+				client = forwardedClient(r.Header, peer, isTrusted)
+			}
+
+			r.RemoteAddr = client.String()
+			next.ServeHTTP(w, r.WithContext(WithClientIP(r.Context(), client.String())))
+		})
+	}
+}
+
+// WithClientIP returns a copy of ctx carrying ip as the client address.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFrom returns the client IP stored in ctx by RealIP, or "" if none
+// is set.
+func ClientIPFrom(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+// forwardedClient returns the client address reported by a trusted peer.
+// Hops are walked from the nearest proxy outward; the walk stops at the
+// first untrusted hop or at an unparsable one, keeping the last valid
+// address seen.
+func forwardedClient(h http.Header, peer netip.Addr, isTrusted func(netip.Addr) bool) netip.Addr {
+	var hops []string
+	for _, v := range h.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	if len(hops) == 0 {
+		if v := h.Get("X-Real-IP"); v != "" {
+			hops = []string{v}
+		}
+	}
+
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		client = addr.Unmap()
+		if !isTrusted(client) {
+			break
+		}
+	}
+	return client
+}
+
+// parseRemoteAddr extracts the IP from a host:port remote address, also
+// accepting a bare IP.
+func parseRemoteAddr(remoteAddr string) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/signedurl"
+)
+
+// SignedURL returns middleware that verifies requests carrying a signed URL
+// signature. A valid signature passes the request through; a tampered,
+// malformed, or expired signature is rejected with 403. Requests without a
+// signature pass through unchanged.
+func SignedURL(signer *signedurl.Signer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if !signedurl.HasSignature(query) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := signer.Verify(r.URL.Path, query); err != nil {
+				handlers.RespondJSON(w, http.StatusForbidden, handlers.ErrorResponse{
+					Error: handlers.ErrorBody{
+						Code:    handlers.ErrorCode(err, http.StatusForbidden),
+						Message: err.Error(),
+					},
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+)
+
+// ErrRequestTimeout is the cause attached to a request context canceled by Timeout.
+var ErrRequestTimeout = errors.New("request timed out")
+
+func init() {
+	handlers.RegisterErrorCode("request_timeout", ErrRequestTimeout)
+}
+
+// Timeout returns middleware that bounds how long a handler may run before
+// responding. The handler's response is buffered; if the handler has not
+// finished after d, the request context is canceled with ErrRequestTimeout
+// as its cause and a 503 error envelope is written instead.
+//
+// Streaming responses are exempt: requests that accept text/event-stream,
+// application/x-ndjson, or text/csv bypass the timeout, and a handler that
+// sets Content-Type to one of them before d elapses is switched to an unbuffered writer
+// and allowed to run to completion. A non-positive d disables the timeout.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isStreaming(r.Header.Get("Accept")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithCancelCause(r.Context())
+			defer cancel(nil)
+
+			tw := &timeoutWriter{
+				w:         w,
+				header:    make(http.Header),
+				streaming: make(chan struct{}),
+			}
+
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				tw.finish()
+			case <-tw.streaming:
+				waitHandler(done, panicked)
+			case <-timer.C:
+				if !tw.expire() {
+					waitHandler(done, panicked)
+					return
+				}
+				cancel(ErrRequestTimeout)
+				handlers.RespondJSON(w, http.StatusServiceUnavailable, handlers.ErrorResponse{
+					Error: handlers.ErrorBody{
+						Code:    handlers.ErrorCode(ErrRequestTimeout, http.StatusServiceUnavailable),
+						Message: ErrRequestTimeout.Error(),
+					},
+				})
+			}
+		})
+	}
+}
+
+func waitHandler(done <-chan struct{}, panicked <-chan any) {
+	select {
+	case <-done:
+	case p := <-panicked:
+		panic(p)
+	}
+}
+
+// streamingTypes are the media types of responses written incrementally.
+var streamingTypes = []string{"text/event-stream", handlers.NDJSONContentType, "text/csv"}
+
+func isStreaming(value string) bool {
+	value = strings.TrimSpace(value)
+	for _, t := range streamingTypes {
+		if strings.HasPrefix(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeoutWriter buffers a handler's response until the handler finishes or
+// the deadline expires. Writing a streaming Content-Type header commits the
+// response to the underlying writer and switches to pass-through mode.
+type timeoutWriter struct {
+	w         http.ResponseWriter
+	header    http.Header
+	streaming chan struct{}
+
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	status   int
+	streamed bool
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeaderLocked(status)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeaderLocked(http.StatusOK)
+	if tw.streamed {
+		return tw.w.Write(b)
+	}
+	return tw.buf.Write(b)
+}
+
+// Flush forwards to the underlying writer once the response is streaming.
+// Buffered responses are flushed when the handler finishes.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.streamed {
+		if f, ok := tw.w.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(status int) {
+	if tw.timedOut || tw.status != 0 {
+		return
+	}
+	tw.status = status
+
+	if isStreaming(tw.header.Get("Content-Type")) {
+		tw.commitLocked()
+		tw.streamed = true
+		close(tw.streaming)
+	}
+}
+
+func (tw *timeoutWriter) commitLocked() {
+	dst := tw.w.Header()
+	for k, v := range tw.header {
+		dst[k] = v
+	}
+	tw.w.WriteHeader(tw.status)
+}
+
+// finish writes the buffered response after the handler returns.
+func (tw *timeoutWriter) finish() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.streamed {
+		return
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	tw.commitLocked()
+	tw.w.Write(tw.buf.Bytes())
+}
+
+// expire marks the response as timed out, discarding any buffered output.
+// Reports false if the response has already started streaming.
+func (tw *timeoutWriter) expire() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.streamed {
+		return false
+	}
+	tw.timedOut = true
+	return true
+}
@@ -3,7 +3,7 @@ package openapi
 import "maps"
 
 // NewComponents creates a Components instance with common shared schemas and responses.
-// Includes PageRequest schema and standard error responses (BadRequest, NotFound, Conflict).
+// Includes PageRequest and Error schemas and standard error responses (BadRequest, NotFound, Conflict).
 func NewComponents() *Components {
 	return &Components{
 		Schemas: map[string]*Schema{
@@ -13,7 +13,21 @@ func NewComponents() *Components {
 					"page":      {Type: "integer", Description: "Page number (1-indexed)", Example: 1},
 					"page_size": {Type: "integer", Description: "Results per page", Example: 20},
 					"search":    {Type: "string", Description: "Search query"},
-					"sort":      {Type: "string", Description: "Comma-separated sort fields. Prefix with - for descending. Example: name,-created_at"},
+					"sort":      {Type: "string", Description: "Comma-separated sort fields. Prefix with - for descending, suffix text fields with :ci for case-insensitive. Example: name:ci,-created_at"},
+				},
+			},
+			"Error": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"error": {
+						Type: "object",
+						Properties: map[string]*Schema{
+							"code":    {Type: "string", Description: "Stable machine-readable error code", Example: "not_found"},
+							"message": {Type: "string", Description: "Human-readable error message"},
+							"details": {Type: "object", Description: "Optional error context such as field-level validation failures"},
+						},
+						Required: []string{"code", "message"},
+					},
 				},
 			},
 		},
@@ -22,12 +36,7 @@ func NewComponents() *Components {
 				Description: "Invalid request",
 				Content: map[string]*MediaType{
 					"application/json": {
-						Schema: &Schema{
-							Type: "object",
-							Properties: map[string]*Schema{
-								"error": {Type: "string", Description: "Error message"},
-							},
-						},
+						Schema: SchemaRef("Error"),
 					},
 				},
 			},
@@ -35,12 +44,7 @@ func NewComponents() *Components {
 				Description: "Resource not found",
 				Content: map[string]*MediaType{
 					"application/json": {
-						Schema: &Schema{
-							Type: "object",
-							Properties: map[string]*Schema{
-								"error": {Type: "string", Description: "Error message"},
-							},
-						},
+						Schema: SchemaRef("Error"),
 					},
 				},
 			},
@@ -48,12 +52,7 @@ func NewComponents() *Components {
 				Description: "Resource conflict (duplicate name)",
 				Content: map[string]*MediaType{
 					"application/json": {
-						Schema: &Schema{
-							Type: "object",
-							Properties: map[string]*Schema{
-								"error": {Type: "string", Description: "Error message"},
-							},
-						},
+						Schema: SchemaRef("Error"),
 					},
 				},
 			},
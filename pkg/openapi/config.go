@@ -1,15 +1,21 @@
 package openapi
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"strings"
+)
 
 type Config struct {
-	Title       string `toml:"title"`
-	Description string `toml:"description"`
+	Title       string   `toml:"title"`
+	Description string   `toml:"description"`
+	Servers     []Server `toml:"servers"`
 }
 
 type ConfigEnv struct {
 	Title       string
 	Description string
+	Servers     string
 }
 
 func (c *Config) Finalize(env *ConfigEnv) error {
@@ -17,9 +23,11 @@ func (c *Config) Finalize(env *ConfigEnv) error {
 	if env != nil {
 		c.loadEnv(env)
 	}
-	return nil
+	return c.validate()
 }
 
+// Merge applies non-zero values from the overlay configuration.
+// Overlay servers replace the base servers rather than extending them.
 func (c *Config) Merge(overlay *Config) {
 	if overlay.Title != "" {
 		c.Title = overlay.Title
@@ -27,6 +35,9 @@ func (c *Config) Merge(overlay *Config) {
 	if overlay.Description != "" {
 		c.Description = overlay.Description
 	}
+	if len(overlay.Servers) > 0 {
+		c.Servers = overlay.Servers
+	}
 }
 
 func (c *Config) loadDefaults() {
@@ -38,6 +49,8 @@ func (c *Config) loadDefaults() {
 	}
 }
 
+// loadEnv reads Servers as a comma-separated list of server URLs, replacing
+// any configured servers. Descriptions can only be set through the config file.
 func (c *Config) loadEnv(env *ConfigEnv) {
 	if env.Title != "" {
 		if v := os.Getenv(env.Title); v != "" {
@@ -49,4 +62,24 @@ func (c *Config) loadEnv(env *ConfigEnv) {
 			c.Description = v
 		}
 	}
+	if env.Servers != "" {
+		if v := os.Getenv(env.Servers); v != "" {
+			var servers []Server
+			for url := range strings.SplitSeq(v, ",") {
+				if url = strings.TrimSpace(url); url != "" {
+					servers = append(servers, Server{URL: url})
+				}
+			}
+			c.Servers = servers
+		}
+	}
+}
+
+func (c *Config) validate() error {
+	for i, s := range c.Servers {
+		if strings.TrimSpace(s.URL) == "" {
+			return fmt.Errorf("servers[%d]: url is required", i)
+		}
+	}
+	return nil
 }
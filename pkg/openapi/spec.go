@@ -7,6 +7,7 @@ type Spec struct {
 	OpenAPI    string               `json:"openapi"`
 	Info       *Info                `json:"info"`
 	Servers    []*Server            `json:"servers,omitempty"`
+	Tags       []*Tag               `json:"tags,omitempty"`
 	Paths      map[string]*PathItem `json:"paths"`
 	Components *Components          `json:"components,omitempty"`
 }
@@ -27,6 +28,33 @@ func (s *Spec) AddServer(url string) {
 	s.Servers = append(s.Servers, &Server{URL: url})
 }
 
+// AddServers appends copies of servers to the spec in order.
+func (s *Spec) AddServers(servers ...Server) {
+	for _, server := range servers {
+		s.Servers = append(s.Servers, &server)
+	}
+}
+
+// AddTag records tag in the spec's tags array. Tags keep the order they are
+// first added; adding a name again fills in a description or external docs
+// the existing entry lacks.
+func (s *Spec) AddTag(tag Tag) {
+	for _, existing := range s.Tags {
+		if existing.Name != tag.Name {
+			continue
+		}
+		if existing.Description == "" {
+			existing.Description = tag.Description
+		}
+		if existing.ExternalDocs == nil {
+			existing.ExternalDocs = tag.ExternalDocs
+		}
+		return
+	}
+
+	s.Tags = append(s.Tags, &tag)
+}
+
 func (s *Spec) SetDescription(desc string) {
 	s.Info.Description = desc
 }
@@ -12,6 +12,20 @@ type Info struct {
 
 // Server represents a server URL for the API.
 type Server struct {
+	URL         string `json:"url" toml:"url"`
+	Description string `json:"description,omitempty" toml:"description"`
+}
+
+// Tag adds a description and optional external documentation to a tag name
+// used by operations.
+type Tag struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+}
+
+// ExternalDocs references external documentation for a tag.
+type ExternalDocs struct {
 	URL         string `json:"url"`
 	Description string `json:"description,omitempty"`
 }
@@ -21,6 +35,7 @@ type PathItem struct {
 	Get    *Operation `json:"get,omitempty"`
 	Post   *Operation `json:"post,omitempty"`
 	Put    *Operation `json:"put,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
 	Delete *Operation `json:"delete,omitempty"`
 }
 
@@ -53,13 +68,21 @@ type RequestBody struct {
 // Response describes a single response from an API operation.
 type Response struct {
 	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
 	Content     map[string]*MediaType `json:"content,omitempty"`
 	Ref         string                `json:"$ref,omitempty"`
 }
 
+// Header describes a single response header.
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
 // MediaType provides schema and examples for a media type.
 type MediaType struct {
-	Schema *Schema `json:"schema,omitempty"`
+	Schema  *Schema `json:"schema,omitempty"`
+	Example any     `json:"example,omitempty"`
 }
 
 // Schema defines the structure of input and output data.
@@ -111,6 +134,15 @@ func RequestBodyJSON(schemaName string, required bool) *RequestBody {
 	}
 }
 
+// WithExample sets example as the sample payload for every media type of the
+// request body and returns the request body for chaining.
+func (b *RequestBody) WithExample(example any) *RequestBody {
+	for _, mt := range b.Content {
+		mt.Example = example
+	}
+	return b
+}
+
 // ResponseJSON creates a response with JSON content type referencing a schema.
 func ResponseJSON(description, schemaName string) *Response {
 	return &Response{
@@ -121,6 +153,58 @@ func ResponseJSON(description, schemaName string) *Response {
 	}
 }
 
+// ResponseWithHeaders adds headers to resp and returns it for chaining.
+func ResponseWithHeaders(resp *Response, headers map[string]*Header) *Response {
+	if resp.Headers == nil {
+		resp.Headers = make(map[string]*Header, len(headers))
+	}
+	for name, h := range headers {
+		resp.Headers[name] = h
+	}
+	return resp
+}
+
+// ResponseWithCSV adds a text/csv media type to resp and returns it for
+// chaining. It documents list endpoints that can stream CSV in place of JSON.
+func ResponseWithCSV(resp *Response) *Response {
+	if resp.Content == nil {
+		resp.Content = make(map[string]*MediaType, 1)
+	}
+	resp.Content["text/csv"] = &MediaType{Schema: &Schema{Type: "string"}}
+	return resp
+}
+
+// ResponseWithNDJSON adds an application/x-ndjson media type whose lines
+// follow the referenced schema to resp and returns it for chaining. It
+// documents list endpoints that can stream one JSON object per line.
+func ResponseWithNDJSON(resp *Response, schemaName string) *Response {
+	if resp.Content == nil {
+		resp.Content = make(map[string]*MediaType, 1)
+	}
+	resp.Content["application/x-ndjson"] = &MediaType{Schema: SchemaRef(schemaName)}
+	return resp
+}
+
+// NewHeader creates a response header with the specified schema type.
+func NewHeader(typ, description string) *Header {
+	return &Header{
+		Description: description,
+		Schema:      &Schema{Type: typ},
+	}
+}
+
+// PageHeaders returns the headers set on paginated responses: X-Total-Count
+// and, when links is true, the RFC 8288 Link header used by GET list endpoints.
+func PageHeaders(links bool) map[string]*Header {
+	headers := map[string]*Header{
+		"X-Total-Count": NewHeader("integer", "Total number of results across all pages"),
+	}
+	if links {
+		headers["Link"] = NewHeader("string", "RFC 8288 links to the first, prev, next, and last pages")
+	}
+	return headers
+}
+
 // PathParam creates a required path parameter with UUID format.
 func PathParam(name, description string) *Parameter {
 	return &Parameter{
@@ -142,3 +226,9 @@ func QueryParam(name, typ, description string, required bool) *Parameter {
 		Schema:      &Schema{Type: typ},
 	}
 }
+
+// FieldsParam creates the optional fields query parameter that restricts a
+// JSON response, or each item of a page, to the listed top-level fields.
+func FieldsParam() *Parameter {
+	return QueryParam("fields", "string", "Comma-separated top-level fields to include (e.g. id,name). Unknown fields are ignored; omit for the full object", false)
+}
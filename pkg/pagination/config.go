@@ -2,21 +2,55 @@
 package pagination
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/query"
+)
+
+// Fallback limits applied by Finalize when values are unset,
+// and by PageRequest.Normalize when given an invalid Config.
+const (
+	DefaultPageSize    = 20
+	DefaultMaxPageSize = 100
+	DefaultMaxOffset   = 10000
 )
 
+// ErrPageOutOfRange indicates a requested page whose offset exceeds the
+// configured MaxOffset.
+var ErrPageOutOfRange = errors.New("page out of range")
+
+func init() {
+	handlers.RegisterErrorCode("page_out_of_range", ErrPageOutOfRange)
+	handlers.RegisterErrorCode("invalid_sort", query.ErrInvalidSort)
+}
+
 // Config holds pagination settings including page size limits.
+// Use NewConfig or Finalize to obtain a validated Config.
 type Config struct {
 	DefaultPageSize int `toml:"default_page_size"`
 	MaxPageSize     int `toml:"max_page_size"`
+	MaxOffset       int `toml:"max_offset"`
 }
 
 // ConfigEnv maps environment variable names for pagination configuration.
 type ConfigEnv struct {
 	DefaultPageSize string
 	MaxPageSize     string
+	MaxOffset       string
+}
+
+// NewConfig creates a Config with the given page sizes.
+// Returns an error unless 0 < defaultSize <= maxSize.
+func NewConfig(defaultSize, maxSize int) (Config, error) {
+	cfg := Config{DefaultPageSize: defaultSize, MaxPageSize: maxSize}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
 }
 
 // Finalize applies defaults and environment variable overrides, then validates.
@@ -25,7 +59,27 @@ func (c *Config) Finalize(env *ConfigEnv) error {
 	if env != nil {
 		c.loadEnv(env)
 	}
-	return c.validate()
+	return c.Validate()
+}
+
+// Validate reports whether the page sizes satisfy 0 < DefaultPageSize <= MaxPageSize
+// and MaxOffset is not negative. A zero MaxOffset falls back to DefaultMaxOffset.
+// All violations are reported together as a joined error.
+func (c Config) Validate() error {
+	var errs []error
+	if c.DefaultPageSize < 1 {
+		errs = append(errs, fmt.Errorf("default_page_size must be positive, got %d", c.DefaultPageSize))
+	}
+	if c.MaxPageSize < 1 {
+		errs = append(errs, fmt.Errorf("max_page_size must be positive, got %d", c.MaxPageSize))
+	}
+	if c.DefaultPageSize > c.MaxPageSize && c.MaxPageSize > 0 {
+		errs = append(errs, fmt.Errorf("default_page_size (%d) cannot exceed max_page_size (%d)", c.DefaultPageSize, c.MaxPageSize))
+	}
+	if c.MaxOffset < 0 {
+		errs = append(errs, fmt.Errorf("max_offset cannot be negative, got %d", c.MaxOffset))
+	}
+	return errors.Join(errs...)
 }
 
 // Merge applies non-zero values from the overlay configuration.
@@ -36,14 +90,20 @@ func (c *Config) Merge(overlay *Config) {
 	if overlay.MaxPageSize != 0 {
 		c.MaxPageSize = overlay.MaxPageSize
 	}
+	if overlay.MaxOffset != 0 {
+		c.MaxOffset = overlay.MaxOffset
+	}
 }
 
 func (c *Config) loadDefaults() {
 	if c.DefaultPageSize <= 0 {
-		c.DefaultPageSize = 20
+		c.DefaultPageSize = DefaultPageSize
 	}
 	if c.MaxPageSize <= 0 {
-		c.MaxPageSize = 100
+		c.MaxPageSize = DefaultMaxPageSize
+	}
+	if c.MaxOffset <= 0 {
+		c.MaxOffset = DefaultMaxOffset
 	}
 }
 
@@ -62,17 +122,11 @@ func (c *Config) loadEnv(env *ConfigEnv) {
 			}
 		}
 	}
-}
-
-func (c *Config) validate() error {
-	if c.DefaultPageSize < 1 {
-		return fmt.Errorf("default_page_size must be positive")
-	}
-	if c.MaxPageSize < 1 {
-		return fmt.Errorf("max_page_size must be positive")
-	}
-	if c.DefaultPageSize > c.MaxPageSize {
-		return fmt.Errorf("default_page_size cannot exceed max_page_size")
+	if env.MaxOffset != "" {
+		if v := os.Getenv(env.MaxOffset); v != "" {
+			if n, err := strconv.Atoi(v); err == nil {
+				c.MaxOffset = n
+			}
+		}
 	}
-	return nil
 }
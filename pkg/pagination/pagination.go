@@ -2,9 +2,13 @@ package pagination
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/agent-lab/pkg/query"
 )
 
@@ -36,16 +40,48 @@ type PageRequest struct {
 }
 
 // Normalize adjusts the request to ensure valid pagination values based on the config.
-func (r *PageRequest) Normalize(cfg Config) {
+// Non-positive config limits fall back to DefaultPageSize, DefaultMaxPageSize,
+// and DefaultMaxOffset, so a zero-value Config never caps the page size to zero.
+//
+// A page whose offset would exceed MaxOffset is clamped to the last reachable
+// page and ErrPageOutOfRange is returned. Callers that ignore the error get the
+// clamped page; handlers can reject the request with a 400 instead.
+func (r *PageRequest) Normalize(cfg Config) error {
 	if r.Page < 1 {
 		r.Page = 1
 	}
+
+	maxSize := cfg.MaxPageSize
+	if maxSize < 1 {
+		maxSize = DefaultMaxPageSize
+	}
+	defaultSize := cfg.DefaultPageSize
+	if defaultSize < 1 {
+		defaultSize = min(DefaultPageSize, maxSize)
+	}
+
 	if r.PageSize < 1 {
-		r.PageSize = cfg.DefaultPageSize
+		r.PageSize = defaultSize
 	}
-	if r.PageSize > cfg.MaxPageSize {
-		r.PageSize = cfg.MaxPageSize
+	if r.PageSize > maxSize {
+		r.PageSize = maxSize
 	}
+
+	maxOffset := cfg.MaxOffset
+	if maxOffset < 1 {
+		maxOffset = DefaultMaxOffset
+	}
+
+	if maxPage := maxOffset/r.PageSize + 1; r.Page > maxPage {
+		requested := r.Page
+		r.Page = maxPage
+		return fmt.Errorf(
+			"%w: page %d exceeds maximum page %d for page_size %d (max offset %d)",
+			ErrPageOutOfRange, requested, maxPage, r.PageSize, maxOffset,
+		)
+	}
+
+	return nil
 }
 
 // Offset calculates the number of records to skip based on page and page size.
@@ -54,9 +90,12 @@ func (r *PageRequest) Offset() int {
 }
 
 // PageRequestFromQuery parses pagination parameters from URL query values.
-// Supported parameters: page, page_size, search, sort (comma-separated, "-" prefix for desc).
-// The result is normalized according to the provided config.
-func PageRequestFromQuery(values url.Values, cfg Config) PageRequest {
+// Supported parameters: page, page_size, search, sort (comma-separated, "-" prefix for desc,
+// ":ci" suffix for case-insensitive).
+// The result is normalized according to the provided config. If the requested
+// page exceeds the configured MaxOffset, the clamped request is returned along
+// with ErrPageOutOfRange.
+func PageRequestFromQuery(values url.Values, cfg Config) (PageRequest, error) {
 	page, _ := strconv.Atoi(values.Get("page"))
 	pageSize, _ := strconv.Atoi(values.Get("page_size"))
 
@@ -74,8 +113,8 @@ func PageRequestFromQuery(values url.Values, cfg Config) PageRequest {
 		Sort:     sort,
 	}
 
-	req.Normalize(cfg)
-	return req
+	err := req.Normalize(cfg)
+	return req, err
 }
 
 // PageResult holds a page of data along with pagination metadata.
@@ -109,3 +148,85 @@ func NewPageResult[T any](data []T, total, page, pageSize int) PageResult[T] {
 		TotalPages: totalPages,
 	}
 }
+
+// HeaderTotalCount is the response header carrying PageResult.Total.
+const HeaderTotalCount = "X-Total-Count"
+
+// SetHeaders writes the pagination headers for the page to h.
+// X-Total-Count is always set. When u is non-nil, an RFC 8288 Link header is
+// set with first, prev, next, and last relations built by replacing the page
+// and page_size query parameters of u.
+func (p PageResult[T]) SetHeaders(h http.Header, u *url.URL) {
+	h.Set(HeaderTotalCount, strconv.Itoa(p.Total))
+
+	if u == nil {
+		return
+	}
+
+	link := func(page int, rel string) string {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("page_size", strconv.Itoa(p.PageSize))
+		target := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		return fmt.Sprintf("<%s>; rel=%q", target.String(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if p.Page > 1 {
+		links = append(links, link(min(p.Page-1, p.TotalPages), "prev"))
+	}
+	if p.Page < p.TotalPages {
+		links = append(links, link(p.Page+1, "next"))
+	}
+	links = append(links, link(p.TotalPages, "last"))
+
+	h.Set("Link", strings.Join(links, ", "))
+}
+
+// Respond writes result as a 200 JSON response with pagination headers.
+// The Link header is only set for GET requests, since other methods carry
+// page parameters in the request body. A fields query parameter restricts
+// each item in data to the requested fields T serializes; see
+// handlers.RequestedFields.
+func Respond[T any](w http.ResponseWriter, r *http.Request, result *PageResult[T]) {
+	u := r.URL
+	if r.Method != http.MethodGet {
+		u = nil
+	}
+	result.SetHeaders(w.Header(), u)
+
+	fields := handlers.RequestedFields[T](r)
+	if fields == nil {
+		handlers.RespondJSON(w, http.StatusOK, result)
+		return
+	}
+
+	selected, err := result.SelectFields(fields)
+	if err != nil {
+		// The items cannot be encoded; respond as RespondJSON would.
+		handlers.RespondJSON(w, http.StatusOK, result)
+		return
+	}
+	handlers.RespondJSON(w, http.StatusOK, selected)
+}
+
+// SelectFields returns a copy of the page whose items keep only the listed
+// top-level fields. Pagination metadata is unchanged.
+func (p PageResult[T]) SelectFields(fields []string) (PageResult[map[string]json.RawMessage], error) {
+	data := make([]map[string]json.RawMessage, len(p.Data))
+	for i, item := range p.Data {
+		selected, err := handlers.SelectFields(item, fields)
+		if err != nil {
+			return PageResult[map[string]json.RawMessage]{}, err
+		}
+		data[i] = selected
+	}
+
+	return PageResult[map[string]json.RawMessage]{
+		Data:       data,
+		Total:      p.Total,
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+		TotalPages: p.TotalPages,
+	}, nil
+}
@@ -1,22 +1,75 @@
 package query
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 )
 
+// ErrInvalidJSONPath indicates a JSON path containing segments that cannot
+// be safely embedded in SQL.
+var ErrInvalidJSONPath = errors.New("invalid JSON path")
+
+// ErrUnknownField indicates a view field name that is not in the projection,
+// or not allowlisted for the requested use.
+var ErrUnknownField = errors.New("unknown field")
+
+// ErrInvalidSort indicates a requested sort field that is not in the
+// projection or not allowlisted as sortable.
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// SortError reports requested sort fields that the projection does not
+// define or allow sorting by, along with the column names that can be
+// sorted on.
+type SortError struct {
+	Fields   []string
+	Sortable []string
+}
+
+func (e *SortError) Error() string {
+	return fmt.Sprintf("%s %s; sortable fields: %s",
+		ErrInvalidSort, strings.Join(e.Fields, ", "), strings.Join(e.Sortable, ", "))
+}
+
+func (e *SortError) Unwrap() error {
+	return ErrInvalidSort
+}
+
+// Details returns the rejected and sortable fields for error responses.
+func (e *SortError) Details() map[string]any {
+	return map[string]any{
+		"sort":     e.Fields,
+		"sortable": e.Sortable,
+	}
+}
+
+// jsonPathSegment allowlists identifier-like keys and array indexes.
+var jsonPathSegment = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*|[0-9]+)$`)
+
+// condition is a WHERE clause fragment. Clauses use "$%d" markers that are
+// numbered in order at build time, unless numbered is set, in which case the
+// clause already uses $1..$n and is shifted to follow the preceding arguments.
 type condition struct {
-	clause string
-	args   []any
+	clause   string
+	args     []any
+	numbered bool
 }
 
+// caseInsensitiveSuffix marks a sort token as case-insensitive (e.g. "name:ci").
+const caseInsensitiveSuffix = ":ci"
+
 // SortField represents a single column in an ORDER BY clause.
 // Field is the logical field name (mapped via ProjectionMap).
 // Descending controls sort direction (false = ASC, true = DESC).
+// CaseInsensitive orders by LOWER(column) instead of the database collation.
+// It applies only to columns projected with ProjectText and is ignored for
+// other columns, whose ordering does not depend on case.
 type SortField struct {
-	Field      string
-	Descending bool
+	Field           string
+	Descending      bool
+	CaseInsensitive bool
 }
 
 // Builder constructs SQL queries using a fluent API with automatic parameter numbering.
@@ -40,6 +93,7 @@ func NewBuilder(projection *ProjectionMap, defaultSort ...SortField) *Builder {
 // ParseSortFields parses a comma-separated sort string into SortField slice.
 // Fields prefixed with "-" are descending. Example: "name,-createdAt" parses to
 // [{Field: "name", Descending: false}, {Field: "createdAt", Descending: true}].
+// A ":ci" suffix requests case-insensitive ordering, e.g. "-name:ci".
 // Returns nil for empty input.
 func ParseSortFields(s string) []SortField {
 	if s == "" {
@@ -55,15 +109,19 @@ func ParseSortFields(s string) []SortField {
 			continue
 		}
 
-		if after, ok := strings.CutPrefix(part, "-"); ok {
+		field, ci := strings.CutSuffix(part, caseInsensitiveSuffix)
+
+		if after, ok := strings.CutPrefix(field, "-"); ok {
 			fields = append(fields, SortField{
-				Field:      after,
-				Descending: true,
+				Field:           after,
+				Descending:      true,
+				CaseInsensitive: ci,
 			})
 		} else {
 			fields = append(fields, SortField{
-				Field:      part,
-				Descending: false,
+				Field:           field,
+				Descending:      false,
+				CaseInsensitive: ci,
 			})
 		}
 	}
@@ -94,6 +152,29 @@ func (b *Builder) BuildCount() (string, []any) {
 	return sql, args
 }
 
+// BuildDistinct returns a SELECT DISTINCT query for the distinct values of
+// field under the current conditions, ordered by that column. The field must
+// be a projected view name the projection allows filtering on; anything else
+// returns ErrUnknownField so that caller-supplied names never reach the SQL.
+func (b *Builder) BuildDistinct(field string) (string, []any, error) {
+	if !b.projection.IsFilterable(field) {
+		return "", nil, fmt.Errorf("%w: %q", ErrUnknownField, field)
+	}
+
+	col := b.projection.Column(field)
+	where, args, _ := b.buildWhere(1)
+
+	sql := fmt.Sprintf(
+		"SELECT DISTINCT %s FROM %s%s ORDER BY %s",
+		col,
+		b.projection.Table(),
+		where,
+		col,
+	)
+
+	return sql, args, nil
+}
+
 // BuildPage returns a paginated SELECT query with ordering, limit, and offset.
 func (b *Builder) BuildPage(page, pageSize int) (string, []any) {
 	where, args, _ := b.buildWhere(1)
@@ -113,6 +194,60 @@ func (b *Builder) BuildPage(page, pageSize int) (string, []any) {
 	return sql, args
 }
 
+// KeysetOrder returns the order BuildKeyset sorts by: the current sort fields
+// followed by tieField ascending, so rows with equal sort values still have a
+// total order. tieField must be unique per row and is not appended when the
+// sort already includes it.
+func (b *Builder) KeysetOrder(tieField string) []SortField {
+	fields := b.sortFields()
+	for _, f := range fields {
+		if f.Field == tieField {
+			return fields
+		}
+	}
+	order := make([]SortField, len(fields), len(fields)+1)
+	copy(order, fields)
+	return append(order, SortField{Field: tieField})
+}
+
+// BuildKeyset returns a SELECT query for the next limit rows in KeysetOrder
+// after the row whose values for those fields are after, or for the first
+// limit rows when after is nil. Unlike BuildPage, the cost of each batch does
+// not grow with how far into the result it starts, so callers can read a
+// large result in batches without holding a connection between them.
+//
+// Nil values in after match NULL columns, placed as PostgreSQL orders them
+// by default: last when ascending, first when descending. BuildKeyset panics
+// if after does not have one value per KeysetOrder field.
+func (b *Builder) BuildKeyset(tieField string, after []any, limit int) (string, []any) {
+	order := b.KeysetOrder(tieField)
+	where, args, next := b.buildWhere(1)
+
+	if after != nil {
+		if len(after) != len(order) {
+			panic(fmt.Sprintf("query: keyset has %d values for %d sort fields", len(after), len(order)))
+		}
+		clause, keyArgs := b.keysetClause(order, tieField, after, next)
+		if where == "" {
+			where = " WHERE " + clause
+		} else {
+			where += " AND " + clause
+		}
+		args = append(args, keyArgs...)
+	}
+
+	sql := fmt.Sprintf(
+		"SELECT %s FROM %s%s%s LIMIT %d",
+		b.projection.Columns(),
+		b.projection.Table(),
+		where,
+		b.orderByClause(order),
+		limit,
+	)
+
+	return sql, args
+}
+
 // BuildSingle returns a SELECT query for a single record by ID.
 func (b *Builder) BuildSingle(idField string, id any) (string, []any) {
 	col := b.projection.Column(idField)
@@ -139,11 +274,44 @@ func (b *Builder) BuildSingleOrNull() (string, []any) {
 
 // OrderByFields sets the sort order for paginated queries.
 // Overrides default sort fields set in NewBuilder. Nil or empty clears explicit sorting.
+// Fields are not validated; use SortBy for caller-supplied sort fields.
 func (b *Builder) OrderByFields(fields []SortField) *Builder {
 	b.orderByFields = fields
 	return b
 }
 
+// SortBy validates fields against the projection and sets them as the sort
+// order. Each field may name a view property ("CreatedAt") or its column
+// ("created_at"). If any field is not projected or not allowlisted with
+// ProjectionMap.Sortable, the order is left unchanged and a *SortError
+// listing the sortable columns is returned. Empty fields keep the current
+// order.
+func (b *Builder) SortBy(fields []SortField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	resolved := make([]SortField, len(fields))
+	var unknown []string
+
+	for i, f := range fields {
+		view, ok := b.projection.Resolve(f.Field)
+		if !ok || !b.projection.IsSortable(view) {
+			unknown = append(unknown, f.Field)
+			continue
+		}
+		f.Field = view
+		resolved[i] = f
+	}
+
+	if len(unknown) > 0 {
+		return &SortError{Fields: unknown, Sortable: b.projection.SortableNames()}
+	}
+
+	b.orderByFields = resolved
+	return nil
+}
+
 // WhereContains adds a case-insensitive ILIKE condition. Nil or empty values are ignored.
 func (b *Builder) WhereContains(field string, value *string) *Builder {
 	if value == nil || *value == "" {
@@ -170,6 +338,157 @@ func (b *Builder) WhereEquals(field string, value any) *Builder {
 	return b
 }
 
+// WhereExists adds an EXISTS (subquery) condition. subSQL numbers its own
+// placeholders from $1, binding subArgs in order; they are renumbered to follow
+// the outer query's arguments when the query is built, and may be repeated or
+// referenced out of order. Placeholders inside quoted literals are left alone.
+//
+// subSQL is embedded in the query text, so it must come from trusted code.
+// WhereExists panics if a placeholder has no corresponding argument.
+func (b *Builder) WhereExists(subSQL string, subArgs ...any) *Builder {
+	return b.whereExists("EXISTS", subSQL, subArgs)
+}
+
+// WhereNotExists adds a NOT EXISTS (subquery) condition. See WhereExists for
+// placeholder numbering.
+func (b *Builder) WhereNotExists(subSQL string, subArgs ...any) *Builder {
+	return b.whereExists("NOT EXISTS", subSQL, subArgs)
+}
+
+// WhereExistsIn adds an EXISTS condition correlating sub's subField with the
+// outer field, e.g. documents with at least one image:
+//
+//	b.WhereExistsIn("ID", query.NewBuilder(images), "DocumentID")
+//
+// produces EXISTS (SELECT 1 FROM public.images i WHERE i.document_id = d.id),
+// followed by any conditions already added to sub. Sub ordering is ignored.
+func (b *Builder) WhereExistsIn(field string, sub *Builder, subField string) *Builder {
+	subSQL, subArgs := sub.buildCorrelated(subField, b.projection.Column(field))
+	return b.WhereExists(subSQL, subArgs...)
+}
+
+// WhereNotExistsIn adds a NOT EXISTS condition correlating sub's subField with
+// the outer field. See WhereExistsIn.
+func (b *Builder) WhereNotExistsIn(field string, sub *Builder, subField string) *Builder {
+	subSQL, subArgs := sub.buildCorrelated(subField, b.projection.Column(field))
+	return b.WhereNotExists(subSQL, subArgs...)
+}
+
+func (b *Builder) whereExists(op, subSQL string, subArgs []any) *Builder {
+	if n := maxPlaceholder(subSQL); n > len(subArgs) {
+		panic(fmt.Sprintf("query: subquery references $%d but has %d args", n, len(subArgs)))
+	}
+	b.conditions = append(b.conditions, condition{
+		clause:   fmt.Sprintf("%s (%s)", op, subSQL),
+		args:     subArgs,
+		numbered: true,
+	})
+	return b
+}
+
+// WhereRaw adds a caller-written condition for predicates the builder does
+// not model, e.g. array containment:
+//
+//	b.WhereRaw("d.tags @> ?", tags)
+//	b.WhereRaw("date_trunc('day', d.created_at) = ?", day)
+//
+// Each ? placeholder binds the next arg and is renumbered to follow the
+// query's other arguments; write ?? for a literal question mark, such as the
+// JSONB ? operator. Question marks inside quoted literals are left alone.
+// The fragment is wrapped in parentheses so it combines safely with other
+// conditions.
+//
+// fragment is embedded in the query text, so it must come from trusted code.
+// Never interpolate values into it; pass them as args. WhereRaw panics if the
+// number of placeholders does not match the number of args.
+func (b *Builder) WhereRaw(fragment string, args ...any) *Builder {
+	clause, n := numberPlaceholders(fragment)
+	if n != len(args) {
+		panic(fmt.Sprintf("query: raw fragment has %d placeholders but %d args", n, len(args)))
+	}
+	b.conditions = append(b.conditions, condition{
+		clause:   "(" + clause + ")",
+		args:     args,
+		numbered: true,
+	})
+	return b
+}
+
+// WhereGreaterThan adds a > condition. Nil values are ignored.
+func (b *Builder) WhereGreaterThan(field string, value any) *Builder {
+	return b.whereCompare(field, ">", value)
+}
+
+// WhereGreaterOrEqual adds a >= condition. Nil values are ignored.
+func (b *Builder) WhereGreaterOrEqual(field string, value any) *Builder {
+	return b.whereCompare(field, ">=", value)
+}
+
+// WhereLessThan adds a < condition. Nil values are ignored.
+func (b *Builder) WhereLessThan(field string, value any) *Builder {
+	return b.whereCompare(field, "<", value)
+}
+
+// WhereLessOrEqual adds a <= condition. Nil values are ignored.
+func (b *Builder) WhereLessOrEqual(field string, value any) *Builder {
+	return b.whereCompare(field, "<=", value)
+}
+
+func (b *Builder) whereCompare(field, op string, value any) *Builder {
+	if isNil(value) {
+		return b
+	}
+	col := b.projection.Column(field)
+	b.conditions = append(b.conditions, condition{
+		clause: fmt.Sprintf("%s %s $%%d", col, op),
+		args:   []any{value},
+	})
+	return b
+}
+
+// WhereJSONEquals adds a condition comparing the text value at a nested path
+// within a JSON/JSONB column, emitting col #>> '{a,b}' = $n. The path uses dot
+// notation (e.g. "provider.name") and each segment must be an identifier or
+// array index; only the value is parameterized. Non-string values are compared
+// by their text form. Nil values are ignored.
+//
+// The path is embedded in the SQL text, so it must come from trusted code.
+// WhereJSONEquals panics on an invalid path; validate untrusted input with
+// ParseJSONPath first.
+func (b *Builder) WhereJSONEquals(field, path string, value any) *Builder {
+	if isNil(value) {
+		return b
+	}
+
+	segments, err := ParseJSONPath(path)
+	if err != nil {
+		panic(err)
+	}
+
+	col := b.projection.Column(field)
+	b.conditions = append(b.conditions, condition{
+		clause: fmt.Sprintf("%s #>> '{%s}' = $%%d", col, strings.Join(segments, ",")),
+		args:   []any{jsonText(value)},
+	})
+	return b
+}
+
+// ParseJSONPath splits a dot-separated JSON path into segments, rejecting
+// any segment that is not an identifier or array index.
+func ParseJSONPath(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidJSONPath)
+	}
+
+	segments := strings.Split(path, ".")
+	for _, seg := range segments {
+		if !jsonPathSegment.MatchString(seg) {
+			return nil, fmt.Errorf("%w: segment %q in %q", ErrInvalidJSONPath, seg, path)
+		}
+	}
+	return segments, nil
+}
+
 // WhereIn adds an IN condition for multiple values. Empty slices are ignored.
 func (b *Builder) WhereIn(field string, values []any) *Builder {
 	if len(values) == 0 {
@@ -187,6 +506,36 @@ func (b *Builder) WhereIn(field string, values []any) *Builder {
 	return b
 }
 
+// WhereNotEquals adds an inequality condition. Nil values are ignored.
+func (b *Builder) WhereNotEquals(field string, value any) *Builder {
+	if isNil(value) {
+		return b
+	}
+	col := b.projection.Column(field)
+	b.conditions = append(b.conditions, condition{
+		clause: fmt.Sprintf("%s <> $%%d", col),
+		args:   []any{value},
+	})
+	return b
+}
+
+// WhereNotIn adds a NOT IN condition for multiple values. Empty slices are ignored.
+func (b *Builder) WhereNotIn(field string, values []any) *Builder {
+	if len(values) == 0 {
+		return b
+	}
+	col := b.projection.Column(field)
+	placeholders := make([]string, len(values))
+	for i := range values {
+		placeholders[i] = "$%d"
+	}
+	b.conditions = append(b.conditions, condition{
+		clause: fmt.Sprintf("%s NOT IN (%s)", col, strings.Join(placeholders, ", ")),
+		args:   values,
+	})
+	return b
+}
+
 // WhereNullable adds an equality or IS NULL condition depending on whether value is nil.
 func (b *Builder) WhereNullable(column string, val any) *Builder {
 	col := b.projection.Column(column)
@@ -229,28 +578,107 @@ func (b *Builder) WhereSearch(search *string, fields ...string) *Builder {
 }
 
 func (b *Builder) buildOrderBy() string {
-	fields := b.orderByFields
-	if len(fields) == 0 {
-		fields = b.defaultSortFields
+	return b.orderByClause(b.sortFields())
+}
+
+func (b *Builder) sortFields() []SortField {
+	if len(b.orderByFields) > 0 {
+		return b.orderByFields
 	}
+	return b.defaultSortFields
+}
 
+func (b *Builder) orderByClause(fields []SortField) string {
 	if len(fields) == 0 {
 		return ""
 	}
 
 	parts := make([]string, len(fields))
 	for i, f := range fields {
-		col := b.projection.Column(f.Field)
 		dir := "ASC"
 		if f.Descending {
 			dir = "DESC"
 		}
-		parts[i] = fmt.Sprintf("%s %s", col, dir)
+		parts[i] = fmt.Sprintf("%s %s", b.sortExpr(f, b.projection.Column(f.Field)), dir)
 	}
 
 	return " ORDER BY " + strings.Join(parts, ", ")
 }
 
+// sortExpr returns expr as f orders it, lowered for case-insensitive text.
+func (b *Builder) sortExpr(f SortField, expr string) string {
+	if f.CaseInsensitive && b.projection.IsText(f.Field) {
+		return fmt.Sprintf("LOWER(%s)", expr)
+	}
+	return expr
+}
+
+// keysetClause returns a condition matching rows that follow the row with
+// values after in order, numbering placeholders from startParam. A row
+// follows when it equals the cursor on the first i fields and follows it on
+// field i, for some i. Only tieField is assumed to be non-null.
+func (b *Builder) keysetClause(order []SortField, tieField string, after []any, startParam int) (string, []any) {
+	var args []any
+	params := make([]string, len(order))
+	for i, v := range after {
+		if isNil(v) {
+			continue
+		}
+		params[i] = fmt.Sprintf("$%d", startParam+len(args))
+		args = append(args, v)
+	}
+
+	var terms []string
+	for i, f := range order {
+		col := b.sortExpr(f, b.projection.Column(f.Field))
+		param := b.sortExpr(f, params[i])
+
+		var follows string
+		switch {
+		case params[i] == "" && f.Descending:
+			follows = col + " IS NOT NULL"
+		case params[i] == "":
+		case f.Descending:
+			follows = fmt.Sprintf("%s < %s", col, param)
+		case f.Field == tieField:
+			follows = fmt.Sprintf("%s > %s", col, param)
+		default:
+			follows = fmt.Sprintf("(%s > %s OR %s IS NULL)", col, param, col)
+		}
+
+		if follows != "" {
+			parts := make([]string, 0, i+1)
+			for j := range i {
+				parts = append(parts, b.keysetEquals(order[j], params[j]))
+			}
+			parts = append(parts, follows)
+			term := strings.Join(parts, " AND ")
+			if len(parts) > 1 {
+				term = "(" + term + ")"
+			}
+			terms = append(terms, term)
+		}
+	}
+
+	switch len(terms) {
+	case 0:
+		return "FALSE", args
+	case 1:
+		return terms[0], args
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// keysetEquals returns a condition matching rows whose f equals param, or
+// whose f is NULL when param is empty.
+func (b *Builder) keysetEquals(f SortField, param string) string {
+	col := b.sortExpr(f, b.projection.Column(f.Field))
+	if param == "" {
+		return col + " IS NULL"
+	}
+	return fmt.Sprintf("%s = %s", col, b.sortExpr(f, param))
+}
+
 func (b *Builder) buildWhere(startParam int) (string, []any, int) {
 	if len(b.conditions) == 0 {
 		return "", nil, startParam
@@ -262,6 +690,13 @@ func (b *Builder) buildWhere(startParam int) (string, []any, int) {
 
 	for _, cond := range b.conditions {
 		clause := cond.clause
+		if cond.numbered {
+			clause = shiftPlaceholders(clause, paramIdx-1)
+			args = append(args, cond.args...)
+			paramIdx += len(cond.args)
+			clauses = append(clauses, clause)
+			continue
+		}
 		for _, arg := range cond.args {
 			clause = strings.Replace(clause, "$%d", fmt.Sprintf("$%d", paramIdx), 1)
 			args = append(args, arg)
@@ -273,6 +708,122 @@ func (b *Builder) buildWhere(startParam int) (string, []any, int) {
 	return " WHERE " + strings.Join(clauses, " AND "), args, paramIdx
 }
 
+// buildCorrelated returns a SELECT 1 subquery over the builder's table whose
+// subField equals outerColumn, followed by the builder's conditions.
+func (b *Builder) buildCorrelated(subField, outerColumn string) (string, []any) {
+	where, args, _ := b.buildWhere(1)
+	clause := fmt.Sprintf("%s = %s", b.projection.Column(subField), outerColumn)
+	if rest, ok := strings.CutPrefix(where, " WHERE "); ok {
+		clause += " AND " + rest
+	}
+	return fmt.Sprintf("SELECT 1 FROM %s WHERE %s", b.projection.Table(), clause), args
+}
+
+// shiftPlaceholders adds offset to every $n placeholder in sql outside of
+// quoted literals and identifiers.
+func shiftPlaceholders(sql string, offset int) string {
+	if offset == 0 {
+		return sql
+	}
+	var out strings.Builder
+	scanPlaceholders(sql, func(literal string, n int) {
+		if n == 0 {
+			out.WriteString(literal)
+			return
+		}
+		fmt.Fprintf(&out, "$%d", n+offset)
+	})
+	return out.String()
+}
+
+// maxPlaceholder returns the highest $n placeholder in sql, or 0 if none.
+func maxPlaceholder(sql string) int {
+	highest := 0
+	scanPlaceholders(sql, func(_ string, n int) {
+		if n > highest {
+			highest = n
+		}
+	})
+	return highest
+}
+
+// scanPlaceholders splits sql into text and $n placeholders, calling emit
+// with n = 0 for text and with the placeholder number otherwise. Text inside
+// single or double quotes is never treated as a placeholder.
+func scanPlaceholders(sql string, emit func(literal string, n int)) {
+	start := 0
+	var quote byte
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '$' && i+1 < len(sql) && isDigit(sql[i+1]):
+			j := i + 1
+			n := 0
+			for j < len(sql) && isDigit(sql[j]) {
+				n = n*10 + int(sql[j]-'0')
+				j++
+			}
+			emit(sql[start:i], 0)
+			emit(sql[i:j], n)
+			start = j
+			i = j - 1
+		}
+	}
+	emit(sql[start:], 0)
+}
+
+// numberPlaceholders rewrites each ? placeholder in sql outside of quoted
+// literals and identifiers as $1, $2, ... in order, and ?? as a literal ?.
+// It returns the rewritten sql and the number of placeholders.
+func numberPlaceholders(sql string) (string, int) {
+	var out strings.Builder
+	var quote byte
+	n := 0
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?' && i+1 < len(sql) && sql[i+1] == '?':
+			i++
+		case c == '?':
+			n++
+			fmt.Fprintf(&out, "$%d", n)
+			continue
+		}
+		out.WriteByte(c)
+	}
+	return out.String(), n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// jsonText converts a filter value to the text form returned by the #>> operator.
+func jsonText(value any) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if s, ok := v.Interface().(string); ok {
+		return s
+	}
+	return fmt.Sprint(v.Interface())
+}
+
 // isNil checks if a value is nil, handling both untyped nil and nil pointers
 // passed as interface values. This is necessary because a nil *string passed
 // as any is not equal to untyped nil (the interface has type *string but nil value).
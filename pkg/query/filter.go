@@ -0,0 +1,73 @@
+package query
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// FilterField binds one query parameter to a field of the filter struct F.
+// Construct fields with UUIDFilter, IntFilter, StringFilter, EnumFilter, or
+// Filter for custom value types.
+type FilterField[F any] struct {
+	Param string
+	apply func(f *F, value string)
+}
+
+// FilterSpec declares how URL query parameters populate a filter struct.
+// Domains declare a spec once and parse each request with it, so every
+// filter shares the same rule: empty or invalid values leave the field unset.
+type FilterSpec[F any] []FilterField[F]
+
+// NewFilterSpec creates a FilterSpec from the given fields.
+func NewFilterSpec[F any](fields ...FilterField[F]) FilterSpec[F] {
+	return FilterSpec[F](fields)
+}
+
+// Parse returns a filter populated from values. Parameters that are absent,
+// empty, or fail to parse are ignored.
+func (s FilterSpec[F]) Parse(values url.Values) F {
+	var f F
+	for _, field := range s {
+		if v := values.Get(field.Param); v != "" {
+			field.apply(&f, v)
+		}
+	}
+	return f
+}
+
+// Filter binds param to a value of type T produced by parse.
+// The setter is only called when parse succeeds.
+func Filter[F, T any](param string, parse func(string) (T, error), set func(f *F, value T)) FilterField[F] {
+	return FilterField[F]{
+		Param: param,
+		apply: func(f *F, value string) {
+			if parsed, err := parse(value); err == nil {
+				set(f, parsed)
+			}
+		},
+	}
+}
+
+// UUIDFilter binds param to a UUID value.
+func UUIDFilter[F any](param string, set func(f *F, value uuid.UUID)) FilterField[F] {
+	return Filter(param, uuid.Parse, set)
+}
+
+// IntFilter binds param to a base-10 integer value.
+func IntFilter[F any](param string, set func(f *F, value int)) FilterField[F] {
+	return Filter(param, strconv.Atoi, set)
+}
+
+// StringFilter binds param to its raw string value.
+func StringFilter[F any](param string, set func(f *F, value string)) FilterField[F] {
+	return Filter(param, func(v string) (string, error) { return v, nil }, set)
+}
+
+// EnumFilter binds param to an enumerated value. parse rejects values
+// outside the enumeration by returning an error, typically the domain's
+// existing ParseXxx function.
+func EnumFilter[F, T any](param string, parse func(string) (T, error), set func(f *F, value T)) FilterField[F] {
+	return Filter(param, parse, set)
+}
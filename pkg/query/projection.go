@@ -13,7 +13,13 @@ type ProjectionMap struct {
 	table      string
 	alias      string
 	columns    map[string]string
+	names      map[string]string
+	views      map[string]string
+	text       map[string]bool
+	sortable   map[string]bool
+	filterable map[string]bool
 	columnList []string
+	nameList   []string
 }
 
 // NewProjectionMap creates a ProjectionMap for the given schema, table, and alias.
@@ -23,7 +29,11 @@ func NewProjectionMap(schema, table, alias string) *ProjectionMap {
 		table:      table,
 		alias:      alias,
 		columns:    make(map[string]string),
+		names:      make(map[string]string),
+		views:      make(map[string]string),
+		text:       make(map[string]bool),
 		columnList: make([]string, 0),
+		nameList:   make([]string, 0),
 	}
 }
 
@@ -31,10 +41,87 @@ func NewProjectionMap(schema, table, alias string) *ProjectionMap {
 func (p *ProjectionMap) Project(column, viewName string) *ProjectionMap {
 	qualified := fmt.Sprintf("%s.%s", p.alias, column)
 	p.columns[viewName] = qualified
+	p.names[viewName] = column
+	p.views[column] = viewName
 	p.columnList = append(p.columnList, qualified)
+	p.nameList = append(p.nameList, column)
 	return p
 }
 
+// ProjectText adds a column mapping like Project and marks the column as text,
+// making it eligible for case-insensitive ordering.
+func (p *ProjectionMap) ProjectText(column, viewName string) *ProjectionMap {
+	p.text[viewName] = true
+	return p.Project(column, viewName)
+}
+
+// IsText reports whether the view property name was projected with ProjectText.
+func (p *ProjectionMap) IsText(viewName string) bool {
+	return p.text[viewName]
+}
+
+// Sortable restricts the fields callers may sort by to the given view
+// property names, which must already be projected. Projections that declare
+// no sortable fields allow sorting by every projected column. Leave internal
+// columns such as storage keys out of the list so clients cannot order by
+// them and infer their values.
+func (p *ProjectionMap) Sortable(viewNames ...string) *ProjectionMap {
+	p.sortable = p.allowlist(viewNames)
+	return p
+}
+
+// Filterable restricts the fields callers may name for filtering, such as a
+// facet field, to the given view property names, which must already be
+// projected. Projections that declare no filterable fields allow every
+// projected column.
+func (p *ProjectionMap) Filterable(viewNames ...string) *ProjectionMap {
+	p.filterable = p.allowlist(viewNames)
+	return p
+}
+
+// IsSortable reports whether callers may sort by the view property name.
+func (p *ProjectionMap) IsSortable(viewName string) bool {
+	return p.allows(p.sortable, viewName)
+}
+
+// IsFilterable reports whether callers may filter on the view property name.
+func (p *ProjectionMap) IsFilterable(viewName string) bool {
+	return p.allows(p.filterable, viewName)
+}
+
+// SortableNames returns the unqualified columns callers may sort by, in
+// projection order.
+func (p *ProjectionMap) SortableNames() []string {
+	if p.sortable == nil {
+		return p.nameList
+	}
+	names := make([]string, 0, len(p.sortable))
+	for _, name := range p.nameList {
+		if p.sortable[p.views[name]] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (p *ProjectionMap) allowlist(viewNames []string) map[string]bool {
+	allowed := make(map[string]bool, len(viewNames))
+	for _, name := range viewNames {
+		if _, ok := p.names[name]; !ok {
+			panic(fmt.Sprintf("query: %s.%s does not project %q", p.schema, p.table, name))
+		}
+		allowed[name] = true
+	}
+	return allowed
+}
+
+func (p *ProjectionMap) allows(allowed map[string]bool, viewName string) bool {
+	if _, ok := p.names[viewName]; !ok {
+		return false
+	}
+	return allowed == nil || allowed[viewName]
+}
+
 // Alias returns the table alias.
 func (p *ProjectionMap) Alias() string {
 	return p.alias
@@ -53,6 +140,30 @@ func (p *ProjectionMap) Column(viewName string) string {
 	return viewName
 }
 
+// ColumnName returns the unqualified column for a view property name.
+// Reports false if the view name is not projected, allowing callers to treat
+// the projection as an allowlist of writable columns.
+func (p *ProjectionMap) ColumnName(viewName string) (string, bool) {
+	col, ok := p.names[viewName]
+	return col, ok
+}
+
+// Resolve returns the view property name for name, which may be either a
+// view property name ("CreatedAt") or an unqualified column ("created_at").
+// Reports false if name matches neither.
+func (p *ProjectionMap) Resolve(name string) (string, bool) {
+	if _, ok := p.names[name]; ok {
+		return name, true
+	}
+	view, ok := p.views[name]
+	return view, ok
+}
+
+// ColumnNames returns the unqualified names of all mapped columns in projection order.
+func (p *ProjectionMap) ColumnNames() []string {
+	return p.nameList
+}
+
 // Columns returns all mapped columns as a comma-separated string.
 func (p *ProjectionMap) Columns() string {
 	return strings.Join(p.columnList, ", ")
@@ -0,0 +1,59 @@
+// Package redact strips credential fields from JSON configuration so it can
+// be persisted, exported, or copied without leaking secrets.
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// secretKeys are config keys whose values are credentials.
+// Matching is case-insensitive.
+var secretKeys = map[string]bool{
+	"token":         true,
+	"api_key":       true,
+	"apikey":        true,
+	"access_token":  true,
+	"client_secret": true,
+	"secret":        true,
+	"password":      true,
+}
+
+// IsSecret reports whether key names a credential field.
+func IsSecret(key string) bool {
+	return secretKeys[strings.ToLower(key)]
+}
+
+// Config removes credential fields at any depth from a JSON config.
+// Removing rather than masking keeps the result loadable without
+// persisting placeholder credentials.
+func Config(config json.RawMessage) (json.RawMessage, error) {
+	if len(config) == 0 {
+		return config, nil
+	}
+
+	var v any
+	if err := json.Unmarshal(config, &v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(redact(v))
+}
+
+func redact(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if IsSecret(k) {
+				delete(t, k)
+				continue
+			}
+			t[k] = redact(val)
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = redact(val)
+		}
+	}
+	return v
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-const pgDuplicateKeyCode = "23505"
+const (
+	pgDuplicateKeyCode = "23505"
+	pgForeignKeyCode   = "23503"
+)
 
 // MapError translates database errors to domain errors.
 // It maps sql.ErrNoRows to notFoundErr and PostgreSQL unique violation (23505)
@@ -30,3 +33,10 @@ func MapError(err error, notFoundErr, duplicateErr error) error {
 
 	return err
 }
+
+// IsForeignKeyViolation reports whether err is a PostgreSQL foreign key
+// violation (23503), such as deleting a row that other rows still reference.
+func IsForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyCode
+}
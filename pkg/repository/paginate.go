@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/query"
+)
+
+// Paginate executes a count query and a page query from the provided builder
+// and wraps the results in a PageResult.
+// The page request is normalized against cfg, and any requested sort fields
+// override the builder's default ordering. Sort fields the builder's
+// projection does not define return a *query.SortError.
+func Paginate[T any](ctx context.Context, q Querier, qb *query.Builder, page pagination.PageRequest, cfg pagination.Config, scan ScanFunc[T]) (*pagination.PageResult[T], error) {
+	page.Normalize(cfg)
+
+	if err := qb.SortBy(page.Sort); err != nil {
+		return nil, err
+	}
+
+	countSQL, countArgs := qb.BuildCount()
+	var total int
+	if err := q.QueryRowContext(ctx, countSQL, countArgs...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("count: %w", err)
+	}
+
+	pageSQL, pageArgs := qb.BuildPage(page.Page, page.PageSize)
+	items, err := QueryMany(ctx, q, pageSQL, pageArgs, scan)
+	if err != nil {
+		return nil, fmt.Errorf("query page: %w", err)
+	}
+
+	result := pagination.NewPageResult(items, total, page.Page, page.PageSize)
+	return &result, nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/JaimeStill/agent-lab/pkg/query"
+)
+
+// BuildPartialUpdate builds an UPDATE statement that sets only the non-nil
+// fields of patch on the row whose idField equals id.
+//
+// patch must be a struct (or pointer to struct) whose exported fields are
+// pointers, slices, or maps. Field names are resolved through proj as view
+// names, so only projected columns can be written; a field without a
+// projection returns an error. When proj projects UpdatedAt and the patch
+// does not set it, updated_at is set to NOW().
+//
+// The statement returns the updated row using proj's column list. Returns an
+// empty query when no fields are set.
+func BuildPartialUpdate(proj *query.ProjectionMap, idField string, id any, patch any) (string, []any, error) {
+	idCol, ok := proj.ColumnName(idField)
+	if !ok {
+		return "", nil, fmt.Errorf("patch: id field %q is not projected", idField)
+	}
+
+	v := reflect.ValueOf(patch)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", nil, fmt.Errorf("patch: expected struct, got %T", patch)
+	}
+
+	var (
+		sets    []string
+		args    = []any{id}
+		touched bool
+	)
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		switch fv.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+		default:
+			return "", nil, fmt.Errorf("patch: field %s must be a pointer, slice, or map", field.Name)
+		}
+
+		col, ok := proj.ColumnName(field.Name)
+		if !ok {
+			return "", nil, fmt.Errorf("patch: field %s is not projected", field.Name)
+		}
+
+		if fv.IsNil() {
+			continue
+		}
+
+		if field.Name == "UpdatedAt" {
+			touched = true
+		}
+
+		value := fv.Interface()
+		if fv.Kind() == reflect.Pointer {
+			value = fv.Elem().Interface()
+		}
+
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", col, len(args)))
+	}
+
+	if len(sets) == 0 {
+		return "", nil, nil
+	}
+
+	if col, ok := proj.ColumnName("UpdatedAt"); ok && !touched {
+		sets = append(sets, col+" = NOW()")
+	}
+
+	q := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s.%s = $1 RETURNING %s",
+		proj.Table(), strings.Join(sets, ", "), proj.Alias(), idCol, proj.Columns(),
+	)
+
+	return q, args, nil
+}
+
+// UpdatePartial applies patch to the row whose idField equals id and returns
+// the resulting row. See BuildPartialUpdate for the patch struct rules.
+// When patch sets no fields the update is skipped and the current row is
+// returned unchanged. Returns sql.ErrNoRows if the row does not exist.
+func UpdatePartial[T any](ctx context.Context, q Querier, proj *query.ProjectionMap, idField string, id any, patch any, scan ScanFunc[T]) (T, error) {
+	updateSQL, args, err := BuildPartialUpdate(proj, idField, id, patch)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	if updateSQL == "" {
+		idCol, _ := proj.ColumnName(idField)
+		selectSQL := fmt.Sprintf(
+			"SELECT %s FROM %s WHERE %s.%s = $1",
+			proj.Columns(), proj.Table(), proj.Alias(), idCol,
+		)
+		return QueryOne(ctx, q, selectSQL, []any{id}, scan)
+	}
+
+	return QueryOne(ctx, q, updateSQL, args, scan)
+}
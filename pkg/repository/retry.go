@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	pgSerializationFailureCode = "40001"
+	pgDeadlockDetectedCode     = "40P01"
+
+	retryBaseDelay = 10 * time.Millisecond
+	retryMaxDelay  = 500 * time.Millisecond
+)
+
+// IsRetryable reports whether err is a PostgreSQL serialization failure (40001)
+// or deadlock (40P01), which indicate the transaction can safely be re-run.
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgSerializationFailureCode || pgErr.Code == pgDeadlockDetectedCode
+}
+
+// WithRetry executes fn within a transaction via WithTx, re-running the whole
+// transaction when it fails with a retryable error (see IsRetryable).
+// Attempts are separated by exponential backoff and stop early if ctx is done.
+// Non-retryable errors are returned immediately. maxAttempts below 1 is treated as 1.
+func WithRetry(ctx context.Context, db *sql.DB, maxAttempts int, fn func(*sql.Tx) error) error {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	delay := retryBaseDelay
+	var err error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		_, err = WithTx(ctx, db, func(tx *sql.Tx) (struct{}, error) {
+			return struct{}{}, fn(tx)
+		})
+
+		if err == nil || !IsRetryable(err) || attempt == maxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, retryMaxDelay)
+	}
+
+	return err
+}
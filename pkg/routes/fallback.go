@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+)
+
+// Options configures the fallback responses of the handler returned by New.
+// Nil handlers use the defaults, which write the standard JSON error envelope.
+type Options struct {
+	NotFound         http.HandlerFunc
+	MethodNotAllowed http.HandlerFunc
+}
+
+// New wraps mux so requests that match no registered route receive JSON
+// errors instead of the plain-text responses of http.ServeMux. Unknown paths
+// get 404. Known paths requested with an unregistered method get 405, with
+// the Allow header set to the registered methods before MethodNotAllowed runs.
+//
+// Mount the result only for API paths; web and static handlers should keep
+// their own not-found behavior.
+func New(mux *http.ServeMux, opts Options) http.Handler {
+	if opts.NotFound == nil {
+		opts.NotFound = notFound
+	}
+	if opts.MethodNotAllowed == nil {
+		opts.MethodNotAllowed = methodNotAllowed
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &headerRecorder{header: http.Header{}}
+		h.ServeHTTP(rec, r)
+
+		if rec.status == http.StatusMethodNotAllowed {
+			w.Header().Set("Allow", rec.header.Get("Allow"))
+			opts.MethodNotAllowed(w, r)
+			return
+		}
+
+		opts.NotFound(w, r)
+	})
+}
+
+func notFound(w http.ResponseWriter, r *http.Request) {
+	respondRouteError(w, http.StatusNotFound, fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	respondRouteError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed for %s", r.Method, r.URL.Path))
+}
+
+func respondRouteError(w http.ResponseWriter, status int, message string) {
+	handlers.RespondJSON(w, status, handlers.ErrorResponse{
+		Error: handlers.ErrorBody{
+			Code:    handlers.StatusCode(status),
+			Message: message,
+		},
+	})
+}
+
+// headerRecorder captures the status and headers written by the ServeMux
+// fallback handler, discarding its plain-text body.
+type headerRecorder struct {
+	header http.Header
+	status int
+}
+
+func (r *headerRecorder) Header() http.Header { return r.header }
+
+func (r *headerRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *headerRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return len(b), nil
+}
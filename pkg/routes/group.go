@@ -9,13 +9,16 @@ import (
 
 // Group represents a collection of routes under a common URL prefix.
 // Groups can contain child groups for hierarchical route organization.
+// Description and ExternalDocs document each of the group's Tags in the
+// OpenAPI tags array.
 type Group struct {
-	Prefix      string
-	Tags        []string
-	Description string
-	Routes      []Route
-	Children    []Group
-	Schemas     map[string]*openapi.Schema
+	Prefix       string
+	Tags         []string
+	Description  string
+	ExternalDocs *openapi.ExternalDocs
+	Routes       []Route
+	Children     []Group
+	Schemas      map[string]*openapi.Schema
 }
 
 // AddToSpec adds the group's routes and schemas to the OpenAPI specification.
@@ -28,6 +31,14 @@ func (g *Group) addOperations(parentPrefix string, spec *openapi.Spec) {
 
 	maps.Copy(spec.Components.Schemas, g.Schemas)
 
+	for _, tag := range g.Tags {
+		spec.AddTag(openapi.Tag{
+			Name:         tag,
+			Description:  g.Description,
+			ExternalDocs: g.ExternalDocs,
+		})
+	}
+
 	for _, route := range g.Routes {
 		if route.OpenAPI == nil {
 			continue
@@ -51,6 +62,8 @@ func (g *Group) addOperations(parentPrefix string, spec *openapi.Spec) {
 			spec.Paths[path].Post = op
 		case "PUT":
 			spec.Paths[path].Put = op
+		case "PATCH":
+			spec.Paths[path].Patch = op
 		case "DELETE":
 			spec.Paths[path].Delete = op
 		}
@@ -0,0 +1,120 @@
+// Package signedurl signs and verifies expiring URLs with HMAC-SHA256, so a
+// link can grant access to one resource for a limited time.
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+)
+
+// Query parameters carried by a signed URL.
+const (
+	ExpiresParam   = "exp"
+	SignatureParam = "sig"
+)
+
+// KeySize is the length in bytes of keys produced by GenerateKey and the
+// minimum accepted by New.
+const KeySize = 32
+
+// Signed URL errors.
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrExpired          = errors.New("signed url expired")
+)
+
+func init() {
+	handlers.RegisterErrorCode("invalid_signature", ErrInvalidSignature)
+	handlers.RegisterErrorCode("signature_expired", ErrExpired)
+}
+
+// Signer produces and verifies signed URLs for paths served under a base
+// path. Signatures cover the path relative to the base path and the expiry,
+// so a signed URL cannot be redirected to another resource or extended.
+type Signer struct {
+	key      []byte
+	basePath string
+}
+
+// New creates a Signer using key, which must be at least KeySize bytes.
+// URLs produced by Sign are prefixed with basePath.
+func New(key []byte, basePath string) (*Signer, error) {
+	if len(key) < KeySize {
+		return nil, fmt.Errorf("signing key must be at least %d bytes, got %d", KeySize, len(key))
+	}
+	return &Signer{key: key, basePath: basePath}, nil
+}
+
+// GenerateKey returns a random KeySize-byte signing key.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate signing key: %w", err)
+	}
+	return key, nil
+}
+
+// Sign returns the signed URL for path, valid for ttl, and its expiry.
+// path is relative to the base path, e.g. /images/{id}/data.
+func (s *Signer) Sign(path string, ttl time.Duration) (string, time.Time) {
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+	exp := strconv.FormatInt(expires.Unix(), 10)
+
+	q := url.Values{}
+	q.Set(ExpiresParam, exp)
+	q.Set(SignatureParam, s.signature(path, exp))
+
+	return s.basePath + path + "?" + q.Encode(), expires
+}
+
+// Verify checks the signature and expiry in query for path, relative to the
+// base path. Returns ErrInvalidSignature when either parameter is missing,
+// malformed, or does not match, and ErrExpired when the URL has expired.
+func (s *Signer) Verify(path string, query url.Values) error {
+	exp := query.Get(ExpiresParam)
+	sig := query.Get(SignatureParam)
+	if exp == "" || sig == "" {
+		return fmt.Errorf("%w: missing %s or %s", ErrInvalidSignature, ExpiresParam, SignatureParam)
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.mac(path, exp)) {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed %s", ErrInvalidSignature, ExpiresParam)
+	}
+	if !time.Now().Before(time.Unix(unix, 0)) {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+// HasSignature reports whether query carries a signature parameter.
+func HasSignature(query url.Values) bool {
+	return query.Has(SignatureParam)
+}
+
+func (s *Signer) signature(path, exp string) string {
+	return base64.RawURLEncoding.EncodeToString(s.mac(path, exp))
+}
+
+func (s *Signer) mac(path, exp string) []byte {
+	m := hmac.New(sha256.New, s.key)
+	m.Write([]byte(path))
+	m.Write([]byte{'\n'})
+	m.Write([]byte(exp))
+	return m.Sum(nil)
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// gzipSuffix marks a blob stored gzip-compressed on disk. The suffix is
+// internal: keys never include it and reads decompress transparently.
+const gzipSuffix = ".gz"
+
+// sniffLen is the number of leading bytes inspected to detect content type.
+const sniffLen = 512
+
+// incompressibleTypes lists sniffed content types that are already
+// compressed, or that callers read in place through Path, and so are
+// always stored as-is.
+var incompressibleTypes = []string{
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"application/zip",
+	"application/x-gzip",
+	"application/x-rar-compressed",
+	"audio/",
+	"video/",
+	"font/woff",
+}
+
+// compressible reports whether content beginning with head benefits from
+// compression. Empty content is stored as-is.
+func compressible(head []byte) bool {
+	if len(head) == 0 {
+		return false
+	}
+
+	contentType := http.DetectContentType(head)
+	for _, t := range incompressibleTypes {
+		if strings.HasPrefix(contentType, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// variantPath returns the on-disk path of the blob for the plain path of a key.
+func variantPath(path string, compressed bool) string {
+	if compressed {
+		return path + gzipSuffix
+	}
+	return path
+}
+
+// locate returns the on-disk path of the blob at the plain path of a key and
+// whether it is stored compressed. Uncompressed blobs take precedence.
+// Filesystem errors are returned unmapped.
+func locate(path string) (string, bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return path, false, nil
+	} else if !os.IsNotExist(err) {
+		return "", false, err
+	}
+
+	compressed := variantPath(path, true)
+	if _, err := os.Stat(compressed); err != nil {
+		return "", false, err
+	}
+	return compressed, true, nil
+}
+
+// openBlob opens the blob at the plain path of a key for reading,
+// decompressing it if it is stored compressed. The returned size is the
+// on-disk size, suitable only as a buffer hint for uncompressed blobs.
+func openBlob(path string) (io.ReadCloser, int64, error) {
+	actual, compressed, err := locate(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	file, err := os.Open(actual)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var size int64
+	if info, err := file.Stat(); err == nil {
+		size = info.Size()
+	}
+
+	if !compressed {
+		return file, size, nil
+	}
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("open compressed file: %w", err)
+	}
+	return &gzipReadCloser{Reader: gz, file: file}, 0, nil
+}
+
+// gzipReadCloser closes both the gzip stream and its underlying file.
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipReadCloser) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
@@ -1,31 +1,65 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/docker/go-units"
 )
 
+// Storage backends selectable through Config.Backend.
+const (
+	BackendFilesystem = "filesystem"
+	BackendMemory     = "memory"
+)
+
 // Config contains blob storage configuration.
 type Config struct {
+	// Backend selects the storage implementation: "filesystem" or "memory".
+	// Memory storage holds blobs in process memory and loses them on exit.
+	// Default: "filesystem"
+	Backend string `toml:"backend"`
+
 	// BasePath is the root directory for filesystem storage.
 	// Default: ".data/blobs"
 	BasePath         string `toml:"base_path"`
 	MaxUploadSize    string `toml:"max_upload_size"`
 	maxUploadSizeVal int64
+
+	// MaxTotalSize caps the total bytes held in storage (e.g. "50GB").
+	// Writes that would exceed it fail with ErrQuotaExceeded.
+	// Default: "0" (unlimited)
+	MaxTotalSize    string `toml:"max_total_size"`
+	maxTotalSizeVal int64
+
+	// CompressStoredBlobs gzip-compresses blobs on disk, skipping content
+	// that is already compressed (e.g. JPEG). Reads return original bytes.
+	// Default: false
+	CompressStoredBlobs bool `toml:"compress_stored_blobs"`
 }
 
 type Env struct {
-	BasePath      string
-	MaxUploadSize string
+	Backend             string
+	BasePath            string
+	MaxUploadSize       string
+	MaxTotalSize        string
+	CompressStoredBlobs string
 }
 
 func (c *Config) MaxUploadSizeBytes() int64 {
 	return c.maxUploadSizeVal
 }
 
+// MaxTotalBytes returns the storage quota in bytes, or 0 when unlimited.
+func (c *Config) MaxTotalBytes() int64 {
+	return c.maxTotalSizeVal
+}
+
 // Finalize applies defaults, loads environment overrides, and validates the storage configuration.
+// All validation problems are reported together as a joined error.
 func (c *Config) Finalize(env *Env) error {
 	c.loadDefaults()
 	if env != nil {
@@ -36,6 +70,9 @@ func (c *Config) Finalize(env *Env) error {
 
 // Merge applies values from overlay configuration that differ from zero values.
 func (c *Config) Merge(overlay *Config) {
+	if overlay.Backend != "" {
+		c.Backend = overlay.Backend
+	}
 	if overlay.BasePath != "" {
 		c.BasePath = overlay.BasePath
 	}
@@ -44,18 +81,38 @@ func (c *Config) Merge(overlay *Config) {
 		c.MaxUploadSize = overlay.MaxUploadSize
 		c.maxUploadSizeVal = size
 	}
+
+	if size, err := units.FromHumanSize(overlay.MaxTotalSize); err == nil {
+		c.MaxTotalSize = overlay.MaxTotalSize
+		c.maxTotalSizeVal = size
+	}
+
+	if overlay.CompressStoredBlobs {
+		c.CompressStoredBlobs = true
+	}
 }
 
 func (c *Config) loadDefaults() {
+	if c.Backend == "" {
+		c.Backend = BackendFilesystem
+	}
 	if c.BasePath == "" {
 		c.BasePath = ".data/blobs"
 	}
 	if c.MaxUploadSize == "" {
 		c.MaxUploadSize = "100MB"
 	}
+	if c.MaxTotalSize == "" {
+		c.MaxTotalSize = "0"
+	}
 }
 
 func (c *Config) loadEnv(env *Env) {
+	if env.Backend != "" {
+		if v := os.Getenv(env.Backend); v != "" {
+			c.Backend = v
+		}
+	}
 	if env.BasePath != "" {
 		if v := os.Getenv(env.BasePath); v != "" {
 			c.BasePath = v
@@ -66,21 +123,47 @@ func (c *Config) loadEnv(env *Env) {
 			c.MaxUploadSize = v
 		}
 	}
+	if env.MaxTotalSize != "" {
+		if v := os.Getenv(env.MaxTotalSize); v != "" {
+			c.MaxTotalSize = v
+		}
+	}
+	if env.CompressStoredBlobs != "" {
+		if v := os.Getenv(env.CompressStoredBlobs); v != "" {
+			if compress, err := strconv.ParseBool(v); err == nil {
+				c.CompressStoredBlobs = compress
+			}
+		}
+	}
 }
 
 func (c *Config) validate() error {
-	if c.BasePath == "" {
-		return fmt.Errorf("base_path required")
+	var errs []error
+	switch c.Backend {
+	case BackendFilesystem:
+		if strings.TrimSpace(c.BasePath) == "" {
+			errs = append(errs, fmt.Errorf("base_path required"))
+		}
+	case BackendMemory:
+	default:
+		errs = append(errs, fmt.Errorf("invalid backend %q: must be %q or %q", c.Backend, BackendFilesystem, BackendMemory))
 	}
 
-	size, err := units.FromHumanSize(c.MaxUploadSize)
-	if err != nil {
-		return fmt.Errorf("invalid max_upload_size: %w", err)
+	if size, err := units.FromHumanSize(c.MaxUploadSize); err != nil {
+		errs = append(errs, fmt.Errorf("invalid max_upload_size: %w", err))
+	} else if size <= 0 {
+		errs = append(errs, fmt.Errorf("max_upload_size must be positive"))
+	} else {
+		c.maxUploadSizeVal = size
 	}
-	if size <= 0 {
-		return fmt.Errorf("max_upload_size must be positive")
+
+	if total, err := units.FromHumanSize(c.MaxTotalSize); err != nil {
+		errs = append(errs, fmt.Errorf("invalid max_total_size: %w", err))
+	} else if total < 0 {
+		errs = append(errs, fmt.Errorf("max_total_size must not be negative"))
+	} else {
+		c.maxTotalSizeVal = total
 	}
-	c.maxUploadSizeVal = size
 
-	return nil
+	return errors.Join(errs...)
 }
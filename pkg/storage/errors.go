@@ -1,6 +1,7 @@
 // Package storage provides blob storage abstractions for the agent-lab service.
 // It defines a System interface for storage operations and includes a filesystem
-// implementation suitable for development and single-node deployments.
+// implementation suitable for development and single-node deployments, and an
+// in-memory implementation for tests and ephemeral previews.
 package storage
 
 import "errors"
@@ -16,4 +17,16 @@ var (
 	// ErrInvalidKey indicates the key is malformed or contains invalid characters.
 	// This includes empty keys and path traversal attempts.
 	ErrInvalidKey = errors.New("storage: invalid key")
+
+	// ErrQuotaExceeded indicates the write would grow total stored bytes
+	// beyond the configured quota.
+	ErrQuotaExceeded = errors.New("storage: quota exceeded")
+
+	// ErrCompressed indicates the key is stored compressed and has no
+	// on-disk file holding its original bytes.
+	ErrCompressed = errors.New("storage: key stored compressed")
+
+	// ErrNotOnDisk indicates the backend does not keep blobs in files, so
+	// the key has no on-disk path.
+	ErrNotOnDisk = errors.New("storage: key not stored on disk")
 )
@@ -1,30 +1,74 @@
 package storage
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"syscall"
 
 	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
 )
 
+// chunkSize is the number of bytes copied between context cancellation checks.
+const chunkSize = 256 * 1024
+
+// tmpSuffix marks in-progress writes, which are excluded from usage.
+const tmpSuffix = ".tmp"
+
 // filesystem implements System using the local filesystem.
 // It stores blobs as files under a configurable base path,
 // with keys mapping directly to relative file paths.
+//
+// Total stored bytes are tracked in used, guarded by mu, so writes can be
+// checked against quota. A quota of 0 disables enforcement.
+//
+// Temp files of in-progress writes are tracked in temps, guarded by tempMu,
+// so SweepTemp never removes a file a live write is using.
+//
+// When compress is set, compressible blobs are written gzip-compressed under
+// the key's path plus gzipSuffix. Reads locate either variant, so blobs
+// stored before compression was toggled remain readable.
 type filesystem struct {
 	basePath string
+	quota    int64
+	compress bool
 	logger   *slog.Logger
+
+	mu   sync.Mutex
+	used int64
+
+	tempMu sync.Mutex
+	temps  map[string]int
+}
+
+// New creates the storage system selected by cfg.Backend. An empty backend
+// selects the filesystem.
+func New(cfg *Config, logger *slog.Logger) (System, error) {
+	switch cfg.Backend {
+	case "", BackendFilesystem:
+		return newFilesystem(cfg, logger)
+	case BackendMemory:
+		return newMemory(cfg, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q", cfg.Backend)
+	}
 }
 
-// New creates a new filesystem storage system.
+// newFilesystem creates a new filesystem storage system.
 // The base path is resolved to an absolute path during construction.
 // Directory creation is deferred to Start() for lifecycle integration.
-func New(cfg *Config, logger *slog.Logger) (System, error) {
+func newFilesystem(cfg *Config, logger *slog.Logger) (System, error) {
 	if cfg.BasePath == "" {
 		return nil, fmt.Errorf("base_path required")
 	}
@@ -36,22 +80,33 @@ func New(cfg *Config, logger *slog.Logger) (System, error) {
 
 	return &filesystem{
 		basePath: absPath,
+		quota:    cfg.MaxTotalBytes(),
+		compress: cfg.CompressStoredBlobs,
 		logger:   logger.With("system", "storage"),
+		temps:    make(map[string]int),
 	}, nil
 }
 
 func (f *filesystem) Path(ctx context.Context, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	path, err := f.fullPath(key)
 	if err != nil {
 		return "", err
 	}
 
-	if _, err := os.Stat(path); err != nil {
+	_, compressed, err := locate(path)
+	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return "", ErrNotFound
 		}
 		return "", fmt.Errorf("stat file: %w", err)
 	}
+	if compressed {
+		return "", ErrCompressed
+	}
 
 	return path, nil
 }
@@ -59,18 +114,38 @@ func (f *filesystem) Path(ctx context.Context, key string) (string, error) {
 func (f *filesystem) Start(lc *lifecycle.Coordinator) error {
 	f.logger.Info("starting storage system", "base_path", f.basePath)
 
-	lc.OnStartup(func() {
+	lc.Register("storage", func() error {
 		if err := os.MkdirAll(f.basePath, 0755); err != nil {
 			f.logger.Error("storage initialization failed", "error", err)
-			return
+			return fmt.Errorf("create storage directory: %w", err)
 		}
-		f.logger.Info("storage directory initialized")
+
+		used, err := diskUsage(f.basePath)
+		if err != nil {
+			f.logger.Error("storage usage scan failed", "error", err)
+			return fmt.Errorf("compute storage usage: %w", err)
+		}
+
+		f.mu.Lock()
+		f.used = used
+		f.mu.Unlock()
+
+		f.logger.Info("storage directory initialized", "used_bytes", used, "quota_bytes", f.quota)
+		return nil
 	})
 
 	return nil
 }
 
 func (f *filesystem) Store(ctx context.Context, key string, data []byte) error {
+	return f.StoreStream(ctx, key, bytes.NewReader(data))
+}
+
+func (f *filesystem) StoreStream(ctx context.Context, key string, r io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	path, err := f.fullPath(key)
 	if err != nil {
 		return err
@@ -81,26 +156,111 @@ func (f *filesystem) Store(ctx context.Context, key string, data []byte) error {
 		return fmt.Errorf("create directory: %w", err)
 	}
 
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("write temp file: %w", err)
+	compressed := false
+	if f.compress {
+		br := bufio.NewReaderSize(r, sniffLen)
+		head, _ := br.Peek(sniffLen)
+		compressed = compressible(head)
+		r = br
 	}
 
-	if err := os.Rename(tmpPath, path); err != nil {
+	limit := int64(-1)
+	if f.quota > 0 {
+		limit = f.headroom(path)
+	}
+
+	tmpPath := variantPath(path, compressed) + tmpSuffix
+	defer f.trackTemp(tmpPath)()
+
+	size, err := writeFile(ctx, tmpPath, r, limit, compressed)
+	if err != nil {
 		os.Remove(tmpPath)
-		return fmt.Errorf("rename temp file: %w", err)
+		return err
+	}
+
+	return f.commit(tmpPath, path, compressed, size)
+}
+
+func (f *filesystem) Copy(ctx context.Context, srcKey, dstKey string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	src, dst, compressed, err := f.transferPaths(srcKey, dstKey)
+	if err != nil {
+		return err
+	}
+	if src == variantPath(dst, compressed) {
+		return nil
 	}
 
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	tmpPath := variantPath(dst, compressed) + tmpSuffix
+	defer f.trackTemp(tmpPath)()
+	os.Remove(tmpPath)
+
+	if err := os.Link(src, tmpPath); err == nil {
+		return f.commit(tmpPath, dst, compressed, fileSize(tmpPath))
+	}
+
+	srcPath, _ := f.fullPath(srcKey)
+	blob, _, err := openBlob(srcPath)
+	if err != nil {
+		return openError(err)
+	}
+	defer blob.Close()
+
+	return f.StoreStream(ctx, dstKey, blob)
+}
+
+func (f *filesystem) Move(ctx context.Context, srcKey, dstKey string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	src, dst, compressed, err := f.transferPaths(srcKey, dstKey)
+	if err != nil {
+		return err
+	}
+	if src == variantPath(dst, compressed) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	if err := f.rename(src, dst, compressed); err != nil {
+		if !errors.Is(err, syscall.EXDEV) {
+			return openError(err)
+		}
+
+		if err := f.Copy(ctx, srcKey, dstKey); err != nil {
+			return err
+		}
+		if err := f.remove(src); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("remove source file: %w", err)
+		}
+	}
+
+	f.cleanupDir(filepath.Dir(src))
 	return nil
 }
 
 func (f *filesystem) Retrieve(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	path, err := f.fullPath(key)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := os.ReadFile(path)
+	blob, size, err := openBlob(path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, ErrNotFound
@@ -108,13 +268,25 @@ func (f *filesystem) Retrieve(ctx context.Context, key string) ([]byte, error) {
 		if errors.Is(err, fs.ErrPermission) {
 			return nil, ErrPermissionDenied
 		}
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer blob.Close()
+
+	var buf bytes.Buffer
+	buf.Grow(int(size))
+
+	if err := copyChunks(ctx, &buf, blob); err != nil {
 		return nil, fmt.Errorf("read file: %w", err)
 	}
 
-	return data, nil
+	return buf.Bytes(), nil
 }
 
 func (f *filesystem) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	path, err := f.fullPath(key)
 	if err != nil {
 		return err
@@ -122,40 +294,89 @@ func (f *filesystem) Delete(ctx context.Context, key string) error {
 
 	dir := filepath.Dir(path)
 
-	if err := os.Remove(path); err != nil {
-		if errors.Is(err, fs.ErrNotExist) {
-			return nil
+	for _, compressed := range []bool{false, true} {
+		if err := f.remove(variantPath(path, compressed)); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if errors.Is(err, fs.ErrPermission) {
+				return ErrPermissionDenied
+			}
+			return fmt.Errorf("remove file: %w", err)
 		}
-		if errors.Is(err, fs.ErrPermission) {
-			return ErrPermissionDenied
+	}
+
+	f.cleanupDir(dir)
+	return nil
+}
+
+func (f *filesystem) List(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	root := f.basePath
+	if i := strings.LastIndex(prefix, "/"); i > 0 {
+		dir, err := f.fullPath(prefix[:i])
+		if err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("remove file: %w", err)
+		root = dir
+	} else if strings.HasPrefix(prefix, "/") {
+		return nil, ErrInvalidKey
 	}
 
-	if dir != f.basePath && strings.HasPrefix(dir, f.basePath) {
-		entries, err := os.ReadDir(dir)
+	keys := make([]string, 0)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if err != nil {
-			f.logger.Warn("failed to read directory for cleanup", "dir", dir, "error", err)
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, tmpSuffix) {
 			return nil
 		}
 
-		if len(entries) == 0 {
-			if err := os.Remove(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
-				f.logger.Warn("failed to remove empty directory", "dir", dir, "error", err)
-			}
+		rel, err := filepath.Rel(f.basePath, path)
+		if err != nil {
+			return err
+		}
+
+		if key := strings.TrimSuffix(filepath.ToSlash(rel), gzipSuffix); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
 		}
+		return nil
+	})
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if errors.Is(err, fs.ErrPermission) {
+			return nil, ErrPermissionDenied
+		}
+		return nil, fmt.Errorf("walk storage: %w", err)
 	}
 
-	return nil
+	slices.Sort(keys)
+	return slices.Compact(keys), nil
 }
 
 func (f *filesystem) Validate(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
 	path, err := f.fullPath(key)
 	if err != nil {
 		return false, err
 	}
 
-	_, err = os.Stat(path)
+	_, _, err = locate(path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			return false, nil
@@ -169,6 +390,120 @@ func (f *filesystem) Validate(ctx context.Context, key string) (bool, error) {
 	return true, nil
 }
 
+// commit renames a completed temp file into place as the compressed or
+// uncompressed variant of the key at path, enforcing the quota and updating
+// usage with the size difference from the files it replaces. The other
+// variant, left by a write made before compression was toggled, is removed.
+func (f *filesystem) commit(tmpPath, path string, compressed bool, size int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	target, stale := variantPath(path, compressed), variantPath(path, !compressed)
+
+	prev := fileSize(target) + fileSize(stale)
+	if f.quota > 0 && f.used-prev+size > f.quota {
+		os.Remove(tmpPath)
+		return ErrQuotaExceeded
+	}
+
+	if err := os.Rename(tmpPath, target); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	os.Remove(stale)
+
+	f.used += size - prev
+	return nil
+}
+
+// rename moves src to the compressed or uncompressed variant of dst,
+// releasing the bytes of any files dst replaces.
+func (f *filesystem) rename(src, dst string, compressed bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	target, stale := variantPath(dst, compressed), variantPath(dst, !compressed)
+
+	prev := fileSize(target) + fileSize(stale)
+	if err := os.Rename(src, target); err != nil {
+		return err
+	}
+	os.Remove(stale)
+
+	f.used -= prev
+	return nil
+}
+
+// cleanupDir removes dir if it is empty and below the base path.
+func (f *filesystem) cleanupDir(dir string) {
+	if dir == f.basePath || !strings.HasPrefix(dir, f.basePath) {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		f.logger.Warn("failed to read directory for cleanup", "dir", dir, "error", err)
+		return
+	}
+
+	if len(entries) == 0 {
+		if err := os.Remove(dir); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			f.logger.Warn("failed to remove empty directory", "dir", dir, "error", err)
+		}
+	}
+}
+
+// transferPaths resolves the source and destination keys of a copy or move,
+// returning ErrNotFound if the source does not exist. The source is returned
+// as its on-disk path and the destination as its plain path, with whether
+// the source is stored compressed.
+func (f *filesystem) transferPaths(srcKey, dstKey string) (string, string, bool, error) {
+	src, err := f.fullPath(srcKey)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	dst, err := f.fullPath(dstKey)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	actual, compressed, err := locate(src)
+	if err != nil {
+		return "", "", false, openError(err)
+	}
+	if info, err := os.Stat(actual); err != nil {
+		return "", "", false, openError(err)
+	} else if info.IsDir() {
+		return "", "", false, ErrInvalidKey
+	}
+
+	return actual, dst, compressed, nil
+}
+
+// remove deletes the file at path and releases its bytes from usage.
+func (f *filesystem) remove(path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	size := fileSize(path)
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	f.used -= size
+	return nil
+}
+
+// headroom returns how many bytes a write to path may take without exceeding
+// the quota, counting the bytes that overwriting path would free. The final
+// check is repeated under the lock once the write size is known.
+func (f *filesystem) headroom(path string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.quota - f.used + fileSize(path)
+}
+
 func (f *filesystem) fullPath(key string) (string, error) {
 	if key == "" {
 		return "", ErrInvalidKey
@@ -187,3 +522,129 @@ func (f *filesystem) fullPath(key string) (string, error) {
 
 	return fullPath, nil
 }
+
+// writeFile copies r into a newly created file at path, honoring ctx between chunks,
+// gzip-compressing it when compress is set, and returns the number of bytes
+// written to disk. A non-negative limit fails the write with ErrQuotaExceeded
+// once more than limit bytes reach disk.
+// The caller is responsible for removing path if an error is returned.
+func writeFile(ctx context.Context, path string, r io.Reader, limit int64, compress bool) (int64, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("create temp file: %w", err)
+	}
+
+	counter := &countingWriter{w: file, limit: limit}
+
+	var dst io.Writer = counter
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(counter)
+		dst = gz
+	}
+
+	err = copyChunks(ctx, dst, r)
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		file.Close()
+		if errors.Is(err, ErrQuotaExceeded) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("write temp file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("close temp file: %w", err)
+	}
+
+	return counter.n, nil
+}
+
+// openError maps filesystem errors on an existing key to storage errors.
+func openError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return ErrNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return ErrPermissionDenied
+	default:
+		return fmt.Errorf("access file: %w", err)
+	}
+}
+
+// diskUsage sums the sizes of stored files under root, skipping in-progress writes.
+func diskUsage(root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, tmpSuffix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
+
+// fileSize returns the size of the file at path, or 0 if it does not exist.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// countingWriter counts bytes written through it. A non-negative limit fails
+// writes with ErrQuotaExceeded once more than limit bytes are written, so
+// oversized writes stop early instead of filling the disk.
+type countingWriter struct {
+	w     io.Writer
+	n     int64
+	limit int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if c.limit >= 0 && c.n > c.limit {
+		return n, ErrQuotaExceeded
+	}
+	return n, err
+}
+
+// copyChunks copies src to dst in chunkSize pieces, returning ctx.Err()
+// as soon as the context is cancelled between chunks.
+func copyChunks(ctx context.Context, dst io.Writer, src io.Reader) error {
+	buf := make([]byte, chunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
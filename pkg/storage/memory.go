@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+)
+
+// memory implements System with blobs held in a map, for tests and
+// ephemeral previews that should not touch disk. Keys are validated and
+// cleaned as by the filesystem backend, so both accept the same keys.
+// There are no directories, so removing a key's last sibling needs no
+// cleanup, and no temp files, so memory is not a TempSweeper.
+//
+// Total stored bytes are tracked in used, guarded by mu alongside blobs,
+// so writes can be checked against quota. A quota of 0 disables enforcement.
+type memory struct {
+	quota  int64
+	logger *slog.Logger
+
+	mu    sync.RWMutex
+	blobs map[string][]byte
+	used  int64
+}
+
+// newMemory creates an empty in-memory storage system.
+func newMemory(cfg *Config, logger *slog.Logger) *memory {
+	return &memory{
+		quota:  cfg.MaxTotalBytes(),
+		logger: logger.With("system", "storage"),
+		blobs:  make(map[string][]byte),
+	}
+}
+
+func (m *memory) Start(lc *lifecycle.Coordinator) error {
+	m.logger.Info("starting storage system", "backend", BackendMemory, "quota_bytes", m.quota)
+	return nil
+}
+
+// Path always fails for an existing key with ErrNotOnDisk, since no blob
+// is held in a file.
+func (m *memory) Path(ctx context.Context, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	exists, err := m.Validate(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", ErrNotFound
+	}
+	return "", ErrNotOnDisk
+}
+
+func (m *memory) Store(ctx context.Context, key string, data []byte) error {
+	return m.StoreStream(ctx, key, bytes.NewReader(data))
+}
+
+func (m *memory) StoreStream(ctx context.Context, key string, r io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	key, err := memoryKey(key)
+	if err != nil {
+		return err
+	}
+
+	limit := int64(-1)
+	if m.quota > 0 {
+		m.mu.RLock()
+		limit = m.quota - m.used + int64(len(m.blobs[key]))
+		m.mu.RUnlock()
+	}
+
+	var buf bytes.Buffer
+	if err := copyChunks(ctx, &countingWriter{w: &buf, limit: limit}, r); err != nil {
+		return err
+	}
+
+	return m.put(key, buf.Bytes())
+}
+
+func (m *memory) Retrieve(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	key, err := memoryKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return bytes.Clone(data), nil
+}
+
+func (m *memory) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	key, err := memoryKey(key)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.used -= int64(len(m.blobs[key]))
+	delete(m.blobs, key)
+	return nil
+}
+
+func (m *memory) Copy(ctx context.Context, srcKey, dstKey string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	src, dst, err := memoryKeys(srcKey, dstKey)
+	if err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	data, ok := m.blobs[src]
+	m.mu.RUnlock()
+	if !ok {
+		return ErrNotFound
+	}
+	if src == dst {
+		return nil
+	}
+
+	return m.put(dst, data)
+}
+
+func (m *memory) Move(ctx context.Context, srcKey, dstKey string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	src, dst, err := memoryKeys(srcKey, dstKey)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.blobs[src]
+	if !ok {
+		return ErrNotFound
+	}
+	if src == dst {
+		return nil
+	}
+
+	m.used -= int64(len(m.blobs[dst]))
+	m.blobs[dst] = data
+	delete(m.blobs, src)
+	return nil
+}
+
+func (m *memory) List(ctx context.Context, prefix string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if i := strings.LastIndex(prefix, "/"); i > 0 {
+		if _, err := memoryKey(prefix[:i]); err != nil {
+			return nil, err
+		}
+	} else if strings.HasPrefix(prefix, "/") {
+		return nil, ErrInvalidKey
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0)
+	for key := range m.blobs {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.Sort(keys)
+	return keys, nil
+}
+
+func (m *memory) Validate(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	key, err := memoryKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.blobs[key]
+	return ok, nil
+}
+
+// put stores a copy of data at key, enforcing the quota and updating usage
+// with the size difference from the blob it replaces.
+func (m *memory) put(key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	size := int64(len(data))
+	prev := int64(len(m.blobs[key]))
+	if m.quota > 0 && m.used-prev+size > m.quota {
+		return ErrQuotaExceeded
+	}
+
+	m.blobs[key] = bytes.Clone(data)
+	m.used += size - prev
+	return nil
+}
+
+// memoryKey validates key and returns its cleaned form, rejecting the same
+// keys as the filesystem backend: empty keys, absolute paths, and keys that
+// escape the root.
+func memoryKey(key string) (string, error) {
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned == "." || strings.HasPrefix(cleaned, "..") || path.IsAbs(cleaned) {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+// memoryKeys validates and cleans the source and destination keys of a
+// copy or move.
+func memoryKeys(srcKey, dstKey string) (string, string, error) {
+	src, err := memoryKey(srcKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	dst, err := memoryKey(dstKey)
+	if err != nil {
+		return "", "", err
+	}
+	return src, dst, nil
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"io"
 
 	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
 )
@@ -13,8 +14,15 @@ type System interface {
 	// Store saves data at the specified key. If the key already exists,
 	// its contents are overwritten. Parent directories are created as needed.
 	// Returns ErrInvalidKey if the key is empty or contains path traversal.
+	// Returns ctx.Err() if the context is cancelled before or during the write.
+	// Returns ErrQuotaExceeded if the write would exceed the storage quota.
 	Store(ctx context.Context, key string, data []byte) error
 
+	// StoreStream saves the contents of r at the specified key, copying in
+	// chunks and checking ctx between chunks. A cancelled write leaves no
+	// partial file behind and any existing contents at key are preserved.
+	StoreStream(ctx context.Context, key string, r io.Reader) error
+
 	// Retrieve returns the data stored at the specified key.
 	// Returns ErrNotFound if the key does not exist.
 	// Returns ErrInvalidKey if the key is malformed.
@@ -25,6 +33,23 @@ type System interface {
 	// Returns ErrInvalidKey if the key is malformed.
 	Delete(ctx context.Context, key string) error
 
+	// Copy duplicates the data at srcKey to dstKey, overwriting dstKey if it
+	// exists. Returns ErrNotFound if srcKey does not exist and ErrInvalidKey
+	// if either key is malformed.
+	Copy(ctx context.Context, srcKey, dstKey string) error
+
+	// Move relocates the data at srcKey to dstKey, overwriting dstKey if it
+	// exists, and removes srcKey. Empty parent directories of srcKey are
+	// cleaned up as with Delete. Returns ErrNotFound if srcKey does not exist
+	// and ErrInvalidKey if either key is malformed.
+	Move(ctx context.Context, srcKey, dstKey string) error
+
+	// List returns the keys that begin with prefix, in lexical order.
+	// An empty prefix lists every key; a prefix matching nothing returns an
+	// empty slice. Returns ErrInvalidKey if the prefix contains path traversal.
+	// Returns ctx.Err() if the context is cancelled during the listing.
+	List(ctx context.Context, prefix string) ([]string, error)
+
 	// Validate checks if a key exists and is accessible.
 	// Returns (true, nil) if the key exists and is readable.
 	// Returns (false, nil) if the key does not exist.
@@ -32,8 +57,13 @@ type System interface {
 	Validate(ctx context.Context, key string) (bool, error)
 
 	// Start registers lifecycle hooks with the coordinator.
-	// For filesystem storage, this creates the base directory.
+	// For filesystem storage, this creates the base directory; in-memory
+	// storage registers none.
 	Start(lc *lifecycle.Coordinator) error
 
+	// Path returns the on-disk path of the file holding the data at key, for
+	// callers that must read it in place. Returns ErrNotFound if the key does
+	// not exist, ErrCompressed if it is stored compressed, and ErrNotOnDisk
+	// if the backend does not keep blobs in files.
 	Path(ctx context.Context, key string) (string, error)
 }
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TempSweeper is implemented by storage systems whose writes stage data in
+// temp files, which are left behind if the process dies mid-write.
+type TempSweeper interface {
+	// SweepTemp removes temp files last modified more than olderThan ago
+	// that no in-progress write is using, and returns how many it removed.
+	// Returns ctx.Err() if the context is cancelled during the sweep.
+	SweepTemp(ctx context.Context, olderThan time.Duration) (int, error)
+}
+
+// trackTemp marks tmpPath as in use by a write and returns a function that
+// releases it. Nested writes to the same temp path are counted.
+func (f *filesystem) trackTemp(tmpPath string) func() {
+	f.tempMu.Lock()
+	f.temps[tmpPath]++
+	f.tempMu.Unlock()
+
+	return func() {
+		f.tempMu.Lock()
+		defer f.tempMu.Unlock()
+		if f.temps[tmpPath]--; f.temps[tmpPath] <= 0 {
+			delete(f.temps, tmpPath)
+		}
+	}
+}
+
+func (f *filesystem) SweepTemp(ctx context.Context, olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+
+	err := filepath.WalkDir(f.basePath, func(path string, d fs.DirEntry, err error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, tmpSuffix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			return nil
+		}
+
+		f.tempMu.Lock()
+		defer f.tempMu.Unlock()
+
+		if f.temps[path] > 0 {
+			return nil
+		}
+		if err := os.Remove(path); err == nil {
+			removed++
+		}
+		return nil
+	})
+
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return removed, ctxErr
+		}
+		return removed, fmt.Errorf("sweep temp files: %w", err)
+	}
+
+	return removed, nil
+}
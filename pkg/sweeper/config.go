@@ -0,0 +1,95 @@
+package sweeper
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config contains background sweeper configuration.
+type Config struct {
+	// Interval is the time between sweeps. "0s" disables the sweeper.
+	// Default: "10m"
+	Interval string `toml:"interval"`
+
+	// TempTTL is the age after which an abandoned storage temp file is
+	// removed. It should comfortably exceed the longest expected write.
+	// Default: "1h"
+	TempTTL string `toml:"temp_ttl"`
+}
+
+// Env maps environment variable names for sweeper configuration.
+type Env struct {
+	Interval string
+	TempTTL  string
+}
+
+// IntervalDuration parses and returns the sweep interval as a time.Duration.
+func (c *Config) IntervalDuration() time.Duration {
+	d, _ := time.ParseDuration(c.Interval)
+	return d
+}
+
+// TempTTLDuration parses and returns the temp file TTL as a time.Duration.
+func (c *Config) TempTTLDuration() time.Duration {
+	d, _ := time.ParseDuration(c.TempTTL)
+	return d
+}
+
+// Finalize applies defaults, loads environment overrides, and validates the sweeper configuration.
+// All validation problems are reported together as a joined error.
+func (c *Config) Finalize(env *Env) error {
+	c.loadDefaults()
+	if env != nil {
+		c.loadEnv(env)
+	}
+	return c.validate()
+}
+
+// Merge applies values from overlay configuration that differ from zero values.
+func (c *Config) Merge(overlay *Config) {
+	if overlay.Interval != "" {
+		c.Interval = overlay.Interval
+	}
+	if overlay.TempTTL != "" {
+		c.TempTTL = overlay.TempTTL
+	}
+}
+
+func (c *Config) loadDefaults() {
+	if c.Interval == "" {
+		c.Interval = "10m"
+	}
+	if c.TempTTL == "" {
+		c.TempTTL = "1h"
+	}
+}
+
+func (c *Config) loadEnv(env *Env) {
+	if env.Interval != "" {
+		if v := os.Getenv(env.Interval); v != "" {
+			c.Interval = v
+		}
+	}
+	if env.TempTTL != "" {
+		if v := os.Getenv(env.TempTTL); v != "" {
+			c.TempTTL = v
+		}
+	}
+}
+
+func (c *Config) validate() error {
+	var errs []error
+	if d, err := time.ParseDuration(c.Interval); err != nil {
+		errs = append(errs, fmt.Errorf("invalid interval: %w", err))
+	} else if d < 0 {
+		errs = append(errs, fmt.Errorf("interval must not be negative"))
+	}
+	if d, err := time.ParseDuration(c.TempTTL); err != nil {
+		errs = append(errs, fmt.Errorf("invalid temp_ttl: %w", err))
+	} else if d <= 0 {
+		errs = append(errs, fmt.Errorf("temp_ttl must be positive"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+// Package sweeper runs periodic cleanup tasks in the background.
+package sweeper
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+)
+
+// Task removes expired artifacts of one kind and returns how many it removed.
+// Tasks run alongside live traffic, so they must only remove artifacts no
+// in-progress operation can still be using, and should return promptly when
+// ctx is cancelled.
+type Task func(ctx context.Context) (int, error)
+
+type namedTask struct {
+	name string
+	run  Task
+}
+
+// Sweeper runs its registered tasks on a fixed interval until shutdown.
+type Sweeper struct {
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	tasks []namedTask
+
+	done chan struct{}
+}
+
+// New creates a Sweeper from a finalized Config. Register tasks with Add
+// before calling Start.
+func New(cfg *Config, logger *slog.Logger) *Sweeper {
+	return &Sweeper{
+		interval: cfg.IntervalDuration(),
+		logger:   logger.With("system", "sweeper"),
+		done:     make(chan struct{}),
+	}
+}
+
+// Add registers a named task to run on every sweep, after those added before it.
+func (s *Sweeper) Add(name string, task Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, namedTask{name: name, run: task})
+}
+
+// Sweep runs every task once, in registration order, and returns the number
+// of artifacts each removed. A failing task is logged and does not prevent
+// later tasks from running. Sweep stops early if ctx is cancelled.
+func (s *Sweeper) Sweep(ctx context.Context) map[string]int {
+	s.mu.Lock()
+	tasks := append([]namedTask(nil), s.tasks...)
+	s.mu.Unlock()
+
+	counts := make(map[string]int, len(tasks))
+	for _, t := range tasks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		removed, err := t.run(ctx)
+		counts[t.name] = removed
+
+		if err != nil {
+			s.logger.Error("sweep task failed", "task", t.name, "removed", removed, "error", err)
+			continue
+		}
+		if removed > 0 {
+			s.logger.Info("sweep task removed artifacts", "task", t.name, "removed", removed)
+		}
+	}
+	return counts
+}
+
+// Start registers the sweeper as a lifecycle subsystem that starts after the
+// subsystems named in deps. Sweeps run every interval until the coordinator
+// shuts down; shutdown waits for an in-progress sweep to finish. A zero
+// interval disables the sweeper.
+func (s *Sweeper) Start(lc *lifecycle.Coordinator, deps ...string) error {
+	if s.interval <= 0 {
+		s.logger.Info("sweeper disabled")
+		return nil
+	}
+
+	lc.Register("sweeper", func() error {
+		go s.run(lc.Context())
+		s.logger.Info("sweeper started", "interval", s.interval.String())
+		return nil
+	}, deps...)
+
+	lc.OnStop("sweeper", func() {
+		<-s.done
+		s.logger.Info("sweeper stopped")
+	})
+
+	return nil
+}
+
+func (s *Sweeper) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep(ctx)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ImmutableCacheControl is applied to fingerprinted asset requests whose
+	// version matches the current content hash.
+	ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+	// DefaultCacheControl is applied to asset requests without a current
+	// fingerprint so stale bundles are revalidated quickly.
+	DefaultCacheControl = "public, max-age=300"
+)
+
+// Assets holds content hashes for the files in a filesystem, computed once
+// at startup. Templates reference fingerprinted URLs via Path, and Handler
+// serves the files with cache headers based on the requested fingerprint.
+type Assets struct {
+	fsys   fs.FS
+	hashes map[string]string
+}
+
+// NewAssets walks fsys and computes a content hash for every file.
+func NewAssets(fsys fs.FS) (*Assets, error) {
+	hashes := make(map[string]string)
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(data)
+		hashes[name] = hex.EncodeToString(sum[:8])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Assets{fsys: fsys, hashes: hashes}, nil
+}
+
+// Path returns name with a ?v=<hash> fingerprint appended.
+// Unknown names, or a nil Assets, return name unchanged.
+func (a *Assets) Path(name string) string {
+	if a == nil {
+		return name
+	}
+	hash, ok := a.hashes[name]
+	if !ok {
+		return name
+	}
+	return name + "?v=" + hash
+}
+
+// Hash returns the content hash for name and whether the file is known.
+func (a *Assets) Hash(name string) (string, bool) {
+	hash, ok := a.hashes[name]
+	return hash, ok
+}
+
+// Handler serves files from the filesystem. Requests whose v query parameter
+// matches the file's current hash are marked immutable; other requests for
+// known files receive DefaultCacheControl.
+func (a *Assets) Handler() http.Handler {
+	server := http.FileServer(http.FS(a.fsys))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hash, ok := a.hashes[strings.TrimPrefix(r.URL.Path, "/")]; ok {
+			if r.URL.Query().Get("v") == hash {
+				w.Header().Set("Cache-Control", ImmutableCacheControl)
+			} else {
+				w.Header().Set("Cache-Control", DefaultCacheControl)
+			}
+		}
+		server.ServeHTTP(w, r)
+	})
+}
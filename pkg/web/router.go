@@ -1,6 +1,9 @@
 package web
 
-import "net/http"
+import (
+	"net/http"
+	"path"
+)
 
 // Router wraps http.ServeMux with optional fallback handling for unmatched routes.
 // Use SetFallback to configure custom 404 behavior; other error handling
@@ -41,3 +44,21 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 	r.mux.ServeHTTP(w, req)
 }
+
+// ShellFallback returns a fallback handler for client-routed applications.
+// GET and HEAD requests for extensionless paths render shell so the client
+// router can resolve them. Paths with a file extension are treated as missing
+// assets and receive a 404, as do requests with any other method.
+func ShellFallback(shell http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.NotFound(w, r)
+			return
+		}
+		if path.Ext(r.URL.Path) != "" {
+			http.NotFound(w, r)
+			return
+		}
+		shell(w, r)
+	}
+}
@@ -21,10 +21,12 @@ type ViewDef struct {
 
 // ViewData contains the data passed to page templates during rendering.
 // BasePath enables portable URL generation in templates via {{ .BasePath }}.
+// Assets enables fingerprinted asset URLs via {{ .Assets.Path "app.js" }}.
 type ViewData struct {
 	Title    string
 	Bundle   string
 	BasePath string
+	Assets   *Assets
 	Data     any
 }
 
@@ -34,6 +36,7 @@ type ViewData struct {
 type TemplateSet struct {
 	views    map[string]*template.Template
 	basePath string
+	assets   *Assets
 }
 
 // NewTemplateSet creates a TemplateSet by parsing layout templates and cloning them
@@ -71,6 +74,12 @@ func NewTemplateSet(layoutFS, viewFS embed.FS, layoutGlob, viewSubdir, basePath
 	}, nil
 }
 
+// SetAssets configures the fingerprinted assets included in ViewData for all handlers.
+// If not set, {{ .Assets.Path }} returns asset names unchanged.
+func (ts *TemplateSet) SetAssets(assets *Assets) {
+	ts.assets = assets
+}
+
 // ErrorHandler returns an HTTP handler that renders an error page with the given status code.
 func (ts *TemplateSet) ErrorHandler(layout string, view ViewDef, status int) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -79,6 +88,7 @@ func (ts *TemplateSet) ErrorHandler(layout string, view ViewDef, status int) htt
 			Title:    view.Title,
 			Bundle:   view.Bundle,
 			BasePath: ts.basePath,
+			Assets:   ts.assets,
 		}
 		if err := ts.Render(w, layout, view.Template, data); err != nil {
 			http.Error(w, http.StatusText(status), status)
@@ -93,6 +103,7 @@ func (ts *TemplateSet) PageHandler(layout string, view ViewDef) http.HandlerFunc
 			Title:    view.Title,
 			Bundle:   view.Bundle,
 			BasePath: ts.basePath,
+			Assets:   ts.assets,
 		}
 		if err := ts.Render(w, layout, view.Template, data); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -0,0 +1,90 @@
+package internal_agents_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+)
+
+const validAgentConfig = `{
+	"name": "ollama-agent",
+	"provider": {"name": "ollama", "base_url": "http://localhost:11434"},
+	"model": {"name": "gemma3:4b"}
+}`
+
+func TestCreateCommand_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        agents.CreateCommand
+		wantFields []string
+	}{
+		{
+			"valid",
+			agents.CreateCommand{Name: "agent", Config: json.RawMessage(validAgentConfig)},
+			nil,
+		},
+		{
+			"missing name",
+			agents.CreateCommand{Name: "  ", Config: json.RawMessage(validAgentConfig)},
+			[]string{"name"},
+		},
+		{
+			"missing config",
+			agents.CreateCommand{Name: "agent"},
+			[]string{"config"},
+		},
+		{
+			"malformed config",
+			agents.CreateCommand{Name: "agent", Config: json.RawMessage(`{"provider":`)},
+			[]string{"config"},
+		},
+		{
+			"config with wrong types",
+			agents.CreateCommand{Name: "agent", Config: json.RawMessage(`{"provider":"ollama"}`)},
+			[]string{"config"},
+		},
+		{
+			"unknown provider",
+			agents.CreateCommand{Name: "agent", Config: json.RawMessage(`{"provider":{"name":"nonexistent"},"model":{"name":"m"}}`)},
+			[]string{"config"},
+		},
+		{
+			"all invalid",
+			agents.CreateCommand{},
+			[]string{"name", "config"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate()
+
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *handlers.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if len(verr.Fields) != len(tt.wantFields) {
+				t.Errorf("Fields = %v, want keys %v", verr.Fields, tt.wantFields)
+			}
+			for _, f := range tt.wantFields {
+				if verr.Fields[f] == "" {
+					t.Errorf("Fields[%q] missing in %v", f, verr.Fields)
+				}
+			}
+			if got := agents.MapHTTPStatus(err); got != http.StatusBadRequest {
+				t.Errorf("MapHTTPStatus() = %d, want %d", got, http.StatusBadRequest)
+			}
+		})
+	}
+}
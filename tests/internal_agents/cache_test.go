@@ -0,0 +1,189 @@
+package internal_agents_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/google/uuid"
+)
+
+var agentID = uuid.MustParse("0c1d2e3f-4a5b-4c6d-8e7f-8091a2b3c4d5")
+
+func TestResponseCache_Hit(t *testing.T) {
+	cache := agents.NewResponseCache(time.Minute, 0)
+
+	calls := 0
+	provider := func() (*response.ChatResponse, error) {
+		calls++
+		return &response.ChatResponse{ID: "resp-1", Model: "test-model"}, nil
+	}
+
+	first, hit, err := cache.Fetch(agentID, "key", provider)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if hit {
+		t.Error("first Fetch() should be a miss")
+	}
+
+	second, hit, err := cache.Fetch(agentID, "key", provider)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if !hit {
+		t.Error("second Fetch() should be a hit")
+	}
+
+	if calls != 1 {
+		t.Errorf("provider calls = %d, want 1", calls)
+	}
+
+	if second.ID != first.ID || second.Model != first.Model {
+		t.Errorf("cached response = %+v, want %+v", second, first)
+	}
+}
+
+func TestResponseCache_Expiry(t *testing.T) {
+	cache := agents.NewResponseCache(20*time.Millisecond, 0)
+
+	calls := 0
+	provider := func() (*response.ChatResponse, error) {
+		calls++
+		return &response.ChatResponse{ID: "resp"}, nil
+	}
+
+	cache.Fetch(agentID, "key", provider)
+	time.Sleep(40 * time.Millisecond)
+
+	_, hit, err := cache.Fetch(agentID, "key", provider)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if hit {
+		t.Error("Fetch() after TTL should be a miss")
+	}
+	if calls != 2 {
+		t.Errorf("provider calls = %d, want 2", calls)
+	}
+}
+
+func TestResponseCache_ErrorNotCached(t *testing.T) {
+	cache := agents.NewResponseCache(time.Minute, 0)
+
+	_, _, err := cache.Fetch(agentID, "key", func() (*response.ChatResponse, error) {
+		return nil, errors.New("provider failed")
+	})
+	if err == nil {
+		t.Fatal("Fetch() should return provider error")
+	}
+
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d, want 0 after failed fetch", cache.Len())
+	}
+}
+
+func TestResponseCache_Invalidate(t *testing.T) {
+	cache := agents.NewResponseCache(time.Minute, 0)
+	other := uuid.New()
+
+	provider := func() (*response.ChatResponse, error) {
+		return &response.ChatResponse{ID: "resp"}, nil
+	}
+
+	cache.Fetch(agentID, "a", provider)
+	cache.Fetch(other, "b", provider)
+
+	cache.Invalidate(agentID)
+
+	if _, hit, _ := cache.Fetch(agentID, "a", provider); hit {
+		t.Error("Fetch() after Invalidate should be a miss")
+	}
+	if _, hit, _ := cache.Fetch(other, "b", provider); !hit {
+		t.Error("Invalidate should keep other agents' responses")
+	}
+}
+
+func TestResponseCache_InvalidateDuringFetch(t *testing.T) {
+	cache := agents.NewResponseCache(time.Minute, 0)
+
+	cache.Fetch(agentID, "key", func() (*response.ChatResponse, error) {
+		cache.Invalidate(agentID)
+		return &response.ChatResponse{ID: "stale"}, nil
+	})
+
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d, a fetch overlapping Invalidate should not be cached", cache.Len())
+	}
+}
+
+func TestResponseCache_MaxEntries(t *testing.T) {
+	cache := agents.NewResponseCache(time.Minute, 2)
+
+	provider := func() (*response.ChatResponse, error) {
+		return &response.ChatResponse{ID: "resp"}, nil
+	}
+
+	for _, key := range []string{"first", "second", "third"} {
+		cache.Fetch(agentID, key, provider)
+		time.Sleep(time.Millisecond)
+	}
+
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+	if _, hit, _ := cache.Fetch(agentID, "third", provider); !hit {
+		t.Error("newest entry should be kept")
+	}
+	if _, hit, _ := cache.Fetch(agentID, "first", provider); hit {
+		t.Error("oldest entry should be evicted")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	id := uuid.New()
+
+	base := agents.CacheKey(id, "", "prompt", []string{"img"}, map[string]any{"system_prompt": "a", "cache": true})
+
+	tests := []struct {
+		name  string
+		key   string
+		equal bool
+	}{
+		{"identical inputs", agents.CacheKey(id, "", "prompt", []string{"img"}, map[string]any{"system_prompt": "a", "cache": true}), true},
+		{"cache option ignored", agents.CacheKey(id, "", "prompt", []string{"img"}, map[string]any{"system_prompt": "a"}), true},
+		{"different agent", agents.CacheKey(uuid.New(), "", "prompt", []string{"img"}, map[string]any{"system_prompt": "a"}), false},
+		{"different prompt", agents.CacheKey(id, "", "other", []string{"img"}, map[string]any{"system_prompt": "a"}), false},
+		{"different images", agents.CacheKey(id, "", "prompt", []string{"img2"}, map[string]any{"system_prompt": "a"}), false},
+		{"different options", agents.CacheKey(id, "", "prompt", []string{"img"}, map[string]any{"system_prompt": "b"}), false},
+		{"token supplied", agents.CacheKey(id, "secret", "prompt", []string{"img"}, map[string]any{"system_prompt": "a"}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.key == base) != tt.equal {
+				t.Errorf("key equality = %v, want %v", tt.key == base, tt.equal)
+			}
+		})
+	}
+}
+
+func TestCacheKey_Token(t *testing.T) {
+	id := uuid.New()
+
+	a := agents.CacheKey(id, "token-a", "prompt", nil, nil)
+	b := agents.CacheKey(id, "token-b", "prompt", nil, nil)
+
+	if a == b {
+		t.Error("keys for different tokens should differ")
+	}
+	if a != agents.CacheKey(id, "token-a", "prompt", nil, nil) {
+		t.Error("keys for the same token should match")
+	}
+	if strings.Contains(a, "token-a") {
+		t.Error("key should not contain the raw token")
+	}
+}
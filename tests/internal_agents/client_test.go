@@ -0,0 +1,56 @@
+package internal_agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/providers"
+	"github.com/google/uuid"
+)
+
+func TestChat_ProviderRequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	provs := &fakeProviders{provider: providers.Provider{
+		ID:     uuid.New(),
+		Config: json.RawMessage(`{"name": "ollama", "base_url": "` + srv.URL + `"}`),
+	}}
+
+	client := config.ProviderHTTPConfig{}
+	if err := client.Finalize(nil); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	client.RequestTimeout = "100ms"
+
+	a := referencingAgent(provs.provider.ID)
+	sys, _ := newAgentsSystemWithClient(t, provs, client, a)
+
+	start := time.Now()
+	_, err := sys.Chat(context.Background(), a.ID, "hello", nil, "")
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Chat() expected a timeout error")
+	}
+	var timeout interface{ Timeout() bool }
+	if !errors.As(err, &timeout) || !timeout.Timeout() {
+		t.Errorf("Chat() error = %v, want a timeout", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("Chat() took %s, want it bounded by the request timeout", elapsed)
+	}
+}
@@ -0,0 +1,327 @@
+package internal_agents_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/providers"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var agentCols = []string{"id", "name", "provider_id", "config", "created_at", "updated_at"}
+
+// fakeAgentsDB serves the agent lookup, insert, update, and delete statements.
+// SELECT returns the agent whose id matches the first argument and counts the
+// lookup; INSERT fails with a unique violation when the name is taken and
+// otherwise records the stored config; UPDATE and DELETE modify the agent
+// whose id is the last argument.
+type fakeAgentsDB struct {
+	mu       sync.Mutex
+	agents   map[string]agents.Agent
+	inserted []byte
+	finds    int
+}
+
+// findCount returns the number of agent lookups served.
+func (db *fakeAgentsDB) findCount() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.finds
+}
+
+var (
+	fakeAgentsMu sync.Mutex
+	fakeAgentDBs = map[string]*fakeAgentsDB{}
+)
+
+func init() {
+	sql.Register("fakeagents", fakeAgentsDriver{})
+}
+
+type fakeAgentsDriver struct{}
+
+func (fakeAgentsDriver) Open(dsn string) (driver.Conn, error) {
+	fakeAgentsMu.Lock()
+	defer fakeAgentsMu.Unlock()
+	return &fakeAgentsConn{db: fakeAgentDBs[dsn]}, nil
+}
+
+type fakeAgentsConn struct {
+	db *fakeAgentsDB
+}
+
+func (c *fakeAgentsConn) Prepare(q string) (driver.Stmt, error) {
+	return &fakeAgentsStmt{db: c.db, query: q}, nil
+}
+
+func (c *fakeAgentsConn) Close() error              { return nil }
+func (c *fakeAgentsConn) Begin() (driver.Tx, error) { return fakeAgentsTx{}, nil }
+
+type fakeAgentsTx struct{}
+
+func (fakeAgentsTx) Commit() error   { return nil }
+func (fakeAgentsTx) Rollback() error { return nil }
+
+type fakeAgentsStmt struct {
+	db    *fakeAgentsDB
+	query string
+}
+
+func (s *fakeAgentsStmt) Close() error  { return nil }
+func (s *fakeAgentsStmt) NumInput() int { return -1 }
+
+func (s *fakeAgentsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if strings.Contains(s.query, "DELETE FROM agents") {
+		s.db.mu.Lock()
+		defer s.db.mu.Unlock()
+
+		id := args[0].(string)
+		if _, ok := s.db.agents[id]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		delete(s.db.agents, id)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeAgentsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if strings.Contains(s.query, "INSERT INTO agents") {
+		name := args[0].(string)
+		for _, a := range s.db.agents {
+			if a.Name == name {
+				return nil, &pgconn.PgError{Code: "23505"}
+			}
+		}
+
+		config, _ := args[1].([]byte)
+		if config == nil {
+			config = []byte(args[1].(string))
+		}
+		s.db.inserted = config
+
+		var providerID *uuid.UUID
+		if id, ok := args[2].(string); ok {
+			parsed := uuid.MustParse(id)
+			providerID = &parsed
+		}
+
+		now := time.Now()
+		a := agents.Agent{ID: uuid.New(), Name: name, ProviderID: providerID, Config: config, CreatedAt: now, UpdatedAt: now}
+		s.db.agents[a.ID.String()] = a
+		return agentRows(a), nil
+	}
+
+	if strings.Contains(s.query, "UPDATE agents") {
+		a, ok := s.db.agents[args[3].(string)]
+		if !ok {
+			return &fakeAgentRows{}, nil
+		}
+
+		config, _ := args[1].([]byte)
+		if config == nil {
+			config = []byte(args[1].(string))
+		}
+		a.Name = args[0].(string)
+		a.Config = config
+		a.UpdatedAt = time.Now()
+		s.db.agents[a.ID.String()] = a
+		return agentRows(a), nil
+	}
+
+	s.db.finds++
+	if a, ok := s.db.agents[args[0].(string)]; ok {
+		return agentRows(a), nil
+	}
+	return &fakeAgentRows{}, nil
+}
+
+func agentRows(a agents.Agent) driver.Rows {
+	var providerID driver.Value
+	if a.ProviderID != nil {
+		providerID = a.ProviderID.String()
+	}
+
+	return &fakeAgentRows{values: [][]driver.Value{{
+		a.ID.String(), a.Name, providerID, []byte(a.Config), a.CreatedAt, a.UpdatedAt,
+	}}}
+}
+
+type fakeAgentRows struct {
+	values [][]driver.Value
+	pos    int
+}
+
+func (r *fakeAgentRows) Columns() []string { return agentCols }
+func (r *fakeAgentRows) Close() error      { return nil }
+
+func (r *fakeAgentRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+func newCloneSystem(t *testing.T, existing ...agents.Agent) (agents.System, *fakeAgentsDB) {
+	t.Helper()
+	return newAgentsSystem(t, nil, existing...)
+}
+
+// newAgentsSystem creates an agents system backed by a fakeAgentsDB seeded
+// with existing agents, resolving provider references through provs.
+func newAgentsSystem(t *testing.T, provs providers.System, existing ...agents.Agent) (agents.System, *fakeAgentsDB) {
+	t.Helper()
+	return newAgentsSystemWithClient(t, provs, config.ProviderHTTPConfig{}, existing...)
+}
+
+// newAgentsSystemWithClient is newAgentsSystem with client as the provider
+// HTTP config of agent executors.
+func newAgentsSystemWithClient(t *testing.T, provs providers.System, client config.ProviderHTTPConfig, existing ...agents.Agent) (agents.System, *fakeAgentsDB) {
+	t.Helper()
+
+	fdb := &fakeAgentsDB{agents: map[string]agents.Agent{}}
+	for _, a := range existing {
+		fdb.agents[a.ID.String()] = a
+	}
+
+	fakeAgentsMu.Lock()
+	fakeAgentDBs[t.Name()] = fdb
+	fakeAgentsMu.Unlock()
+
+	db, err := sql.Open("fakeagents", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return agents.New(provs, nil, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, nil, config.AgentDebugConfig{}, client), fdb
+}
+
+func sourceAgent() agents.Agent {
+	return agents.Agent{
+		ID:   uuid.New(),
+		Name: "source",
+		Config: json.RawMessage(`{
+			"name": "source",
+			"system_prompt": "be brief",
+			"provider": {"name": "azure", "base_url": "https://example.test", "options": {"token": "secret-token", "deployment": "gpt-4o"}},
+			"model": {"name": "gpt-4o"}
+		}`),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func cloneRequest(id, body string) (*http.Request, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/api/agents/"+id+"/clone", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", id)
+	return req, httptest.NewRecorder()
+}
+
+func TestClone_CopiesConfigWithoutCredentials(t *testing.T) {
+	src := sourceAgent()
+	sys, fdb := newCloneSystem(t, src)
+
+	req, rec := cloneRequest(src.ID.String(), `{"name":"copy"}`)
+	sys.Handler().Clone(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+
+	var got agents.Agent
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if got.Name != "copy" {
+		t.Errorf("Name = %q, want %q", got.Name, "copy")
+	}
+	if got.ID == src.ID {
+		t.Error("clone reused the source ID")
+	}
+
+	if strings.Contains(string(fdb.inserted), "secret-token") {
+		t.Errorf("stored config contains credential: %s", fdb.inserted)
+	}
+
+	var cfg struct {
+		SystemPrompt string `json:"system_prompt"`
+		Provider     struct {
+			Name    string         `json:"name"`
+			BaseURL string         `json:"base_url"`
+			Options map[string]any `json:"options"`
+		} `json:"provider"`
+		Model struct {
+			Name string `json:"name"`
+		} `json:"model"`
+	}
+	if err := json.Unmarshal(got.Config, &cfg); err != nil {
+		t.Fatalf("decode config: %v", err)
+	}
+
+	if cfg.SystemPrompt != "be brief" || cfg.Provider.Name != "azure" ||
+		cfg.Provider.BaseURL != "https://example.test" || cfg.Model.Name != "gpt-4o" {
+		t.Errorf("config fields not copied: %+v", cfg)
+	}
+	if cfg.Provider.Options["deployment"] != "gpt-4o" {
+		t.Errorf("provider options not copied: %v", cfg.Provider.Options)
+	}
+	if _, ok := cfg.Provider.Options["token"]; ok {
+		t.Error("token was copied to the clone")
+	}
+}
+
+func TestClone_NameConflict(t *testing.T) {
+	src := sourceAgent()
+	sys, _ := newCloneSystem(t, src)
+
+	req, rec := cloneRequest(src.ID.String(), `{"name":"source"}`)
+	sys.Handler().Clone(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body.String())
+	}
+}
+
+func TestClone_SourceNotFound(t *testing.T) {
+	sys, _ := newCloneSystem(t)
+
+	req, rec := cloneRequest(uuid.NewString(), `{"name":"copy"}`)
+	sys.Handler().Clone(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+}
+
+func TestClone_MissingName(t *testing.T) {
+	src := sourceAgent()
+	sys, _ := newCloneSystem(t, src)
+
+	req, rec := cloneRequest(src.ID.String(), `{"name":"  "}`)
+	sys.Handler().Clone(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+}
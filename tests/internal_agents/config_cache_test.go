@@ -0,0 +1,241 @@
+package internal_agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	agtconfig "github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/google/uuid"
+)
+
+func parsedConfig() agents.ParsedConfig {
+	cfg := agtconfig.DefaultAgentConfig()
+	cfg.Provider.Options["deployment"] = "gpt-4o"
+	return agents.ParsedConfig{Config: cfg}
+}
+
+func TestConfigCache_ConcurrentMissesShareLoad(t *testing.T) {
+	cache := agents.NewConfigCache()
+	id := uuid.New()
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func() (agents.ParsedConfig, error) {
+		loads.Add(1)
+		<-release
+		return parsedConfig(), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			if _, err := cache.Get(id, load); err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+		})
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("load called %d times, want 1", n)
+	}
+
+	if _, err := cache.Get(id, load); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("load called %d times after cache fill, want 1", n)
+	}
+}
+
+func TestConfigCache_ReturnsCopy(t *testing.T) {
+	cache := agents.NewConfigCache()
+	id := uuid.New()
+	load := func() (agents.ParsedConfig, error) { return parsedConfig(), nil }
+
+	first, _ := cache.Get(id, load)
+	first.Config.Provider.Options["token"] = "per-call-token"
+	first.Config.SystemPrompt = "per-call prompt"
+
+	second, _ := cache.Get(id, load)
+	if _, ok := second.Config.Provider.Options["token"]; ok {
+		t.Error("per-call provider option leaked into the cached config")
+	}
+	if second.Config.SystemPrompt != "" {
+		t.Errorf("SystemPrompt = %q, per-call override leaked into the cached config", second.Config.SystemPrompt)
+	}
+	if second.Config.Provider.Options["deployment"] != "gpt-4o" {
+		t.Errorf("cached options = %v, want deployment preserved", second.Config.Provider.Options)
+	}
+}
+
+func TestConfigCache_ErrorNotCached(t *testing.T) {
+	cache := agents.NewConfigCache()
+	id := uuid.New()
+
+	if _, err := cache.Get(id, func() (agents.ParsedConfig, error) { return agents.ParsedConfig{}, agents.ErrNotFound }); !errors.Is(err, agents.ErrNotFound) {
+		t.Fatalf("Get() error = %v, want ErrNotFound", err)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d, failed loads should not be cached", cache.Len())
+	}
+}
+
+func TestConfigCache_InvalidateDuringLoad(t *testing.T) {
+	cache := agents.NewConfigCache()
+	id := uuid.New()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Get(id, func() (agents.ParsedConfig, error) {
+			close(started)
+			<-release
+			return parsedConfig(), nil
+		})
+	}()
+
+	<-started
+	cache.Invalidate(id)
+	close(release)
+	<-done
+
+	if cache.Len() != 0 {
+		t.Errorf("Len() = %d, a load overlapping Invalidate should not be cached", cache.Len())
+	}
+}
+
+// promptServer is an OpenAI-compatible chat endpoint that records the system
+// prompt of each request.
+func promptServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var prompts []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		for _, m := range body.Messages {
+			if m.Role == "system" {
+				prompts = append(prompts, m.Content)
+			}
+		}
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"m","choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), prompts...)
+	}
+}
+
+func promptAgent(baseURL, prompt string) agents.Agent {
+	return agents.Agent{
+		ID:        uuid.New(),
+		Name:      "cached",
+		Config:    promptConfig(baseURL, prompt),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func promptConfig(baseURL, prompt string) json.RawMessage {
+	cfg, _ := json.Marshal(map[string]any{
+		"name":          "cached",
+		"system_prompt": prompt,
+		"provider":      map[string]any{"name": "ollama", "base_url": baseURL},
+		"model":         map[string]any{"name": "m"},
+	})
+	return cfg
+}
+
+func TestChat_ReusesCachedConfig(t *testing.T) {
+	srv, _ := promptServer(t)
+	a := promptAgent(srv.URL, "first prompt")
+	sys, fdb := newAgentsSystem(t, nil, a)
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := sys.Chat(ctx, a.ID, "hello", nil, ""); err != nil {
+			t.Fatalf("Chat() error = %v", err)
+		}
+	}
+
+	if n := fdb.findCount(); n != 1 {
+		t.Errorf("agent lookups = %d, want 1 (later calls served from cache)", n)
+	}
+}
+
+func TestUpdate_InvalidatesCachedConfig(t *testing.T) {
+	srv, prompts := promptServer(t)
+	a := promptAgent(srv.URL, "first prompt")
+	sys, fdb := newAgentsSystem(t, nil, a)
+	ctx := context.Background()
+
+	if _, err := sys.Chat(ctx, a.ID, "hello", nil, ""); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+
+	cmd := agents.UpdateCommand{Name: a.Name, Config: promptConfig(srv.URL, "second prompt")}
+	if _, err := sys.Update(ctx, a.ID, cmd); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if _, err := sys.Chat(ctx, a.ID, "hello", nil, ""); err != nil {
+		t.Fatalf("Chat() after Update error = %v", err)
+	}
+
+	got := prompts()
+	if len(got) != 2 || got[0] != "first prompt" || got[1] != "second prompt" {
+		t.Errorf("system prompts = %v, want [first prompt second prompt]", got)
+	}
+	if n := fdb.findCount(); n != 2 {
+		t.Errorf("agent lookups = %d, want 2 (reload after Update)", n)
+	}
+}
+
+func TestDelete_InvalidatesCachedConfig(t *testing.T) {
+	srv, _ := promptServer(t)
+	a := promptAgent(srv.URL, "first prompt")
+	sys, _ := newAgentsSystem(t, nil, a)
+	ctx := context.Background()
+
+	if _, err := sys.Chat(ctx, a.ID, "hello", nil, ""); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+
+	if err := sys.Delete(ctx, a.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := sys.Chat(ctx, a.ID, "hello", nil, ""); !errors.Is(err, agents.ErrNotFound) {
+		t.Errorf("Chat() after Delete error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,219 @@
+package internal_agents_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/google/uuid"
+)
+
+func newDebugLogger(t *testing.T, cfg config.AgentDebugConfig, level slog.Level) (*agents.RequestLogger, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: level}))
+	return agents.NewRequestLogger(logger, cfg), &buf
+}
+
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestLogger_DisabledByDefault(t *testing.T) {
+	l, buf := newDebugLogger(t, config.AgentDebugConfig{}, slog.LevelDebug)
+
+	if l != nil {
+		t.Fatal("NewRequestLogger() with Enabled=false should return nil")
+	}
+
+	cid := l.Request(context.Background(), agents.DebugRequest{Prompt: "hello", Token: "secret"})
+	l.Response(context.Background(), cid, map[string]string{"content": "hi"}, nil)
+
+	if cid != "" {
+		t.Errorf("Request() correlation ID = %q, want empty", cid)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("disabled logger wrote output: %s", buf.String())
+	}
+}
+
+func TestRequestLogger_SkipsWhenDebugLevelDisabled(t *testing.T) {
+	l, buf := newDebugLogger(t, config.AgentDebugConfig{Enabled: true}, slog.LevelInfo)
+
+	cid := l.Request(context.Background(), agents.DebugRequest{Prompt: "hello"})
+	l.Response(context.Background(), cid, "ok", nil)
+
+	if buf.Len() != 0 {
+		t.Errorf("logger wrote output below its level: %s", buf.String())
+	}
+}
+
+func TestRequestLogger_RedactsTokens(t *testing.T) {
+	l, buf := newDebugLogger(t, config.AgentDebugConfig{Enabled: true, RedactKeys: []string{"Customer_ID"}}, slog.LevelDebug)
+
+	cid := l.Request(context.Background(), agents.DebugRequest{
+		Capability: "chat",
+		AgentID:    uuid.New(),
+		Prompt:     "classify this",
+		Token:      "runtime-token",
+		Options: map[string]any{
+			"temperature":   0.2,
+			"api_key":       "option-key",
+			"customer_id":   "c-123",
+			"nested":        map[string]any{"token": "nested-token", "keep": "visible"},
+			"system_prompt": "be brief",
+		},
+	})
+
+	out := buf.String()
+	for _, secret := range []string{"runtime-token", "option-key", "c-123", "nested-token"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output contains %q: %s", secret, out)
+		}
+	}
+
+	entries := logEntries(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("got %d log entries, want 1", len(entries))
+	}
+	entry := entries[0]
+
+	if entry["level"] != "DEBUG" || entry["msg"] != "provider request" {
+		t.Errorf("entry level/msg = %v/%v, want DEBUG/provider request", entry["level"], entry["msg"])
+	}
+	if entry["correlation_id"] != cid {
+		t.Errorf("correlation_id = %v, want %s", entry["correlation_id"], cid)
+	}
+	if entry["token"] != "[REDACTED]" {
+		t.Errorf("token = %v, want [REDACTED]", entry["token"])
+	}
+	if entry["prompt"] != "classify this" {
+		t.Errorf("prompt = %v, want classify this", entry["prompt"])
+	}
+
+	opts := entry["options"].(map[string]any)
+	if opts["api_key"] != "[REDACTED]" || opts["customer_id"] != "[REDACTED]" {
+		t.Errorf("options not redacted: %v", opts)
+	}
+	if opts["temperature"] != 0.2 || opts["system_prompt"] != "be brief" {
+		t.Errorf("non-sensitive options altered: %v", opts)
+	}
+	nested := opts["nested"].(map[string]any)
+	if nested["token"] != "[REDACTED]" || nested["keep"] != "visible" {
+		t.Errorf("nested options = %v, want token redacted and keep visible", nested)
+	}
+}
+
+func TestRequestLogger_SummarizesImages(t *testing.T) {
+	l, buf := newDebugLogger(t, config.AgentDebugConfig{Enabled: true}, slog.LevelDebug)
+
+	payload := strings.Repeat("QUJD", 256)
+	images := []string{
+		"data:image/png;base64," + payload,
+		"data:image/jpeg;base64," + payload,
+	}
+
+	cid := l.Request(context.Background(), agents.DebugRequest{
+		Capability: "vision",
+		Prompt:     "describe",
+		Images:     images,
+		Options:    map[string]any{"reference": "data:image/png;base64," + payload},
+	})
+	l.Response(context.Background(), cid, map[string]string{"echo": images[0]}, nil)
+
+	if strings.Contains(buf.String(), payload) {
+		t.Fatalf("log output contains image data: %s", buf.String())
+	}
+
+	entries := logEntries(t, buf)
+	if len(entries) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(entries))
+	}
+
+	summary := entries[0]["images"].(map[string]any)
+	if summary["count"] != float64(2) {
+		t.Errorf("images.count = %v, want 2", summary["count"])
+	}
+	if summary["total_bytes"] != float64(len(images[0])+len(images[1])) {
+		t.Errorf("images.total_bytes = %v, want %d", summary["total_bytes"], len(images[0])+len(images[1]))
+	}
+	types := summary["types"].([]any)
+	if len(types) != 2 || types[0] != "image/png" || types[1] != "image/jpeg" {
+		t.Errorf("images.types = %v, want [image/png image/jpeg]", types)
+	}
+
+	ref := entries[0]["options"].(map[string]any)["reference"].(string)
+	if !strings.HasPrefix(ref, "[image/png image,") {
+		t.Errorf("data URI option = %q, want summary", ref)
+	}
+}
+
+func TestRequestLogger_Response(t *testing.T) {
+	l, buf := newDebugLogger(t, config.AgentDebugConfig{Enabled: true, MaxResponseLength: 20}, slog.LevelDebug)
+
+	cid := l.Request(context.Background(), agents.DebugRequest{Prompt: "hi"})
+	l.Response(context.Background(), cid, map[string]string{"content": strings.Repeat("x", 100)}, nil)
+	l.Response(context.Background(), cid, nil, errors.New("provider down"))
+
+	entries := logEntries(t, buf)
+	if len(entries) != 3 {
+		t.Fatalf("got %d log entries, want 3", len(entries))
+	}
+
+	resp := entries[1]
+	if resp["correlation_id"] != cid {
+		t.Errorf("response correlation_id = %v, want %s", resp["correlation_id"], cid)
+	}
+	body := resp["response"].(string)
+	if !strings.HasPrefix(body, `{"content":"xxxxxxxx`) || !strings.Contains(body, "truncated") {
+		t.Errorf("response = %q, want truncated body", body)
+	}
+	if resp["response_bytes"] != float64(len(`{"content":""}`)+100) {
+		t.Errorf("response_bytes = %v", resp["response_bytes"])
+	}
+
+	if entries[2]["error"] != "provider down" {
+		t.Errorf("error entry = %v, want provider down", entries[2]["error"])
+	}
+}
+
+func TestRequestLogger_ToolsAndInputs(t *testing.T) {
+	l, buf := newDebugLogger(t, config.AgentDebugConfig{Enabled: true}, slog.LevelDebug)
+
+	l.Request(context.Background(), agents.DebugRequest{
+		Capability: "tools",
+		Prompt:     "weather?",
+		Tools:      []agent.Tool{{Name: "get_weather"}, {Name: "get_time"}},
+		Inputs:     []string{"a", "b", "c"},
+	})
+
+	entry := logEntries(t, buf)[0]
+	tools := entry["tools"].([]any)
+	if len(tools) != 2 || tools[0] != "get_weather" {
+		t.Errorf("tools = %v, want tool names", tools)
+	}
+	if entry["input_count"] != float64(3) {
+		t.Errorf("input_count = %v, want 3", entry["input_count"])
+	}
+}
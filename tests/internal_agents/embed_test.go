@@ -0,0 +1,168 @@
+package internal_agents_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// recordingEmbedder returns a one-element vector holding each input's length
+// and records the inputs it was asked to embed.
+type recordingEmbedder struct {
+	calls [][]string
+}
+
+func (e *recordingEmbedder) embed(ctx context.Context, inputs []string) ([][]float64, *response.TokenUsage, error) {
+	e.calls = append(e.calls, inputs)
+
+	vectors := make([][]float64, len(inputs))
+	for i, input := range inputs {
+		vectors[i] = []float64{float64(len(input))}
+	}
+	return vectors, &response.TokenUsage{PromptTokens: len(inputs), TotalTokens: len(inputs)}, nil
+}
+
+func TestEmbedBatch_PreservesOrder(t *testing.T) {
+	inputs := []string{"aaa", "a", "aaaaa", "aa"}
+	e := &recordingEmbedder{}
+
+	resp, fresh, err := agents.EmbedBatch(context.Background(), inputs, nil, e.embed)
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+
+	if len(resp.Embeddings) != len(inputs) {
+		t.Fatalf("len(Embeddings) = %d, want %d", len(resp.Embeddings), len(inputs))
+	}
+
+	for i, input := range inputs {
+		if resp.Embeddings[i][0] != float64(len(input)) {
+			t.Errorf("Embeddings[%d] = %v, want vector for %q", i, resp.Embeddings[i], input)
+		}
+	}
+
+	if len(fresh) != len(inputs) {
+		t.Errorf("len(fresh) = %d, want %d", len(fresh), len(inputs))
+	}
+
+	if resp.Reused != 0 {
+		t.Errorf("Reused = %d, want 0", resp.Reused)
+	}
+}
+
+func TestEmbedBatch_DedupByHash(t *testing.T) {
+	inputs := []string{"alpha", "beta", "alpha", "gamma"}
+	known := map[string][]float64{
+		agents.HashInput("gamma"): {42},
+	}
+	e := &recordingEmbedder{}
+
+	resp, fresh, err := agents.EmbedBatch(context.Background(), inputs, known, e.embed)
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+
+	if len(e.calls) != 1 {
+		t.Fatalf("embed calls = %d, want 1", len(e.calls))
+	}
+
+	if got := strings.Join(e.calls[0], ","); got != "alpha,beta" {
+		t.Errorf("embedded inputs = %q, want %q", got, "alpha,beta")
+	}
+
+	if resp.Embeddings[0][0] != resp.Embeddings[2][0] {
+		t.Errorf("duplicate inputs should share a vector, got %v and %v", resp.Embeddings[0], resp.Embeddings[2])
+	}
+
+	if resp.Embeddings[3][0] != 42 {
+		t.Errorf("Embeddings[3] = %v, want stored vector [42]", resp.Embeddings[3])
+	}
+
+	if resp.Reused != 1 {
+		t.Errorf("Reused = %d, want 1", resp.Reused)
+	}
+
+	if _, ok := fresh[agents.HashInput("gamma")]; ok {
+		t.Error("stored vectors should not be returned as fresh")
+	}
+
+	if len(fresh) != 2 {
+		t.Errorf("len(fresh) = %d, want 2", len(fresh))
+	}
+}
+
+func TestEmbedBatch_AllKnownSkipsEmbed(t *testing.T) {
+	known := map[string][]float64{agents.HashInput("x"): {1}}
+	e := &recordingEmbedder{}
+
+	resp, _, err := agents.EmbedBatch(context.Background(), []string{"x", "x"}, known, e.embed)
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+
+	if len(e.calls) != 0 {
+		t.Errorf("embed calls = %d, want 0", len(e.calls))
+	}
+
+	if resp.Reused != 2 {
+		t.Errorf("Reused = %d, want 2", resp.Reused)
+	}
+}
+
+func TestEmbedBatch_BatchSizeCap(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{"empty", 0, true},
+		{"at max", agents.MaxBatchSize, false},
+		{"over max", agents.MaxBatchSize + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs := make([]string, tt.size)
+			for i := range inputs {
+				inputs[i] = fmt.Sprintf("input-%d", i)
+			}
+			e := &recordingEmbedder{}
+
+			_, _, err := agents.EmbedBatch(context.Background(), inputs, nil, e.embed)
+
+			if tt.wantErr {
+				if !errors.Is(err, agents.ErrInvalidBatch) {
+					t.Fatalf("EmbedBatch() error = %v, want ErrInvalidBatch", err)
+				}
+				if status := agents.MapHTTPStatus(err); status != http.StatusBadRequest {
+					t.Errorf("MapHTTPStatus() = %d, want %d", status, http.StatusBadRequest)
+				}
+				if len(e.calls) != 0 {
+					t.Error("embed should not be called for an invalid batch")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("EmbedBatch() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestEmbedBatch_EmbedError(t *testing.T) {
+	failing := func(ctx context.Context, inputs []string) ([][]float64, *response.TokenUsage, error) {
+		return nil, nil, agents.ErrProviderUnavailable
+	}
+
+	_, _, err := agents.EmbedBatch(context.Background(), []string{"a"}, nil, failing)
+	if !errors.Is(err, agents.ErrProviderUnavailable) {
+		t.Errorf("EmbedBatch() error = %v, want ErrProviderUnavailable", err)
+	}
+}
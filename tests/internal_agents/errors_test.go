@@ -3,10 +3,13 @@ package internal_agents_test
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/go-agents/pkg/client"
 )
 
 func TestMapHTTPStatus(t *testing.T) {
@@ -87,6 +90,10 @@ func TestErrorValues(t *testing.T) {
 		{"ErrDuplicate", agents.ErrDuplicate, "agent name already exists"},
 		{"ErrInvalidConfig", agents.ErrInvalidConfig, "invalid agent config"},
 		{"ErrExecution", agents.ErrExecution, "agent execution failed"},
+		{"ErrProviderAuth", agents.ErrProviderAuth, "provider authentication failed"},
+		{"ErrProviderRateLimited", agents.ErrProviderRateLimited, "provider rate limit exceeded"},
+		{"ErrModelNotFound", agents.ErrModelNotFound, "model not found"},
+		{"ErrProviderUnavailable", agents.ErrProviderUnavailable, "provider unavailable"},
 	}
 
 	for _, tt := range tests {
@@ -97,3 +104,114 @@ func TestErrorValues(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyProviderError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantKind   error
+		wantStatus int
+	}{
+		{
+			"unauthorized",
+			&client.HTTPStatusError{StatusCode: 401, Status: "401 Unauthorized"},
+			agents.ErrProviderAuth,
+			http.StatusUnauthorized,
+		},
+		{
+			"forbidden",
+			&client.HTTPStatusError{StatusCode: 403, Status: "403 Forbidden"},
+			agents.ErrProviderAuth,
+			http.StatusUnauthorized,
+		},
+		{
+			"rate limited after retries",
+			fmt.Errorf("max retries (3) exceeded: %w", &client.HTTPStatusError{StatusCode: 429, Status: "429 Too Many Requests"}),
+			agents.ErrProviderRateLimited,
+			http.StatusTooManyRequests,
+		},
+		{
+			"model not found",
+			&client.HTTPStatusError{StatusCode: 404, Status: "404 Not Found"},
+			agents.ErrModelNotFound,
+			http.StatusNotFound,
+		},
+		{
+			"service unavailable",
+			&client.HTTPStatusError{StatusCode: 503, Status: "503 Service Unavailable"},
+			agents.ErrProviderUnavailable,
+			http.StatusServiceUnavailable,
+		},
+		{
+			"network error",
+			&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			agents.ErrProviderUnavailable,
+			http.StatusServiceUnavailable,
+		},
+		{
+			"streaming status error",
+			errors.New("streaming request failed with status 429: slow down"),
+			agents.ErrProviderRateLimited,
+			http.StatusTooManyRequests,
+		},
+		{
+			"unclassified error",
+			&client.HTTPStatusError{StatusCode: 400, Status: "400 Bad Request"},
+			agents.ErrExecution,
+			http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agents.ClassifyProviderError(tt.err)
+
+			if !errors.Is(got, tt.wantKind) {
+				t.Errorf("ClassifyProviderError() = %v, want %v", got, tt.wantKind)
+			}
+
+			if !errors.Is(got, tt.err) {
+				t.Error("ClassifyProviderError() should wrap the original error")
+			}
+
+			if status := agents.MapHTTPStatus(got); status != tt.wantStatus {
+				t.Errorf("MapHTTPStatus() = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestClassifyProviderError_Nil(t *testing.T) {
+	if err := agents.ClassifyProviderError(nil); err != nil {
+		t.Errorf("ClassifyProviderError(nil) = %v, want nil", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"seconds", `{"error":{"message":"Please retry after 20 seconds."}}`, 20 * time.Second, true},
+		{"fractional", "Rate limit reached. Please try again in 1.5s.", 1500 * time.Millisecond, true},
+		{"milliseconds", "Please try again in 250ms.", 250 * time.Millisecond, true},
+		{"absent", "rate limit exceeded", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := agents.ClassifyProviderError(&client.HTTPStatusError{
+				StatusCode: 429,
+				Status:     "429 Too Many Requests",
+				Body:       []byte(tt.body),
+			})
+
+			got, ok := agents.RetryAfter(err)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("RetryAfter() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
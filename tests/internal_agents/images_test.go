@@ -0,0 +1,176 @@
+package internal_agents_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/google/uuid"
+)
+
+type fakeImageSource struct {
+	images map[uuid.UUID][]byte
+}
+
+func (f *fakeImageSource) Data(ctx context.Context, id uuid.UUID) ([]byte, string, error) {
+	data, ok := f.images[id]
+	if !ok {
+		return nil, "", errors.New("image not found")
+	}
+	return data, "image/png", nil
+}
+
+func TestResolveImages_ImageID(t *testing.T) {
+	id := uuid.New()
+	src := &fakeImageSource{images: map[uuid.UUID][]byte{id: createTestPNG()}}
+	fetcher := agents.NewImageFetcher(time.Second, 1<<20)
+
+	got, err := agents.ResolveImages(context.Background(), src, fetcher, []uuid.UUID{id}, nil)
+	if err != nil {
+		t.Fatalf("ResolveImages() error = %v", err)
+	}
+
+	want := "data:image/png;base64," + base64.StdEncoding.EncodeToString(createTestPNG())
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("ResolveImages() = %q, want [%q]", got, want)
+	}
+}
+
+func TestResolveImages_UnknownImageID(t *testing.T) {
+	src := &fakeImageSource{}
+	fetcher := agents.NewImageFetcher(time.Second, 1<<20)
+
+	_, err := agents.ResolveImages(context.Background(), src, fetcher, []uuid.UUID{uuid.New()}, nil)
+	if !errors.Is(err, agents.ErrImageUnavailable) {
+		t.Errorf("ResolveImages() error = %v, want ErrImageUnavailable", err)
+	}
+}
+
+func TestImageFetcher_RejectsDisallowedURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(createTestPNG())
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"loopback test server", server.URL + "/image.png"},
+		{"localhost", "http://localhost/image.png"},
+		{"private range", "http://10.0.0.5/image.png"},
+		{"cloud metadata", "http://169.254.169.254/latest/meta-data"},
+		{"ipv6 loopback", "http://[::1]/image.png"},
+		{"ipv4-mapped loopback", "http://[::ffff:127.0.0.1]/image.png"},
+		{"file scheme", "file:///etc/passwd"},
+		{"ftp scheme", "ftp://example.com/image.png"},
+	}
+
+	fetcher := agents.NewImageFetcher(time.Second, 1<<20)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fetcher.Fetch(context.Background(), tt.url)
+			if !errors.Is(err, agents.ErrImageURLNotAllowed) {
+				t.Errorf("Fetch(%q) error = %v, want ErrImageURLNotAllowed", tt.url, err)
+			}
+		})
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:4700::1111", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"100.64.0.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fd00::1", false},
+		{"fe80::1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := agents.PublicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("PublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisionRequest_Validate(t *testing.T) {
+	urls := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = "https://example.com/image.png"
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		req       agents.VisionRequest
+		wantField string
+	}{
+		{"valid id", agents.VisionRequest{Prompt: "describe", ImageIDs: []uuid.UUID{uuid.New()}}, ""},
+		{"valid url", agents.VisionRequest{Prompt: "describe", ImageURLs: urls(1)}, ""},
+		{"missing prompt", agents.VisionRequest{ImageURLs: urls(1)}, "prompt"},
+		{"no images", agents.VisionRequest{Prompt: "describe"}, "image_ids"},
+		{"too many images", agents.VisionRequest{Prompt: "describe", ImageURLs: urls(agents.MaxVisionImages + 1)}, "image_ids"},
+		{"invalid option", agents.VisionRequest{Prompt: "describe", ImageURLs: urls(1), Options: map[string]any{"temperature": 5.0}}, "options.temperature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *handlers.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want ValidationError", err)
+			}
+			if _, ok := verr.Fields[tt.wantField]; !ok {
+				t.Errorf("Validate() fields = %v, want %q", verr.Fields, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestHandler_VisionJSON_DisallowedURL(t *testing.T) {
+	sys, _ := newCloneSystem(t)
+
+	body := `{"prompt":"describe","image_urls":["http://127.0.0.1:8080/admin"]}`
+	req := httptest.NewRequest(http.MethodPost, "/agents/x/vision/json", strings.NewReader(body))
+	req.SetPathValue("id", uuid.NewString())
+	rec := httptest.NewRecorder()
+
+	sys.Handler().VisionJSON(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "image_url_not_allowed") {
+		t.Errorf("body = %s, want image_url_not_allowed code", rec.Body.String())
+	}
+}
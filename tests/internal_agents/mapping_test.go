@@ -122,3 +122,30 @@ func TestFilters_Apply(t *testing.T) {
 func strPtr(s string) *string {
 	return &s
 }
+
+func TestFiltersFromQuery_Provider(t *testing.T) {
+	filters := agents.FiltersFromQuery(url.Values{"provider": {"azure"}})
+	if filters.Provider == nil || *filters.Provider != "azure" {
+		t.Errorf("FiltersFromQuery() Provider = %v, want azure", filters.Provider)
+	}
+
+	filters = agents.FiltersFromQuery(url.Values{})
+	if filters.Provider != nil {
+		t.Errorf("FiltersFromQuery() Provider = %q, want nil", *filters.Provider)
+	}
+}
+
+func TestFilters_Apply_Provider(t *testing.T) {
+	b := query.NewBuilder(newTestProjection(), query.SortField{Field: "Name"})
+
+	agents.Filters{Provider: strPtr("azure")}.Apply(b)
+
+	sql, args := b.BuildCount()
+
+	if !strings.Contains(sql, "#>> '{provider,name}' = $1") {
+		t.Errorf("Apply() missing provider condition, got %q", sql)
+	}
+	if len(args) != 1 || args[0] != "azure" {
+		t.Errorf("Apply() args = %v, want [azure]", args)
+	}
+}
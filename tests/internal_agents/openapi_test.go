@@ -0,0 +1,55 @@
+package internal_agents_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
+)
+
+func decodeExample(t *testing.T, body *openapi.RequestBody, dest any) {
+	t.Helper()
+
+	example := body.Content["application/json"].Example
+	if example == nil {
+		t.Fatal("request body has no example")
+	}
+
+	data, err := json.Marshal(example)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dest); err != nil {
+		t.Fatalf("example does not decode as %T: %v", dest, err)
+	}
+}
+
+func TestSpec_Create_Example(t *testing.T) {
+	var cmd agents.CreateCommand
+	decodeExample(t, agents.Spec.Create.RequestBody, &cmd)
+
+	if err := cmd.Validate(); err != nil {
+		t.Errorf("example CreateAgentCommand is invalid: %v", err)
+	}
+}
+
+func TestSpec_Chat_Example(t *testing.T) {
+	for name, op := range map[string]*openapi.Operation{
+		"Chat":       agents.Spec.Chat,
+		"ChatStream": agents.Spec.ChatStream,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var req agents.ChatRequest
+			decodeExample(t, op.RequestBody, &req)
+
+			if req.Prompt == "" {
+				t.Error("example ChatRequest has no prompt")
+			}
+		})
+	}
+}
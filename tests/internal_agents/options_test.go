@@ -0,0 +1,147 @@
+package internal_agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/google/uuid"
+)
+
+// chatSpy records the options passed to Chat; other System methods are not used.
+type chatSpy struct {
+	agents.System
+	called bool
+	opts   map[string]any
+}
+
+func (s *chatSpy) Chat(ctx context.Context, id uuid.UUID, prompt string, opts map[string]any, token string) (*response.ChatResponse, error) {
+	s.called = true
+	s.opts = opts
+	return &response.ChatResponse{}, nil
+}
+
+func TestParseChatOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    string
+		wantFields []string
+	}{
+		{"none", `{}`, nil},
+		{"valid", `{"temperature": 0.7, "top_p": 1, "max_tokens": 512}`, nil},
+		{"bounds", `{"temperature": 2, "top_p": 0}`, nil},
+		{"temperature too high", `{"temperature": 2.5}`, []string{"options.temperature"}},
+		{"temperature not a number", `{"temperature": "hot"}`, []string{"options.temperature"}},
+		{"top_p negative", `{"top_p": -0.1}`, []string{"options.top_p"}},
+		{"max_tokens zero", `{"max_tokens": 0}`, []string{"options.max_tokens"}},
+		{"max_tokens fractional", `{"max_tokens": 10.5}`, []string{"options.max_tokens"}},
+		{"all invalid", `{"temperature": -1, "top_p": 2, "max_tokens": -5}`, []string{"options.temperature", "options.top_p", "options.max_tokens"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options map[string]any
+			if err := json.Unmarshal([]byte(tt.options), &options); err != nil {
+				t.Fatalf("unmarshal options: %v", err)
+			}
+
+			_, err := agents.ParseChatOptions(options)
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Errorf("ParseChatOptions() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *handlers.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("ParseChatOptions() error = %v, want *handlers.ValidationError", err)
+			}
+
+			fields := verr.Fields
+			for _, f := range tt.wantFields {
+				if _, ok := fields[f]; !ok {
+					t.Errorf("missing field error %q in %v", f, fields)
+				}
+			}
+			if len(fields) != len(tt.wantFields) {
+				t.Errorf("field errors = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestParseChatOptions_Typed(t *testing.T) {
+	opts, err := agents.ParseChatOptions(map[string]any{"temperature": 0.2, "max_tokens": float64(256)})
+	if err != nil {
+		t.Fatalf("ParseChatOptions() error = %v", err)
+	}
+
+	if opts.Temperature == nil || *opts.Temperature != 0.2 {
+		t.Errorf("Temperature = %v, want 0.2", opts.Temperature)
+	}
+	if opts.TopP != nil {
+		t.Errorf("TopP = %v, want nil", *opts.TopP)
+	}
+	if opts.MaxTokens == nil || *opts.MaxTokens != 256 {
+		t.Errorf("MaxTokens = %v, want 256", opts.MaxTokens)
+	}
+}
+
+func TestHandler_Chat_Options(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"valid options", `{"prompt":"hi","options":{"temperature":0.5,"top_p":0.9,"max_tokens":100}}`, http.StatusOK},
+		{"out of range temperature", `{"prompt":"hi","options":{"temperature":3}}`, http.StatusBadRequest},
+		{"unknown option passes through", `{"prompt":"hi","options":{"seed":42,"response_format":{"type":"json_object"}}}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &chatSpy{}
+			handler := agents.NewHandler(spy, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+
+			id := uuid.NewString()
+			req := httptest.NewRequest(http.MethodPost, "/agents/"+id+"/chat", strings.NewReader(tt.body))
+			req.SetPathValue("id", id)
+			rec := httptest.NewRecorder()
+
+			handler.Chat(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				if spy.called {
+					t.Error("Chat() called despite invalid options")
+				}
+				if !strings.Contains(rec.Body.String(), "options.temperature") {
+					t.Errorf("body = %s, want options.temperature field error", rec.Body.String())
+				}
+				return
+			}
+
+			var want map[string]any
+			json.Unmarshal([]byte(tt.body), &want)
+			got, _ := json.Marshal(spy.opts)
+			wantOpts, _ := json.Marshal(want["options"])
+			if string(got) != string(wantOpts) {
+				t.Errorf("options = %s, want %s passed through untouched", got, wantOpts)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package internal_agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/internal/providers"
+	agtconfig "github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/google/uuid"
+)
+
+// fakeProviders is a providers.System holding a single provider record.
+// Only Resolve and RotateCredentials are implemented.
+type fakeProviders struct {
+	providers.System
+	mu       sync.Mutex
+	provider providers.Provider
+}
+
+func (f *fakeProviders) Resolve(ctx context.Context, id uuid.UUID) (*agtconfig.ProviderConfig, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id != f.provider.ID {
+		return nil, providers.ErrNotFound
+	}
+	return f.provider.ProviderConfig()
+}
+
+func (f *fakeProviders) RotateCredentials(ctx context.Context, id uuid.UUID, cmd providers.CredentialsCommand) error {
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if id != f.provider.ID {
+		return providers.ErrNotFound
+	}
+	f.provider.Credentials = cmd.Credentials
+	return nil
+}
+
+// authServer is an OpenAI-compatible chat endpoint that records the
+// Authorization header of each request.
+func authServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var seen []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"m","choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func referencingAgent(providerID uuid.UUID) agents.Agent {
+	return agents.Agent{
+		ID:         uuid.New(),
+		Name:       "referencing",
+		ProviderID: &providerID,
+		Config: json.RawMessage(`{
+			"name": "referencing",
+			"provider": {"name": "ollama", "base_url": "http://unused.invalid", "options": {"token": "stale-agent-token"}},
+			"model": {"name": "m"}
+		}`),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestChat_UsesRotatedProviderCredentials(t *testing.T) {
+	srv, seen := authServer(t)
+
+	provs := &fakeProviders{provider: providers.Provider{
+		ID:          uuid.New(),
+		Name:        "shared",
+		Config:      json.RawMessage(`{"name": "ollama", "base_url": "` + srv.URL + `", "options": {"auth_type": "bearer"}}`),
+		Credentials: map[string]string{"token": "old-secret"},
+	}}
+
+	first, second := referencingAgent(provs.provider.ID), referencingAgent(provs.provider.ID)
+	second.Name = "second"
+	sys, _ := newAgentsSystem(t, provs, first, second)
+	ctx := context.Background()
+
+	if _, err := sys.Chat(ctx, first.ID, "hello", nil, ""); err != nil {
+		t.Fatalf("Chat() before rotation error = %v", err)
+	}
+
+	rotate := providers.CredentialsCommand{Credentials: map[string]string{"token": "new-secret"}}
+	if err := provs.RotateCredentials(ctx, provs.provider.ID, rotate); err != nil {
+		t.Fatalf("RotateCredentials() error = %v", err)
+	}
+
+	for _, a := range []agents.Agent{first, second} {
+		if _, err := sys.Chat(ctx, a.ID, "hello", nil, ""); err != nil {
+			t.Fatalf("Chat(%s) after rotation error = %v", a.Name, err)
+		}
+	}
+
+	want := []string{"Bearer old-secret", "Bearer new-secret", "Bearer new-secret"}
+	got := seen()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Authorization headers = %v, want %v", got, want)
+	}
+}
+
+func TestChat_RequestTokenOverridesProviderCredentials(t *testing.T) {
+	srv, seen := authServer(t)
+
+	provs := &fakeProviders{provider: providers.Provider{
+		ID:          uuid.New(),
+		Config:      json.RawMessage(`{"name": "ollama", "base_url": "` + srv.URL + `", "options": {"auth_type": "bearer"}}`),
+		Credentials: map[string]string{"token": "stored-secret"},
+	}}
+
+	a := referencingAgent(provs.provider.ID)
+	sys, _ := newAgentsSystem(t, provs, a)
+
+	if _, err := sys.Chat(context.Background(), a.ID, "hello", nil, "request-token"); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+
+	if got := seen(); len(got) != 1 || got[0] != "Bearer request-token" {
+		t.Errorf("Authorization headers = %v, want the request token", got)
+	}
+}
+
+func TestChat_MissingProviderIsInvalidConfig(t *testing.T) {
+	provs := &fakeProviders{provider: providers.Provider{ID: uuid.New()}}
+
+	a := referencingAgent(uuid.New())
+	sys, _ := newAgentsSystem(t, provs, a)
+
+	_, err := sys.Chat(context.Background(), a.ID, "hello", nil, "")
+	if agents.MapHTTPStatus(err) != http.StatusBadRequest {
+		t.Errorf("Chat() error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestFind_ReferencingAgentOmitsProviderCredentials(t *testing.T) {
+	provs := &fakeProviders{provider: providers.Provider{
+		ID:          uuid.New(),
+		Config:      json.RawMessage(`{"name": "ollama", "base_url": "http://localhost:11434"}`),
+		Credentials: map[string]string{"token": "never-serialized"},
+	}}
+
+	a := referencingAgent(provs.provider.ID)
+	sys, _ := newAgentsSystem(t, provs, a)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/agents/"+a.ID.String(), nil)
+	req.SetPathValue("id", a.ID.String())
+	rec := httptest.NewRecorder()
+	sys.Handler().Find(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "never-serialized") {
+		t.Errorf("response contains provider credential: %s", rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"provider_id":"`+provs.provider.ID.String()+`"`) {
+		t.Errorf("response missing provider_id: %s", rec.Body.String())
+	}
+}
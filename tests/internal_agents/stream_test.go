@@ -0,0 +1,236 @@
+package internal_agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/google/uuid"
+)
+
+const toolsResponseJSON = `{
+	"model": "test-model",
+	"choices": [{
+		"index": 0,
+		"message": {
+			"role": "assistant",
+			"content": "Checking the weather.",
+			"tool_calls": [{
+				"id": "call_1",
+				"type": "function",
+				"function": {"name": "get_weather", "arguments": "{\"city\":\"Paris\"}"}
+			}]
+		},
+		"finish_reason": "tool_calls"
+	}],
+	"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+}`
+
+func parseToolsResponse(t *testing.T) *response.ToolsResponse {
+	t.Helper()
+
+	resp, err := response.ParseTools([]byte(toolsResponseJSON))
+	if err != nil {
+		t.Fatalf("ParseTools() error = %v", err)
+	}
+	return resp
+}
+
+// streamSystem is a fake System whose ToolsStream and ChatStream replay a
+// fixed event or chunk sequence.
+type streamSystem struct {
+	agents.System
+	events []agents.ToolsEvent
+	chunks []*response.StreamingChunk
+	ctx    context.Context
+}
+
+func (s *streamSystem) ChatStream(ctx context.Context, id uuid.UUID, prompt string, opts map[string]any, token string) (<-chan *response.StreamingChunk, error) {
+	ch := make(chan *response.StreamingChunk, len(s.chunks))
+	for _, c := range s.chunks {
+		ch <- c
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (s *streamSystem) ToolsStream(ctx context.Context, id uuid.UUID, prompt string, tools []agent.Tool, opts map[string]any, token string) (<-chan agents.ToolsEvent, error) {
+	s.ctx = ctx
+
+	ch := make(chan agents.ToolsEvent)
+	go func() {
+		defer close(ch)
+		for _, e := range s.events {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func TestToolsEvents(t *testing.T) {
+	events := agents.ToolsEvents(parseToolsResponse(t))
+
+	wantTypes := []agents.ToolsEventType{
+		agents.ToolsEventToolCall,
+		agents.ToolsEventContent,
+		agents.ToolsEventDone,
+	}
+
+	if len(events) != len(wantTypes) {
+		t.Fatalf("len(events) = %d, want %d", len(events), len(wantTypes))
+	}
+
+	for i, want := range wantTypes {
+		if events[i].Type != want {
+			t.Errorf("events[%d].Type = %q, want %q", i, events[i].Type, want)
+		}
+	}
+
+	if events[0].ToolCall == nil || events[0].ToolCall.Function.Name != "get_weather" {
+		t.Errorf("tool call event = %+v, want get_weather call", events[0].ToolCall)
+	}
+
+	if events[1].Content != "Checking the weather." {
+		t.Errorf("content = %q, want %q", events[1].Content, "Checking the weather.")
+	}
+
+	done := events[2]
+	if done.FinishReason != "tool_calls" {
+		t.Errorf("FinishReason = %q, want %q", done.FinishReason, "tool_calls")
+	}
+	if done.Usage == nil || done.Usage.TotalTokens != 15 {
+		t.Errorf("Usage = %+v, want total 15", done.Usage)
+	}
+}
+
+func TestHandler_ToolsStream(t *testing.T) {
+	events := append([]agents.ToolsEvent{{Type: agents.ToolsEventStart}}, agents.ToolsEvents(parseToolsResponse(t))...)
+	sys := &streamSystem{events: events}
+	handler := agents.NewHandler(sys, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+
+	body := `{"prompt":"weather in Paris?","tools":[{"name":"get_weather","description":"Get weather"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/agents/"+uuid.NewString()+"/tools/stream", strings.NewReader(body))
+	req.SetPathValue("id", uuid.NewString())
+	rec := httptest.NewRecorder()
+
+	handler.ToolsStream(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	var names []string
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			names = append(names, name)
+		}
+	}
+
+	want := []string{"start", "tool_call", "content", "done"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", names, want)
+	}
+
+	if !strings.HasSuffix(rec.Body.String(), "data: [DONE]\n\n") {
+		t.Errorf("stream should end with [DONE], got %q", rec.Body.String())
+	}
+}
+
+func TestHandler_ToolsStream_ErrorEndsStream(t *testing.T) {
+	sys := &streamSystem{events: []agents.ToolsEvent{
+		{Type: agents.ToolsEventError, Error: "provider unavailable"},
+		{Type: agents.ToolsEventDone},
+	}}
+	handler := agents.NewHandler(sys, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+
+	req := httptest.NewRequest(http.MethodPost, "/agents/x/tools/stream", strings.NewReader(`{"prompt":"p","tools":[]}`))
+	req.SetPathValue("id", uuid.NewString())
+	rec := httptest.NewRecorder()
+
+	handler.ToolsStream(rec, req)
+
+	out := rec.Body.String()
+	if !strings.Contains(out, "event: error") {
+		t.Errorf("stream missing error event, got %q", out)
+	}
+
+	if strings.Contains(out, "event: done") {
+		t.Errorf("stream should end after error event, got %q", out)
+	}
+
+	if !strings.HasSuffix(out, "event: error\n"+`data: {"type":"error","error":"provider unavailable"}`+"\n\ndata: [DONE]\n\n") {
+		t.Errorf("error event should be followed by [DONE], got %q", out)
+	}
+}
+
+func TestHandler_ToolsStream_DisconnectCancelsUpstream(t *testing.T) {
+	sys := &streamSystem{}
+	handler := agents.NewHandler(sys, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/agents/x/tools/stream", strings.NewReader(`{"prompt":"p","tools":[]}`)).WithContext(ctx)
+	req.SetPathValue("id", uuid.NewString())
+
+	handler.ToolsStream(httptest.NewRecorder(), req)
+	cancel()
+
+	if sys.ctx == nil || sys.ctx.Err() == nil {
+		t.Error("upstream context should be cancelled when the client disconnects")
+	}
+}
+
+func TestHandler_ChatStream_ErrorMidStream(t *testing.T) {
+	var content response.StreamingChunk
+	if err := json.Unmarshal([]byte(`{"model":"m","choices":[{"index":0,"delta":{"content":"partial"}}]}`), &content); err != nil {
+		t.Fatalf("unmarshal chunk: %v", err)
+	}
+
+	sys := &streamSystem{chunks: []*response.StreamingChunk{
+		&content,
+		{Error: errors.New("connection reset by provider")},
+		&content,
+	}}
+	handler := agents.NewHandler(sys, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+
+	req := httptest.NewRequest(http.MethodPost, "/agents/x/chat/stream", strings.NewReader(`{"prompt":"p"}`))
+	req.SetPathValue("id", uuid.NewString())
+	rec := httptest.NewRecorder()
+
+	handler.ChatStream(rec, req)
+
+	frames := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n\n"), "\n\n")
+	if len(frames) != 3 {
+		t.Fatalf("frames = %q, want content, error, and [DONE]", frames)
+	}
+
+	if !strings.Contains(frames[0], "partial") {
+		t.Errorf("frames[0] = %q, want content chunk", frames[0])
+	}
+
+	wantErr := "event: error\n" + `data: {"error":"connection reset by provider"}`
+	if frames[1] != wantErr {
+		t.Errorf("frames[1] = %q, want %q", frames[1], wantErr)
+	}
+
+	if frames[2] != "data: [DONE]" {
+		t.Errorf("frames[2] = %q, want terminating [DONE]", frames[2])
+	}
+}
@@ -0,0 +1,131 @@
+package internal_agents_test
+
+import (
+	"errors"
+	"math"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+)
+
+func TestSummarizeUsage(t *testing.T) {
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	prices := config.PriceTable{
+		"gpt-4o": {Prompt: 2.00, Completion: 4.00},
+	}
+
+	rows := []agents.UsageRow{
+		{Day: day2, Model: "gpt-4o", Calls: 1, PromptTokens: 500_000, CompletionTokens: 0, TotalTokens: 500_000},
+		{Day: day1, Model: "gpt-4o", Calls: 2, PromptTokens: 1_000_000, CompletionTokens: 250_000, TotalTokens: 1_250_000},
+		{Day: day1, Model: "llama3", Calls: 3, PromptTokens: 300, CompletionTokens: 200, TotalTokens: 500},
+	}
+
+	summary := agents.SummarizeUsage(rows, prices)
+
+	if summary.Calls != 6 {
+		t.Errorf("Calls = %d, want 6", summary.Calls)
+	}
+	if summary.PromptTokens != 1_500_300 {
+		t.Errorf("PromptTokens = %d, want 1500300", summary.PromptTokens)
+	}
+	if summary.CompletionTokens != 250_200 {
+		t.Errorf("CompletionTokens = %d, want 250200", summary.CompletionTokens)
+	}
+	if summary.TotalTokens != 1_750_500 {
+		t.Errorf("TotalTokens = %d, want 1750500", summary.TotalTokens)
+	}
+	if math.Abs(summary.EstimatedCost-4.00) > 1e-9 {
+		t.Errorf("EstimatedCost = %v, want 4.00", summary.EstimatedCost)
+	}
+
+	if len(summary.Days) != 2 {
+		t.Fatalf("Days count = %d, want 2", len(summary.Days))
+	}
+	if summary.Days[0].Date != "2025-01-01" || summary.Days[1].Date != "2025-01-02" {
+		t.Errorf("Days = [%s %s], want ordered by date", summary.Days[0].Date, summary.Days[1].Date)
+	}
+	if summary.Days[0].Calls != 5 {
+		t.Errorf("Days[0].Calls = %d, want 5", summary.Days[0].Calls)
+	}
+	if math.Abs(summary.Days[0].EstimatedCost-3.00) > 1e-9 {
+		t.Errorf("Days[0].EstimatedCost = %v, want 3.00", summary.Days[0].EstimatedCost)
+	}
+
+	if len(summary.UnpricedModels) != 1 || summary.UnpricedModels[0] != "llama3" {
+		t.Errorf("UnpricedModels = %v, want [llama3]", summary.UnpricedModels)
+	}
+}
+
+func TestSummarizeUsage_Empty(t *testing.T) {
+	summary := agents.SummarizeUsage(nil, nil)
+
+	if summary.Calls != 0 || summary.EstimatedCost != 0 {
+		t.Errorf("summary = %+v, want zero totals", summary.UsageTotals)
+	}
+	if summary.Days == nil {
+		t.Error("Days should be an empty slice, not nil")
+	}
+}
+
+func TestUsageDay_CSV(t *testing.T) {
+	var sb strings.Builder
+	enc := handlers.NewCSVEncoder[agents.UsageDay](&sb)
+	enc.WriteHeader()
+	enc.Encode(agents.UsageDay{Date: "2026-03-01", UsageTotals: agents.UsageTotals{Calls: 2, PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15, EstimatedCost: 0.5}})
+	enc.Flush()
+
+	want := "date,calls,prompt_tokens,completion_tokens,total_tokens,estimated_cost\n2026-03-01,2,10,5,15,0.5\n"
+	if sb.String() != want {
+		t.Errorf("CSV = %q, want %q", sb.String(), want)
+	}
+}
+
+func TestUsageRangeFromQuery(t *testing.T) {
+	t.Run("explicit dates", func(t *testing.T) {
+		from, to, err := agents.UsageRangeFromQuery(url.Values{
+			"from": {"2025-01-01"},
+			"to":   {"2025-02-01T00:00:00Z"},
+		})
+		if err != nil {
+			t.Fatalf("UsageRangeFromQuery() error = %v", err)
+		}
+		if !from.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("from = %v", from)
+		}
+		if !to.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("to = %v", to)
+		}
+	})
+
+	t.Run("defaults to trailing window", func(t *testing.T) {
+		from, to, err := agents.UsageRangeFromQuery(url.Values{})
+		if err != nil {
+			t.Fatalf("UsageRangeFromQuery() error = %v", err)
+		}
+		if !from.Before(to) {
+			t.Errorf("from %v should be before to %v", from, to)
+		}
+	})
+
+	invalid := []url.Values{
+		{"from": {"yesterday"}},
+		{"to": {"not-a-date"}},
+		{"from": {"2025-02-01"}, "to": {"2025-01-01"}},
+	}
+
+	for _, values := range invalid {
+		t.Run("invalid "+values.Encode(), func(t *testing.T) {
+			_, _, err := agents.UsageRangeFromQuery(values)
+			if !errors.Is(err, agents.ErrInvalidUsageRange) {
+				t.Errorf("error = %v, want ErrInvalidUsageRange", err)
+			}
+		})
+	}
+}
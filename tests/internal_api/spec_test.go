@@ -0,0 +1,52 @@
+package internal_api_test
+
+import (
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/api"
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
+)
+
+func TestNewSpec_ConfiguredServers(t *testing.T) {
+	cfg := &config.Config{
+		Domain:  "http://localhost:8080",
+		Version: "1.2.3",
+		API: config.APIConfig{
+			OpenAPI: openapi.Config{
+				Title: "Agent Lab API",
+				Servers: []openapi.Server{
+					{URL: "http://localhost:8080", Description: "Local"},
+					{URL: "https://lab.example.com/agent-lab", Description: "Production"},
+				},
+			},
+		},
+	}
+
+	spec := api.NewSpec(cfg)
+
+	if spec.Info.Version != "1.2.3" {
+		t.Errorf("Info.Version = %q, want %q", spec.Info.Version, "1.2.3")
+	}
+
+	if len(spec.Servers) != 2 {
+		t.Fatalf("len(Servers) = %d, want 2", len(spec.Servers))
+	}
+
+	for i, want := range cfg.API.OpenAPI.Servers {
+		got := spec.Servers[i]
+		if got.URL != want.URL || got.Description != want.Description {
+			t.Errorf("Servers[%d] = %+v, want %+v", i, *got, want)
+		}
+	}
+}
+
+func TestNewSpec_DefaultsToDomain(t *testing.T) {
+	cfg := &config.Config{Domain: "http://localhost:8080"}
+
+	spec := api.NewSpec(cfg)
+
+	if len(spec.Servers) != 1 || spec.Servers[0].URL != cfg.Domain {
+		t.Errorf("Servers = %v, want the service domain only", spec.Servers)
+	}
+}
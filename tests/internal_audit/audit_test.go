@@ -0,0 +1,146 @@
+package internal_audit_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/agents"
+	"github.com/JaimeStill/agent-lab/internal/audit"
+	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/pkg/events"
+	"github.com/JaimeStill/agent-lab/pkg/middleware"
+)
+
+// entryLog collects entries passed to a RecordFunc.
+type entryLog struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+}
+
+func (l *entryLog) record(ctx context.Context, e audit.Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, e)
+	return nil
+}
+
+func discardLogf(string, ...any) {}
+
+func waitBus(t *testing.T, bus *events.Bus) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := bus.Wait(ctx); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+}
+
+func TestEntryFromEvent(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx := middleware.WithActor(context.Background(), "alice")
+
+	entry, ok := audit.EntryFromEvent(ctx, events.Event{
+		Type:    agents.EventCreated,
+		Subject: "agent-1",
+		Time:    ts,
+	})
+	if !ok {
+		t.Fatal("EntryFromEvent() ok = false, want true")
+	}
+
+	if entry.Actor != "alice" {
+		t.Errorf("Actor = %q, want %q", entry.Actor, "alice")
+	}
+	if entry.Action != "created" {
+		t.Errorf("Action = %q, want %q", entry.Action, "created")
+	}
+	if entry.ResourceType != "agent" {
+		t.Errorf("ResourceType = %q, want %q", entry.ResourceType, "agent")
+	}
+	if entry.ResourceID != "agent-1" {
+		t.Errorf("ResourceID = %q, want %q", entry.ResourceID, "agent-1")
+	}
+	if !entry.CreatedAt.Equal(ts) {
+		t.Errorf("CreatedAt = %v, want %v", entry.CreatedAt, ts)
+	}
+}
+
+func TestEntryFromEvent_AnonymousActor(t *testing.T) {
+	entry, ok := audit.EntryFromEvent(context.Background(), events.Event{Type: documents.EventDeleted})
+	if !ok {
+		t.Fatal("EntryFromEvent() ok = false, want true")
+	}
+	if entry.Actor != audit.AnonymousActor {
+		t.Errorf("Actor = %q, want %q", entry.Actor, audit.AnonymousActor)
+	}
+}
+
+func TestEntryFromEvent_InvalidType(t *testing.T) {
+	for _, eventType := range []string{"", "created", ".created", "agent."} {
+		if _, ok := audit.EntryFromEvent(context.Background(), events.Event{Type: eventType}); ok {
+			t.Errorf("EntryFromEvent(%q) ok = true, want false", eventType)
+		}
+	}
+}
+
+func TestRecorder_CreateUpdateDelete(t *testing.T) {
+	bus := events.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	log := &entryLog{}
+
+	recorder := audit.Recorder(log.record, discardLogf)
+	for _, eventType := range []string{
+		profiles.EventCreated, profiles.EventUpdated, profiles.EventDeleted,
+	} {
+		bus.Subscribe(eventType, recorder)
+	}
+
+	ctx := middleware.WithActor(context.Background(), "bob")
+	bus.Publish(ctx, events.Event{Type: profiles.EventCreated, Subject: "p1"})
+	waitBus(t, bus)
+	bus.Publish(ctx, events.Event{Type: profiles.EventUpdated, Subject: "p1"})
+	waitBus(t, bus)
+	bus.Publish(ctx, events.Event{Type: profiles.EventDeleted, Subject: "p1"})
+	waitBus(t, bus)
+
+	bus.Publish(ctx, events.Event{Type: "run.completed", Subject: "r1"})
+	waitBus(t, bus)
+
+	want := []string{"created", "updated", "deleted"}
+	if len(log.entries) != len(want) {
+		t.Fatalf("recorded %d entries, want %d", len(log.entries), len(want))
+	}
+
+	for i, action := range want {
+		e := log.entries[i]
+		if e.Action != action {
+			t.Errorf("entries[%d].Action = %q, want %q", i, e.Action, action)
+		}
+		if e.ResourceType != "profile" || e.ResourceID != "p1" {
+			t.Errorf("entries[%d] resource = %s/%s, want profile/p1", i, e.ResourceType, e.ResourceID)
+		}
+		if e.Actor != "bob" {
+			t.Errorf("entries[%d].Actor = %q, want %q", i, e.Actor, "bob")
+		}
+	}
+}
+
+func TestRecorder_LogsRecordFailure(t *testing.T) {
+	var logged []string
+	logf := func(msg string, args ...any) { logged = append(logged, msg) }
+
+	recorder := audit.Recorder(func(context.Context, audit.Entry) error {
+		return errors.New("db down")
+	}, logf)
+
+	recorder(context.Background(), events.Event{Type: agents.EventDeleted, Subject: "a1"})
+
+	if len(logged) != 1 {
+		t.Fatalf("logged %d messages, want 1", len(logged))
+	}
+}
@@ -0,0 +1,105 @@
+package internal_audit_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/audit"
+	"github.com/JaimeStill/agent-lab/pkg/query"
+)
+
+func TestFiltersFromQuery(t *testing.T) {
+	values, _ := url.ParseQuery("resource_type=agent&resource_id=abc&actor=alice&action=deleted&page=2")
+	filters := audit.FiltersFromQuery(values)
+
+	checks := []struct {
+		name string
+		got  *string
+		want string
+	}{
+		{"ResourceType", filters.ResourceType, "agent"},
+		{"ResourceID", filters.ResourceID, "abc"},
+		{"Actor", filters.Actor, "alice"},
+		{"Action", filters.Action, "deleted"},
+	}
+
+	for _, c := range checks {
+		if c.got == nil {
+			t.Errorf("FiltersFromQuery() %s = nil, want %q", c.name, c.want)
+		} else if *c.got != c.want {
+			t.Errorf("FiltersFromQuery() %s = %q, want %q", c.name, *c.got, c.want)
+		}
+	}
+}
+
+func TestFiltersFromQuery_Empty(t *testing.T) {
+	filters := audit.FiltersFromQuery(url.Values{"resource_type": {""}})
+
+	if filters.ResourceType != nil || filters.ResourceID != nil || filters.Actor != nil || filters.Action != nil {
+		t.Errorf("FiltersFromQuery() = %+v, want all nil", filters)
+	}
+}
+
+func newTestProjection() *query.ProjectionMap {
+	return query.NewProjectionMap("public", "audit_log", "a").
+		Project("actor", "Actor").
+		Project("action", "Action").
+		Project("resource_type", "ResourceType").
+		Project("resource_id", "ResourceID").
+		Project("created_at", "CreatedAt")
+}
+
+func TestFilters_Apply(t *testing.T) {
+	tests := []struct {
+		name      string
+		filters   audit.Filters
+		wantWhere []string
+	}{
+		{"no filters", audit.Filters{}, nil},
+		{
+			"resource type only",
+			audit.Filters{ResourceType: strPtr("agent")},
+			[]string{"a.resource_type = $1"},
+		},
+		{
+			"resource type and id",
+			audit.Filters{ResourceType: strPtr("agent"), ResourceID: strPtr("abc")},
+			[]string{"a.resource_type = $1", "a.resource_id = $2"},
+		},
+		{
+			"actor and action",
+			audit.Filters{Actor: strPtr("alice"), Action: strPtr("created")},
+			[]string{"a.actor = $1", "a.action = $2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := query.NewBuilder(newTestProjection(), query.SortField{Field: "CreatedAt", Descending: true})
+			tt.filters.Apply(b)
+
+			sql, args := b.BuildCount()
+
+			if len(tt.wantWhere) == 0 {
+				if strings.Contains(sql, "WHERE") {
+					t.Errorf("Apply() unexpected WHERE clause, got %q", sql)
+				}
+				return
+			}
+
+			for _, clause := range tt.wantWhere {
+				if !strings.Contains(sql, clause) {
+					t.Errorf("Apply() SQL %q missing %q", sql, clause)
+				}
+			}
+			if len(args) != len(tt.wantWhere) {
+				t.Errorf("Apply() args count = %d, want %d", len(args), len(tt.wantWhere))
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
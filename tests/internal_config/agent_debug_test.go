@@ -0,0 +1,37 @@
+package internal_config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+)
+
+func TestAgentDebugConfig_Finalize(t *testing.T) {
+	t.Setenv("TEST_AGENT_DEBUG_ENABLED", "true")
+	t.Setenv("TEST_AGENT_DEBUG_REDACT_KEYS", "account, tenant ,")
+
+	cfg := config.AgentDebugConfig{RedactKeys: []string{"customer_id"}}
+	err := cfg.Finalize(&config.AgentDebugConfigEnv{
+		Enabled:    "TEST_AGENT_DEBUG_ENABLED",
+		RedactKeys: "TEST_AGENT_DEBUG_REDACT_KEYS",
+	})
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	if !cfg.Enabled {
+		t.Error("Enabled = false, want true from env")
+	}
+	if want := []string{"customer_id", "account", "tenant"}; strings.Join(cfg.RedactKeys, ",") != strings.Join(want, ",") {
+		t.Errorf("RedactKeys = %v, want %v", cfg.RedactKeys, want)
+	}
+	if cfg.MaxResponseLength != config.DefaultAgentDebugResponseLength {
+		t.Errorf("MaxResponseLength = %d, want %d", cfg.MaxResponseLength, config.DefaultAgentDebugResponseLength)
+	}
+
+	bad := config.AgentDebugConfig{MaxResponseLength: -1}
+	if err := bad.Finalize(nil); err == nil {
+		t.Error("Finalize() with negative max_response_length should fail")
+	}
+}
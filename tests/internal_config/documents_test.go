@@ -0,0 +1,48 @@
+package internal_config_test
+
+import (
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+)
+
+func TestDocumentsConfig_Finalize(t *testing.T) {
+	env := &config.DocumentsConfigEnv{AsyncPageCountSize: "TEST_DOCUMENTS_ASYNC_PAGE_COUNT_SIZE"}
+	t.Setenv("TEST_DOCUMENTS_ASYNC_PAGE_COUNT_SIZE", "")
+
+	cfg := config.DocumentsConfig{}
+	if err := cfg.Finalize(env); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.AsyncPageCountSizeBytes() != 25_000_000 {
+		t.Errorf("default AsyncPageCountSizeBytes = %d, want 25000000", cfg.AsyncPageCountSizeBytes())
+	}
+
+	t.Setenv("TEST_DOCUMENTS_ASYNC_PAGE_COUNT_SIZE", "0")
+	cfg = config.DocumentsConfig{}
+	if err := cfg.Finalize(env); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.AsyncPageCountSizeBytes() != 0 {
+		t.Errorf("env AsyncPageCountSizeBytes = %d, want 0", cfg.AsyncPageCountSizeBytes())
+	}
+
+	cfg = config.DocumentsConfig{AsyncPageCountSize: "lots"}
+	if err := cfg.Finalize(nil); err == nil {
+		t.Error("invalid AsyncPageCountSize: expected error")
+	}
+}
+
+func TestDocumentsConfig_Merge(t *testing.T) {
+	cfg := config.DocumentsConfig{AsyncPageCountSize: "25MB"}
+
+	cfg.Merge(&config.DocumentsConfig{})
+	if cfg.AsyncPageCountSize != "25MB" {
+		t.Errorf("empty overlay changed AsyncPageCountSize to %q", cfg.AsyncPageCountSize)
+	}
+
+	cfg.Merge(&config.DocumentsConfig{AsyncPageCountSize: "100MB"})
+	if cfg.AsyncPageCountSize != "100MB" {
+		t.Errorf("AsyncPageCountSize = %q, want 100MB", cfg.AsyncPageCountSize)
+	}
+}
@@ -0,0 +1,39 @@
+package internal_config_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+)
+
+func TestPriceTable_Cost(t *testing.T) {
+	prices := config.PriceTable{
+		"gpt-4o": {Prompt: 2.50, Completion: 10.00},
+	}
+
+	tests := []struct {
+		name       string
+		model      string
+		prompt     int
+		completion int
+		wantCost   float64
+		wantOK     bool
+	}{
+		{"known model", "gpt-4o", 1_000_000, 500_000, 7.50, true},
+		{"zero tokens", "gpt-4o", 0, 0, 0, true},
+		{"unknown model falls back to zero", "llama3", 1_000_000, 1_000_000, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, ok := prices.Cost(tt.model, tt.prompt, tt.completion)
+			if ok != tt.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if math.Abs(cost-tt.wantCost) > 1e-9 {
+				t.Errorf("cost = %v, want %v", cost, tt.wantCost)
+			}
+		})
+	}
+}
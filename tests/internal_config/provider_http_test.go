@@ -0,0 +1,68 @@
+package internal_config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+)
+
+func TestProviderHTTPConfig_Finalize(t *testing.T) {
+	t.Setenv("TEST_PROVIDER_HTTP_REQUEST_TIMEOUT", "")
+	t.Setenv("TEST_PROVIDER_HTTP_MAX_IDLE_CONNS", "")
+	env := &config.ProviderHTTPConfigEnv{
+		RequestTimeout: "TEST_PROVIDER_HTTP_REQUEST_TIMEOUT",
+		MaxIdleConns:   "TEST_PROVIDER_HTTP_MAX_IDLE_CONNS",
+	}
+
+	cfg := config.ProviderHTTPConfig{}
+	if err := cfg.Finalize(env); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	want := config.ProviderHTTPConfig{
+		DialTimeout:         "10s",
+		TLSHandshakeTimeout: "10s",
+		MaxIdleConns:        10,
+		IdleConnTimeout:     "30s",
+		RequestTimeout:      "2m",
+	}
+	if cfg != want {
+		t.Errorf("defaults = %+v, want %+v", cfg, want)
+	}
+
+	t.Setenv("TEST_PROVIDER_HTTP_REQUEST_TIMEOUT", "45s")
+	t.Setenv("TEST_PROVIDER_HTTP_MAX_IDLE_CONNS", "32")
+	cfg = config.ProviderHTTPConfig{}
+	if err := cfg.Finalize(env); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.RequestTimeoutDuration() != 45*time.Second {
+		t.Errorf("env RequestTimeout = %s, want 45s", cfg.RequestTimeout)
+	}
+	if cfg.MaxIdleConns != 32 {
+		t.Errorf("env MaxIdleConns = %d, want 32", cfg.MaxIdleConns)
+	}
+
+	invalid := []config.ProviderHTTPConfig{
+		{DialTimeout: "soon"},
+		{TLSHandshakeTimeout: "-1s"},
+		{IdleConnTimeout: "later"},
+		{RequestTimeout: "-5s"},
+		{MaxIdleConns: -1},
+	}
+	for _, cfg := range invalid {
+		if err := cfg.Finalize(nil); err == nil {
+			t.Errorf("%+v: expected error", cfg)
+		}
+	}
+}
+
+func TestProviderHTTPConfig_Merge(t *testing.T) {
+	cfg := config.ProviderHTTPConfig{DialTimeout: "10s", MaxIdleConns: 10, RequestTimeout: "2m"}
+	cfg.Merge(&config.ProviderHTTPConfig{MaxIdleConns: 50, RequestTimeout: "30s"})
+
+	want := config.ProviderHTTPConfig{DialTimeout: "10s", MaxIdleConns: 50, RequestTimeout: "30s"}
+	if cfg != want {
+		t.Errorf("Merge = %+v, want %+v", cfg, want)
+	}
+}
@@ -0,0 +1,85 @@
+package internal_config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+)
+
+func TestRenderConfig_Finalize(t *testing.T) {
+	t.Setenv("TEST_RENDER_PAGE_TIMEOUT", "")
+
+	cfg := config.RenderConfig{}
+	if err := cfg.Finalize(&config.RenderConfigEnv{PageTimeout: "TEST_RENDER_PAGE_TIMEOUT"}); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.PageTimeoutDuration() != 2*time.Minute {
+		t.Errorf("default PageTimeout = %s, want 2m", cfg.PageTimeout)
+	}
+
+	t.Setenv("TEST_RENDER_PAGE_TIMEOUT", "15s")
+	cfg = config.RenderConfig{}
+	if err := cfg.Finalize(&config.RenderConfigEnv{PageTimeout: "TEST_RENDER_PAGE_TIMEOUT"}); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.PageTimeoutDuration() != 15*time.Second {
+		t.Errorf("env PageTimeout = %s, want 15s", cfg.PageTimeout)
+	}
+
+	for _, v := range []string{"soon", "-1s"} {
+		cfg := config.RenderConfig{PageTimeout: v}
+		if err := cfg.Finalize(nil); err == nil {
+			t.Errorf("PageTimeout %q: expected error", v)
+		}
+	}
+}
+
+func TestRenderConfig_Merge(t *testing.T) {
+	cfg := config.RenderConfig{PageTimeout: "2m", MemoryLimit: "256MiB"}
+	cfg.Merge(&config.RenderConfig{PageTimeout: "30s", AreaLimit: "128MP"})
+
+	want := config.RenderConfig{PageTimeout: "30s", MemoryLimit: "256MiB", AreaLimit: "128MP"}
+	if cfg != want {
+		t.Errorf("Merge = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestRenderConfig_MaxWorkers(t *testing.T) {
+	t.Setenv("TEST_RENDER_MAX_WORKERS", "2")
+
+	cfg := config.RenderConfig{}
+	if err := cfg.Finalize(&config.RenderConfigEnv{MaxWorkers: "TEST_RENDER_MAX_WORKERS"}); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.MaxWorkers != 2 {
+		t.Errorf("MaxWorkers = %d, want 2", cfg.MaxWorkers)
+	}
+
+	cfg = config.RenderConfig{MaxWorkers: -1}
+	if err := cfg.Finalize(nil); err == nil {
+		t.Error("expected error for negative max_workers")
+	}
+}
+
+func TestRenderConfig_MaxConcurrent(t *testing.T) {
+	t.Setenv("TEST_RENDER_MAX_CONCURRENT", "6")
+
+	cfg := config.RenderConfig{}
+	if err := cfg.Finalize(&config.RenderConfigEnv{MaxConcurrent: "TEST_RENDER_MAX_CONCURRENT"}); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.MaxConcurrent != 6 {
+		t.Errorf("MaxConcurrent = %d, want 6", cfg.MaxConcurrent)
+	}
+
+	cfg.Merge(&config.RenderConfig{MaxConcurrent: 2})
+	if cfg.MaxConcurrent != 2 {
+		t.Errorf("Merge MaxConcurrent = %d, want 2", cfg.MaxConcurrent)
+	}
+
+	cfg = config.RenderConfig{MaxConcurrent: -1}
+	if err := cfg.Finalize(nil); err == nil {
+		t.Error("expected error for negative max_concurrent")
+	}
+}
@@ -0,0 +1,66 @@
+package internal_config_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/pkg/database"
+	"github.com/JaimeStill/agent-lab/pkg/middleware"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+func TestFinalize_Valid(t *testing.T) {
+	cfg := &config.Config{Database: database.Config{Name: "agent_lab", User: "agent_lab"}}
+
+	if err := cfg.Finalize(); err != nil {
+		t.Fatalf("Finalize() error = %v, want nil", err)
+	}
+}
+
+func TestFinalize_ReportsAllProblems(t *testing.T) {
+	t.Setenv("SERVER_PORT", "0")
+
+	cfg := &config.Config{
+		ShutdownTimeout: "forever",
+		Server:          config.ServerConfig{ReadTimeout: "soon"},
+		Database:        database.Config{Name: "agent_lab", User: "agent_lab"},
+		Storage:         storage.Config{BasePath: "   "},
+		API: config.APIConfig{
+			CORS:       middleware.CORSConfig{MaxAge: 90000},
+			Pagination: pagination.Config{DefaultPageSize: 50, MaxPageSize: 10},
+		},
+	}
+
+	err := cfg.Finalize()
+	if err == nil {
+		t.Fatal("Finalize() error = nil, want problems")
+	}
+
+	var verr *config.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Finalize() error = %T, want *config.ValidationError", err)
+	}
+
+	want := []string{
+		"invalid shutdown_timeout",
+		"server: invalid port: 0",
+		"server: invalid read_timeout",
+		"storage: base_path required",
+		"api: cors: invalid max_age: 90000",
+		"api: pagination: default_page_size (50) cannot exceed max_page_size (10)",
+	}
+
+	if len(verr.Problems) != len(want) {
+		t.Errorf("len(Problems) = %d, want %d:\n%v", len(verr.Problems), len(want), err)
+	}
+
+	msg := err.Error()
+	for _, w := range want {
+		if !strings.Contains(msg, w) {
+			t.Errorf("error missing %q:\n%s", w, msg)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package internal_config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+)
+
+func TestWorkflowsConfig_Finalize(t *testing.T) {
+	t.Setenv("TEST_WORKFLOWS_MAX_CONCURRENT", "")
+
+	cfg := config.WorkflowsConfig{}
+	if err := cfg.Finalize(&config.WorkflowsConfigEnv{MaxConcurrent: "TEST_WORKFLOWS_MAX_CONCURRENT"}); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.MaxConcurrent != config.DefaultWorkflowMaxConcurrent {
+		t.Errorf("default MaxConcurrent = %d, want %d", cfg.MaxConcurrent, config.DefaultWorkflowMaxConcurrent)
+	}
+
+	t.Setenv("TEST_WORKFLOWS_MAX_CONCURRENT", "8")
+	cfg = config.WorkflowsConfig{}
+	if err := cfg.Finalize(&config.WorkflowsConfigEnv{MaxConcurrent: "TEST_WORKFLOWS_MAX_CONCURRENT"}); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.MaxConcurrent != 8 {
+		t.Errorf("env MaxConcurrent = %d, want 8", cfg.MaxConcurrent)
+	}
+
+	cfg = config.WorkflowsConfig{MaxConcurrent: -1}
+	if err := cfg.Finalize(nil); err == nil {
+		t.Error("negative MaxConcurrent: expected error")
+	}
+}
+
+func TestWorkflowsConfig_Merge(t *testing.T) {
+	cfg := config.WorkflowsConfig{MaxConcurrent: 4}
+
+	cfg.Merge(&config.WorkflowsConfig{})
+	if cfg.MaxConcurrent != 4 {
+		t.Errorf("Merge(zero) MaxConcurrent = %d, want 4", cfg.MaxConcurrent)
+	}
+
+	cfg.Merge(&config.WorkflowsConfig{MaxConcurrent: 2})
+	if cfg.MaxConcurrent != 2 {
+		t.Errorf("Merge MaxConcurrent = %d, want 2", cfg.MaxConcurrent)
+	}
+}
+
+func TestWorkflowsConfig_StreamRetry(t *testing.T) {
+	env := &config.WorkflowsConfigEnv{StreamRetry: "TEST_WORKFLOWS_STREAM_RETRY"}
+
+	t.Setenv("TEST_WORKFLOWS_STREAM_RETRY", "")
+	cfg := config.WorkflowsConfig{}
+	if err := cfg.Finalize(env); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.StreamRetryDuration() != 3*time.Second {
+		t.Errorf("default StreamRetryDuration() = %v, want 3s", cfg.StreamRetryDuration())
+	}
+
+	t.Setenv("TEST_WORKFLOWS_STREAM_RETRY", "500ms")
+	cfg = config.WorkflowsConfig{}
+	if err := cfg.Finalize(env); err != nil {
+		t.Fatalf("Finalize: %v", err)
+	}
+	if cfg.StreamRetryDuration() != 500*time.Millisecond {
+		t.Errorf("env StreamRetryDuration() = %v, want 500ms", cfg.StreamRetryDuration())
+	}
+
+	for _, v := range []string{"soon", "-1s"} {
+		cfg := config.WorkflowsConfig{StreamRetry: v}
+		if err := cfg.Finalize(nil); err == nil {
+			t.Errorf("StreamRetry %q: expected error", v)
+		}
+	}
+
+	cfg = config.WorkflowsConfig{StreamRetry: "3s"}
+	cfg.Merge(&config.WorkflowsConfig{StreamRetry: "0"})
+	if cfg.StreamRetry != "0" {
+		t.Errorf("Merge StreamRetry = %q, want 0", cfg.StreamRetry)
+	}
+}
@@ -0,0 +1,149 @@
+package internal_documents_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+const testPassword = "s3cret"
+
+// minimalPDF returns a valid single-page PDF with an empty page.
+func minimalPDF() []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 200] >>",
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return b.Bytes()
+}
+
+// encryptedPDF writes a PDF that requires testPassword to open and returns its path.
+func encryptedPDF(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.pdf")
+	if err := os.WriteFile(plain, minimalPDF(), 0644); err != nil {
+		t.Fatalf("write pdf: %v", err)
+	}
+
+	encrypted := filepath.Join(dir, "encrypted.pdf")
+	if err := api.EncryptFile(plain, encrypted, model.NewAESConfiguration(testPassword, "owner-"+testPassword, 256)); err != nil {
+		t.Fatalf("encrypt pdf: %v", err)
+	}
+	return encrypted
+}
+
+func TestDecryptPDF(t *testing.T) {
+	path := encryptedPDF(t)
+
+	for _, password := range []string{"", "wrong"} {
+		t.Run("password "+password, func(t *testing.T) {
+			if _, err := documents.DecryptPDF(path, password); !errors.Is(err, documents.ErrEncrypted) {
+				t.Errorf("DecryptPDF() error = %v, want ErrEncrypted", err)
+			}
+		})
+	}
+
+	decrypted, err := documents.DecryptPDF(path, testPassword)
+	if err != nil {
+		t.Fatalf("DecryptPDF() error = %v", err)
+	}
+	defer os.Remove(decrypted)
+
+	if n, err := api.PageCountFile(decrypted); err != nil || n != 1 {
+		t.Errorf("decrypted page count = %d, %v, want 1", n, err)
+	}
+}
+
+func newEncryptedUploadRequest(t *testing.T, password string) *http.Request {
+	t.Helper()
+
+	data, err := os.ReadFile(encryptedPDF(t))
+	if err != nil {
+		t.Fatalf("read pdf: %v", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", "encrypted.pdf")
+	if err != nil {
+		t.Fatalf("CreateFormFile() error = %v", err)
+	}
+	part.Write(data)
+
+	if password != "" {
+		writer.WriteField("password", password)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/documents", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestHandler_Upload_Encrypted(t *testing.T) {
+	tests := []struct {
+		name       string
+		password   string
+		wantStatus int
+	}{
+		{"no password", "", http.StatusUnprocessableEntity},
+		{"wrong password", "wrong", http.StatusUnprocessableEntity},
+		{"correct password", testPassword, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys := &captureSystem{}
+			rec := httptest.NewRecorder()
+
+			sys.Handler(1<<20).Upload(rec, newEncryptedUploadRequest(t, tt.password))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				if sys.created != nil {
+					t.Error("Create should not be called without a valid password")
+				}
+				return
+			}
+			if sys.created.PageCount == nil || *sys.created.PageCount != 1 {
+				t.Errorf("PageCount = %v, want 1", sys.created.PageCount)
+			}
+			if bytes.Contains(rec.Body.Bytes(), []byte(testPassword)) {
+				t.Error("response echoes the password")
+			}
+		})
+	}
+}
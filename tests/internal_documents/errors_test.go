@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
 )
 
 func TestMapHTTPStatus(t *testing.T) {
@@ -55,6 +56,16 @@ func TestMapHTTPStatus(t *testing.T) {
 			fmt.Errorf("failed: %w", documents.ErrInvalidFile),
 			http.StatusBadRequest,
 		},
+		{
+			"encrypted error",
+			fmt.Errorf("count pages: %w", documents.ErrEncrypted),
+			http.StatusUnprocessableEntity,
+		},
+		{
+			"storage quota exceeded error",
+			fmt.Errorf("store file: %w", storage.ErrQuotaExceeded),
+			http.StatusInsufficientStorage,
+		},
 		{
 			"unknown error",
 			errors.New("unknown error"),
@@ -87,6 +98,7 @@ func TestErrorValues(t *testing.T) {
 		{"ErrDuplicate", documents.ErrDuplicate, "document storage key already exists"},
 		{"ErrFileTooLarge", documents.ErrFileTooLarge, "file exceeds maximum upload size"},
 		{"ErrInvalidFile", documents.ErrInvalidFile, "invalid file"},
+		{"ErrEncrypted", documents.ErrEncrypted, "document is encrypted and requires a valid password"},
 	}
 
 	for _, tt := range tests {
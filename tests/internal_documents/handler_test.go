@@ -0,0 +1,137 @@
+package internal_documents_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+type captureSystem struct {
+	created *documents.CreateCommand
+}
+
+func (s *captureSystem) Handler(maxUploadSize int64) *documents.Handler {
+	return documents.NewHandler(s, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, maxUploadSize, 0)
+}
+
+func (s *captureSystem) List(ctx context.Context, page pagination.PageRequest, filters documents.Filters) (*pagination.PageResult[documents.Document], error) {
+	return nil, nil
+}
+
+func (s *captureSystem) Find(ctx context.Context, id uuid.UUID) (*documents.Document, error) {
+	return nil, documents.ErrNotFound
+}
+
+func (s *captureSystem) Create(ctx context.Context, cmd documents.CreateCommand) (*documents.Document, error) {
+	s.created = &cmd
+	return &documents.Document{
+		ID:               uuid.New(),
+		Name:             cmd.Name,
+		Filename:         cmd.Filename,
+		ContentType:      cmd.ContentType,
+		SizeBytes:        cmd.SizeBytes,
+		PageCount:        cmd.PageCount,
+		PageCountPending: cmd.PageCountPending,
+	}, nil
+}
+
+func (s *captureSystem) Update(ctx context.Context, id uuid.UUID, cmd documents.UpdateCommand) (*documents.Document, error) {
+	return nil, documents.ErrNotFound
+}
+
+func (s *captureSystem) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (s *captureSystem) CreateVersion(ctx context.Context, id uuid.UUID, cmd documents.CreateVersionCommand) (*documents.Document, error) {
+	return nil, documents.ErrNotFound
+}
+
+func (s *captureSystem) ListVersions(ctx context.Context, id uuid.UUID) ([]documents.Version, error) {
+	return nil, documents.ErrNotFound
+}
+
+func (s *captureSystem) FindVersion(ctx context.Context, id uuid.UUID, version int) (*documents.Version, error) {
+	return nil, documents.ErrVersionNotFound
+}
+
+func (s *captureSystem) Download(ctx context.Context, id uuid.UUID, version int) ([]byte, *documents.Version, error) {
+	return nil, nil, documents.ErrNotFound
+}
+
+func newUploadRequest(t *testing.T, filename string, content []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("CreateFormFile() error = %v", err)
+	}
+
+	if _, err := part.Write(content); err != nil {
+		t.Fatalf("part.Write() error = %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("writer.Close() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/documents", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestHandler_Upload_ReadsFullBody(t *testing.T) {
+	content := bytes.Repeat([]byte("agent-lab upload chunk\n"), 200000)
+
+	sys := &captureSystem{}
+	handler := sys.Handler(int64(len(content)) * 2)
+
+	rec := httptest.NewRecorder()
+	handler.Upload(rec, newUploadRequest(t, "large.txt", content))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+
+	if sys.created == nil {
+		t.Fatal("Create was not called")
+	}
+
+	if !bytes.Equal(sys.created.Data, content) {
+		t.Errorf("stored %d bytes, want %d matching bytes", len(sys.created.Data), len(content))
+	}
+
+	if sys.created.SizeBytes != int64(len(content)) {
+		t.Errorf("SizeBytes = %d, want %d", sys.created.SizeBytes, len(content))
+	}
+}
+
+func TestHandler_Upload_TooLarge(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 4096)
+
+	sys := &captureSystem{}
+	handler := sys.Handler(1024)
+
+	rec := httptest.NewRecorder()
+	handler.Upload(rec, newUploadRequest(t, "large.txt", content))
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+
+	if sys.created != nil {
+		t.Error("Create should not be called for oversized uploads")
+	}
+}
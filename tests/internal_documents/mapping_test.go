@@ -100,6 +100,33 @@ func TestFiltersFromQuery(t *testing.T) {
 	}
 }
 
+func TestFiltersFromQuery_HasImages(t *testing.T) {
+	tests := []struct {
+		query string
+		want  *bool
+	}{
+		{"", nil},
+		{"has_images=true", boolPtr(true)},
+		{"has_images=1", boolPtr(true)},
+		{"has_images=false", boolPtr(false)},
+		{"has_images=maybe", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			got := documents.FiltersFromQuery(values).HasImages
+
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("FiltersFromQuery() HasImages = %v, want nil", *got)
+			case tt.want != nil && (got == nil || *got != *tt.want):
+				t.Errorf("FiltersFromQuery() HasImages = %v, want %v", got, *tt.want)
+			}
+		})
+	}
+}
+
 func newTestProjection() *query.ProjectionMap {
 	return query.NewProjectionMap("public", "documents", "d").
 		Project("id", "ID").
@@ -169,6 +196,43 @@ func TestFilters_Apply(t *testing.T) {
 	}
 }
 
+func TestFilters_Apply_HasImages(t *testing.T) {
+	tests := []struct {
+		name      string
+		hasImages bool
+		want      string
+	}{
+		{"with images", true, "EXISTS (SELECT 1 FROM public.images i WHERE i.document_id = d.id)"},
+		{"without images", false, "NOT EXISTS (SELECT 1 FROM public.images i WHERE i.document_id = d.id)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := query.NewBuilder(newTestProjection())
+
+			filters := documents.Filters{
+				Name:      strPtr("report"),
+				HasImages: &tt.hasImages,
+			}
+			filters.Apply(b)
+
+			sql, args := b.BuildCount()
+
+			want := "SELECT COUNT(*) FROM public.documents d WHERE d.name ILIKE $1 AND " + tt.want
+			if sql != want {
+				t.Errorf("Apply() sql = %q, want %q", sql, want)
+			}
+			if len(args) != 1 {
+				t.Errorf("Apply() args = %v, want 1 arg", args)
+			}
+		})
+	}
+}
+
 func strPtr(s string) *string {
 	return &s
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
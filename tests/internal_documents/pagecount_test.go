@@ -0,0 +1,232 @@
+package internal_documents_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+)
+
+func newPDFUploadRequest(t *testing.T, content []byte, password string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", "large.pdf")
+	if err != nil {
+		t.Fatalf("CreateFormFile() error = %v", err)
+	}
+	part.Write(content)
+	writer.WriteField("password", password)
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/documents", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestHandler_Upload_LargePDFPendingPageCount(t *testing.T) {
+	content := append([]byte("%PDF-1.7\n"), bytes.Repeat([]byte("0"), 4096)...)
+
+	sys := &captureSystem{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := documents.NewHandler(sys, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 1<<20, 1024)
+
+	rec := httptest.NewRecorder()
+	handler.Upload(rec, newPDFUploadRequest(t, content, "secret"))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+
+	if !sys.created.PageCountPending {
+		t.Error("PageCountPending = false, want true")
+	}
+	if sys.created.PageCount != nil {
+		t.Errorf("PageCount = %d, want nil", *sys.created.PageCount)
+	}
+	if sys.created.Password != "secret" {
+		t.Errorf("Password = %q, want %q", sys.created.Password, "secret")
+	}
+
+	var doc documents.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !doc.PageCountPending || doc.PageCount != nil {
+		t.Errorf("response page_count_pending = %v, page_count = %v, want pending with no count", doc.PageCountPending, doc.PageCount)
+	}
+}
+
+func TestHandler_Upload_SmallPDFCountedInline(t *testing.T) {
+	content := []byte("%PDF-1.7\nnot really a pdf")
+
+	sys := &captureSystem{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := documents.NewHandler(sys, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 1<<20, 1024)
+
+	rec := httptest.NewRecorder()
+	handler.Upload(rec, newPDFUploadRequest(t, content, ""))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	if sys.created.PageCountPending {
+		t.Error("PageCountPending = true, want false below the async size")
+	}
+}
+
+// pageExtraction stands in for PDF parsing, blocking until released.
+type pageExtraction struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+	count   int
+	err     error
+}
+
+func newPageExtraction(count int, err error) *pageExtraction {
+	return &pageExtraction{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+		count:   count,
+		err:     err,
+	}
+}
+
+func (e *pageExtraction) extract() (*int, error) {
+	e.calls.Add(1)
+	e.started <- struct{}{}
+	<-e.release
+	if e.err != nil {
+		return nil, e.err
+	}
+	n := e.count
+	return &n, nil
+}
+
+type pageResult struct {
+	count *int
+	err   error
+}
+
+func waitPageResult(t *testing.T, ch <-chan pageResult) pageResult {
+	t.Helper()
+
+	select {
+	case res := <-ch:
+		return res
+	case <-time.After(2 * time.Second):
+		t.Fatal("page count was not delivered")
+		return pageResult{}
+	}
+}
+
+func TestPageCounter_PopulatesCount(t *testing.T) {
+	var counter documents.PageCounter
+	ext := newPageExtraction(12, nil)
+
+	results := make(chan pageResult, 1)
+	counter.Start("key", ext.extract, func(count *int, err error) {
+		results <- pageResult{count, err}
+	})
+
+	<-ext.started
+	select {
+	case <-results:
+		t.Fatal("count delivered before extraction finished")
+	default:
+	}
+
+	close(ext.release)
+	res := waitPageResult(t, results)
+
+	if res.err != nil {
+		t.Fatalf("done error = %v", res.err)
+	}
+	if res.count == nil || *res.count != 12 {
+		t.Errorf("done count = %v, want 12", res.count)
+	}
+}
+
+func TestPageCounter_ConcurrentStartsShareExtraction(t *testing.T) {
+	var counter documents.PageCounter
+	ext := newPageExtraction(7, nil)
+
+	results := make(chan pageResult, 2)
+	done := func(count *int, err error) {
+		results <- pageResult{count, err}
+	}
+
+	counter.Start("key", ext.extract, done)
+	<-ext.started
+	counter.Start("key", ext.extract, done)
+
+	time.Sleep(50 * time.Millisecond)
+	close(ext.release)
+
+	first := waitPageResult(t, results)
+	second := waitPageResult(t, results)
+
+	for i, res := range []pageResult{first, second} {
+		if res.err != nil || res.count == nil || *res.count != 7 {
+			t.Errorf("result %d = %v, %v, want 7", i, res.count, res.err)
+		}
+	}
+	if first.count == second.count {
+		t.Error("callers share the same count pointer, want independent copies")
+	}
+	if n := ext.calls.Load(); n != 1 {
+		t.Errorf("extractions = %d, want 1", n)
+	}
+}
+
+func TestPageCounter_Error(t *testing.T) {
+	var counter documents.PageCounter
+	wantErr := errors.New("corrupt pdf")
+	ext := newPageExtraction(0, wantErr)
+	close(ext.release)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	var got pageResult
+	counter.Start("key", ext.extract, func(count *int, err error) {
+		got = pageResult{count, err}
+		wg.Done()
+	})
+	wg.Wait()
+
+	if !errors.Is(got.err, wantErr) {
+		t.Errorf("done error = %v, want %v", got.err, wantErr)
+	}
+	if got.count != nil {
+		t.Errorf("done count = %d, want nil", *got.count)
+	}
+}
+
+func TestPageCountKey(t *testing.T) {
+	data := []byte("%PDF-1.7")
+
+	if documents.PageCountKey(data, "a") != documents.PageCountKey(data, "a") {
+		t.Error("keys differ for identical content and password")
+	}
+	if documents.PageCountKey(data, "a") == documents.PageCountKey(data, "b") {
+		t.Error("key unchanged when password differs")
+	}
+	if documents.PageCountKey(data, "") == documents.PageCountKey([]byte("%PDF-1.6"), "") {
+		t.Error("key unchanged when content differs")
+	}
+}
@@ -0,0 +1,278 @@
+package internal_documents_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/documents"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/routes"
+	"github.com/google/uuid"
+)
+
+// versionSystem keeps document versions in memory, mirroring the
+// repository's numbering so handler routes can be exercised end to end.
+type versionSystem struct {
+	captureSystem
+	docs     map[uuid.UUID]*documents.Document
+	versions map[uuid.UUID][]documents.Version
+	data     map[string][]byte
+}
+
+func newVersionSystem() *versionSystem {
+	return &versionSystem{
+		docs:     map[uuid.UUID]*documents.Document{},
+		versions: map[uuid.UUID][]documents.Version{},
+		data:     map[string][]byte{},
+	}
+}
+
+func (s *versionSystem) Handler(maxUploadSize int64) *documents.Handler {
+	return documents.NewHandler(s, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, maxUploadSize, 0)
+}
+
+func (s *versionSystem) Find(ctx context.Context, id uuid.UUID) (*documents.Document, error) {
+	doc, ok := s.docs[id]
+	if !ok {
+		return nil, documents.ErrNotFound
+	}
+	return doc, nil
+}
+
+func (s *versionSystem) Create(ctx context.Context, cmd documents.CreateCommand) (*documents.Document, error) {
+	doc := &documents.Document{ID: uuid.New(), Name: cmd.Name}
+	s.docs[doc.ID] = doc
+	return s.CreateVersion(ctx, doc.ID, documents.CreateVersionCommand{
+		Filename:    cmd.Filename,
+		ContentType: cmd.ContentType,
+		SizeBytes:   cmd.SizeBytes,
+		PageCount:   cmd.PageCount,
+		Data:        cmd.Data,
+	})
+}
+
+func (s *versionSystem) CreateVersion(ctx context.Context, id uuid.UUID, cmd documents.CreateVersionCommand) (*documents.Document, error) {
+	doc, ok := s.docs[id]
+	if !ok {
+		return nil, documents.ErrNotFound
+	}
+
+	v := documents.Version{
+		DocumentID:  id,
+		Version:     doc.Version + 1,
+		Filename:    cmd.Filename,
+		ContentType: cmd.ContentType,
+		SizeBytes:   cmd.SizeBytes,
+		PageCount:   cmd.PageCount,
+		StorageKey:  uuid.NewString(),
+	}
+	s.data[v.StorageKey] = cmd.Data
+	s.versions[id] = append(s.versions[id], v)
+
+	doc.Version = v.Version
+	doc.Filename = v.Filename
+	doc.ContentType = v.ContentType
+	doc.SizeBytes = v.SizeBytes
+	doc.PageCount = v.PageCount
+	doc.StorageKey = v.StorageKey
+	return doc, nil
+}
+
+func (s *versionSystem) ListVersions(ctx context.Context, id uuid.UUID) ([]documents.Version, error) {
+	if _, ok := s.docs[id]; !ok {
+		return nil, documents.ErrNotFound
+	}
+	versions := slices.Clone(s.versions[id])
+	slices.Reverse(versions)
+	return versions, nil
+}
+
+func (s *versionSystem) FindVersion(ctx context.Context, id uuid.UUID, version int) (*documents.Version, error) {
+	for _, v := range s.versions[id] {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, documents.ErrVersionNotFound
+}
+
+func (s *versionSystem) Download(ctx context.Context, id uuid.UUID, version int) ([]byte, *documents.Version, error) {
+	if version <= 0 {
+		doc, err := s.Find(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		version = doc.Version
+	}
+	v, err := s.FindVersion(ctx, id, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.data[v.StorageKey], v, nil
+}
+
+func versionMux(sys *versionSystem) *http.ServeMux {
+	mux := http.NewServeMux()
+	routes.Register(mux, "", openapi.NewSpec("test", "1.0"), sys.Handler(1<<20).Routes())
+	return mux
+}
+
+func multipartRequest(t *testing.T, method, target, filename string, content []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("CreateFormFile() error = %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest(method, target, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func serve(mux *http.ServeMux, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func uploadVersions(t *testing.T, mux *http.ServeMux) documents.Document {
+	t.Helper()
+
+	rec := serve(mux, multipartRequest(t, http.MethodPost, "/documents", "scan.txt", []byte("first scan")))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("upload status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var doc documents.Document
+	json.NewDecoder(rec.Body).Decode(&doc)
+
+	rec = serve(mux, multipartRequest(t, http.MethodPost, "/documents/"+doc.ID.String()+"/versions", "scan-fixed.txt", []byte("corrected scan")))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("version upload status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var updated documents.Document
+	if err := json.NewDecoder(rec.Body).Decode(&updated); err != nil {
+		t.Fatalf("decode version response: %v", err)
+	}
+	return updated
+}
+
+func TestVersions_UploadSecondVersion(t *testing.T) {
+	mux := versionMux(newVersionSystem())
+	doc := uploadVersions(t, mux)
+
+	if doc.Version != 2 {
+		t.Errorf("Version = %d, want 2", doc.Version)
+	}
+	if doc.Filename != "scan-fixed.txt" {
+		t.Errorf("Filename = %q, want latest filename", doc.Filename)
+	}
+	if doc.Name != "scan.txt" {
+		t.Errorf("Name = %q, want display name preserved", doc.Name)
+	}
+}
+
+func TestVersions_List(t *testing.T) {
+	mux := versionMux(newVersionSystem())
+	doc := uploadVersions(t, mux)
+
+	rec := serve(mux, httptest.NewRequest(http.MethodGet, "/documents/"+doc.ID.String()+"/versions", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var versions []documents.Version
+	if err := json.NewDecoder(rec.Body).Decode(&versions); err != nil {
+		t.Fatalf("decode versions: %v", err)
+	}
+
+	if len(versions) != 2 || versions[0].Version != 2 || versions[1].Version != 1 {
+		t.Fatalf("versions = %+v, want versions 2 then 1", versions)
+	}
+	if versions[1].Filename != "scan.txt" {
+		t.Errorf("versions[1].Filename = %q, want original filename", versions[1].Filename)
+	}
+}
+
+func TestVersions_Download(t *testing.T) {
+	mux := versionMux(newVersionSystem())
+	doc := uploadVersions(t, mux)
+	base := "/documents/" + doc.ID.String()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantBody string
+		wantFile string
+	}{
+		{"latest", base + "/download", "corrected scan", "scan-fixed.txt"},
+		{"version 1", base + "/versions/1/download", "first scan", "scan.txt"},
+		{"version 2", base + "/versions/2/download", "corrected scan", "scan-fixed.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(mux, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+			if got, want := rec.Header().Get("Content-Disposition"), `attachment; filename=`+tt.wantFile; got != want {
+				t.Errorf("Content-Disposition = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestVersions_DownloadErrors(t *testing.T) {
+	mux := versionMux(newVersionSystem())
+	doc := uploadVersions(t, mux)
+	base := "/documents/" + doc.ID.String()
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"missing version", base + "/versions/3/download", http.StatusNotFound},
+		{"zero version", base + "/versions/0/download", http.StatusBadRequest},
+		{"non-numeric version", base + "/versions/latest/download", http.StatusBadRequest},
+		{"missing document", "/documents/" + uuid.NewString() + "/download", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(mux, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersions_CreateForMissingDocument(t *testing.T) {
+	mux := versionMux(newVersionSystem())
+
+	rec := serve(mux, multipartRequest(t, http.MethodPost, "/documents/"+uuid.NewString()+"/versions", "scan.txt", []byte("x")))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,226 @@
+package internal_images_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/images"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// asyncResult is what RenderAsync passes to its done callback.
+type asyncResult struct {
+	final []images.Image
+	err   error
+}
+
+func asyncRenderOptions(t *testing.T) images.RenderOptions {
+	t.Helper()
+
+	opts := images.RenderOptions{Format: "png", DPI: 150}
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	return opts
+}
+
+// renderAll returns an AsyncRenderFunc that waits for release, then reports
+// every pending image rendered at size bytes.
+func renderAll(release <-chan struct{}, size int64, calls *int) images.AsyncRenderFunc {
+	return func(ctx context.Context, pending []images.Image) ([]images.Image, error) {
+		*calls++
+		<-release
+		rendered := make([]images.Image, len(pending))
+		for i, img := range pending {
+			img.SizeBytes = size
+			rendered[i] = img
+		}
+		return rendered, nil
+	}
+}
+
+func waitAsync(t *testing.T, done <-chan asyncResult) asyncResult {
+	t.Helper()
+
+	select {
+	case res := <-done:
+		return res
+	case <-time.After(time.Second):
+		t.Fatal("async render did not finish")
+		return asyncResult{}
+	}
+}
+
+func TestRenderAsync_PendingThenReady(t *testing.T) {
+	docID := uuid.New()
+	db := openFakeImagesDB(t, &fakeImagesDB{})
+	opts := asyncRenderOptions(t)
+
+	release := make(chan struct{})
+	done := make(chan asyncResult, 1)
+	var calls int
+
+	start := time.Now()
+	queued, err := images.RenderAsync(context.Background(), db, docID, []int{1, 2, 3}, opts, renderAll(release, 42, &calls),
+		func(final []images.Image, err error) { done <- asyncResult{final, err} })
+	if err != nil {
+		t.Fatalf("RenderAsync() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("RenderAsync() took %s, want it to return without waiting for the render", elapsed)
+	}
+
+	if len(queued) != 3 {
+		t.Fatalf("RenderAsync() returned %d images, want 3", len(queued))
+	}
+	for i, img := range queued {
+		if img.Status != images.StatusPending || img.PageNumber != i+1 || img.StorageKey == "" {
+			t.Errorf("queued[%d] = page %d %s key %q, want page %d pending with a storage key", i, img.PageNumber, img.Status, img.StorageKey, i+1)
+		}
+	}
+
+	stored, err := images.DocumentImages(context.Background(), db, docID)
+	if err != nil {
+		t.Fatalf("DocumentImages() error = %v", err)
+	}
+	for _, img := range stored {
+		if img.Status != images.StatusPending {
+			t.Errorf("page %d recorded %s before the render finished, want pending", img.PageNumber, img.Status)
+		}
+	}
+
+	close(release)
+	res := waitAsync(t, done)
+	if res.err != nil {
+		t.Fatalf("done error = %v", res.err)
+	}
+	if len(res.final) != 3 {
+		t.Fatalf("done received %d images, want 3", len(res.final))
+	}
+
+	stored, err = images.DocumentImages(context.Background(), db, docID)
+	if err != nil {
+		t.Fatalf("DocumentImages() error = %v", err)
+	}
+	for _, img := range stored {
+		if img.Status != images.StatusReady || img.SizeBytes != 42 || img.RenderError != nil {
+			t.Errorf("page %d = %s, %d bytes, error %v; want ready, 42 bytes", img.PageNumber, img.Status, img.SizeBytes, img.RenderError)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("render called %d times, want 1", calls)
+	}
+}
+
+func TestRenderAsync_FailedPages(t *testing.T) {
+	docID := uuid.New()
+	db := openFakeImagesDB(t, &fakeImagesDB{})
+	done := make(chan asyncResult, 1)
+
+	render := func(ctx context.Context, pending []images.Image) ([]images.Image, error) {
+		first := pending[0]
+		first.SizeBytes = 7
+		return []images.Image{first}, errors.New("page 2 exploded")
+	}
+
+	_, err := images.RenderAsync(context.Background(), db, docID, []int{1, 2}, asyncRenderOptions(t), render,
+		func(final []images.Image, err error) { done <- asyncResult{final, err} })
+	if err != nil {
+		t.Fatalf("RenderAsync() error = %v", err)
+	}
+	waitAsync(t, done)
+
+	stored, err := images.DocumentImages(context.Background(), db, docID)
+	if err != nil {
+		t.Fatalf("DocumentImages() error = %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("stored %d images, want 2", len(stored))
+	}
+	if stored[0].Status != images.StatusReady {
+		t.Errorf("page 1 status = %s, want ready", stored[0].Status)
+	}
+	if stored[1].Status != images.StatusFailed || stored[1].RenderError == nil || *stored[1].RenderError != "page 2 exploded" {
+		t.Errorf("page 2 = %s with error %v, want failed with the render error", stored[1].Status, stored[1].RenderError)
+	}
+}
+
+func TestRenderAsync_SkipsReadyAndPendingPages(t *testing.T) {
+	docID := uuid.New()
+	fdb := &fakeImagesDB{}
+	seedImages(t, fdb, newMemStorage(), docID, 1)
+	db := openFakeImagesDB(t, fdb)
+	opts := asyncRenderOptions(t)
+
+	release := make(chan struct{})
+	done := make(chan asyncResult, 1)
+	var calls int
+	render := renderAll(release, 5, &calls)
+	record := func(final []images.Image, err error) { done <- asyncResult{final, err} }
+
+	first, err := images.RenderAsync(context.Background(), db, docID, []int{1, 2}, opts, render, record)
+	if err != nil {
+		t.Fatalf("RenderAsync() error = %v", err)
+	}
+	if first[0].Status != images.StatusReady || first[1].Status != images.StatusPending {
+		t.Errorf("statuses = %s, %s; want ready page 1 reused and page 2 pending", first[0].Status, first[1].Status)
+	}
+
+	second, err := images.RenderAsync(context.Background(), db, docID, []int{2}, opts, render, record)
+	if err != nil {
+		t.Fatalf("second RenderAsync() error = %v", err)
+	}
+	if second[0].ID != first[1].ID || second[0].Status != images.StatusPending {
+		t.Errorf("second render returned %s %s, want the pending image %s", second[0].ID, second[0].Status, first[1].ID)
+	}
+
+	close(release)
+	res := waitAsync(t, done)
+	if len(res.final) != 1 || res.final[0].PageNumber != 2 {
+		t.Errorf("rendered %+v, want only page 2", res.final)
+	}
+	if calls != 1 {
+		t.Errorf("render called %d times, want 1", calls)
+	}
+}
+
+func TestData_PendingImageNotReady(t *testing.T) {
+	docID := uuid.New()
+	fdb := &fakeImagesDB{}
+	seedImages(t, fdb, newMemStorage(), docID, 1)
+	fdb.rows[0]["status"] = "pending"
+	id := uuid.MustParse(fdb.rows[0]["id"].(string))
+
+	db := openFakeImagesDB(t, fdb)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sys := images.New(nil, db, newMemStorage(), nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, config.RenderConfig{}, nil)
+
+	if _, _, err := sys.Data(context.Background(), id); !errors.Is(err, images.ErrNotReady) {
+		t.Errorf("Data() error = %v, want ErrNotReady", err)
+	}
+}
+
+func TestHandler_Render_InvalidAsync(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := images.NewHandler(nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+	docID := uuid.NewString()
+
+	req := httptest.NewRequest(http.MethodPost, "/"+docID+"/render?async=soon", strings.NewReader(`{"format":"png"}`))
+	req.SetPathValue("documentId", docID)
+	rec := httptest.NewRecorder()
+
+	handler.Render(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+}
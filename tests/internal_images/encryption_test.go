@@ -0,0 +1,112 @@
+package internal_images_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/images"
+	"github.com/JaimeStill/document-context/pkg/document"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+const testPassword = "s3cret"
+
+// writePDF writes a valid single-page PDF to dir and returns its path.
+func writePDF(t *testing.T, dir string) string {
+	t.Helper()
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 200] >>",
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	path := filepath.Join(dir, "plain.pdf")
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatalf("write pdf: %v", err)
+	}
+	return path
+}
+
+func TestOpenablePath_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+	encrypted := filepath.Join(dir, "encrypted.pdf")
+	if err := api.EncryptFile(writePDF(t, dir), encrypted, model.NewAESConfiguration(testPassword, "owner-"+testPassword, 256)); err != nil {
+		t.Fatalf("encrypt pdf: %v", err)
+	}
+
+	if _, err := document.Open(encrypted, "application/pdf"); err == nil {
+		t.Fatal("document.Open() succeeded on encrypted pdf without a password")
+	}
+
+	for _, password := range []string{"", "wrong"} {
+		t.Run("password "+password, func(t *testing.T) {
+			_, _, err := images.OpenablePath(encrypted, "application/pdf", password)
+			if !errors.Is(err, images.ErrDocumentEncrypted) {
+				t.Fatalf("OpenablePath() error = %v, want ErrDocumentEncrypted", err)
+			}
+			if errors.Is(err, images.ErrRenderFailed) {
+				t.Error("encryption error should not be classified as ErrRenderFailed")
+			}
+		})
+	}
+
+	t.Run("correct password", func(t *testing.T) {
+		path, release, err := images.OpenablePath(encrypted, "application/pdf", testPassword)
+		if err != nil {
+			t.Fatalf("OpenablePath() error = %v", err)
+		}
+
+		doc, err := document.Open(path, "application/pdf")
+		if err != nil {
+			t.Fatalf("document.Open() on decrypted path error = %v", err)
+		}
+		if doc.PageCount() != 1 {
+			t.Errorf("PageCount() = %d, want 1", doc.PageCount())
+		}
+		doc.Close()
+
+		release()
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("decrypted copy %s not removed on release", path)
+		}
+	})
+}
+
+func TestOpenablePath_Unencrypted(t *testing.T) {
+	plain := writePDF(t, t.TempDir())
+
+	path, release, err := images.OpenablePath(plain, "application/pdf", "ignored")
+	if err != nil {
+		t.Fatalf("OpenablePath() error = %v", err)
+	}
+	release()
+
+	if path != plain {
+		t.Errorf("path = %q, want unchanged %q", path, plain)
+	}
+	if _, err := os.Stat(plain); err != nil {
+		t.Errorf("release removed the original document: %v", err)
+	}
+}
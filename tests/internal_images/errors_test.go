@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/JaimeStill/agent-lab/internal/images"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
 )
 
 func TestMapHTTPStatus(t *testing.T) {
@@ -35,6 +37,11 @@ func TestMapHTTPStatus(t *testing.T) {
 			fmt.Errorf("failed: %w", images.ErrDuplicate),
 			http.StatusConflict,
 		},
+		{
+			"not ready error",
+			fmt.Errorf("%w: image is pending", images.ErrNotReady),
+			http.StatusConflict,
+		},
 		{
 			"document not found error",
 			images.ErrDocumentNotFound,
@@ -95,6 +102,21 @@ func TestMapHTTPStatus(t *testing.T) {
 			fmt.Errorf("failed: %w", images.ErrRenderFailed),
 			http.StatusInternalServerError,
 		},
+		{
+			"render timeout error",
+			fmt.Errorf("%w: %w: page 3", images.ErrRenderFailed, images.ErrRenderTimeout),
+			http.StatusUnprocessableEntity,
+		},
+		{
+			"document encrypted error",
+			fmt.Errorf("%w: wrong password", images.ErrDocumentEncrypted),
+			http.StatusUnprocessableEntity,
+		},
+		{
+			"render failed by storage quota",
+			fmt.Errorf("%w: %w", images.ErrRenderFailed, storage.ErrQuotaExceeded),
+			http.StatusInsufficientStorage,
+		},
 		{
 			"unknown error",
 			errors.New("unknown error"),
@@ -131,6 +153,8 @@ func TestErrorValues(t *testing.T) {
 		{"ErrPageOutOfRange", images.ErrPageOutOfRange, "page number out of range"},
 		{"ErrInvalidRenderOption", images.ErrInvalidRenderOption, "invalid render option"},
 		{"ErrRenderFailed", images.ErrRenderFailed, "render failed"},
+		{"ErrRenderTimeout", images.ErrRenderTimeout, "page render timed out"},
+		{"ErrNotReady", images.ErrNotReady, "image is not rendered"},
 	}
 
 	for _, tt := range tests {
@@ -141,3 +165,32 @@ func TestErrorValues(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{"ErrNotFound", images.ErrNotFound, "not_found"},
+		{"ErrDuplicate", images.ErrDuplicate, "duplicate"},
+		{"ErrDocumentNotFound", images.ErrDocumentNotFound, "not_found"},
+		{"ErrUnsupportedFormat", images.ErrUnsupportedFormat, "unsupported_format"},
+		{"ErrInvalidPageRange", images.ErrInvalidPageRange, "invalid_page_range"},
+		{"ErrPageOutOfRange", images.ErrPageOutOfRange, "page_out_of_range"},
+		{"wrapped ErrInvalidRenderOption", fmt.Errorf("%w: dpi", images.ErrInvalidRenderOption), "invalid_render_option"},
+		{"ErrRenderFailed", images.ErrRenderFailed, "render_failed"},
+		{"wrapped ErrRenderTimeout", fmt.Errorf("%w: %w", images.ErrRenderFailed, images.ErrRenderTimeout), "render_timeout"},
+		{"ErrDocumentEncrypted", images.ErrDocumentEncrypted, "document_encrypted"},
+		{"ErrNotReady", images.ErrNotReady, "not_ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := handlers.ErrorCode(tt.err, images.MapHTTPStatus(tt.err))
+			if got != tt.wantCode {
+				t.Errorf("ErrorCode() = %q, want %q", got, tt.wantCode)
+			}
+		})
+	}
+}
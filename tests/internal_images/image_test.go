@@ -136,6 +136,26 @@ func TestRenderOptions_Validate(t *testing.T) {
 			images.RenderOptions{Rotation: intPtr(90)},
 			false, nil,
 		},
+		{
+			"valid grayscale",
+			images.RenderOptions{Grayscale: boolPtr(true)},
+			false, nil,
+		},
+		{
+			"threshold too low",
+			images.RenderOptions{Threshold: intPtr(-1)},
+			true, images.ErrInvalidRenderOption,
+		},
+		{
+			"threshold too high",
+			images.RenderOptions{Threshold: intPtr(101)},
+			true, images.ErrInvalidRenderOption,
+		},
+		{
+			"valid threshold",
+			images.RenderOptions{Threshold: intPtr(50)},
+			false, nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -269,6 +289,45 @@ func TestRenderOptions_ToImageConfig(t *testing.T) {
 	if cfg.Options["background"] != "blue" {
 		t.Errorf("ToImageConfig() Options[background] = %v, want blue", cfg.Options["background"])
 	}
+	if _, ok := cfg.Options["grayscale"]; ok {
+		t.Errorf("ToImageConfig() Options[grayscale] set without Grayscale option")
+	}
+}
+
+func TestRenderOptions_ToImageConfig_Filters(t *testing.T) {
+	opts := images.RenderOptions{
+		Format:    document.PNG,
+		DPI:       300,
+		Grayscale: boolPtr(true),
+		Threshold: intPtr(60),
+	}
+
+	cfg := opts.ToImageConfig()
+
+	if cfg.Options["grayscale"] != true {
+		t.Errorf("ToImageConfig() Options[grayscale] = %v, want true", cfg.Options["grayscale"])
+	}
+	if cfg.Options["threshold"] != 60 {
+		t.Errorf("ToImageConfig() Options[threshold] = %v, want 60", cfg.Options["threshold"])
+	}
+}
+
+func TestRenderOptions_ToImage_Filters(t *testing.T) {
+	opts := images.RenderOptions{
+		Format:    document.PNG,
+		DPI:       300,
+		Grayscale: boolPtr(true),
+		Threshold: intPtr(40),
+	}
+
+	img := opts.ToImage(uuid.New(), uuid.New(), 1, "images/test/1.png", 100)
+
+	if img.Grayscale == nil || !*img.Grayscale {
+		t.Errorf("ToImage() Grayscale = %v, want true", img.Grayscale)
+	}
+	if img.Threshold == nil || *img.Threshold != 40 {
+		t.Errorf("ToImage() Threshold = %v, want 40", img.Threshold)
+	}
 }
 
 func TestRenderOptions_ToImageConfig_JpegDefaultQuality(t *testing.T) {
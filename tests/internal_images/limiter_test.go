@@ -0,0 +1,72 @@
+package internal_images_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/images"
+)
+
+func TestRenderLimiter_BoundsConcurrency(t *testing.T) {
+	const limit = 3
+	limiter := images.NewRenderLimiter(limit)
+
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for range 4 * limit {
+		wg.Go(func() {
+			if err := limiter.Acquire(context.Background()); err != nil {
+				t.Errorf("Acquire() error = %v", err)
+				return
+			}
+			defer limiter.Release()
+
+			n := inFlight.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			inFlight.Add(-1)
+		})
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > limit {
+		t.Errorf("peak in-flight renders = %d, want at most %d", got, limit)
+	}
+	if got := peak.Load(); got != limit {
+		t.Errorf("peak in-flight renders = %d, want the limit %d to be reached", got, limit)
+	}
+}
+
+func TestRenderLimiter_AcquireRespectsContext(t *testing.T) {
+	limiter := images.NewRenderLimiter(1)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire() on a full limiter = %v, want context.DeadlineExceeded", err)
+	}
+
+	limiter.Release()
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire() after Release error = %v", err)
+	}
+}
+
+func TestNewRenderLimiter_DefaultSize(t *testing.T) {
+	if got := images.NewRenderLimiter(0).Size(); got < 1 {
+		t.Errorf("Size() = %d, want the CPU count", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/internal/images"
 	"github.com/JaimeStill/agent-lab/pkg/query"
@@ -154,7 +155,9 @@ func newTestProjection() *query.ProjectionMap {
 		Project("id", "ID").
 		Project("document_id", "DocumentID").
 		Project("format", "Format").
-		Project("page_number", "PageNumber")
+		Project("page_number", "PageNumber").
+		Project("size_bytes", "SizeBytes").
+		Project("created_at", "CreatedAt")
 }
 
 func TestFilters_Apply(t *testing.T) {
@@ -231,6 +234,146 @@ func TestFilters_Apply(t *testing.T) {
 	}
 }
 
+func TestFiltersFromQuery_SizeAndDate(t *testing.T) {
+	after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2025, 6, 30, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		query         string
+		minSize       *int64
+		maxSize       *int64
+		createdAfter  *time.Time
+		createdBefore *time.Time
+	}{
+		{
+			"size bounds",
+			"min_size=1024&max_size=1048576",
+			int64Ptr(1024), int64Ptr(1048576), nil, nil,
+		},
+		{
+			"date bounds",
+			"created_after=2025-01-01&created_before=2025-06-30T12:30:00Z",
+			nil, nil, &after, &before,
+		},
+		{
+			"zero min size",
+			"min_size=0",
+			int64Ptr(0), nil, nil, nil,
+		},
+		{
+			"negative size ignored",
+			"min_size=-1&max_size=-5",
+			nil, nil, nil, nil,
+		},
+		{
+			"non-numeric size ignored",
+			"min_size=big&max_size=1MB",
+			nil, nil, nil, nil,
+		},
+		{
+			"invalid dates ignored",
+			"created_after=yesterday&created_before=2025-13-45",
+			nil, nil, nil, nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+			filters := images.FiltersFromQuery(values)
+
+			if !reflect.DeepEqual(filters.MinSize, tt.minSize) {
+				t.Errorf("MinSize = %v, want %v", deref(filters.MinSize), deref(tt.minSize))
+			}
+			if !reflect.DeepEqual(filters.MaxSize, tt.maxSize) {
+				t.Errorf("MaxSize = %v, want %v", deref(filters.MaxSize), deref(tt.maxSize))
+			}
+			if !timeEqual(filters.CreatedAfter, tt.createdAfter) {
+				t.Errorf("CreatedAfter = %v, want %v", deref(filters.CreatedAfter), deref(tt.createdAfter))
+			}
+			if !timeEqual(filters.CreatedBefore, tt.createdBefore) {
+				t.Errorf("CreatedBefore = %v, want %v", deref(filters.CreatedBefore), deref(tt.createdBefore))
+			}
+		})
+	}
+}
+
+func TestFilters_Apply_SizeAndDate(t *testing.T) {
+	now := time.Now()
+	testDocID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name         string
+		filters      images.Filters
+		wantClauses  []string
+		wantArgCount int
+	}{
+		{
+			"min size only",
+			images.Filters{MinSize: int64Ptr(100)},
+			[]string{"i.size_bytes >= $1"},
+			1,
+		},
+		{
+			"size range",
+			images.Filters{MinSize: int64Ptr(100), MaxSize: int64Ptr(200)},
+			[]string{"i.size_bytes >= $1", "i.size_bytes <= $2"},
+			2,
+		},
+		{
+			"date range",
+			images.Filters{CreatedAfter: &now, CreatedBefore: &now},
+			[]string{"i.created_at > $1", "i.created_at < $2"},
+			2,
+		},
+		{
+			"combined with existing filters",
+			images.Filters{
+				DocumentID:    &testDocID,
+				MinSize:       int64Ptr(100),
+				MaxSize:       int64Ptr(200),
+				CreatedAfter:  &now,
+				CreatedBefore: &now,
+			},
+			[]string{"i.document_id = $1", "i.size_bytes >= $2", "i.created_at < $5"},
+			5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := query.NewBuilder(newTestProjection(), query.SortField{Field: "ID"})
+			tt.filters.Apply(b)
+
+			sql, args := b.BuildCount()
+
+			for _, clause := range tt.wantClauses {
+				if !strings.Contains(sql, clause) {
+					t.Errorf("Apply() missing %q, got %q", clause, sql)
+				}
+			}
+			if len(args) != tt.wantArgCount {
+				t.Errorf("Apply() args count = %d, want %d", len(args), tt.wantArgCount)
+			}
+		})
+	}
+}
+
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func timeEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
 func TestParsePageRange(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -464,3 +607,11 @@ func TestParsePageRange_SinglePageDocument(t *testing.T) {
 func intPtr(i int) *int {
 	return &i
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,171 @@
+package internal_images_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/images"
+	"github.com/google/uuid"
+)
+
+// pageStore stands in for the images table: find returns the image once
+// render has recorded it, as findExisting does after renderPage.
+type pageStore struct {
+	mu      sync.Mutex
+	img     *images.Image
+	renders atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func newPageStore() *pageStore {
+	return &pageStore{started: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (s *pageStore) find(ctx context.Context) (*images.Image, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.img, nil
+}
+
+func (s *pageStore) render(ctx context.Context) (*images.Image, error) {
+	s.renders.Add(1)
+	s.started <- struct{}{}
+	<-s.release
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.img = &images.Image{ID: uuid.New(), PageNumber: 1}
+	return s.img, nil
+}
+
+func TestPageLoader_FirstRenderThenReuse(t *testing.T) {
+	var loader images.PageLoader
+	store := newPageStore()
+	close(store.release)
+	key := images.PageImageKey(uuid.New(), 1, images.RenderOptions{Format: "png", DPI: 300})
+
+	first, err := loader.Load(context.Background(), key, store.find, store.render)
+	if err != nil {
+		t.Fatalf("first Load() error = %v", err)
+	}
+	if first == nil {
+		t.Fatal("first Load() returned nil image")
+	}
+
+	second, err := loader.Load(context.Background(), key, store.find, store.render)
+	if err != nil {
+		t.Fatalf("second Load() error = %v", err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("second Load() ID = %s, want %s", second.ID, first.ID)
+	}
+
+	if n := store.renders.Load(); n != 1 {
+		t.Errorf("renders = %d, want 1", n)
+	}
+}
+
+func TestPageLoader_ConcurrentRequestSharesRender(t *testing.T) {
+	var loader images.PageLoader
+	store := newPageStore()
+	key := images.PageImageKey(uuid.New(), 1, images.RenderOptions{Format: "png", DPI: 300})
+
+	results := make([]*images.Image, 2)
+	errs := make([]error, 2)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		results[0], errs[0] = loader.Load(context.Background(), key, store.find, store.render)
+	})
+
+	<-store.started
+
+	wg.Go(func() {
+		results[1], errs[1] = loader.Load(context.Background(), key, store.find, store.render)
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	close(store.release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Load() %d error = %v", i, err)
+		}
+	}
+
+	if results[0].ID != results[1].ID {
+		t.Errorf("concurrent Load() IDs = %s and %s, want the same image", results[0].ID, results[1].ID)
+	}
+
+	if n := store.renders.Load(); n != 1 {
+		t.Errorf("renders = %d, want 1", n)
+	}
+}
+
+func TestPageLoader_CallerCancelled(t *testing.T) {
+	var loader images.PageLoader
+	store := newPageStore()
+	key := images.PageImageKey(uuid.New(), 1, images.RenderOptions{Format: "png", DPI: 300})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := loader.Load(ctx, key, store.find, store.render)
+		done <- err
+	}()
+
+	<-store.started
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Load() error = %v, want context.Canceled", err)
+	}
+
+	close(store.release)
+
+	img, err := loader.Load(context.Background(), key, store.find, store.render)
+	if err != nil {
+		t.Fatalf("Load() after cancel error = %v", err)
+	}
+	if img == nil {
+		t.Error("Load() after cancel returned nil image")
+	}
+	if n := store.renders.Load(); n != 1 {
+		t.Errorf("renders = %d, want 1", n)
+	}
+}
+
+func TestPageImageKey(t *testing.T) {
+	docID := uuid.New()
+	q1, q2 := 80, 80
+	base := images.RenderOptions{Format: "jpg", DPI: 150, Quality: &q1}
+
+	if images.PageImageKey(docID, 2, base) != images.PageImageKey(docID, 2, images.RenderOptions{Format: "jpg", DPI: 150, Quality: &q2}) {
+		t.Error("keys differ for equal option values")
+	}
+
+	variants := []struct {
+		name    string
+		pageNum int
+		opts    images.RenderOptions
+	}{
+		{"page", 3, base},
+		{"dpi", 2, images.RenderOptions{Format: "jpg", DPI: 300, Quality: &q1}},
+		{"format", 2, images.RenderOptions{Format: "png", DPI: 150, Quality: &q1}},
+		{"unset option", 2, images.RenderOptions{Format: "jpg", DPI: 150}},
+	}
+
+	for _, tt := range variants {
+		t.Run(tt.name, func(t *testing.T) {
+			if images.PageImageKey(docID, tt.pageNum, tt.opts) == images.PageImageKey(docID, 2, base) {
+				t.Errorf("key unchanged when %s differs", tt.name)
+			}
+		})
+	}
+}
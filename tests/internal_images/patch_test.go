@@ -0,0 +1,110 @@
+package internal_images_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/images"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/document-context/pkg/document"
+	"github.com/google/uuid"
+)
+
+func newPatchSystem(t *testing.T) (images.System, uuid.UUID) {
+	t.Helper()
+
+	fdb := &fakeImagesDB{}
+	seedImages(t, fdb, newMemStorage(), uuid.New(), 1)
+	fdb.rows[0]["notes"] = "original notes"
+	id := uuid.MustParse(fdb.rows[0]["id"].(string))
+
+	db := openFakeImagesDB(t, fdb)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sys := images.New(nil, db, newMemStorage(), nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, config.RenderConfig{}, nil)
+	return sys, id
+}
+
+func TestPatch_UpdatesLabelOnly(t *testing.T) {
+	sys, id := newPatchSystem(t)
+
+	label := "signature page"
+	img, err := sys.Patch(context.Background(), id, images.PatchImageCommand{Label: &label})
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+
+	if img.Label == nil || *img.Label != label {
+		t.Errorf("Label = %v, want %q", img.Label, label)
+	}
+
+	found, err := sys.Find(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+
+	if found.Label == nil || *found.Label != label {
+		t.Errorf("persisted Label = %v, want %q", found.Label, label)
+	}
+	if found.Notes == nil || *found.Notes != "original notes" {
+		t.Errorf("Notes = %v, want unchanged %q", found.Notes, "original notes")
+	}
+	if found.Format != document.PNG || found.DPI != 150 {
+		t.Errorf("format/dpi = %s/%d, want unchanged png/150", found.Format, found.DPI)
+	}
+}
+
+func TestPatch_RejectsRenderParameters(t *testing.T) {
+	sys, id := newPatchSystem(t)
+
+	dpi := 300
+	_, err := sys.Patch(context.Background(), id, images.PatchImageCommand{DPI: &dpi})
+	if !errors.Is(err, handlers.ErrValidation) {
+		t.Fatalf("Patch() error = %v, want ErrValidation", err)
+	}
+
+	found, err := sys.Find(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if found.DPI != 150 {
+		t.Errorf("DPI = %d, want unchanged 150", found.DPI)
+	}
+}
+
+func TestHandler_Patch(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"label", `{"label":"cover"}`, http.StatusOK},
+		{"dpi", `{"dpi":300}`, http.StatusBadRequest},
+		{"format", `{"format":"jpg"}`, http.StatusBadRequest},
+		{"label too long", `{"label":"` + strings.Repeat("x", 201) + `"}`, http.StatusBadRequest},
+		{"unknown field", `{"quality":50}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sys, id := newPatchSystem(t)
+
+			req := httptest.NewRequest(http.MethodPatch, "/"+id.String(), strings.NewReader(tt.body))
+			req.SetPathValue("id", id.String())
+			rec := httptest.NewRecorder()
+
+			sys.Handler().Patch(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package internal_images_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/images"
+)
+
+func TestRenderPageWithin_SlowPageTimesOut(t *testing.T) {
+	const slowPage = 2
+	timeout := 50 * time.Millisecond
+
+	release := make(chan struct{})
+	defer close(release)
+
+	observed := make(chan error, 1)
+
+	render := func(pageNum int) func(context.Context) (*images.Image, error) {
+		return func(ctx context.Context) (*images.Image, error) {
+			if pageNum == slowPage {
+				<-ctx.Done()
+				observed <- context.Cause(ctx)
+				<-release
+				return nil, ctx.Err()
+			}
+			return &images.Image{PageNumber: pageNum}, nil
+		}
+	}
+
+	pages := []int{1, 2, 3}
+	results := make([]*images.Image, len(pages))
+	errs := make([]error, len(pages))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, pageNum := range pages {
+		wg.Go(func() {
+			results[i], errs[i] = images.RenderPageWithin(context.Background(), pageNum, timeout, render(pageNum))
+		})
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("batch took %s, slow page should not block it", elapsed)
+	}
+
+	for i, pageNum := range pages {
+		if pageNum == slowPage {
+			if !errors.Is(errs[i], images.ErrRenderTimeout) {
+				t.Fatalf("page %d: got %v, want ErrRenderTimeout", pageNum, errs[i])
+			}
+			if !errors.Is(errs[i], images.ErrRenderFailed) {
+				t.Errorf("page %d: timeout should also wrap ErrRenderFailed", pageNum)
+			}
+			if results[i] != nil {
+				t.Errorf("page %d: expected no result", pageNum)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Fatalf("page %d: unexpected error %v", pageNum, errs[i])
+		}
+		if results[i] == nil || results[i].PageNumber != pageNum {
+			t.Errorf("page %d: got %+v", pageNum, results[i])
+		}
+	}
+
+	if slowCause := <-observed; !errors.Is(slowCause, images.ErrRenderTimeout) {
+		t.Errorf("abandoned render cause = %v, want ErrRenderTimeout", slowCause)
+	}
+}
+
+func TestRenderPageWithin_ZeroTimeoutRunsDirectly(t *testing.T) {
+	ctx := context.Background()
+
+	img, err := images.RenderPageWithin(ctx, 1, 0, func(pageCtx context.Context) (*images.Image, error) {
+		if _, ok := pageCtx.Deadline(); ok {
+			t.Error("expected no deadline without a timeout")
+		}
+		return &images.Image{PageNumber: 1}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if img.PageNumber != 1 {
+		t.Errorf("PageNumber = %d, want 1", img.PageNumber)
+	}
+}
+
+func TestRenderPageWithin_ParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := images.RenderPageWithin(ctx, 1, time.Minute, func(pageCtx context.Context) (*images.Image, error) {
+		<-pageCtx.Done()
+		return nil, pageCtx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if errors.Is(err, images.ErrRenderTimeout) {
+		t.Error("parent cancellation should not report a render timeout")
+	}
+}
+
+func TestRenderPageWithin_PropagatesRenderError(t *testing.T) {
+	_, err := images.RenderPageWithin(context.Background(), 1, time.Minute, func(context.Context) (*images.Image, error) {
+		return nil, images.ErrRenderFailed
+	})
+	if !errors.Is(err, images.ErrRenderFailed) || errors.Is(err, images.ErrRenderTimeout) {
+		t.Fatalf("got %v, want plain ErrRenderFailed", err)
+	}
+}
+
+func TestRenderTimeout_HTTPStatus(t *testing.T) {
+	_, err := images.RenderPageWithin(context.Background(), 4, time.Millisecond, func(ctx context.Context) (*images.Image, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if got := images.MapHTTPStatus(err); got != http.StatusUnprocessableEntity {
+		t.Errorf("MapHTTPStatus = %d, want %d", got, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestRenderOptions_WithLimits(t *testing.T) {
+	cfg := config.RenderConfig{PageTimeout: "30s", MemoryLimit: "256MiB", AreaLimit: "128MP"}
+	opts := images.RenderOptions{Format: "png", DPI: 150}.WithLimits(cfg)
+
+	if opts.PageTimeout != 30*time.Second {
+		t.Errorf("PageTimeout = %s, want 30s", opts.PageTimeout)
+	}
+
+	ic := opts.ToImageConfig()
+	if ic.Options["memory_limit"] != "256MiB" {
+		t.Errorf("memory_limit = %v, want 256MiB", ic.Options["memory_limit"])
+	}
+	if ic.Options["area_limit"] != "128MP" {
+		t.Errorf("area_limit = %v, want 128MP", ic.Options["area_limit"])
+	}
+
+	bare := images.RenderOptions{Format: "png", DPI: 150}.ToImageConfig()
+	if _, ok := bare.Options["memory_limit"]; ok {
+		t.Error("memory_limit should be omitted when unset")
+	}
+}
+
+func TestRenderWorkerCount(t *testing.T) {
+	cpus := runtime.NumCPU()
+
+	tests := []struct {
+		name       string
+		pageCount  int
+		maxWorkers int
+		want       int
+	}{
+		{"unconfigured uses cpu count", cpus + 10, 0, cpus},
+		{"unconfigured bounded by pages", 1, 0, 1},
+		{"configured max below cpu count", cpus + 10, 1, 1},
+		{"configured max above cpu count", cpus + 10, cpus + 5, cpus},
+		{"negative max ignored", cpus + 10, -3, cpus},
+		{"zero pages", 0, 0, 1},
+		{"zero pages with max", 0, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := images.RenderWorkerCount(tt.pageCount, tt.maxWorkers); got != tt.want {
+				t.Errorf("RenderWorkerCount(%d, %d) = %d, want %d", tt.pageCount, tt.maxWorkers, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package internal_images_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/images"
+	"github.com/JaimeStill/document-context/pkg/document"
+	"github.com/google/uuid"
+)
+
+var imageCols = []string{
+	"id", "document_id", "page_number", "format", "dpi", "quality",
+	"brightness", "contrast", "saturation", "rotation", "background",
+	"grayscale", "threshold", "storage_key", "size_bytes", "status",
+	"render_error", "label", "notes", "created_at",
+}
+
+// setPattern matches the "col = $n" assignments of an UPDATE statement.
+var setPattern = regexp.MustCompile(`(\w+) = \$(\d+)`)
+
+// fakeImagesDB holds image rows keyed by column name. Transactions snapshot
+// the rows on Begin and restore them on Rollback. failPage makes the INSERT
+// for that page number fail.
+type fakeImagesDB struct {
+	mu       sync.Mutex
+	rows     []map[string]driver.Value
+	snapshot []map[string]driver.Value
+	failPage int64
+}
+
+var (
+	fakeImagesMu  sync.Mutex
+	fakeImagesDBs = map[string]*fakeImagesDB{}
+)
+
+func init() {
+	sql.Register("fakeimages", fakeImagesDriver{})
+}
+
+type fakeImagesDriver struct{}
+
+func (fakeImagesDriver) Open(dsn string) (driver.Conn, error) {
+	fakeImagesMu.Lock()
+	defer fakeImagesMu.Unlock()
+	return &fakeImagesConn{db: fakeImagesDBs[dsn]}, nil
+}
+
+type fakeImagesConn struct {
+	db *fakeImagesDB
+}
+
+func (c *fakeImagesConn) Prepare(q string) (driver.Stmt, error) {
+	return &fakeImagesStmt{db: c.db, query: q}, nil
+}
+
+func (c *fakeImagesConn) Close() error { return nil }
+
+func (c *fakeImagesConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.snapshot = append([]map[string]driver.Value(nil), c.db.rows...)
+	return &fakeImagesTx{db: c.db}, nil
+}
+
+type fakeImagesTx struct {
+	db *fakeImagesDB
+}
+
+func (t *fakeImagesTx) Commit() error { return nil }
+
+func (t *fakeImagesTx) Rollback() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.rows = t.db.snapshot
+	return nil
+}
+
+type fakeImagesStmt struct {
+	db    *fakeImagesDB
+	query string
+}
+
+func (s *fakeImagesStmt) Close() error  { return nil }
+func (s *fakeImagesStmt) NumInput() int { return -1 }
+
+func (s *fakeImagesStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(s.query), "DELETE FROM images WHERE document_id"):
+		var kept []map[string]driver.Value
+		for _, row := range s.db.rows {
+			if row["document_id"] != args[0] {
+				kept = append(kept, row)
+			}
+		}
+		affected := len(s.db.rows) - len(kept)
+		s.db.rows = kept
+		return driver.RowsAffected(affected), nil
+	case strings.HasPrefix(strings.TrimSpace(s.query), "INSERT INTO images"):
+		if args[2] == s.db.failPage {
+			return nil, errors.New("insert failed")
+		}
+		row := map[string]driver.Value{"created_at": time.Now()}
+		for i, col := range imageCols[:16] {
+			row[col] = args[i]
+		}
+		s.db.rows = append(s.db.rows, row)
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("unexpected exec %q", s.query)
+}
+
+func (s *fakeImagesStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	match := "document_id"
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(s.query), "UPDATE "):
+		match = "id"
+		sets := s.query[:strings.Index(s.query, " WHERE ")]
+		for _, row := range s.db.rows {
+			if row["id"] != args[0] {
+				continue
+			}
+			for _, m := range setPattern.FindAllStringSubmatch(sets, -1) {
+				n, _ := strconv.Atoi(m[2])
+				row[m[1]] = args[n-1]
+			}
+		}
+	case strings.Contains(s.query, "i.id = $1"):
+		match = "id"
+	case !strings.Contains(s.query, "i.document_id = $1"):
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+
+	// Single page lookups also filter on page number.
+	byPage := strings.Contains(s.query, "i.page_number = $2")
+
+	rows := &fakeImageRows{}
+	for _, row := range s.db.rows {
+		if row[match] != args[0] || byPage && row["page_number"] != args[1] {
+			continue
+		}
+		values := make([]driver.Value, len(imageCols))
+		for i, col := range imageCols {
+			values[i] = row[col]
+		}
+		rows.values = append(rows.values, values)
+	}
+	return rows, nil
+}
+
+type fakeImageRows struct {
+	values [][]driver.Value
+	pos    int
+}
+
+func (r *fakeImageRows) Columns() []string { return imageCols }
+func (r *fakeImageRows) Close() error      { return nil }
+
+func (r *fakeImageRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+func openFakeImagesDB(t *testing.T, fdb *fakeImagesDB) *sql.DB {
+	t.Helper()
+
+	fakeImagesMu.Lock()
+	fakeImagesDBs[t.Name()] = fdb
+	fakeImagesMu.Unlock()
+
+	db, err := sql.Open("fakeimages", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// seedImages stores and records one PNG image per page at 150 DPI.
+func seedImages(t *testing.T, fdb *fakeImagesDB, store *memStorage, docID uuid.UUID, pages int) []string {
+	t.Helper()
+
+	var keys []string
+	for page := 1; page <= pages; page++ {
+		key := fmt.Sprintf("images/%s/%s.png", docID, uuid.New())
+		store.Store(context.Background(), key, []byte("old"))
+		keys = append(keys, key)
+
+		fdb.rows = append(fdb.rows, map[string]driver.Value{
+			"id":          uuid.NewString(),
+			"document_id": docID.String(),
+			"page_number": int64(page),
+			"format":      "png",
+			"dpi":         int64(150),
+			"storage_key": key,
+			"size_bytes":  int64(3),
+			"status":      "ready",
+			"created_at":  time.Now(),
+		})
+	}
+	return keys
+}
+
+// stageImages stores images for each page as Rerender would before the swap.
+func stageImages(t *testing.T, store *memStorage, docID uuid.UUID, pages int, opts images.RenderOptions) []images.Image {
+	t.Helper()
+
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	var staged []images.Image
+	for page := 1; page <= pages; page++ {
+		key := fmt.Sprintf("images/%s/%s.%s", docID, uuid.New(), opts.Format)
+		store.Store(context.Background(), key, []byte("new"))
+		staged = append(staged, *opts.ToImage(uuid.New(), docID, page, key, 3))
+	}
+	return staged
+}
+
+func TestReplaceDocumentImages(t *testing.T) {
+	docID := uuid.New()
+	otherDoc := uuid.New()
+
+	fdb := &fakeImagesDB{}
+	store := newMemStorage()
+	oldKeys := seedImages(t, fdb, store, docID, 2)
+	otherKeys := seedImages(t, fdb, store, otherDoc, 1)
+	db := openFakeImagesDB(t, fdb)
+
+	grayscale := true
+	opts := images.RenderOptions{Format: document.JPEG, DPI: 300, Grayscale: &grayscale}
+	staged := stageImages(t, store, docID, 3, opts)
+
+	removed, err := images.ReplaceDocumentImages(context.Background(), db, store, docID, staged)
+	if err != nil {
+		t.Fatalf("ReplaceDocumentImages() error = %v", err)
+	}
+
+	if len(removed) != 2 {
+		t.Errorf("removed = %d images, want 2", len(removed))
+	}
+
+	for _, key := range oldKeys {
+		if _, ok := store.data[key]; ok {
+			t.Errorf("old image file %s was not deleted", key)
+		}
+	}
+	for _, key := range otherKeys {
+		if _, ok := store.data[key]; !ok {
+			t.Errorf("image file %s of another document was deleted", key)
+		}
+	}
+
+	current, err := images.DocumentImages(context.Background(), db, docID)
+	if err != nil {
+		t.Fatalf("DocumentImages() error = %v", err)
+	}
+
+	if len(current) != 3 {
+		t.Fatalf("DocumentImages() = %d images, want 3", len(current))
+	}
+
+	for i, img := range current {
+		if img.PageNumber != i+1 {
+			t.Errorf("image %d PageNumber = %d, want %d", i, img.PageNumber, i+1)
+		}
+		if img.Format != document.JPEG || img.DPI != 300 {
+			t.Errorf("image %d format/dpi = %s/%d, want jpg/300", i, img.Format, img.DPI)
+		}
+		if img.Grayscale == nil || !*img.Grayscale {
+			t.Errorf("image %d Grayscale = %v, want true", i, img.Grayscale)
+		}
+		if string(store.data[img.StorageKey]) != "new" {
+			t.Errorf("image %d storage key %s does not hold new data", i, img.StorageKey)
+		}
+	}
+
+	others, err := images.DocumentImages(context.Background(), db, otherDoc)
+	if err != nil {
+		t.Fatalf("DocumentImages() error = %v", err)
+	}
+	if len(others) != 1 {
+		t.Errorf("other document images = %d, want 1", len(others))
+	}
+}
+
+func TestReplaceDocumentImages_FailureKeepsOldSet(t *testing.T) {
+	docID := uuid.New()
+
+	fdb := &fakeImagesDB{failPage: 2}
+	store := newMemStorage()
+	oldKeys := seedImages(t, fdb, store, docID, 2)
+	db := openFakeImagesDB(t, fdb)
+
+	staged := stageImages(t, store, docID, 2, images.RenderOptions{Format: document.PNG, DPI: 72})
+
+	if _, err := images.ReplaceDocumentImages(context.Background(), db, store, docID, staged); err == nil {
+		t.Fatal("ReplaceDocumentImages() error = nil, want insert failure")
+	}
+
+	for _, key := range oldKeys {
+		if _, ok := store.data[key]; !ok {
+			t.Errorf("old image file %s was deleted after failed swap", key)
+		}
+	}
+	for _, img := range staged {
+		if _, ok := store.data[img.StorageKey]; ok {
+			t.Errorf("staged image file %s was not cleaned up", img.StorageKey)
+		}
+	}
+
+	current, err := images.DocumentImages(context.Background(), db, docID)
+	if err != nil {
+		t.Fatalf("DocumentImages() error = %v", err)
+	}
+
+	if len(current) != 2 {
+		t.Fatalf("DocumentImages() = %d images, want the 2 originals", len(current))
+	}
+	for _, img := range current {
+		if img.DPI != 150 {
+			t.Errorf("image DPI = %d, want original 150", img.DPI)
+		}
+	}
+}
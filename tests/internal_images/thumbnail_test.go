@@ -0,0 +1,207 @@
+package internal_images_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/images"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+	"github.com/JaimeStill/document-context/pkg/document"
+	"github.com/google/uuid"
+)
+
+// memStorage is an in-memory storage.System that counts stores per key.
+type memStorage struct {
+	data   map[string][]byte
+	stores map[string]int
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{data: map[string][]byte{}, stores: map[string]int{}}
+}
+
+func (s *memStorage) Store(ctx context.Context, key string, data []byte) error {
+	s.data[key] = data
+	s.stores[key]++
+	return nil
+}
+
+func (s *memStorage) StoreStream(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return s.Store(ctx, key, data)
+}
+
+func (s *memStorage) Retrieve(ctx context.Context, key string) ([]byte, error) {
+	data, ok := s.data[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return data, nil
+}
+
+func (s *memStorage) Delete(ctx context.Context, key string) error {
+	delete(s.data, key)
+	return nil
+}
+
+func (s *memStorage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	data, ok := s.data[srcKey]
+	if !ok {
+		return storage.ErrNotFound
+	}
+	s.data[dstKey] = data
+	return nil
+}
+
+func (s *memStorage) Move(ctx context.Context, srcKey, dstKey string) error {
+	if err := s.Copy(ctx, srcKey, dstKey); err != nil {
+		return err
+	}
+	if srcKey != dstKey {
+		delete(s.data, srcKey)
+	}
+	return nil
+}
+
+func (s *memStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+func (s *memStorage) Validate(ctx context.Context, key string) (bool, error) {
+	_, ok := s.data[key]
+	return ok, nil
+}
+
+func (s *memStorage) Start(lc *lifecycle.Coordinator) error { return nil }
+
+func (s *memStorage) Path(ctx context.Context, key string) (string, error) {
+	return key, nil
+}
+
+func testImage() images.Image {
+	docID := uuid.New()
+	return images.Image{
+		ID:         uuid.New(),
+		DocumentID: docID,
+		Format:     document.PNG,
+		StorageKey: "images/" + docID.String() + "/" + uuid.NewString() + ".png",
+	}
+}
+
+func TestLoadThumbnail_GeneratesThenReuses(t *testing.T) {
+	store := newMemStorage()
+	img := testImage()
+	store.data[img.StorageKey] = []byte("full-resolution")
+
+	var scaled int
+	scale := func(ctx context.Context, data []byte, format document.ImageFormat, maxDim int) ([]byte, error) {
+		scaled++
+		return []byte("thumb:" + string(data)), nil
+	}
+
+	first, err := images.LoadThumbnail(context.Background(), store, img, 256, scale)
+	if err != nil {
+		t.Fatalf("first LoadThumbnail() error = %v", err)
+	}
+
+	if string(first) != "thumb:full-resolution" {
+		t.Errorf("thumbnail = %q, want scaled source", first)
+	}
+
+	key := images.ThumbnailKey(img, 256)
+	if store.stores[key] != 1 {
+		t.Errorf("stores at %s = %d, want 1", key, store.stores[key])
+	}
+
+	second, err := images.LoadThumbnail(context.Background(), store, img, 256, scale)
+	if err != nil {
+		t.Fatalf("second LoadThumbnail() error = %v", err)
+	}
+
+	if string(second) != string(first) {
+		t.Errorf("second thumbnail = %q, want %q", second, first)
+	}
+
+	if scaled != 1 {
+		t.Errorf("scale calls = %d, want 1", scaled)
+	}
+
+	if store.stores[key] != 1 {
+		t.Errorf("thumbnail should not be stored again, stores = %d", store.stores[key])
+	}
+}
+
+func TestLoadThumbnail_CachedPerSize(t *testing.T) {
+	store := newMemStorage()
+	img := testImage()
+	store.data[img.StorageKey] = []byte("source")
+
+	var sizes []int
+	scale := func(ctx context.Context, data []byte, format document.ImageFormat, maxDim int) ([]byte, error) {
+		sizes = append(sizes, maxDim)
+		return data, nil
+	}
+
+	for _, size := range []int{128, 256, 128} {
+		if _, err := images.LoadThumbnail(context.Background(), store, img, size, scale); err != nil {
+			t.Fatalf("LoadThumbnail(%d) error = %v", size, err)
+		}
+	}
+
+	if len(sizes) != 2 || sizes[0] != 128 || sizes[1] != 256 {
+		t.Errorf("scaled sizes = %v, want [128 256]", sizes)
+	}
+
+	if images.ThumbnailKey(img, 128) == images.ThumbnailKey(img, 256) {
+		t.Error("thumbnail keys should differ by size")
+	}
+}
+
+func TestLoadThumbnail_InvalidSize(t *testing.T) {
+	tests := []int{0, 8, images.MaxThumbnailSize + 1}
+
+	for _, size := range tests {
+		_, err := images.LoadThumbnail(context.Background(), newMemStorage(), testImage(), size, nil)
+		if !errors.Is(err, images.ErrInvalidThumbnailSize) {
+			t.Errorf("LoadThumbnail(%d) error = %v, want ErrInvalidThumbnailSize", size, err)
+		}
+		if status := images.MapHTTPStatus(err); status != http.StatusBadRequest {
+			t.Errorf("MapHTTPStatus() = %d, want %d", status, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestLoadThumbnail_ScaleFailure(t *testing.T) {
+	store := newMemStorage()
+	img := testImage()
+	store.data[img.StorageKey] = []byte("source")
+
+	scale := func(ctx context.Context, data []byte, format document.ImageFormat, maxDim int) ([]byte, error) {
+		return nil, errors.New("magick exited")
+	}
+
+	_, err := images.LoadThumbnail(context.Background(), store, img, 256, scale)
+	if !errors.Is(err, images.ErrRenderFailed) {
+		t.Errorf("LoadThumbnail() error = %v, want ErrRenderFailed", err)
+	}
+
+	if _, ok := store.data[images.ThumbnailKey(img, 256)]; ok {
+		t.Error("failed thumbnail should not be stored")
+	}
+}
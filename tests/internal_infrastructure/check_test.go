@@ -0,0 +1,137 @@
+package internal_infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/infrastructure"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+type pingDriver struct{}
+
+func (pingDriver) Open(name string) (driver.Conn, error) { return pingConn{}, nil }
+
+type pingConn struct{}
+
+func (pingConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (pingConn) Close() error                              { return nil }
+func (pingConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+func (pingConn) Ping(ctx context.Context) error            { return nil }
+
+func init() {
+	sql.Register("checkping", pingDriver{})
+}
+
+type fakeDatabase struct {
+	db *sql.DB
+}
+
+func (f *fakeDatabase) Connection() *sql.DB                   { return f.db }
+func (f *fakeDatabase) Start(lc *lifecycle.Coordinator) error { return nil }
+
+func TestCheck_Valid(t *testing.T) {
+	db, err := sql.Open("checkping", "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	base := t.TempDir()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store, err := storage.New(&storage.Config{BasePath: base}, logger)
+	if err != nil {
+		t.Fatalf("storage.New() error = %v", err)
+	}
+
+	infra := &infrastructure.Infrastructure{Logger: logger, Database: &fakeDatabase{db: db}, Storage: store}
+
+	report := infra.Check(context.Background())
+	if !report.OK() {
+		var out bytes.Buffer
+		report.Write(&out)
+		t.Fatalf("Check() failed:\n%s", out.String())
+	}
+
+	var out bytes.Buffer
+	report.Write(&out)
+	if !strings.Contains(out.String(), "check passed: 2 checks") {
+		t.Errorf("summary = %q, want passed verdict", out.String())
+	}
+
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("storage probe left %d entries behind", len(entries))
+	}
+}
+
+func TestCheckConfig_Invalid(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	closedPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	// A base path beneath a regular file can never be created.
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Database.Host = "127.0.0.1"
+	cfg.Database.Port = closedPort
+	cfg.Database.Name = "agent_lab"
+	cfg.Database.User = "agent_lab"
+	cfg.Database.ConnTimeout = "2s"
+	cfg.Storage.BasePath = filepath.Join(blocker, "blobs")
+	if err := cfg.Finalize(); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	report := infrastructure.CheckConfig(ctx, cfg)
+	if report.OK() {
+		t.Fatal("CheckConfig() OK = true, want failures")
+	}
+
+	failed := map[string]bool{}
+	for _, res := range report.Results {
+		if res.Err != nil {
+			failed[res.Name] = true
+		}
+	}
+	for _, name := range []string{"database", "storage"} {
+		if !failed[name] {
+			t.Errorf("%s check passed, want failure", name)
+		}
+	}
+	if failed["config"] {
+		t.Error("config check failed, want pass for a finalized config")
+	}
+
+	var out bytes.Buffer
+	report.Write(&out)
+	if !strings.Contains(out.String(), "check failed: 2 of 3 checks failed") {
+		t.Errorf("summary = %q, want failed verdict", out.String())
+	}
+}
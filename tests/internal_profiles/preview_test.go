@@ -0,0 +1,166 @@
+package internal_profiles_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// classifyDefaults mirrors a workflow's hardcoded default profile.
+func classifyDefaults() *profiles.ProfileWithStages {
+	return profiles.NewProfileWithStages(
+		profiles.ProfileStage{StageName: "detect", SystemPrompt: strPtr("default detect prompt")},
+		profiles.ProfileStage{
+			StageName:    "classify",
+			SystemPrompt: strPtr("default classify prompt"),
+			Options:      json.RawMessage(`{"temperature":0.2}`),
+		},
+	)
+}
+
+// storedProfile overrides the classify stage with its own agent, prompt, and options.
+func storedProfile(agentID uuid.UUID) *profiles.ProfileWithStages {
+	p := profiles.NewProfileWithStages(profiles.ProfileStage{
+		StageName:    "classify",
+		AgentID:      &agentID,
+		SystemPrompt: strPtr("strict classify prompt"),
+		Options:      json.RawMessage(`{"temperature":0}`),
+	})
+	p.ID = uuid.New()
+	p.WorkflowName = "classify-docs"
+	return p
+}
+
+func TestPreviewStage_ReflectsOverride(t *testing.T) {
+	agentID := uuid.New()
+	profile := storedProfile(agentID)
+
+	got, err := profiles.PreviewStage(profile, classifyDefaults(), "classify")
+	if err != nil {
+		t.Fatalf("PreviewStage() error = %v", err)
+	}
+
+	if got.Source != profiles.PreviewSourceProfile {
+		t.Errorf("Source = %q, want %q", got.Source, profiles.PreviewSourceProfile)
+	}
+	if got.AgentID == nil || *got.AgentID != agentID {
+		t.Errorf("AgentID = %v, want %s", got.AgentID, agentID)
+	}
+	if got.SystemPrompt == nil || *got.SystemPrompt != "strict classify prompt" {
+		t.Errorf("SystemPrompt = %v, want the profile override", got.SystemPrompt)
+	}
+	if string(got.Options) != `{"temperature":0}` {
+		t.Errorf("Options = %s, want the profile override", got.Options)
+	}
+	if got.ProfileID != profile.ID || got.WorkflowName != "classify-docs" {
+		t.Errorf("preview identifies profile %s/%s, want %s/classify-docs", got.ProfileID, got.WorkflowName, profile.ID)
+	}
+}
+
+func TestPreviewStage_FallsBackToDefault(t *testing.T) {
+	profile := storedProfile(uuid.New())
+
+	got, err := profiles.PreviewStage(profile, classifyDefaults(), "detect")
+	if err != nil {
+		t.Fatalf("PreviewStage() error = %v", err)
+	}
+
+	if got.Source != profiles.PreviewSourceDefault {
+		t.Errorf("Source = %q, want %q", got.Source, profiles.PreviewSourceDefault)
+	}
+	if got.AgentID != nil {
+		t.Errorf("AgentID = %v, want nil (supplied by agent_id param)", got.AgentID)
+	}
+	if got.SystemPrompt == nil || *got.SystemPrompt != "default detect prompt" {
+		t.Errorf("SystemPrompt = %v, want the default prompt", got.SystemPrompt)
+	}
+}
+
+func TestPreviewStage_NoDefaults(t *testing.T) {
+	profile := storedProfile(uuid.New())
+
+	if _, err := profiles.PreviewStage(profile, nil, "classify"); err != nil {
+		t.Errorf("PreviewStage() error = %v, want profile stage without defaults", err)
+	}
+}
+
+func TestPreviewStage_UnknownStage(t *testing.T) {
+	_, err := profiles.PreviewStage(storedProfile(uuid.New()), classifyDefaults(), "missing")
+	if !errors.Is(err, profiles.ErrStageNotFound) {
+		t.Errorf("PreviewStage() error = %v, want ErrStageNotFound", err)
+	}
+}
+
+// previewSystem serves PreviewStage for a single stored profile.
+type previewSystem struct {
+	profiles.System
+	profile *profiles.ProfileWithStages
+}
+
+func (s *previewSystem) PreviewStage(ctx context.Context, id uuid.UUID, stage string) (*profiles.StagePreview, error) {
+	if id != s.profile.ID {
+		return nil, profiles.ErrNotFound
+	}
+	return profiles.PreviewStage(s.profile, classifyDefaults(), stage)
+}
+
+func servePreview(t *testing.T, sys profiles.System, id, stage string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := profiles.NewHandler(sys, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profiles/"+id+"/stages/"+stage+"/preview", nil)
+	req.SetPathValue("id", id)
+	req.SetPathValue("stage", stage)
+	rec := httptest.NewRecorder()
+
+	handler.PreviewStage(rec, req)
+	return rec
+}
+
+func TestHandler_PreviewStage(t *testing.T) {
+	sys := &previewSystem{profile: storedProfile(uuid.New())}
+
+	rec := servePreview(t, sys, sys.profile.ID.String(), "classify")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var got profiles.StagePreview
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode preview: %v", err)
+	}
+	if got.StageName != "classify" || got.SystemPrompt == nil || *got.SystemPrompt != "strict classify prompt" {
+		t.Errorf("preview = %+v, want the resolved classify stage", got)
+	}
+}
+
+func TestHandler_PreviewStage_NotFound(t *testing.T) {
+	sys := &previewSystem{profile: storedProfile(uuid.New())}
+
+	tests := []struct {
+		name  string
+		id    string
+		stage string
+	}{
+		{"unknown stage", sys.profile.ID.String(), "missing"},
+		{"unknown profile", uuid.NewString(), "classify"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := servePreview(t, sys, tt.id, tt.stage); rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusNotFound, rec.Body.String())
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package internal_profiles_test
 
 import (
+	"errors"
+	"net/http"
+	"slices"
 	"testing"
 
 	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/google/uuid"
 )
 
@@ -295,3 +299,94 @@ func TestProfileWithStages_Merge_UsesOtherMetadata(t *testing.T) {
 		t.Errorf("Merge() Name = %q, want %q", result.Name, "other-profile")
 	}
 }
+
+func TestProfileWithStages_SortStages(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []string
+		want  []string
+	}{
+		{"declared order", []string{"init", "detect", "classify", "score"}, []string{"init", "detect", "classify", "score", "zeta"}},
+		{"undeclared stages by name", []string{"score"}, []string{"score", "classify", "detect", "init", "zeta"}},
+		{"no order", nil, []string{"classify", "detect", "init", "score", "zeta"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := profiles.NewProfileWithStages(
+				profiles.ProfileStage{StageName: "zeta"},
+				profiles.ProfileStage{StageName: "score"},
+				profiles.ProfileStage{StageName: "detect"},
+				profiles.ProfileStage{StageName: "init"},
+				profiles.ProfileStage{StageName: "classify"},
+			)
+
+			p.SortStages(tt.order)
+
+			if got := p.StageNames(); !slices.Equal(got, tt.want) {
+				t.Errorf("SortStages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateProfileCommand_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		cmd        profiles.CreateProfileCommand
+		wantFields []string
+	}{
+		{"valid", profiles.CreateProfileCommand{WorkflowName: "classify-docs", Name: "baseline"}, nil},
+		{"missing workflow_name", profiles.CreateProfileCommand{Name: "baseline"}, []string{"workflow_name"}},
+		{"missing name", profiles.CreateProfileCommand{WorkflowName: "classify-docs", Name: " "}, []string{"name"}},
+		{"all missing", profiles.CreateProfileCommand{}, []string{"workflow_name", "name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate()
+
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *handlers.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			if len(verr.Fields) != len(tt.wantFields) {
+				t.Errorf("Fields = %v, want keys %v", verr.Fields, tt.wantFields)
+			}
+			for _, f := range tt.wantFields {
+				if verr.Fields[f] == "" {
+					t.Errorf("Fields[%q] missing in %v", f, verr.Fields)
+				}
+			}
+			if got := profiles.MapHTTPStatus(err); got != http.StatusBadRequest {
+				t.Errorf("MapHTTPStatus() = %d, want %d", got, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestPatchProfileCommand_Validate(t *testing.T) {
+	name := "renamed"
+	blank := "  "
+	desc := ""
+
+	if err := (profiles.PatchProfileCommand{}).Validate(); err != nil {
+		t.Errorf("Validate() empty patch error = %v, want nil", err)
+	}
+	if err := (profiles.PatchProfileCommand{Name: &name, Description: &desc}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+
+	err := profiles.PatchProfileCommand{Name: &blank}.Validate()
+	var verr *handlers.ValidationError
+	if !errors.As(err, &verr) || verr.Fields["name"] == "" {
+		t.Errorf("Validate() error = %v, want name field error", err)
+	}
+}
@@ -0,0 +1,186 @@
+package internal_profiles_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+var (
+	profileCols = []string{"id", "workflow_name", "name", "description", "created_at", "updated_at"}
+	stageCols   = []string{"profile_id", "stage_name", "agent_id", "system_prompt", "options"}
+)
+
+// fakeProfilesDB serves the profile and stage lookups of Find. Stages are
+// returned in insertion order, ignoring any ORDER BY, so results depend on
+// the ordering Find applies itself.
+type fakeProfilesDB struct {
+	profile profiles.Profile
+	stages  []string
+}
+
+var (
+	fakeProfilesMu sync.Mutex
+	fakeProfileDBs = map[string]*fakeProfilesDB{}
+)
+
+func init() {
+	sql.Register("fakeprofiles", fakeProfilesDriver{})
+}
+
+type fakeProfilesDriver struct{}
+
+func (fakeProfilesDriver) Open(dsn string) (driver.Conn, error) {
+	fakeProfilesMu.Lock()
+	defer fakeProfilesMu.Unlock()
+	return &fakeProfilesConn{db: fakeProfileDBs[dsn]}, nil
+}
+
+type fakeProfilesConn struct {
+	db *fakeProfilesDB
+}
+
+func (c *fakeProfilesConn) Prepare(q string) (driver.Stmt, error) {
+	return &fakeProfilesStmt{db: c.db, query: q}, nil
+}
+
+func (c *fakeProfilesConn) Close() error              { return nil }
+func (c *fakeProfilesConn) Begin() (driver.Tx, error) { return fakeProfilesTx{}, nil }
+
+type fakeProfilesTx struct{}
+
+func (fakeProfilesTx) Commit() error   { return nil }
+func (fakeProfilesTx) Rollback() error { return nil }
+
+type fakeProfilesStmt struct {
+	db    *fakeProfilesDB
+	query string
+}
+
+func (s *fakeProfilesStmt) Close() error  { return nil }
+func (s *fakeProfilesStmt) NumInput() int { return -1 }
+
+func (s *fakeProfilesStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+
+func (s *fakeProfilesStmt) Query(args []driver.Value) (driver.Rows, error) {
+	p := s.db.profile
+	if args[0].(string) != p.ID.String() {
+		return &fakeProfilesRows{cols: profileCols}, nil
+	}
+
+	if strings.Contains(s.query, "profile_stages") {
+		rows := &fakeProfilesRows{cols: stageCols}
+		for _, name := range s.db.stages {
+			rows.values = append(rows.values, []driver.Value{p.ID.String(), name, nil, nil, nil})
+		}
+		return rows, nil
+	}
+
+	return &fakeProfilesRows{cols: profileCols, values: [][]driver.Value{{
+		p.ID.String(), p.WorkflowName, p.Name, nil, p.CreatedAt, p.UpdatedAt,
+	}}}, nil
+}
+
+type fakeProfilesRows struct {
+	cols   []string
+	values [][]driver.Value
+	pos    int
+}
+
+func (r *fakeProfilesRows) Columns() []string { return r.cols }
+func (r *fakeProfilesRows) Close() error      { return nil }
+
+func (r *fakeProfilesRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+// newProfilesSystem creates a profiles system whose database holds a
+// classify-docs profile with stages inserted in the given order.
+func newProfilesSystem(t *testing.T, defaults profiles.DefaultsFunc, stages ...string) (profiles.System, uuid.UUID) {
+	t.Helper()
+
+	now := time.Now()
+	fdb := &fakeProfilesDB{
+		profile: profiles.Profile{ID: uuid.New(), WorkflowName: "classify-docs", Name: "tuned", CreatedAt: now, UpdatedAt: now},
+		stages:  stages,
+	}
+
+	fakeProfilesMu.Lock()
+	fakeProfileDBs[t.Name()] = fdb
+	fakeProfilesMu.Unlock()
+
+	db, err := sql.Open("fakeprofiles", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return profiles.New(db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, defaults), fdb.profile.ID
+}
+
+func TestFind_StagesInDeclaredOrder(t *testing.T) {
+	defaults := func(workflowName string) *profiles.ProfileWithStages {
+		if workflowName != "classify-docs" {
+			return nil
+		}
+		return profiles.NewProfileWithStages(
+			profiles.ProfileStage{StageName: "init"},
+			profiles.ProfileStage{StageName: "detect"},
+			profiles.ProfileStage{StageName: "enhance"},
+			profiles.ProfileStage{StageName: "classify"},
+			profiles.ProfileStage{StageName: "score"},
+		)
+	}
+
+	want := []string{"init", "detect", "classify", "score", "custom"}
+	inserts := [][]string{
+		{"score", "custom", "classify", "init", "detect"},
+		{"custom", "detect", "score", "init", "classify"},
+		{"init", "detect", "classify", "score", "custom"},
+	}
+
+	for _, order := range inserts {
+		t.Run(strings.Join(order, ","), func(t *testing.T) {
+			sys, id := newProfilesSystem(t, defaults, order...)
+
+			got, err := sys.Find(context.Background(), id)
+			if err != nil {
+				t.Fatalf("Find() error = %v", err)
+			}
+			if names := got.StageNames(); !slices.Equal(names, want) {
+				t.Errorf("Find() stages = %v, want %v", names, want)
+			}
+		})
+	}
+}
+
+func TestFind_StagesByNameWithoutDefaults(t *testing.T) {
+	sys, id := newProfilesSystem(t, nil, "score", "detect", "init")
+
+	got, err := sys.Find(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if names, want := got.StageNames(), []string{"detect", "init", "score"}; !slices.Equal(names, want) {
+		t.Errorf("Find() stages = %v, want %v", names, want)
+	}
+}
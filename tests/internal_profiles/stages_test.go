@@ -0,0 +1,129 @@
+package internal_profiles_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// findSystem serves Find for a single stored profile.
+type findSystem struct {
+	profiles.System
+	profile *profiles.ProfileWithStages
+}
+
+func (s *findSystem) Find(ctx context.Context, id uuid.UUID) (*profiles.ProfileWithStages, error) {
+	if id != s.profile.ID {
+		return nil, profiles.ErrNotFound
+	}
+	return s.profile, nil
+}
+
+func stagesProfile() *profiles.ProfileWithStages {
+	p := profiles.NewProfileWithStages(
+		profiles.ProfileStage{StageName: "detect", SystemPrompt: strPtr("detect prompt")},
+		profiles.ProfileStage{StageName: "classify", Options: json.RawMessage(`{"temperature":0}`)},
+	)
+	p.ID = uuid.New()
+	p.WorkflowName = "classify-docs"
+	return p
+}
+
+func serveStages(t *testing.T, sys profiles.System, id, stage string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := profiles.NewHandler(sys, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+
+	path := "/api/profiles/" + id + "/stages"
+	if stage != "" {
+		path += "/" + stage
+	}
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.SetPathValue("id", id)
+	rec := httptest.NewRecorder()
+
+	if stage == "" {
+		handler.ListStages(rec, req)
+	} else {
+		req.SetPathValue("stage", stage)
+		handler.FindStage(rec, req)
+	}
+	return rec
+}
+
+func TestHandler_FindStage(t *testing.T) {
+	sys := &findSystem{profile: stagesProfile()}
+
+	rec := serveStages(t, sys, sys.profile.ID.String(), "classify")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var got profiles.ProfileStage
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode stage: %v", err)
+	}
+	if got.StageName != "classify" || string(got.Options) != `{"temperature":0}` {
+		t.Errorf("stage = %+v, want the stored classify stage", got)
+	}
+}
+
+func TestHandler_FindStage_NotFound(t *testing.T) {
+	sys := &findSystem{profile: stagesProfile()}
+
+	tests := []struct {
+		name  string
+		id    string
+		stage string
+	}{
+		{"unknown stage", sys.profile.ID.String(), "missing"},
+		{"unknown profile", uuid.NewString(), "classify"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serveStages(t, sys, tt.id, tt.stage); rec.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusNotFound, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ListStages(t *testing.T) {
+	sys := &findSystem{profile: stagesProfile()}
+
+	rec := serveStages(t, sys, sys.profile.ID.String(), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var got []profiles.ProfileStage
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode stages: %v", err)
+	}
+	if len(got) != 2 || got[0].StageName != "detect" || got[1].StageName != "classify" {
+		t.Errorf("stages = %+v, want detect and classify", got)
+	}
+}
+
+func TestHandler_ListStages_Empty(t *testing.T) {
+	p := profiles.NewProfileWithStages()
+	p.ID = uuid.New()
+
+	rec := serveStages(t, &findSystem{profile: p}, p.ID.String(), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if body := rec.Body.String(); body != "[]\n" && body != "[]" {
+		t.Errorf("body = %q, want empty array", body)
+	}
+}
@@ -0,0 +1,33 @@
+package internal_providers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/config"
+	"github.com/JaimeStill/agent-lab/internal/providers"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	cfg := config.ProviderHTTPConfig{TLSHandshakeTimeout: "3s", MaxIdleConns: 4, IdleConnTimeout: "45s", RequestTimeout: "20s"}
+
+	client := providers.NewHTTPClient(cfg)
+	if client.Timeout != 20*time.Second {
+		t.Errorf("Timeout = %s, want 20s", client.Timeout)
+	}
+
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Transport = %T, want *http.Transport", client.Transport)
+	}
+	if transport.TLSHandshakeTimeout != 3*time.Second {
+		t.Errorf("TLSHandshakeTimeout = %s, want 3s", transport.TLSHandshakeTimeout)
+	}
+	if transport.MaxIdleConns != 4 || transport.MaxIdleConnsPerHost != 4 {
+		t.Errorf("MaxIdleConns = %d/%d, want 4", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if transport.IdleConnTimeout != 45*time.Second {
+		t.Errorf("IdleConnTimeout = %s, want 45s", transport.IdleConnTimeout)
+	}
+}
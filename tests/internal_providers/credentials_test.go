@@ -0,0 +1,193 @@
+package internal_providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/providers"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+func TestProvider_CredentialsNeverSerialized(t *testing.T) {
+	p := ollamaProvider(t, "shared", "http://localhost:11434")
+	p.Credentials = map[string]string{"token": "top-secret"}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	if strings.Contains(string(data), "top-secret") || strings.Contains(string(data), "credentials") {
+		t.Errorf("serialized provider exposes credentials: %s", data)
+	}
+}
+
+func TestProvider_ProviderConfig(t *testing.T) {
+	p := providers.Provider{
+		ID:          uuid.New(),
+		Config:      json.RawMessage(`{"name": "azure", "base_url": "https://example.test", "options": {"deployment": "gpt-4o", "token": "embedded"}}`),
+		Credentials: map[string]string{"token": "rotated"},
+	}
+
+	cfg, err := p.ProviderConfig()
+	if err != nil {
+		t.Fatalf("ProviderConfig() error = %v", err)
+	}
+
+	if cfg.Name != "azure" || cfg.BaseURL != "https://example.test" {
+		t.Errorf("ProviderConfig() = %+v, want stored name and base_url", cfg)
+	}
+	if cfg.Options["deployment"] != "gpt-4o" {
+		t.Errorf("options[deployment] = %v, want gpt-4o", cfg.Options["deployment"])
+	}
+	if cfg.Options["token"] != "rotated" {
+		t.Errorf("options[token] = %v, want credential to override config", cfg.Options["token"])
+	}
+}
+
+func TestProvider_ProviderConfig_InvalidConfig(t *testing.T) {
+	p := providers.Provider{Config: json.RawMessage(`not json`)}
+
+	if _, err := p.ProviderConfig(); !errors.Is(err, providers.ErrInvalidConfig) {
+		t.Errorf("ProviderConfig() error = %v, want ErrInvalidConfig", err)
+	}
+}
+
+func TestCredentialsCommand_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		creds      map[string]string
+		wantFields []string
+	}{
+		{"valid token", map[string]string{"token": "abc"}, nil},
+		{"valid api key", map[string]string{"api_key": "abc"}, nil},
+		{"empty", nil, []string{"credentials"}},
+		{"blank value", map[string]string{"token": "  "}, []string{"credentials.token"}},
+		{"non-secret key", map[string]string{"deployment": "gpt-4o"}, []string{"credentials.deployment"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := providers.CredentialsCommand{Credentials: tt.creds}.Validate()
+
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var verr *handlers.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			for _, f := range tt.wantFields {
+				if _, ok := verr.Fields[f]; !ok {
+					t.Errorf("Fields = %v, missing %q", verr.Fields, f)
+				}
+			}
+		})
+	}
+}
+
+// rotateSpy records RotateCredentials calls; other System methods are not used.
+type rotateSpy struct {
+	providers.System
+	id    uuid.UUID
+	cmd   providers.CredentialsCommand
+	calls int
+}
+
+func (s *rotateSpy) RotateCredentials(ctx context.Context, id uuid.UUID, cmd providers.CredentialsCommand) error {
+	s.calls++
+	if id != s.id {
+		return providers.ErrNotFound
+	}
+	if err := cmd.Validate(); err != nil {
+		return err
+	}
+	s.cmd = cmd
+	return nil
+}
+
+func rotateRequest(t *testing.T, spy *rotateSpy, id, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	handler := providers.NewHandler(spy, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/providers/"+id+"/credentials", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.SetPathValue("id", id)
+	rec := httptest.NewRecorder()
+
+	handler.RotateCredentials(rec, req)
+	return rec
+}
+
+func TestHandler_RotateCredentials(t *testing.T) {
+	spy := &rotateSpy{id: uuid.New()}
+
+	rec := rotateRequest(t, spy, spy.id.String(), `{"credentials": {"token": "new-secret"}}`)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body.String())
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty so credentials are never echoed", rec.Body.String())
+	}
+	if spy.cmd.Credentials["token"] != "new-secret" {
+		t.Errorf("rotated credentials = %v, want token new-secret", spy.cmd.Credentials)
+	}
+}
+
+func TestHandler_RotateCredentials_Errors(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		wantStatus int
+	}{
+		{"invalid id", "not-a-uuid", `{"credentials": {"token": "x"}}`, http.StatusBadRequest},
+		{"malformed body", id.String(), `{`, http.StatusBadRequest},
+		{"non-secret key", id.String(), `{"credentials": {"base_url": "x"}}`, http.StatusBadRequest},
+		{"unknown provider", uuid.NewString(), `{"credentials": {"token": "x"}}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := rotateRequest(t, &rotateSpy{id: id}, tt.id, tt.body)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if strings.Contains(rec.Body.String(), `"x"`) {
+				t.Errorf("error response echoes the credential: %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_Routes_Credentials(t *testing.T) {
+	handler := providers.NewHandler(&rotateSpy{}, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{})
+
+	for _, r := range handler.Routes().Routes {
+		if r.Method == "PUT" && r.Pattern == "/{id}/credentials" {
+			if r.OpenAPI != providers.Spec.Rotate {
+				t.Error("credentials route should document Spec.Rotate")
+			}
+			return
+		}
+	}
+	t.Error("PUT /{id}/credentials route not registered")
+}
@@ -45,6 +45,11 @@ func TestMapHTTPStatus(t *testing.T) {
 			fmt.Errorf("failed: %w", providers.ErrInvalidConfig),
 			http.StatusBadRequest,
 		},
+		{
+			"in use error",
+			providers.ErrInUse,
+			http.StatusConflict,
+		},
 		{
 			"unknown error",
 			errors.New("unknown error"),
@@ -76,6 +81,7 @@ func TestErrorValues(t *testing.T) {
 		{"ErrNotFound", providers.ErrNotFound, "provider not found"},
 		{"ErrDuplicate", providers.ErrDuplicate, "provider name already exists"},
 		{"ErrInvalidConfig", providers.ErrInvalidConfig, "invalid provider config"},
+		{"ErrInUse", providers.ErrInUse, "provider is referenced by agents"},
 	}
 
 	for _, tt := range tests {
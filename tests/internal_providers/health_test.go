@@ -0,0 +1,311 @@
+package internal_providers_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/providers"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+func ollamaProvider(t *testing.T, name, baseURL string) providers.Provider {
+	t.Helper()
+
+	cfg, err := json.Marshal(map[string]any{
+		"name":     "ollama",
+		"base_url": baseURL,
+		"options":  map[string]any{},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	return providers.Provider{ID: uuid.New(), Name: name, Config: cfg, UpdatedAt: time.Now()}
+}
+
+// unreachableURL returns the address of a listener that has been closed,
+// so connections to it are refused.
+func unreachableURL(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error = %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return "http://" + addr
+}
+
+func TestTestConnection(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	unauthorized := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer unauthorized.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		want    providers.HealthStatus
+	}{
+		{"healthy", healthy.URL, providers.HealthOK},
+		{"unauthorized", unauthorized.URL, providers.HealthAuthFailed},
+		{"server error", failing.URL, providers.HealthUnreachable},
+		{"refused", unreachableURL(t), providers.HealthUnreachable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ollamaProvider(t, tt.name, tt.baseURL)
+
+			got := providers.TestConnection(context.Background(), http.DefaultClient, p)
+
+			if got.Status != tt.want {
+				t.Errorf("Status = %q, want %q (error: %s)", got.Status, tt.want, got.Error)
+			}
+			if got.ID != p.ID || got.Name != p.Name {
+				t.Errorf("result identity = %s/%s, want %s/%s", got.ID, got.Name, p.ID, p.Name)
+			}
+			if got.CheckedAt.IsZero() {
+				t.Error("CheckedAt is zero")
+			}
+			if tt.want != providers.HealthOK && got.Error == "" {
+				t.Error("Error is empty for failed probe")
+			}
+		})
+	}
+}
+
+func TestTestConnection_InvalidConfig(t *testing.T) {
+	p := providers.Provider{ID: uuid.New(), Name: "broken", Config: json.RawMessage(`{"name":"nonexistent"}`)}
+
+	got := providers.TestConnection(context.Background(), http.DefaultClient, p)
+	if got.Status != providers.HealthUnreachable {
+		t.Errorf("Status = %q, want %q", got.Status, providers.HealthUnreachable)
+	}
+}
+
+func TestHealthChecker_Aggregates(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	probe := func(ctx context.Context, p providers.Provider) providers.ProviderHealth {
+		return providers.TestConnection(ctx, http.DefaultClient, p)
+	}
+	checker := providers.NewHealthChecker(probe, time.Second, time.Minute)
+
+	report := checker.Check(context.Background(), []providers.Provider{
+		ollamaProvider(t, "b-unreachable", unreachableURL(t)),
+		ollamaProvider(t, "a-healthy", healthy.URL),
+	})
+
+	if report.Healthy {
+		t.Error("Healthy = true, want false")
+	}
+	if len(report.Providers) != 2 {
+		t.Fatalf("len(Providers) = %d, want 2", len(report.Providers))
+	}
+	if report.Providers[0].Name != "a-healthy" || report.Providers[0].Status != providers.HealthOK {
+		t.Errorf("Providers[0] = %s/%s, want a-healthy/ok", report.Providers[0].Name, report.Providers[0].Status)
+	}
+	if report.Providers[1].Name != "b-unreachable" || report.Providers[1].Status != providers.HealthUnreachable {
+		t.Errorf("Providers[1] = %s/%s, want b-unreachable/unreachable", report.Providers[1].Name, report.Providers[1].Status)
+	}
+}
+
+func TestHealthChecker_ProbesConcurrentlyWithinTimeout(t *testing.T) {
+	const timeout = 200 * time.Millisecond
+
+	var active, peak atomic.Int32
+	probe := func(ctx context.Context, p providers.Provider) providers.ProviderHealth {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			cur := peak.Load()
+			if n <= cur || peak.CompareAndSwap(cur, n) {
+				break
+			}
+		}
+
+		// Simulate a hung provider: block until the probe deadline.
+		<-ctx.Done()
+		return providers.ProviderHealth{ID: p.ID, Name: p.Name, Status: providers.HealthUnreachable, Error: ctx.Err().Error()}
+	}
+	checker := providers.NewHealthChecker(probe, timeout, time.Minute)
+
+	list := make([]providers.Provider, 4)
+	for i := range list {
+		list[i] = providers.Provider{ID: uuid.New(), Name: uuid.NewString()}
+	}
+
+	start := time.Now()
+	report := checker.Check(context.Background(), list)
+	elapsed := time.Since(start)
+
+	if elapsed >= 2*timeout {
+		t.Errorf("Check() took %v, want < %v (probes should run concurrently)", elapsed, 2*timeout)
+	}
+	if got := peak.Load(); got != int32(len(list)) {
+		t.Errorf("peak concurrent probes = %d, want %d", got, len(list))
+	}
+	for _, p := range report.Providers {
+		if p.Status != providers.HealthUnreachable {
+			t.Errorf("%s Status = %q, want %q", p.Name, p.Status, providers.HealthUnreachable)
+		}
+	}
+}
+
+func TestHealthChecker_CachesResults(t *testing.T) {
+	var calls atomic.Int32
+	probe := func(ctx context.Context, p providers.Provider) providers.ProviderHealth {
+		calls.Add(1)
+		return providers.ProviderHealth{ID: p.ID, Name: p.Name, Status: providers.HealthOK}
+	}
+	checker := providers.NewHealthChecker(probe, time.Second, time.Minute)
+
+	p := providers.Provider{ID: uuid.New(), Name: "cached", UpdatedAt: time.Now()}
+
+	checker.Check(context.Background(), []providers.Provider{p})
+	checker.Check(context.Background(), []providers.Provider{p})
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("probe calls = %d, want 1 (second check should be cached)", got)
+	}
+
+	p.UpdatedAt = p.UpdatedAt.Add(time.Second)
+	checker.Check(context.Background(), []providers.Provider{p})
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("probe calls = %d, want 2 (updated provider should be re-probed)", got)
+	}
+}
+
+func TestHealthChecker_CacheExpires(t *testing.T) {
+	var calls atomic.Int32
+	probe := func(ctx context.Context, p providers.Provider) providers.ProviderHealth {
+		calls.Add(1)
+		return providers.ProviderHealth{ID: p.ID, Name: p.Name, Status: providers.HealthOK}
+	}
+	checker := providers.NewHealthChecker(probe, time.Second, 20*time.Millisecond)
+
+	p := providers.Provider{ID: uuid.New(), Name: "expiring"}
+
+	checker.Check(context.Background(), []providers.Provider{p})
+	time.Sleep(40 * time.Millisecond)
+	checker.Check(context.Background(), []providers.Provider{p})
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("probe calls = %d, want 2", got)
+	}
+}
+
+func TestHealthChecker_RefreshBypassesAndUpdatesCache(t *testing.T) {
+	var calls atomic.Int32
+	status := providers.HealthUnreachable
+	probe := func(ctx context.Context, p providers.Provider) providers.ProviderHealth {
+		calls.Add(1)
+		return providers.ProviderHealth{ID: p.ID, Name: p.Name, Status: status}
+	}
+	checker := providers.NewHealthChecker(probe, time.Second, time.Minute)
+
+	p := providers.Provider{ID: uuid.New(), Name: "fixed", UpdatedAt: time.Now()}
+
+	checker.Check(context.Background(), []providers.Provider{p})
+
+	// The provider is fixed out of band; a cached check still reports the stale result.
+	status = providers.HealthOK
+	if report := checker.Check(context.Background(), []providers.Provider{p}); report.Healthy {
+		t.Error("cached Check() Healthy = true, want stale false")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("probe calls = %d, want 1 (second check should be cached)", got)
+	}
+
+	if report := checker.Refresh(context.Background(), []providers.Provider{p}); !report.Healthy {
+		t.Error("Refresh() Healthy = false, want true")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("probe calls = %d, want 2 (refresh should bypass cache)", got)
+	}
+
+	if report := checker.Check(context.Background(), []providers.Provider{p}); !report.Healthy {
+		t.Error("Check() after Refresh() Healthy = false, want refreshed result")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("probe calls = %d, want 2 (refreshed result should be cached)", got)
+	}
+}
+
+// healthSpy serves Health from a HealthChecker over a fixed provider list.
+type healthSpy struct {
+	providers.System
+	checker *providers.HealthChecker
+	list    []providers.Provider
+}
+
+func (s *healthSpy) Health(ctx context.Context, refresh bool) (*providers.HealthReport, error) {
+	if refresh {
+		return s.checker.Refresh(ctx, s.list), nil
+	}
+	return s.checker.Check(ctx, s.list), nil
+}
+
+func TestHandler_Health_Refresh(t *testing.T) {
+	var calls atomic.Int32
+	probe := func(ctx context.Context, p providers.Provider) providers.ProviderHealth {
+		calls.Add(1)
+		return providers.ProviderHealth{ID: p.ID, Name: p.Name, Status: providers.HealthOK}
+	}
+	spy := &healthSpy{
+		checker: providers.NewHealthChecker(probe, time.Second, time.Minute),
+		list:    []providers.Provider{{ID: uuid.New(), Name: "probed", UpdatedAt: time.Now()}},
+	}
+	handler := providers.NewHandler(spy, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{})
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantCalls  int32
+	}{
+		{"", http.StatusOK, 1},
+		{"", http.StatusOK, 1},
+		{"?refresh=true", http.StatusOK, 2},
+		{"?refresh=false", http.StatusOK, 2},
+		{"?refresh=maybe", http.StatusBadRequest, 2},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/providers/health"+tt.query, nil)
+		rec := httptest.NewRecorder()
+
+		handler.Health(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%q: status = %d, want %d: %s", tt.query, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if got := calls.Load(); got != tt.wantCalls {
+			t.Errorf("%q: probe calls = %d, want %d", tt.query, got, tt.wantCalls)
+		}
+	}
+}
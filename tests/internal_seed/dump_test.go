@@ -0,0 +1,175 @@
+package internal_seed_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/internal/seed"
+	"github.com/google/uuid"
+)
+
+const secret = "sk-super-secret"
+
+func testSnapshot() *seed.Snapshot {
+	agentID := uuid.New()
+	providerID := uuid.New()
+	profileID := uuid.New()
+	prompt := "Detect markings"
+	desc := "Default profile"
+
+	return &seed.Snapshot{
+		Agents: []seed.AgentSeed{
+			{
+				ID:       &agentID,
+				Name:     "azure-agent",
+				Provider: "azure",
+				Config: json.RawMessage(`{
+					"name": "azure-agent",
+					"provider": {"name": "azure", "base_url": "https://example.openai.azure.com", "options": {"deployment": "gpt-4o", "token": "` + secret + `"}},
+					"model": {"name": "gpt-4o"}
+				}`),
+			},
+		},
+		Providers: []seed.ProviderSeed{
+			{
+				ID:     &providerID,
+				Name:   "azure",
+				Config: json.RawMessage(`{"name": "azure", "base_url": "https://example.openai.azure.com", "options": {"api_key": "` + secret + `", "api_version": "2024-08-01"}}`),
+			},
+		},
+		Profiles: []profiles.ProfileWithStages{
+			{
+				Profile: profiles.Profile{ID: profileID, WorkflowName: "classify-docs", Name: "default", Description: &desc},
+				Stages: []profiles.ProfileStage{
+					{ProfileID: profileID, StageName: "detect", AgentID: &agentID, SystemPrompt: &prompt},
+				},
+			},
+		},
+	}
+}
+
+func canonical(t *testing.T, raw json.RawMessage) any {
+	t.Helper()
+
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		t.Fatalf("unmarshal config: %v", err)
+	}
+	return v
+}
+
+func TestDump_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	snap := testSnapshot()
+
+	if err := seed.WriteSnapshot(dir, snap); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+
+	agentSeeder := seed.NewAgentSeeder(nil)
+	agentSeeder.SetFile(filepath.Join(dir, seed.AgentsFile))
+	agentData, err := agentSeeder.Load()
+	if err != nil {
+		t.Fatalf("AgentSeeder.Load() error = %v", err)
+	}
+
+	providerSeeder := seed.NewProviderSeeder(nil)
+	providerSeeder.SetFile(filepath.Join(dir, seed.ProvidersFile))
+	providerData, err := providerSeeder.Load()
+	if err != nil {
+		t.Fatalf("ProviderSeeder.Load() error = %v", err)
+	}
+
+	profileSeeder := seed.NewProfileSeeder(nil)
+	profileSeeder.SetFile(filepath.Join(dir, seed.ProfilesFile))
+	profileData, err := profileSeeder.Load()
+	if err != nil {
+		t.Fatalf("ProfileSeeder.Load() error = %v", err)
+	}
+
+	if len(agentData.Agents) != 1 {
+		t.Fatalf("len(Agents) = %d, want 1", len(agentData.Agents))
+	}
+	gotAgent := agentData.Agents[0]
+	if *gotAgent.ID != *snap.Agents[0].ID || gotAgent.Name != snap.Agents[0].Name {
+		t.Errorf("agent = %s/%s, want %s/%s", gotAgent.ID, gotAgent.Name, snap.Agents[0].ID, snap.Agents[0].Name)
+	}
+	if gotAgent.Provider != "azure" {
+		t.Errorf("agent provider = %q, want %q", gotAgent.Provider, "azure")
+	}
+
+	wantAgent := canonical(t, snap.Agents[0].Config)
+	delete(wantAgent.(map[string]any)["provider"].(map[string]any)["options"].(map[string]any), "token")
+	if got := canonical(t, gotAgent.Config); !reflect.DeepEqual(got, wantAgent) {
+		t.Errorf("agent config = %v, want %v", got, wantAgent)
+	}
+
+	if len(providerData.Providers) != 1 {
+		t.Fatalf("len(Providers) = %d, want 1", len(providerData.Providers))
+	}
+	wantProvider := canonical(t, snap.Providers[0].Config)
+	delete(wantProvider.(map[string]any)["options"].(map[string]any), "api_key")
+	if got := canonical(t, providerData.Providers[0].Config); !reflect.DeepEqual(got, wantProvider) {
+		t.Errorf("provider config = %v, want %v", got, wantProvider)
+	}
+
+	if len(profileData.Profiles) != 1 {
+		t.Fatalf("len(Profiles) = %d, want 1", len(profileData.Profiles))
+	}
+	gotProfile := profileData.Profiles[0]
+	if gotProfile.WorkflowName != "classify-docs" || gotProfile.Name != "default" || *gotProfile.Description != "Default profile" {
+		t.Errorf("profile = %+v, want classify-docs/default", gotProfile.Profile)
+	}
+	if len(gotProfile.Stages) != 1 || *gotProfile.Stages[0].AgentID != *snap.Agents[0].ID {
+		t.Errorf("stages = %+v, want detect stage referencing dumped agent", gotProfile.Stages)
+	}
+}
+
+func TestDump_RedactsSecrets(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := seed.WriteSnapshot(dir, testSnapshot()); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+
+	for _, name := range []string{seed.AgentsFile, seed.ProvidersFile, seed.ProfilesFile} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if strings.Contains(string(content), secret) {
+			t.Errorf("%s contains a credential", name)
+		}
+	}
+}
+
+func TestRedactConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"nested token", `{"options": {"token": "x", "deployment": "d"}}`, `{"options": {"deployment": "d"}}`},
+		{"case insensitive", `{"API_KEY": "x", "name": "n"}`, `{"name": "n"}`},
+		{"within arrays", `{"items": [{"password": "x", "id": 1}]}`, `{"items": [{"id": 1}]}`},
+		{"no secrets", `{"name": "ollama"}`, `{"name": "ollama"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := seed.RedactConfig(json.RawMessage(tt.config))
+			if err != nil {
+				t.Fatalf("RedactConfig() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(canonical(t, got), canonical(t, json.RawMessage(tt.want))) {
+				t.Errorf("RedactConfig() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
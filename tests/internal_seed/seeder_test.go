@@ -0,0 +1,256 @@
+package internal_seed_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/seed"
+	"github.com/google/uuid"
+)
+
+// fakeStore records natural keys per table so seeders can detect existing rows.
+type fakeStore struct {
+	mu   sync.Mutex
+	keys map[string]map[string]bool
+}
+
+var (
+	fakeStoresMu sync.Mutex
+	fakeStores   = map[string]*fakeStore{}
+
+	existsPattern = regexp.MustCompile(`FROM (\w+) WHERE`)
+	lookupPattern = regexp.MustCompile(`^SELECT id FROM (\w+) WHERE`)
+	insertPattern = regexp.MustCompile(`INSERT INTO (\w+)`)
+)
+
+func init() {
+	sql.Register("fakeseed", fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fakeStoresMu.Lock()
+	defer fakeStoresMu.Unlock()
+	return &fakeConn{store: fakeStores[dsn]}, nil
+}
+
+type fakeConn struct {
+	store *fakeStore
+}
+
+func (c *fakeConn) Prepare(q string) (driver.Stmt, error) {
+	return &fakeStmt{store: c.store, query: q}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	store *fakeStore
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+// key derives a table's natural key from insert arguments: name for agents
+// and providers, workflow_name and name for profiles.
+func key(table string, args []driver.Value) string {
+	if table == "profiles" {
+		return fmt.Sprint(args[1], "/", args[2])
+	}
+	return fmt.Sprint(args[1])
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.insert(args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if m := lookupPattern.FindStringSubmatch(s.query); m != nil {
+		name := fmt.Sprint(args[0])
+
+		s.store.mu.Lock()
+		found := s.store.keys[m[1]][name]
+		s.store.mu.Unlock()
+
+		rows := &fakeRows{cols: []string{"id"}}
+		if found {
+			rows.values = [][]driver.Value{{uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String()}}
+		}
+		return rows, nil
+	}
+
+	if m := existsPattern.FindStringSubmatch(s.query); m != nil && strings.Contains(s.query, "EXISTS") {
+		parts := make([]string, len(args))
+		for i, a := range args {
+			parts[i] = fmt.Sprint(a)
+		}
+
+		s.store.mu.Lock()
+		found := s.store.keys[m[1]][strings.Join(parts, "/")]
+		s.store.mu.Unlock()
+
+		return &fakeRows{cols: []string{"exists"}, values: [][]driver.Value{{found}}}, nil
+	}
+
+	s.insert(args)
+	return &fakeRows{cols: []string{"id"}, values: [][]driver.Value{{args[0]}}}, nil
+}
+
+func (s *fakeStmt) insert(args []driver.Value) {
+	m := insertPattern.FindStringSubmatch(s.query)
+	if m == nil || m[1] == "profile_stages" {
+		return
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if s.store.keys[m[1]] == nil {
+		s.store.keys[m[1]] = map[string]bool{}
+	}
+	s.store.keys[m[1]][key(m[1], args)] = true
+}
+
+type fakeRows struct {
+	cols   []string
+	values [][]driver.Value
+	pos    int
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+func openFakeDB(t *testing.T) (*sql.DB, *fakeStore) {
+	t.Helper()
+
+	store := &fakeStore{keys: map[string]map[string]bool{}}
+
+	fakeStoresMu.Lock()
+	fakeStores[t.Name()] = store
+	fakeStoresMu.Unlock()
+
+	db, err := sql.Open("fakeseed", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, store
+}
+
+// seeders returns seeders loaded from a dumped test snapshot.
+func seeders(t *testing.T) []seed.Seeder {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := seed.WriteSnapshot(dir, testSnapshot()); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+
+	return []seed.Seeder{
+		seed.NewProviderSeeder(mustRead(t, filepath.Join(dir, seed.ProvidersFile))),
+		seed.NewAgentSeeder(mustRead(t, filepath.Join(dir, seed.AgentsFile))),
+		seed.NewProfileSeeder(mustRead(t, filepath.Join(dir, seed.ProfilesFile))),
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return content
+}
+
+func TestApply_SeedingTwiceUpdates(t *testing.T) {
+	db, _ := openFakeDB(t)
+	ctx := context.Background()
+
+	for _, s := range seeders(t) {
+		t.Run(s.Name(), func(t *testing.T) {
+			first, err := s.Apply(ctx, db, false)
+			if err != nil {
+				t.Fatalf("first Apply() error = %v", err)
+			}
+			if first.String() != "1 created, 0 updated" {
+				t.Errorf("first Apply() = %q, want %q", first, "1 created, 0 updated")
+			}
+
+			second, err := s.Apply(ctx, db, false)
+			if err != nil {
+				t.Fatalf("second Apply() error = %v", err)
+			}
+			if second.String() != "0 created, 1 updated" {
+				t.Errorf("second Apply() = %q, want %q", second, "0 created, 1 updated")
+			}
+		})
+	}
+}
+
+func TestApply_DryRunDoesNotWrite(t *testing.T) {
+	db, store := openFakeDB(t)
+	ctx := context.Background()
+
+	for _, s := range seeders(t) {
+		t.Run(s.Name(), func(t *testing.T) {
+			for range 2 {
+				result, err := s.Apply(ctx, db, true)
+				if err != nil {
+					t.Fatalf("Apply() error = %v", err)
+				}
+				if result != (seed.Result{Created: 1}) {
+					t.Errorf("Apply() = %+v, want 1 created", result)
+				}
+			}
+		})
+	}
+
+	if len(store.keys) != 0 {
+		t.Errorf("dry run wrote %v", store.keys)
+	}
+}
+
+func TestApply_AgentUnknownProvider(t *testing.T) {
+	db, _ := openFakeDB(t)
+
+	dir := t.TempDir()
+	if err := seed.WriteSnapshot(dir, testSnapshot()); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+
+	agents := seed.NewAgentSeeder(mustRead(t, filepath.Join(dir, seed.AgentsFile)))
+
+	_, err := agents.Apply(context.Background(), db, false)
+	if err == nil || !strings.Contains(err.Error(), "provider azure not found") {
+		t.Errorf("Apply() error = %v, want provider not found", err)
+	}
+}
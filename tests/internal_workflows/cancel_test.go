@@ -0,0 +1,274 @@
+package internal_workflows_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+	"github.com/google/uuid"
+)
+
+const cancelWorkflow = "test-cancel-reason"
+
+func init() {
+	workflows.Register(cancelWorkflow, func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		graph.AddNode("first", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			return s, nil
+		}))
+		graph.AddNode("block", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			<-ctx.Done()
+			return s, ctx.Err()
+		}))
+		graph.AddEdge("first", "block", nil)
+		graph.SetEntryPoint("first")
+		graph.SetExitPoint("block")
+		return state.New(nil), nil
+	}, "Blocks until cancelled")
+}
+
+func newCancelSystem(t *testing.T, rows ...fakeRow) (workflows.System, *fakeDB) {
+	t.Helper()
+
+	now := time.Now()
+	fdb := &fakeDB{
+		run: fakeRow{
+			"workflow_name": cancelWorkflow,
+			"status":        string(workflows.StatusRunning),
+			"created_at":    now,
+			"updated_at":    now,
+		},
+		rows: rows,
+	}
+	db := openFakeDB(t, fdb)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	return workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0), fdb
+}
+
+// recordedCancelReasons returns the cancel_reason written by each UPDATE that
+// marked a run cancelled.
+func recordedCancelReasons(fdb *fakeDB) []string {
+	fdb.mu.Lock()
+	defer fdb.mu.Unlock()
+
+	var reasons []string
+	for _, u := range fdb.runUpdates {
+		if len(u.args) == 5 && u.args[0] == string(workflows.StatusCancelled) {
+			reason, _ := u.args[3].(string)
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons
+}
+
+func waitActive(t *testing.T, sys workflows.System, id uuid.UUID) {
+	t.Helper()
+
+	deadline := time.After(2 * time.Second)
+	for !slices.Contains(sys.ActiveRuns(), id) {
+		select {
+		case <-deadline:
+			t.Fatalf("ActiveRuns() = %v, want to contain %s", sys.ActiveRuns(), id)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestExecutor_Cancel_RecordsUserReason(t *testing.T) {
+	sys, fdb := newCancelSystem(t)
+
+	stream, run, err := sys.Execute(cancelWorkflow, nil, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	waitActive(t, sys, run.ID)
+
+	if err := sys.Cancel(context.Background(), run.ID, workflows.CancelUser); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	for range stream {
+	}
+
+	if got := recordedCancelReasons(fdb); !slices.Equal(got, []string{"user"}) {
+		t.Errorf("cancel reasons = %v, want [user]", got)
+	}
+}
+
+func TestExecutor_Drain_RecordsShutdownReason(t *testing.T) {
+	sys, fdb := newCancelSystem(t)
+
+	stream, run, err := sys.Execute(cancelWorkflow, nil, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	waitActive(t, sys, run.ID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := sys.Drain(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Drain() error = %v, want context.Canceled", err)
+	}
+	for range stream {
+	}
+
+	if got := recordedCancelReasons(fdb); !slices.Equal(got, []string{"shutdown"}) {
+		t.Errorf("cancel reasons = %v, want [shutdown]", got)
+	}
+}
+
+func TestShutdown_RecordsRunIgnoringDrainAsCancelled(t *testing.T) {
+	now := time.Now()
+	fdb := &fakeDB{run: fakeRow{
+		"workflow_name": cancelWorkflow,
+		"status":        string(workflows.StatusRunning),
+		"created_at":    now,
+		"updated_at":    now,
+	}}
+	db := openFakeDB(t, fdb)
+
+	lc := lifecycle.New()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lc, logger)
+	sys := workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	lc.OnDrain(func(ctx context.Context) {
+		sys.Drain(ctx)
+	})
+
+	reasonsAtClose := make(chan []string, 1)
+	lc.OnShutdown(func() {
+		<-lc.Context().Done()
+		reasonsAtClose <- recordedCancelReasons(fdb)
+	})
+
+	stream, run, err := sys.Execute(cancelWorkflow, nil, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	waitActive(t, sys, run.ID)
+
+	err = lc.Shutdown(400 * time.Millisecond)
+	if err == nil || strings.Contains(err.Error(), "shutdown timeout") {
+		t.Errorf("Shutdown() error = %v, want only a drain timeout", err)
+	}
+	for range stream {
+	}
+
+	if got := <-reasonsAtClose; !slices.Equal(got, []string{"shutdown"}) {
+		t.Errorf("cancel reasons before shutdown hooks = %v, want [shutdown]", got)
+	}
+}
+
+func TestExecutor_Resume_RecordsTimeoutReason(t *testing.T) {
+	runID := uuid.New()
+	now := time.Now()
+	sys, fdb := newCancelSystem(t,
+		checkpointRow(t, runID, "first", "checkpoint"),
+		fakeRow{
+			"id":            runID.String(),
+			"workflow_name": cancelWorkflow,
+			"status":        string(workflows.StatusFailed),
+			"created_at":    now,
+			"updated_at":    now,
+		},
+	)
+	fdb.run["id"] = runID.String()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := sys.Resume(ctx, runID, ""); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	if got := recordedCancelReasons(fdb); !slices.Equal(got, []string{"timeout"}) {
+		t.Errorf("cancel reasons = %v, want [timeout]", got)
+	}
+}
+
+func TestParseCancelReason(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    workflows.CancelReason
+		wantErr bool
+	}{
+		{"", workflows.CancelUser, false},
+		{"user", workflows.CancelUser, false},
+		{"shutdown", workflows.CancelShutdown, false},
+		{"timeout", workflows.CancelTimeout, false},
+		{"bored", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := workflows.ParseCancelReason(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, workflows.ErrInvalidCancelReason) {
+					t.Errorf("ParseCancelReason(%q) error = %v, want ErrInvalidCancelReason", tt.value, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseCancelReason(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
+
+// cancelSpy records the reason passed to Cancel; other System methods are not used.
+type cancelSpy struct {
+	workflows.System
+	reason workflows.CancelReason
+}
+
+func (s *cancelSpy) Cancel(ctx context.Context, runID uuid.UUID, reason workflows.CancelReason) error {
+	s.reason = reason
+	return nil
+}
+
+func TestHandler_Cancel_Reason(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantReason workflows.CancelReason
+	}{
+		{"default", "", http.StatusNoContent, workflows.CancelUser},
+		{"explicit", "?reason=shutdown", http.StatusNoContent, workflows.CancelShutdown},
+		{"invalid", "?reason=bored", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &cancelSpy{}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			handler := workflows.NewHandler(spy, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+			req := httptest.NewRequest(http.MethodPost, "/workflows/runs/x/cancel"+tt.query, nil)
+			req.SetPathValue("id", uuid.NewString())
+			rec := httptest.NewRecorder()
+
+			handler.Cancel(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if spy.reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", spy.reason, tt.wantReason)
+			}
+		})
+	}
+}
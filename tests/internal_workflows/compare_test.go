@@ -0,0 +1,94 @@
+package internal_workflows_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/google/uuid"
+)
+
+func completedRun(workflow, result string) *workflows.Run {
+	return &workflows.Run{
+		ID:           uuid.New(),
+		WorkflowName: workflow,
+		Status:       workflows.StatusCompleted,
+		Result:       json.RawMessage(result),
+	}
+}
+
+func TestCompareRuns_Generic(t *testing.T) {
+	a := completedRun("compare-generic-workflow", `{"answer":42,"tags":["x","y"]}`)
+	b := completedRun("compare-generic-workflow", `{ "tags": ["x", "y"], "answer": 42 }`)
+
+	got, err := workflows.CompareRuns(a, b)
+	if err != nil {
+		t.Fatalf("CompareRuns() error = %v", err)
+	}
+
+	cmp, ok := got.(workflows.RunComparison)
+	if !ok {
+		t.Fatalf("CompareRuns() type = %T, want RunComparison", got)
+	}
+	if cmp.RunA != a.ID || cmp.RunB != b.ID || cmp.WorkflowName != a.WorkflowName {
+		t.Errorf("comparison = %+v, want run metadata", cmp)
+	}
+	if !cmp.ResultsEqual {
+		t.Error("ResultsEqual = false, want true for equivalent JSON")
+	}
+
+	c := completedRun("compare-generic-workflow", `{"answer":41}`)
+	got, err = workflows.CompareRuns(a, c)
+	if err != nil {
+		t.Fatalf("CompareRuns() error = %v", err)
+	}
+	if got.(workflows.RunComparison).ResultsEqual {
+		t.Error("ResultsEqual = true, want false for differing results")
+	}
+}
+
+func TestCompareRuns_DifferentWorkflows(t *testing.T) {
+	a := completedRun("compare-workflow-a", `{}`)
+	b := completedRun("compare-workflow-b", `{}`)
+
+	_, err := workflows.CompareRuns(a, b)
+	if !errors.Is(err, workflows.ErrRunsNotComparable) {
+		t.Errorf("CompareRuns() error = %v, want ErrRunsNotComparable", err)
+	}
+}
+
+func TestCompareRuns_NotCompleted(t *testing.T) {
+	a := completedRun("compare-generic-workflow", `{}`)
+	b := &workflows.Run{ID: uuid.New(), WorkflowName: "compare-generic-workflow", Status: workflows.StatusRunning}
+
+	_, err := workflows.CompareRuns(a, b)
+	if !errors.Is(err, workflows.ErrRunNotCompleted) {
+		t.Errorf("CompareRuns() error = %v, want ErrRunNotCompleted", err)
+	}
+}
+
+type stubComparer struct{}
+
+func (stubComparer) Compare(a, b *workflows.Run) (any, error) {
+	return map[string]string{"stub": "compare"}, nil
+}
+
+func TestCompareRuns_RegisteredComparer(t *testing.T) {
+	workflows.RegisterComparer("compare-stub-workflow", stubComparer{})
+
+	if _, ok := workflows.GetComparer("compare-stub-workflow"); !ok {
+		t.Fatal("GetComparer() exists = false, want true")
+	}
+
+	got, err := workflows.CompareRuns(
+		completedRun("compare-stub-workflow", `{}`),
+		completedRun("compare-stub-workflow", `{}`),
+	)
+	if err != nil {
+		t.Fatalf("CompareRuns() error = %v", err)
+	}
+	if m, ok := got.(map[string]string); !ok || m["stub"] != "compare" {
+		t.Errorf("CompareRuns() = %v, want stub comparison", got)
+	}
+}
@@ -0,0 +1,96 @@
+package internal_workflows_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+)
+
+func TestDescribe(t *testing.T) {
+	factory := func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		for _, node := range []string{"fetch", "check", "fix", "done"} {
+			if err := graph.AddNode(node, nil); err != nil {
+				return state.State{}, err
+			}
+		}
+		if err := graph.AddEdge("fetch", "check", nil); err != nil {
+			return state.State{}, err
+		}
+		if err := workflows.AddLabeledEdge(graph, "check", "fix", state.KeyEquals("broken", true), workflows.EdgeLabel{Name: "broken", Reason: "needs repair"}); err != nil {
+			return state.State{}, err
+		}
+		if err := graph.AddEdge("check", "done", state.KeyEquals("broken", false)); err != nil {
+			return state.State{}, err
+		}
+		if err := graph.SetEntryPoint("fetch"); err != nil {
+			return state.State{}, err
+		}
+		if err := graph.SetExitPoint("fix"); err != nil {
+			return state.State{}, err
+		}
+		if err := graph.SetExitPoint("done"); err != nil {
+			return state.State{}, err
+		}
+		return state.New(nil), nil
+	}
+
+	workflows.Register("describe-test", factory, "Describe test workflow")
+	workflows.RegisterParams("describe-test", workflows.ProfileParams(map[string]*openapi.Schema{
+		"url": {Type: "string"},
+	}, "url"))
+
+	graph, err := workflows.Describe(context.Background(), "describe-test", nil)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	if graph.Name != "describe-test" || graph.Description != "Describe test workflow" {
+		t.Errorf("Name, Description = %q, %q", graph.Name, graph.Description)
+	}
+	if len(graph.Nodes) != 4 || graph.Nodes[0] != "fetch" || graph.Nodes[3] != "done" {
+		t.Errorf("Nodes = %v, want [fetch check fix done]", graph.Nodes)
+	}
+	if graph.EntryPoint != "fetch" {
+		t.Errorf("EntryPoint = %q, want fetch", graph.EntryPoint)
+	}
+	if len(graph.ExitPoints) != 2 || graph.ExitPoints[0] != "fix" || graph.ExitPoints[1] != "done" {
+		t.Errorf("ExitPoints = %v, want [fix done]", graph.ExitPoints)
+	}
+
+	want := []workflows.GraphEdge{
+		{From: "fetch", To: "check"},
+		{From: "check", To: "fix", Conditional: true, Label: "broken", Reason: "needs repair"},
+		{From: "check", To: "done", Conditional: true},
+	}
+	if len(graph.Edges) != len(want) {
+		t.Fatalf("Edges = %+v, want %+v", graph.Edges, want)
+	}
+	for i := range want {
+		if graph.Edges[i] != want[i] {
+			t.Errorf("Edges[%d] = %+v, want %+v", i, graph.Edges[i], want[i])
+		}
+	}
+
+	if graph.Params == nil {
+		t.Fatal("Params = nil, want registered schema")
+	}
+	for _, prop := range []string{"url", "profile_id", "agent_id"} {
+		if _, ok := graph.Params.Properties[prop]; !ok {
+			t.Errorf("Params missing property %q", prop)
+		}
+	}
+	if len(graph.Params.Required) != 1 || graph.Params.Required[0] != "url" {
+		t.Errorf("Params.Required = %v, want [url]", graph.Params.Required)
+	}
+}
+
+func TestDescribe_NotFound(t *testing.T) {
+	_, err := workflows.Describe(context.Background(), "nonexistent-workflow", nil)
+	if !errors.Is(err, workflows.ErrWorkflowNotFound) {
+		t.Errorf("Describe() error = %v, want ErrWorkflowNotFound", err)
+	}
+}
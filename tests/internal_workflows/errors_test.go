@@ -18,6 +18,19 @@ func TestMapHTTPStatus(t *testing.T) {
 		{"ErrNotFound", workflows.ErrNotFound, http.StatusNotFound},
 		{"ErrWorkflowNotFound", workflows.ErrWorkflowNotFound, http.StatusNotFound},
 		{"ErrInvalidStatus", workflows.ErrInvalidStatus, http.StatusBadRequest},
+		{"ErrDraining", workflows.ErrDraining, http.StatusServiceUnavailable},
+		{"ErrIncompleteProfile", workflows.ErrIncompleteProfile, http.StatusBadRequest},
+		{"ErrInvalidProfile", workflows.ErrInvalidProfile, http.StatusBadRequest},
+		{"ErrInvalidNode", workflows.ErrInvalidNode, http.StatusBadRequest},
+		{"ErrCheckpointNotFound", workflows.ErrCheckpointNotFound, http.StatusConflict},
+		{"ErrInvalidReportFormat", workflows.ErrInvalidReportFormat, http.StatusBadRequest},
+		{"ErrReportUnsupported", workflows.ErrReportUnsupported, http.StatusUnsupportedMediaType},
+		{"ErrReportUnavailable", workflows.ErrReportUnavailable, http.StatusConflict},
+		{"ErrInvalidTraceFormat", workflows.ErrInvalidTraceFormat, http.StatusBadRequest},
+		{"ErrInvalidFacet", workflows.ErrInvalidFacet, http.StatusBadRequest},
+		{"ErrInvalidComparison", workflows.ErrInvalidComparison, http.StatusBadRequest},
+		{"ErrRunsNotComparable", workflows.ErrRunsNotComparable, http.StatusBadRequest},
+		{"ErrRunNotCompleted", workflows.ErrRunNotCompleted, http.StatusConflict},
 		{"wrapped ErrNotFound", fmt.Errorf("wrapped: %w", workflows.ErrNotFound), http.StatusNotFound},
 		{"unknown error", errors.New("unknown"), http.StatusInternalServerError},
 		{"nil error", nil, http.StatusInternalServerError},
@@ -1,13 +1,23 @@
 package internal_workflows_test
 
 import (
+	"context"
+	"errors"
+	"io"
 	"log/slog"
 	"os"
+	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/internal/workflows"
 	_ "github.com/JaimeStill/agent-lab/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/events"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+	"github.com/google/uuid"
 )
 
 func TestNewSystem(t *testing.T) {
@@ -18,7 +28,7 @@ func TestNewSystem(t *testing.T) {
 		MaxPageSize:     100,
 	}
 
-	sys := workflows.NewSystem(runtime, nil, logger, paginationCfg)
+	sys := workflows.NewSystem(runtime, nil, nil, logger, paginationCfg, 0, 0)
 
 	if sys == nil {
 		t.Fatal("NewSystem() returned nil")
@@ -33,7 +43,7 @@ func TestNewSystem_ImplementsInterface(t *testing.T) {
 		MaxPageSize:     100,
 	}
 
-	var _ workflows.System = workflows.NewSystem(runtime, nil, logger, paginationCfg)
+	var _ workflows.System = workflows.NewSystem(runtime, nil, nil, logger, paginationCfg, 0, 0)
 }
 
 func TestExecutor_ListWorkflows(t *testing.T) {
@@ -44,7 +54,7 @@ func TestExecutor_ListWorkflows(t *testing.T) {
 		MaxPageSize:     100,
 	}
 
-	sys := workflows.NewSystem(runtime, nil, logger, paginationCfg)
+	sys := workflows.NewSystem(runtime, nil, nil, logger, paginationCfg, 0, 0)
 
 	infos := sys.ListWorkflows()
 	if infos == nil {
@@ -105,3 +115,247 @@ func TestWorkflows_Info(t *testing.T) {
 		}
 	}
 }
+
+func TestExecutor_Drain_RejectsNewExecutions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, nil, logger)
+	paginationCfg := pagination.Config{
+		DefaultPageSize: 20,
+		MaxPageSize:     100,
+	}
+
+	sys := workflows.NewSystem(runtime, nil, nil, logger, paginationCfg, 0, 0)
+
+	if err := sys.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if _, _, err := sys.Execute("summarize", nil, "", 0); !errors.Is(err, workflows.ErrDraining) {
+		t.Errorf("Execute() error = %v, want ErrDraining", err)
+	}
+
+	if _, err := sys.Resume(context.Background(), uuid.New(), ""); !errors.Is(err, workflows.ErrDraining) {
+		t.Errorf("Resume() error = %v, want ErrDraining", err)
+	}
+}
+
+func TestExecutor_Drain_WaitsForInFlightRun(t *testing.T) {
+	now := time.Now()
+	db := openFakeDB(t, &fakeDB{run: fakeRow{
+		"workflow_name": "test-drain-in-flight",
+		"status":        string(workflows.StatusRunning),
+		"created_at":    now,
+		"updated_at":    now,
+	}})
+
+	release := make(chan struct{})
+	workflows.Register("test-drain-in-flight", func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		graph.AddNode("block", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			<-release
+			return s, nil
+		}))
+		graph.SetEntryPoint("block")
+		graph.SetExitPoint("block")
+		return state.New(nil), nil
+	}, "Blocks until released")
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	stream, run, err := sys.Execute("test-drain-in-flight", nil, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for !slices.Contains(sys.ActiveRuns(), run.ID) {
+		select {
+		case <-deadline:
+			t.Fatalf("ActiveRuns() = %v, want to contain %s", sys.ActiveRuns(), run.ID)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	drained := make(chan error, 1)
+	go func() { drained <- sys.Drain(ctx) }()
+
+	for {
+		if _, err := sys.Resume(context.Background(), uuid.New(), ""); errors.Is(err, workflows.ErrDraining) {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("executor did not start draining")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	select {
+	case err := <-drained:
+		t.Fatalf("Drain() returned %v before the in-flight run finished", err)
+	default:
+	}
+
+	close(release)
+
+	var last workflows.ExecutionEvent
+	for event := range stream {
+		last = event
+	}
+
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Errorf("Drain() error = %v, want nil", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Drain() did not return after the in-flight run finished")
+	}
+
+	if last.Type != workflows.EventComplete {
+		t.Errorf("last event = %q, want %q", last.Type, workflows.EventComplete)
+	}
+
+	if slices.Contains(sys.ActiveRuns(), run.ID) {
+		t.Errorf("ActiveRuns() = %v, should not contain drained run %s", sys.ActiveRuns(), run.ID)
+	}
+}
+
+func TestExecutor_ActiveRuns(t *testing.T) {
+	runID := uuid.New()
+	now := time.Now()
+	db := openFakeDB(t, &fakeDB{run: fakeRow{
+		"id":            runID.String(),
+		"workflow_name": "test-active-runs",
+		"status":        string(workflows.StatusRunning),
+		"created_at":    now,
+		"updated_at":    now,
+	}})
+
+	release := make(chan struct{})
+	workflows.Register("test-active-runs", func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		<-release
+		return state.State{}, errors.New("released")
+	}, "Blocks until released")
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	untracked := uuid.New()
+
+	events, run, err := sys.Execute("test-active-runs", nil, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for !slices.Contains(sys.ActiveRuns(), run.ID) {
+		select {
+		case <-deadline:
+			t.Fatalf("ActiveRuns() = %v, want to contain %s", sys.ActiveRuns(), run.ID)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	if slices.Contains(sys.ActiveRuns(), untracked) {
+		t.Errorf("ActiveRuns() should not contain untracked run %s", untracked)
+	}
+
+	close(release)
+	for range events {
+	}
+
+	if slices.Contains(sys.ActiveRuns(), run.ID) {
+		t.Errorf("ActiveRuns() = %v, should not contain completed run %s", sys.ActiveRuns(), run.ID)
+	}
+}
+
+func TestExecutor_CancelAll(t *testing.T) {
+	const runCount = 3
+
+	now := time.Now()
+	db := openFakeDB(t, &fakeDB{run: fakeRow{
+		"workflow_name": "test-cancel-all",
+		"status":        string(workflows.StatusRunning),
+		"created_at":    now,
+		"updated_at":    now,
+	}})
+
+	workflows.Register("test-cancel-all", func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		graph.AddNode("block", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			<-ctx.Done()
+			return s, ctx.Err()
+		}))
+		graph.SetEntryPoint("block")
+		graph.SetExitPoint("block")
+		return state.New(nil), nil
+	}, "Blocks until cancelled")
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bus := events.New(logger)
+
+	var mu sync.Mutex
+	cancelled := map[string]bool{}
+	bus.Subscribe(workflows.RunEventCancelled, func(ctx context.Context, event events.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		cancelled[event.Subject] = true
+	})
+
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, db, bus, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	streams := make([]<-chan workflows.ExecutionEvent, 0, runCount)
+	ids := make([]uuid.UUID, 0, runCount)
+	for range runCount {
+		stream, run, err := sys.Execute("test-cancel-all", nil, "", 0)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		streams = append(streams, stream)
+		ids = append(ids, run.ID)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for len(sys.ActiveRuns()) != runCount {
+		select {
+		case <-deadline:
+			t.Fatalf("ActiveRuns() = %v, want %d runs", sys.ActiveRuns(), runCount)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	if n := sys.CancelAll(); n != runCount {
+		t.Errorf("CancelAll() = %d, want %d", n, runCount)
+	}
+
+	for _, stream := range streams {
+		for range stream {
+		}
+	}
+
+	if err := bus.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, id := range ids {
+		if !cancelled[id.String()] {
+			t.Errorf("run %s was not recorded as cancelled", id)
+		}
+	}
+
+	if active := sys.ActiveRuns(); len(active) != 0 {
+		t.Errorf("ActiveRuns() = %v, want none after CancelAll", active)
+	}
+
+	if n := sys.CancelAll(); n != 0 {
+		t.Errorf("second CancelAll() = %d, want 0", n)
+	}
+}
@@ -0,0 +1,217 @@
+package internal_workflows_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+func exportRows() []fakeRow {
+	now := time.Now()
+	row := func(name, status, params string) fakeRow {
+		return fakeRow{
+			"id":            uuid.NewString(),
+			"workflow_name": name,
+			"status":        status,
+			"params":        []byte(params),
+			"created_at":    now,
+			"updated_at":    now,
+		}
+	}
+
+	return []fakeRow{
+		row("classify-docs", "completed", `{"document_id":"a","options":{"dpi":150}}`),
+		row("classify-docs", "failed", `{"document_id":"b"}`),
+		row("summarize", "completed", `{}`),
+		row("classify-docs", "completed", `{"document_id":"c"}`),
+	}
+}
+
+func newExportHandler(t *testing.T) *workflows.Handler {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	db := openFakeDB(t, &fakeDB{rows: exportRows()})
+	paginationCfg := pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}
+
+	sys := workflows.NewSystem(runtime, db, nil, logger, paginationCfg, 0, 0)
+	return workflows.NewHandler(sys, logger, paginationCfg, 0)
+}
+
+func TestHandler_ListRuns_CSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		accept   string
+		wantRows int
+	}{
+		{"format param", "/workflows/runs?format=csv&workflow_name=classify-docs", "", 3},
+		{"accept header", "/workflows/runs?workflow_name=classify-docs&status=completed", "text/csv", 2},
+		{"ignores pagination", "/workflows/runs?format=csv&page_size=1", "", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newExportHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ListRuns(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+				t.Errorf("Content-Type = %q, want text/csv", ct)
+			}
+
+			records, err := csv.NewReader(rec.Body).ReadAll()
+			if err != nil {
+				t.Fatalf("response is not valid CSV: %v", err)
+			}
+
+			wantHeader := []string{"id", "workflow_name", "status", "params", "result", "error_message", "cancel_reason", "replayed_from", "started_at", "completed_at", "created_at", "updated_at"}
+			if len(records) == 0 || !slices.Equal(records[0], wantHeader) {
+				t.Fatalf("header = %q, want %q", records[0], wantHeader)
+			}
+			if got := len(records) - 1; got != tt.wantRows {
+				t.Errorf("rows = %d, want %d", got, tt.wantRows)
+			}
+
+			for _, row := range records[1:] {
+				if len(row) != len(wantHeader) {
+					t.Errorf("row has %d cells, want %d", len(row), len(wantHeader))
+				}
+				if !strings.HasPrefix(row[3], "{") {
+					t.Errorf("params cell = %q, want JSON object", row[3])
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ListRuns_NDJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		accept    string
+		wantLines int
+	}{
+		{"accept header", "/workflows/runs?workflow_name=classify-docs", "application/x-ndjson", 3},
+		{"format param", "/workflows/runs?format=ndjson&workflow_name=classify-docs&status=completed", "", 2},
+		{"ignores pagination", "/workflows/runs?page_size=1&sort=-created_at", "application/x-ndjson", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newExportHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ListRuns(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+				t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+			if len(lines) != tt.wantLines {
+				t.Fatalf("lines = %d, want %d: %q", len(lines), tt.wantLines, rec.Body.String())
+			}
+
+			for i, line := range lines {
+				var run workflows.Run
+				if err := json.Unmarshal([]byte(line), &run); err != nil {
+					t.Fatalf("line %d is not a JSON object: %v: %q", i, err, line)
+				}
+				if run.ID == uuid.Nil || run.WorkflowName == "" {
+					t.Errorf("line %d = %+v, want a populated run", i, run)
+				}
+			}
+		})
+	}
+}
+
+func TestExecutor_EachRun_KeysetBatches(t *testing.T) {
+	now := time.Now()
+	total := workflows.RunStreamBatchSize + 3
+
+	rows := make([]fakeRow, total)
+	want := make([]string, total)
+	for i := range rows {
+		want[i] = fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+		rows[i] = fakeRow{
+			"id":            want[i],
+			"workflow_name": "classify-docs",
+			"status":        "completed",
+			"created_at":    now,
+			"updated_at":    now,
+		}
+	}
+
+	fdb := &fakeDB{rows: rows}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, openFakeDB(t, fdb), nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	var got []string
+	err := sys.EachRun(context.Background(), workflows.RunFilters{}, nil, func(run workflows.Run) error {
+		got = append(got, run.ID.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachRun() error = %v", err)
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("EachRun() yielded %d runs, want each of %d once in order", len(got), total)
+	}
+
+	fdb.mu.Lock()
+	defer fdb.mu.Unlock()
+
+	if len(fdb.selects) != 2 {
+		t.Fatalf("queries = %d, want 2 batches", len(fdb.selects))
+	}
+
+	first, second := fdb.selects[0], fdb.selects[1]
+	if !strings.HasSuffix(first.query, fmt.Sprintf("ORDER BY r.created_at DESC, r.id ASC LIMIT %d", workflows.RunStreamBatchSize)) {
+		t.Errorf("first batch query = %q, want keyset order and batch limit", first.query)
+	}
+	if len(first.args) != 0 {
+		t.Errorf("first batch args = %v, want none", first.args)
+	}
+
+	if !strings.Contains(second.query, "(r.created_at < $1 OR (r.created_at = $1 AND r.id > $2))") {
+		t.Errorf("second batch query = %q, want keyset condition", second.query)
+	}
+	if len(second.args) != 2 || second.args[1] != want[workflows.RunStreamBatchSize-1] {
+		t.Errorf("second batch args = %v, want cursor after run %s", second.args, want[workflows.RunStreamBatchSize-1])
+	}
+}
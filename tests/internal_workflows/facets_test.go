@@ -0,0 +1,94 @@
+package internal_workflows_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+)
+
+func facetRows() []fakeRow {
+	return []fakeRow{
+		{"workflow_name": "classify-docs", "status": "completed"},
+		{"workflow_name": "classify-docs", "status": "failed"},
+		{"workflow_name": "summarize", "status": "completed"},
+		{"workflow_name": "summarize", "status": "completed"},
+	}
+}
+
+func newFacetsHandler(t *testing.T) *workflows.Handler {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	db := openFakeDB(t, &fakeDB{rows: facetRows()})
+	paginationCfg := pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}
+
+	sys := workflows.NewSystem(runtime, db, nil, logger, paginationCfg, 0, 0)
+	return workflows.NewHandler(sys, logger, paginationCfg, 0)
+}
+
+func TestHandler_RunFacets(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantField  string
+		wantValues []string
+	}{
+		{"workflow_name", "?field=workflow_name", "workflow_name", []string{"classify-docs", "summarize"}},
+		{"status", "?field=status", "status", []string{"completed", "failed"}},
+		{"filtered", "?field=status&workflow_name=summarize", "status", []string{"completed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newFacetsHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/workflows/runs/facets"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.RunFacets(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+			}
+
+			var facets workflows.RunFacets
+			if err := json.NewDecoder(rec.Body).Decode(&facets); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if facets.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", facets.Field, tt.wantField)
+			}
+			if !slices.Equal(facets.Values, tt.wantValues) {
+				t.Errorf("Values = %v, want %v", facets.Values, tt.wantValues)
+			}
+		})
+	}
+}
+
+func TestHandler_RunFacets_RejectedField(t *testing.T) {
+	for _, field := range []string{"", "params", "WorkflowName", "workflow_name; DROP TABLE runs"} {
+		t.Run(field, func(t *testing.T) {
+			handler := newFacetsHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/workflows/runs/facets", nil)
+			req.URL.RawQuery = "field=" + url.QueryEscape(field)
+			rec := httptest.NewRecorder()
+
+			handler.RunFacets(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -1,19 +1,30 @@
 package internal_workflows_test
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
 )
 
 func TestNewHandler(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	paginationCfg := pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}
 
-	handler := workflows.NewHandler(nil, logger, paginationCfg)
+	handler := workflows.NewHandler(nil, logger, paginationCfg, 0)
 
 	if handler == nil {
 		t.Fatal("NewHandler() returned nil")
@@ -24,7 +35,7 @@ func TestHandler_Routes(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	paginationCfg := pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}
 
-	handler := workflows.NewHandler(nil, logger, paginationCfg)
+	handler := workflows.NewHandler(nil, logger, paginationCfg, 0)
 	group := handler.Routes()
 
 	if group.Prefix != "/workflows" {
@@ -40,6 +51,7 @@ func TestHandler_Routes(t *testing.T) {
 		pattern string
 	}{
 		{"GET", ""},
+		{"GET", "/{name}"},
 		{"POST", "/{name}/execute"},
 	}
 
@@ -67,7 +79,7 @@ func TestHandler_Routes_Children(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	paginationCfg := pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}
 
-	handler := workflows.NewHandler(nil, logger, paginationCfg)
+	handler := workflows.NewHandler(nil, logger, paginationCfg, 0)
 	group := handler.Routes()
 
 	if len(group.Children) != 1 {
@@ -89,12 +101,21 @@ func TestHandler_Routes_Children(t *testing.T) {
 		pattern string
 	}{
 		{"GET", ""},
+		{"GET", "/active"},
+		{"GET", "/queued"},
+		{"GET", "/facets"},
+		{"GET", "/compare"},
+		{"POST", "/cancel-all"},
 		{"GET", "/{id}"},
 		{"GET", "/{id}/stages"},
 		{"GET", "/{id}/decisions"},
+		{"GET", "/{id}/report"},
+		{"GET", "/{id}/trace"},
+		{"GET", "/{id}/events"},
 		{"DELETE", "/{id}"},
 		{"POST", "/{id}/cancel"},
 		{"POST", "/{id}/resume"},
+		{"POST", "/{id}/replay"},
 	}
 
 	if len(runsGroup.Routes) != len(expectedRunsRoutes) {
@@ -116,3 +137,337 @@ func TestHandler_Routes_Children(t *testing.T) {
 		}
 	}
 }
+
+// executeSpy records calls to Execute; other System methods are not used.
+type executeSpy struct {
+	workflows.System
+	calls int
+}
+
+func (s *executeSpy) Execute(name string, params map[string]any, token string, priority int) (<-chan workflows.ExecutionEvent, *workflows.Run, error) {
+	s.calls++
+	return nil, nil, workflows.ErrWorkflowNotFound
+}
+
+func TestHandler_Execute_MalformedBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty body", ""},
+		{"syntax error", `{"params": {`},
+		{"wrong type", `{"params": "x"}`},
+		{"unknown field", `{"parms": {}}`},
+		{"trailing value", `{"params": {}} {"params": {}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &executeSpy{}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			handler := workflows.NewHandler(spy, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+			req := httptest.NewRequest(http.MethodPost, "/workflows/classify-docs/execute", strings.NewReader(tt.body))
+			req.SetPathValue("name", "classify-docs")
+			req.Header.Set("Accept", "text/event-stream")
+			rec := httptest.NewRecorder()
+
+			handler.Execute(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if spy.calls != 0 {
+				t.Errorf("System.Execute called %d times, want 0", spy.calls)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+
+			dec := json.NewDecoder(rec.Body)
+			var body handlers.ErrorResponse
+			if err := dec.Decode(&body); err != nil {
+				t.Fatalf("decode error response: %v", err)
+			}
+			if body.Error.Code == "" {
+				t.Error("error code is empty")
+			}
+			if dec.More() {
+				t.Error("response contains more than one body")
+			}
+		})
+	}
+}
+
+func TestHandler_Execute_ValidBodyReachesSystem(t *testing.T) {
+	spy := &executeSpy{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := workflows.NewHandler(spy, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+	req := httptest.NewRequest(http.MethodPost, "/workflows/missing/execute", strings.NewReader(`{"params": {"k": "v"}}`))
+	req.SetPathValue("name", "missing")
+	rec := httptest.NewRecorder()
+
+	handler.Execute(rec, req)
+
+	if spy.calls != 1 {
+		t.Errorf("System.Execute called %d times, want 1", spy.calls)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// resumeSpy records the node passed to Resume; other System methods are not used.
+type resumeSpy struct {
+	workflows.System
+	fromNode string
+	err      error
+}
+
+func (s *resumeSpy) Resume(ctx context.Context, runID uuid.UUID, fromNode string) (*workflows.Run, error) {
+	s.fromNode = fromNode
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &workflows.Run{ID: runID}, nil
+}
+
+func TestHandler_Resume_FromNode(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantNode   string
+		wantStatus int
+	}{
+		{"latest checkpoint", "", nil, "", http.StatusOK},
+		{"named node", "?from_node=detect", nil, "detect", http.StatusOK},
+		{"invalid node", "?from_node=bogus", fmt.Errorf("%w: %q (available: init, detect)", workflows.ErrInvalidNode, "bogus"), "bogus", http.StatusBadRequest},
+		{"node without checkpoint", "?from_node=score", workflows.ErrCheckpointNotFound, "score", http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spy := &resumeSpy{err: tt.err}
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			handler := workflows.NewHandler(spy, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+			id := uuid.New()
+			req := httptest.NewRequest(http.MethodPost, "/workflows/runs/"+id.String()+"/resume"+tt.query, nil)
+			req.SetPathValue("id", id.String())
+			rec := httptest.NewRecorder()
+
+			handler.Resume(rec, req)
+
+			if spy.fromNode != tt.wantNode {
+				t.Errorf("fromNode = %q, want %q", spy.fromNode, tt.wantNode)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// cancelAllSpy returns a fixed count from CancelAll; other System methods are not used.
+type cancelAllSpy struct {
+	workflows.System
+	cancelled int
+}
+
+func (s *cancelAllSpy) CancelAll() int {
+	return s.cancelled
+}
+
+func TestHandler_CancelAll(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := workflows.NewHandler(&cancelAllSpy{cancelled: 3}, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+	req := httptest.NewRequest(http.MethodPost, "/workflows/runs/cancel-all", nil)
+	rec := httptest.NewRecorder()
+
+	handler.CancelAll(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var result workflows.CancelAllResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if result.Cancelled != 3 {
+		t.Errorf("Cancelled = %d, want 3", result.Cancelled)
+	}
+}
+
+// streamSpy returns a fixed event sequence from Execute; other System methods are not used.
+type streamSpy struct {
+	workflows.System
+	events []workflows.ExecutionEvent
+}
+
+func (s *streamSpy) Execute(name string, params map[string]any, token string, priority int) (<-chan workflows.ExecutionEvent, *workflows.Run, error) {
+	ch := make(chan workflows.ExecutionEvent, len(s.events))
+	for _, e := range s.events {
+		ch <- e
+	}
+	close(ch)
+	return ch, &workflows.Run{ID: uuid.New()}, nil
+}
+
+func TestHandler_Execute_ErrorEndsWithSentinel(t *testing.T) {
+	spy := &streamSpy{events: []workflows.ExecutionEvent{
+		{Type: workflows.EventStageStart, Data: map[string]any{"node": "detect"}},
+		{Type: workflows.EventError, Data: map[string]any{"error": "provider unavailable", "node": "detect"}},
+	}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := workflows.NewHandler(spy, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+	req := httptest.NewRequest(http.MethodPost, "/workflows/classify-docs/execute", strings.NewReader(`{}`))
+	req.SetPathValue("name", "classify-docs")
+	rec := httptest.NewRecorder()
+
+	handler.Execute(rec, req)
+
+	frames := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n\n"), "\n\n")
+	if len(frames) != 3 {
+		t.Fatalf("frames = %q, want stage.start, error, and [DONE]", frames)
+	}
+
+	if !strings.HasPrefix(frames[1], "event: error\n") || !strings.Contains(frames[1], "provider unavailable") {
+		t.Errorf("frames[1] = %q, want error event", frames[1])
+	}
+
+	if frames[2] != "data: [DONE]" {
+		t.Errorf("frames[2] = %q, want terminating [DONE]", frames[2])
+	}
+}
+
+// prioritySpy records the priority passed to Execute; other System methods are not used.
+type prioritySpy struct {
+	workflows.System
+	priority int
+}
+
+func (s *prioritySpy) Execute(name string, params map[string]any, token string, priority int) (<-chan workflows.ExecutionEvent, *workflows.Run, error) {
+	s.priority = priority
+	return nil, nil, workflows.ErrWorkflowNotFound
+}
+
+func TestHandler_Execute_Priority(t *testing.T) {
+	spy := &prioritySpy{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := workflows.NewHandler(spy, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+	req := httptest.NewRequest(http.MethodPost, "/workflows/classify-docs/execute", strings.NewReader(`{"priority": 7}`))
+	req.SetPathValue("name", "classify-docs")
+	rec := httptest.NewRecorder()
+
+	handler.Execute(rec, req)
+
+	if spy.priority != 7 {
+		t.Errorf("priority = %d, want 7", spy.priority)
+	}
+}
+
+// queuedSpy returns fixed queued runs; other System methods are not used.
+type queuedSpy struct {
+	workflows.System
+	queued []workflows.QueuedRun
+}
+
+func (s *queuedSpy) QueuedRuns() []workflows.QueuedRun {
+	return s.queued
+}
+
+func TestHandler_ListQueued(t *testing.T) {
+	queued := []workflows.QueuedRun{
+		{RunID: uuid.New(), WorkflowName: "classify-docs", Priority: 5},
+		{RunID: uuid.New(), WorkflowName: "classify-docs", Priority: 0},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := workflows.NewHandler(&queuedSpy{queued: queued}, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/workflows/runs/queued", nil)
+	rec := httptest.NewRecorder()
+
+	handler.ListQueued(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var result []workflows.QueuedRun
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(result) != 2 || result[0].RunID != queued[0].RunID || result[1].Priority != 0 {
+		t.Errorf("result = %+v, want %+v", result, queued)
+	}
+}
+
+func TestHandler_ListRuns_UnknownSort(t *testing.T) {
+	handler := newFacetsHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/workflows/runs?sort=-rank", nil)
+	rec := httptest.NewRecorder()
+
+	handler.ListRuns(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Sort     []string `json:"sort"`
+				Sortable []string `json:"sortable"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+
+	if body.Error.Code != "invalid_sort" {
+		t.Errorf("code = %q, want invalid_sort", body.Error.Code)
+	}
+	if len(body.Error.Details.Sort) != 1 || body.Error.Details.Sort[0] != "rank" {
+		t.Errorf("details.sort = %v, want [rank]", body.Error.Details.Sort)
+	}
+	if !slices.Contains(body.Error.Details.Sortable, "created_at") || !slices.Contains(body.Error.Details.Sortable, "workflow_name") {
+		t.Errorf("details.sortable = %v, want the run columns", body.Error.Details.Sortable)
+	}
+}
+
+func TestHandler_ListRuns_SortAllowlist(t *testing.T) {
+	tests := []struct {
+		sort string
+		want int
+	}{
+		{"-workflow_name", http.StatusOK},
+		{"status,-created_at", http.StatusOK},
+		{"params", http.StatusBadRequest},
+		{"-result", http.StatusBadRequest},
+		{"error_message", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			handler := newExportHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/workflows/runs?sort="+url.QueryEscape(tt.sort), nil)
+			rec := httptest.NewRecorder()
+
+			handler.ListRuns(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -13,30 +13,42 @@ func TestRunFiltersFromQuery(t *testing.T) {
 		query            string
 		wantWorkflowName *string
 		wantStatus       *string
+		wantStatusNot    *string
 	}{
 		{
 			"empty query",
 			"",
 			nil,
 			nil,
+			nil,
 		},
 		{
 			"workflow_name only",
 			"workflow_name=classify-docs",
 			strPtr("classify-docs"),
 			nil,
+			nil,
 		},
 		{
 			"status only",
 			"status=running",
 			nil,
 			strPtr("running"),
+			nil,
 		},
 		{
 			"both filters",
 			"workflow_name=classify-docs&status=completed",
 			strPtr("classify-docs"),
 			strPtr("completed"),
+			nil,
+		},
+		{
+			"status_not only",
+			"status_not=completed",
+			nil,
+			nil,
+			strPtr("completed"),
 		},
 	}
 
@@ -52,6 +64,10 @@ func TestRunFiltersFromQuery(t *testing.T) {
 			if !strPtrEqual(got.Status, tt.wantStatus) {
 				t.Errorf("Status = %v, want %v", strPtrVal(got.Status), strPtrVal(tt.wantStatus))
 			}
+
+			if !strPtrEqual(got.StatusNot, tt.wantStatusNot) {
+				t.Errorf("StatusNot = %v, want %v", strPtrVal(got.StatusNot), strPtrVal(tt.wantStatusNot))
+			}
 		})
 	}
 }
@@ -1,11 +1,17 @@
 package internal_workflows_test
 
 import (
+	"context"
+	"io"
 	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/config"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/observability"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
 	"github.com/google/uuid"
 )
 
@@ -26,3 +32,128 @@ func TestPostgresObserver_ImplementsInterface(t *testing.T) {
 
 	var _ observability.Observer = observer
 }
+
+func decisionExecs(fdb *fakeDB) []fakeExec {
+	fdb.mu.Lock()
+	defer fdb.mu.Unlock()
+
+	var execs []fakeExec
+	for _, e := range fdb.execs {
+		if strings.Contains(e.query, "INSERT INTO decisions") {
+			execs = append(execs, e)
+		}
+	}
+	return execs
+}
+
+func TestPostgresObserver_EdgeTransition_LabeledEdge(t *testing.T) {
+	fdb := &fakeDB{}
+	observer := workflows.NewPostgresObserver(openFakeDB(t, fdb), uuid.New(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	observer.LabelEdge("detect", "enhance", workflows.EdgeLabel{
+		Name:   "needs_enhancement",
+		Reason: "markings below legibility threshold → enhance",
+	})
+
+	observer.OnEvent(context.Background(), observability.Event{
+		Type:      observability.EventEdgeTransition,
+		Timestamp: time.Now(),
+		Data: map[string]any{
+			"from":             "detect",
+			"to":               "enhance",
+			"predicate_name":   "",
+			"predicate_result": true,
+		},
+	})
+
+	execs := decisionExecs(fdb)
+	if len(execs) != 1 {
+		t.Fatalf("decision inserts = %d, want 1", len(execs))
+	}
+
+	// run_id, from_node, to_node, predicate_name, predicate_result, reason, created_at
+	args := execs[0].args
+	if args[1] != "detect" || args[2] != "enhance" {
+		t.Errorf("edge = %v -> %v, want detect -> enhance", args[1], args[2])
+	}
+	if args[3] != "needs_enhancement" {
+		t.Errorf("predicate_name = %v, want needs_enhancement", args[3])
+	}
+	if args[4] != true {
+		t.Errorf("predicate_result = %v, want true", args[4])
+	}
+	if args[5] != "markings below legibility threshold → enhance" {
+		t.Errorf("reason = %v, want label reason", args[5])
+	}
+}
+
+func TestPostgresObserver_EdgeTransition_Unlabeled(t *testing.T) {
+	fdb := &fakeDB{}
+	observer := workflows.NewPostgresObserver(openFakeDB(t, fdb), uuid.New(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	observer.OnEvent(context.Background(), observability.Event{
+		Type:      observability.EventEdgeTransition,
+		Timestamp: time.Now(),
+		Data: map[string]any{
+			"from":             "init",
+			"to":               "detect",
+			"predicate_result": true,
+		},
+	})
+
+	execs := decisionExecs(fdb)
+	if len(execs) != 1 {
+		t.Fatalf("decision inserts = %d, want 1", len(execs))
+	}
+	if args := execs[0].args; args[3] != nil || args[5] != nil {
+		t.Errorf("predicate_name = %v, reason = %v, want both NULL", args[3], args[5])
+	}
+}
+
+// labelingGraph records labels passed through AddLabeledEdge.
+type labelingGraph struct {
+	state.StateGraph
+	labels map[string]workflows.EdgeLabel
+}
+
+func (g *labelingGraph) LabelEdge(from, to string, label workflows.EdgeLabel) {
+	g.labels[from+"->"+to] = label
+}
+
+func newTestGraph(t *testing.T) state.StateGraph {
+	t.Helper()
+
+	graph, err := state.NewGraphWithDeps(config.DefaultGraphConfig("labels"), observability.NoOpObserver{}, nil)
+	if err != nil {
+		t.Fatalf("NewGraphWithDeps() error = %v", err)
+	}
+	noop := state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) { return s, nil })
+	graph.AddNode("a", noop)
+	graph.AddNode("b", noop)
+	return graph
+}
+
+func TestAddLabeledEdge(t *testing.T) {
+	graph := &labelingGraph{StateGraph: newTestGraph(t), labels: map[string]workflows.EdgeLabel{}}
+	label := workflows.EdgeLabel{Name: "ready", Reason: "a finished → b"}
+
+	if err := workflows.AddLabeledEdge(graph, "a", "b", nil, label); err != nil {
+		t.Fatalf("AddLabeledEdge() error = %v", err)
+	}
+	if got := graph.labels["a->b"]; got != label {
+		t.Errorf("label = %+v, want %+v", got, label)
+	}
+
+	if err := workflows.AddLabeledEdge(graph, "a", "missing", nil, label); err == nil {
+		t.Error("AddLabeledEdge() to unknown node should fail")
+	}
+	if _, ok := graph.labels["a->missing"]; ok {
+		t.Error("failed edge should not be labeled")
+	}
+}
+
+func TestAddLabeledEdge_PlainGraph(t *testing.T) {
+	if err := workflows.AddLabeledEdge(newTestGraph(t), "a", "b", nil, workflows.EdgeLabel{Name: "ready"}); err != nil {
+		t.Fatalf("AddLabeledEdge() error = %v", err)
+	}
+}
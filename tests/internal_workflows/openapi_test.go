@@ -1,9 +1,11 @@
 package internal_workflows_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/google/uuid"
 )
 
 func TestSpec_Operations(t *testing.T) {
@@ -15,9 +17,16 @@ func TestSpec_Operations(t *testing.T) {
 		{"Execute", workflows.Spec.Execute},
 		{"ListRuns", workflows.Spec.ListRuns},
 		{"FindRun", workflows.Spec.FindRun},
+		{"ListActiveRuns", workflows.Spec.ListActiveRuns},
+		{"ListQueued", workflows.Spec.ListQueued},
+		{"RunFacets", workflows.Spec.RunFacets},
+		{"CompareRuns", workflows.Spec.CompareRuns},
 		{"GetStages", workflows.Spec.GetStages},
 		{"GetDecisions", workflows.Spec.GetDecisions},
+		{"GetReport", workflows.Spec.GetReport},
+		{"GetTrace", workflows.Spec.GetTrace},
 		{"Cancel", workflows.Spec.Cancel},
+		{"CancelAll", workflows.Spec.CancelAll},
 		{"Resume", workflows.Spec.Resume},
 	}
 
@@ -37,11 +46,20 @@ func TestSpec_Schemas(t *testing.T) {
 		"WorkflowInfo",
 		"WorkflowInfoList",
 		"Run",
+		"RunList",
 		"RunPageResult",
 		"Stage",
-		"StageList",
+		"StagePageResult",
 		"Decision",
-		"DecisionList",
+		"DecisionPageResult",
+		"RunReport",
+		"RunTrace",
+		"TraceEntry",
+		"RunComparison",
+		"CancelAllResult",
+		"QueuedRun",
+		"QueuedRunList",
+		"RunFacets",
 		"ExecuteRequest",
 		"ExecutionEvent",
 	}
@@ -79,6 +97,20 @@ func TestSpec_Execute_SSEResponse(t *testing.T) {
 	if sseContent.Schema == nil {
 		t.Error("SSE content schema is nil")
 	}
+
+	if h := response200.Headers["X-Run-ID"]; h == nil || h.Schema.Format != "uuid" {
+		t.Errorf("Execute.Responses[200].Headers[X-Run-ID] = %+v, want uuid header", h)
+	}
+}
+
+func TestSpec_ListRuns_PageHeaders(t *testing.T) {
+	headers := workflows.Spec.ListRuns.Responses[200].Headers
+
+	for _, name := range []string{"X-Total-Count", "Link", workflows.ServerTimeHeader} {
+		if headers[name] == nil {
+			t.Errorf("ListRuns.Responses[200].Headers[%q] is nil", name)
+		}
+	}
 }
 
 func TestSpec_RunSchema_StatusEnum(t *testing.T) {
@@ -164,3 +196,25 @@ func TestSpec_ExecutionEventSchema_TypeEnum(t *testing.T) {
 		}
 	}
 }
+
+func TestSpec_Execute_Example(t *testing.T) {
+	example := workflows.Spec.Execute.RequestBody.Content["application/json"].Example
+	if example == nil {
+		t.Fatal("Execute request body has no example")
+	}
+
+	data, err := json.Marshal(example)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var req workflows.ExecuteRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("example does not decode as ExecuteRequest: %v", err)
+	}
+
+	docID, _ := req.Params["document_id"].(string)
+	if _, err := uuid.Parse(docID); err != nil {
+		t.Errorf("example document_id = %q, want a UUID", docID)
+	}
+}
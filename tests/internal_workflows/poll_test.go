@@ -0,0 +1,131 @@
+package internal_workflows_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// pollRows returns runs last updated one, two, and three minutes before base.
+func pollRows(base time.Time) []fakeRow {
+	row := func(name string, updated time.Time) fakeRow {
+		return fakeRow{
+			"id":            uuid.NewString(),
+			"workflow_name": name,
+			"status":        "completed",
+			"params":        []byte(`{}`),
+			"created_at":    base.Add(-time.Hour),
+			"updated_at":    updated,
+		}
+	}
+
+	return []fakeRow{
+		row("oldest", base.Add(-3*time.Minute)),
+		row("middle", base.Add(-2*time.Minute)),
+		row("newest", base.Add(-time.Minute)),
+	}
+}
+
+func newPollHandler(t *testing.T, base time.Time) *workflows.Handler {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	db := openFakeDB(t, &fakeDB{rows: pollRows(base)})
+	paginationCfg := pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}
+	return workflows.NewHandler(workflows.NewSystem(runtime, db, nil, logger, paginationCfg, 0, 0), logger, paginationCfg, 0)
+}
+
+// listRunsSince lists runs updated after updatedAfter, or all runs when it is
+// empty, and returns their workflow names and the server time header.
+func listRunsSince(t *testing.T, handler *workflows.Handler, updatedAfter string) ([]string, string) {
+	t.Helper()
+
+	target := "/workflows/runs"
+	if updatedAfter != "" {
+		target += "?updated_after=" + url.QueryEscape(updatedAfter)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	handler.ListRuns(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var page pagination.PageResult[workflows.Run]
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode page: %v", err)
+	}
+
+	var names []string
+	for _, run := range page.Data {
+		names = append(names, run.WorkflowName)
+	}
+	return names, rec.Header().Get(workflows.ServerTimeHeader)
+}
+
+func TestHandler_ListRuns_UpdatedAfter(t *testing.T) {
+	base := time.Now().UTC()
+
+	handler := newPollHandler(t, base)
+
+	tests := []struct {
+		name         string
+		updatedAfter string
+		want         []string
+	}{
+		{"no cursor", "", []string{"oldest", "middle", "newest"}},
+		{"exclusive bound", base.Add(-2 * time.Minute).Format(time.RFC3339Nano), []string{"newest"}},
+		{"offset timestamp", base.Add(-150 * time.Second).In(time.FixedZone("EST", -5*3600)).Format(time.RFC3339), []string{"middle", "newest"}},
+		{"invalid cursor ignored", "yesterday", []string{"oldest", "middle", "newest"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := listRunsSince(t, handler, tt.updatedAfter)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("runs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_ListRuns_ServerTimeCursor(t *testing.T) {
+	base := time.Now().UTC()
+
+	handler := newPollHandler(t, base)
+
+	_, cursor := listRunsSince(t, handler, "")
+	if cursor == "" {
+		t.Fatalf("missing %s header", workflows.ServerTimeHeader)
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, cursor)
+	if err != nil {
+		t.Fatalf("%s = %q is not RFC 3339: %v", workflows.ServerTimeHeader, cursor, err)
+	}
+	if at.Before(base) || at.After(time.Now()) {
+		t.Errorf("%s = %s, want the time of the request", workflows.ServerTimeHeader, at)
+	}
+
+	got, next := listRunsSince(t, handler, cursor)
+	if len(got) != 0 {
+		t.Errorf("poll with cursor returned %v, want no unchanged runs", got)
+	}
+	if nextAt, err := time.Parse(time.RFC3339Nano, next); err != nil || nextAt.Before(at) {
+		t.Errorf("next cursor = %q, want one at or after %q", next, cursor)
+	}
+}
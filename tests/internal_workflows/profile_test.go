@@ -1,10 +1,18 @@
 package internal_workflows_test
 
 import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/JaimeStill/agent-lab/internal/profiles"
 	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
 	"github.com/google/uuid"
 )
@@ -131,3 +139,145 @@ func TestExtractAgentParams_WithToken(t *testing.T) {
 		t.Errorf("ExtractAgentParams() token = %q, want %q", token, "test-token")
 	}
 }
+
+func completenessProfile(agentID *uuid.UUID) *profiles.ProfileWithStages {
+	return profiles.NewProfileWithStages(
+		profiles.ProfileStage{StageName: "detect", AgentID: agentID},
+		profiles.ProfileStage{StageName: "classify"},
+		profiles.ProfileStage{StageName: "score"},
+	)
+}
+
+var completenessStages = []workflows.AgentStage{
+	{Name: "detect"},
+	{Name: "classify", HasAgents: func(stage *profiles.ProfileStage) bool {
+		return string(stage.Options) == `{"agent_ids":["a","b"]}`
+	}},
+	{Name: "score"},
+}
+
+func TestValidateProfile_Complete(t *testing.T) {
+	agentID := uuid.New()
+
+	tests := []struct {
+		name    string
+		profile *profiles.ProfileWithStages
+		params  map[string]any
+	}{
+		{"agent from params", completenessProfile(nil), map[string]any{"agent_id": agentID.String()}},
+		{"agents from stages", profiles.NewProfileWithStages(
+			profiles.ProfileStage{StageName: "detect", AgentID: &agentID},
+			profiles.ProfileStage{StageName: "classify", Options: []byte(`{"agent_ids":["a","b"]}`)},
+			profiles.ProfileStage{StageName: "score", AgentID: &agentID},
+		), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := workflows.ValidateProfile(tt.profile, tt.params, completenessStages); err != nil {
+				t.Errorf("ValidateProfile() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateProfile_Incomplete(t *testing.T) {
+	agentID := uuid.New()
+
+	tests := []struct {
+		name    string
+		profile *profiles.ProfileWithStages
+		params  map[string]any
+		missing string
+	}{
+		{"no agents", completenessProfile(nil), nil, "detect, classify, score"},
+		{"partial stage agents", completenessProfile(&agentID), nil, "classify, score"},
+		{"invalid param agent", completenessProfile(&agentID), map[string]any{"agent_id": "not-a-uuid"}, "classify, score"},
+		{"stage not in profile", profiles.NewProfileWithStages(), nil, "detect, classify, score"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := workflows.ValidateProfile(tt.profile, tt.params, completenessStages)
+			if !errors.Is(err, workflows.ErrIncompleteProfile) {
+				t.Fatalf("ValidateProfile() error = %v, want ErrIncompleteProfile", err)
+			}
+			if !strings.HasSuffix(err.Error(), "no agent for stages: "+tt.missing) {
+				t.Errorf("error = %q, want missing stages %q", err, tt.missing)
+			}
+		})
+	}
+}
+
+func TestExecutor_Execute_IncompleteProfile(t *testing.T) {
+	const name = "test-incomplete-profile"
+
+	workflows.Register(name, func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		t.Error("factory should not run for an incomplete profile")
+		return state.New(nil), nil
+	}, "Requires an agent for its only stage")
+	workflows.RegisterStages(name, func() *profiles.ProfileWithStages {
+		return profiles.NewProfileWithStages(profiles.ProfileStage{StageName: "answer"})
+	}, workflows.AgentStage{Name: "answer"})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, nil, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	_, run, err := sys.Execute(name, nil, "", 0)
+	if !errors.Is(err, workflows.ErrIncompleteProfile) {
+		t.Fatalf("Execute() error = %v, want ErrIncompleteProfile", err)
+	}
+	if !strings.Contains(err.Error(), "answer") {
+		t.Errorf("error %q should list the missing stage", err)
+	}
+	if run != nil {
+		t.Errorf("Execute() run = %+v, want no run created", run)
+	}
+
+	if err := sys.Drain(context.Background()); err != nil {
+		t.Errorf("Drain() error = %v, rejected execution should not hold the drain", err)
+	}
+}
+
+func TestExecutor_Execute_InvalidProfileID(t *testing.T) {
+	const name = "test-invalid-profile-id"
+
+	workflows.Register(name, func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		t.Error("factory should not run for an invalid profile_id")
+		return state.New(nil), nil
+	}, "Rejects a malformed profile_id")
+	workflows.RegisterStages(name, func() *profiles.ProfileWithStages {
+		return profiles.NewProfileWithStages(profiles.ProfileStage{StageName: "answer"})
+	}, workflows.AgentStage{Name: "answer"})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, nil, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	_, run, err := sys.Execute(name, map[string]any{"profile_id": "not-a-uuid"}, "", 0)
+	if !errors.Is(err, workflows.ErrInvalidProfile) {
+		t.Fatalf("Execute() error = %v, want ErrInvalidProfile", err)
+	}
+	if run != nil {
+		t.Errorf("Execute() run = %+v, want no run created", run)
+	}
+	if got := workflows.MapHTTPStatus(err); got != http.StatusBadRequest {
+		t.Errorf("MapHTTPStatus() = %d, want %d", got, http.StatusBadRequest)
+	}
+}
+
+func TestDefaultProfile(t *testing.T) {
+	const name = "test-default-profile"
+
+	workflows.RegisterStages(name, func() *profiles.ProfileWithStages {
+		return profiles.NewProfileWithStages(profiles.ProfileStage{StageName: "answer"})
+	}, workflows.AgentStage{Name: "answer"})
+
+	if p := workflows.DefaultProfile(name); p == nil || p.Stage("answer") == nil {
+		t.Errorf("DefaultProfile(%q) = %+v, want the registered default", name, p)
+	}
+	if p := workflows.DefaultProfile("test-unregistered-workflow"); p != nil {
+		t.Errorf("DefaultProfile(unregistered) = %+v, want nil", p)
+	}
+}
@@ -0,0 +1,214 @@
+package internal_workflows_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+)
+
+// startRecorder records the label param of each run as it starts executing.
+type startRecorder struct {
+	mu     sync.Mutex
+	labels []string
+}
+
+func (r *startRecorder) add(label string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.labels = append(r.labels, label)
+}
+
+func (r *startRecorder) started() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.labels...)
+}
+
+// registerQueueWorkflow registers a workflow whose runs record their label
+// and then block until a value is sent on release.
+func registerQueueWorkflow(name string, rec *startRecorder, release <-chan struct{}) {
+	workflows.Register(name, func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		label, _ := params["label"].(string)
+		graph.AddNode("block", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			rec.add(label)
+			select {
+			case <-release:
+				return s, nil
+			case <-ctx.Done():
+				return s, ctx.Err()
+			}
+		}))
+		graph.SetEntryPoint("block")
+		graph.SetExitPoint("block")
+		return state.New(nil), nil
+	}, "Records its start and blocks until released")
+}
+
+func newQueueSystem(t *testing.T, name string, maxConcurrent int) workflows.System {
+	t.Helper()
+
+	now := time.Now()
+	db := openFakeDB(t, &fakeDB{run: fakeRow{
+		"workflow_name": name,
+		"status":        string(workflows.StatusRunning),
+		"created_at":    now,
+		"updated_at":    now,
+	}})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	return workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, maxConcurrent, 0)
+}
+
+func waitStarted(t *testing.T, rec *startRecorder, n int) []string {
+	t.Helper()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		if started := rec.started(); len(started) >= n {
+			return started
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("started = %v, want %d runs started", rec.started(), n)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
+
+func TestExecutor_Queue_HigherPriorityDispatchesFirst(t *testing.T) {
+	const name = "test-queue-priority"
+
+	rec := &startRecorder{}
+	release := make(chan struct{})
+	registerQueueWorkflow(name, rec, release)
+	sys := newQueueSystem(t, name, 1)
+
+	var streams []<-chan workflows.ExecutionEvent
+	execute := func(label string, priority int) *workflows.Run {
+		t.Helper()
+		stream, run, err := sys.Execute(name, map[string]any{"label": label}, "", priority)
+		if err != nil {
+			t.Fatalf("Execute(%s) error = %v", label, err)
+		}
+		streams = append(streams, stream)
+		return run
+	}
+
+	execute("first", 0)
+	waitStarted(t, rec, 1)
+
+	low := execute("low", 0)
+	high := execute("high", 5)
+
+	queued := sys.QueuedRuns()
+	if len(queued) != 2 || queued[0].RunID != high.ID || queued[1].RunID != low.ID {
+		t.Fatalf("QueuedRuns() = %+v, want high then low", queued)
+	}
+	if queued[0].Priority != 5 || queued[0].WorkflowName != name {
+		t.Errorf("QueuedRuns()[0] = %+v, want priority 5 for %s", queued[0], name)
+	}
+
+	release <- struct{}{}
+	started := waitStarted(t, rec, 2)
+	if started[1] != "high" {
+		t.Errorf("second run started = %q, want %q", started[1], "high")
+	}
+
+	if queued := sys.QueuedRuns(); len(queued) != 1 || queued[0].RunID != low.ID {
+		t.Errorf("QueuedRuns() = %+v, want only low", queued)
+	}
+
+	close(release)
+	for _, stream := range streams {
+		for range stream {
+		}
+	}
+
+	if started := rec.started(); len(started) != 3 || started[2] != "low" {
+		t.Errorf("started = %v, want low last", started)
+	}
+	if queued := sys.QueuedRuns(); len(queued) != 0 {
+		t.Errorf("QueuedRuns() = %+v, want empty", queued)
+	}
+}
+
+func TestExecutor_Queue_EqualPriorityIsFIFO(t *testing.T) {
+	const name = "test-queue-fifo"
+
+	rec := &startRecorder{}
+	release := make(chan struct{})
+	registerQueueWorkflow(name, rec, release)
+	sys := newQueueSystem(t, name, 1)
+
+	var streams []<-chan workflows.ExecutionEvent
+	for _, label := range []string{"a", "b", "c"} {
+		stream, _, err := sys.Execute(name, map[string]any{"label": label}, "", 1)
+		if err != nil {
+			t.Fatalf("Execute(%s) error = %v", label, err)
+		}
+		streams = append(streams, stream)
+	}
+
+	close(release)
+	for _, stream := range streams {
+		for range stream {
+		}
+	}
+
+	started := rec.started()
+	if len(started) != 3 || started[0] != "a" || started[1] != "b" || started[2] != "c" {
+		t.Errorf("started = %v, want [a b c]", started)
+	}
+}
+
+func TestExecutor_Queue_CancelQueuedRun(t *testing.T) {
+	const name = "test-queue-cancel"
+
+	rec := &startRecorder{}
+	release := make(chan struct{})
+	registerQueueWorkflow(name, rec, release)
+	sys := newQueueSystem(t, name, 1)
+
+	first, _, err := sys.Execute(name, map[string]any{"label": "first"}, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	waitStarted(t, rec, 1)
+
+	stream, run, err := sys.Execute(name, map[string]any{"label": "queued"}, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if err := sys.Cancel(context.Background(), run.ID, workflows.CancelUser); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+
+	var last workflows.ExecutionEvent
+	for event := range stream {
+		last = event
+	}
+	if last.Type != workflows.EventError {
+		t.Errorf("last event = %q, want %q", last.Type, workflows.EventError)
+	}
+	if queued := sys.QueuedRuns(); len(queued) != 0 {
+		t.Errorf("QueuedRuns() = %+v, want empty after cancel", queued)
+	}
+
+	close(release)
+	for range first {
+	}
+
+	if started := rec.started(); len(started) != 1 {
+		t.Errorf("started = %v, cancelled run should never start", started)
+	}
+}
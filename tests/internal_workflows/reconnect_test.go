@@ -0,0 +1,300 @@
+package internal_workflows_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+	"github.com/google/uuid"
+)
+
+const reconnectWorkflow = "test-reconnect"
+
+// reconnectRelease unblocks the wait node of reconnectWorkflow.
+var reconnectRelease chan struct{}
+
+func init() {
+	workflows.Register(reconnectWorkflow, func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		graph.AddNode("first", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			return s, nil
+		}))
+		graph.AddNode("wait", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			<-reconnectRelease
+			return s, nil
+		}))
+		graph.AddEdge("first", "wait", nil)
+		graph.SetEntryPoint("first")
+		graph.SetExitPoint("wait")
+		return state.New(nil), nil
+	}, "Waits for release after its first node")
+}
+
+// newReconnectHandler returns a handler for a system whose database holds a
+// completed run of two stages joined by one decision.
+func newReconnectHandler(t *testing.T, runID uuid.UUID) *workflows.Handler {
+	t.Helper()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	completed := base.Add(time.Minute)
+
+	detect := stageRow(runID, "detect", "completed", base)
+	detect["output_snapshot"] = []byte(`{"pages": 2}`)
+	classify := stageRow(runID, "classify", "completed", base.Add(2*time.Second))
+	decision := decisionRow(runID, "detect", base.Add(time.Second))
+	decision["to_node"] = "classify"
+
+	db := openFakeDB(t, &fakeDB{rows: []fakeRow{
+		{
+			"id":            runID.String(),
+			"workflow_name": "classify-docs",
+			"status":        string(workflows.StatusCompleted),
+			"result":        []byte(`{"label": "invoice"}`),
+			"completed_at":  completed,
+			"created_at":    base,
+			"updated_at":    completed,
+		},
+		detect,
+		classify,
+		decision,
+	}})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 3*time.Second)
+	return sys.Handler()
+}
+
+// sseFrame is a parsed SSE frame.
+type sseFrame struct {
+	id    string
+	event string
+	data  string
+	retry string
+}
+
+func streamRun(t *testing.T, handler *workflows.Handler, runID uuid.UUID, lastEventID string) (*httptest.ResponseRecorder, []sseFrame) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/workflows/runs/"+runID.String()+"/events", nil)
+	req.SetPathValue("id", runID.String())
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	rec := httptest.NewRecorder()
+
+	handler.StreamRun(rec, req)
+
+	var frames []sseFrame
+	for _, block := range strings.Split(strings.TrimSuffix(rec.Body.String(), "\n\n"), "\n\n") {
+		var f sseFrame
+		for _, line := range strings.Split(block, "\n") {
+			field, value, _ := strings.Cut(line, ": ")
+			switch field {
+			case "id":
+				f.id = value
+			case "event":
+				f.event = value
+			case "data":
+				f.data = value
+			case "retry":
+				f.retry = value
+			}
+		}
+		frames = append(frames, f)
+	}
+	return rec, frames
+}
+
+func TestHandler_StreamRun_ReplaysPersistedEvents(t *testing.T) {
+	runID := uuid.New()
+	handler := newReconnectHandler(t, runID)
+
+	rec, frames := streamRun(t, handler, runID, "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("X-Run-ID"); got != runID.String() {
+		t.Errorf("X-Run-ID = %q, want %q", got, runID)
+	}
+
+	want := []struct{ id, event string }{
+		{"", ""},
+		{"1", "stage.start"},
+		{"2", "stage.complete"},
+		{"3", "decision"},
+		{"4", "stage.start"},
+		{"5", "stage.complete"},
+		{"6", "complete"},
+		{"", ""},
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("frames = %+v, want %d frames", frames, len(want))
+	}
+
+	if frames[0].retry != "3000" {
+		t.Errorf("retry = %q, want 3000", frames[0].retry)
+	}
+	for i, w := range want[1 : len(want)-1] {
+		f := frames[i+1]
+		if f.id != w.id || f.event != w.event {
+			t.Errorf("frames[%d] = id %q event %q, want id %q event %q", i+1, f.id, f.event, w.id, w.event)
+		}
+	}
+	if last := frames[len(frames)-1]; last.data != "[DONE]" {
+		t.Errorf("last frame = %+v, want [DONE]", last)
+	}
+
+	var decision workflows.ExecutionEvent
+	if err := json.Unmarshal([]byte(frames[3].data), &decision); err != nil {
+		t.Fatalf("decode decision: %v", err)
+	}
+	if decision.ID != 3 || decision.Data["from_node"] != "detect" || decision.Data["to_node"] != "classify" {
+		t.Errorf("decision = %+v, want detect -> classify with ID 3", decision)
+	}
+
+	var complete workflows.ExecutionEvent
+	if err := json.Unmarshal([]byte(frames[6].data), &complete); err != nil {
+		t.Fatalf("decode complete: %v", err)
+	}
+	result, _ := complete.Data["result"].(map[string]any)
+	if result["label"] != "invoice" {
+		t.Errorf("complete result = %v, want run result", complete.Data["result"])
+	}
+}
+
+func TestHandler_StreamRun_LastEventIDReplaysSubsequentEvents(t *testing.T) {
+	runID := uuid.New()
+	handler := newReconnectHandler(t, runID)
+
+	_, frames := streamRun(t, handler, runID, "3")
+
+	var got []string
+	for _, f := range frames {
+		if f.id != "" {
+			got = append(got, f.id+":"+f.event)
+		}
+	}
+
+	want := "4:stage.start 5:stage.complete 6:complete"
+	if strings.Join(got, " ") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+
+	_, frames = streamRun(t, handler, runID, "6")
+	if len(frames) != 2 || frames[1].data != "[DONE]" {
+		t.Errorf("frames after final event = %+v, want retry and [DONE] only", frames)
+	}
+}
+
+// streamRunSpy records the Last-Event-ID passed to StreamRun; other System
+// methods are not used.
+type streamRunSpy struct {
+	workflows.System
+	lastEventID int
+}
+
+func (s *streamRunSpy) StreamRun(ctx context.Context, runID uuid.UUID, lastEventID int) (<-chan workflows.ExecutionEvent, *workflows.Run, error) {
+	s.lastEventID = lastEventID
+	return nil, nil, workflows.ErrNotFound
+}
+
+func TestHandler_StreamRun_Errors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	spy := &streamRunSpy{}
+	handler := workflows.NewHandler(spy, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+	runID := uuid.New()
+	rec, _ := streamRun(t, handler, runID, "12")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown run status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if spy.lastEventID != 12 {
+		t.Errorf("lastEventID = %d, want 12", spy.lastEventID)
+	}
+
+	rec, _ = streamRun(t, handler, runID, "latest")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid Last-Event-ID status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestExecutor_StreamRun_NotFound(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, openFakeDB(t, &fakeDB{}), nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	_, _, err := sys.StreamRun(context.Background(), uuid.New(), 0)
+	if !errors.Is(err, workflows.ErrNotFound) {
+		t.Errorf("StreamRun() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestExecutor_StreamRun_TailsLiveEvents(t *testing.T) {
+	reconnectRelease = make(chan struct{})
+
+	runID := uuid.New()
+	now := time.Now()
+	run := fakeRow{
+		"id":            runID.String(),
+		"workflow_name": reconnectWorkflow,
+		"status":        string(workflows.StatusRunning),
+		"created_at":    now,
+		"updated_at":    now,
+	}
+	db := openFakeDB(t, &fakeDB{run: run, rows: []fakeRow{run}})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	events, _, err := sys.Execute(reconnectWorkflow, nil, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for waiting := true; waiting; {
+		select {
+		case event := <-events:
+			waiting = event.Type != workflows.EventStageStart || event.Data["node_name"] != "wait"
+		case <-timeout:
+			t.Fatal("timed out waiting for the wait stage")
+		}
+	}
+
+	stream, _, err := sys.StreamRun(context.Background(), runID, 2)
+	if err != nil {
+		t.Fatalf("StreamRun() error = %v", err)
+	}
+	close(reconnectRelease)
+
+	var got []string
+	for {
+		select {
+		case event, ok := <-stream:
+			if !ok {
+				want := "5:stage.complete 6:complete"
+				if strings.Join(got, " ") != want {
+					t.Errorf("events = %v, want %s", got, want)
+				}
+				return
+			}
+			got = append(got, fmt.Sprintf("%d:%s", event.ID, event.Type))
+		case <-timeout:
+			t.Fatalf("timed out with events %v", got)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package internal_workflows_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+	"github.com/google/uuid"
+)
+
+const replayWorkflow = "test-replay"
+
+func init() {
+	workflows.Register(replayWorkflow, func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		graph.AddNode("done", state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+			return s, nil
+		}))
+		graph.SetEntryPoint("done")
+		graph.SetExitPoint("done")
+		return state.New(nil), nil
+	}, "Completes immediately")
+}
+
+func newReplaySystem(t *testing.T, original fakeRow) (workflows.System, *fakeDB) {
+	t.Helper()
+
+	now := time.Now()
+	fdb := &fakeDB{
+		run: fakeRow{
+			"workflow_name": replayWorkflow,
+			"status":        string(workflows.StatusPending),
+			"replayed_from": original["id"],
+			"created_at":    now,
+			"updated_at":    now,
+		},
+		rows: []fakeRow{original},
+	}
+	db := openFakeDB(t, fdb)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	return workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0), fdb
+}
+
+func originalRunRow(id uuid.UUID, status workflows.RunStatus, params string) fakeRow {
+	now := time.Now()
+	return fakeRow{
+		"id":            id.String(),
+		"workflow_name": replayWorkflow,
+		"status":        string(status),
+		"params":        []byte(params),
+		"error_message": "boom",
+		"created_at":    now,
+		"updated_at":    now,
+	}
+}
+
+func TestExecutor_Replay_CreatesLinkedRunWithSameParams(t *testing.T) {
+	originalID := uuid.New()
+	params := `{"document_id": "9b2d7f4e-5c1a-4e8b-a3d6-2f71c0e84b19", "options": {"dpi": 300, "pages": [1, 2]}}`
+	sys, fdb := newReplaySystem(t, originalRunRow(originalID, workflows.StatusFailed, params))
+
+	stream, run, err := sys.Replay(context.Background(), originalID, "secret", 0)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	for range stream {
+	}
+
+	if run.ID == originalID {
+		t.Error("Replay() reused the original run ID, want a new run")
+	}
+	if run.ReplayedFrom == nil || *run.ReplayedFrom != originalID {
+		t.Errorf("run.ReplayedFrom = %v, want %s", run.ReplayedFrom, originalID)
+	}
+
+	fdb.mu.Lock()
+	inserts := fdb.runInserts
+	fdb.mu.Unlock()
+
+	if len(inserts) != 1 {
+		t.Fatalf("run inserts = %d, want 1", len(inserts))
+	}
+	args := inserts[0].args
+
+	if args[0] != replayWorkflow {
+		t.Errorf("workflow_name = %v, want %s", args[0], replayWorkflow)
+	}
+
+	var got, want map[string]any
+	if err := json.Unmarshal(args[2].([]byte), &got); err != nil {
+		t.Fatalf("inserted params are not JSON: %v", err)
+	}
+	json.Unmarshal([]byte(params), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("params = %v, want %v", got, want)
+	}
+
+	if args[3] != originalID.String() {
+		t.Errorf("replayed_from = %v, want %s", args[3], originalID)
+	}
+}
+
+func TestExecutor_Execute_NoReplayedFrom(t *testing.T) {
+	sys, fdb := newReplaySystem(t, originalRunRow(uuid.New(), workflows.StatusFailed, `{}`))
+
+	stream, _, err := sys.Execute(replayWorkflow, nil, "", 0)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for range stream {
+	}
+
+	fdb.mu.Lock()
+	defer fdb.mu.Unlock()
+	if len(fdb.runInserts) != 1 || fdb.runInserts[0].args[3] != nil {
+		t.Errorf("run inserts = %v, want one with nil replayed_from", fdb.runInserts)
+	}
+}
+
+func TestExecutor_Replay_ActiveRun(t *testing.T) {
+	for _, status := range []workflows.RunStatus{workflows.StatusPending, workflows.StatusRunning} {
+		t.Run(string(status), func(t *testing.T) {
+			originalID := uuid.New()
+			sys, fdb := newReplaySystem(t, originalRunRow(originalID, status, `{}`))
+
+			if _, _, err := sys.Replay(context.Background(), originalID, "", 0); !errors.Is(err, workflows.ErrInvalidStatus) {
+				t.Errorf("Replay() error = %v, want ErrInvalidStatus", err)
+			}
+			if len(fdb.runInserts) != 0 {
+				t.Errorf("run inserts = %d, want 0", len(fdb.runInserts))
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package internal_workflows_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/google/uuid"
+)
+
+func TestParseReportFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    workflows.ReportFormat
+		wantErr bool
+	}{
+		{"", workflows.ReportJSON, false},
+		{"json", workflows.ReportJSON, false},
+		{"md", workflows.ReportMarkdown, false},
+		{"pdf", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := workflows.ParseReportFormat(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, workflows.ErrInvalidReportFormat) {
+					t.Errorf("ParseReportFormat(%q) error = %v, want ErrInvalidReportFormat", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReportFormat(%q) error = %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseReportFormat(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderReport_Generic(t *testing.T) {
+	started := time.Now().Add(-2 * time.Second)
+	completed := started.Add(1500 * time.Millisecond)
+	run := &workflows.Run{
+		ID:           uuid.New(),
+		WorkflowName: "report-generic-workflow",
+		Status:       workflows.StatusCompleted,
+		Result:       json.RawMessage(`{"answer":42}`),
+		StartedAt:    &started,
+		CompletedAt:  &completed,
+	}
+
+	got, err := workflows.RenderReport(run, workflows.ReportJSON)
+	if err != nil {
+		t.Fatalf("RenderReport(json) error = %v", err)
+	}
+
+	report, ok := got.(workflows.RunReport)
+	if !ok {
+		t.Fatalf("RenderReport(json) type = %T, want RunReport", got)
+	}
+	if report.RunID != run.ID || report.WorkflowName != run.WorkflowName {
+		t.Errorf("report = %+v, want run metadata", report)
+	}
+	if report.DurationMs == nil || *report.DurationMs != 1500 {
+		t.Errorf("DurationMs = %v, want 1500", report.DurationMs)
+	}
+	if string(report.Result) != `{"answer":42}` {
+		t.Errorf("Result = %s, want stored result", report.Result)
+	}
+
+	if _, err := workflows.RenderReport(run, workflows.ReportMarkdown); !errors.Is(err, workflows.ErrReportUnsupported) {
+		t.Errorf("RenderReport(md) error = %v, want ErrReportUnsupported", err)
+	}
+}
+
+type stubReporter struct{}
+
+func (stubReporter) JSON(run *workflows.Run) (any, error) {
+	return map[string]string{"stub": "json"}, nil
+}
+func (stubReporter) Markdown(run *workflows.Run) (string, error) { return "# stub", nil }
+
+func TestRenderReport_RegisteredReporter(t *testing.T) {
+	workflows.RegisterReporter("report-stub-workflow", stubReporter{})
+
+	if _, ok := workflows.GetReporter("report-stub-workflow"); !ok {
+		t.Fatal("GetReporter() exists = false, want true")
+	}
+
+	run := &workflows.Run{ID: uuid.New(), WorkflowName: "report-stub-workflow"}
+
+	got, err := workflows.RenderReport(run, workflows.ReportMarkdown)
+	if err != nil {
+		t.Fatalf("RenderReport(md) error = %v", err)
+	}
+	if got != "# stub" {
+		t.Errorf("RenderReport(md) = %v, want %q", got, "# stub")
+	}
+}
@@ -0,0 +1,426 @@
+package internal_workflows_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// fakeRow maps unqualified column names to values. A row with a fakeTable
+// entry only matches queries against that table.
+type fakeRow map[string]driver.Value
+
+// fakeTable is the fakeRow key naming the table a row belongs to.
+const fakeTable = "_table"
+
+// fakeDB serves SELECT and COUNT queries generated by query.Builder against
+// in-memory rows. Rows are returned in insertion order; equality conditions,
+// greater-than conditions on timestamps and strings, LIMIT with or without
+// OFFSET are honored, and SELECT DISTINCT drops repeated rows. SELECT
+// statements are recorded in selects.
+// INSERT or UPDATE statements against runs
+// return the run row, with a fresh id on INSERT when the row has none, and
+// are recorded in runInserts and runUpdates respectively; other
+// statements succeed without effect and are recorded in execs.
+type fakeDB struct {
+	rows []fakeRow
+	run  fakeRow
+
+	mu         sync.Mutex
+	execs      []fakeExec
+	selects    []fakeExec
+	runInserts []fakeExec
+	runUpdates []fakeExec
+}
+
+// fakeExec is a statement executed against a fakeDB.
+type fakeExec struct {
+	query string
+	args  []driver.Value
+}
+
+var (
+	fakeDBMu sync.Mutex
+	fakeDBs  = map[string]*fakeDB{}
+
+	selectPattern = regexp.MustCompile(`^SELECT (.+?) FROM `)
+	fromPattern   = regexp.MustCompile(` FROM (?:\w+\.)?(\w+)`)
+	wherePattern  = regexp.MustCompile(`\w+\.(\w+) = \$(\d+)`)
+	afterPattern  = regexp.MustCompile(`\w+\.(\w+) > \$(\d+)`)
+	limitPattern  = regexp.MustCompile(`LIMIT (\d+)(?: OFFSET (\d+))?`)
+)
+
+func init() {
+	sql.Register("fakeworkflows", fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fakeDBMu.Lock()
+	defer fakeDBMu.Unlock()
+	return &fakeConn{db: fakeDBs[dsn]}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(q string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: q}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	s.db.execs = append(s.db.execs, fakeExec{query: s.query, args: args})
+	s.db.mu.Unlock()
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, "INSERT INTO runs") {
+		s.db.mu.Lock()
+		s.db.runInserts = append(s.db.runInserts, fakeExec{query: s.query, args: args})
+		s.db.mu.Unlock()
+	}
+
+	if strings.Contains(s.query, "UPDATE runs") {
+		s.db.mu.Lock()
+		s.db.runUpdates = append(s.db.runUpdates, fakeExec{query: s.query, args: args})
+		s.db.mu.Unlock()
+	}
+
+	if strings.Contains(s.query, "INSERT INTO runs") || strings.Contains(s.query, "UPDATE runs") {
+		return s.runRow(strings.Contains(s.query, "INSERT INTO runs")), nil
+	}
+
+	if strings.HasPrefix(s.query, "SELECT ") {
+		s.db.mu.Lock()
+		s.db.selects = append(s.db.selects, fakeExec{query: s.query, args: args})
+		s.db.mu.Unlock()
+	}
+
+	var matched []fakeRow
+	for _, row := range s.db.rows {
+		if s.matches(row, args) {
+			matched = append(matched, row)
+		}
+	}
+
+	if strings.HasPrefix(s.query, "SELECT COUNT(*)") {
+		return &fakeRows{cols: []string{"count"}, values: [][]driver.Value{{int64(len(matched))}}}, nil
+	}
+
+	if m := limitPattern.FindStringSubmatch(s.query); m != nil {
+		var limit, offset int
+		fmt.Sscan(m[1], &limit)
+		fmt.Sscan(m[2], &offset)
+		matched = matched[min(offset, len(matched)):min(offset+limit, len(matched))]
+	}
+
+	sel := selectPattern.FindStringSubmatch(s.query)
+	if sel == nil {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+
+	columns, distinct := strings.CutPrefix(sel[1], "DISTINCT ")
+
+	var cols []string
+	for _, c := range strings.Split(columns, ", ") {
+		cols = append(cols, c[strings.Index(c, ".")+1:])
+	}
+
+	rows := &fakeRows{cols: cols}
+	seen := map[string]bool{}
+	for _, row := range matched {
+		values := make([]driver.Value, len(cols))
+		for i, c := range cols {
+			values[i] = row[c]
+		}
+		if distinct {
+			key := fmt.Sprint(values)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		rows.values = append(rows.values, values)
+	}
+	return rows, nil
+}
+
+func (s *fakeStmt) runRow(insert bool) driver.Rows {
+	cols := []string{"id", "workflow_name", "status", "params", "result", "error_message", "cancel_reason", "replayed_from", "started_at", "completed_at", "created_at", "updated_at"}
+
+	values := make([]driver.Value, len(cols))
+	for i, c := range cols {
+		values[i] = s.db.run[c]
+	}
+	if insert && values[0] == nil {
+		values[0] = uuid.New().String()
+	}
+	return &fakeRows{cols: cols, values: [][]driver.Value{values}}
+}
+
+func (s *fakeStmt) matches(row fakeRow, args []driver.Value) bool {
+	if table, ok := row[fakeTable]; ok {
+		if m := fromPattern.FindStringSubmatch(s.query); m == nil || m[1] != table {
+			return false
+		}
+	}
+	for _, m := range wherePattern.FindAllStringSubmatch(s.query, -1) {
+		var n int
+		fmt.Sscan(m[2], &n)
+		if fmt.Sprint(row[m[1]]) != fmt.Sprint(args[n-1]) {
+			return false
+		}
+	}
+	for _, m := range afterPattern.FindAllStringSubmatch(s.query, -1) {
+		var n int
+		fmt.Sscan(m[2], &n)
+		if at, ok := args[n-1].(time.Time); ok {
+			if v, _ := row[m[1]].(time.Time); !v.After(at) {
+				return false
+			}
+			continue
+		}
+		if fmt.Sprint(row[m[1]]) <= fmt.Sprint(args[n-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+type fakeRows struct {
+	cols   []string
+	values [][]driver.Value
+	pos    int
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+func newFakeRepo(t *testing.T, rows []fakeRow) interface {
+	ListStages(ctx context.Context, runID uuid.UUID, page pagination.PageRequest, filters workflows.StageFilters) (*pagination.PageResult[workflows.Stage], error)
+	GetStages(ctx context.Context, runID uuid.UUID, filters workflows.StageFilters) ([]workflows.Stage, error)
+	ListDecisions(ctx context.Context, runID uuid.UUID, page pagination.PageRequest) (*pagination.PageResult[workflows.Decision], error)
+} {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return workflows.New(openFakeDB(t, &fakeDB{rows: rows}), logger, pagination.Config{DefaultPageSize: 2, MaxPageSize: 10})
+}
+
+func openFakeDB(t *testing.T, fdb *fakeDB) *sql.DB {
+	t.Helper()
+
+	fakeDBMu.Lock()
+	fakeDBs[t.Name()] = fdb
+	fakeDBMu.Unlock()
+
+	db, err := sql.Open("fakeworkflows", t.Name())
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func stageRow(runID uuid.UUID, node, status string, at time.Time) fakeRow {
+	return fakeRow{
+		fakeTable:         "stages",
+		"id":              uuid.NewString(),
+		"run_id":          runID.String(),
+		"node_name":       node,
+		"iteration":       int64(1),
+		"status":          status,
+		"input_snapshot":  nil,
+		"output_snapshot": nil,
+		"duration_ms":     nil,
+		"error_message":   nil,
+		"created_at":      at,
+	}
+}
+
+func decisionRow(runID uuid.UUID, from string, at time.Time) fakeRow {
+	return fakeRow{
+		fakeTable:          "decisions",
+		"id":               uuid.NewString(),
+		"run_id":           runID.String(),
+		"from_node":        from,
+		"to_node":          nil,
+		"predicate_name":   nil,
+		"predicate_result": nil,
+		"reason":           nil,
+		"created_at":       at,
+	}
+}
+
+func stageRows(runID, otherRun uuid.UUID) []fakeRow {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []fakeRow{
+		stageRow(runID, "init", "completed", base),
+		stageRow(runID, "classify", "completed", base.Add(time.Second)),
+		stageRow(runID, "classify", "failed", base.Add(2*time.Second)),
+		stageRow(otherRun, "classify", "completed", base.Add(3*time.Second)),
+		stageRow(runID, "classify", "completed", base.Add(4*time.Second)),
+		stageRow(runID, "finalize", "completed", base.Add(5*time.Second)),
+	}
+}
+
+func TestRepo_ListStages_Paging(t *testing.T) {
+	runID, otherRun := uuid.New(), uuid.New()
+	r := newFakeRepo(t, stageRows(runID, otherRun))
+
+	tests := []struct {
+		page      int
+		wantNodes []string
+	}{
+		{1, []string{"init", "classify"}},
+		{2, []string{"classify", "classify"}},
+		{3, []string{"finalize"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("page %d", tt.page), func(t *testing.T) {
+			result, err := r.ListStages(context.Background(), runID, pagination.PageRequest{Page: tt.page, PageSize: 2}, workflows.StageFilters{})
+			if err != nil {
+				t.Fatalf("ListStages() error = %v", err)
+			}
+
+			if result.Total != 5 {
+				t.Errorf("Total = %d, want 5", result.Total)
+			}
+
+			if result.TotalPages != 3 {
+				t.Errorf("TotalPages = %d, want 3", result.TotalPages)
+			}
+
+			var nodes []string
+			for _, s := range result.Data {
+				if s.RunID != runID {
+					t.Errorf("stage from run %s, want %s", s.RunID, runID)
+				}
+				nodes = append(nodes, s.NodeName)
+			}
+
+			if strings.Join(nodes, ",") != strings.Join(tt.wantNodes, ",") {
+				t.Errorf("nodes = %v, want %v", nodes, tt.wantNodes)
+			}
+		})
+	}
+}
+
+func TestRepo_ListStages_Filters(t *testing.T) {
+	runID, otherRun := uuid.New(), uuid.New()
+	r := newFakeRepo(t, stageRows(runID, otherRun))
+
+	tests := []struct {
+		name      string
+		filters   workflows.StageFilters
+		wantTotal int
+	}{
+		{"node_name", workflows.StageFilters{NodeName: strPtr("classify")}, 3},
+		{"status", workflows.StageFilters{Status: strPtr("failed")}, 1},
+		{"node_name and status", workflows.StageFilters{NodeName: strPtr("classify"), Status: strPtr("completed")}, 2},
+		{"no match", workflows.StageFilters{NodeName: strPtr("missing")}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.ListStages(context.Background(), runID, pagination.PageRequest{Page: 1, PageSize: 10}, tt.filters)
+			if err != nil {
+				t.Fatalf("ListStages() error = %v", err)
+			}
+
+			if result.Total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", result.Total, tt.wantTotal)
+			}
+
+			for _, s := range result.Data {
+				if tt.filters.NodeName != nil && s.NodeName != *tt.filters.NodeName {
+					t.Errorf("NodeName = %q, want %q", s.NodeName, *tt.filters.NodeName)
+				}
+				if tt.filters.Status != nil && string(s.Status) != *tt.filters.Status {
+					t.Errorf("Status = %q, want %q", s.Status, *tt.filters.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestRepo_GetStages_AllWithFilters(t *testing.T) {
+	runID, otherRun := uuid.New(), uuid.New()
+	r := newFakeRepo(t, stageRows(runID, otherRun))
+
+	stages, err := r.GetStages(context.Background(), runID, workflows.StageFilters{NodeName: strPtr("classify")})
+	if err != nil {
+		t.Fatalf("GetStages() error = %v", err)
+	}
+
+	if len(stages) != 3 {
+		t.Errorf("len(stages) = %d, want 3", len(stages))
+	}
+}
+
+func TestRepo_ListDecisions_Paging(t *testing.T) {
+	runID := uuid.New()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var rows []fakeRow
+	for i := range 3 {
+		rows = append(rows, decisionRow(runID, fmt.Sprintf("node-%d", i), base.Add(time.Duration(i)*time.Second)))
+	}
+	r := newFakeRepo(t, rows)
+
+	result, err := r.ListDecisions(context.Background(), runID, pagination.PageRequest{Page: 2, PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListDecisions() error = %v", err)
+	}
+
+	if result.Total != 3 || result.TotalPages != 2 {
+		t.Errorf("Total = %d, TotalPages = %d, want 3 and 2", result.Total, result.TotalPages)
+	}
+
+	if len(result.Data) != 1 || result.Data[0].FromNode != "node-2" {
+		t.Errorf("Data = %+v, want single decision from node-2", result.Data)
+	}
+}
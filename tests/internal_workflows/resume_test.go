@@ -0,0 +1,182 @@
+package internal_workflows_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+	"github.com/google/uuid"
+)
+
+const resumeWorkflow = "test-resume-from-node"
+
+// resumeTrace records the nodes executed by the resume test workflow and the
+// "origin" value each node observed in its input state.
+var resumeTrace = struct {
+	sync.Mutex
+	nodes   []string
+	origins []string
+}{}
+
+func init() {
+	workflows.Register(resumeWorkflow, func(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+		nodes := []string{"first", "second", "third"}
+		for _, name := range nodes {
+			graph.AddNode(name, state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+				origin, _ := s.Get("origin")
+
+				resumeTrace.Lock()
+				resumeTrace.nodes = append(resumeTrace.nodes, name)
+				resumeTrace.origins = append(resumeTrace.origins, origin.(string))
+				resumeTrace.Unlock()
+
+				return s, nil
+			}))
+		}
+		graph.AddEdge("first", "second", nil)
+		graph.AddEdge("second", "third", nil)
+		graph.SetEntryPoint("first")
+		graph.SetExitPoint("third")
+		return state.New(nil), nil
+	}, "Three-node workflow for resume tests")
+}
+
+func resetResumeTrace() ([]string, []string) {
+	resumeTrace.Lock()
+	defer resumeTrace.Unlock()
+	nodes, origins := resumeTrace.nodes, resumeTrace.origins
+	resumeTrace.nodes, resumeTrace.origins = nil, nil
+	return nodes, origins
+}
+
+func checkpointRow(t *testing.T, runID uuid.UUID, node, origin string) fakeRow {
+	t.Helper()
+
+	st := state.New(nil).Set("origin", origin).SetCheckpointNode(node)
+	st.RunID = runID.String()
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		t.Fatalf("marshal state: %v", err)
+	}
+
+	return fakeRow{
+		"run_id":          runID.String(),
+		"checkpoint_node": node,
+		"state_data":      data,
+	}
+}
+
+// newResumeSystem returns a System backed by a fake database holding a failed
+// run of the resume test workflow. The latest checkpoint is after "second";
+// per-node checkpoints exist for "first" and "second" only.
+func newResumeSystem(t *testing.T) (workflows.System, uuid.UUID) {
+	t.Helper()
+
+	runID := uuid.New()
+	now := time.Now()
+	run := fakeRow{
+		"id":            runID.String(),
+		"workflow_name": resumeWorkflow,
+		"status":        string(workflows.StatusFailed),
+		"created_at":    now,
+		"updated_at":    now,
+	}
+
+	db := openFakeDB(t, &fakeDB{
+		run: run,
+		rows: []fakeRow{
+			checkpointRow(t, runID, "second", "latest"),
+			checkpointRow(t, runID, "first", "after-first"),
+			run,
+		},
+	})
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	runtime := workflows.NewRuntime(nil, nil, nil, nil, lifecycle.New(), logger)
+	sys := workflows.NewSystem(runtime, db, nil, logger, pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0, 0)
+
+	resetResumeTrace()
+	t.Cleanup(func() { resetResumeTrace() })
+
+	return sys, runID
+}
+
+func TestExecutor_Resume_FromLatestCheckpoint(t *testing.T) {
+	sys, runID := newResumeSystem(t)
+
+	if _, err := sys.Resume(context.Background(), runID, ""); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	nodes, origins := resetResumeTrace()
+	if !slices.Equal(nodes, []string{"third"}) {
+		t.Errorf("executed nodes = %v, want [third]", nodes)
+	}
+	if !slices.Equal(origins, []string{"latest"}) {
+		t.Errorf("origins = %v, want [latest]", origins)
+	}
+}
+
+func TestExecutor_Resume_FromNamedNode(t *testing.T) {
+	sys, runID := newResumeSystem(t)
+
+	if _, err := sys.Resume(context.Background(), runID, "first"); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+
+	nodes, origins := resetResumeTrace()
+	if !slices.Equal(nodes, []string{"second", "third"}) {
+		t.Errorf("executed nodes = %v, want [second third]", nodes)
+	}
+	if !slices.Equal(origins, []string{"after-first", "after-first"}) {
+		t.Errorf("origins = %v, want checkpoint state from node first", origins)
+	}
+}
+
+func TestExecutor_Resume_InvalidNode(t *testing.T) {
+	sys, runID := newResumeSystem(t)
+
+	_, err := sys.Resume(context.Background(), runID, "missing")
+	if !errors.Is(err, workflows.ErrInvalidNode) {
+		t.Fatalf("Resume() error = %v, want ErrInvalidNode", err)
+	}
+	if !strings.Contains(err.Error(), "first, second, third") {
+		t.Errorf("error %q should list available nodes", err)
+	}
+	if got := workflows.MapHTTPStatus(err); got != http.StatusBadRequest {
+		t.Errorf("MapHTTPStatus() = %d, want %d", got, http.StatusBadRequest)
+	}
+
+	if nodes, _ := resetResumeTrace(); len(nodes) != 0 {
+		t.Errorf("executed nodes = %v, want none", nodes)
+	}
+}
+
+func TestExecutor_Resume_NodeWithoutCheckpoint(t *testing.T) {
+	sys, runID := newResumeSystem(t)
+
+	_, err := sys.Resume(context.Background(), runID, "third")
+	if !errors.Is(err, workflows.ErrCheckpointNotFound) {
+		t.Fatalf("Resume() error = %v, want ErrCheckpointNotFound", err)
+	}
+	if got := workflows.MapHTTPStatus(err); got != http.StatusConflict {
+		t.Errorf("MapHTTPStatus() = %d, want %d", got, http.StatusConflict)
+	}
+
+	if nodes, _ := resetResumeTrace(); len(nodes) != 0 {
+		t.Errorf("executed nodes = %v, want none", nodes)
+	}
+}
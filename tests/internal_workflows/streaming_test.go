@@ -210,3 +210,94 @@ func TestStreamingObserver_OnEvent_EdgeTransition(t *testing.T) {
 		t.Fatal("Timed out waiting for event")
 	}
 }
+
+func TestReportProgress_StreamingObserver(t *testing.T) {
+	obs := workflows.NewStreamingObserver(10)
+	ctx := workflows.WithProgress(context.Background(), obs.SendProgress)
+
+	workflows.ReportProgress(ctx, workflows.EventPageComplete, map[string]any{"page_number": 2})
+
+	select {
+	case event := <-obs.Events():
+		if event.Type != workflows.EventPageComplete {
+			t.Errorf("Type = %q, want %q", event.Type, workflows.EventPageComplete)
+		}
+		if event.Data["page_number"] != 2 {
+			t.Errorf("Data[page_number] = %v, want 2", event.Data["page_number"])
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timed out waiting for event")
+	}
+}
+
+func TestReportProgress_NoProgressFunc(t *testing.T) {
+	workflows.ReportProgress(context.Background(), workflows.EventPageComplete, map[string]any{"page_number": 1})
+}
+
+func TestStreamingObserver_NumbersEvents(t *testing.T) {
+	obs := workflows.NewStreamingObserver(10)
+	events := obs.Events()
+
+	obs.OnEvent(context.Background(), observability.Event{
+		Type: observability.EventNodeStart,
+		Data: map[string]any{"node": "detect", "iteration": 1},
+	})
+	obs.SendProgress(workflows.EventPageComplete, map[string]any{"page": 1})
+	obs.OnEvent(context.Background(), observability.Event{
+		Type: observability.EventNodeComplete,
+		Data: map[string]any{"node": "detect", "iteration": 1},
+	})
+	obs.SendComplete(map[string]any{})
+	obs.Close()
+
+	var ids []int
+	for event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	want := []int{1, 0, 2, 3}
+	if len(ids) != len(want) {
+		t.Fatalf("IDs = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("IDs = %v, want %v", ids, want)
+			break
+		}
+	}
+}
+
+func TestStreamingObserver_Subscribe(t *testing.T) {
+	obs := workflows.NewStreamingObserver(10)
+
+	obs.SendProgress(workflows.EventPageComplete, map[string]any{"page": 1})
+
+	sub, stop := obs.Subscribe(10)
+	obs.SendError(errorf("boom"), "")
+
+	select {
+	case event := <-sub:
+		if event.Type != workflows.EventError || event.ID != 1 {
+			t.Errorf("event = %+v, want error event with ID 1", event)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timed out waiting for subscribed event")
+	}
+
+	stop()
+	if _, ok := <-sub; ok {
+		t.Error("expected channel closed after stop")
+	}
+	stop()
+
+	sub, _ = obs.Subscribe(10)
+	obs.Close()
+	if _, ok := <-sub; ok {
+		t.Error("expected subscribed channel closed with observer")
+	}
+
+	sub, _ = obs.Subscribe(10)
+	if _, ok := <-sub; ok {
+		t.Error("expected closed channel from closed observer")
+	}
+}
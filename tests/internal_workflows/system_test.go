@@ -17,14 +17,24 @@ func TestSystem_Interface(t *testing.T) {
 	t.Run("interface has expected methods", func(t *testing.T) {
 		type systemInterface interface {
 			ListWorkflows() []workflows.WorkflowInfo
-			Execute(name string, params map[string]any, token string) (<-chan workflows.ExecutionEvent, *workflows.Run, error)
+			DescribeWorkflow(ctx context.Context, name string) (*workflows.WorkflowGraph, error)
+			Execute(name string, params map[string]any, token string, priority int) (<-chan workflows.ExecutionEvent, *workflows.Run, error)
 			ListRuns(ctx context.Context, page pagination.PageRequest, filters workflows.RunFilters) (*pagination.PageResult[workflows.Run], error)
+			RunFacets(ctx context.Context, field string, filters workflows.RunFilters) (*workflows.RunFacets, error)
 			FindRun(ctx context.Context, id uuid.UUID) (*workflows.Run, error)
-			GetStages(ctx context.Context, runID uuid.UUID) ([]workflows.Stage, error)
+			ActiveRuns() []uuid.UUID
+			ListActiveRuns(ctx context.Context) ([]workflows.Run, error)
+			QueuedRuns() []workflows.QueuedRun
+			ListStages(ctx context.Context, runID uuid.UUID, page pagination.PageRequest, filters workflows.StageFilters) (*pagination.PageResult[workflows.Stage], error)
+			GetStages(ctx context.Context, runID uuid.UUID, filters workflows.StageFilters) ([]workflows.Stage, error)
+			ListDecisions(ctx context.Context, runID uuid.UUID, page pagination.PageRequest) (*pagination.PageResult[workflows.Decision], error)
 			GetDecisions(ctx context.Context, runID uuid.UUID) ([]workflows.Decision, error)
 			DeleteRun(ctx context.Context, id uuid.UUID) error
-			Cancel(ctx context.Context, runID uuid.UUID) error
-			Resume(ctx context.Context, runID uuid.UUID) (*workflows.Run, error)
+			Cancel(ctx context.Context, runID uuid.UUID, reason workflows.CancelReason) error
+			CancelAll() int
+			Resume(ctx context.Context, runID uuid.UUID, fromNode string) (*workflows.Run, error)
+			Replay(ctx context.Context, runID uuid.UUID, token string, priority int) (<-chan workflows.ExecutionEvent, *workflows.Run, error)
+			Drain(ctx context.Context) error
 		}
 
 		var _ systemInterface = (workflows.System)(nil)
@@ -0,0 +1,215 @@
+package internal_workflows_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// traceFixture returns a run with three stages and two decisions whose
+// creation times interleave: detect, detect->enhance, enhance,
+// enhance->classify, classify.
+func traceFixture() (*workflows.Run, []workflows.Stage, []workflows.Decision) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return base.Add(time.Duration(seconds) * time.Second) }
+	runID := uuid.New()
+	snapshot := json.RawMessage(`{"pages":[1,2,3]}`)
+	to := func(s string) *string { return &s }
+
+	run := &workflows.Run{ID: runID, WorkflowName: "classify-docs", Status: workflows.StatusCompleted, CreatedAt: base}
+
+	stages := []workflows.Stage{
+		{ID: uuid.New(), RunID: runID, NodeName: "detect", InputSnapshot: snapshot, OutputSnapshot: snapshot, CreatedAt: at(1)},
+		{ID: uuid.New(), RunID: runID, NodeName: "enhance", InputSnapshot: snapshot, OutputSnapshot: snapshot, CreatedAt: at(3)},
+		{ID: uuid.New(), RunID: runID, NodeName: "classify", InputSnapshot: snapshot, OutputSnapshot: snapshot, CreatedAt: at(5)},
+	}
+
+	decisions := []workflows.Decision{
+		{ID: uuid.New(), RunID: runID, FromNode: "detect", ToNode: to("enhance"), CreatedAt: at(2)},
+		{ID: uuid.New(), RunID: runID, FromNode: "enhance", ToNode: to("classify"), CreatedAt: at(4)},
+	}
+
+	return run, stages, decisions
+}
+
+// traceLabels summarizes trace entries as "stage:node" or "decision:from->to".
+func traceLabels(entries []workflows.TraceEntry) []string {
+	labels := make([]string, 0, len(entries))
+	for _, e := range entries {
+		switch e.Type {
+		case workflows.TraceEntryRun:
+			labels = append(labels, "run")
+		case workflows.TraceEntryStage:
+			labels = append(labels, "stage:"+e.Stage.NodeName)
+		case workflows.TraceEntryDecision:
+			labels = append(labels, "decision:"+e.Decision.FromNode+"->"+*e.Decision.ToNode)
+		}
+	}
+	return labels
+}
+
+func TestBuildTrace_ChronologicalOrder(t *testing.T) {
+	run, stages, decisions := traceFixture()
+
+	trace := workflows.BuildTrace(run, stages, decisions, true)
+
+	want := "stage:detect,decision:detect->enhance,stage:enhance,decision:enhance->classify,stage:classify"
+	if got := strings.Join(traceLabels(trace.Events), ","); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+
+	if trace.Run.ID != run.ID {
+		t.Errorf("Run.ID = %s, want %s", trace.Run.ID, run.ID)
+	}
+
+	for _, e := range trace.Events {
+		if e.Type == workflows.TraceEntryStage && (e.Stage.InputSnapshot == nil || e.Stage.OutputSnapshot == nil) {
+			t.Errorf("stage %s missing snapshots", e.Stage.NodeName)
+		}
+	}
+}
+
+func TestBuildTrace_OmitsSnapshots(t *testing.T) {
+	run, stages, decisions := traceFixture()
+
+	trace := workflows.BuildTrace(run, stages, decisions, false)
+
+	for _, e := range trace.Events {
+		if e.Type == workflows.TraceEntryStage && (e.Stage.InputSnapshot != nil || e.Stage.OutputSnapshot != nil) {
+			t.Errorf("stage %s should omit snapshots", e.Stage.NodeName)
+		}
+	}
+
+	if stages[0].InputSnapshot == nil {
+		t.Error("BuildTrace should not modify the caller's stages")
+	}
+}
+
+func TestBuildTrace_TiesKeepStageFirst(t *testing.T) {
+	run, stages, decisions := traceFixture()
+	decisions[0].CreatedAt = stages[0].CreatedAt
+
+	trace := workflows.BuildTrace(run, stages, decisions, true)
+
+	if got := traceLabels(trace.Events[:2]); got[0] != "stage:detect" || got[1] != "decision:detect->enhance" {
+		t.Errorf("first events = %v, want stage before its decision", got)
+	}
+}
+
+func TestParseTraceFormat(t *testing.T) {
+	for _, v := range []string{"", "json", "ndjson"} {
+		if _, err := workflows.ParseTraceFormat(v); err != nil {
+			t.Errorf("ParseTraceFormat(%q) error = %v", v, err)
+		}
+	}
+
+	if _, err := workflows.ParseTraceFormat("xml"); !errors.Is(err, workflows.ErrInvalidTraceFormat) {
+		t.Errorf("ParseTraceFormat(xml) error = %v, want ErrInvalidTraceFormat", err)
+	}
+}
+
+// traceSpy serves a fixed run, stages, and decisions; other System methods are not used.
+type traceSpy struct {
+	workflows.System
+	run       *workflows.Run
+	stages    []workflows.Stage
+	decisions []workflows.Decision
+}
+
+func (s *traceSpy) FindRun(ctx context.Context, id uuid.UUID) (*workflows.Run, error) {
+	return s.run, nil
+}
+
+func (s *traceSpy) GetStages(ctx context.Context, runID uuid.UUID, filters workflows.StageFilters) ([]workflows.Stage, error) {
+	return s.stages, nil
+}
+
+func (s *traceSpy) GetDecisions(ctx context.Context, runID uuid.UUID) ([]workflows.Decision, error) {
+	return s.decisions, nil
+}
+
+func serveTrace(t *testing.T, query string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	run, stages, decisions := traceFixture()
+	spy := &traceSpy{run: run, stages: stages, decisions: decisions}
+	handler := workflows.NewHandler(spy, slog.New(slog.NewTextHandler(io.Discard, nil)), pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/workflows/runs/"+run.ID.String()+"/trace"+query, nil)
+	req.SetPathValue("id", run.ID.String())
+	rec := httptest.NewRecorder()
+
+	handler.GetTrace(rec, req)
+	return rec
+}
+
+func TestHandler_GetTrace_JSON(t *testing.T) {
+	rec := serveTrace(t, "?snapshots=false")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var trace workflows.RunTrace
+	if err := json.NewDecoder(rec.Body).Decode(&trace); err != nil {
+		t.Fatalf("decode trace: %v", err)
+	}
+
+	if len(trace.Events) != 5 {
+		t.Fatalf("events = %d, want 5", len(trace.Events))
+	}
+	if strings.Contains(rec.Body.String(), "input_snapshot") {
+		t.Error("snapshots=false should omit stage snapshots")
+	}
+}
+
+func TestHandler_GetTrace_NDJSON(t *testing.T) {
+	rec := serveTrace(t, "?format=ndjson")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q, want application/x-ndjson", ct)
+	}
+
+	body := rec.Body.String()
+
+	var entries []workflows.TraceEntry
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var e workflows.TraceEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+
+	want := "run,stage:detect,decision:detect->enhance,stage:enhance,decision:enhance->classify,stage:classify"
+	if got := strings.Join(traceLabels(entries), ","); got != want {
+		t.Errorf("lines = %s, want %s", got, want)
+	}
+	if !strings.Contains(body, "input_snapshot") {
+		t.Error("snapshots should be included by default")
+	}
+}
+
+func TestHandler_GetTrace_InvalidParams(t *testing.T) {
+	for _, query := range []string{"?format=xml", "?snapshots=maybe"} {
+		if rec := serveTrace(t, query); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET trace%s status = %d, want %d", query, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package pkg_events_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/events"
+)
+
+func testBus() *events.Bus {
+	return events.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func waitBus(t *testing.T, bus *events.Bus) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := bus.Wait(ctx); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+}
+
+func TestPublish_DeliversToAllSubscribers(t *testing.T) {
+	bus := testBus()
+
+	var mu sync.Mutex
+	received := map[string]int{}
+
+	record := func(name string) events.Handler {
+		return func(ctx context.Context, e events.Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			received[name]++
+		}
+	}
+
+	bus.Subscribe("run.completed", record("first"))
+	bus.Subscribe("run.completed", record("second"))
+	bus.Subscribe(events.All, record("all"))
+	bus.Subscribe("run.failed", record("other"))
+
+	bus.Publish(context.Background(), events.Event{Type: "run.completed", Data: "payload"})
+	waitBus(t, bus)
+
+	for _, name := range []string{"first", "second", "all"} {
+		if received[name] != 1 {
+			t.Errorf("handler %q received %d events, want 1", name, received[name])
+		}
+	}
+	if received["other"] != 0 {
+		t.Errorf("handler for other type received %d events, want 0", received["other"])
+	}
+}
+
+func TestPublish_SetsTime(t *testing.T) {
+	bus := testBus()
+
+	got := make(chan events.Event, 1)
+	bus.Subscribe("doc", func(ctx context.Context, e events.Event) { got <- e })
+
+	before := time.Now()
+	bus.Publish(context.Background(), events.Event{Type: "doc"})
+	waitBus(t, bus)
+
+	e := <-got
+	if e.Time.Before(before) {
+		t.Errorf("Event.Time = %v, want at or after %v", e.Time, before)
+	}
+}
+
+func TestPublish_PanickingHandlerIsolated(t *testing.T) {
+	bus := testBus()
+
+	delivered := make(chan struct{}, 1)
+	bus.Subscribe("evt", func(ctx context.Context, e events.Event) { panic("boom") })
+	bus.Subscribe("evt", func(ctx context.Context, e events.Event) { delivered <- struct{}{} })
+
+	bus.Publish(context.Background(), events.Event{Type: "evt"})
+	waitBus(t, bus)
+
+	select {
+	case <-delivered:
+	default:
+		t.Error("healthy handler did not receive event after another handler panicked")
+	}
+}
+
+func TestPublish_NonBlocking(t *testing.T) {
+	bus := testBus()
+
+	release := make(chan struct{})
+	bus.Subscribe("slow", func(ctx context.Context, e events.Event) { <-release })
+
+	done := make(chan struct{})
+	go func() {
+		bus.Publish(context.Background(), events.Event{Type: "slow"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish() blocked on a slow handler")
+	}
+
+	close(release)
+	waitBus(t, bus)
+}
+
+func TestPublish_DetachesHandlerContext(t *testing.T) {
+	bus := testBus()
+
+	got := make(chan error, 1)
+	bus.Subscribe("evt", func(ctx context.Context, e events.Event) { got <- ctx.Err() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bus.Publish(ctx, events.Event{Type: "evt"})
+	waitBus(t, bus)
+
+	if err := <-got; err != nil {
+		t.Errorf("handler ctx.Err() = %v, want nil", err)
+	}
+}
+
+func TestWait_ExpiresWithSlowHandler(t *testing.T) {
+	bus := testBus()
+
+	release := make(chan struct{})
+	defer close(release)
+	bus.Subscribe("slow", func(ctx context.Context, e events.Event) { <-release })
+	bus.Publish(context.Background(), events.Event{Type: "slow"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := bus.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var bus *events.Bus
+
+	bus.Publish(context.Background(), events.Event{Type: "evt"})
+
+	if err := bus.Wait(context.Background()); err != nil {
+		t.Errorf("nil Bus Wait() error = %v, want nil", err)
+	}
+}
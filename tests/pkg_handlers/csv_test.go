@@ -0,0 +1,147 @@
+package pkg_handlers_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/google/uuid"
+)
+
+type csvTotals struct {
+	Calls int     `json:"calls"`
+	Cost  float64 `json:"cost"`
+}
+
+type csvRecord struct {
+	ID     uuid.UUID       `json:"id"`
+	Name   string          `json:"name"`
+	Note   *string         `json:"note,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Tags   []string        `json:"tags"`
+	csvTotals
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func TestCSVEncoder(t *testing.T) {
+	id := uuid.MustParse("6f1c2d3e-4a5b-4c6d-8e7f-8091a2b3c4d5")
+	at := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	note := `says "hi", twice`
+
+	var sb strings.Builder
+	enc := handlers.NewCSVEncoder[csvRecord](&sb)
+
+	if err := enc.WriteHeader(); err != nil {
+		t.Fatalf("WriteHeader() error = %v", err)
+	}
+	records := []csvRecord{
+		{ID: id, Name: "full", Note: &note, Params: json.RawMessage(`{"a":1}`), Tags: []string{"x", "y"}, csvTotals: csvTotals{Calls: 3, Cost: 0.25}, Secret: "s", CreatedAt: at},
+		{ID: id, Name: "empty", CreatedAt: at},
+	}
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(sb.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, sb.String())
+	}
+
+	want := [][]string{
+		{"id", "name", "note", "params", "tags", "calls", "cost", "created_at"},
+		{id.String(), "full", note, `{"a":1}`, `["x","y"]`, "3", "0.25", "2026-03-01T12:30:00Z"},
+		{id.String(), "empty", "", "", "", "0", "0", "2026-03-01T12:30:00Z"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %d, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if !slices.Equal(rows[i], want[i]) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestWantsCSV(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		want   bool
+	}{
+		{"default", "/runs", "", false},
+		{"json accept", "/runs", "application/json", false},
+		{"format param", "/runs?format=csv", "", true},
+		{"other format", "/runs?format=json", "", false},
+		{"csv accept", "/runs", "text/csv", true},
+		{"csv in accept list", "/runs", "application/json;q=0.9, text/csv;q=1.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if got := handlers.WantsCSV(req); got != tt.want {
+				t.Errorf("WantsCSV() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRespondCSV(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rec := httptest.NewRecorder()
+
+	handlers.RespondCSV(rec, logger, "records.csv", func(enc *handlers.CSVEncoder[csvTotals]) error {
+		return enc.Encode(csvTotals{Calls: 1, Cost: 2})
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename=records.csv` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	if body := rec.Body.String(); body != "calls,cost\n1,2\n" {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestRespondCSV_ErrorBeforeOutput(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rec := httptest.NewRecorder()
+
+	handlers.RespondCSV(rec, logger, "records.csv", func(enc *handlers.CSVEncoder[csvTotals]) error {
+		return errors.New("query failed")
+	})
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != "" {
+		t.Errorf("Content-Disposition = %q, want none", cd)
+	}
+}
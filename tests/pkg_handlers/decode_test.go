@@ -0,0 +1,76 @@
+package pkg_handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+)
+
+type decodeTarget struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		maxBytes   int64
+		wantErr    error
+		wantStatus int
+	}{
+		{"valid", `{"name":"test","count":3}`, 1024, nil, 0},
+		{"default limit", `{"name":"test"}`, 0, nil, 0},
+		{"empty body", ``, 1024, handlers.ErrEmptyBody, http.StatusBadRequest},
+		{"malformed", `{"name":`, 1024, handlers.ErrMalformedJSON, http.StatusBadRequest},
+		{"syntax error", `{"name" "test"}`, 1024, handlers.ErrMalformedJSON, http.StatusBadRequest},
+		{"wrong type", `{"count":"three"}`, 1024, handlers.ErrMalformedJSON, http.StatusBadRequest},
+		{"trailing data", `{"name":"a"}{"name":"b"}`, 1024, handlers.ErrMalformedJSON, http.StatusBadRequest},
+		{"unknown field", `{"name":"test","extra":true}`, 1024, handlers.ErrUnknownField, http.StatusBadRequest},
+		{"oversized", `{"name":"` + strings.Repeat("x", 2048) + `"}`, 64, handlers.ErrBodyTooLarge, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			var dst decodeTarget
+			err := handlers.DecodeJSON(w, r, &dst, tt.maxBytes)
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("DecodeJSON() error = %v", err)
+				}
+				if dst.Name != "test" {
+					t.Errorf("Name = %q, want %q", dst.Name, "test")
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeJSON() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if status := handlers.DecodeStatus(err); status != tt.wantStatus {
+				t.Errorf("DecodeStatus() = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_UnknownFieldMessage(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"bogus":1}`))
+
+	var dst decodeTarget
+	err := handlers.DecodeJSON(w, r, &dst, 1024)
+
+	if err == nil || !strings.Contains(err.Error(), `"bogus"`) {
+		t.Errorf("error = %v, want message naming the unknown field", err)
+	}
+}
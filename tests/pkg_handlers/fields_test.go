@@ -0,0 +1,124 @@
+package pkg_handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/google/uuid"
+)
+
+type fieldsRecord struct {
+	ID     uuid.UUID       `json:"id"`
+	Name   string          `json:"name"`
+	Config json.RawMessage `json:"config"`
+	Count  int64           `json:"count"`
+	Secret string          `json:"-"`
+}
+
+func TestJSONFields(t *testing.T) {
+	want := []string{"id", "name", "config", "count"}
+	if got := handlers.JSONFields[fieldsRecord](); !slices.Equal(got, want) {
+		t.Errorf("JSONFields() = %v, want %v", got, want)
+	}
+	if got := handlers.JSONFields[string](); got != nil {
+		t.Errorf("JSONFields[string]() = %v, want nil", got)
+	}
+}
+
+func TestRequestedFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{"absent", "/agents", nil},
+		{"empty", "/agents?fields=", nil},
+		{"selected", "/agents?fields=id,name", []string{"id", "name"}},
+		{"request order", "/agents?fields=name,id", []string{"name", "id"}},
+		{"unknown ignored", "/agents?fields=id,bogus", []string{"id"}},
+		{"only unknown", "/agents?fields=bogus", nil},
+		{"hidden field", "/agents?fields=Secret,id", []string{"id"}},
+		{"duplicates and spaces", "/agents?fields=id,+name,id", []string{"id", "name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if got := handlers.RequestedFields[fieldsRecord](req); !slices.Equal(got, tt.want) {
+				t.Errorf("RequestedFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRespondFields(t *testing.T) {
+	record := &fieldsRecord{
+		ID:     uuid.New(),
+		Name:   "gpt-4o",
+		Config: json.RawMessage(`{"model":{"name":"gpt-4o"}}`),
+		Count:  1 << 60,
+		Secret: "hidden",
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		wantKeys []string
+	}{
+		{"id and name", "/agents/x?fields=id,name", []string{"id", "name"}},
+		{"absent returns full object", "/agents/x", []string{"config", "count", "id", "name"}},
+		{"empty returns full object", "/agents/x?fields=", []string{"config", "count", "id", "name"}},
+		{"unknown returns full object", "/agents/x?fields=bogus", []string{"config", "count", "id", "name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+
+			handlers.RespondFields(rec, req, http.StatusOK, record)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("response is not a JSON object: %v", err)
+			}
+
+			var keys []string
+			for k := range body {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+
+			if got := string(body["name"]); got != `"gpt-4o"` {
+				t.Errorf("name = %s, want %q", got, record.Name)
+			}
+		})
+	}
+}
+
+func TestSelectFields_PreservesValues(t *testing.T) {
+	record := fieldsRecord{Count: 1<<60 + 1, Config: json.RawMessage(`{"a":[1,2]}`)}
+
+	got, err := handlers.SelectFields(record, []string{"count", "config"})
+	if err != nil {
+		t.Fatalf("SelectFields() error = %v", err)
+	}
+
+	if string(got["count"]) != "1152921504606846977" {
+		t.Errorf("count = %s, want exact integer", got["count"])
+	}
+	if string(got["config"]) != `{"a":[1,2]}` {
+		t.Errorf("config = %s", got["config"])
+	}
+}
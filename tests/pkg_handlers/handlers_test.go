@@ -3,6 +3,7 @@ package pkg_handlers_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -129,12 +130,116 @@ func TestRespondError(t *testing.T) {
 			}
 
 			body, _ := io.ReadAll(resp.Body)
-			var result map[string]string
+			var result handlers.ErrorResponse
 			json.Unmarshal(body, &result)
 
-			if result["error"] != tt.wantError {
-				t.Errorf("error = %q, want %q", result["error"], tt.wantError)
+			if result.Error.Message != tt.wantError {
+				t.Errorf("error.message = %q, want %q", result.Error.Message, tt.wantError)
 			}
 		})
 	}
 }
+
+func TestRespondError_Envelope(t *testing.T) {
+	w := httptest.NewRecorder()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	handlers.RespondError(
+		w, logger, http.StatusBadRequest, errors.New("validation failed"),
+		map[string]any{"name": "required"},
+		map[string]any{"config": "invalid"},
+	)
+
+	var raw map[string]map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	envelope, ok := raw["error"]
+	if !ok {
+		t.Fatal("missing error envelope")
+	}
+
+	if envelope["code"] != "bad_request" {
+		t.Errorf("code = %v, want %q", envelope["code"], "bad_request")
+	}
+
+	if envelope["message"] != "validation failed" {
+		t.Errorf("message = %v, want %q", envelope["message"], "validation failed")
+	}
+
+	details, ok := envelope["details"].(map[string]any)
+	if !ok {
+		t.Fatalf("details = %T, want object", envelope["details"])
+	}
+
+	if details["name"] != "required" || details["config"] != "invalid" {
+		t.Errorf("details = %v, want merged field errors", details)
+	}
+}
+
+func TestRespondError_OmitsEmptyDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	handlers.RespondError(w, logger, http.StatusNotFound, errors.New("missing"))
+
+	var raw map[string]map[string]any
+	json.Unmarshal(w.Body.Bytes(), &raw)
+
+	if _, ok := raw["error"]["details"]; ok {
+		t.Error("details should be omitted when none are provided")
+	}
+}
+
+var (
+	errTestNotFound     = errors.New("widget not found")
+	errTestDuplicate    = errors.New("widget already exists")
+	errTestInvalidRange = errors.New("invalid widget range")
+)
+
+func init() {
+	handlers.RegisterErrorCode("not_found", errTestNotFound)
+	handlers.RegisterErrorCode("duplicate", errTestDuplicate)
+	handlers.RegisterErrorCode("invalid_widget_range", errTestInvalidRange)
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		status   int
+		wantCode string
+	}{
+		{"registered not found", errTestNotFound, http.StatusNotFound, "not_found"},
+		{"wrapped not found", fmt.Errorf("find: %w", errTestNotFound), http.StatusNotFound, "not_found"},
+		{"registered duplicate", errTestDuplicate, http.StatusConflict, "duplicate"},
+		{"domain specific code", fmt.Errorf("render: %w", errTestInvalidRange), http.StatusBadRequest, "invalid_widget_range"},
+		{"unregistered bad request", errors.New("bad"), http.StatusBadRequest, "bad_request"},
+		{"unregistered too large", errors.New("big"), http.StatusRequestEntityTooLarge, "request_entity_too_large"},
+		{"unregistered internal", errors.New("boom"), http.StatusInternalServerError, "internal_server_error"},
+		{"unknown status", errors.New("odd"), 599, "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := handlers.ErrorCode(tt.err, tt.status); got != tt.wantCode {
+				t.Errorf("ErrorCode() = %q, want %q", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRespondError_RegisteredCode(t *testing.T) {
+	w := httptest.NewRecorder()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	handlers.RespondError(w, logger, http.StatusNotFound, fmt.Errorf("lookup: %w", errTestNotFound))
+
+	var result handlers.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &result)
+
+	if result.Error.Code != "not_found" {
+		t.Errorf("code = %q, want %q", result.Error.Code, "not_found")
+	}
+}
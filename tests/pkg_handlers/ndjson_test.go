@@ -0,0 +1,151 @@
+package pkg_handlers_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+)
+
+type ndjsonItem struct {
+	N    int    `json:"n"`
+	Name string `json:"name"`
+}
+
+// flushRecorder counts flushes of the wrapped recorder.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes++
+	r.ResponseRecorder.Flush()
+}
+
+func TestWantsNDJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		want   bool
+	}{
+		{"format param", "/runs?format=ndjson", "", true},
+		{"accept header", "/runs", "application/x-ndjson", true},
+		{"accept list", "/runs", "application/json, application/x-ndjson;q=0.9", true},
+		{"json", "/runs", "application/json", false},
+		{"csv", "/runs?format=csv", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if got := handlers.WantsNDJSON(req); got != tt.want {
+				t.Errorf("WantsNDJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRespondNDJSON_StreamsLines(t *testing.T) {
+	const total = 2*handlers.NDJSONFlushInterval + 5
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	handlers.RespondNDJSON(rec, logger, func(enc *handlers.NDJSONEncoder[ndjsonItem]) error {
+		for i := range total {
+			if err := enc.Encode(ndjsonItem{N: i, Name: "item"}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != handlers.NDJSONContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, handlers.NDJSONContentType)
+	}
+
+	scanner := bufio.NewScanner(rec.Body)
+	lines := 0
+	for scanner.Scan() {
+		var item ndjsonItem
+		if err := json.Unmarshal(scanner.Bytes(), &item); err != nil {
+			t.Fatalf("line %d is not a JSON object: %q", lines, scanner.Text())
+		}
+		if item.N != lines {
+			t.Errorf("line %d n = %d", lines, item.N)
+		}
+		lines++
+	}
+	if lines != total {
+		t.Errorf("lines = %d, want %d", lines, total)
+	}
+
+	if rec.flushes != 3 {
+		t.Errorf("flushes = %d, want 3 (two periodic, one final)", rec.flushes)
+	}
+}
+
+func TestRespondNDJSON_Empty(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rec := httptest.NewRecorder()
+
+	handlers.RespondNDJSON(rec, logger, func(enc *handlers.NDJSONEncoder[ndjsonItem]) error {
+		return nil
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", rec.Body.String())
+	}
+}
+
+func TestRespondNDJSON_ErrorBeforeOutput(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rec := httptest.NewRecorder()
+
+	handlers.RespondNDJSON(rec, logger, func(enc *handlers.NDJSONEncoder[ndjsonItem]) error {
+		return errors.New("query failed")
+	})
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if !strings.Contains(rec.Body.String(), "query failed") {
+		t.Errorf("body = %q, want error envelope", rec.Body.String())
+	}
+}
+
+func TestRespondNDJSON_ErrorAfterOutput(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	rec := httptest.NewRecorder()
+
+	handlers.RespondNDJSON(rec, logger, func(enc *handlers.NDJSONEncoder[ndjsonItem]) error {
+		if err := enc.Encode(ndjsonItem{N: 1}); err != nil {
+			return err
+		}
+		return errors.New("connection lost")
+	})
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Body.String(); got != "{\"n\":1,\"name\":\"\"}\n" {
+		t.Errorf("body = %q, want the line written before the failure", got)
+	}
+}
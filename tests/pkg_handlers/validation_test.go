@@ -0,0 +1,74 @@
+package pkg_handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+)
+
+func TestValidationError_Empty(t *testing.T) {
+	var v handlers.ValidationError
+	if err := v.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestValidationError_Fields(t *testing.T) {
+	var v handlers.ValidationError
+	v.Add("name", "name is required")
+	v.Add("config", "config is required")
+	v.Add("name", "ignored duplicate")
+
+	err := v.Err()
+	if !errors.Is(err, handlers.ErrValidation) {
+		t.Fatalf("Err() = %v, want ErrValidation", err)
+	}
+
+	if v.Fields["name"] != "name is required" {
+		t.Errorf("Fields[name] = %q, want first message", v.Fields["name"])
+	}
+
+	want := "validation failed: config: config is required; name: name is required"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestRespondError_ValidationError(t *testing.T) {
+	w := httptest.NewRecorder()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	var v handlers.ValidationError
+	v.Add("name", "name is required")
+	err := fmt.Errorf("create agent: %w", v.Err())
+
+	handlers.RespondError(w, logger, http.StatusBadRequest, err)
+
+	var resp handlers.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if resp.Error.Code != "validation_failed" {
+		t.Errorf("code = %q, want %q", resp.Error.Code, "validation_failed")
+	}
+	if !strings.Contains(resp.Error.Message, "name: name is required") {
+		t.Errorf("message = %q, want field summary", resp.Error.Message)
+	}
+
+	fields, ok := resp.Error.Details["fields"].(map[string]any)
+	if !ok {
+		t.Fatalf("details.fields = %T, want object", resp.Error.Details["fields"])
+	}
+	if fields["name"] != "name is required" {
+		t.Errorf("details.fields = %v, want name error", fields)
+	}
+}
@@ -1,6 +1,8 @@
 package pkg_lifecycle_test
 
 import (
+	"context"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -151,6 +153,116 @@ func TestCoordinator_Shutdown_Timeout(t *testing.T) {
 	}
 }
 
+func TestCoordinator_OnDrain_CompletesBeforeCancel(t *testing.T) {
+	lc := lifecycle.New()
+
+	var runCompleted atomic.Bool
+	var cancelledDuringRun atomic.Bool
+
+	lc.OnDrain(func(ctx context.Context) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			if lc.Context().Err() != nil {
+				cancelledDuringRun.Store(true)
+			}
+			runCompleted.Store(true)
+		case <-ctx.Done():
+		}
+	})
+
+	if err := lc.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown() failed: %v", err)
+	}
+
+	if !runCompleted.Load() {
+		t.Error("in-progress work should complete during drain")
+	}
+
+	if cancelledDuringRun.Load() {
+		t.Error("context should not be cancelled until drain completes")
+	}
+
+	if lc.Context().Err() == nil {
+		t.Error("context should be cancelled after shutdown")
+	}
+}
+
+func TestCoordinator_OnDrain_Timeout(t *testing.T) {
+	lc := lifecycle.New()
+
+	deadlineObserved := make(chan struct{})
+	lc.OnDrain(func(ctx context.Context) {
+		<-ctx.Done()
+		close(deadlineObserved)
+	})
+
+	err := lc.Shutdown(50 * time.Millisecond)
+	if err == nil {
+		t.Error("Shutdown() should return drain timeout error")
+	}
+
+	select {
+	case <-deadlineObserved:
+	case <-time.After(time.Second):
+		t.Error("drain context should expire at the shutdown timeout")
+	}
+}
+
+func TestCoordinator_OnDrain_GraceBeforeShutdownHooks(t *testing.T) {
+	lc := lifecycle.New()
+
+	var cleanedUp atomic.Bool
+	lc.OnDrain(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		cleanedUp.Store(true)
+	})
+
+	cleanedBeforeHooks := make(chan bool, 1)
+	lc.OnShutdown(func() {
+		<-lc.Context().Done()
+		cleanedBeforeHooks <- cleanedUp.Load()
+	})
+
+	err := lc.Shutdown(200 * time.Millisecond)
+	if err == nil {
+		t.Error("Shutdown() should return drain timeout error")
+	} else if strings.Contains(err.Error(), "shutdown timeout") {
+		t.Errorf("Shutdown() error = %v, want shutdown hooks to finish in the grace period", err)
+	}
+
+	if !<-cleanedBeforeHooks {
+		t.Error("drain cleanup after the drain deadline should finish before shutdown hooks run")
+	}
+}
+
+func TestCoordinator_Shutdown_SharesDeadline(t *testing.T) {
+	lc := lifecycle.New()
+
+	lc.OnDrain(func(ctx context.Context) {
+		time.Sleep(150 * time.Millisecond)
+	})
+
+	release := make(chan struct{})
+	defer close(release)
+	lc.OnShutdown(func() {
+		<-lc.Context().Done()
+		<-release
+	})
+
+	start := time.Now()
+	err := lc.Shutdown(200 * time.Millisecond)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Error("Shutdown() should return timeout error")
+	}
+
+	if elapsed > 350*time.Millisecond {
+		t.Errorf("Shutdown() took %v, want both phases bounded by one 200ms deadline", elapsed)
+	}
+}
+
 func TestCoordinator_ReadinessChecker(t *testing.T) {
 	lc := lifecycle.New()
 
@@ -0,0 +1,174 @@
+package pkg_lifecycle_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+)
+
+// recorder appends subsystem events in the order they occur.
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) start(name string) func() error {
+	return func() error {
+		r.events = append(r.events, "start:"+name)
+		return nil
+	}
+}
+
+func (r *recorder) stop(name string) func() {
+	return func() {
+		r.events = append(r.events, "stop:"+name)
+	}
+}
+
+func TestCoordinator_Register_DependencyOrder(t *testing.T) {
+	lc := lifecycle.New()
+	rec := &recorder{}
+
+	lc.Register("api", rec.start("api"), "database", "storage")
+	lc.Register("storage", rec.start("storage"))
+	lc.Register("database", rec.start("database"))
+	lc.Register("cache", rec.start("cache"), "database")
+
+	if err := lc.WaitForStartup(); err != nil {
+		t.Fatalf("WaitForStartup() error = %v", err)
+	}
+
+	want := "start:storage,start:database,start:api,start:cache"
+	if got := strings.Join(rec.events, ","); got != want {
+		t.Errorf("order = %s, want %s", got, want)
+	}
+
+	if !lc.Ready() {
+		t.Error("Ready() = false after successful startup")
+	}
+}
+
+func TestCoordinator_Register_InvalidGraph(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(lc *lifecycle.Coordinator, rec *recorder)
+		wantErr  string
+	}{
+		{
+			name: "cycle",
+			register: func(lc *lifecycle.Coordinator, rec *recorder) {
+				lc.Register("a", rec.start("a"), "c")
+				lc.Register("b", rec.start("b"), "a")
+				lc.Register("c", rec.start("c"), "b")
+				lc.Register("d", rec.start("d"))
+			},
+			wantErr: "cycle",
+		},
+		{
+			name: "unknown dependency",
+			register: func(lc *lifecycle.Coordinator, rec *recorder) {
+				lc.Register("a", rec.start("a"), "missing")
+			},
+			wantErr: "unknown subsystem missing",
+		},
+		{
+			name: "duplicate name",
+			register: func(lc *lifecycle.Coordinator, rec *recorder) {
+				lc.Register("a", rec.start("a"))
+				lc.Register("a", rec.start("a"))
+			},
+			wantErr: "more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := lifecycle.New()
+			rec := &recorder{}
+			tt.register(lc, rec)
+
+			err := lc.WaitForStartup()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("WaitForStartup() error = %v, want containing %q", err, tt.wantErr)
+			}
+
+			if len(rec.events) != 0 {
+				t.Errorf("no subsystem should start, got %v", rec.events)
+			}
+
+			if lc.Ready() {
+				t.Error("Ready() = true after failed startup")
+			}
+		})
+	}
+}
+
+func TestCoordinator_Register_RollbackOnFailure(t *testing.T) {
+	lc := lifecycle.New()
+	rec := &recorder{}
+	errBoom := errors.New("boom")
+
+	lc.Register("database", rec.start("database"))
+	lc.Register("storage", rec.start("storage"), "database")
+	lc.Register("api", func() error {
+		rec.events = append(rec.events, "start:api")
+		return errBoom
+	}, "storage")
+	lc.Register("worker", rec.start("worker"), "api")
+
+	lc.OnStop("database", rec.stop("database"))
+	lc.OnStop("storage", rec.stop("storage"))
+	lc.OnStop("api", rec.stop("api"))
+	lc.OnStop("worker", rec.stop("worker"))
+
+	err := lc.WaitForStartup()
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("WaitForStartup() error = %v, want %v", err, errBoom)
+	}
+
+	want := "start:database,start:storage,start:api,stop:storage,stop:database"
+	if got := strings.Join(rec.events, ","); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+
+	if lc.Ready() {
+		t.Error("Ready() = true after failed startup")
+	}
+
+	if err := lc.WaitForStartup(); !errors.Is(err, errBoom) {
+		t.Errorf("repeated WaitForStartup() error = %v, want %v", err, errBoom)
+	}
+
+	if err := lc.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if got := strings.Join(rec.events, ","); got != want {
+		t.Errorf("Shutdown should not stop rolled back subsystems again, events = %s", got)
+	}
+}
+
+func TestCoordinator_Shutdown_StopsInReverseOrder(t *testing.T) {
+	lc := lifecycle.New()
+	rec := &recorder{}
+
+	lc.Register("database", rec.start("database"))
+	lc.Register("storage", rec.start("storage"), "database")
+	lc.OnStop("database", rec.stop("database"))
+	lc.OnStop("storage", rec.stop("storage"))
+
+	if err := lc.WaitForStartup(); err != nil {
+		t.Fatalf("WaitForStartup() error = %v", err)
+	}
+
+	if err := lc.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	want := "start:database,start:storage,stop:storage,stop:database"
+	if got := strings.Join(rec.events, ","); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package pkg_middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/middleware"
+)
+
+func TestActor_StoresHeaderInContext(t *testing.T) {
+	var got string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = middleware.ActorFrom(r.Context())
+	})
+
+	wrapped := middleware.Actor(middleware.DefaultActorHeader)(handler)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/agents", nil)
+	req.Header.Set(middleware.DefaultActorHeader, "alice")
+	wrapped.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "alice" {
+		t.Errorf("ActorFrom() = %q, want %q", got, "alice")
+	}
+}
+
+func TestActor_MissingHeader(t *testing.T) {
+	got := "unset"
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = middleware.ActorFrom(r.Context())
+	})
+
+	middleware.Actor(middleware.DefaultActorHeader)(handler).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got != "" {
+		t.Errorf("ActorFrom() = %q, want empty", got)
+	}
+}
+
+func TestActorFrom_EmptyContext(t *testing.T) {
+	if got := middleware.ActorFrom(context.Background()); got != "" {
+		t.Errorf("ActorFrom() = %q, want empty", got)
+	}
+}
@@ -187,3 +187,17 @@ func TestCORSConfig_Merge(t *testing.T) {
 		})
 	}
 }
+
+func TestCORSConfig_Finalize_InvalidMaxAge(t *testing.T) {
+	cfg := &middleware.CORSConfig{MaxAge: middleware.MaxCORSMaxAge + 1}
+	if err := cfg.Finalize(nil); err == nil {
+		t.Errorf("Finalize() with max_age %d error = nil, want error", cfg.MaxAge)
+	}
+
+	t.Setenv("TEST_CORS_INVALID_MAX_AGE", "-1")
+
+	cfg = &middleware.CORSConfig{}
+	if err := cfg.Finalize(&middleware.CORSEnv{MaxAge: "TEST_CORS_INVALID_MAX_AGE"}); err == nil {
+		t.Error("Finalize() with negative max_age from env error = nil, want error")
+	}
+}
@@ -0,0 +1,137 @@
+package pkg_middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/middleware"
+)
+
+// serveRealIP runs a request from remoteAddr with headers through RealIP and
+// returns the client IP seen in the context and in r.RemoteAddr.
+func serveRealIP(t *testing.T, cfg *middleware.ProxyConfig, remoteAddr string, headers map[string]string) (string, string) {
+	t.Helper()
+
+	var fromCtx, remote string
+	handler := middleware.RealIP(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromCtx = middleware.ClientIPFrom(r.Context())
+		remote = r.RemoteAddr
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	return fromCtx, remote
+}
+
+func TestRealIP(t *testing.T) {
+	cfg := &middleware.ProxyConfig{TrustedProxies: []string{"10.0.0.0/8", "192.168.1.10"}}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "trusted proxy chain",
+			remoteAddr: "10.0.0.2:41000",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, 203.0.113.7, 192.168.1.10, 10.1.2.3"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "untrusted peer ignored",
+			remoteAddr: "203.0.113.50:5000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Real-IP": "1.2.3.4"},
+			want:       "203.0.113.50",
+		},
+		{
+			name:       "x-real-ip from trusted proxy",
+			remoteAddr: "192.168.1.10:8080",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.9"},
+			want:       "198.51.100.9",
+		},
+		{
+			name:       "trusted proxy without headers",
+			remoteAddr: "10.0.0.2:41000",
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "all hops trusted",
+			remoteAddr: "10.0.0.2:41000",
+			headers:    map[string]string{"X-Forwarded-For": "10.9.9.9, 10.0.0.1"},
+			want:       "10.9.9.9",
+		},
+		{
+			name:       "malformed hop stops the walk",
+			remoteAddr: "10.0.0.2:41000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7, not-an-ip, 10.0.0.1"},
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "ipv6 client",
+			remoteAddr: "[::ffff:10.0.0.2]:41000",
+			headers:    map[string]string{"X-Forwarded-For": "2001:db8::1"},
+			want:       "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromCtx, remote := serveRealIP(t, cfg, tt.remoteAddr, tt.headers)
+
+			if fromCtx != tt.want {
+				t.Errorf("ClientIPFrom() = %q, want %q", fromCtx, tt.want)
+			}
+			if remote != tt.want {
+				t.Errorf("RemoteAddr = %q, want %q", remote, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealIP_NoTrustedProxies(t *testing.T) {
+	fromCtx, _ := serveRealIP(t, &middleware.ProxyConfig{}, "10.0.0.2:41000", map[string]string{"X-Forwarded-For": "203.0.113.7"})
+
+	if fromCtx != "10.0.0.2" {
+		t.Errorf("ClientIPFrom() = %q, want the peer address", fromCtx)
+	}
+}
+
+func TestProxyConfig_Finalize(t *testing.T) {
+	t.Setenv("TEST_TRUSTED_PROXIES", " 10.0.0.0/8 , 192.168.1.10 ")
+
+	cfg := &middleware.ProxyConfig{}
+	if err := cfg.Finalize(&middleware.ProxyEnv{TrustedProxies: "TEST_TRUSTED_PROXIES"}); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	if len(cfg.TrustedProxies) != 2 || cfg.TrustedProxies[0] != "10.0.0.0/8" || cfg.TrustedProxies[1] != "192.168.1.10" {
+		t.Errorf("TrustedProxies = %q, want env entries", cfg.TrustedProxies)
+	}
+
+	for _, entry := range []string{"10.0.0.0/33", "proxy.internal"} {
+		cfg := &middleware.ProxyConfig{TrustedProxies: []string{entry}}
+		if err := cfg.Finalize(nil); err == nil {
+			t.Errorf("Finalize() with %q: expected error", entry)
+		}
+	}
+}
+
+func TestProxyConfig_Merge(t *testing.T) {
+	cfg := &middleware.ProxyConfig{TrustedProxies: []string{"10.0.0.1"}}
+
+	cfg.Merge(&middleware.ProxyConfig{})
+	if len(cfg.TrustedProxies) != 1 {
+		t.Errorf("nil overlay changed TrustedProxies to %q", cfg.TrustedProxies)
+	}
+
+	cfg.Merge(&middleware.ProxyConfig{TrustedProxies: []string{}})
+	if len(cfg.TrustedProxies) != 0 {
+		t.Errorf("empty overlay should clear TrustedProxies, got %q", cfg.TrustedProxies)
+	}
+}
@@ -0,0 +1,106 @@
+package pkg_middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/middleware"
+	"github.com/JaimeStill/agent-lab/pkg/signedurl"
+)
+
+// signedServer wraps a handler that records whether the request reached it. The signer has no base path because module routing strips it
+// before middleware runs.
+func signedServer(t *testing.T) (*signedurl.Signer, http.Handler, *bool) {
+	t.Helper()
+
+	signer, err := signedurl.New(bytes.Repeat([]byte("k"), signedurl.KeySize), "")
+	if err != nil {
+		t.Fatalf("signedurl.New() error = %v", err)
+	}
+
+	reached := new(bool)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*reached = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return signer, middleware.SignedURL(signer)(handler), reached
+}
+
+func TestSignedURL_Valid(t *testing.T) {
+	signer, handler, reached := signedServer(t)
+	signed, _ := signer.Sign("/images/abc/data", time.Hour)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, signed, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !*reached {
+		t.Error("signed request did not reach handler")
+	}
+}
+
+func TestSignedURL_Expired(t *testing.T) {
+	signer, handler, reached := signedServer(t)
+	signed, _ := signer.Sign("/images/abc/data", -time.Minute)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, signed, nil))
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if !strings.Contains(rec.Body.String(), "signature_expired") {
+		t.Errorf("body = %s, want signature_expired code", rec.Body.String())
+	}
+	if *reached {
+		t.Error("expired request reached handler")
+	}
+}
+
+func TestSignedURL_Tampered(t *testing.T) {
+	signer, handler, _ := signedServer(t)
+	signed, _ := signer.Sign("/images/abc/data", time.Hour)
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"path", strings.Replace(signed, "/images/abc/", "/images/xyz/", 1)},
+		{"signature", strings.Replace(signed, "sig=", "sig=AAAA", 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusForbidden)
+			}
+			if !strings.Contains(rec.Body.String(), "invalid_signature") {
+				t.Errorf("body = %s, want invalid_signature code", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSignedURL_UnsignedPassesThrough(t *testing.T) {
+	_, handler, reached := signedServer(t)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/images/abc/data", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if !*reached {
+		t.Error("unsigned request did not reach handler")
+	}
+}
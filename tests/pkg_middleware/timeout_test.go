@@ -0,0 +1,221 @@
+package pkg_middleware_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/middleware"
+)
+
+func TestTimeout_FastHandlerPassesThrough(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Custom", "value")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	wrapped := middleware.Timeout(time.Second)(handler)
+
+	rec := httptest.NewRecorder()
+	wrapped.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/agents", nil))
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if rec.Header().Get("X-Custom") != "value" {
+		t.Errorf("X-Custom = %q, want %q", rec.Header().Get("X-Custom"), "value")
+	}
+	if rec.Body.String() != `{"ok":true}` {
+		t.Errorf("body = %q, want handler body", rec.Body.String())
+	}
+}
+
+func TestTimeout_SlowHandlerReturns503(t *testing.T) {
+	canceled := make(chan error, 1)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		canceled <- context.Cause(r.Context())
+		w.Write([]byte("too late"))
+	})
+
+	wrapped := middleware.Timeout(20 * time.Millisecond)(handler)
+
+	rec := httptest.NewRecorder()
+	wrapped.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/agents", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	var body handlers.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", rec.Body.String(), err)
+	}
+	if body.Error.Code != "request_timeout" {
+		t.Errorf("error code = %q, want request_timeout", body.Error.Code)
+	}
+
+	select {
+	case cause := <-canceled:
+		if !errors.Is(cause, middleware.ErrRequestTimeout) {
+			t.Errorf("context cause = %v, want ErrRequestTimeout", cause)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not canceled")
+	}
+}
+
+func TestTimeout_StreamingResponseExempt(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		time.Sleep(60 * time.Millisecond)
+		if r.Context().Err() != nil {
+			return
+		}
+		w.Write([]byte("data: done\n\n"))
+	})
+
+	wrapped := middleware.Timeout(20 * time.Millisecond)(handler)
+
+	rec := httptest.NewRecorder()
+	wrapped.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/workflows/classify-docs/execute", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.String() != "data: done\n\n" {
+		t.Errorf("body = %q, want streamed event", rec.Body.String())
+	}
+}
+
+func TestTimeout_EventStreamRequestBypasses(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); ok {
+			t.Error("event-stream request context should not be bounded")
+		}
+		time.Sleep(40 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	wrapped := middleware.Timeout(10 * time.Millisecond)(handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	rec := httptest.NewRecorder()
+	wrapped.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestTimeout_NDJSONResponseExempt(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("{\"n\":1}\n"))
+
+		time.Sleep(60 * time.Millisecond)
+		if r.Context().Err() != nil {
+			return
+		}
+		w.Write([]byte("{\"n\":2}\n"))
+	})
+
+	wrapped := middleware.Timeout(20 * time.Millisecond)(handler)
+
+	rec := httptest.NewRecorder()
+	wrapped.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/workflows/runs?format=ndjson", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.String() != "{\"n\":1}\n{\"n\":2}\n" {
+		t.Errorf("body = %q, want both streamed lines", rec.Body.String())
+	}
+}
+
+type exportRow struct {
+	N int `json:"n"`
+}
+
+func TestTimeout_CSVExportStreams(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	release := make(chan struct{})
+	returned := make(chan struct{})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(returned)
+		handlers.RespondCSV(w, logger, "export.csv", func(enc *handlers.CSVEncoder[exportRow]) error {
+			for i := range handlers.CSVFlushInterval {
+				if err := enc.Encode(exportRow{N: i}); err != nil {
+					return err
+				}
+			}
+			select {
+			case <-release:
+			case <-time.After(time.Second):
+			}
+			return enc.Encode(exportRow{N: handlers.CSVFlushInterval})
+		})
+	})
+
+	srv := httptest.NewServer(middleware.Timeout(20 * time.Millisecond)(handler))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/workflows/runs?format=csv")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	for i := 0; i <= handlers.CSVFlushInterval; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			t.Fatalf("read line %d: %v", i, err)
+		}
+	}
+
+	select {
+	case <-returned:
+		t.Fatal("first rows arrived only after the handler returned")
+	default:
+	}
+	close(release)
+
+	rest, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read rest: %v", err)
+	}
+	if want := strconv.Itoa(handlers.CSVFlushInterval) + "\n"; string(rest) != want {
+		t.Errorf("rest = %q, want %q", rest, want)
+	}
+}
+
+func TestTimeout_Disabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	wrapped := middleware.Timeout(0)(handler)
+
+	if _, ok := wrapped.(http.HandlerFunc); !ok {
+		t.Errorf("Timeout(0) wrapped handler = %T, want the original handler", wrapped)
+	}
+}
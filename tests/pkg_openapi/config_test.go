@@ -0,0 +1,66 @@
+package pkg_openapi_test
+
+import (
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
+)
+
+func TestConfig_Finalize_ServersFromEnv(t *testing.T) {
+	t.Setenv("TEST_OPENAPI_SERVERS", "http://localhost:8080, https://lab.example.com ,")
+
+	cfg := openapi.Config{Servers: []openapi.Server{{URL: "http://replaced"}}}
+	if err := cfg.Finalize(&openapi.ConfigEnv{Servers: "TEST_OPENAPI_SERVERS"}); err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+
+	want := []string{"http://localhost:8080", "https://lab.example.com"}
+	if len(cfg.Servers) != len(want) {
+		t.Fatalf("Servers = %v, want %v", cfg.Servers, want)
+	}
+	for i, url := range want {
+		if cfg.Servers[i].URL != url {
+			t.Errorf("Servers[%d].URL = %q, want %q", i, cfg.Servers[i].URL, url)
+		}
+	}
+}
+
+func TestConfig_Finalize_RejectsEmptyServerURL(t *testing.T) {
+	cfg := openapi.Config{Servers: []openapi.Server{{URL: "http://localhost:8080"}, {Description: "missing"}}}
+
+	if err := cfg.Finalize(nil); err == nil {
+		t.Error("Finalize() with empty server url should fail")
+	}
+}
+
+func TestConfig_Merge_ReplacesServers(t *testing.T) {
+	cfg := openapi.Config{Servers: []openapi.Server{{URL: "http://localhost:8080"}}}
+
+	cfg.Merge(&openapi.Config{})
+	if len(cfg.Servers) != 1 {
+		t.Errorf("Merge() with no servers changed Servers to %v", cfg.Servers)
+	}
+
+	cfg.Merge(&openapi.Config{Servers: []openapi.Server{{URL: "https://a"}, {URL: "https://b"}}})
+	if len(cfg.Servers) != 2 || cfg.Servers[0].URL != "https://a" || cfg.Servers[1].URL != "https://b" {
+		t.Errorf("Servers = %v, want overlay servers", cfg.Servers)
+	}
+}
+
+func TestSpec_AddServers(t *testing.T) {
+	spec := openapi.NewSpec("Test API", "1.0.0")
+	servers := []openapi.Server{
+		{URL: "http://localhost:8080", Description: "Local"},
+		{URL: "https://lab.example.com", Description: "Production"},
+	}
+
+	spec.AddServers(servers...)
+	servers[0].URL = "mutated"
+
+	if len(spec.Servers) != 2 {
+		t.Fatalf("len(Servers) = %d, want 2", len(spec.Servers))
+	}
+	if spec.Servers[0].URL != "http://localhost:8080" || spec.Servers[1].Description != "Production" {
+		t.Errorf("Servers = %+v, %+v, want configured order", *spec.Servers[0], *spec.Servers[1])
+	}
+}
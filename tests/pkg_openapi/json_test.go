@@ -100,3 +100,28 @@ func TestWriteJSON_InvalidPath(t *testing.T) {
 		t.Error("WriteJSON() expected error for invalid path, got nil")
 	}
 }
+
+func TestSpec_AddTag_MergesDuplicates(t *testing.T) {
+	spec := openapi.NewSpec("Test API", "1.0.0")
+
+	spec.AddTag(openapi.Tag{Name: "Images"})
+	spec.AddTag(openapi.Tag{Name: "Documents", Description: "Document management"})
+	spec.AddTag(openapi.Tag{
+		Name:         "Images",
+		Description:  "Image rendering",
+		ExternalDocs: &openapi.ExternalDocs{URL: "https://example.com/images"},
+	})
+	spec.AddTag(openapi.Tag{Name: "Images", Description: "ignored"})
+
+	if len(spec.Tags) != 2 {
+		t.Fatalf("len(Tags) = %d, want 2", len(spec.Tags))
+	}
+
+	images := spec.Tags[0]
+	if images.Name != "Images" || images.Description != "Image rendering" {
+		t.Errorf("Tags[0] = {%q, %q}, want {Images, Image rendering}", images.Name, images.Description)
+	}
+	if images.ExternalDocs == nil || images.ExternalDocs.URL != "https://example.com/images" {
+		t.Errorf("Tags[0].ExternalDocs = %+v, want https://example.com/images", images.ExternalDocs)
+	}
+}
@@ -1,6 +1,8 @@
 package pkg_openapi_test
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/JaimeStill/agent-lab/pkg/openapi"
@@ -82,6 +84,45 @@ func TestRequestBodyJSON(t *testing.T) {
 	}
 }
 
+func TestRequestBody_WithExample(t *testing.T) {
+	op := &openapi.Operation{
+		Summary:     "Create user",
+		RequestBody: openapi.RequestBodyJSON("CreateUser", true).WithExample(map[string]any{"name": "ada"}),
+		Responses:   map[int]*openapi.Response{201: {Description: "Created"}},
+	}
+
+	data, err := json.Marshal(op)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got struct {
+		RequestBody struct {
+			Content map[string]struct {
+				Example map[string]any `json:"example"`
+			} `json:"content"`
+		} `json:"requestBody"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	example := got.RequestBody.Content["application/json"].Example
+	if example["name"] != "ada" {
+		t.Errorf("requestBody example = %v, want name ada in %s", example, data)
+	}
+}
+
+func TestRequestBodyJSON_OmitsEmptyExample(t *testing.T) {
+	data, err := json.Marshal(openapi.RequestBodyJSON("CreateUser", true))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "example") {
+		t.Errorf("marshaled body = %s, want no example field", data)
+	}
+}
+
 func TestResponseJSON(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -113,6 +154,66 @@ func TestResponseJSON(t *testing.T) {
 	}
 }
 
+func TestResponseWithHeaders(t *testing.T) {
+	resp := openapi.ResponseWithHeaders(
+		openapi.ResponseJSON("User list", "UserList"),
+		openapi.PageHeaders(true),
+	)
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var got struct {
+		Headers map[string]struct {
+			Description string `json:"description"`
+			Schema      struct {
+				Type string `json:"type"`
+			} `json:"schema"`
+		} `json:"headers"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := map[string]string{"X-Total-Count": "integer", "Link": "string"}
+	if len(got.Headers) != len(want) {
+		t.Fatalf("headers = %v, want %d entries in %s", got.Headers, len(want), data)
+	}
+	for name, typ := range want {
+		h, ok := got.Headers[name]
+		if !ok {
+			t.Errorf("headers missing %q", name)
+			continue
+		}
+		if h.Schema.Type != typ || h.Description == "" {
+			t.Errorf("headers[%q] = %+v, want type %q with description", name, h, typ)
+		}
+	}
+}
+
+func TestPageHeaders_WithoutLinks(t *testing.T) {
+	headers := openapi.PageHeaders(false)
+
+	if headers["X-Total-Count"] == nil {
+		t.Error("PageHeaders(false) missing X-Total-Count")
+	}
+	if _, ok := headers["Link"]; ok {
+		t.Error("PageHeaders(false) should not include Link")
+	}
+}
+
+func TestResponseJSON_OmitsEmptyHeaders(t *testing.T) {
+	data, err := json.Marshal(openapi.ResponseJSON("User", "User"))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "headers") {
+		t.Errorf("marshaled response = %s, want no headers field", data)
+	}
+}
+
 func TestPathParam(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -193,3 +294,30 @@ func TestQueryParam(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseWithCSV(t *testing.T) {
+	resp := openapi.ResponseWithCSV(openapi.ResponseJSON("User list", "UserList"))
+
+	if len(resp.Content) != 2 {
+		t.Fatalf("Content has %d media types, want 2", len(resp.Content))
+	}
+	if resp.Content["application/json"] == nil {
+		t.Error("application/json media type was dropped")
+	}
+	csv := resp.Content["text/csv"]
+	if csv == nil || csv.Schema == nil || csv.Schema.Type != "string" {
+		t.Errorf("text/csv media type = %+v, want string schema", csv)
+	}
+}
+
+func TestResponseWithNDJSON(t *testing.T) {
+	resp := openapi.ResponseWithNDJSON(openapi.ResponseJSON("User list", "UserList"), "User")
+
+	if resp.Content["application/json"] == nil {
+		t.Error("application/json media type was dropped")
+	}
+	ndjson := resp.Content["application/x-ndjson"]
+	if ndjson == nil || ndjson.Schema == nil || ndjson.Schema.Ref != "#/components/schemas/User" {
+		t.Errorf("application/x-ndjson media type = %+v, want User schema ref", ndjson)
+	}
+}
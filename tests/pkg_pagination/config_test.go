@@ -138,3 +138,96 @@ func TestConfig_Merge(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultSize int
+		maxSize     int
+		wantErr     bool
+	}{
+		{"valid", 20, 100, false},
+		{"default equals max", 50, 50, false},
+		{"minimum sizes", 1, 1, false},
+		{"zero default", 0, 100, true},
+		{"negative default", -1, 100, true},
+		{"zero max", 20, 0, true},
+		{"default exceeds max", 50, 25, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := pagination.NewConfig(tt.defaultSize, tt.maxSize)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NewConfig(%d, %d) succeeded, want error", tt.defaultSize, tt.maxSize)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewConfig(%d, %d) error = %v", tt.defaultSize, tt.maxSize, err)
+			}
+			if cfg.DefaultPageSize != tt.defaultSize || cfg.MaxPageSize != tt.maxSize {
+				t.Errorf("NewConfig() = %+v, want {%d %d}", cfg, tt.defaultSize, tt.maxSize)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     pagination.Config
+		wantErr bool
+	}{
+		{"valid", pagination.Config{DefaultPageSize: 20, MaxPageSize: 100}, false},
+		{"zero value", pagination.Config{}, true},
+		{"zero max", pagination.Config{DefaultPageSize: 20}, true},
+		{"default exceeds max", pagination.Config{DefaultPageSize: 101, MaxPageSize: 100}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_Finalize_RejectsInvalidEnv(t *testing.T) {
+	t.Setenv("TEST_PAGINATION_MAX_INVALID", "0")
+
+	cfg := &pagination.Config{}
+	env := &pagination.ConfigEnv{MaxPageSize: "TEST_PAGINATION_MAX_INVALID"}
+
+	if err := cfg.Finalize(env); err == nil {
+		t.Error("Finalize() succeeded with max_page_size 0, want error")
+	}
+}
+
+func TestConfig_Finalize_MaxOffset(t *testing.T) {
+	cfg := &pagination.Config{}
+	if err := cfg.Finalize(nil); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+	if cfg.MaxOffset != pagination.DefaultMaxOffset {
+		t.Errorf("MaxOffset = %d, want %d", cfg.MaxOffset, pagination.DefaultMaxOffset)
+	}
+
+	t.Setenv("TEST_PAGINATION_MAX_OFFSET", "500")
+	cfg = &pagination.Config{}
+	if err := cfg.Finalize(&pagination.ConfigEnv{MaxOffset: "TEST_PAGINATION_MAX_OFFSET"}); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+	if cfg.MaxOffset != 500 {
+		t.Errorf("MaxOffset = %d, want 500 (env override)", cfg.MaxOffset)
+	}
+
+	if err := (pagination.Config{DefaultPageSize: 1, MaxPageSize: 1, MaxOffset: -1}).Validate(); err == nil {
+		t.Error("Validate() succeeded with negative max_offset, want error")
+	}
+}
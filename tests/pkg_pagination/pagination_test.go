@@ -1,7 +1,13 @@
 package pkg_pagination_test
 
 import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/JaimeStill/agent-lab/pkg/pagination"
@@ -73,6 +79,97 @@ func TestPageRequest_Normalize(t *testing.T) {
 	}
 }
 
+func TestPageRequest_Normalize_EdgeConfigs(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          pagination.Config
+		pageSize     int
+		wantPageSize int
+	}{
+		{"zero config uses defaults", pagination.Config{}, 0, pagination.DefaultPageSize},
+		{"zero max does not cap to zero", pagination.Config{DefaultPageSize: 10}, 50, 50},
+		{"zero max caps at fallback max", pagination.Config{DefaultPageSize: 10}, 500, pagination.DefaultMaxPageSize},
+		{"zero default uses fallback", pagination.Config{MaxPageSize: 100}, 0, pagination.DefaultPageSize},
+		{"zero default bounded by small max", pagination.Config{MaxPageSize: 5}, 0, 5},
+		{"default above max is capped", pagination.Config{DefaultPageSize: 50, MaxPageSize: 10}, 0, 10},
+		{"negative limits use defaults", pagination.Config{DefaultPageSize: -1, MaxPageSize: -1}, -3, pagination.DefaultPageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := pagination.PageRequest{Page: 1, PageSize: tt.pageSize}
+			req.Normalize(tt.cfg)
+
+			if req.PageSize != tt.wantPageSize {
+				t.Errorf("PageSize = %d, want %d", req.PageSize, tt.wantPageSize)
+			}
+		})
+	}
+}
+
+func TestPageRequest_Normalize_MaxOffset(t *testing.T) {
+	cfg := pagination.Config{DefaultPageSize: 20, MaxPageSize: 100, MaxOffset: 1000}
+
+	tests := []struct {
+		name     string
+		request  pagination.PageRequest
+		wantPage int
+		wantErr  bool
+	}{
+		{"first page", pagination.PageRequest{Page: 1, PageSize: 20}, 1, false},
+		{"normal page", pagination.PageRequest{Page: 10, PageSize: 20}, 10, false},
+		{"page at max offset", pagination.PageRequest{Page: 51, PageSize: 20}, 51, false},
+		{"page beyond max offset", pagination.PageRequest{Page: 52, PageSize: 20}, 51, true},
+		{"absurd page", pagination.PageRequest{Page: 100000000, PageSize: 100}, 11, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Normalize(cfg)
+
+			if tt.wantErr {
+				if !errors.Is(err, pagination.ErrPageOutOfRange) {
+					t.Errorf("Normalize() error = %v, want ErrPageOutOfRange", err)
+				}
+			} else if err != nil {
+				t.Errorf("Normalize() error = %v, want nil", err)
+			}
+
+			if tt.request.Page != tt.wantPage {
+				t.Errorf("Page = %d, want %d", tt.request.Page, tt.wantPage)
+			}
+			if tt.request.Offset() > cfg.MaxOffset {
+				t.Errorf("Offset() = %d, exceeds MaxOffset %d", tt.request.Offset(), cfg.MaxOffset)
+			}
+		})
+	}
+}
+
+func TestPageRequest_Normalize_DefaultMaxOffset(t *testing.T) {
+	req := pagination.PageRequest{Page: 100000000, PageSize: 10}
+
+	err := req.Normalize(pagination.Config{DefaultPageSize: 10, MaxPageSize: 100})
+	if !errors.Is(err, pagination.ErrPageOutOfRange) {
+		t.Fatalf("Normalize() error = %v, want ErrPageOutOfRange", err)
+	}
+	if req.Offset() > pagination.DefaultMaxOffset {
+		t.Errorf("Offset() = %d, exceeds DefaultMaxOffset %d", req.Offset(), pagination.DefaultMaxOffset)
+	}
+}
+
+func TestPageRequestFromQuery_PageOutOfRange(t *testing.T) {
+	cfg := pagination.Config{DefaultPageSize: 20, MaxPageSize: 100, MaxOffset: 1000}
+	values := url.Values{"page": {"100000000"}}
+
+	req, err := pagination.PageRequestFromQuery(values, cfg)
+	if !errors.Is(err, pagination.ErrPageOutOfRange) {
+		t.Fatalf("PageRequestFromQuery() error = %v, want ErrPageOutOfRange", err)
+	}
+	if req.Page != 51 {
+		t.Errorf("Page = %d, want clamped 51", req.Page)
+	}
+}
+
 func TestPageRequest_Offset(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -253,6 +350,14 @@ func TestPageRequestFromQuery(t *testing.T) {
 			wantSearch:   strPtr("foo"),
 			wantSort:     []query.SortField{{Field: "name", Descending: true}},
 		},
+		{
+			name:         "case-insensitive sort token",
+			query:        "sort=-name:ci",
+			wantPage:     1,
+			wantPageSize: 20,
+			wantSearch:   nil,
+			wantSort:     []query.SortField{{Field: "name", Descending: true, CaseInsensitive: true}},
+		},
 		{
 			name:         "invalid page defaults to 1",
 			query:        "page=invalid",
@@ -274,7 +379,10 @@ func TestPageRequestFromQuery(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			values, _ := url.ParseQuery(tt.query)
-			req := pagination.PageRequestFromQuery(values, cfg)
+			req, err := pagination.PageRequestFromQuery(values, cfg)
+			if err != nil {
+				t.Fatalf("PageRequestFromQuery() error = %v", err)
+			}
 
 			if req.Page != tt.wantPage {
 				t.Errorf("Page = %d, want %d", req.Page, tt.wantPage)
@@ -311,6 +419,9 @@ func TestPageRequestFromQuery(t *testing.T) {
 					if req.Sort[i].Descending != want.Descending {
 						t.Errorf("Sort[%d].Descending = %v, want %v", i, req.Sort[i].Descending, want.Descending)
 					}
+					if req.Sort[i].CaseInsensitive != want.CaseInsensitive {
+						t.Errorf("Sort[%d].CaseInsensitive = %v, want %v", i, req.Sort[i].CaseInsensitive, want.CaseInsensitive)
+					}
 				}
 			}
 		})
@@ -451,3 +562,150 @@ func TestSortFields_UnmarshalJSON_InvalidInput(t *testing.T) {
 		})
 	}
 }
+
+func TestPageResult_SetHeaders(t *testing.T) {
+	result := pagination.NewPageResult([]string{"c", "d"}, 7, 2, 2)
+	u, _ := url.Parse("/api/agents?page=2&page_size=2&search=gpt")
+
+	h := http.Header{}
+	result.SetHeaders(h, u)
+
+	if got := h.Get("X-Total-Count"); got != "7" {
+		t.Errorf("X-Total-Count = %q, want %q", got, "7")
+	}
+
+	link := h.Get("Link")
+	want := []string{
+		`</api/agents?page=1&page_size=2&search=gpt>; rel="first"`,
+		`</api/agents?page=1&page_size=2&search=gpt>; rel="prev"`,
+		`</api/agents?page=3&page_size=2&search=gpt>; rel="next"`,
+		`</api/agents?page=4&page_size=2&search=gpt>; rel="last"`,
+	}
+	if link != strings.Join(want, ", ") {
+		t.Errorf("Link = %q, want %q", link, strings.Join(want, ", "))
+	}
+}
+
+func TestPageResult_SetHeaders_SinglePage(t *testing.T) {
+	result := pagination.NewPageResult([]string{"a"}, 1, 1, 20)
+	u, _ := url.Parse("/api/profiles")
+
+	h := http.Header{}
+	result.SetHeaders(h, u)
+
+	link := h.Get("Link")
+	if strings.Contains(link, `rel="prev"`) || strings.Contains(link, `rel="next"`) {
+		t.Errorf("Link = %q, want only first and last", link)
+	}
+	if !strings.Contains(link, `rel="first"`) || !strings.Contains(link, `rel="last"`) {
+		t.Errorf("Link = %q, want first and last", link)
+	}
+}
+
+func TestPageResult_SetHeaders_WithoutURL(t *testing.T) {
+	result := pagination.NewPageResult([]string{"a"}, 3, 1, 1)
+
+	h := http.Header{}
+	result.SetHeaders(h, nil)
+
+	if got := h.Get("X-Total-Count"); got != "3" {
+		t.Errorf("X-Total-Count = %q, want %q", got, "3")
+	}
+	if got := h.Get("Link"); got != "" {
+		t.Errorf("Link = %q, want empty without URL", got)
+	}
+}
+
+func TestRespond(t *testing.T) {
+	result := pagination.NewPageResult([]string{"a", "b"}, 5, 1, 2)
+
+	tests := []struct {
+		method   string
+		wantLink bool
+	}{
+		{http.MethodGet, true},
+		{http.MethodPost, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/documents?page=1", nil)
+			rec := httptest.NewRecorder()
+
+			pagination.Respond(rec, req, &result)
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get("X-Total-Count"); got != "5" {
+				t.Errorf("X-Total-Count = %q, want %q", got, "5")
+			}
+			if hasLink := rec.Header().Get("Link") != ""; hasLink != tt.wantLink {
+				t.Errorf("Link present = %v, want %v", hasLink, tt.wantLink)
+			}
+		})
+	}
+}
+
+type fieldsItem struct {
+	ID     string         `json:"id"`
+	Name   string         `json:"name"`
+	Config map[string]any `json:"config"`
+}
+
+func TestRespond_Fields(t *testing.T) {
+	result := pagination.NewPageResult([]fieldsItem{
+		{ID: "1", Name: "a", Config: map[string]any{"large": true}},
+		{ID: "2", Name: "b", Config: map[string]any{"large": true}},
+	}, 5, 1, 2)
+
+	tests := []struct {
+		name     string
+		target   string
+		wantKeys []string
+	}{
+		{"id and name", "/api/agents?fields=id,name", []string{"id", "name"}},
+		{"absent", "/api/agents", []string{"config", "id", "name"}},
+		{"empty", "/api/agents?fields=", []string{"config", "id", "name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+
+			pagination.Respond(rec, req, &result)
+
+			var body struct {
+				Data       []map[string]json.RawMessage `json:"data"`
+				Total      int                          `json:"total"`
+				TotalPages int                          `json:"total_pages"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("response is not a page: %v", err)
+			}
+
+			if body.Total != 5 || body.TotalPages != 3 {
+				t.Errorf("total/total_pages = %d/%d, want 5/3", body.Total, body.TotalPages)
+			}
+			if len(body.Data) != 2 {
+				t.Fatalf("data has %d items, want 2", len(body.Data))
+			}
+
+			for _, item := range body.Data {
+				var keys []string
+				for k := range item {
+					keys = append(keys, k)
+				}
+				slices.Sort(keys)
+				if !slices.Equal(keys, tt.wantKeys) {
+					t.Errorf("item keys = %v, want %v", keys, tt.wantKeys)
+				}
+			}
+
+			if link := rec.Header().Get("Link"); tt.wantKeys[0] == "id" && !strings.Contains(link, "fields=id%2Cname") {
+				t.Errorf("Link = %q, want fields preserved", link)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package pkg_query_test
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -10,8 +12,8 @@ import (
 func newTestProjection() *query.ProjectionMap {
 	return query.NewProjectionMap("public", "users", "u").
 		Project("id", "ID").
-		Project("name", "Name").
-		Project("email", "Email")
+		ProjectText("name", "Name").
+		ProjectText("email", "Email")
 }
 
 func TestBuilder_Build_NoConditions(t *testing.T) {
@@ -87,6 +89,43 @@ func TestBuilder_BuildCount_NoConditions(t *testing.T) {
 	}
 }
 
+func TestBuilder_BuildDistinct(t *testing.T) {
+	pm := newTestProjection()
+	b := query.NewBuilder(pm, query.SortField{Field: "ID"}).
+		WhereEquals("Email", "a@example.com")
+
+	sql, args, err := b.BuildDistinct("Name")
+	if err != nil {
+		t.Fatalf("BuildDistinct() error = %v", err)
+	}
+
+	wantSQL := "SELECT DISTINCT u.name FROM public.users u WHERE u.email = $1 ORDER BY u.name"
+	if sql != wantSQL {
+		t.Errorf("BuildDistinct() sql = %q, want %q", sql, wantSQL)
+	}
+
+	if len(args) != 1 || args[0] != "a@example.com" {
+		t.Errorf("BuildDistinct() args = %v, want [a@example.com]", args)
+	}
+}
+
+func TestBuilder_BuildDistinct_UnknownField(t *testing.T) {
+	pm := newTestProjection()
+	b := query.NewBuilder(pm)
+
+	for _, field := range []string{"", "name", "Password", "Name; DROP TABLE users"} {
+		t.Run(field, func(t *testing.T) {
+			sql, _, err := b.BuildDistinct(field)
+			if !errors.Is(err, query.ErrUnknownField) {
+				t.Errorf("BuildDistinct(%q) error = %v, want ErrUnknownField", field, err)
+			}
+			if sql != "" {
+				t.Errorf("BuildDistinct(%q) sql = %q, want empty", field, sql)
+			}
+		})
+	}
+}
+
 func TestBuilder_BuildPage_NoConditions(t *testing.T) {
 	pm := newTestProjection()
 	b := query.NewBuilder(pm, query.SortField{Field: "Name"})
@@ -205,6 +244,274 @@ func TestBuilder_OrderByFields(t *testing.T) {
 	}
 }
 
+func TestBuilder_OrderByFields_CaseInsensitive(t *testing.T) {
+	pm := newTestProjection()
+
+	tests := []struct {
+		name      string
+		fields    []query.SortField
+		wantOrder string
+	}{
+		{
+			"ascending case-insensitive",
+			[]query.SortField{{Field: "Name", CaseInsensitive: true}},
+			"ORDER BY LOWER(u.name) ASC",
+		},
+		{
+			"descending case-insensitive",
+			[]query.SortField{{Field: "Name", Descending: true, CaseInsensitive: true}},
+			"ORDER BY LOWER(u.name) DESC",
+		},
+		{
+			"mixed sensitivity",
+			[]query.SortField{
+				{Field: "Name", CaseInsensitive: true},
+				{Field: "Email", Descending: true},
+			},
+			"ORDER BY LOWER(u.name) ASC, u.email DESC",
+		},
+		{
+			"non-text column ignores case-insensitivity",
+			[]query.SortField{{Field: "ID", Descending: true, CaseInsensitive: true}},
+			"ORDER BY u.id DESC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, _ := query.NewBuilder(pm).OrderByFields(tt.fields).BuildPage(1, 20)
+
+			if !strings.Contains(sql, tt.wantOrder) {
+				t.Errorf("BuildPage() missing %q, got %q", tt.wantOrder, sql)
+			}
+		})
+	}
+}
+
+func TestBuilder_SortBy(t *testing.T) {
+	tests := []struct {
+		name      string
+		fields    []query.SortField
+		wantOrder string
+	}{
+		{"view name", []query.SortField{{Field: "Name", Descending: true}}, "ORDER BY u.name DESC"},
+		{"column name", []query.SortField{{Field: "email"}}, "ORDER BY u.email ASC"},
+		{"case-insensitive column name", []query.SortField{{Field: "name", CaseInsensitive: true}}, "ORDER BY LOWER(u.name) ASC"},
+		{"empty keeps default", nil, "ORDER BY u.id ASC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := query.NewBuilder(newTestProjection(), query.SortField{Field: "ID"})
+			if err := b.SortBy(tt.fields); err != nil {
+				t.Fatalf("SortBy() error = %v", err)
+			}
+
+			sql, _ := b.BuildPage(1, 20)
+			if !strings.Contains(sql, tt.wantOrder) {
+				t.Errorf("BuildPage() missing %q, got %q", tt.wantOrder, sql)
+			}
+		})
+	}
+}
+
+func TestBuilder_SortBy_UnknownField(t *testing.T) {
+	b := query.NewBuilder(newTestProjection(), query.SortField{Field: "ID"})
+
+	err := b.SortBy([]query.SortField{{Field: "name"}, {Field: "password"}, {Field: "1; DROP TABLE users"}})
+	if !errors.Is(err, query.ErrInvalidSort) {
+		t.Fatalf("SortBy() error = %v, want ErrInvalidSort", err)
+	}
+
+	var serr *query.SortError
+	if !errors.As(err, &serr) {
+		t.Fatalf("SortBy() error = %T, want *query.SortError", err)
+	}
+	if !reflect.DeepEqual(serr.Fields, []string{"password", "1; DROP TABLE users"}) {
+		t.Errorf("Fields = %q, want the two unknown fields", serr.Fields)
+	}
+	if !reflect.DeepEqual(serr.Sortable, []string{"id", "name", "email"}) {
+		t.Errorf("Sortable = %v, want [id name email]", serr.Sortable)
+	}
+	if !strings.Contains(err.Error(), "sortable fields: id, name, email") {
+		t.Errorf("Error() = %q, want the sortable fields listed", err.Error())
+	}
+
+	sql, _ := b.BuildPage(1, 20)
+	if strings.Contains(sql, "DROP") || !strings.Contains(sql, "ORDER BY u.id ASC") {
+		t.Errorf("BuildPage() after rejected sort = %q, want default order", sql)
+	}
+}
+
+func newAllowlistProjection() *query.ProjectionMap {
+	return query.NewProjectionMap("public", "users", "u").
+		Project("id", "ID").
+		ProjectText("name", "Name").
+		ProjectText("password_hash", "PasswordHash").
+		Sortable("ID", "Name").
+		Filterable("Name")
+}
+
+func TestBuilder_SortBy_Allowlisted(t *testing.T) {
+	b := query.NewBuilder(newAllowlistProjection(), query.SortField{Field: "ID"})
+
+	if err := b.SortBy([]query.SortField{{Field: "name", Descending: true}}); err != nil {
+		t.Fatalf("SortBy() error = %v", err)
+	}
+
+	sql, _ := b.BuildPage(1, 20)
+	if !strings.Contains(sql, "ORDER BY u.name DESC") {
+		t.Errorf("BuildPage() missing allowlisted order, got %q", sql)
+	}
+}
+
+func TestBuilder_SortBy_NotAllowlisted(t *testing.T) {
+	for _, field := range []string{"password_hash", "PasswordHash"} {
+		t.Run(field, func(t *testing.T) {
+			b := query.NewBuilder(newAllowlistProjection(), query.SortField{Field: "ID"})
+
+			err := b.SortBy([]query.SortField{{Field: field}})
+			var serr *query.SortError
+			if !errors.As(err, &serr) {
+				t.Fatalf("SortBy() error = %v, want *query.SortError", err)
+			}
+			if !reflect.DeepEqual(serr.Sortable, []string{"id", "name"}) {
+				t.Errorf("Sortable = %v, want [id name]", serr.Sortable)
+			}
+
+			sql, _ := b.BuildPage(1, 20)
+			if !strings.Contains(sql, "ORDER BY u.id ASC") {
+				t.Errorf("BuildPage() after rejected sort = %q, want default order", sql)
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildDistinct_NotFilterable(t *testing.T) {
+	b := query.NewBuilder(newAllowlistProjection())
+
+	if _, _, err := b.BuildDistinct("Name"); err != nil {
+		t.Errorf("BuildDistinct(Name) error = %v", err)
+	}
+	if _, _, err := b.BuildDistinct("PasswordHash"); !errors.Is(err, query.ErrUnknownField) {
+		t.Errorf("BuildDistinct(PasswordHash) error = %v, want ErrUnknownField", err)
+	}
+}
+
+func TestProjectionMap_Sortable_UnprojectedPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Sortable() did not panic on an unprojected field")
+		}
+	}()
+
+	newTestProjection().Sortable("Name", "Password")
+}
+
+func TestBuilder_BuildKeyset(t *testing.T) {
+	tests := []struct {
+		name      string
+		sort      []query.SortField
+		after     []any
+		wantWhere string
+		wantOrder string
+		wantArgs  []any
+	}{
+		{
+			name:      "first batch",
+			sort:      []query.SortField{{Field: "Name"}},
+			wantWhere: "WHERE u.email = $1 ORDER BY",
+			wantOrder: "ORDER BY u.name ASC, u.id ASC LIMIT 10",
+			wantArgs:  []any{"a@example.com"},
+		},
+		{
+			name:      "ascending after value",
+			sort:      []query.SortField{{Field: "Name"}},
+			after:     []any{"bob", 7},
+			wantWhere: "WHERE u.email = $1 AND ((u.name > $2 OR u.name IS NULL) OR (u.name = $2 AND u.id > $3))",
+			wantOrder: "ORDER BY u.name ASC, u.id ASC LIMIT 10",
+			wantArgs:  []any{"a@example.com", "bob", 7},
+		},
+		{
+			name:      "descending case-insensitive after value",
+			sort:      []query.SortField{{Field: "Name", Descending: true, CaseInsensitive: true}},
+			after:     []any{"Bob", 7},
+			wantWhere: "AND (LOWER(u.name) < LOWER($2) OR (LOWER(u.name) = LOWER($2) AND u.id > $3))",
+			wantOrder: "ORDER BY LOWER(u.name) DESC, u.id ASC LIMIT 10",
+			wantArgs:  []any{"a@example.com", "Bob", 7},
+		},
+		{
+			name:      "ascending after null",
+			sort:      []query.SortField{{Field: "Name"}},
+			after:     []any{(*string)(nil), 7},
+			wantWhere: "WHERE u.email = $1 AND (u.name IS NULL AND u.id > $2) ORDER BY",
+			wantArgs:  []any{"a@example.com", 7},
+		},
+		{
+			name:      "descending after null",
+			sort:      []query.SortField{{Field: "Name", Descending: true}},
+			after:     []any{nil, 7},
+			wantWhere: "AND (u.name IS NOT NULL OR (u.name IS NULL AND u.id > $2))",
+			wantArgs:  []any{"a@example.com", 7},
+		},
+		{
+			name:      "sort already includes tie field",
+			sort:      []query.SortField{{Field: "ID", Descending: true}},
+			after:     []any{7},
+			wantWhere: "WHERE u.email = $1 AND u.id < $2 ORDER BY",
+			wantOrder: "ORDER BY u.id DESC LIMIT 10",
+			wantArgs:  []any{"a@example.com", 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := query.NewBuilder(newTestProjection()).WhereEquals("Email", "a@example.com")
+			if err := b.SortBy(tt.sort); err != nil {
+				t.Fatalf("SortBy() error = %v", err)
+			}
+
+			sql, args := b.BuildKeyset("ID", tt.after, 10)
+			if !strings.HasPrefix(sql, "SELECT u.id, u.name, u.email FROM public.users u WHERE") {
+				t.Errorf("BuildKeyset() sql = %q, want a select over the projection", sql)
+			}
+			if !strings.Contains(sql, tt.wantWhere) {
+				t.Errorf("BuildKeyset() sql = %q, want %q", sql, tt.wantWhere)
+			}
+			if tt.wantOrder != "" && !strings.HasSuffix(sql, tt.wantOrder) {
+				t.Errorf("BuildKeyset() sql = %q, want suffix %q", sql, tt.wantOrder)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("BuildKeyset() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildKeyset_MismatchedCursorPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("BuildKeyset() did not panic on a cursor missing the tie field")
+		}
+	}()
+
+	query.NewBuilder(newTestProjection(), query.SortField{Field: "Name"}).BuildKeyset("ID", []any{"bob"}, 10)
+}
+
+func TestBuilder_KeysetOrder(t *testing.T) {
+	b := query.NewBuilder(newTestProjection(), query.SortField{Field: "Name", Descending: true})
+
+	want := []query.SortField{{Field: "Name", Descending: true}, {Field: "ID"}}
+	if got := b.KeysetOrder("ID"); !reflect.DeepEqual(got, want) {
+		t.Errorf("KeysetOrder() = %v, want %v", got, want)
+	}
+
+	sql, _ := b.BuildPage(1, 10)
+	if !strings.Contains(sql, "ORDER BY u.name DESC LIMIT") {
+		t.Errorf("KeysetOrder() changed the page order: %q", sql)
+	}
+}
+
 func TestBuilder_OrderByFields_EmptyUsesDefault(t *testing.T) {
 	pm := newTestProjection()
 	b := query.NewBuilder(pm, query.SortField{Field: "Name"}).OrderByFields(nil)
@@ -334,6 +641,130 @@ func TestBuilder_WhereIn_EmptyIgnored(t *testing.T) {
 	}
 }
 
+func TestBuilder_WhereNotEquals(t *testing.T) {
+	pm := newTestProjection()
+	b := query.NewBuilder(pm, query.SortField{Field: "Name"}).WhereNotEquals("Name", "test")
+
+	sql, args := b.BuildCount()
+
+	if !strings.Contains(sql, "WHERE u.name <> $1") {
+		t.Errorf("BuildCount() missing inequality clause, got %q", sql)
+	}
+
+	if len(args) != 1 || args[0] != "test" {
+		t.Errorf("BuildCount() args = %v, want [test]", args)
+	}
+}
+
+func TestBuilder_WhereNotEquals_NilIgnored(t *testing.T) {
+	pm := newTestProjection()
+	b := query.NewBuilder(pm, query.SortField{Field: "Name"}).WhereNotEquals("ID", nil)
+
+	sql, args := b.BuildCount()
+
+	if strings.Contains(sql, "WHERE") {
+		t.Errorf("BuildCount() should not have WHERE for nil, got %q", sql)
+	}
+
+	if len(args) != 0 {
+		t.Errorf("BuildCount() args = %v, want empty", args)
+	}
+}
+
+func TestBuilder_WhereNotIn(t *testing.T) {
+	pm := newTestProjection()
+	b := query.NewBuilder(pm, query.SortField{Field: "Name"}).WhereNotIn("ID", []any{1, 2, 3})
+
+	sql, args := b.BuildCount()
+
+	if !strings.Contains(sql, "WHERE u.id NOT IN ($1, $2, $3)") {
+		t.Errorf("BuildCount() missing NOT IN clause, got %q", sql)
+	}
+
+	if len(args) != 3 {
+		t.Errorf("BuildCount() len(args) = %d, want 3", len(args))
+	}
+}
+
+func TestBuilder_WhereNotIn_EmptyIgnored(t *testing.T) {
+	pm := newTestProjection()
+	b := query.NewBuilder(pm, query.SortField{Field: "Name"}).WhereNotIn("ID", []any{})
+
+	sql, args := b.BuildCount()
+
+	if strings.Contains(sql, "WHERE") {
+		t.Errorf("BuildCount() should not have WHERE for empty slice, got %q", sql)
+	}
+
+	if len(args) != 0 {
+		t.Errorf("BuildCount() args = %v, want empty", args)
+	}
+}
+
+func TestBuilder_WhereComparisons(t *testing.T) {
+	tests := []struct {
+		name   string
+		apply  func(*query.Builder) *query.Builder
+		clause string
+	}{
+		{"greater than", func(b *query.Builder) *query.Builder { return b.WhereGreaterThan("ID", 5) }, "WHERE u.id > $1"},
+		{"greater or equal", func(b *query.Builder) *query.Builder { return b.WhereGreaterOrEqual("ID", 5) }, "WHERE u.id >= $1"},
+		{"less than", func(b *query.Builder) *query.Builder { return b.WhereLessThan("ID", 5) }, "WHERE u.id < $1"},
+		{"less or equal", func(b *query.Builder) *query.Builder { return b.WhereLessOrEqual("ID", 5) }, "WHERE u.id <= $1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.apply(query.NewBuilder(newTestProjection(), query.SortField{Field: "Name"}))
+
+			sql, args := b.BuildCount()
+
+			if !strings.Contains(sql, tt.clause) {
+				t.Errorf("BuildCount() missing %q, got %q", tt.clause, sql)
+			}
+
+			if len(args) != 1 || args[0] != 5 {
+				t.Errorf("BuildCount() args = %v, want [5]", args)
+			}
+		})
+	}
+}
+
+func TestBuilder_WhereComparisons_Range(t *testing.T) {
+	b := query.NewBuilder(newTestProjection(), query.SortField{Field: "Name"}).
+		WhereGreaterOrEqual("ID", 1).
+		WhereLessThan("ID", 10)
+
+	sql, args := b.BuildCount()
+
+	if !strings.Contains(sql, "WHERE u.id >= $1 AND u.id < $2") {
+		t.Errorf("BuildCount() missing range clause, got %q", sql)
+	}
+
+	if len(args) != 2 {
+		t.Errorf("BuildCount() len(args) = %d, want 2", len(args))
+	}
+}
+
+func TestBuilder_WhereComparisons_NilIgnored(t *testing.T) {
+	var nilInt *int
+	b := query.NewBuilder(newTestProjection(), query.SortField{Field: "Name"}).
+		WhereGreaterThan("ID", nil).
+		WhereGreaterOrEqual("ID", nilInt).
+		WhereLessThan("ID", nil).
+		WhereLessOrEqual("ID", nilInt)
+
+	sql, args := b.BuildCount()
+
+	if strings.Contains(sql, "WHERE") {
+		t.Errorf("BuildCount() should not have WHERE for nil, got %q", sql)
+	}
+
+	if len(args) != 0 {
+		t.Errorf("BuildCount() args = %v, want empty", args)
+	}
+}
+
 func TestBuilder_WhereSearch(t *testing.T) {
 	pm := newTestProjection()
 	search := "test"
@@ -488,6 +919,19 @@ func TestParseSortFields(t *testing.T) {
 				{Field: "email", Descending: false},
 			},
 		},
+		{
+			"case-insensitive ascending",
+			"name:ci",
+			[]query.SortField{{Field: "name", CaseInsensitive: true}},
+		},
+		{
+			"case-insensitive descending with mixed fields",
+			"-name:ci,email",
+			[]query.SortField{
+				{Field: "name", Descending: true, CaseInsensitive: true},
+				{Field: "email", Descending: false},
+			},
+		},
 		{
 			"with spaces",
 			"name, -createdAt, email",
@@ -521,7 +965,298 @@ func TestParseSortFields(t *testing.T) {
 				if got[i].Descending != wantField.Descending {
 					t.Errorf("ParseSortFields(%q)[%d].Descending = %v, want %v", tt.input, i, got[i].Descending, wantField.Descending)
 				}
+				if got[i].CaseInsensitive != wantField.CaseInsensitive {
+					t.Errorf("ParseSortFields(%q)[%d].CaseInsensitive = %v, want %v", tt.input, i, got[i].CaseInsensitive, wantField.CaseInsensitive)
+				}
 			}
 		})
 	}
 }
+
+func newJSONProjection() *query.ProjectionMap {
+	return query.NewProjectionMap("public", "agents", "a").
+		Project("id", "ID").
+		Project("config", "Config")
+}
+
+func TestBuilder_WhereJSONEquals(t *testing.T) {
+	provider := "azure"
+	b := query.NewBuilder(newJSONProjection()).
+		WhereEquals("ID", 7).
+		WhereJSONEquals("Config", "provider.name", &provider)
+
+	sql, args := b.Build()
+
+	if !strings.Contains(sql, "WHERE a.id = $1 AND a.config #>> '{provider,name}' = $2") {
+		t.Errorf("Build() missing JSON condition, got %q", sql)
+	}
+	if len(args) != 2 || args[1] != "azure" {
+		t.Errorf("Build() args = %v, want [7 azure]", args)
+	}
+}
+
+func TestBuilder_WhereJSONEquals_ArrayIndexAndNonString(t *testing.T) {
+	b := query.NewBuilder(newJSONProjection()).
+		WhereJSONEquals("Config", "stages.0.retries", 3)
+
+	sql, args := b.BuildCount()
+
+	if !strings.Contains(sql, "a.config #>> '{stages,0,retries}' = $1") {
+		t.Errorf("BuildCount() missing JSON condition, got %q", sql)
+	}
+	if len(args) != 1 || args[0] != "3" {
+		t.Errorf("BuildCount() args = %v, want [\"3\"]", args)
+	}
+}
+
+func TestBuilder_WhereJSONEquals_NilIgnored(t *testing.T) {
+	var provider *string
+	b := query.NewBuilder(newJSONProjection()).
+		WhereJSONEquals("Config", "provider.name", provider).
+		WhereJSONEquals("Config", "provider.name", nil)
+
+	sql, args := b.Build()
+
+	if strings.Contains(sql, "WHERE") {
+		t.Errorf("Build() unexpected WHERE clause, got %q", sql)
+	}
+	if len(args) != 0 {
+		t.Errorf("Build() args = %v, want empty", args)
+	}
+}
+
+func TestBuilder_WhereJSONEquals_RejectsMaliciousPath(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("WhereJSONEquals() did not panic on malicious path")
+		}
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, query.ErrInvalidJSONPath) {
+			t.Errorf("panic = %v, want ErrInvalidJSONPath", r)
+		}
+	}()
+
+	query.NewBuilder(newJSONProjection()).
+		WhereJSONEquals("Config", "provider}' = '' OR 1=1 --", "x")
+}
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{"provider", []string{"provider"}, false},
+		{"provider.name", []string{"provider", "name"}, false},
+		{"items.0.id", []string{"items", "0", "id"}, false},
+		{"", nil, true},
+		{"provider..name", nil, true},
+		{"provider.name'", nil, true},
+		{"a,b", nil, true},
+		{"a}' OR '1'='1", nil, true},
+		{"a b", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := query.ParseJSONPath(tt.path)
+
+			if tt.wantErr {
+				if !errors.Is(err, query.ErrInvalidJSONPath) {
+					t.Errorf("ParseJSONPath(%q) error = %v, want ErrInvalidJSONPath", tt.path, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%q) error = %v", tt.path, err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ParseJSONPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func newImageProjection() *query.ProjectionMap {
+	return query.NewProjectionMap("public", "images", "i").
+		Project("document_id", "DocumentID").
+		Project("format", "Format").
+		Project("dpi", "DPI")
+}
+
+func TestBuilder_WhereExists_RenumbersPlaceholders(t *testing.T) {
+	name := "report"
+	b := query.NewBuilder(newTestProjection()).
+		WhereEquals("ID", 5).
+		WhereExists("SELECT 1 FROM public.images i WHERE i.document_id = u.id AND i.format = $1 AND i.dpi > $2", "png", 150).
+		WhereContains("Name", &name)
+
+	sql, args := b.BuildCount()
+
+	want := "SELECT COUNT(*) FROM public.users u WHERE u.id = $1" +
+		" AND EXISTS (SELECT 1 FROM public.images i WHERE i.document_id = u.id AND i.format = $2 AND i.dpi > $3)" +
+		" AND u.name ILIKE $4"
+	if sql != want {
+		t.Errorf("BuildCount() sql = %q, want %q", sql, want)
+	}
+
+	wantArgs := []any{5, "png", 150, "%report%"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("BuildCount() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBuilder_WhereExists_RepeatedAndQuotedPlaceholders(t *testing.T) {
+	b := query.NewBuilder(newTestProjection()).
+		WhereEquals("Email", "a@example.com").
+		WhereNotExists(`SELECT 1 FROM public.images i WHERE i.format = $2 AND (i.dpi = $1 OR i.dpi = $1 * 2) AND i.note <> '$1' AND "$2" IS NULL`, 300, "jpg")
+
+	sql, args := b.BuildCount()
+
+	want := "SELECT COUNT(*) FROM public.users u WHERE u.email = $1" +
+		` AND NOT EXISTS (SELECT 1 FROM public.images i WHERE i.format = $3 AND (i.dpi = $2 OR i.dpi = $2 * 2) AND i.note <> '$1' AND "$2" IS NULL)`
+	if sql != want {
+		t.Errorf("BuildCount() sql = %q, want %q", sql, want)
+	}
+
+	wantArgs := []any{"a@example.com", 300, "jpg"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("BuildCount() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBuilder_WhereExists_MissingArgPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WhereExists() did not panic on unbound placeholder")
+		}
+	}()
+
+	query.NewBuilder(newTestProjection()).
+		WhereExists("SELECT 1 FROM public.images i WHERE i.dpi = $2", 300)
+}
+
+func TestBuilder_WhereExistsIn_Correlated(t *testing.T) {
+	sub := query.NewBuilder(newImageProjection(), query.SortField{Field: "DPI"}).
+		WhereEquals("Format", "png").
+		WhereGreaterOrEqual("DPI", 150)
+
+	b := query.NewBuilder(newTestProjection(), query.SortField{Field: "Name"}).
+		WhereEquals("Name", "report").
+		WhereExistsIn("ID", sub, "DocumentID").
+		WhereEquals("Email", "a@example.com")
+
+	sql, args := b.BuildPage(2, 10)
+
+	want := "SELECT u.id, u.name, u.email FROM public.users u WHERE u.name = $1" +
+		" AND EXISTS (SELECT 1 FROM public.images i WHERE i.document_id = u.id AND i.format = $2 AND i.dpi >= $3)" +
+		" AND u.email = $4 ORDER BY u.name ASC LIMIT 10 OFFSET 10"
+	if sql != want {
+		t.Errorf("BuildPage() sql = %q, want %q", sql, want)
+	}
+
+	wantArgs := []any{"report", "png", 150, "a@example.com"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("BuildPage() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBuilder_WhereNotExistsIn_NoSubConditions(t *testing.T) {
+	b := query.NewBuilder(newTestProjection()).
+		WhereNotExistsIn("ID", query.NewBuilder(newImageProjection()), "DocumentID")
+
+	sql, args := b.BuildCount()
+
+	want := "SELECT COUNT(*) FROM public.users u WHERE NOT EXISTS (SELECT 1 FROM public.images i WHERE i.document_id = u.id)"
+	if sql != want {
+		t.Errorf("BuildCount() sql = %q, want %q", sql, want)
+	}
+	if len(args) != 0 {
+		t.Errorf("BuildCount() args = %v, want empty", args)
+	}
+}
+
+func TestBuilder_WhereExists_Nested(t *testing.T) {
+	inner := query.NewBuilder(newImageProjection()).
+		WhereEquals("Format", "png")
+	middle := query.NewBuilder(newJSONProjection()).
+		WhereEquals("Config", "x").
+		WhereExistsIn("ID", inner, "DocumentID")
+
+	b := query.NewBuilder(newTestProjection()).
+		WhereEquals("Name", "report").
+		WhereExistsIn("ID", middle, "ID")
+
+	sql, args := b.BuildCount()
+
+	want := "SELECT COUNT(*) FROM public.users u WHERE u.name = $1" +
+		" AND EXISTS (SELECT 1 FROM public.agents a WHERE a.id = u.id AND a.config = $2" +
+		" AND EXISTS (SELECT 1 FROM public.images i WHERE i.document_id = a.id AND i.format = $3))"
+	if sql != want {
+		t.Errorf("BuildCount() sql = %q, want %q", sql, want)
+	}
+
+	wantArgs := []any{"report", "x", "png"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("BuildCount() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBuilder_WhereRaw_MergesWithConditions(t *testing.T) {
+	name := "report"
+	b := query.NewBuilder(newTestProjection(), query.SortField{Field: "Name"}).
+		WhereEquals("ID", 5).
+		WhereRaw("u.tags @> ? OR date_trunc('day', u.created_at) = ?", []string{"a"}, "2026-01-01").
+		WhereContains("Name", &name)
+
+	sql, args := b.BuildPage(1, 10)
+
+	want := "SELECT u.id, u.name, u.email FROM public.users u WHERE u.id = $1" +
+		" AND (u.tags @> $2 OR date_trunc('day', u.created_at) = $3)" +
+		" AND u.name ILIKE $4 ORDER BY u.name ASC LIMIT 10 OFFSET 0"
+	if sql != want {
+		t.Errorf("BuildPage() sql = %q, want %q", sql, want)
+	}
+
+	wantArgs := []any{5, []string{"a"}, "2026-01-01", "%report%"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("BuildPage() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBuilder_WhereRaw_Renumbering(t *testing.T) {
+	b := query.NewBuilder(newTestProjection()).
+		WhereRaw("u.meta ?? 'x' AND u.note <> '?' AND \"?\" IS NULL AND u.score > ?", 10).
+		WhereExists("SELECT 1 FROM public.images i WHERE i.dpi = $1", 300).
+		WhereRaw("u.rank BETWEEN ? AND ?", 1, 3).
+		WhereEquals("Email", "a@example.com")
+
+	sql, args := b.BuildCount()
+
+	want := "SELECT COUNT(*) FROM public.users u WHERE" +
+		" (u.meta ? 'x' AND u.note <> '?' AND \"?\" IS NULL AND u.score > $1)" +
+		" AND EXISTS (SELECT 1 FROM public.images i WHERE i.dpi = $2)" +
+		" AND (u.rank BETWEEN $3 AND $4)" +
+		" AND u.email = $5"
+	if sql != want {
+		t.Errorf("BuildCount() sql = %q, want %q", sql, want)
+	}
+
+	wantArgs := []any{10, 300, 1, 3, "a@example.com"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("BuildCount() args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestBuilder_WhereRaw_ArgCountMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WhereRaw() did not panic on mismatched args")
+		}
+	}()
+
+	query.NewBuilder(newTestProjection()).
+		WhereRaw("u.id = ? AND u.name = ?", 5)
+}
@@ -0,0 +1,108 @@
+package pkg_query_test
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/google/uuid"
+)
+
+type testFormat string
+
+func parseTestFormat(s string) (testFormat, error) {
+	switch s {
+	case "png":
+		return "png", nil
+	case "jpg", "jpeg":
+		return "jpg", nil
+	default:
+		return "", fmt.Errorf("unsupported format %q", s)
+	}
+}
+
+type testFilters struct {
+	DocumentID *uuid.UUID
+	Format     *testFormat
+	PageNumber *int
+	Name       *string
+}
+
+var testFilterSpec = query.NewFilterSpec(
+	query.UUIDFilter("document_id", func(f *testFilters, v uuid.UUID) { f.DocumentID = &v }),
+	query.EnumFilter("format", parseTestFormat, func(f *testFilters, v testFormat) { f.Format = &v }),
+	query.IntFilter("page_number", func(f *testFilters, v int) { f.PageNumber = &v }),
+	query.StringFilter("name", func(f *testFilters, v string) { f.Name = &v }),
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestFilterSpec_Parse(t *testing.T) {
+	docID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+
+	tests := []struct {
+		name  string
+		query string
+		want  testFilters
+	}{
+		{"empty query", "", testFilters{}},
+		{"uuid field", "document_id=11111111-1111-1111-1111-111111111111", testFilters{DocumentID: &docID}},
+		{"enum field", "format=png", testFilters{Format: ptr(testFormat("png"))}},
+		{"enum alias", "format=jpg", testFilters{Format: ptr(testFormat("jpg"))}},
+		{"int field", "page_number=5", testFilters{PageNumber: ptr(5)}},
+		{"string field", "name=report", testFilters{Name: ptr("report")}},
+		{
+			"all fields",
+			"document_id=11111111-1111-1111-1111-111111111111&format=png&page_number=3&name=report",
+			testFilters{DocumentID: &docID, Format: ptr(testFormat("png")), PageNumber: ptr(3), Name: ptr("report")},
+		},
+		{"invalid uuid ignored", "document_id=invalid", testFilters{}},
+		{"invalid enum ignored", "format=gif", testFilters{}},
+		{"invalid int ignored", "page_number=abc", testFilters{}},
+		{"empty values ignored", "document_id=&format=&page_number=&name=", testFilters{}},
+		{"unrelated params ignored", "page=1&page_size=10", testFilters{}},
+		{
+			"invalid value does not affect others",
+			"document_id=invalid&page_number=2",
+			testFilters{PageNumber: ptr(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, _ := url.ParseQuery(tt.query)
+
+			if got := testFilterSpec.Parse(values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_CustomParser(t *testing.T) {
+	type bounds struct{ Min *int64 }
+
+	nonNegative := func(s string) (int64, error) {
+		var n int64
+		if _, err := fmt.Sscan(s, &n); err != nil {
+			return 0, err
+		}
+		if n < 0 {
+			return 0, fmt.Errorf("negative")
+		}
+		return n, nil
+	}
+
+	spec := query.NewFilterSpec(
+		query.Filter("min", nonNegative, func(f *bounds, v int64) { f.Min = &v }),
+	)
+
+	if got := spec.Parse(url.Values{"min": {"10"}}); got.Min == nil || *got.Min != 10 {
+		t.Errorf("Parse(min=10) Min = %v, want 10", got.Min)
+	}
+	if got := spec.Parse(url.Values{"min": {"-1"}}); got.Min != nil {
+		t.Errorf("Parse(min=-1) Min = %d, want nil", *got.Min)
+	}
+}
@@ -43,6 +43,28 @@ func TestProjectionMap_Project(t *testing.T) {
 	}
 }
 
+func TestProjectionMap_ProjectText(t *testing.T) {
+	pm := query.NewProjectionMap("public", "users", "u").
+		Project("id", "ID").
+		ProjectText("name", "Name")
+
+	if col := pm.Column("Name"); col != "u.name" {
+		t.Errorf("Column(%q) = %q, want %q", "Name", col, "u.name")
+	}
+
+	if !pm.IsText("Name") {
+		t.Error("IsText(\"Name\") = false, want true")
+	}
+
+	if pm.IsText("ID") {
+		t.Error("IsText(\"ID\") = true, want false")
+	}
+
+	if pm.IsText("Unknown") {
+		t.Error("IsText(\"Unknown\") = true, want false")
+	}
+}
+
 func TestProjectionMap_Column_UnknownReturnsInput(t *testing.T) {
 	pm := query.NewProjectionMap("public", "users", "u").
 		Project("id", "ID")
@@ -53,6 +75,20 @@ func TestProjectionMap_Column_UnknownReturnsInput(t *testing.T) {
 	}
 }
 
+func TestProjectionMap_ColumnName(t *testing.T) {
+	pm := query.NewProjectionMap("public", "users", "u").
+		Project("email", "Email")
+
+	col, ok := pm.ColumnName("Email")
+	if !ok || col != "email" {
+		t.Errorf("ColumnName(%q) = %q, %v, want %q, true", "Email", col, ok, "email")
+	}
+
+	if col, ok := pm.ColumnName("Unknown"); ok {
+		t.Errorf("ColumnName(%q) = %q, true, want not found", "Unknown", col)
+	}
+}
+
 func TestProjectionMap_Columns(t *testing.T) {
 	pm := query.NewProjectionMap("public", "users", "u").
 		Project("id", "ID").
@@ -84,3 +120,29 @@ func TestProjectionMap_ColumnList(t *testing.T) {
 		t.Errorf("ColumnList()[1] = %q, want %q", list[1], "u.email")
 	}
 }
+
+func TestProjectionMap_Resolve(t *testing.T) {
+	pm := query.NewProjectionMap("public", "users", "u").
+		Project("created_at", "CreatedAt")
+
+	for _, name := range []string{"CreatedAt", "created_at"} {
+		if view, ok := pm.Resolve(name); !ok || view != "CreatedAt" {
+			t.Errorf("Resolve(%q) = %q, %v, want %q, true", name, view, ok, "CreatedAt")
+		}
+	}
+
+	if view, ok := pm.Resolve("createdAt"); ok {
+		t.Errorf("Resolve(%q) = %q, true, want not found", "createdAt", view)
+	}
+}
+
+func TestProjectionMap_ColumnNames(t *testing.T) {
+	pm := query.NewProjectionMap("public", "users", "u").
+		Project("id", "ID").
+		Project("email", "Email")
+
+	names := pm.ColumnNames()
+	if len(names) != 2 || names[0] != "id" || names[1] != "email" {
+		t.Errorf("ColumnNames() = %v, want [id email]", names)
+	}
+}
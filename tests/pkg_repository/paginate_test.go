@@ -0,0 +1,232 @@
+package pkg_repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/pagination"
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/agent-lab/pkg/repository"
+)
+
+// fakeTable is an in-memory table served by fakeDriver. COUNT queries return
+// len(names); page queries honor the LIMIT and OFFSET emitted by BuildPage;
+// unpaged single-row lookups return the first name.
+type fakeTable struct {
+	names   []string
+	queries []string
+}
+
+var (
+	fakeMu     sync.Mutex
+	fakeTables = map[string]*fakeTable{}
+)
+
+func init() {
+	sql.Register("fakepage", fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	return &fakeConn{table: fakeTables[dsn]}, nil
+}
+
+type fakeConn struct {
+	table *fakeTable
+}
+
+func (c *fakeConn) Prepare(q string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: q}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("not supported") }
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	t := s.conn.table
+	t.queries = append(t.queries, s.query)
+
+	if strings.HasPrefix(s.query, "SELECT COUNT(*)") {
+		return &fakeRows{cols: []string{"count"}, values: [][]driver.Value{{int64(len(t.names))}}}, nil
+	}
+
+	var limit, offset int
+	idx := strings.Index(s.query, " LIMIT ")
+	if idx < 0 && strings.Contains(s.query, " WHERE ") && len(t.names) > 0 {
+		return &fakeRows{cols: []string{"name"}, values: [][]driver.Value{{t.names[0]}}}, nil
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+	if _, err := fmt.Sscanf(s.query[idx:], " LIMIT %d OFFSET %d", &limit, &offset); err != nil {
+		return nil, err
+	}
+
+	rows := &fakeRows{cols: []string{"name"}}
+	for i := offset; i < len(t.names) && i < offset+limit; i++ {
+		rows.values = append(rows.values, []driver.Value{t.names[i]})
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	cols   []string
+	values [][]driver.Value
+	pos    int
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+func openFakeDB(t *testing.T, names []string) (*sql.DB, *fakeTable) {
+	t.Helper()
+
+	table := &fakeTable{names: names}
+	dsn := t.Name()
+
+	fakeMu.Lock()
+	fakeTables[dsn] = table
+	fakeMu.Unlock()
+
+	db, err := sql.Open("fakepage", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, table
+}
+
+func scanName(s repository.Scanner) (string, error) {
+	var name string
+	err := s.Scan(&name)
+	return name, err
+}
+
+func newPaginateBuilder() *query.Builder {
+	pm := query.NewProjectionMap("public", "items", "i").Project("name", "Name")
+	return query.NewBuilder(pm, query.SortField{Field: "Name"})
+}
+
+var paginateConfig = pagination.Config{DefaultPageSize: 2, MaxPageSize: 10}
+
+func TestPaginate_Empty(t *testing.T) {
+	db, _ := openFakeDB(t, nil)
+
+	result, err := repository.Paginate(context.Background(), db, newPaginateBuilder(), pagination.PageRequest{}, paginateConfig, scanName)
+	if err != nil {
+		t.Fatalf("Paginate() error = %v", err)
+	}
+
+	if result.Total != 0 {
+		t.Errorf("Total = %d, want 0", result.Total)
+	}
+
+	if result.Data == nil || len(result.Data) != 0 {
+		t.Errorf("Data = %v, want empty slice", result.Data)
+	}
+
+	if result.Page != 1 {
+		t.Errorf("Page = %d, want 1 (normalized)", result.Page)
+	}
+
+	if result.PageSize != paginateConfig.DefaultPageSize {
+		t.Errorf("PageSize = %d, want %d", result.PageSize, paginateConfig.DefaultPageSize)
+	}
+}
+
+func TestPaginate_MultiPage(t *testing.T) {
+	db, _ := openFakeDB(t, []string{"a", "b", "c", "d", "e"})
+
+	tests := []struct {
+		page     int
+		wantData []string
+	}{
+		{1, []string{"a", "b"}},
+		{2, []string{"c", "d"}},
+		{3, []string{"e"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("page %d", tt.page), func(t *testing.T) {
+			req := pagination.PageRequest{Page: tt.page, PageSize: 2}
+
+			result, err := repository.Paginate(context.Background(), db, newPaginateBuilder(), req, paginateConfig, scanName)
+			if err != nil {
+				t.Fatalf("Paginate() error = %v", err)
+			}
+
+			if result.Total != 5 {
+				t.Errorf("Total = %d, want 5", result.Total)
+			}
+
+			if result.TotalPages != 3 {
+				t.Errorf("TotalPages = %d, want 3", result.TotalPages)
+			}
+
+			if strings.Join(result.Data, ",") != strings.Join(tt.wantData, ",") {
+				t.Errorf("Data = %v, want %v", result.Data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestPaginate_AppliesSort(t *testing.T) {
+	db, table := openFakeDB(t, []string{"a"})
+
+	req := pagination.PageRequest{Sort: []query.SortField{{Field: "Name", Descending: true}}}
+
+	if _, err := repository.Paginate(context.Background(), db, newPaginateBuilder(), req, paginateConfig, scanName); err != nil {
+		t.Fatalf("Paginate() error = %v", err)
+	}
+
+	last := table.queries[len(table.queries)-1]
+	if !strings.Contains(last, "ORDER BY i.name DESC") {
+		t.Errorf("page query missing requested sort, got %q", last)
+	}
+}
+
+func TestPaginate_UnknownSortField(t *testing.T) {
+	db, table := openFakeDB(t, []string{"a"})
+
+	req := pagination.PageRequest{Sort: []query.SortField{{Field: "rank"}}}
+
+	_, err := repository.Paginate(context.Background(), db, newPaginateBuilder(), req, paginateConfig, scanName)
+	if !errors.Is(err, query.ErrInvalidSort) {
+		t.Fatalf("Paginate() error = %v, want ErrInvalidSort", err)
+	}
+	if len(table.queries) != 0 {
+		t.Errorf("queries = %q, want none for a rejected sort", table.queries)
+	}
+}
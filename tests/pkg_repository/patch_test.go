@@ -0,0 +1,128 @@
+package pkg_repository_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/query"
+	"github.com/JaimeStill/agent-lab/pkg/repository"
+)
+
+var patchProjection = query.
+	NewProjectionMap("public", "profiles", "p").
+	Project("id", "ID").
+	Project("name", "Name").
+	Project("description", "Description").
+	Project("options", "Options").
+	Project("updated_at", "UpdatedAt")
+
+type profilePatch struct {
+	Name        *string
+	Description *string
+	Options     json.RawMessage
+}
+
+func TestBuildPartialUpdate_SingleField(t *testing.T) {
+	desc := "new description"
+
+	q, args, err := repository.BuildPartialUpdate(patchProjection, "ID", "id-1", profilePatch{Description: &desc})
+	if err != nil {
+		t.Fatalf("BuildPartialUpdate() error = %v", err)
+	}
+
+	want := "UPDATE public.profiles p SET description = $2, updated_at = NOW() WHERE p.id = $1 " +
+		"RETURNING p.id, p.name, p.description, p.options, p.updated_at"
+	if q != want {
+		t.Errorf("query = %q, want %q", q, want)
+	}
+	if len(args) != 2 || args[0] != "id-1" || args[1] != desc {
+		t.Errorf("args = %v, want [id-1 %q]", args, desc)
+	}
+}
+
+func TestBuildPartialUpdate_MultipleFields(t *testing.T) {
+	name := "renamed"
+	desc := "updated"
+	opts := json.RawMessage(`{"temperature":0.2}`)
+
+	q, args, err := repository.BuildPartialUpdate(patchProjection, "ID", "id-1", &profilePatch{Name: &name, Description: &desc, Options: opts})
+	if err != nil {
+		t.Fatalf("BuildPartialUpdate() error = %v", err)
+	}
+
+	wantSet := "SET name = $2, description = $3, options = $4, updated_at = NOW()"
+	if !strings.Contains(q, wantSet) {
+		t.Errorf("query = %q, want %q", q, wantSet)
+	}
+	if len(args) != 4 || args[1] != name || args[2] != desc || string(args[3].(json.RawMessage)) != string(opts) {
+		t.Errorf("args = %v, want id, name, description, options", args)
+	}
+}
+
+func TestBuildPartialUpdate_NoFields(t *testing.T) {
+	q, args, err := repository.BuildPartialUpdate(patchProjection, "ID", "id-1", profilePatch{})
+	if err != nil {
+		t.Fatalf("BuildPartialUpdate() error = %v", err)
+	}
+	if q != "" || args != nil {
+		t.Errorf("BuildPartialUpdate() = %q, %v, want empty query", q, args)
+	}
+}
+
+func TestBuildPartialUpdate_RejectsUnprojectedField(t *testing.T) {
+	type injection struct {
+		Name *string
+		Role *string
+	}
+	role := "admin"
+
+	if _, _, err := repository.BuildPartialUpdate(patchProjection, "ID", "id-1", injection{Role: &role}); err == nil {
+		t.Error("BuildPartialUpdate() with unprojected field should fail")
+	}
+}
+
+func TestBuildPartialUpdate_RejectsInvalidPatch(t *testing.T) {
+	type valueField struct {
+		Name string
+	}
+
+	tests := []struct {
+		name    string
+		idField string
+		patch   any
+	}{
+		{"non-struct", "ID", "name"},
+		{"non-nillable field", "ID", valueField{Name: "x"}},
+		{"unprojected id", "Key", profilePatch{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := repository.BuildPartialUpdate(patchProjection, tt.idField, "id-1", tt.patch); err == nil {
+				t.Error("BuildPartialUpdate() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestUpdatePartial_NoOpSelectsCurrentRow(t *testing.T) {
+	db, table := openFakeDB(t, []string{"current"})
+
+	pm := query.NewProjectionMap("public", "items", "i").
+		Project("id", "ID").
+		Project("name", "Name")
+
+	got, err := repository.UpdatePartial(context.Background(), db, pm, "ID", "id-1", struct{ Name *string }{}, scanName)
+	if err != nil {
+		t.Fatalf("UpdatePartial() error = %v", err)
+	}
+	if got != "current" {
+		t.Errorf("UpdatePartial() = %q, want %q", got, "current")
+	}
+
+	if len(table.queries) != 1 || !strings.Contains(table.queries[0], "SELECT i.id, i.name FROM public.items i WHERE i.id = $1") {
+		t.Errorf("queries = %v, want a single SELECT", table.queries)
+	}
+}
@@ -0,0 +1,182 @@
+package pkg_repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// txCounter records transaction outcomes observed by txDriver.
+type txCounter struct {
+	commits   atomic.Int32
+	rollbacks atomic.Int32
+}
+
+var txCounters = map[string]*txCounter{}
+
+func init() {
+	sql.Register("faketx", txDriver{})
+}
+
+type txDriver struct{}
+
+func (txDriver) Open(dsn string) (driver.Conn, error) {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	return &txConn{counter: txCounters[dsn]}, nil
+}
+
+type txConn struct {
+	counter *txCounter
+}
+
+func (c *txConn) Prepare(q string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (c *txConn) Close() error              { return nil }
+func (c *txConn) Begin() (driver.Tx, error) { return &fakeTx{counter: c.counter}, nil }
+
+type fakeTx struct {
+	counter *txCounter
+}
+
+func (t *fakeTx) Commit() error {
+	t.counter.commits.Add(1)
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.counter.rollbacks.Add(1)
+	return nil
+}
+
+func openTxDB(t *testing.T) (*sql.DB, *txCounter) {
+	t.Helper()
+
+	counter := &txCounter{}
+	dsn := t.Name()
+
+	fakeMu.Lock()
+	txCounters[dsn] = counter
+	fakeMu.Unlock()
+
+	db, err := sql.Open("faketx", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, counter
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pgconn.PgError{Code: "40001"}, true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"wrapped serialization failure", fmt.Errorf("update: %w", &pgconn.PgError{Code: "40001"}), true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"plain error", errors.New("boom"), false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repository.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetry_RetriesSerializationFailure(t *testing.T) {
+	db, counter := openTxDB(t)
+
+	attempts := 0
+	err := repository.WithRetry(context.Background(), db, 3, func(tx *sql.Tx) error {
+		attempts++
+		if attempts <= 2 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("WithRetry() error = %v, want nil", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if got := counter.commits.Load(); got != 1 {
+		t.Errorf("commits = %d, want 1", got)
+	}
+}
+
+func TestWithRetry_NonRetryableReturnsImmediately(t *testing.T) {
+	db, counter := openTxDB(t)
+
+	wantErr := &pgconn.PgError{Code: "23505"}
+	attempts := 0
+	err := repository.WithRetry(context.Background(), db, 3, func(tx *sql.Tx) error {
+		attempts++
+		return wantErr
+	})
+
+	if !errors.Is(err, wantErr) {
+		t.Errorf("WithRetry() error = %v, want %v", err, wantErr)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+	if got := counter.commits.Load(); got != 0 {
+		t.Errorf("commits = %d, want 0", got)
+	}
+}
+
+func TestWithRetry_ExhaustsAttempts(t *testing.T) {
+	db, _ := openTxDB(t)
+
+	attempts := 0
+	err := repository.WithRetry(context.Background(), db, 2, func(tx *sql.Tx) error {
+		attempts++
+		return &pgconn.PgError{Code: "40P01"}
+	})
+
+	if !repository.IsRetryable(err) {
+		t.Errorf("WithRetry() error = %v, want retryable error", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestWithRetry_StopsOnContextCancel(t *testing.T) {
+	db, _ := openTxDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	err := repository.WithRetry(ctx, db, 5, func(tx *sql.Tx) error {
+		attempts++
+		cancel()
+		return &pgconn.PgError{Code: "40001"}
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WithRetry() error = %v, want context.Canceled", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
@@ -0,0 +1,113 @@
+package pkg_routes_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/handlers"
+	"github.com/JaimeStill/agent-lab/pkg/routes"
+)
+
+func newFallbackMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /agents", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("POST /agents", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	return mux
+}
+
+func decodeErrorResponse(t *testing.T, rec *httptest.ResponseRecorder) handlers.ErrorResponse {
+	t.Helper()
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var body handlers.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body %q: %v", rec.Body.String(), err)
+	}
+	return body
+}
+
+func TestNew_UnknownPathReturnsJSON404(t *testing.T) {
+	h := routes.New(newFallbackMux(), routes.Options{})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/foo", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	body := decodeErrorResponse(t, rec)
+	if body.Error.Code != "not_found" || !strings.Contains(body.Error.Message, "/foo") {
+		t.Errorf("error = %+v, want not_found for /foo", body.Error)
+	}
+}
+
+func TestNew_WrongMethodReturnsJSON405(t *testing.T) {
+	h := routes.New(newFallbackMux(), routes.Options{})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/agents", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+
+	allow := rec.Header().Get("Allow")
+	for _, method := range []string{"GET", "POST"} {
+		if !strings.Contains(allow, method) {
+			t.Errorf("Allow = %q, want it to include %s", allow, method)
+		}
+	}
+
+	body := decodeErrorResponse(t, rec)
+	if body.Error.Code != "method_not_allowed" {
+		t.Errorf("error code = %q, want method_not_allowed", body.Error.Code)
+	}
+}
+
+func TestNew_MatchedRoutePassesThrough(t *testing.T) {
+	h := routes.New(newFallbackMux(), routes.Options{})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/agents", nil))
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+}
+
+func TestNew_CustomFallbacks(t *testing.T) {
+	h := routes.New(newFallbackMux(), routes.Options{
+		NotFound: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		},
+		MethodNotAllowed: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("NotFound status = %d, want %d", rec.Code, http.StatusTeapot)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/agents", nil))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("MethodNotAllowed status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if rec.Header().Get("Allow") == "" {
+		t.Error("Allow header not set before custom MethodNotAllowed")
+	}
+}
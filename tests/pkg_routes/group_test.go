@@ -1,6 +1,7 @@
 package pkg_routes_test
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -210,6 +211,7 @@ func TestGroup_AddToSpec_AllMethods(t *testing.T) {
 			{Method: "GET", Pattern: "", Handler: func(w http.ResponseWriter, r *http.Request) {}, OpenAPI: &openapi.Operation{Summary: "Get"}},
 			{Method: "POST", Pattern: "", Handler: func(w http.ResponseWriter, r *http.Request) {}, OpenAPI: &openapi.Operation{Summary: "Create"}},
 			{Method: "PUT", Pattern: "", Handler: func(w http.ResponseWriter, r *http.Request) {}, OpenAPI: &openapi.Operation{Summary: "Update"}},
+			{Method: "PATCH", Pattern: "", Handler: func(w http.ResponseWriter, r *http.Request) {}, OpenAPI: &openapi.Operation{Summary: "Patch"}},
 			{Method: "DELETE", Pattern: "", Handler: func(w http.ResponseWriter, r *http.Request) {}, OpenAPI: &openapi.Operation{Summary: "Delete"}},
 		},
 	}
@@ -233,6 +235,10 @@ func TestGroup_AddToSpec_AllMethods(t *testing.T) {
 		t.Error("PUT operation incorrect")
 	}
 
+	if pathItem.Patch == nil || pathItem.Patch.Summary != "Patch" {
+		t.Error("PATCH operation incorrect")
+	}
+
 	if pathItem.Delete == nil || pathItem.Delete.Summary != "Delete" {
 		t.Error("DELETE operation incorrect")
 	}
@@ -432,3 +438,97 @@ func TestRegister_NestedChildren(t *testing.T) {
 		t.Error("nested spec path not added")
 	}
 }
+
+func TestGroup_AddToSpec_TagDescriptions(t *testing.T) {
+	spec := openapi.NewSpec("Test API", "1.0.0")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+
+	groups := []routes.Group{
+		{
+			Prefix:      "/agents",
+			Tags:        []string{"Agents"},
+			Description: "Agent configuration and execution",
+			ExternalDocs: &openapi.ExternalDocs{
+				URL: "https://example.com/docs/agents",
+			},
+			Routes: []routes.Route{
+				{Method: "GET", Pattern: "", Handler: handler, OpenAPI: &openapi.Operation{Summary: "List agents"}},
+			},
+		},
+		{
+			Prefix:      "/workflows",
+			Tags:        []string{"Workflows"},
+			Description: "Workflow execution and management",
+			Routes: []routes.Route{
+				{Method: "GET", Pattern: "", Handler: handler, OpenAPI: &openapi.Operation{Summary: "List workflows"}},
+			},
+			Children: []routes.Group{
+				{
+					Prefix:      "/runs",
+					Tags:        []string{"Runs"},
+					Description: "Workflow run inspection and control",
+					Routes: []routes.Route{
+						{Method: "GET", Pattern: "", Handler: handler, OpenAPI: &openapi.Operation{Summary: "List runs"}},
+					},
+				},
+			},
+		},
+		{
+			Prefix: "/agents/usage",
+			Tags:   []string{"Agents"},
+			Routes: []routes.Route{
+				{Method: "GET", Pattern: "", Handler: handler, OpenAPI: &openapi.Operation{Summary: "Usage"}},
+			},
+		},
+	}
+
+	for _, g := range groups {
+		g.AddToSpec("/api", spec)
+	}
+
+	data, err := openapi.MarshalJSON(spec)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	var result struct {
+		Tags []struct {
+			Name         string `json:"name"`
+			Description  string `json:"description"`
+			ExternalDocs *struct {
+				URL string `json:"url"`
+			} `json:"externalDocs"`
+		} `json:"tags"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("spec is not valid JSON: %v", err)
+	}
+
+	want := []struct {
+		name, description, url string
+	}{
+		{"Agents", "Agent configuration and execution", "https://example.com/docs/agents"},
+		{"Workflows", "Workflow execution and management", ""},
+		{"Runs", "Workflow run inspection and control", ""},
+	}
+
+	if len(result.Tags) != len(want) {
+		t.Fatalf("tags = %+v, want %d entries", result.Tags, len(want))
+	}
+
+	for i, w := range want {
+		got := result.Tags[i]
+		if got.Name != w.name || got.Description != w.description {
+			t.Errorf("tags[%d] = {%q, %q}, want {%q, %q}", i, got.Name, got.Description, w.name, w.description)
+		}
+
+		url := ""
+		if got.ExternalDocs != nil {
+			url = got.ExternalDocs.URL
+		}
+		if url != w.url {
+			t.Errorf("tags[%d].externalDocs.url = %q, want %q", i, url, w.url)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package pkg_signedurl_test
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/signedurl"
+)
+
+func newSigner(t *testing.T) *signedurl.Signer {
+	t.Helper()
+
+	s, err := signedurl.New(bytes.Repeat([]byte("k"), signedurl.KeySize), "/api")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return s
+}
+
+// parse splits a signed URL into its path relative to the base path and its query.
+func parse(t *testing.T, signed string) (string, url.Values) {
+	t.Helper()
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", signed, err)
+	}
+	return strings.TrimPrefix(u.Path, "/api"), u.Query()
+}
+
+func TestSigner_Valid(t *testing.T) {
+	s := newSigner(t)
+
+	signed, expires := s.Sign("/images/abc/data", time.Hour)
+	if !strings.HasPrefix(signed, "/api/images/abc/data?") {
+		t.Errorf("Sign() = %q, want /api/images/abc/data prefix", signed)
+	}
+	if time.Until(expires) <= 0 {
+		t.Errorf("expires = %v, want future", expires)
+	}
+
+	path, query := parse(t, signed)
+	if err := s.Verify(path, query); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestSigner_Expired(t *testing.T) {
+	s := newSigner(t)
+
+	path, query := parse(t, mustSign(s, "/images/abc/data", -time.Minute))
+	if err := s.Verify(path, query); !errors.Is(err, signedurl.ErrExpired) {
+		t.Errorf("Verify() error = %v, want ErrExpired", err)
+	}
+}
+
+func TestSigner_Tampered(t *testing.T) {
+	s := newSigner(t)
+	path, query := parse(t, mustSign(s, "/images/abc/data", time.Hour))
+
+	tests := []struct {
+		name  string
+		path  string
+		query func() url.Values
+	}{
+		{"path", "/images/xyz/data", func() url.Values { return query }},
+		{"expiry", path, func() url.Values {
+			q := cloneValues(query)
+			q.Set(signedurl.ExpiresParam, "9999999999")
+			return q
+		}},
+		{"signature", path, func() url.Values {
+			q := cloneValues(query)
+			q.Set(signedurl.SignatureParam, "AAAA"+q.Get(signedurl.SignatureParam)[4:])
+			return q
+		}},
+		{"missing expiry", path, func() url.Values {
+			q := cloneValues(query)
+			q.Del(signedurl.ExpiresParam)
+			return q
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.Verify(tt.path, tt.query()); !errors.Is(err, signedurl.ErrInvalidSignature) {
+				t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestSigner_DifferentKey(t *testing.T) {
+	path, query := parse(t, mustSign(newSigner(t), "/images/abc/data", time.Hour))
+
+	other, err := signedurl.New(bytes.Repeat([]byte("o"), signedurl.KeySize), "/api")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := other.Verify(path, query); !errors.Is(err, signedurl.ErrInvalidSignature) {
+		t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestNew_ShortKey(t *testing.T) {
+	if _, err := signedurl.New([]byte("short"), "/api"); err == nil {
+		t.Error("New() with short key succeeded, want error")
+	}
+}
+
+func mustSign(s *signedurl.Signer, path string, ttl time.Duration) string {
+	signed, _ := s.Sign(path, ttl)
+	return signed
+}
+
+func cloneValues(v url.Values) url.Values {
+	c := url.Values{}
+	for k, vals := range v {
+		c[k] = append([]string(nil), vals...)
+	}
+	return c
+}
@@ -0,0 +1,214 @@
+package pkg_storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+func compressedStorage(t *testing.T, dir string, compress bool) storage.System {
+	t.Helper()
+
+	cfg := &storage.Config{BasePath: dir, CompressStoredBlobs: compress}
+	if err := cfg.Finalize(nil); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+
+	sys, err := storage.New(cfg, testLogger())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	lc := lifecycle.New()
+	if err := sys.Start(lc); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	lc.WaitForStartup()
+
+	return sys
+}
+
+// whitespacePage encodes a mostly blank page, like a rendered document page.
+func whitespacePage(t *testing.T, encode func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+
+	img := image.NewGray(image.Rect(0, 0, 400, 400))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for x := 50; x < 350; x++ {
+		img.Set(x, 200, color.Black)
+	}
+
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatalf("encode image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func pngPage(t *testing.T) []byte {
+	return whitespacePage(t, func(buf *bytes.Buffer, img image.Image) error {
+		return png.Encode(buf, img)
+	})
+}
+
+func jpegPage(t *testing.T) []byte {
+	return whitespacePage(t, func(buf *bytes.Buffer, img image.Image) error {
+		return jpeg.Encode(buf, img, nil)
+	})
+}
+
+func TestCompression_RoundTrip(t *testing.T) {
+	dir := tempStorageDir(t)
+	sys := compressedStorage(t, dir, true)
+	ctx := context.Background()
+
+	data := pngPage(t)
+	if err := sys.Store(ctx, "images/doc/page.png", data); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "images/doc/page.png.gz")); err != nil {
+		t.Fatalf("expected compressed file on disk: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "images/doc/page.png")); !os.IsNotExist(err) {
+		t.Errorf("expected no uncompressed file on disk, got %v", err)
+	}
+
+	got, err := sys.Retrieve(ctx, "images/doc/page.png")
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Retrieve() returned %d bytes differing from the %d stored", len(got), len(data))
+	}
+
+	if ok, err := sys.Validate(ctx, "images/doc/page.png"); err != nil || !ok {
+		t.Errorf("Validate() = %v, %v, want true, nil", ok, err)
+	}
+
+	keys, err := sys.List(ctx, "images/")
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if !slices.Equal(keys, []string{"images/doc/page.png"}) {
+		t.Errorf("List() = %v, want the original key", keys)
+	}
+
+	if _, err := sys.Path(ctx, "images/doc/page.png"); !errors.Is(err, storage.ErrCompressed) {
+		t.Errorf("Path() error = %v, want ErrCompressed", err)
+	}
+}
+
+func TestCompression_SkipsJPEG(t *testing.T) {
+	dir := tempStorageDir(t)
+	sys := compressedStorage(t, dir, true)
+	ctx := context.Background()
+
+	data := jpegPage(t)
+	if err := sys.Store(ctx, "images/doc/page.jpg", data); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	onDisk, err := os.ReadFile(filepath.Join(dir, "images/doc/page.jpg"))
+	if err != nil {
+		t.Fatalf("expected uncompressed file on disk: %v", err)
+	}
+	if !bytes.Equal(onDisk, data) {
+		t.Error("JPEG should be stored byte-for-byte")
+	}
+
+	got, err := sys.Retrieve(ctx, "images/doc/page.jpg")
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("Retrieve() should return the stored JPEG unchanged")
+	}
+}
+
+func TestCompression_TransferAndDelete(t *testing.T) {
+	dir := tempStorageDir(t)
+	sys := compressedStorage(t, dir, true)
+	ctx := context.Background()
+
+	data := pngPage(t)
+	sys.Store(ctx, "a/page.png", data)
+
+	if err := sys.Copy(ctx, "a/page.png", "b/page.png"); err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+	if err := sys.Move(ctx, "b/page.png", "c/page.png"); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+
+	for _, key := range []string{"a/page.png", "c/page.png"} {
+		got, err := sys.Retrieve(ctx, key)
+		if err != nil {
+			t.Fatalf("Retrieve(%q) failed: %v", key, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("Retrieve(%q) differs from stored bytes", key)
+		}
+	}
+
+	if err := sys.Delete(ctx, "a/page.png"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if ok, _ := sys.Validate(ctx, "a/page.png"); ok {
+		t.Error("Validate() = true after Delete()")
+	}
+}
+
+func TestCompression_ToggledOff(t *testing.T) {
+	dir := tempStorageDir(t)
+	ctx := context.Background()
+	data := pngPage(t)
+
+	if err := compressedStorage(t, dir, true).Store(ctx, "page.png", data); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	sys := compressedStorage(t, dir, false)
+	got, err := sys.Retrieve(ctx, "page.png")
+	if err != nil {
+		t.Fatalf("Retrieve() after disabling compression failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("previously compressed blob should still read back unchanged")
+	}
+
+	if err := sys.Store(ctx, "page.png", data); err != nil {
+		t.Fatalf("Store() overwrite failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "page.png.gz")); !os.IsNotExist(err) {
+		t.Errorf("overwrite should remove the stale compressed file, got %v", err)
+	}
+	if _, err := sys.Path(ctx, "page.png"); err != nil {
+		t.Errorf("Path() failed: %v", err)
+	}
+}
+
+func TestConfig_CompressStoredBlobsEnv(t *testing.T) {
+	t.Setenv("TEST_STORAGE_COMPRESS", "true")
+
+	cfg := &storage.Config{}
+	if err := cfg.Finalize(&storage.Env{CompressStoredBlobs: "TEST_STORAGE_COMPRESS"}); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+	if !cfg.CompressStoredBlobs {
+		t.Error("CompressStoredBlobs = false, want true from env")
+	}
+}
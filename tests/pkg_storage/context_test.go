@@ -0,0 +1,150 @@
+package pkg_storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+func startedStorage(t *testing.T) (storage.System, string) {
+	t.Helper()
+
+	dir := tempStorageDir(t)
+	sys, err := storage.New(&storage.Config{BasePath: dir}, testLogger())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	lc := lifecycle.New()
+	sys.Start(lc)
+	lc.WaitForStartup()
+
+	return sys, dir
+}
+
+// cancelReader yields data in small reads and cancels its context after the
+// first read, simulating a caller that aborts a slow write.
+type cancelReader struct {
+	cancel context.CancelFunc
+	reads  int
+}
+
+func (r *cancelReader) Read(p []byte) (int, error) {
+	r.reads++
+	if r.reads == 1 {
+		r.cancel()
+	}
+	n := min(len(p), 1024)
+	for i := range n {
+		p[i] = 'x'
+	}
+	return n, nil
+}
+
+func TestStore_CancelledContext(t *testing.T) {
+	sys, dir := startedStorage(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := sys.Store(ctx, "test/file.txt", []byte("data"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Store() error = %v, want context.Canceled", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "test")); !os.IsNotExist(err) {
+		t.Errorf("Store() with cancelled context created directory, stat err = %v", err)
+	}
+}
+
+func TestRetrieve_CancelledContext(t *testing.T) {
+	sys, _ := startedStorage(t)
+
+	if err := sys.Store(context.Background(), "test/file.txt", []byte("data")); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := sys.Retrieve(ctx, "test/file.txt")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Retrieve() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestStoreStream_RoundTrip(t *testing.T) {
+	sys, _ := startedStorage(t)
+	ctx := context.Background()
+
+	data := bytes.Repeat([]byte("abcdefgh"), 100_000)
+
+	if err := sys.StoreStream(ctx, "stream/large.bin", bytes.NewReader(data)); err != nil {
+		t.Fatalf("StoreStream() failed: %v", err)
+	}
+
+	retrieved, err := sys.Retrieve(ctx, "stream/large.bin")
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+
+	if !bytes.Equal(retrieved, data) {
+		t.Errorf("Retrieve() returned %d bytes, want %d", len(retrieved), len(data))
+	}
+}
+
+func TestStoreStream_CancelMidWriteRemovesTempFile(t *testing.T) {
+	sys, dir := startedStorage(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := &cancelReader{cancel: cancel}
+
+	err := sys.StoreStream(ctx, "stream/file.bin", r)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("StoreStream() error = %v, want context.Canceled", err)
+	}
+
+	if r.reads == 0 {
+		t.Fatal("StoreStream() did not read from source before cancelling")
+	}
+
+	path := filepath.Join(dir, "stream", "file.bin")
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind after cancellation, stat err = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("destination file created after cancellation, stat err = %v", err)
+	}
+}
+
+func TestStoreStream_CancelPreservesExisting(t *testing.T) {
+	sys, _ := startedStorage(t)
+
+	original := []byte("original")
+	if err := sys.Store(context.Background(), "stream/file.bin", original); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := sys.StoreStream(ctx, "stream/file.bin", &cancelReader{cancel: cancel}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("StoreStream() error = %v, want context.Canceled", err)
+	}
+
+	retrieved, err := sys.Retrieve(context.Background(), "stream/file.bin")
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	if !bytes.Equal(retrieved, original) {
+		t.Errorf("Retrieve() = %q, want %q", retrieved, original)
+	}
+}
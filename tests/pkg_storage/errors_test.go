@@ -16,6 +16,8 @@ func TestErrors_Defined(t *testing.T) {
 		{"ErrNotFound", storage.ErrNotFound, "storage: key not found"},
 		{"ErrPermissionDenied", storage.ErrPermissionDenied, "storage: permission denied"},
 		{"ErrInvalidKey", storage.ErrInvalidKey, "storage: invalid key"},
+		{"ErrQuotaExceeded", storage.ErrQuotaExceeded, "storage: quota exceeded"},
+		{"ErrCompressed", storage.ErrCompressed, "storage: key stored compressed"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,111 @@
+package pkg_storage_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+func seedKeys(t *testing.T, sys storage.System, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if err := sys.Store(context.Background(), key, []byte(key)); err != nil {
+			t.Fatalf("Store(%q) failed: %v", key, err)
+		}
+	}
+}
+
+func TestList_Prefix(t *testing.T) {
+	sys, _ := startedStorage(t)
+	seedKeys(t, sys,
+		"documents/d1/report.pdf",
+		"images/doc-a/1.png",
+		"images/doc-a/2.png",
+		"images/doc-a/thumbs/1.png",
+		"images/doc-ab/1.png",
+		"images/doc-b/1.png",
+	)
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"images/doc-a/", []string{"images/doc-a/1.png", "images/doc-a/2.png", "images/doc-a/thumbs/1.png"}},
+		{"images/doc-a", []string{"images/doc-a/1.png", "images/doc-a/2.png", "images/doc-a/thumbs/1.png", "images/doc-ab/1.png"}},
+		{"images/doc-a/1", []string{"images/doc-a/1.png"}},
+		{"doc", []string{"documents/d1/report.pdf"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got, err := sys.List(context.Background(), tt.prefix)
+			if err != nil {
+				t.Fatalf("List() failed: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("List(%q) = %v, want %v", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestList_EmptyPrefixReturnsAll(t *testing.T) {
+	sys, dir := startedStorage(t)
+	seedKeys(t, sys, "b/2.txt", "a/1.txt", "c.txt")
+
+	if err := os.WriteFile(filepath.Join(dir, "a", "partial.txt.tmp"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := sys.List(context.Background(), "")
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+
+	want := []string{"a/1.txt", "b/2.txt", "c.txt"}
+	if !slices.Equal(got, want) {
+		t.Errorf("List(\"\") = %v, want %v", got, want)
+	}
+}
+
+func TestList_NonexistentPrefix(t *testing.T) {
+	sys, _ := startedStorage(t)
+	seedKeys(t, sys, "images/doc-a/1.png")
+
+	for _, prefix := range []string{"images/missing/", "missing/deep/path/", "zzz"} {
+		got, err := sys.List(context.Background(), prefix)
+		if err != nil {
+			t.Fatalf("List(%q) failed: %v", prefix, err)
+		}
+		if got == nil || len(got) != 0 {
+			t.Errorf("List(%q) = %#v, want empty slice", prefix, got)
+		}
+	}
+}
+
+func TestList_RejectsTraversal(t *testing.T) {
+	sys, _ := startedStorage(t)
+
+	for _, prefix := range []string{"../", "../etc/", "images/../../", "/etc/"} {
+		if _, err := sys.List(context.Background(), prefix); !errors.Is(err, storage.ErrInvalidKey) {
+			t.Errorf("List(%q) error = %v, want ErrInvalidKey", prefix, err)
+		}
+	}
+}
+
+func TestList_Cancelled(t *testing.T) {
+	sys, _ := startedStorage(t)
+	seedKeys(t, sys, "a/1.txt", "a/2.txt")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := sys.List(ctx, ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("List() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,314 @@
+package pkg_storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+func memoryStorage(t *testing.T, cfg *storage.Config) storage.System {
+	t.Helper()
+
+	cfg.Backend = storage.BackendMemory
+	if err := cfg.Finalize(nil); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+
+	sys, err := storage.New(cfg, testLogger())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	lc := lifecycle.New()
+	if err := sys.Start(lc); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	lc.WaitForStartup()
+
+	return sys
+}
+
+func TestMemory_New(t *testing.T) {
+	sys, err := storage.New(&storage.Config{Backend: storage.BackendMemory}, testLogger())
+	if err != nil {
+		t.Fatalf("New() without base path failed: %v", err)
+	}
+	if _, ok := sys.(storage.TempSweeper); ok {
+		t.Error("memory storage should not be a TempSweeper")
+	}
+
+	if _, err := storage.New(&storage.Config{Backend: "s3"}, testLogger()); err == nil {
+		t.Error("New() succeeded with unknown backend, want error")
+	}
+}
+
+func TestMemory_ConfigBackend(t *testing.T) {
+	t.Setenv("TEST_STORAGE_BACKEND", "memory")
+
+	cfg := &storage.Config{}
+	if err := cfg.Finalize(&storage.Env{Backend: "TEST_STORAGE_BACKEND"}); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+	if cfg.Backend != storage.BackendMemory {
+		t.Errorf("Backend = %q, want %q", cfg.Backend, storage.BackendMemory)
+	}
+
+	cfg = &storage.Config{}
+	if err := cfg.Finalize(nil); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+	if cfg.Backend != storage.BackendFilesystem {
+		t.Errorf("default Backend = %q, want %q", cfg.Backend, storage.BackendFilesystem)
+	}
+
+	cfg = &storage.Config{Backend: "tape"}
+	if err := cfg.Finalize(nil); err == nil {
+		t.Error("Finalize() accepted unknown backend")
+	}
+}
+
+func TestMemory_StoreRetrieve(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		key  string
+		data []byte
+	}{
+		{"round trip", "test/file.txt", []byte("hello world")},
+		{"nested key", "deeply/nested/path/file.txt", []byte("nested content")},
+		{"empty data", "empty.txt", []byte{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := sys.Store(ctx, tt.key, tt.data); err != nil {
+				t.Fatalf("Store() failed: %v", err)
+			}
+
+			got, err := sys.Retrieve(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Retrieve() failed: %v", err)
+			}
+			if !bytes.Equal(got, tt.data) {
+				t.Errorf("Retrieved data = %q, want %q", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestMemory_StoreCopiesData(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	data := []byte("original")
+	sys.Store(ctx, "copy.txt", data)
+	data[0] = 'X'
+
+	got, _ := sys.Retrieve(ctx, "copy.txt")
+	got[1] = 'X'
+
+	again, _ := sys.Retrieve(ctx, "copy.txt")
+	if string(again) != "original" {
+		t.Errorf("stored data = %q, want it isolated from callers", again)
+	}
+}
+
+func TestMemory_Overwrite(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	sys.Store(ctx, "overwrite.txt", []byte("original"))
+	sys.Store(ctx, "overwrite.txt", []byte("updated"))
+
+	data, _ := sys.Retrieve(ctx, "overwrite.txt")
+	if string(data) != "updated" {
+		t.Errorf("Retrieved = %q after overwrite, want %q", data, "updated")
+	}
+}
+
+func TestMemory_RetrieveNotFound(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+
+	_, err := sys.Retrieve(context.Background(), "nonexistent.txt")
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Retrieve() error = %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+func TestMemory_Delete(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	sys.Store(ctx, "shared/file1.txt", []byte("content1"))
+	sys.Store(ctx, "shared/file2.txt", []byte("content2"))
+
+	if err := sys.Delete(ctx, "shared/file1.txt"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if _, err := sys.Retrieve(ctx, "shared/file1.txt"); !errors.Is(err, storage.ErrNotFound) {
+		t.Error("key still exists after Delete()")
+	}
+	if _, err := sys.Retrieve(ctx, "shared/file2.txt"); err != nil {
+		t.Error("sibling key should still exist")
+	}
+
+	if err := sys.Delete(ctx, "nonexistent.txt"); err != nil {
+		t.Errorf("Delete() on non-existent key returned error: %v", err)
+	}
+}
+
+func TestMemory_DeleteLeavesNoParent(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	sys.Store(ctx, "documents/abc-123/file.pdf", []byte("pdf content"))
+	sys.Store(ctx, "documents/other.txt", []byte("other"))
+
+	if err := sys.Delete(ctx, "documents/abc-123/file.pdf"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+
+	keys, err := sys.List(ctx, "documents/")
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if !slices.Equal(keys, []string{"documents/other.txt"}) {
+		t.Errorf("List() = %v, want only the remaining key", keys)
+	}
+}
+
+func TestMemory_Validate(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	sys.Store(ctx, "exists.txt", []byte("content"))
+
+	if exists, err := sys.Validate(ctx, "exists.txt"); err != nil || !exists {
+		t.Errorf("Validate(existing) = %v, %v, want true", exists, err)
+	}
+	if exists, err := sys.Validate(ctx, "nonexistent.txt"); err != nil || exists {
+		t.Errorf("Validate(missing) = %v, %v, want false", exists, err)
+	}
+}
+
+func TestMemory_InvalidKeys(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	for _, key := range []string{"", "../escape.txt", "foo/../../escape.txt", "/absolute/path.txt"} {
+		t.Run(key, func(t *testing.T) {
+			if err := sys.Store(ctx, key, []byte("malicious")); !errors.Is(err, storage.ErrInvalidKey) {
+				t.Errorf("Store(%q) error = %v, want %v", key, err, storage.ErrInvalidKey)
+			}
+			if _, err := sys.Retrieve(ctx, key); !errors.Is(err, storage.ErrInvalidKey) {
+				t.Errorf("Retrieve(%q) error = %v, want %v", key, err, storage.ErrInvalidKey)
+			}
+			if err := sys.Delete(ctx, key); !errors.Is(err, storage.ErrInvalidKey) {
+				t.Errorf("Delete(%q) error = %v, want %v", key, err, storage.ErrInvalidKey)
+			}
+			if exists, err := sys.Validate(ctx, key); !errors.Is(err, storage.ErrInvalidKey) || exists {
+				t.Errorf("Validate(%q) = %v, %v, want false, %v", key, exists, err, storage.ErrInvalidKey)
+			}
+		})
+	}
+
+	if _, err := sys.List(ctx, "../"); !errors.Is(err, storage.ErrInvalidKey) {
+		t.Errorf("List(\"../\") error = %v, want %v", err, storage.ErrInvalidKey)
+	}
+}
+
+func TestMemory_CleanedKeys(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	sys.Store(ctx, "a//b/./c.txt", []byte("data"))
+
+	if _, err := sys.Retrieve(ctx, "a/b/c.txt"); err != nil {
+		t.Errorf("Retrieve(cleaned key) failed: %v", err)
+	}
+}
+
+func TestMemory_CopyMove(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	sys.Store(ctx, "src.txt", []byte("payload"))
+
+	if err := sys.Copy(ctx, "src.txt", "copy/dst.txt"); err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+	if err := sys.Move(ctx, "src.txt", "moved/dst.txt"); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+
+	keys, _ := sys.List(ctx, "")
+	if want := []string{"copy/dst.txt", "moved/dst.txt"}; !slices.Equal(keys, want) {
+		t.Errorf("List() = %v, want %v", keys, want)
+	}
+
+	if err := sys.Copy(ctx, "missing.txt", "dst.txt"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Copy(missing) error = %v, want %v", err, storage.ErrNotFound)
+	}
+	if err := sys.Move(ctx, "missing.txt", "dst.txt"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Move(missing) error = %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+func TestMemory_Path(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+	ctx := context.Background()
+
+	sys.Store(ctx, "doc.pdf", []byte("%PDF"))
+
+	if _, err := sys.Path(ctx, "doc.pdf"); !errors.Is(err, storage.ErrNotOnDisk) {
+		t.Errorf("Path(existing) error = %v, want %v", err, storage.ErrNotOnDisk)
+	}
+	if _, err := sys.Path(ctx, "missing.pdf"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Path(missing) error = %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+func TestMemory_Quota(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{MaxTotalSize: "100B"})
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "a.bin", make([]byte, 60)); err != nil {
+		t.Fatalf("Store(a) failed: %v", err)
+	}
+	if err := sys.Store(ctx, "b.bin", make([]byte, 50)); !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Fatalf("Store(b) error = %v, want ErrQuotaExceeded", err)
+	}
+	if err := sys.Store(ctx, "a.bin", make([]byte, 100)); err != nil {
+		t.Errorf("overwrite within quota failed: %v", err)
+	}
+
+	sys.Delete(ctx, "a.bin")
+	if err := sys.Store(ctx, "b.bin", make([]byte, 50)); err != nil {
+		t.Errorf("Store(b) after Delete failed: %v", err)
+	}
+}
+
+func TestMemory_StoreStreamCancelled(t *testing.T) {
+	sys := memoryStorage(t, &storage.Config{})
+
+	sys.Store(context.Background(), "keep.txt", []byte("original"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := sys.StoreStream(ctx, "keep.txt", bytes.NewReader([]byte("replacement"))); !errors.Is(err, context.Canceled) {
+		t.Fatalf("StoreStream() error = %v, want context.Canceled", err)
+	}
+
+	data, _ := sys.Retrieve(context.Background(), "keep.txt")
+	if string(data) != "original" {
+		t.Errorf("Retrieved = %q after cancelled write, want %q", data, "original")
+	}
+}
@@ -0,0 +1,208 @@
+package pkg_storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+func quotaStorage(t *testing.T, dir, quota string) storage.System {
+	t.Helper()
+
+	cfg := &storage.Config{BasePath: dir, MaxTotalSize: quota}
+	if err := cfg.Finalize(nil); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+
+	sys, err := storage.New(cfg, testLogger())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	lc := lifecycle.New()
+	if err := sys.Start(lc); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	lc.WaitForStartup()
+
+	return sys
+}
+
+func TestConfig_MaxTotalSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"100", 100, false},
+		{"1MB", 1000000, false},
+		{"lots", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			cfg := &storage.Config{MaxTotalSize: tt.value}
+			err := cfg.Finalize(nil)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Finalize() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Finalize() failed: %v", err)
+			}
+			if got := cfg.MaxTotalBytes(); got != tt.want {
+				t.Errorf("MaxTotalBytes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuota_WritesUpToLimit(t *testing.T) {
+	sys := quotaStorage(t, tempStorageDir(t), "100")
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "a.bin", make([]byte, 60)); err != nil {
+		t.Fatalf("Store(a) failed: %v", err)
+	}
+	if err := sys.Store(ctx, "b.bin", make([]byte, 40)); err != nil {
+		t.Fatalf("Store(b) at quota failed: %v", err)
+	}
+
+	err := sys.Store(ctx, "c.bin", make([]byte, 1))
+	if !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Fatalf("Store(c) error = %v, want ErrQuotaExceeded", err)
+	}
+
+	if exists, _ := sys.Validate(ctx, "c.bin"); exists {
+		t.Error("rejected write left a file behind")
+	}
+}
+
+func TestQuota_DeleteFreesSpace(t *testing.T) {
+	sys := quotaStorage(t, tempStorageDir(t), "100")
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "docs/a.bin", make([]byte, 80)); err != nil {
+		t.Fatalf("Store(a) failed: %v", err)
+	}
+	if err := sys.Store(ctx, "docs/b.bin", make([]byte, 30)); !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Fatalf("Store(b) error = %v, want ErrQuotaExceeded", err)
+	}
+
+	if err := sys.Delete(ctx, "docs/a.bin"); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+
+	if err := sys.Store(ctx, "docs/b.bin", make([]byte, 30)); err != nil {
+		t.Errorf("Store(b) after delete failed: %v", err)
+	}
+}
+
+func TestQuota_OverwriteCountsReplacedBytes(t *testing.T) {
+	sys := quotaStorage(t, tempStorageDir(t), "100")
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "a.bin", make([]byte, 90)); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+	if err := sys.Store(ctx, "a.bin", make([]byte, 100)); err != nil {
+		t.Errorf("overwrite within quota failed: %v", err)
+	}
+	if err := sys.Store(ctx, "a.bin", make([]byte, 101)); !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Errorf("overwrite beyond quota error = %v, want ErrQuotaExceeded", err)
+	}
+
+	data, err := sys.Retrieve(ctx, "a.bin")
+	if err != nil || len(data) != 100 {
+		t.Errorf("Retrieve() = %d bytes, %v; want previous 100 bytes preserved", len(data), err)
+	}
+}
+
+func TestQuota_StreamStopsEarly(t *testing.T) {
+	sys := quotaStorage(t, tempStorageDir(t), "1KB")
+
+	err := sys.StoreStream(context.Background(), "big.bin", bytes.NewReader(make([]byte, 1<<20)))
+	if !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Fatalf("StoreStream() error = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestQuota_ExistingFilesCountedOnStart(t *testing.T) {
+	dir := tempStorageDir(t)
+	if err := os.MkdirAll(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docs", "old.bin"), make([]byte, 70), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "docs", "stale.bin.tmp"), make([]byte, 500), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sys := quotaStorage(t, dir, "100")
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "new.bin", make([]byte, 31)); !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Errorf("Store() error = %v, want ErrQuotaExceeded", err)
+	}
+	if err := sys.Store(ctx, "new.bin", make([]byte, 30)); err != nil {
+		t.Errorf("Store() within remaining quota failed: %v", err)
+	}
+}
+
+func TestQuota_ConcurrentWrites(t *testing.T) {
+	sys := quotaStorage(t, tempStorageDir(t), "1000")
+	ctx := context.Background()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		stored   int
+		rejected int
+	)
+
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := sys.Store(ctx, fmt.Sprintf("blob-%d.bin", i), make([]byte, 100))
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				stored++
+			case errors.Is(err, storage.ErrQuotaExceeded):
+				rejected++
+			default:
+				t.Errorf("Store() unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stored != 10 || rejected != 40 {
+		t.Errorf("stored = %d, rejected = %d, want 10 and 40", stored, rejected)
+	}
+}
+
+func TestQuota_ZeroIsUnlimited(t *testing.T) {
+	sys := quotaStorage(t, tempStorageDir(t), "0")
+
+	if err := sys.Store(context.Background(), "a.bin", make([]byte, 1<<20)); err != nil {
+		t.Errorf("Store() with no quota failed: %v", err)
+	}
+}
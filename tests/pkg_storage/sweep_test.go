@@ -0,0 +1,88 @@
+package pkg_storage_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+func writeAged(t *testing.T, path string, age time.Duration) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("partial"), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatalf("chtimes %s: %v", path, err)
+	}
+}
+
+func TestSweepTemp(t *testing.T) {
+	dir := tempStorageDir(t)
+	sys := compressedStorage(t, dir, false)
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "documents/a/doc.pdf", []byte("%PDF-1.4")); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	expired := []string{
+		filepath.Join(dir, "documents/b/doc.pdf.tmp"),
+		filepath.Join(dir, "images/c/page.png.gz.tmp"),
+	}
+	for _, p := range expired {
+		writeAged(t, p, 2*time.Hour)
+	}
+
+	fresh := filepath.Join(dir, "images/d/page.png.tmp")
+	writeAged(t, fresh, time.Minute)
+
+	oldBlob := filepath.Join(dir, "documents/e/doc.pdf")
+	writeAged(t, oldBlob, 48*time.Hour)
+
+	sweeper, ok := sys.(storage.TempSweeper)
+	if !ok {
+		t.Fatal("filesystem storage does not implement TempSweeper")
+	}
+
+	removed, err := sweeper.SweepTemp(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("SweepTemp() error = %v", err)
+	}
+	if removed != len(expired) {
+		t.Errorf("SweepTemp() removed = %d, want %d", removed, len(expired))
+	}
+
+	for _, p := range expired {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("expired temp file %s still exists", p)
+		}
+	}
+	for _, p := range []string{fresh, oldBlob, filepath.Join(dir, "documents/a/doc.pdf")} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("%s should be preserved: %v", p, err)
+		}
+	}
+}
+
+func TestSweepTemp_Cancelled(t *testing.T) {
+	dir := tempStorageDir(t)
+	sys := compressedStorage(t, dir, false)
+	writeAged(t, filepath.Join(dir, "a/blob.tmp"), 2*time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := sys.(storage.TempSweeper).SweepTemp(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("SweepTemp() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,177 @@
+package pkg_storage_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/storage"
+)
+
+func TestCopy_RoundTrip(t *testing.T) {
+	sys, _ := startedStorage(t)
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "docs/a.txt", []byte("original")); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	if err := sys.Copy(ctx, "docs/a.txt", "trash/2026/a.txt"); err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+
+	for _, key := range []string{"docs/a.txt", "trash/2026/a.txt"} {
+		data, err := sys.Retrieve(ctx, key)
+		if err != nil {
+			t.Fatalf("Retrieve(%q) failed: %v", key, err)
+		}
+		if string(data) != "original" {
+			t.Errorf("Retrieve(%q) = %q, want %q", key, data, "original")
+		}
+	}
+}
+
+func TestCopy_IndependentOfSource(t *testing.T) {
+	sys, _ := startedStorage(t)
+	ctx := context.Background()
+
+	sys.Store(ctx, "a.txt", []byte("v1"))
+	if err := sys.Copy(ctx, "a.txt", "b.txt"); err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+
+	if err := sys.Store(ctx, "a.txt", []byte("v2")); err != nil {
+		t.Fatalf("Store() overwrite failed: %v", err)
+	}
+
+	data, _ := sys.Retrieve(ctx, "b.txt")
+	if string(data) != "v1" {
+		t.Errorf("copy changed after source overwrite: got %q, want %q", data, "v1")
+	}
+}
+
+func TestCopy_OverwritesDestination(t *testing.T) {
+	sys, _ := startedStorage(t)
+	ctx := context.Background()
+
+	sys.Store(ctx, "a.txt", []byte("new"))
+	sys.Store(ctx, "b.txt", []byte("old"))
+
+	if err := sys.Copy(ctx, "a.txt", "b.txt"); err != nil {
+		t.Fatalf("Copy() failed: %v", err)
+	}
+
+	data, _ := sys.Retrieve(ctx, "b.txt")
+	if string(data) != "new" {
+		t.Errorf("Retrieve() = %q, want %q", data, "new")
+	}
+}
+
+func TestMove_RoundTrip(t *testing.T) {
+	sys, dir := startedStorage(t)
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "docs/nested/a.txt", []byte("moving")); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+
+	if err := sys.Move(ctx, "docs/nested/a.txt", "trash/a.txt"); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+
+	if exists, _ := sys.Validate(ctx, "docs/nested/a.txt"); exists {
+		t.Error("source still exists after Move()")
+	}
+
+	data, err := sys.Retrieve(ctx, "trash/a.txt")
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	if string(data) != "moving" {
+		t.Errorf("Retrieve() = %q, want %q", data, "moving")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "docs", "nested")); !os.IsNotExist(err) {
+		t.Error("Move() did not clean up empty source directory")
+	}
+}
+
+func TestMove_PreservesNonEmptySourceDirectory(t *testing.T) {
+	sys, dir := startedStorage(t)
+	ctx := context.Background()
+
+	sys.Store(ctx, "docs/a.txt", []byte("a"))
+	sys.Store(ctx, "docs/b.txt", []byte("b"))
+
+	if err := sys.Move(ctx, "docs/a.txt", "archive/a.txt"); err != nil {
+		t.Fatalf("Move() failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "docs")); err != nil {
+		t.Errorf("Move() removed non-empty directory: %v", err)
+	}
+}
+
+func TestTransfer_MissingSource(t *testing.T) {
+	sys, _ := startedStorage(t)
+	ctx := context.Background()
+
+	if err := sys.Copy(ctx, "missing.txt", "b.txt"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Copy() error = %v, want ErrNotFound", err)
+	}
+	if err := sys.Move(ctx, "missing.txt", "b.txt"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Move() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestTransfer_InvalidKeys(t *testing.T) {
+	sys, _ := startedStorage(t)
+	ctx := context.Background()
+	sys.Store(ctx, "a.txt", []byte("a"))
+
+	tests := []struct {
+		name     string
+		src, dst string
+	}{
+		{"empty source", "", "b.txt"},
+		{"empty destination", "a.txt", ""},
+		{"source traversal", "../etc/passwd", "b.txt"},
+		{"destination traversal", "a.txt", "../../escaped.txt"},
+		{"absolute destination", "a.txt", "/tmp/escaped.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := sys.Copy(ctx, tt.src, tt.dst); !errors.Is(err, storage.ErrInvalidKey) {
+				t.Errorf("Copy() error = %v, want ErrInvalidKey", err)
+			}
+			if err := sys.Move(ctx, tt.src, tt.dst); !errors.Is(err, storage.ErrInvalidKey) {
+				t.Errorf("Move() error = %v, want ErrInvalidKey", err)
+			}
+		})
+	}
+
+	if exists, _ := sys.Validate(ctx, "a.txt"); !exists {
+		t.Error("source removed by rejected Move()")
+	}
+}
+
+func TestCopy_CountsAgainstQuota(t *testing.T) {
+	sys := quotaStorage(t, tempStorageDir(t), "100")
+	ctx := context.Background()
+
+	if err := sys.Store(ctx, "a.bin", make([]byte, 60)); err != nil {
+		t.Fatalf("Store() failed: %v", err)
+	}
+	if err := sys.Copy(ctx, "a.bin", "b.bin"); !errors.Is(err, storage.ErrQuotaExceeded) {
+		t.Errorf("Copy() error = %v, want ErrQuotaExceeded", err)
+	}
+	if err := sys.Move(ctx, "a.bin", "b.bin"); err != nil {
+		t.Errorf("Move() within quota failed: %v", err)
+	}
+	if err := sys.Store(ctx, "c.bin", make([]byte, 40)); err != nil {
+		t.Errorf("Store() after Move() failed: %v", err)
+	}
+}
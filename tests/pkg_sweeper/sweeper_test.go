@@ -0,0 +1,159 @@
+package pkg_sweeper_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/pkg/lifecycle"
+	"github.com/JaimeStill/agent-lab/pkg/sweeper"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newSweeper(t *testing.T, interval string) *sweeper.Sweeper {
+	t.Helper()
+
+	cfg := &sweeper.Config{Interval: interval}
+	if err := cfg.Finalize(nil); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+	return sweeper.New(cfg, testLogger())
+}
+
+// expiringSet holds artifacts by expiry time; its sweep task removes those past now.
+type expiringSet struct {
+	items map[string]time.Time
+}
+
+func (s *expiringSet) sweep(ctx context.Context) (int, error) {
+	removed := 0
+	for name, expires := range s.items {
+		if time.Now().After(expires) {
+			delete(s.items, name)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func TestSweep_RemovesExpiredOnly(t *testing.T) {
+	now := time.Now()
+	uploads := &expiringSet{items: map[string]time.Time{
+		"upload-expired": now.Add(-time.Minute),
+		"upload-active":  now.Add(time.Hour),
+	}}
+	trash := &expiringSet{items: map[string]time.Time{
+		"doc-expired-1": now.Add(-48 * time.Hour),
+		"doc-expired-2": now.Add(-time.Second),
+		"doc-retained":  now.Add(24 * time.Hour),
+	}}
+
+	s := newSweeper(t, "10m")
+	s.Add("uploads", uploads.sweep)
+	s.Add("trash", trash.sweep)
+
+	counts := s.Sweep(context.Background())
+
+	if counts["uploads"] != 1 || counts["trash"] != 2 {
+		t.Errorf("Sweep() counts = %v, want uploads 1, trash 2", counts)
+	}
+	if _, ok := uploads.items["upload-active"]; !ok || len(uploads.items) != 1 {
+		t.Errorf("uploads = %v, want only upload-active", uploads.items)
+	}
+	if _, ok := trash.items["doc-retained"]; !ok || len(trash.items) != 1 {
+		t.Errorf("trash = %v, want only doc-retained", trash.items)
+	}
+}
+
+func TestSweep_FailingTaskDoesNotStopOthers(t *testing.T) {
+	s := newSweeper(t, "10m")
+	s.Add("broken", func(ctx context.Context) (int, error) {
+		return 0, errors.New("boom")
+	})
+	s.Add("healthy", func(ctx context.Context) (int, error) {
+		return 3, nil
+	})
+
+	if counts := s.Sweep(context.Background()); counts["healthy"] != 3 {
+		t.Errorf("Sweep() counts = %v, want healthy 3", counts)
+	}
+}
+
+func TestStart_RunsOnIntervalAndStopsOnShutdown(t *testing.T) {
+	var runs atomic.Int32
+	s := newSweeper(t, "10ms")
+	s.Add("count", func(ctx context.Context) (int, error) {
+		runs.Add(1)
+		return 0, nil
+	})
+
+	lc := lifecycle.New()
+	if err := s.Start(lc); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	if err := lc.WaitForStartup(); err != nil {
+		t.Fatalf("WaitForStartup() failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runs.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if runs.Load() < 2 {
+		t.Fatalf("runs = %d after 2s, want at least 2", runs.Load())
+	}
+
+	if err := lc.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	after := runs.Load()
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != after {
+		t.Errorf("sweeps continued after shutdown: %d -> %d", after, runs.Load())
+	}
+}
+
+func TestStart_ZeroIntervalDisables(t *testing.T) {
+	s := newSweeper(t, "0s")
+	s.Add("never", func(ctx context.Context) (int, error) {
+		t.Error("task ran with sweeper disabled")
+		return 0, nil
+	})
+
+	lc := lifecycle.New()
+	if err := s.Start(lc); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	if err := lc.WaitForStartup(); err != nil {
+		t.Fatalf("WaitForStartup() failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if err := lc.Shutdown(time.Second); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+}
+
+func TestConfig_Finalize(t *testing.T) {
+	cfg := &sweeper.Config{}
+	if err := cfg.Finalize(nil); err != nil {
+		t.Fatalf("Finalize() failed: %v", err)
+	}
+	if cfg.IntervalDuration() != 10*time.Minute || cfg.TempTTLDuration() != time.Hour {
+		t.Errorf("defaults = %s, %s, want 10m, 1h", cfg.Interval, cfg.TempTTL)
+	}
+
+	t.Setenv("TEST_SWEEPER_INTERVAL", "soon")
+	bad := &sweeper.Config{TempTTL: "0s"}
+	if err := bad.Finalize(&sweeper.Env{Interval: "TEST_SWEEPER_INTERVAL"}); err == nil {
+		t.Error("Finalize() error = nil, want invalid interval and temp_ttl")
+	}
+}
@@ -0,0 +1,115 @@
+package pkg_web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/pkg/web"
+)
+
+func TestNewAssets(t *testing.T) {
+	assets, err := web.NewAssets(staticFS)
+	if err != nil {
+		t.Fatalf("NewAssets() error = %v", err)
+	}
+
+	if _, ok := assets.Hash("testdata/static/app.js"); !ok {
+		t.Error("Hash() should know embedded files")
+	}
+
+	if _, ok := assets.Hash("testdata/static/missing.js"); ok {
+		t.Error("Hash() should not know missing files")
+	}
+}
+
+func TestAssetsPath(t *testing.T) {
+	assets, err := web.NewAssets(staticFS)
+	if err != nil {
+		t.Fatalf("NewAssets() error = %v", err)
+	}
+
+	hash, _ := assets.Hash("testdata/static/app.js")
+
+	tests := []struct {
+		name   string
+		assets *web.Assets
+		path   string
+		want   string
+	}{
+		{"known file", assets, "testdata/static/app.js", "testdata/static/app.js?v=" + hash},
+		{"unknown file", assets, "testdata/static/missing.js", "testdata/static/missing.js"},
+		{"nil assets", nil, "testdata/static/app.js", "testdata/static/app.js"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.assets.Path(tt.path); got != tt.want {
+				t.Errorf("Path() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssetsHandlerCacheControl(t *testing.T) {
+	assets, err := web.NewAssets(staticFS)
+	if err != nil {
+		t.Fatalf("NewAssets() error = %v", err)
+	}
+
+	handler := assets.Handler()
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantCache  string
+	}{
+		{"fingerprinted", "/" + assets.Path("testdata/static/app.js"), http.StatusOK, web.ImmutableCacheControl},
+		{"unversioned", "/testdata/static/app.js", http.StatusOK, web.DefaultCacheControl},
+		{"stale version", "/testdata/static/app.js?v=stale", http.StatusOK, web.DefaultCacheControl},
+		{"missing", "/testdata/static/missing.js", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if got := resp.Header.Get("Cache-Control"); got != tt.wantCache {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.wantCache)
+			}
+		})
+	}
+}
+
+func TestTemplateSetRendersAssetPath(t *testing.T) {
+	ts, err := web.NewTemplateSet(layoutFS, pageFS, "testdata/layouts/*.html", "testdata/pages", "/app", testPages)
+	if err != nil {
+		t.Fatalf("NewTemplateSet() error = %v", err)
+	}
+
+	assets, err := web.NewAssets(staticFS)
+	if err != nil {
+		t.Fatalf("NewAssets() error = %v", err)
+	}
+	ts.SetAssets(assets)
+
+	w := httptest.NewRecorder()
+	ts.PageHandler("test.html", testPages[0])(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := `src="` + assets.Path("testdata/static/app.js") + `"`
+	if !strings.Contains(w.Body.String(), want) {
+		t.Errorf("body should reference %s, got %q", want, w.Body.String())
+	}
+}
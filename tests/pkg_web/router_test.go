@@ -199,3 +199,52 @@ func TestRouterMultipleRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestShellFallback(t *testing.T) {
+	shell := func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("shell"))
+	}
+
+	r := web.NewRouter()
+	r.HandleFunc("GET /static/app.js", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("asset"))
+	})
+	r.SetFallback(web.ShellFallback(shell))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"deep client path", http.MethodGet, "/runs/123/stages", http.StatusOK, "shell"},
+		{"head client path", http.MethodHead, "/runs", http.StatusOK, ""},
+		{"known asset", http.MethodGet, "/static/app.js", http.StatusOK, "asset"},
+		{"missing asset", http.MethodGet, "/static/missing.css", http.StatusNotFound, ""},
+		{"non-GET method", http.MethodPost, "/runs", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if tt.wantBody != "" {
+				body, _ := io.ReadAll(resp.Body)
+				if string(body) != tt.wantBody {
+					t.Errorf("body = %q, want %q", string(body), tt.wantBody)
+				}
+			}
+		})
+	}
+}
@@ -91,7 +91,7 @@ func TestModuleServesComponents(t *testing.T) {
 	}
 }
 
-func TestModuleServes404(t *testing.T) {
+func TestModuleServesShellForClientPaths(t *testing.T) {
 	m, err := app.NewModule("/app")
 	if err != nil {
 		t.Fatalf("NewModule() error = %v", err)
@@ -99,7 +99,7 @@ func TestModuleServes404(t *testing.T) {
 
 	handler := m.Handler()
 
-	req := httptest.NewRequest(http.MethodGet, "/nonexistent-page", nil)
+	req := httptest.NewRequest(http.MethodGet, "/workflows/runs/123", nil)
 	w := httptest.NewRecorder()
 
 	handler.ServeHTTP(w, req)
@@ -107,15 +107,42 @@ func TestModuleServes404(t *testing.T) {
 	resp := w.Result()
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNotFound {
-		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
 	}
 
 	body, _ := io.ReadAll(resp.Body)
 	bodyStr := string(body)
 
 	if !strings.Contains(bodyStr, "<!DOCTYPE html>") {
-		t.Error("404 response should be HTML page")
+		t.Error("client path should be served the app shell")
+	}
+}
+
+func TestModuleMissingAssetNotFound(t *testing.T) {
+	m, err := app.NewModule("/app")
+	if err != nil {
+		t.Fatalf("NewModule() error = %v", err)
+	}
+
+	handler := m.Handler()
+
+	tests := []string{"/missing.png", "/assets/missing.js"}
+
+	for _, path := range tests {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+			}
+		})
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/JaimeStill/agent-lab/pkg/web"
 	"github.com/JaimeStill/agent-lab/web/scalar"
 )
 
@@ -109,3 +110,64 @@ func TestServeAssets(t *testing.T) {
 		})
 	}
 }
+
+func TestServeIndexFingerprintsAssets(t *testing.T) {
+	m := scalar.NewModule("/scalar")
+	handler := m.Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	for _, pattern := range []string{`src="scalar.js?v=`, `href="scalar.css?v=`} {
+		if !strings.Contains(body, pattern) {
+			t.Errorf("response body does not contain %q", pattern)
+		}
+	}
+}
+
+func TestServeFingerprintedAssetsImmutable(t *testing.T) {
+	m := scalar.NewModule("/scalar")
+	handler := m.Handler()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	_, rest, ok := strings.Cut(w.Body.String(), `src="`)
+	if !ok {
+		t.Fatal("response body does not reference a script")
+	}
+	src, _, _ := strings.Cut(rest, `"`)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantCache string
+	}{
+		{"fingerprinted", "/" + src, web.ImmutableCacheControl},
+		{"unversioned", "/scalar.js", web.DefaultCacheControl},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+			}
+
+			if got := resp.Header.Get("Cache-Control"); got != tc.wantCache {
+				t.Errorf("Cache-Control = %q, want %q", got, tc.wantCache)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package workflows_classify_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+	"github.com/google/uuid"
+)
+
+func classifyRun(t *testing.T, docID uuid.UUID, classification string, confidence float64, detections []classify.PageDetection) *workflows.Run {
+	t.Helper()
+
+	result := map[string]any{
+		"document":   map[string]any{"id": docID, "name": "brief.pdf"},
+		"detections": detections,
+		"classification": classify.ClassificationResult{
+			Classification: classification,
+		},
+		"confidence": classify.ConfidenceAssessment{
+			OverallScore:   confidence,
+			Recommendation: "ACCEPT",
+			Factors: []classify.ConfidenceFactor{
+				{Name: "marking_clarity", Score: confidence, Weight: 0.5},
+			},
+		},
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	return &workflows.Run{
+		ID:           uuid.New(),
+		WorkflowName: "classify-docs",
+		Status:       workflows.StatusCompleted,
+		Result:       data,
+	}
+}
+
+func detection(page int, clarity float64, markings ...string) classify.PageDetection {
+	d := classify.PageDetection{PageNumber: page, OriginalImageID: uuid.New(), ClarityScore: clarity}
+	for _, m := range markings {
+		d.MarkingsFound = append(d.MarkingsFound, classify.MarkingInfo{Text: m, Location: "header"})
+	}
+	return d
+}
+
+func TestCompare_Agreeing(t *testing.T) {
+	docID := uuid.New()
+	a := classifyRun(t, docID, "SECRET//NOFORN", 0.8, []classify.PageDetection{
+		detection(1, 0.9, "SECRET//NOFORN"),
+		detection(2, 0.7, "SECRET"),
+	})
+	b := classifyRun(t, docID, "secret//noforn", 0.9, []classify.PageDetection{
+		detection(1, 0.95, "secret//noforn"),
+		detection(2, 0.7, "SECRET", " SECRET "),
+	})
+
+	cmp, err := classify.Compare(a, b)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+
+	if !cmp.SameDocument {
+		t.Error("SameDocument = false, want true")
+	}
+	if !cmp.ClassificationAgrees {
+		t.Errorf("ClassificationAgrees = false for %q and %q", cmp.ClassificationA, cmp.ClassificationB)
+	}
+	if !cmp.MarkingsAgree || cmp.PagesDiffering != 0 {
+		t.Errorf("MarkingsAgree = %v, PagesDiffering = %d, want true, 0", cmp.MarkingsAgree, cmp.PagesDiffering)
+	}
+	if len(cmp.Pages) != 2 {
+		t.Fatalf("len(Pages) = %d, want 2", len(cmp.Pages))
+	}
+	if got := cmp.Pages[0].ClarityDelta; got < 0.049 || got > 0.051 {
+		t.Errorf("Pages[0].ClarityDelta = %v, want 0.05", got)
+	}
+	if cmp.Confidence.Delta == nil || *cmp.Confidence.Delta < 0.099 || *cmp.Confidence.Delta > 0.101 {
+		t.Errorf("Confidence.Delta = %v, want 0.1", cmp.Confidence.Delta)
+	}
+	if len(cmp.Confidence.Factors) != 1 || cmp.Confidence.Factors[0].Delta == nil {
+		t.Errorf("Confidence.Factors = %+v, want marking_clarity delta", cmp.Confidence.Factors)
+	}
+}
+
+func TestCompare_Differing(t *testing.T) {
+	a := classifyRun(t, uuid.New(), "SECRET", 0.9, []classify.PageDetection{
+		detection(1, 0.9, "SECRET"),
+		detection(2, 0.8, "SECRET"),
+	})
+	b := classifyRun(t, uuid.New(), "CONFIDENTIAL", 0.6, []classify.PageDetection{
+		detection(1, 0.9, "SECRET"),
+		detection(2, 0.5, "CONFIDENTIAL"),
+		detection(3, 0.4),
+	})
+
+	cmp, err := classify.Compare(a, b)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+
+	if cmp.SameDocument {
+		t.Error("SameDocument = true, want false")
+	}
+	if cmp.ClassificationAgrees {
+		t.Error("ClassificationAgrees = true, want false")
+	}
+	if cmp.MarkingsAgree || cmp.PagesDiffering != 2 {
+		t.Errorf("MarkingsAgree = %v, PagesDiffering = %d, want false, 2", cmp.MarkingsAgree, cmp.PagesDiffering)
+	}
+	if len(cmp.Pages) != 3 {
+		t.Fatalf("len(Pages) = %d, want 3", len(cmp.Pages))
+	}
+
+	if !cmp.Pages[0].Agrees {
+		t.Errorf("Pages[0] = %+v, want agreement", cmp.Pages[0])
+	}
+
+	page2 := cmp.Pages[1]
+	if page2.Agrees || len(page2.OnlyInA) != 1 || page2.OnlyInA[0] != "SECRET" ||
+		len(page2.OnlyInB) != 1 || page2.OnlyInB[0] != "CONFIDENTIAL" {
+		t.Errorf("Pages[1] = %+v, want SECRET only in A and CONFIDENTIAL only in B", page2)
+	}
+
+	page3 := cmp.Pages[2]
+	if page3.Agrees || len(page3.MarkingsA) != 0 {
+		t.Errorf("Pages[2] = %+v, want page missing from A to differ", page3)
+	}
+
+	if cmp.Confidence.Delta == nil || *cmp.Confidence.Delta > -0.299 || *cmp.Confidence.Delta < -0.301 {
+		t.Errorf("Confidence.Delta = %v, want -0.3", cmp.Confidence.Delta)
+	}
+}
+
+func TestCompare_RegisteredComparer(t *testing.T) {
+	docID := uuid.New()
+	pages := []classify.PageDetection{detection(1, 0.9, "SECRET")}
+
+	got, err := workflows.CompareRuns(
+		classifyRun(t, docID, "SECRET", 0.9, pages),
+		classifyRun(t, docID, "SECRET", 0.9, pages),
+	)
+	if err != nil {
+		t.Fatalf("CompareRuns() error = %v", err)
+	}
+
+	if _, ok := got.(*classify.Comparison); !ok {
+		t.Errorf("CompareRuns() type = %T, want *classify.Comparison", got)
+	}
+}
@@ -0,0 +1,179 @@
+package workflows_classify_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+	"github.com/google/uuid"
+)
+
+func TestClassifyOptions_Consensus(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		want  bool
+	}{
+		{"no agents", 0, false},
+		{"single agent", 1, false},
+		{"multiple agents", 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := classify.ClassifyOptions{}
+			for range tt.count {
+				opts.AgentIDs = append(opts.AgentIDs, uuid.New())
+			}
+			if got := opts.Consensus(); got != tt.want {
+				t.Errorf("Consensus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateClassifications_Empty(t *testing.T) {
+	result := classify.AggregateClassifications(nil)
+	if result.Classification != "" {
+		t.Errorf("Classification = %q, want empty", result.Classification)
+	}
+}
+
+func TestAggregateClassifications_SingleVote(t *testing.T) {
+	vote := classify.ClassificationResult{
+		Classification: "SECRET",
+		MarkingSummary: []string{"SECRET"},
+		Rationale:      "Header marking",
+		Confidence:     0.8,
+	}
+
+	result := classify.AggregateClassifications([]classify.ClassificationResult{vote})
+
+	if result.Classification != "SECRET" || result.Rationale != "Header marking" {
+		t.Errorf("AggregateClassifications() = %+v, want single vote unchanged", result)
+	}
+}
+
+func TestAggregateClassifications_Agreeing(t *testing.T) {
+	votes := []classify.ClassificationResult{
+		{Classification: "SECRET//NOFORN", MarkingSummary: []string{"SECRET", "NOFORN"}, Confidence: 0.9},
+		{Classification: "SECRET//NOFORN", MarkingSummary: []string{"SECRET//NOFORN"}, Confidence: 0.7},
+		{Classification: "SECRET//NOFORN", MarkingSummary: []string{"NOFORN"}, Confidence: 0.8},
+	}
+
+	result := classify.AggregateClassifications(votes)
+
+	if result.Classification != "SECRET//NOFORN" {
+		t.Errorf("Classification = %q, want %q", result.Classification, "SECRET//NOFORN")
+	}
+	if len(result.AlternativeReadings) != 0 {
+		t.Errorf("AlternativeReadings = %+v, want none", result.AlternativeReadings)
+	}
+	if math.Abs(result.Confidence-0.8) > 1e-9 {
+		t.Errorf("Confidence = %f, want 0.8", result.Confidence)
+	}
+
+	wantSummary := []string{"SECRET", "NOFORN", "SECRET//NOFORN"}
+	if !slices.Equal(result.MarkingSummary, wantSummary) {
+		t.Errorf("MarkingSummary = %v, want %v", result.MarkingSummary, wantSummary)
+	}
+}
+
+func TestAggregateClassifications_Disagreeing(t *testing.T) {
+	votes := []classify.ClassificationResult{
+		{Classification: "CONFIDENTIAL", MarkingSummary: []string{"CONFIDENTIAL"}, Confidence: 0.6},
+		{Classification: "SECRET", MarkingSummary: []string{"SECRET"}, Confidence: 0.9, Rationale: "Footer reads SECRET"},
+		{
+			Classification: "SECRET",
+			MarkingSummary: []string{"SECRET"},
+			Confidence:     0.9,
+			AlternativeReadings: []classify.AlternativeReading{
+				{Classification: "TOP SECRET", Probability: 0.1, Reason: "Smudged header"},
+				{Classification: "CONFIDENTIAL", Probability: 0.2, Reason: "Duplicate of vote"},
+			},
+		},
+	}
+
+	result := classify.AggregateClassifications(votes)
+
+	if result.Classification != "SECRET" {
+		t.Errorf("Classification = %q, want %q", result.Classification, "SECRET")
+	}
+	if math.Abs(result.Confidence-0.8) > 1e-9 {
+		t.Errorf("Confidence = %f, want 0.8", result.Confidence)
+	}
+
+	if len(result.AlternativeReadings) != 2 {
+		t.Fatalf("AlternativeReadings count = %d, want 2: %+v", len(result.AlternativeReadings), result.AlternativeReadings)
+	}
+
+	dissent := result.AlternativeReadings[0]
+	if dissent.Classification != "CONFIDENTIAL" {
+		t.Errorf("AlternativeReadings[0].Classification = %q, want %q", dissent.Classification, "CONFIDENTIAL")
+	}
+	if math.Abs(dissent.Probability-1.0/3.0) > 1e-9 {
+		t.Errorf("AlternativeReadings[0].Probability = %f, want 1/3", dissent.Probability)
+	}
+
+	if result.AlternativeReadings[1].Classification != "TOP SECRET" {
+		t.Errorf("AlternativeReadings[1].Classification = %q, want %q", result.AlternativeReadings[1].Classification, "TOP SECRET")
+	}
+
+	wantSummary := []string{"CONFIDENTIAL", "SECRET"}
+	if !slices.Equal(result.MarkingSummary, wantSummary) {
+		t.Errorf("MarkingSummary = %v, want %v", result.MarkingSummary, wantSummary)
+	}
+}
+
+func TestAggregateClassifications_TieBrokenByConfidence(t *testing.T) {
+	votes := []classify.ClassificationResult{
+		{Classification: "CONFIDENTIAL", Confidence: 0.5},
+		{Classification: "SECRET", Confidence: 0.9},
+	}
+
+	result := classify.AggregateClassifications(votes)
+
+	if result.Classification != "SECRET" {
+		t.Errorf("Classification = %q, want %q", result.Classification, "SECRET")
+	}
+	if len(result.AlternativeReadings) != 1 || result.AlternativeReadings[0].Probability != 0.5 {
+		t.Errorf("AlternativeReadings = %+v, want CONFIDENTIAL at 0.5", result.AlternativeReadings)
+	}
+}
+
+func TestAgentStages_ConsensusSatisfiesClassify(t *testing.T) {
+	stages, defaultProfile, ok := workflows.GetAgentStages("classify-docs")
+	if !ok {
+		t.Fatal("classify-docs should register its agent stages")
+	}
+
+	err := workflows.ValidateProfile(defaultProfile(), nil, stages)
+	if !errors.Is(err, workflows.ErrIncompleteProfile) {
+		t.Fatalf("ValidateProfile(default) error = %v, want ErrIncompleteProfile", err)
+	}
+	if !strings.HasSuffix(err.Error(), "detect, enhance, classify, score") {
+		t.Errorf("error = %q, want every agent stage listed", err)
+	}
+
+	agentID := uuid.New()
+	opts, _ := json.Marshal(classify.ClassifyOptions{AgentIDs: []uuid.UUID{uuid.New(), uuid.New()}})
+
+	profile := defaultProfile()
+	for i := range profile.Stages {
+		switch profile.Stages[i].StageName {
+		case "classify":
+			profile.Stages[i].Options = opts
+		case "detect", "enhance", "score":
+			profile.Stages[i].AgentID = &agentID
+		}
+	}
+
+	if err := workflows.ValidateProfile(profile, nil, stages); err != nil {
+		t.Errorf("ValidateProfile(consensus) error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,44 @@
+package workflows_classify_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	_ "github.com/JaimeStill/agent-lab/workflows/classify"
+)
+
+func TestDescribe_ClassifyDocs(t *testing.T) {
+	graph, err := workflows.Describe(context.Background(), "classify-docs", nil)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	wantNodes := []string{"init", "detect", "enhance", "classify", "score"}
+	if !slices.Equal(graph.Nodes, wantNodes) {
+		t.Errorf("Nodes = %v, want %v", graph.Nodes, wantNodes)
+	}
+
+	wantEdges := []workflows.GraphEdge{
+		{From: "init", To: "detect"},
+		{From: "detect", To: "enhance", Conditional: true, Label: "needs_enhancement", Reason: "markings below legibility threshold → enhance"},
+		{From: "detect", To: "classify", Conditional: true, Label: "markings_legible", Reason: "all markings legible → classify"},
+		{From: "enhance", To: "classify"},
+		{From: "classify", To: "score"},
+	}
+	if !slices.Equal(graph.Edges, wantEdges) {
+		t.Errorf("Edges = %+v, want %+v", graph.Edges, wantEdges)
+	}
+
+	if graph.EntryPoint != "init" {
+		t.Errorf("EntryPoint = %q, want init", graph.EntryPoint)
+	}
+	if !slices.Equal(graph.ExitPoints, []string{"score"}) {
+		t.Errorf("ExitPoints = %v, want [score]", graph.ExitPoints)
+	}
+
+	if graph.Params == nil || !slices.Contains(graph.Params.Required, "document_id") {
+		t.Errorf("Params = %+v, want document_id required", graph.Params)
+	}
+}
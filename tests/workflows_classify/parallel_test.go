@@ -0,0 +1,84 @@
+package workflows_classify_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+)
+
+func stageWithOptions(t *testing.T, opts any) *profiles.ProfileStage {
+	t.Helper()
+	data, err := json.Marshal(opts)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return &profiles.ProfileStage{StageName: "detect", Options: data}
+}
+
+func TestStageParallelConfig_Defaults(t *testing.T) {
+	for name, stage := range map[string]*profiles.ProfileStage{
+		"nil stage":     nil,
+		"empty options": {StageName: "detect"},
+		"unrelated":     {StageName: "enhance", Options: json.RawMessage(`{"legibility_threshold":0.4}`)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := classify.StageParallelConfig(stage)
+			if err != nil {
+				t.Fatalf("StageParallelConfig() error = %v", err)
+			}
+			if cfg.MaxWorkers != 0 {
+				t.Errorf("MaxWorkers = %d, want 0", cfg.MaxWorkers)
+			}
+			if cfg.Observer != "noop" {
+				t.Errorf("Observer = %q, want %q", cfg.Observer, "noop")
+			}
+			if !cfg.FailFast() {
+				t.Error("FailFast() = false, want true")
+			}
+		})
+	}
+}
+
+func TestStageParallelConfig_Configured(t *testing.T) {
+	stage := stageWithOptions(t, map[string]any{
+		"max_concurrency":      4,
+		"observer":             "slog",
+		"legibility_threshold": 0.3,
+	})
+
+	cfg, err := classify.StageParallelConfig(stage)
+	if err != nil {
+		t.Fatalf("StageParallelConfig() error = %v", err)
+	}
+
+	if cfg.MaxWorkers != 4 {
+		t.Errorf("MaxWorkers = %d, want 4", cfg.MaxWorkers)
+	}
+	if cfg.Observer != "slog" {
+		t.Errorf("Observer = %q, want %q", cfg.Observer, "slog")
+	}
+}
+
+func TestStageParallelConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts map[string]any
+	}{
+		{"negative concurrency", map[string]any{"max_concurrency": -1}},
+		{"excessive concurrency", map[string]any{"max_concurrency": classify.MaxStageConcurrency + 1}},
+		{"unknown observer", map[string]any{"observer": "does-not-exist"}},
+		{"wrong type", map[string]any{"max_concurrency": "four"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := classify.StageParallelConfig(stageWithOptions(t, tt.opts))
+			if !errors.Is(err, classify.ErrInvalidStageOptions) {
+				t.Errorf("StageParallelConfig() error = %v, want ErrInvalidStageOptions", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package workflows_classify_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+	wf "github.com/JaimeStill/go-agents-orchestration/pkg/workflows"
+)
+
+func TestPageProgress_CompletionOrder(t *testing.T) {
+	order := []int{3, 1, 4, 2}
+
+	// Each page waits until the page finishing before it has been reported,
+	// so pages complete in order regardless of scheduling.
+	reported := make(map[int]chan struct{}, len(order))
+	for _, p := range order {
+		reported[p] = make(chan struct{})
+	}
+	prev := make(map[int]int, len(order))
+	for i := 1; i < len(order); i++ {
+		prev[order[i]] = order[i-1]
+	}
+
+	var mu sync.Mutex
+	var events []workflows.ExecutionEvent
+	ctx := workflows.WithProgress(context.Background(), func(eventType workflows.ExecutionEventType, data map[string]any) {
+		mu.Lock()
+		events = append(events, workflows.ExecutionEvent{Type: eventType, Data: data})
+		mu.Unlock()
+		close(reported[data["page_number"].(int)])
+	})
+
+	pages := []classify.PageImage{{PageNumber: 1}, {PageNumber: 2}, {PageNumber: 3}, {PageNumber: 4}}
+
+	processor := func(ctx context.Context, img classify.PageImage) (classify.PageDetection, error) {
+		if p, ok := prev[img.PageNumber]; ok {
+			select {
+			case <-reported[p]:
+			case <-time.After(5 * time.Second):
+				t.Errorf("page %d timed out waiting for page %d", img.PageNumber, p)
+			}
+		}
+
+		markings := make([]classify.MarkingInfo, img.PageNumber)
+		markings[0].Faded = true
+		return classify.PageDetection{PageNumber: img.PageNumber, MarkingsFound: markings}, nil
+	}
+
+	cfg := classify.ParallelOptions{MaxConcurrency: len(pages)}.ParallelConfig()
+	if _, err := wf.ProcessParallel(ctx, cfg, pages, processor, classify.PageProgress(ctx)); err != nil {
+		t.Fatalf("ProcessParallel() error = %v", err)
+	}
+
+	if len(events) != len(pages) {
+		t.Fatalf("events = %d, want one per page (%d)", len(events), len(pages))
+	}
+
+	var got []int
+	for i, e := range events {
+		if e.Type != workflows.EventPageComplete {
+			t.Errorf("event %d type = %q, want %q", i, e.Type, workflows.EventPageComplete)
+		}
+		page := e.Data["page_number"].(int)
+		got = append(got, page)
+
+		if e.Data["markings_found"] != page {
+			t.Errorf("page %d markings_found = %v, want %d", page, e.Data["markings_found"], page)
+		}
+		if e.Data["faded_markings"] != 1 {
+			t.Errorf("page %d faded_markings = %v, want 1", page, e.Data["faded_markings"])
+		}
+		if e.Data["completed"] != i+1 || e.Data["total"] != len(pages) {
+			t.Errorf("event %d progress = %v/%v, want %d/%d", i, e.Data["completed"], e.Data["total"], i+1, len(pages))
+		}
+	}
+
+	if !slices.Equal(got, order) {
+		t.Errorf("page order = %v, want completion order %v", got, order)
+	}
+}
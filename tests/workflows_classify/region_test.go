@@ -0,0 +1,176 @@
+package workflows_classify_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+	"github.com/google/uuid"
+)
+
+func findMarking(markings []classify.MarkingInfo, location string) (classify.MarkingInfo, bool) {
+	for _, m := range markings {
+		if m.Location == location {
+			return m, true
+		}
+	}
+	return classify.MarkingInfo{}, false
+}
+
+func TestMergeRegionDetections(t *testing.T) {
+	originalImage, enhancedImage := uuid.New(), uuid.New()
+	threshold := classify.DefaultLegibilityThreshold
+
+	original := classify.PageDetection{
+		PageNumber:      2,
+		OriginalImageID: originalImage,
+		ClarityScore:    0.7,
+		MarkingsFound: []classify.MarkingInfo{
+			{Text: "SECRET", Location: "header", Legibility: 0.95},
+			{Text: "SECRET", Location: "footer", Legibility: 0.2, Faded: true},
+			{Text: "NOFORN", Location: "margin", Legibility: 0.3, Faded: true},
+		},
+	}
+
+	crops := []classify.RegionDetection{
+		{
+			Region: classify.MarkingRegion{Text: "SECRET", Location: "footer"},
+			Detection: classify.PageDetection{
+				ClarityScore: 0.9,
+				MarkingsFound: []classify.MarkingInfo{
+					{Text: "SECRET", Location: "body", Legibility: 0.88},
+					{Text: "SECRET", Location: "body", Legibility: 0.5},
+				},
+			},
+		},
+		{
+			Region: classify.MarkingRegion{Text: "NOFORN", Location: "margin"},
+			Detection: classify.PageDetection{
+				MarkingsFound: []classify.MarkingInfo{
+					{Text: "NOFORN", Location: "body", Legibility: 0.25},
+				},
+			},
+		},
+		{
+			Region:    classify.MarkingRegion{Text: "SECRET", Location: "header"},
+			Detection: classify.PageDetection{},
+		},
+	}
+
+	merged := classify.MergeRegionDetections(original, enhancedImage, crops, threshold)
+
+	if merged.OriginalImageID != originalImage {
+		t.Errorf("OriginalImageID = %v, want %v", merged.OriginalImageID, originalImage)
+	}
+	if merged.EnhancedImageID == nil || *merged.EnhancedImageID != enhancedImage {
+		t.Errorf("EnhancedImageID = %v, want %v", merged.EnhancedImageID, enhancedImage)
+	}
+	if merged.ClarityScore != 0.7 {
+		t.Errorf("ClarityScore = %v, want page clarity 0.7 preserved", merged.ClarityScore)
+	}
+	if len(merged.MarkingsFound) != 3 {
+		t.Fatalf("len(MarkingsFound) = %d, want 3: %+v", len(merged.MarkingsFound), merged.MarkingsFound)
+	}
+
+	tests := []struct {
+		location       string
+		wantLegibility float64
+		wantFaded      bool
+	}{
+		{"header", 0.95, false},
+		{"footer", 0.88, false},
+		{"margin", 0.3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			m, ok := findMarking(merged.MarkingsFound, tt.location)
+			if !ok {
+				t.Fatalf("no marking at %s", tt.location)
+			}
+			if m.Legibility != tt.wantLegibility {
+				t.Errorf("Legibility = %v, want %v", m.Legibility, tt.wantLegibility)
+			}
+			if m.Faded != tt.wantFaded {
+				t.Errorf("Faded = %v, want %v", m.Faded, tt.wantFaded)
+			}
+		})
+	}
+}
+
+func TestFilterSuggestion_Region(t *testing.T) {
+	fs := &classify.FilterSuggestion{
+		Regions: []classify.MarkingRegion{
+			{Text: "SECRET", Location: "footer", Y: 0.9, Width: 1, Height: 0.1},
+		},
+	}
+
+	if r, ok := fs.Region(classify.MarkingInfo{Text: "SECRET", Location: "footer"}); !ok || r.Y != 0.9 {
+		t.Errorf("Region(SECRET footer) = %+v, %v, want footer region", r, ok)
+	}
+	if _, ok := fs.Region(classify.MarkingInfo{Text: "SECRET", Location: "header"}); ok {
+		t.Error("Region(SECRET header) ok = true, want false")
+	}
+
+	var none *classify.FilterSuggestion
+	if _, ok := none.Region(classify.MarkingInfo{Text: "SECRET", Location: "footer"}); ok {
+		t.Error("nil Region() ok = true, want false")
+	}
+}
+
+func TestCropRegion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 200, 100))); err != nil {
+		t.Fatalf("encode page: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		region       classify.MarkingRegion
+		wantW, wantH int
+	}{
+		{"footer band", classify.MarkingRegion{Location: "footer", X: 0, Y: 0.9, Width: 1, Height: 0.1}, 200, 10},
+		{"corner", classify.MarkingRegion{Location: "margin", X: 0.5, Y: 0.5, Width: 0.25, Height: 0.2}, 50, 20},
+		{"clamped", classify.MarkingRegion{Location: "margin", X: 0.9, Y: 0.9, Width: 0.5, Height: 0.5}, 20, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cropped, err := classify.CropRegion(buf.Bytes(), tt.region)
+			if err != nil {
+				t.Fatalf("CropRegion() error = %v", err)
+			}
+
+			img, err := png.Decode(bytes.NewReader(cropped))
+			if err != nil {
+				t.Fatalf("decode crop: %v", err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Errorf("crop size = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+
+	if _, err := classify.CropRegion(buf.Bytes(), classify.MarkingRegion{Location: "body"}); err == nil {
+		t.Error("CropRegion(empty region) error = nil, want error")
+	}
+	if _, err := classify.CropRegion([]byte("not an image"), classify.MarkingRegion{Width: 1, Height: 1}); err == nil {
+		t.Error("CropRegion(invalid data) error = nil, want error")
+	}
+}
+
+func TestParseDetectionResponse_ClampsRegions(t *testing.T) {
+	content := `{"page_number": 1, "markings_found": [], "clarity_score": 0.5, "filter_suggestion": {"regions": [{"text": "SECRET", "location": "footer", "x": -0.2, "y": 0.8, "width": 1.5, "height": 0.4}]}}`
+
+	d, err := classify.ParseDetectionResponse(content)
+	if err != nil {
+		t.Fatalf("ParseDetectionResponse() error = %v", err)
+	}
+
+	r := d.FilterSuggestion.Regions[0]
+	if r.X != 0 || r.Y != 0.8 || r.Width != 1 || r.Height < 0.19 || r.Height > 0.21 {
+		t.Errorf("region = %+v, want clamped to the page", r)
+	}
+}
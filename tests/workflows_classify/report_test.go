@@ -0,0 +1,164 @@
+package workflows_classify_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+	"github.com/google/uuid"
+)
+
+func sampleClassifyRun(t *testing.T) *workflows.Run {
+	t.Helper()
+
+	enhancedID := uuid.New()
+	result := map[string]any{
+		"document": map[string]any{
+			"id":   uuid.New(),
+			"name": "quarterly-brief.pdf",
+		},
+		"enhancement_applied": true,
+		"detections": []classify.PageDetection{
+			{
+				PageNumber:      1,
+				OriginalImageID: uuid.New(),
+				ClarityScore:    0.92,
+				MarkingsFound: []classify.MarkingInfo{
+					{Text: "SECRET//NOFORN", Location: "header", Legibility: 0.95},
+				},
+			},
+			{
+				PageNumber:      2,
+				OriginalImageID: uuid.New(),
+				EnhancedImageID: &enhancedID,
+				ClarityScore:    0.41,
+				MarkingsFound: []classify.MarkingInfo{
+					{Text: "SECRET", Location: "footer", Legibility: 0.38, Faded: true},
+				},
+			},
+			{
+				PageNumber:      3,
+				OriginalImageID: uuid.New(),
+				ClarityScore:    0.88,
+			},
+		},
+		"classification": classify.ClassificationResult{
+			Classification: "SECRET//NOFORN",
+			MarkingSummary: []string{"SECRET//NOFORN", "SECRET"},
+			Rationale:      "Highest marking observed in page banners.",
+			AlternativeReadings: []classify.AlternativeReading{
+				{Classification: "SECRET", Probability: 0.2, Reason: "Footer | caveat faded"},
+			},
+		},
+		"confidence": classify.ConfidenceAssessment{
+			OverallScore:   0.87,
+			Recommendation: "ACCEPT",
+			Factors: []classify.ConfidenceFactor{
+				{Name: "marking_clarity", Score: 0.9, Weight: 0.3, Description: "Markings are clear"},
+			},
+		},
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	return &workflows.Run{
+		ID:           uuid.New(),
+		WorkflowName: "classify-docs",
+		Status:       workflows.StatusCompleted,
+		Result:       data,
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	run := sampleClassifyRun(t)
+
+	report, err := classify.BuildReport(run)
+	if err != nil {
+		t.Fatalf("BuildReport() error = %v", err)
+	}
+
+	if report.DocumentName != "quarterly-brief.pdf" {
+		t.Errorf("DocumentName = %q, want %q", report.DocumentName, "quarterly-brief.pdf")
+	}
+	if report.Classification != "SECRET//NOFORN" {
+		t.Errorf("Classification = %q, want %q", report.Classification, "SECRET//NOFORN")
+	}
+	if !report.EnhancementApplied {
+		t.Error("EnhancementApplied = false, want true")
+	}
+	if len(report.Pages) != 3 {
+		t.Errorf("len(Pages) = %d, want 3", len(report.Pages))
+	}
+	if report.Confidence == nil || report.Confidence.OverallScore != 0.87 {
+		t.Errorf("Confidence = %+v, want overall score 0.87", report.Confidence)
+	}
+}
+
+func TestBuildReport_NoResult(t *testing.T) {
+	run := &workflows.Run{ID: uuid.New(), WorkflowName: "classify-docs", Status: workflows.StatusRunning}
+
+	_, err := classify.BuildReport(run)
+	if !errors.Is(err, workflows.ErrReportUnavailable) {
+		t.Errorf("BuildReport() error = %v, want ErrReportUnavailable", err)
+	}
+}
+
+func TestReport_Markdown(t *testing.T) {
+	report, err := classify.BuildReport(sampleClassifyRun(t))
+	if err != nil {
+		t.Fatalf("BuildReport() error = %v", err)
+	}
+
+	md := report.Markdown()
+
+	sections := []string{
+		"# Classification Report: quarterly-brief.pdf",
+		"- **Enhancement Applied:** yes",
+		"## Classification",
+		"**SECRET//NOFORN**",
+		"### Marking Summary",
+		"### Alternative Readings",
+		"## Page Markings",
+		"| Page | Marking | Location | Legibility | Faded | Clarity | Enhanced |",
+		"| 1 | SECRET//NOFORN | header | 0.95 | no | 0.92 | no |",
+		"| 2 | SECRET | footer | 0.38 | yes | 0.41 | yes |",
+		"| 3 | _none detected_ |",
+		"## Confidence",
+		"- **Overall Score:** 0.87",
+		"- **Recommendation:** ACCEPT",
+		"| marking_clarity | 0.90 | 0.30 | Markings are clear |",
+		`Footer \| caveat faded`,
+	}
+
+	for _, want := range sections {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q\n%s", want, md)
+		}
+	}
+}
+
+func TestRenderReport_Classify(t *testing.T) {
+	run := sampleClassifyRun(t)
+
+	md, err := workflows.RenderReport(run, workflows.ReportMarkdown)
+	if err != nil {
+		t.Fatalf("RenderReport(md) error = %v", err)
+	}
+	if s, ok := md.(string); !ok || !strings.HasPrefix(s, "# Classification Report") {
+		t.Errorf("RenderReport(md) = %v, want classify Markdown", md)
+	}
+
+	structured, err := workflows.RenderReport(run, workflows.ReportJSON)
+	if err != nil {
+		t.Fatalf("RenderReport(json) error = %v", err)
+	}
+	if _, ok := structured.(*classify.Report); !ok {
+		t.Errorf("RenderReport(json) type = %T, want *classify.Report", structured)
+	}
+}
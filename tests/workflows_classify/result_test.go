@@ -0,0 +1,134 @@
+package workflows_classify_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+	"github.com/google/uuid"
+)
+
+// storedRun returns a completed classify run whose result is data encoded
+// the way the executor persists a run's final state.
+func storedRun(t *testing.T, data map[string]any) *workflows.Run {
+	t.Helper()
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	return &workflows.Run{
+		ID:           uuid.New(),
+		WorkflowName: classify.WorkflowName,
+		Status:       workflows.StatusCompleted,
+		Result:       encoded,
+	}
+}
+
+func TestResultFromRun_RoundTrip(t *testing.T) {
+	classification := classify.ClassificationResult{
+		Classification: "SECRET//NOFORN",
+		AlternativeReadings: []classify.AlternativeReading{
+			{Classification: "SECRET", Probability: 0.2, Reason: "caveat faded on page 2"},
+		},
+		MarkingSummary: []string{"SECRET//NOFORN", "SECRET"},
+		Rationale:      "Banner markings agree across pages",
+		Confidence:     0.91,
+	}
+	assessment := classify.ConfidenceAssessment{
+		OverallScore:   0.87,
+		Recommendation: "ACCEPT",
+		Factors: []classify.ConfidenceFactor{
+			{Name: "marking_clarity", Score: 0.9, Weight: 0.3, Description: "Markings are clear"},
+			{Name: "page_agreement", Score: 0.85, Weight: 0.7, Description: "Pages agree"},
+		},
+	}
+
+	run := storedRun(t, map[string]any{
+		"document_id":    uuid.New().String(),
+		"classification": classification,
+		"confidence":     assessment,
+		"detections":     []classify.PageDetection{detection(1, 0.9, "SECRET//NOFORN")},
+	})
+
+	gotClassification, gotAssessment, err := classify.ResultFromRun(run)
+	if err != nil {
+		t.Fatalf("ResultFromRun() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(*gotClassification, classification) {
+		t.Errorf("classification = %+v, want %+v", *gotClassification, classification)
+	}
+	if gotAssessment == nil || !reflect.DeepEqual(*gotAssessment, assessment) {
+		t.Errorf("assessment = %+v, want %+v", gotAssessment, assessment)
+	}
+}
+
+func TestResultFromRun_WithoutAssessment(t *testing.T) {
+	run := storedRun(t, map[string]any{
+		"classification": classify.ClassificationResult{Classification: "UNCLASSIFIED"},
+	})
+
+	classification, assessment, err := classify.ResultFromRun(run)
+	if err != nil {
+		t.Fatalf("ResultFromRun() error = %v", err)
+	}
+	if classification.Classification != "UNCLASSIFIED" {
+		t.Errorf("Classification = %q, want UNCLASSIFIED", classification.Classification)
+	}
+	if assessment != nil {
+		t.Errorf("assessment = %+v, want nil", assessment)
+	}
+}
+
+func TestResultFromRun_Errors(t *testing.T) {
+	other := storedRun(t, map[string]any{"classification": classify.ClassificationResult{}})
+	other.WorkflowName = "summarize"
+
+	malformed := storedRun(t, map[string]any{"classification": "SECRET"})
+
+	missing := storedRun(t, map[string]any{"detections": []any{}})
+
+	empty := storedRun(t, nil)
+	empty.Result = nil
+
+	tests := []struct {
+		name string
+		run  *workflows.Run
+		want error
+	}{
+		{"other workflow", other, classify.ErrNotClassifyRun},
+		{"malformed result", malformed, classify.ErrMalformedResult},
+		{"no classification", missing, nil},
+		{"no result", empty, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := classify.ResultFromRun(tt.run)
+			if !errors.Is(err, workflows.ErrReportUnavailable) {
+				t.Fatalf("ResultFromRun() error = %v, want ErrReportUnavailable", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("ResultFromRun() error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (errors.Is(err, classify.ErrNotClassifyRun) || errors.Is(err, classify.ErrMalformedResult)) {
+				t.Errorf("ResultFromRun() error = %v, want neither ErrNotClassifyRun nor ErrMalformedResult", err)
+			}
+		})
+	}
+}
+
+func TestBuildReport_NotClassifyRun(t *testing.T) {
+	run := sampleClassifyRun(t)
+	run.WorkflowName = "summarize"
+
+	_, err := classify.BuildReport(run)
+	if !errors.Is(err, classify.ErrNotClassifyRun) {
+		t.Errorf("BuildReport() error = %v, want ErrNotClassifyRun", err)
+	}
+}
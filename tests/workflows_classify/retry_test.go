@@ -0,0 +1,187 @@
+package workflows_classify_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/config"
+	wf "github.com/JaimeStill/go-agents-orchestration/pkg/workflows"
+)
+
+var errFlaky = errors.New("flaky provider")
+
+// attemptCounter counts processor calls per item.
+type attemptCounter struct {
+	mu    sync.Mutex
+	calls map[int]int
+}
+
+func (c *attemptCounter) next(item int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls == nil {
+		c.calls = map[int]int{}
+	}
+	c.calls[item]++
+	return c.calls[item]
+}
+
+func testParallelConfig(failFast bool) config.ParallelConfig {
+	cfg := config.DefaultParallelConfig()
+	cfg.Observer = "noop"
+	cfg.FailFastNil = &failFast
+	return cfg
+}
+
+func TestWithRetry_FailOnceThenSucceed(t *testing.T) {
+	var counter attemptCounter
+	processor := func(ctx context.Context, item int) (int, error) {
+		if item == 2 && counter.next(item) == 1 {
+			return 0, errFlaky
+		}
+		return item * 10, nil
+	}
+
+	retry := classify.RetryOptions{MaxAttempts: 3, Backoff: "1ms"}
+	result, err := wf.ProcessParallel(context.Background(), testParallelConfig(true), []int{1, 2, 3}, classify.WithRetry(retry, processor), nil)
+	if err != nil {
+		t.Fatalf("ProcessParallel() error = %v", err)
+	}
+
+	want := []int{10, 20, 30}
+	if len(result.Results) != len(want) {
+		t.Fatalf("Results = %v, want %v", result.Results, want)
+	}
+	for i := range want {
+		if result.Results[i] != want[i] {
+			t.Errorf("Results[%d] = %d, want %d", i, result.Results[i], want[i])
+		}
+	}
+	if counter.calls[2] != 2 {
+		t.Errorf("item 2 attempts = %d, want 2", counter.calls[2])
+	}
+	if counter.calls[1] != 0 || counter.calls[3] != 0 {
+		t.Errorf("only the failing item should be retried, calls = %v", counter.calls)
+	}
+}
+
+func TestWithRetry_ExhaustedSurfacesItemError(t *testing.T) {
+	var counter attemptCounter
+	processor := func(ctx context.Context, item int) (int, error) {
+		if item == 2 {
+			counter.next(item)
+			return 0, errFlaky
+		}
+		return item * 10, nil
+	}
+
+	retry := classify.RetryOptions{MaxAttempts: 3}
+	result, err := wf.ProcessParallel(context.Background(), testParallelConfig(false), []int{1, 2, 3}, classify.WithRetry(retry, processor), nil)
+	if err != nil {
+		t.Fatalf("ProcessParallel() error = %v", err)
+	}
+
+	if len(result.Results) != 2 {
+		t.Errorf("Results = %v, want the two healthy items", result.Results)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Errors = %v, want one", result.Errors)
+	}
+	taskErr := result.Errors[0]
+	if taskErr.Item != 2 {
+		t.Errorf("failed item = %d, want 2", taskErr.Item)
+	}
+	if !errors.Is(taskErr.Err, errFlaky) {
+		t.Errorf("item error = %v, want errFlaky", taskErr.Err)
+	}
+	if counter.calls[2] != 3 {
+		t.Errorf("item 2 attempts = %d, want 3", counter.calls[2])
+	}
+}
+
+func TestWithRetry_StopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var counter attemptCounter
+	processor := func(ctx context.Context, item int) (int, error) {
+		if counter.next(item) == 1 {
+			cancel()
+		}
+		return 0, errFlaky
+	}
+
+	retry := classify.RetryOptions{MaxAttempts: 5, Backoff: "1h"}
+	start := time.Now()
+	_, err := classify.WithRetry(retry, processor)(ctx, 1)
+	if !errors.Is(err, errFlaky) {
+		t.Errorf("error = %v, want errFlaky", err)
+	}
+	if counter.calls[1] != 1 {
+		t.Errorf("attempts = %d, want 1", counter.calls[1])
+	}
+	if time.Since(start) > time.Second {
+		t.Error("retry should not wait out the backoff after cancellation")
+	}
+}
+
+func TestWithRetry_Disabled(t *testing.T) {
+	var counter attemptCounter
+	processor := func(ctx context.Context, item int) (int, error) {
+		counter.next(item)
+		return 0, errFlaky
+	}
+
+	for _, attempts := range []int{0, 1} {
+		counter.calls = nil
+		_, err := classify.WithRetry(classify.RetryOptions{MaxAttempts: attempts}, processor)(context.Background(), 1)
+		if !errors.Is(err, errFlaky) {
+			t.Errorf("MaxAttempts %d: error = %v, want errFlaky", attempts, err)
+		}
+		if counter.calls[1] != 1 {
+			t.Errorf("MaxAttempts %d: attempts = %d, want 1", attempts, counter.calls[1])
+		}
+	}
+}
+
+func TestStageParallelOptions_Retry(t *testing.T) {
+	stage := stageWithOptions(t, map[string]any{
+		"max_concurrency": 2,
+		"retry":           map[string]any{"max_attempts": 3, "backoff": "250ms"},
+	})
+
+	opts, err := classify.StageParallelOptions(stage)
+	if err != nil {
+		t.Fatalf("StageParallelOptions() error = %v", err)
+	}
+	if opts.Retry.MaxAttempts != 3 {
+		t.Errorf("MaxAttempts = %d, want 3", opts.Retry.MaxAttempts)
+	}
+	if opts.Retry.BackoffDuration() != 250*time.Millisecond {
+		t.Errorf("Backoff = %s, want 250ms", opts.Retry.BackoffDuration())
+	}
+}
+
+func TestStageParallelOptions_InvalidRetry(t *testing.T) {
+	tests := []struct {
+		name  string
+		retry map[string]any
+	}{
+		{"negative attempts", map[string]any{"max_attempts": -1}},
+		{"excessive attempts", map[string]any{"max_attempts": classify.MaxStageAttempts + 1}},
+		{"malformed backoff", map[string]any{"backoff": "soon"}},
+		{"negative backoff", map[string]any{"backoff": "-1s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := classify.StageParallelOptions(stageWithOptions(t, map[string]any{"retry": tt.retry}))
+			if !errors.Is(err, classify.ErrInvalidStageOptions) {
+				t.Errorf("StageParallelOptions() error = %v, want ErrInvalidStageOptions", err)
+			}
+		})
+	}
+}
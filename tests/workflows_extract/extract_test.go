@@ -0,0 +1,198 @@
+package workflows_extract_test
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/agent-lab/workflows/extract"
+)
+
+const invoiceSchema = `{
+	"type": "object",
+	"required": ["invoice_number"],
+	"additionalProperties": false,
+	"properties": {
+		"invoice_number": {"type": ["string", "null"]},
+		"vendor": {"type": ["string", "null"]},
+		"currency": {"type": ["string", "null"], "enum": ["USD", "EUR", null]},
+		"total": {"type": ["number", "null"]},
+		"line_items": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"required": ["description", "quantity"],
+				"properties": {
+					"description": {"type": "string"},
+					"quantity": {"type": "integer"},
+					"amount": {"type": "number"}
+				}
+			}
+		}
+	}
+}`
+
+func parseInvoiceSchema(t *testing.T) *extract.Schema {
+	t.Helper()
+
+	schema, err := extract.ParseSchema(invoiceSchema)
+	if err != nil {
+		t.Fatalf("ParseSchema() error = %v", err)
+	}
+	return schema
+}
+
+func TestParsePageResponse_Valid(t *testing.T) {
+	schema := parseInvoiceSchema(t)
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"direct json", `{"invoice_number": "INV-7", "vendor": "Acme", "currency": "USD", "line_items": [{"description": "Widget", "quantity": 2, "amount": 9.5}]}`},
+		{"markdown code block", "Here is the data:\n\n```json\n{\"invoice_number\": \"INV-7\", \"line_items\": []}\n```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := extract.ParsePageResponse(tt.content, schema)
+			if err != nil {
+				t.Fatalf("ParsePageResponse() error = %v", err)
+			}
+			if !page.Valid {
+				t.Errorf("Valid = false, errors = %v", page.Errors)
+			}
+
+			data, ok := page.Data.(map[string]any)
+			if !ok || data["invoice_number"] != "INV-7" {
+				t.Errorf("Data = %v, want invoice_number INV-7", page.Data)
+			}
+		})
+	}
+}
+
+func TestParsePageResponse_SchemaViolations(t *testing.T) {
+	schema := parseInvoiceSchema(t)
+
+	content := `{"vendor": 42, "currency": "GBP", "line_items": [{"description": "Widget", "quantity": 1.5}], "notes": "x"}`
+
+	page, err := extract.ParsePageResponse(content, schema)
+	if err != nil {
+		t.Fatalf("ParsePageResponse() error = %v", err)
+	}
+	if page.Valid {
+		t.Fatal("Valid = true, want false")
+	}
+
+	want := []string{
+		`$: missing required property "invoice_number"`,
+		"$.currency: value not in enum",
+		"$.line_items[0].quantity: expected integer, got number",
+		`$: unexpected property "notes"`,
+		"$.vendor: expected string or null, got number",
+	}
+	for _, w := range want {
+		if !slices.Contains(page.Errors, w) {
+			t.Errorf("Errors = %v, missing %q", page.Errors, w)
+		}
+	}
+	if len(page.Errors) != len(want) {
+		t.Errorf("len(Errors) = %d, want %d: %v", len(page.Errors), len(want), page.Errors)
+	}
+}
+
+func TestParsePageResponse_NoJSON(t *testing.T) {
+	_, err := extract.ParsePageResponse("I could not read this page.", parseInvoiceSchema(t))
+	if !errors.Is(err, extract.ErrParseResponse) {
+		t.Errorf("error = %v, want ErrParseResponse", err)
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr bool
+	}{
+		{"string", `{"type": "object"}`, false},
+		{"decoded object", map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, false},
+		{"missing", nil, true},
+		{"malformed json", `{"type":`, true},
+		{"unknown type", `{"type": "date"}`, true},
+		{"unknown nested type", `{"properties": {"when": {"type": "datetime"}}}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := extract.ParseSchema(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, extract.ErrInvalidSchema) {
+					t.Errorf("ParseSchema() error = %v, want ErrInvalidSchema", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseSchema() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	schema := parseInvoiceSchema(t)
+
+	responses := map[int]string{
+		2: `{"invoice_number": null, "total": 30, "line_items": [{"description": "Gadget", "quantity": 1, "amount": 12}]}`,
+		1: `{"invoice_number": "INV-7", "vendor": "Acme", "total": null, "line_items": [{"description": "Widget", "quantity": 2, "amount": 9}]}`,
+		3: `{"vendor": "Other"}`,
+	}
+
+	var pages []extract.PageExtraction
+	for _, n := range []int{2, 3, 1} {
+		page, err := extract.ParsePageResponse(responses[n], schema)
+		if err != nil {
+			t.Fatalf("page %d: ParsePageResponse() error = %v", n, err)
+		}
+		page.PageNumber = n
+		pages = append(pages, page)
+	}
+
+	result := extract.Aggregate(pages)
+
+	if result.ValidPages != 2 {
+		t.Errorf("ValidPages = %d, want 2", result.ValidPages)
+	}
+	if !slices.Equal(result.InvalidPages, []int{3}) {
+		t.Errorf("InvalidPages = %v, want [3]", result.InvalidPages)
+	}
+	for i, p := range result.Pages {
+		if p.PageNumber != i+1 {
+			t.Errorf("Pages[%d].PageNumber = %d, want %d", i, p.PageNumber, i+1)
+		}
+	}
+
+	want := map[string]any{
+		"invoice_number": "INV-7",
+		"vendor":         "Acme",
+		"total":          float64(30),
+		"line_items": []any{
+			map[string]any{"description": "Widget", "quantity": float64(2), "amount": float64(9)},
+			map[string]any{"description": "Gadget", "quantity": float64(1), "amount": float64(12)},
+		},
+	}
+	if !reflect.DeepEqual(result.Data, want) {
+		t.Errorf("Data = %v, want %v", result.Data, want)
+	}
+}
+
+func TestBuildExtractionPrompt(t *testing.T) {
+	prompt := extract.BuildExtractionPrompt("Extract the invoice header.", parseInvoiceSchema(t), 4)
+
+	for _, want := range []string{"page 4", "Extract the invoice header.", `"invoice_number"`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
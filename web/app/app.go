@@ -30,7 +30,7 @@ var publicFiles = []string{
 }
 
 var views = []web.ViewDef{
-	{Route: "/{path...}", Template: "shell.html", Title: "Agent Lab", Bundle: "app"},
+	{Route: "/", Template: "shell.html", Title: "Agent Lab", Bundle: "app"},
 }
 
 // NewModule creates the app module configured for the given base path.
@@ -47,16 +47,24 @@ func NewModule(basePath string) (*module.Module, error) {
 		return nil, err
 	}
 
-	router := buildRouter(ts)
+	assets, err := web.NewAssets(distFS)
+	if err != nil {
+		return nil, err
+	}
+	ts.SetAssets(assets)
+
+	router := buildRouter(ts, assets)
 	return module.New(basePath, router), nil
 }
 
-func buildRouter(ts *web.TemplateSet) http.Handler {
+func buildRouter(ts *web.TemplateSet, assets *web.Assets) http.Handler {
 	r := web.NewRouter()
 
-	r.HandleFunc("GET /{path...}", ts.PageHandler("app.html", views[0]))
+	shell := ts.PageHandler("app.html", views[0])
+	r.HandleFunc("GET /{$}", shell)
+	r.SetFallback(web.ShellFallback(shell))
 
-	r.Handle("GET /dist/", http.FileServer(http.FS(distFS)))
+	r.Handle("GET /dist/", assets.Handler())
 
 	for _, route := range web.PublicFileRoutes(publicFS, "public", publicFiles...) {
 		r.HandleFunc(route.Method+" "+route.Pattern, route.Handler)
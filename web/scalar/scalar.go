@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/JaimeStill/agent-lab/pkg/module"
+	"github.com/JaimeStill/agent-lab/pkg/web"
 )
 
 //go:embed index.html scalar.css scalar.js
@@ -22,13 +23,18 @@ func NewModule(basePath string) *module.Module {
 func buildRouter(basePath string) http.Handler {
 	mux := http.NewServeMux()
 
+	assets, err := web.NewAssets(staticFS)
+	if err != nil {
+		panic("failed to fingerprint assets: " + err.Error())
+	}
+
 	tmpl := template.Must(template.ParseFS(staticFS, "index.html"))
 	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		tmpl.Execute(w, map[string]string{"BasePath": basePath})
+		tmpl.Execute(w, map[string]any{"BasePath": basePath, "Assets": assets})
 	})
 
-	mux.Handle("GET /", http.FileServer(http.FS(staticFS)))
+	mux.Handle("GET /", assets.Handler())
 
 	return mux
 }
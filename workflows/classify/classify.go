@@ -18,11 +18,15 @@ import (
 	"github.com/JaimeStill/agent-lab/internal/images"
 	"github.com/JaimeStill/agent-lab/internal/profiles"
 	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/config"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
 	wf "github.com/JaimeStill/go-agents-orchestration/pkg/workflows"
 )
 
+// WorkflowName is the name the classify workflow is registered under.
+const WorkflowName = "classify-docs"
+
 // DefaultLegibilityThreshold is the legibility score below which markings are
 // candidates for enhancement. Pages with any marking below this threshold and
 // a valid FilterSuggestion will be re-rendered with suggested filters.
@@ -57,10 +61,27 @@ type MarkingInfo struct {
 }
 
 // FilterSuggestion recommends image enhancement settings for improved detection.
+// Regions optionally locates the low-legibility markings so marking-level
+// enhancement can re-analyze them individually.
 type FilterSuggestion struct {
-	Brightness *int `json:"brightness,omitempty"`
-	Contrast   *int `json:"contrast,omitempty"`
-	Saturation *int `json:"saturation,omitempty"`
+	Brightness *int            `json:"brightness,omitempty"`
+	Contrast   *int            `json:"contrast,omitempty"`
+	Saturation *int            `json:"saturation,omitempty"`
+	Grayscale  *bool           `json:"grayscale,omitempty"`
+	Regions    []MarkingRegion `json:"regions,omitempty"`
+}
+
+// MarkingRegion is a crop hint bounding one marking on its page. X, Y,
+// Width, and Height are fractions of the page dimensions measured from the
+// top-left corner, so hints hold at any render DPI. Text and Location
+// identify the marking the region belongs to.
+type MarkingRegion struct {
+	Text     string  `json:"text"`
+	Location string  `json:"location"`
+	X        float64 `json:"x"`
+	Y        float64 `json:"y"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
 }
 
 // ClassificationResult contains the overall document classification determined
@@ -70,6 +91,7 @@ type ClassificationResult struct {
 	AlternativeReadings []AlternativeReading `json:"alternative_readings,omitempty"`
 	MarkingSummary      []string             `json:"marking_summary"`
 	Rationale           string               `json:"rationale"`
+	Confidence          float64              `json:"confidence,omitempty"`
 }
 
 // AlternativeReading represents a possible alternative classification when
@@ -97,8 +119,12 @@ type ConfidenceFactor struct {
 }
 
 // EnhanceOptions configures the enhancement stage behavior.
+// When MarkingLevel is set, only the regions of sub-threshold markings are
+// cropped from the enhanced page and re-analyzed; pages missing a region
+// for any sub-threshold marking fall back to whole-page re-analysis.
 type EnhanceOptions struct {
 	LegibilityThreshold float64 `json:"legibility_threshold"`
+	MarkingLevel        bool    `json:"marking_level,omitempty"`
 }
 
 // DefaultEnhanceOptions returns the default enhancement configuration.
@@ -107,7 +133,20 @@ func DefaultEnhanceOptions() EnhanceOptions {
 }
 
 func init() {
-	workflows.Register("classify-docs", factory, "Classifies document security markings using vision analysis")
+	workflows.Register(WorkflowName, factory, "Classifies document security markings using vision analysis")
+	workflows.RegisterParams(WorkflowName, workflows.ProfileParams(map[string]*openapi.Schema{
+		"document_id": {Type: "string", Format: "uuid", Description: "Document to classify"},
+	}, "document_id"))
+	workflows.RegisterReporter(WorkflowName, reporter{})
+	workflows.RegisterComparer(WorkflowName, comparer{})
+	workflows.RegisterStages(WorkflowName, DefaultProfile,
+		workflows.AgentStage{Name: "detect"},
+		workflows.AgentStage{Name: "enhance"},
+		workflows.AgentStage{Name: "classify", HasAgents: func(stage *profiles.ProfileStage) bool {
+			return extractClassifyOptions(stage).Consensus()
+		}},
+		workflows.AgentStage{Name: "score"},
+	)
 }
 
 func factory(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
@@ -116,7 +155,7 @@ func factory(ctx context.Context, graph state.StateGraph, runtime *workflows.Run
 		return state.State{}, err
 	}
 
-	if err := graph.AddNode("init", initNode(runtime)); err != nil {
+	if err := graph.AddNode("init", InitNode(runtime)); err != nil {
 		return state.State{}, err
 	}
 
@@ -140,11 +179,17 @@ func factory(ctx context.Context, graph state.StateGraph, runtime *workflows.Run
 		return state.State{}, err
 	}
 
-	if err := graph.AddEdge("detect", "enhance", state.KeyEquals("needs_enhancement", true)); err != nil {
+	if err := workflows.AddLabeledEdge(graph, "detect", "enhance", state.KeyEquals("needs_enhancement", true), workflows.EdgeLabel{
+		Name:   "needs_enhancement",
+		Reason: "markings below legibility threshold → enhance",
+	}); err != nil {
 		return state.State{}, err
 	}
 
-	if err := graph.AddEdge("detect", "classify", state.KeyEquals("needs_enhancement", false)); err != nil {
+	if err := workflows.AddLabeledEdge(graph, "detect", "classify", state.KeyEquals("needs_enhancement", false), workflows.EdgeLabel{
+		Name:   "markings_legible",
+		Reason: "all markings legible → classify",
+	}); err != nil {
 		return state.State{}, err
 	}
 
@@ -172,7 +217,10 @@ func factory(ctx context.Context, graph state.StateGraph, runtime *workflows.Run
 	return initialState, nil
 }
 
-func initNode(runtime *workflows.Runtime) state.StateNode {
+// InitNode returns the node that loads the document named by the document_id
+// parameter and renders every page at 300 DPI, storing the document under
+// "document" and the rendered []PageImage under "page_images".
+func InitNode(runtime *workflows.Runtime) state.StateNode {
 	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
 		start := time.Now()
 		defer logNodeTiming(runtime.Logger(), "init", start)
@@ -234,6 +282,12 @@ func detectNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime)
 			return s, err
 		}
 
+		parallel, err := StageParallelOptions(stage)
+		if err != nil {
+			return s, err
+		}
+		cfg := parallel.ParallelConfig()
+
 		pages, ok := s.Get("page_images")
 		if !ok {
 			return s, fmt.Errorf("page_images not found in state")
@@ -253,7 +307,7 @@ func detectNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime)
 				return PageDetection{}, fmt.Errorf("%w: failed to retrieve image data: %v", ErrDetectionFailed, err)
 			}
 
-			dataURI := buildDataURI(data, contentType)
+			dataURI := BuildDataURI(data, contentType)
 			prompt := fmt.Sprintf("Analyze page %d of this document for security classification markings.", img.PageNumber)
 
 			resp, err := runtime.Agents().Vision(ctx, agentID, prompt, []string{dataURI}, opts, token)
@@ -272,8 +326,7 @@ func detectNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime)
 			return detection, nil
 		}
 
-		cfg := detectionParallelConfig()
-		result, err := wf.ProcessParallel(ctx, cfg, pageImages, processor, nil)
+		result, err := wf.ProcessParallel(ctx, cfg, pageImages, WithRetry(parallel.Retry, processor), PageProgress(ctx))
 		if err != nil {
 			return s, fmt.Errorf("parallel detection failed: %w", err)
 		}
@@ -301,6 +354,34 @@ func detectNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime)
 	})
 }
 
+// PageProgress returns a ProcessParallel progress callback that reports each
+// finished page detection on ctx as a page.complete event carrying the page
+// number, marking counts, and overall completion. A page number of zero means
+// the page failed and carries no detection, so no event is sent for it.
+func PageProgress(ctx context.Context) wf.ProgressFunc[PageDetection] {
+	return func(completed, total int, d PageDetection) {
+		if d.PageNumber == 0 {
+			return
+		}
+
+		faded := 0
+		for _, m := range d.MarkingsFound {
+			if m.Faded {
+				faded++
+			}
+		}
+
+		workflows.ReportProgress(ctx, workflows.EventPageComplete, map[string]any{
+			"node_name":      "detect",
+			"page_number":    d.PageNumber,
+			"markings_found": len(d.MarkingsFound),
+			"faded_markings": faded,
+			"completed":      completed,
+			"total":          total,
+		})
+	}
+}
+
 func enhanceNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime) state.StateNode {
 	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
 		start := time.Now()
@@ -313,6 +394,12 @@ func enhanceNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime
 			return s, err
 		}
 
+		parallel, err := StageParallelOptions(stage)
+		if err != nil {
+			return s, err
+		}
+		cfg := parallel.ParallelConfig()
+
 		detections, _ := s.Get("detections")
 		detectList := detections.([]PageDetection)
 
@@ -352,6 +439,14 @@ func enhanceNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime
 			return s, nil
 		}
 
+		analyze := func(ctx context.Context, data []byte, contentType, prompt string) (PageDetection, error) {
+			resp, err := runtime.Agents().Vision(ctx, agentID, prompt, []string{BuildDataURI(data, contentType)}, opts, token)
+			if err != nil {
+				return PageDetection{}, fmt.Errorf("%w: %v", ErrEnhancementFailed, err)
+			}
+			return ParseDetectionResponse(resp.Content())
+		}
+
 		processor := func(ctx context.Context, original PageDetection) (PageDetection, error) {
 			renderOpts := images.RenderOptions{
 				Pages:      fmt.Sprintf("%d", original.PageNumber),
@@ -360,6 +455,7 @@ func enhanceNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime
 				Brightness: original.FilterSuggestion.Brightness,
 				Contrast:   original.FilterSuggestion.Contrast,
 				Saturation: original.FilterSuggestion.Saturation,
+				Grayscale:  original.FilterSuggestion.Grayscale,
 				Force:      true,
 			}
 
@@ -379,15 +475,30 @@ func enhanceNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime
 				return PageDetection{}, fmt.Errorf("%w: failed to retrieve enhanced image data: %v", ErrEnhancementFailed, err)
 			}
 
-			dataURI := buildDataURI(data, contentType)
-			prompt := fmt.Sprintf("Analyze page %d of this document for security classification markings.", original.PageNumber)
-
-			resp, err := runtime.Agents().Vision(ctx, agentID, prompt, []string{dataURI}, opts, token)
-			if err != nil {
-				return PageDetection{}, fmt.Errorf("%w: %v", ErrEnhancementFailed, err)
+			if enhanceOpts.MarkingLevel {
+				if regions, ok := markingRegions(original, enhanceOpts.LegibilityThreshold); ok {
+					crops := make([]RegionDetection, 0, len(regions))
+					for _, region := range regions {
+						cropped, err := CropRegion(data, region)
+						if err != nil {
+							return PageDetection{}, fmt.Errorf("%w: crop %s marking: %v", ErrEnhancementFailed, region.Location, err)
+						}
+
+						prompt := fmt.Sprintf("Analyze this cropped %s region of page %d for a security classification marking.", region.Location, original.PageNumber)
+						detection, err := analyze(ctx, cropped, "image/png", prompt)
+						if err != nil {
+							return PageDetection{}, err
+						}
+
+						crops = append(crops, RegionDetection{Region: region, Detection: detection})
+					}
+
+					return MergeRegionDetections(original, enhancedImg.ID, crops, enhanceOpts.LegibilityThreshold), nil
+				}
 			}
 
-			enhanced, err := ParseDetectionResponse(resp.Content())
+			prompt := fmt.Sprintf("Analyze page %d of this document for security classification markings.", original.PageNumber)
+			enhanced, err := analyze(ctx, data, contentType, prompt)
 			if err != nil {
 				return PageDetection{}, err
 			}
@@ -398,8 +509,7 @@ func enhanceNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime
 			return mergeDetections(original, enhanced, enhanceOpts.LegibilityThreshold), nil
 		}
 
-		cfg := detectionParallelConfig()
-		result, err := wf.ProcessParallel(ctx, cfg, pagesToEnhance, processor, nil)
+		result, err := wf.ProcessParallel(ctx, cfg, pagesToEnhance, WithRetry(parallel.Retry, processor), nil)
 		if err != nil {
 			return s, fmt.Errorf("parallel enhancement failed: %w", err)
 		}
@@ -431,11 +541,7 @@ func classifyNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtim
 		defer logNodeTiming(runtime.Logger(), "classify", start)
 
 		stage := profile.Stage("classify")
-
-		agentID, token, err := workflows.ExtractAgentParams(s, stage)
-		if err != nil {
-			return s, err
-		}
+		classifyOpts := extractClassifyOptions(stage)
 
 		detections, _ := s.Get("detections")
 		detectList := detections.([]PageDetection)
@@ -450,17 +556,42 @@ func classifyNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtim
 			opts["system_prompt"] = *stage.SystemPrompt
 		}
 
-		resp, err := runtime.Agents().Chat(ctx, agentID, prompt, opts, token)
-		if err != nil {
-			return s, fmt.Errorf("%w: %v", ErrClassificationFailed, err)
+		var token string
+		if tkn, ok := s.GetSecret("token"); ok {
+			token = tkn.(string)
+		}
+
+		classify := func(ctx context.Context, agentID uuid.UUID) (ClassificationResult, error) {
+			resp, err := runtime.Agents().Chat(ctx, agentID, prompt, opts, token)
+			if err != nil {
+				return ClassificationResult{}, fmt.Errorf("%w: %v", ErrClassificationFailed, err)
+			}
+			return ParseClassificationResponse(resp.Content())
+		}
+
+		if !classifyOpts.Consensus() {
+			agentID, _, err := workflows.ExtractAgentParams(s, stage)
+			if err != nil {
+				return s, err
+			}
+
+			classification, err := classify(ctx, agentID)
+			if err != nil {
+				return s, err
+			}
+
+			s = s.Set("classification", classification)
+			return s, nil
 		}
 
-		classification, err := ParseClassificationResponse(resp.Content())
+		cfg := detectionParallelConfig()
+		result, err := wf.ProcessParallel(ctx, cfg, classifyOpts.AgentIDs, classify, nil)
 		if err != nil {
-			return s, err
+			return s, fmt.Errorf("parallel classification failed: %w", err)
 		}
 
-		s = s.Set("classification", classification)
+		s = s.Set("classification", AggregateClassifications(result.Results))
+		s = s.Set("classification_votes", result.Results)
 
 		return s, nil
 	})
@@ -537,7 +668,8 @@ func buildClassificationPrompt(docName string, detections []PageDetection) strin
 	return sb.String()
 }
 
-func buildDataURI(data []byte, contentType string) string {
+// BuildDataURI encodes image data as a base64 data URI for vision requests.
+func BuildDataURI(data []byte, contentType string) string {
 	encoded := base64.StdEncoding.EncodeToString(data)
 	return fmt.Sprintf("data:%s;base64,%s", contentType, encoded)
 }
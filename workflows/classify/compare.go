@@ -0,0 +1,227 @@
+package classify
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/google/uuid"
+)
+
+// Comparison is the diff of two completed classify runs, typically the same
+// document run through two profiles. Deltas are computed as B minus A.
+type Comparison struct {
+	RunA                 uuid.UUID        `json:"run_a"`
+	RunB                 uuid.UUID        `json:"run_b"`
+	SameDocument         bool             `json:"same_document"`
+	ClassificationA      string           `json:"classification_a"`
+	ClassificationB      string           `json:"classification_b"`
+	ClassificationAgrees bool             `json:"classification_agrees"`
+	MarkingsAgree        bool             `json:"markings_agree"`
+	PagesDiffering       int              `json:"pages_differing"`
+	Pages                []PageComparison `json:"pages"`
+	Confidence           ConfidenceDelta  `json:"confidence"`
+}
+
+// PageComparison contrasts the markings detected on one page by each run.
+// Markings are compared by normalized text; OnlyInA and OnlyInB list the
+// markings detected by one run but not the other.
+type PageComparison struct {
+	PageNumber   int      `json:"page_number"`
+	MarkingsA    []string `json:"markings_a"`
+	MarkingsB    []string `json:"markings_b"`
+	OnlyInA      []string `json:"only_in_a,omitempty"`
+	OnlyInB      []string `json:"only_in_b,omitempty"`
+	Agrees       bool     `json:"agrees"`
+	ClarityDelta float64  `json:"clarity_delta"`
+}
+
+// ConfidenceDelta contrasts the confidence assessments of each run.
+// Scores are nil when a run has no confidence assessment.
+type ConfidenceDelta struct {
+	ScoreA          *float64      `json:"score_a,omitempty"`
+	ScoreB          *float64      `json:"score_b,omitempty"`
+	Delta           *float64      `json:"delta,omitempty"`
+	RecommendationA string        `json:"recommendation_a,omitempty"`
+	RecommendationB string        `json:"recommendation_b,omitempty"`
+	Factors         []FactorDelta `json:"factors,omitempty"`
+}
+
+// FactorDelta contrasts a single named confidence factor.
+type FactorDelta struct {
+	Name   string   `json:"name"`
+	ScoreA *float64 `json:"score_a,omitempty"`
+	ScoreB *float64 `json:"score_b,omitempty"`
+	Delta  *float64 `json:"delta,omitempty"`
+}
+
+// Compare builds the Comparison of two classify runs.
+// Returns workflows.ErrReportUnavailable if either run has no classification result.
+func Compare(a, b *workflows.Run) (*Comparison, error) {
+	ra, err := BuildReport(a)
+	if err != nil {
+		return nil, err
+	}
+	rb, err := BuildReport(b)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Comparison{
+		RunA:                 a.ID,
+		RunB:                 b.ID,
+		SameDocument:         ra.DocumentID != nil && rb.DocumentID != nil && *ra.DocumentID == *rb.DocumentID,
+		ClassificationA:      ra.Classification,
+		ClassificationB:      rb.Classification,
+		ClassificationAgrees: normalizeMarking(ra.Classification) == normalizeMarking(rb.Classification),
+		Pages:                comparePages(ra.Pages, rb.Pages),
+		Confidence:           compareConfidence(ra.Confidence, rb.Confidence),
+	}
+
+	for _, p := range c.Pages {
+		if !p.Agrees {
+			c.PagesDiffering++
+		}
+	}
+	c.MarkingsAgree = c.PagesDiffering == 0
+
+	return c, nil
+}
+
+func comparePages(a, b []PageDetection) []PageComparison {
+	pagesA := indexPages(a)
+	pagesB := indexPages(b)
+
+	var numbers []int
+	for n := range pagesA {
+		numbers = append(numbers, n)
+	}
+	for n := range pagesB {
+		if _, ok := pagesA[n]; !ok {
+			numbers = append(numbers, n)
+		}
+	}
+	slices.Sort(numbers)
+
+	pages := make([]PageComparison, 0, len(numbers))
+	for _, n := range numbers {
+		pa, pb := pagesA[n], pagesB[n]
+
+		markingsA := pageMarkings(pa)
+		markingsB := pageMarkings(pb)
+
+		pc := PageComparison{
+			PageNumber: n,
+			MarkingsA:  markingsA,
+			MarkingsB:  markingsB,
+			OnlyInA:    difference(markingsA, markingsB),
+			OnlyInB:    difference(markingsB, markingsA),
+		}
+		pc.Agrees = len(pc.OnlyInA) == 0 && len(pc.OnlyInB) == 0 && (pa != nil) == (pb != nil)
+
+		if pa != nil && pb != nil {
+			pc.ClarityDelta = pb.ClarityScore - pa.ClarityScore
+		}
+
+		pages = append(pages, pc)
+	}
+
+	return pages
+}
+
+func compareConfidence(a, b *ConfidenceAssessment) ConfidenceDelta {
+	var d ConfidenceDelta
+
+	if a != nil {
+		d.ScoreA = &a.OverallScore
+		d.RecommendationA = a.Recommendation
+	}
+	if b != nil {
+		d.ScoreB = &b.OverallScore
+		d.RecommendationB = b.Recommendation
+	}
+	d.Delta = delta(d.ScoreA, d.ScoreB)
+
+	var names []string
+	factorsA := map[string]float64{}
+	factorsB := map[string]float64{}
+	if a != nil {
+		for _, f := range a.Factors {
+			factorsA[f.Name] = f.Score
+			names = append(names, f.Name)
+		}
+	}
+	if b != nil {
+		for _, f := range b.Factors {
+			factorsB[f.Name] = f.Score
+			if _, ok := factorsA[f.Name]; !ok {
+				names = append(names, f.Name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		fd := FactorDelta{Name: name}
+		if s, ok := factorsA[name]; ok {
+			fd.ScoreA = &s
+		}
+		if s, ok := factorsB[name]; ok {
+			fd.ScoreB = &s
+		}
+		fd.Delta = delta(fd.ScoreA, fd.ScoreB)
+		d.Factors = append(d.Factors, fd)
+	}
+
+	return d
+}
+
+func indexPages(pages []PageDetection) map[int]*PageDetection {
+	index := make(map[int]*PageDetection, len(pages))
+	for i := range pages {
+		index[pages[i].PageNumber] = &pages[i]
+	}
+	return index
+}
+
+// pageMarkings returns the sorted, deduplicated normalized marking text of a page.
+func pageMarkings(p *PageDetection) []string {
+	markings := []string{}
+	if p == nil {
+		return markings
+	}
+	for _, m := range p.MarkingsFound {
+		if text := normalizeMarking(m.Text); text != "" {
+			markings = append(markings, text)
+		}
+	}
+	slices.Sort(markings)
+	return slices.Compact(markings)
+}
+
+func difference(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func delta(a, b *float64) *float64 {
+	if a == nil || b == nil {
+		return nil
+	}
+	d := *b - *a
+	return &d
+}
+
+func normalizeMarking(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), " "))
+}
+
+type comparer struct{}
+
+func (comparer) Compare(a, b *workflows.Run) (any, error) {
+	return Compare(a, b)
+}
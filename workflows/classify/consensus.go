@@ -0,0 +1,123 @@
+package classify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/google/uuid"
+)
+
+// ClassifyOptions configures the classify stage behavior.
+// When AgentIDs lists more than one agent, each agent classifies the document
+// independently and the results are aggregated by AggregateClassifications.
+type ClassifyOptions struct {
+	AgentIDs []uuid.UUID `json:"agent_ids,omitempty"`
+}
+
+// Consensus reports whether the options configure multi-agent voting.
+func (o ClassifyOptions) Consensus() bool {
+	return len(o.AgentIDs) > 1
+}
+
+func extractClassifyOptions(stage *profiles.ProfileStage) ClassifyOptions {
+	var opts ClassifyOptions
+	if stage == nil || len(stage.Options) == 0 {
+		return opts
+	}
+	json.Unmarshal(stage.Options, &opts)
+	return opts
+}
+
+// AggregateClassifications combines independent classification votes into a
+// single result. The classification is decided by majority vote, with ties
+// broken by higher average confidence and then by first occurrence. Marking
+// summaries are unioned, confidence is averaged across all votes, and every
+// losing classification is recorded as an AlternativeReading whose probability
+// is its share of the vote, followed by any alternatives the voters reported.
+func AggregateClassifications(votes []ClassificationResult) ClassificationResult {
+	switch len(votes) {
+	case 0:
+		return ClassificationResult{}
+	case 1:
+		return votes[0]
+	}
+
+	type tally struct {
+		classification string
+		count          int
+		confidence     float64
+		first          int
+	}
+
+	tallies := make(map[string]*tally)
+	order := make([]string, 0, len(votes))
+	var totalConfidence float64
+
+	for i, v := range votes {
+		key := strings.TrimSpace(v.Classification)
+		t, ok := tallies[key]
+		if !ok {
+			t = &tally{classification: key, first: i}
+			tallies[key] = t
+			order = append(order, key)
+		}
+		t.count++
+		t.confidence += v.Confidence
+		totalConfidence += v.Confidence
+	}
+
+	winner := tallies[order[0]]
+	for _, key := range order[1:] {
+		t := tallies[key]
+		if t.count > winner.count ||
+			(t.count == winner.count && t.confidence/float64(t.count) > winner.confidence/float64(winner.count)) {
+			winner = t
+		}
+	}
+
+	total := len(votes)
+	result := ClassificationResult{
+		Classification: winner.classification,
+		MarkingSummary: []string{},
+		Confidence:     totalConfidence / float64(total),
+		Rationale: fmt.Sprintf("Consensus of %d of %d agents. %s",
+			winner.count, total, votes[winner.first].Rationale),
+	}
+
+	seenAlt := map[string]bool{winner.classification: true}
+	for _, key := range order {
+		t := tallies[key]
+		if t == winner {
+			continue
+		}
+		seenAlt[key] = true
+		result.AlternativeReadings = append(result.AlternativeReadings, AlternativeReading{
+			Classification: t.classification,
+			Probability:    float64(t.count) / float64(total),
+			Reason:         fmt.Sprintf("%d of %d agents voted for this classification", t.count, total),
+		})
+	}
+
+	seenMarking := make(map[string]bool)
+	for _, v := range votes {
+		for _, m := range v.MarkingSummary {
+			if !seenMarking[m] {
+				seenMarking[m] = true
+				result.MarkingSummary = append(result.MarkingSummary, m)
+			}
+		}
+
+		for _, alt := range v.AlternativeReadings {
+			key := strings.TrimSpace(alt.Classification)
+			if seenAlt[key] {
+				continue
+			}
+			seenAlt[key] = true
+			result.AlternativeReadings = append(result.AlternativeReadings, alt)
+		}
+	}
+
+	return result
+}
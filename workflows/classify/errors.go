@@ -12,4 +12,7 @@ var (
 	ErrEnhancementFailed    = errors.New("enhancement failed")
 	ErrClassificationFailed = errors.New("classification failed")
 	ErrScoringFailed        = errors.New("scoring failed")
+	ErrInvalidStageOptions  = errors.New("invalid stage options")
+	ErrNotClassifyRun       = errors.New("run is not a classify run")
+	ErrMalformedResult      = errors.New("malformed classify result")
 )
@@ -0,0 +1,79 @@
+package classify
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/config"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/observability"
+)
+
+// MaxStageConcurrency is the largest worker count a stage may configure.
+const MaxStageConcurrency = 64
+
+// ParallelOptions configures page-level parallelism for the detect and enhance
+// stages. Zero values keep the default behavior: an auto-detected worker count,
+// the noop observer, and no retries. Lowering MaxConcurrency throttles requests
+// against provider rate limits.
+type ParallelOptions struct {
+	MaxConcurrency int          `json:"max_concurrency,omitempty"`
+	Observer       string       `json:"observer,omitempty"`
+	Retry          RetryOptions `json:"retry,omitzero"`
+}
+
+// Validate checks that MaxConcurrency is within [0, MaxStageConcurrency],
+// that Observer, if set, names a registered observer, and that Retry is valid.
+func (o ParallelOptions) Validate() error {
+	if o.MaxConcurrency < 0 || o.MaxConcurrency > MaxStageConcurrency {
+		return fmt.Errorf("%w: max_concurrency must be between 0 and %d", ErrInvalidStageOptions, MaxStageConcurrency)
+	}
+
+	if o.Observer != "" {
+		if _, err := observability.GetObserver(o.Observer); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidStageOptions, err)
+		}
+	}
+
+	return o.Retry.Validate()
+}
+
+// ParallelConfig applies the options to the default detection ParallelConfig.
+func (o ParallelOptions) ParallelConfig() config.ParallelConfig {
+	cfg := detectionParallelConfig()
+	if o.MaxConcurrency > 0 {
+		cfg.MaxWorkers = o.MaxConcurrency
+	}
+	if o.Observer != "" {
+		cfg.Observer = o.Observer
+	}
+	return cfg
+}
+
+// StageParallelConfig reads ParallelOptions from the stage options, validates
+// them, and returns the resulting ParallelConfig. A nil stage or empty options
+// yield the default configuration.
+func StageParallelConfig(stage *profiles.ProfileStage) (config.ParallelConfig, error) {
+	opts, err := StageParallelOptions(stage)
+	if err != nil {
+		return config.ParallelConfig{}, err
+	}
+	return opts.ParallelConfig(), nil
+}
+
+// StageParallelOptions reads ParallelOptions from the stage options and
+// validates them. A nil stage or empty options yield the zero value.
+func StageParallelOptions(stage *profiles.ProfileStage) (ParallelOptions, error) {
+	var opts ParallelOptions
+	if stage != nil && len(stage.Options) > 0 {
+		if err := json.Unmarshal(stage.Options, &opts); err != nil {
+			return ParallelOptions{}, fmt.Errorf("%w: %v", ErrInvalidStageOptions, err)
+		}
+	}
+
+	if err := opts.Validate(); err != nil {
+		return ParallelOptions{}, err
+	}
+
+	return opts, nil
+}
@@ -47,17 +47,30 @@ func computeRecommendation(score float64) string {
 	}
 }
 
-func parseResponse[T any](content string, validate func(T) T, errMsg string) (T, error) {
-	var result T
+// ExtractJSON returns the JSON document in an LLM response: the whole
+// response when it is valid JSON, otherwise the contents of the first
+// markdown code block that is. Reports false when neither holds.
+func ExtractJSON(content string) ([]byte, bool) {
 	content = strings.TrimSpace(content)
-	if err := json.Unmarshal([]byte(content), &result); err == nil {
-		return validate(result), nil
+	if json.Valid([]byte(content)) {
+		return []byte(content), true
 	}
 
 	matches := jsonBlockRegex.FindStringSubmatch(content)
 	if len(matches) >= 2 {
-		cleaned := strings.TrimSpace(matches[1])
-		if err := json.Unmarshal([]byte(cleaned), &result); err == nil {
+		cleaned := []byte(strings.TrimSpace(matches[1]))
+		if json.Valid(cleaned) {
+			return cleaned, true
+		}
+	}
+
+	return nil, false
+}
+
+func parseResponse[T any](content string, validate func(T) T, errMsg string) (T, error) {
+	var result T
+	if data, ok := ExtractJSON(content); ok {
+		if err := json.Unmarshal(data, &result); err == nil {
 			return validate(result), nil
 		}
 	}
@@ -66,6 +79,7 @@ func parseResponse[T any](content string, validate func(T) T, errMsg string) (T,
 }
 
 func validateClassification(c ClassificationResult) ClassificationResult {
+	c.Confidence = clamp(c.Confidence, 0.0, 1.0)
 	for i := range c.AlternativeReadings {
 		c.AlternativeReadings[i].Probability = clamp(c.AlternativeReadings[i].Probability, 0.0, 1.0)
 	}
@@ -79,6 +93,16 @@ func validateDetection(d PageDetection) PageDetection {
 		d.MarkingsFound[i].Legibility = clamp(d.MarkingsFound[i].Legibility, 0.0, 1.0)
 	}
 
+	if d.FilterSuggestion != nil {
+		for i := range d.FilterSuggestion.Regions {
+			r := &d.FilterSuggestion.Regions[i]
+			r.X = clamp(r.X, 0.0, 1.0)
+			r.Y = clamp(r.Y, 0.0, 1.0)
+			r.Width = clamp(r.Width, 0.0, 1.0-r.X)
+			r.Height = clamp(r.Height, 0.0, 1.0-r.Y)
+		}
+	}
+
 	return d
 }
 
@@ -25,7 +25,17 @@ OUTPUT FORMAT: Respond with ONLY a JSON object matching this exact schema:
 	"filter_suggestion": {
 		"brightness": <optional integer 0-200>,
 		"contrast": <optional integer -100 to 100>,
-		"saturation": <optional integer 0-200>
+		"saturation": <optional integer 0-200>,
+		"regions": [
+			{
+				"text": "<marking text>",
+				"location": "<header|footer|margin|body>",
+				"x": <0.0-1.0>,
+				"y": <0.0-1.0>,
+				"width": <0.0-1.0>,
+				"height": <0.0-1.0>
+			}
+		]
 	} or null
 }
 
@@ -37,6 +47,7 @@ INSTRUCTIONS:
 - IMPORTANT: A faded marking can still have high legibility if the text is readable
 - clarity_score reflects overall page quality for marking detection
 - Only suggest filter_suggestion if legibility < 0.4 AND you believe enhancement would improve readability
+- In filter_suggestion.regions, give the bounding box of each low-legibility marking as fractions of the page width and height from the top-left corner
 - JSON response only; no preamble or dialog`
 
 const ClassificationSystemPrompt = `You are a document classification specialist. Analyze security marking detections across all pages to determine the overall document classification.
@@ -52,7 +63,8 @@ OUTPUT FORMAT: Respond with ONLY a JSON object matching this exact schema:
 		}
 	],
 	"marking_summary": ["<list of unique markings found>"],
-	"rationale": "<1-2 sentences, max 40 words>"
+	"rationale": "<1-2 sentences, max 40 words>",
+	"confidence": <0.0-1.0>
 }
 
 INSTRUCTIONS:
@@ -65,6 +77,7 @@ INSTRUCTIONS:
 - Only list alternative readings if there is genuine ambiguity about what marking text says
 - marking_summary should list unique marking texts (deduplicated)
 - Keep rationale brief: 1-2 sentences explaining the key deciding factor
+- confidence reflects how certain you are of the primary classification
 - JSON response only; no preamble or dialog`
 
 const ScoringSystemPrompt = `You are a confidence scoring specialist. Evaluate the quality and reliability of document classification results.
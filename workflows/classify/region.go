@@ -0,0 +1,116 @@
+package classify
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// RegionDetection is the re-analysis of a single cropped marking region.
+type RegionDetection struct {
+	Region    MarkingRegion
+	Detection PageDetection
+}
+
+// Region returns the crop hint for marking m, matched by text and location.
+func (f *FilterSuggestion) Region(m MarkingInfo) (MarkingRegion, bool) {
+	if f == nil {
+		return MarkingRegion{}, false
+	}
+	for _, r := range f.Regions {
+		if r.Text == m.Text && r.Location == m.Location {
+			return r, true
+		}
+	}
+	return MarkingRegion{}, false
+}
+
+// markingRegions returns the crop hints for every marking on d below
+// threshold. Reports false if any such marking has no hint, in which case
+// the page must be re-analyzed whole.
+func markingRegions(d PageDetection, threshold float64) ([]MarkingRegion, bool) {
+	var regions []MarkingRegion
+	for _, m := range d.MarkingsFound {
+		if m.Legibility >= threshold {
+			continue
+		}
+		r, ok := d.FilterSuggestion.Region(m)
+		if !ok {
+			return nil, false
+		}
+		regions = append(regions, r)
+	}
+	return regions, len(regions) > 0
+}
+
+// CropRegion crops region from an encoded page image and returns the crop
+// encoded as PNG. Region bounds are clamped to the image.
+func CropRegion(data []byte, region MarkingRegion) ([]byte, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	b := img.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+
+	rect := image.Rect(
+		b.Min.X+int(math.Floor(region.X*w)),
+		b.Min.Y+int(math.Floor(region.Y*h)),
+		b.Min.X+int(math.Ceil((region.X+region.Width)*w)),
+		b.Min.Y+int(math.Ceil((region.Y+region.Height)*h)),
+	).Intersect(b)
+
+	if rect.Empty() {
+		return nil, fmt.Errorf("region %s is empty", region.Location)
+	}
+
+	sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, fmt.Errorf("image type %T does not support cropping", img)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sub.SubImage(rect)); err != nil {
+		return nil, fmt.Errorf("encode crop: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// MergeRegionDetections merges marking-level re-analysis back into the
+// original page detection. The most legible marking read from each crop is
+// attributed to its region's location and merged with mergeDetections, so
+// only markings that were below threshold and improved are replaced; legible
+// markings and the page clarity score are preserved. enhancedImageID is the
+// filtered render the crops were taken from.
+func MergeRegionDetections(original PageDetection, enhancedImageID uuid.UUID, crops []RegionDetection, threshold float64) PageDetection {
+	enhanced := PageDetection{
+		PageNumber:      original.PageNumber,
+		OriginalImageID: enhancedImageID,
+	}
+
+	for _, c := range crops {
+		var best *MarkingInfo
+		for i, m := range c.Detection.MarkingsFound {
+			if best == nil || m.Legibility > best.Legibility {
+				best = &c.Detection.MarkingsFound[i]
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		m := *best
+		m.Location = c.Region.Location
+		enhanced.MarkingsFound = append(enhanced.MarkingsFound, m)
+	}
+
+	return mergeDetections(original, enhanced, threshold)
+}
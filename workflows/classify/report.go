@@ -0,0 +1,170 @@
+package classify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/google/uuid"
+)
+
+// Report is the human-readable summary of a completed classify run.
+type Report struct {
+	RunID               uuid.UUID             `json:"run_id"`
+	Status              workflows.RunStatus   `json:"status"`
+	DocumentID          *uuid.UUID            `json:"document_id,omitempty"`
+	DocumentName        string                `json:"document_name"`
+	Classification      string                `json:"classification"`
+	Rationale           string                `json:"rationale,omitempty"`
+	MarkingSummary      []string              `json:"marking_summary"`
+	AlternativeReadings []AlternativeReading  `json:"alternative_readings,omitempty"`
+	EnhancementApplied  bool                  `json:"enhancement_applied"`
+	Confidence          *ConfidenceAssessment `json:"confidence,omitempty"`
+	Pages               []PageDetection       `json:"pages"`
+}
+
+// BuildReport extracts a Report from the stored result of a classify run.
+// Returns workflows.ErrReportUnavailable if the run has no classification
+// result, along with the errors described by ResultFromRun.
+func BuildReport(run *workflows.Run) (*Report, error) {
+	result, err := decodeRunResult(run)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		RunID:               run.ID,
+		Status:              run.Status,
+		Classification:      result.Classification.Classification,
+		Rationale:           result.Classification.Rationale,
+		MarkingSummary:      result.Classification.MarkingSummary,
+		AlternativeReadings: result.Classification.AlternativeReadings,
+		EnhancementApplied:  result.EnhancementApplied,
+		Confidence:          result.Confidence,
+		Pages:               result.Detections,
+	}
+
+	if result.Document != nil {
+		report.DocumentID = &result.Document.ID
+		report.DocumentName = result.Document.Name
+	}
+
+	if report.MarkingSummary == nil {
+		report.MarkingSummary = []string{}
+	}
+	if report.Pages == nil {
+		report.Pages = []PageDetection{}
+	}
+
+	return report, nil
+}
+
+// Markdown renders the report as a Markdown document suitable for pasting
+// into tickets.
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+
+	name := r.DocumentName
+	if name == "" {
+		name = "Unknown document"
+	}
+
+	fmt.Fprintf(&sb, "# Classification Report: %s\n\n", mdInline(name))
+	fmt.Fprintf(&sb, "- **Run:** `%s`\n", r.RunID)
+	if r.DocumentID != nil {
+		fmt.Fprintf(&sb, "- **Document:** `%s`\n", *r.DocumentID)
+	}
+	fmt.Fprintf(&sb, "- **Status:** %s\n", r.Status)
+	fmt.Fprintf(&sb, "- **Enhancement Applied:** %s\n", yesNo(r.EnhancementApplied))
+
+	sb.WriteString("\n## Classification\n\n")
+	fmt.Fprintf(&sb, "**%s**\n", mdInline(r.Classification))
+	if r.Rationale != "" {
+		fmt.Fprintf(&sb, "\n%s\n", mdInline(r.Rationale))
+	}
+
+	if len(r.MarkingSummary) > 0 {
+		sb.WriteString("\n### Marking Summary\n\n")
+		for _, m := range r.MarkingSummary {
+			fmt.Fprintf(&sb, "- %s\n", mdInline(m))
+		}
+	}
+
+	if len(r.AlternativeReadings) > 0 {
+		sb.WriteString("\n### Alternative Readings\n\n")
+		sb.WriteString("| Classification | Probability | Reason |\n")
+		sb.WriteString("| --- | ---: | --- |\n")
+		for _, alt := range r.AlternativeReadings {
+			fmt.Fprintf(&sb, "| %s | %.2f | %s |\n",
+				mdCell(alt.Classification), alt.Probability, mdCell(alt.Reason))
+		}
+	}
+
+	sb.WriteString("\n## Page Markings\n\n")
+	if len(r.Pages) == 0 {
+		sb.WriteString("No page detections recorded.\n")
+	} else {
+		sb.WriteString("| Page | Marking | Location | Legibility | Faded | Clarity | Enhanced |\n")
+		sb.WriteString("| ---: | --- | --- | ---: | --- | ---: | --- |\n")
+		for _, p := range r.Pages {
+			enhanced := yesNo(p.EnhancedImageID != nil)
+			if len(p.MarkingsFound) == 0 {
+				fmt.Fprintf(&sb, "| %d | _none detected_ | | | | %.2f | %s |\n",
+					p.PageNumber, p.ClarityScore, enhanced)
+				continue
+			}
+			for _, m := range p.MarkingsFound {
+				fmt.Fprintf(&sb, "| %d | %s | %s | %.2f | %s | %.2f | %s |\n",
+					p.PageNumber, mdCell(m.Text), mdCell(m.Location), m.Legibility,
+					yesNo(m.Faded), p.ClarityScore, enhanced)
+			}
+		}
+	}
+
+	sb.WriteString("\n## Confidence\n\n")
+	if r.Confidence == nil {
+		sb.WriteString("No confidence assessment recorded.\n")
+	} else {
+		fmt.Fprintf(&sb, "- **Overall Score:** %.2f\n", r.Confidence.OverallScore)
+		fmt.Fprintf(&sb, "- **Recommendation:** %s\n", mdInline(r.Confidence.Recommendation))
+		if len(r.Confidence.Factors) > 0 {
+			sb.WriteString("\n| Factor | Score | Weight | Description |\n")
+			sb.WriteString("| --- | ---: | ---: | --- |\n")
+			for _, f := range r.Confidence.Factors {
+				fmt.Fprintf(&sb, "| %s | %.2f | %.2f | %s |\n",
+					mdCell(f.Name), f.Score, f.Weight, mdCell(f.Description))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+type reporter struct{}
+
+func (reporter) JSON(run *workflows.Run) (any, error) {
+	return BuildReport(run)
+}
+
+func (reporter) Markdown(run *workflows.Run) (string, error) {
+	report, err := BuildReport(run)
+	if err != nil {
+		return "", err
+	}
+	return report.Markdown(), nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func mdInline(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func mdCell(s string) string {
+	return strings.ReplaceAll(mdInline(s), "|", `\|`)
+}
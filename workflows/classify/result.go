@@ -0,0 +1,62 @@
+package classify
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/google/uuid"
+)
+
+// runResult mirrors the subset of the final workflow state persisted as the
+// run result that is read back by reports and comparisons.
+type runResult struct {
+	Document *struct {
+		ID   uuid.UUID `json:"id"`
+		Name string    `json:"name"`
+	} `json:"document"`
+	Detections         []PageDetection       `json:"detections"`
+	Classification     *ClassificationResult `json:"classification"`
+	Confidence         *ConfidenceAssessment `json:"confidence"`
+	EnhancementApplied bool                  `json:"enhancement_applied"`
+}
+
+// ResultFromRun decodes the classification and confidence assessment stored
+// as the result of a classify run. The assessment is nil when the run did not
+// record one.
+//
+// Every error wraps workflows.ErrReportUnavailable. Runs of another workflow
+// also return ErrNotClassifyRun, and results that do not decode into the
+// classify types return ErrMalformedResult.
+func ResultFromRun(run *workflows.Run) (*ClassificationResult, *ConfidenceAssessment, error) {
+	result, err := decodeRunResult(run)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.Classification, result.Confidence, nil
+}
+
+// decodeRunResult decodes the stored result of a classify run, requiring a
+// classification.
+func decodeRunResult(run *workflows.Run) (*runResult, error) {
+	if run.WorkflowName != WorkflowName {
+		return nil, fmt.Errorf("%w: %w: run %s is %s",
+			workflows.ErrReportUnavailable, ErrNotClassifyRun, run.ID, run.WorkflowName)
+	}
+
+	if len(run.Result) == 0 {
+		return nil, fmt.Errorf("%w: run %s has no result", workflows.ErrReportUnavailable, run.ID)
+	}
+
+	var result runResult
+	if err := json.Unmarshal(run.Result, &result); err != nil {
+		return nil, fmt.Errorf("%w: %w: run %s: %v",
+			workflows.ErrReportUnavailable, ErrMalformedResult, run.ID, err)
+	}
+
+	if result.Classification == nil {
+		return nil, fmt.Errorf("%w: run %s has no classification", workflows.ErrReportUnavailable, run.ID)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,82 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	wf "github.com/JaimeStill/go-agents-orchestration/pkg/workflows"
+)
+
+// MaxStageAttempts is the largest number of attempts a stage may configure per item.
+const MaxStageAttempts = 10
+
+// RetryOptions configures per-item retries for a parallel stage. An item whose
+// processor fails is retried on its own, waiting Backoff before the first retry
+// and doubling the wait after each further failure. Zero values disable
+// retries: MaxAttempts of 0 or 1 runs each item once.
+type RetryOptions struct {
+	MaxAttempts int    `json:"max_attempts,omitempty"`
+	Backoff     string `json:"backoff,omitempty"`
+}
+
+// Validate checks that MaxAttempts is within [0, MaxStageAttempts] and that
+// Backoff, if set, is a non-negative duration.
+func (o RetryOptions) Validate() error {
+	if o.MaxAttempts < 0 || o.MaxAttempts > MaxStageAttempts {
+		return fmt.Errorf("%w: retry.max_attempts must be between 0 and %d", ErrInvalidStageOptions, MaxStageAttempts)
+	}
+
+	if o.Backoff != "" {
+		d, err := time.ParseDuration(o.Backoff)
+		if err != nil || d < 0 {
+			return fmt.Errorf("%w: retry.backoff must be a non-negative duration", ErrInvalidStageOptions)
+		}
+	}
+
+	return nil
+}
+
+// BackoffDuration parses and returns the initial retry delay.
+func (o RetryOptions) BackoffDuration() time.Duration {
+	d, _ := time.ParseDuration(o.Backoff)
+	return d
+}
+
+// WithRetry wraps processor so that a failing item is retried according to
+// opts before its error is returned. Retries stop as soon as ctx is done, and
+// the last processor error is returned annotated with the attempt count.
+func WithRetry[TItem, TResult any](opts RetryOptions, processor wf.TaskProcessor[TItem, TResult]) wf.TaskProcessor[TItem, TResult] {
+	if opts.MaxAttempts <= 1 {
+		return processor
+	}
+
+	backoff := opts.BackoffDuration()
+
+	return func(ctx context.Context, item TItem) (TResult, error) {
+		delay := backoff
+		for attempt := 1; ; attempt++ {
+			result, err := processor(ctx, item)
+			if err == nil {
+				return result, nil
+			}
+
+			if attempt >= opts.MaxAttempts || ctx.Err() != nil {
+				var zero TResult
+				return zero, fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+
+			if delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					var zero TResult
+					return zero, fmt.Errorf("after %d attempts: %w", attempt, err)
+				case <-timer.C:
+				}
+				delay *= 2
+			}
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package extract
+
+import "errors"
+
+// Domain errors for the extract-fields workflow.
+var (
+	ErrInvalidSchema    = errors.New("invalid extraction schema")
+	ErrParseResponse    = errors.New("failed to parse extraction response")
+	ErrExtractionFailed = errors.New("extraction failed")
+)
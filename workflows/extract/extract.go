@@ -0,0 +1,176 @@
+// Package extract implements a generic structured extraction workflow. It
+// runs vision analysis on each document page with a caller-supplied JSON
+// schema as the output format, validates each page's result against the
+// schema, and aggregates the valid results.
+package extract
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/JaimeStill/agent-lab/internal/profiles"
+	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
+	wf "github.com/JaimeStill/go-agents-orchestration/pkg/workflows"
+)
+
+// PageExtraction contains the data extracted from a single document page.
+// Errors lists schema violations when Valid is false.
+type PageExtraction struct {
+	PageNumber int       `json:"page_number"`
+	ImageID    uuid.UUID `json:"image_id"`
+	Data       any       `json:"data"`
+	Valid      bool      `json:"valid"`
+	Errors     []string  `json:"errors,omitempty"`
+}
+
+// ExtractionResult is the aggregate of all page extractions. Data merges the
+// valid pages; Pages holds every page result in page order.
+type ExtractionResult struct {
+	Data         any              `json:"data"`
+	Pages        []PageExtraction `json:"pages"`
+	ValidPages   int              `json:"valid_pages"`
+	InvalidPages []int            `json:"invalid_pages,omitempty"`
+}
+
+func init() {
+	workflows.Register("extract-fields", factory, "Extracts structured data matching a JSON schema from document pages using vision analysis")
+	workflows.RegisterParams("extract-fields", workflows.ProfileParams(map[string]*openapi.Schema{
+		"document_id": {Type: "string", Format: "uuid", Description: "Document to extract from"},
+		"schema":      {Type: "object", Description: "JSON schema the extracted data must match"},
+		"prompt":      {Type: "string", Description: "Extraction instructions"},
+	}, "document_id", "schema", "prompt"))
+	workflows.RegisterStages("extract-fields", DefaultProfile, workflows.AgentStage{Name: "extract"})
+}
+
+func logNodeTiming(logger *slog.Logger, nodeName string, start time.Time) {
+	logger.Info("node completed", "node", nodeName, "duration", time.Since(start).String())
+}
+
+func factory(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
+	profile, err := workflows.LoadProfile(ctx, runtime, params, DefaultProfile())
+	if err != nil {
+		return state.State{}, err
+	}
+
+	if err := graph.AddNode("init", classify.InitNode(runtime)); err != nil {
+		return state.State{}, err
+	}
+
+	if err := graph.AddNode("extract", extractNode(profile, runtime)); err != nil {
+		return state.State{}, err
+	}
+
+	if err := graph.AddEdge("init", "extract", nil); err != nil {
+		return state.State{}, err
+	}
+
+	if err := graph.SetEntryPoint("init"); err != nil {
+		return state.State{}, err
+	}
+
+	if err := graph.SetExitPoint("extract"); err != nil {
+		return state.State{}, err
+	}
+
+	initialState := state.New(nil)
+	for k, v := range params {
+		initialState = initialState.Set(k, v)
+	}
+
+	return initialState, nil
+}
+
+func extractNode(profile *profiles.ProfileWithStages, runtime *workflows.Runtime) state.StateNode {
+	return state.NewFunctionNode(func(ctx context.Context, s state.State) (state.State, error) {
+		start := time.Now()
+		defer logNodeTiming(runtime.Logger(), "extract", start)
+
+		stage := profile.Stage("extract")
+
+		agentID, token, err := workflows.ExtractAgentParams(s, stage)
+		if err != nil {
+			return s, err
+		}
+
+		schemaVal, _ := s.Get("schema")
+		schema, err := ParseSchema(schemaVal)
+		if err != nil {
+			return s, err
+		}
+
+		promptVal, _ := s.Get("prompt")
+		instructions, _ := promptVal.(string)
+		if strings.TrimSpace(instructions) == "" {
+			return s, fmt.Errorf("prompt is required")
+		}
+
+		parallel, err := classify.StageParallelOptions(stage)
+		if err != nil {
+			return s, err
+		}
+		cfg := parallel.ParallelConfig()
+
+		pages, ok := s.Get("page_images")
+		if !ok {
+			return s, fmt.Errorf("page_images not found in state")
+		}
+		pageImages := pages.([]classify.PageImage)
+
+		opts := map[string]any{}
+		if stage.SystemPrompt != nil {
+			opts["system_prompt"] = *stage.SystemPrompt
+		}
+
+		processor := func(ctx context.Context, img classify.PageImage) (PageExtraction, error) {
+			data, contentType, err := runtime.Images().Data(ctx, img.ImageID)
+			if err != nil {
+				return PageExtraction{}, fmt.Errorf("%w: failed to retrieve image data: %v", ErrExtractionFailed, err)
+			}
+
+			prompt := BuildExtractionPrompt(instructions, schema, img.PageNumber)
+
+			resp, err := runtime.Agents().Vision(ctx, agentID, prompt, []string{classify.BuildDataURI(data, contentType)}, opts, token)
+			if err != nil {
+				return PageExtraction{}, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+			}
+
+			page, err := ParsePageResponse(resp.Content(), schema)
+			if err != nil {
+				return PageExtraction{}, err
+			}
+
+			page.PageNumber = img.PageNumber
+			page.ImageID = img.ImageID
+
+			return page, nil
+		}
+
+		result, err := wf.ProcessParallel(ctx, cfg, pageImages, classify.WithRetry(parallel.Retry, processor), nil)
+		if err != nil {
+			return s, fmt.Errorf("parallel extraction failed: %w", err)
+		}
+
+		s = s.Set("extraction", Aggregate(result.Results))
+
+		return s, nil
+	})
+}
+
+// BuildExtractionPrompt combines the user's instructions with the schema the
+// response must match for one page.
+func BuildExtractionPrompt(instructions string, schema *Schema, pageNumber int) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Extract data from page %d of this document.\n\n", pageNumber)
+	sb.WriteString(strings.TrimSpace(instructions))
+	sb.WriteString("\n\nOUTPUT FORMAT: Respond with ONLY a JSON value matching this JSON schema:\n")
+	sb.WriteString(schema.String())
+	return sb.String()
+}
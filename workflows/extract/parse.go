@@ -0,0 +1,88 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/JaimeStill/agent-lab/workflows/classify"
+)
+
+// ParsePageResponse parses an LLM response into a PageExtraction, using the
+// classify workflow's JSON extraction so that bare JSON and markdown code
+// blocks are both accepted. The data is validated against schema; a response
+// that parses but violates the schema is returned with Valid false and the
+// violations in Errors rather than as an error.
+func ParsePageResponse(content string, schema *Schema) (PageExtraction, error) {
+	raw, ok := classify.ExtractJSON(content)
+	if !ok {
+		return PageExtraction{}, fmt.Errorf("%w: could not find extraction JSON", ErrParseResponse)
+	}
+
+	var data any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return PageExtraction{}, fmt.Errorf("%w: %v", ErrParseResponse, err)
+	}
+
+	errs := schema.Validate(data)
+	return PageExtraction{
+		Data:   data,
+		Valid:  len(errs) == 0,
+		Errors: errs,
+	}, nil
+}
+
+// Aggregate combines per-page extractions into an ExtractionResult. Pages are
+// ordered by page number and the data of each valid page is merged in that
+// order: objects merge by property, arrays concatenate, and otherwise the
+// first non-null value wins. Invalid pages are kept in Pages and listed in
+// InvalidPages but do not contribute to Data.
+func Aggregate(pages []PageExtraction) ExtractionResult {
+	sorted := make([]PageExtraction, len(pages))
+	copy(sorted, pages)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PageNumber < sorted[j].PageNumber
+	})
+
+	result := ExtractionResult{Pages: sorted}
+	for _, p := range sorted {
+		if !p.Valid {
+			result.InvalidPages = append(result.InvalidPages, p.PageNumber)
+			continue
+		}
+		result.ValidPages++
+		result.Data = mergeValues(result.Data, p.Data)
+	}
+
+	return result
+}
+
+func mergeValues(into, from any) any {
+	if into == nil {
+		return from
+	}
+
+	switch dst := into.(type) {
+	case map[string]any:
+		src, ok := from.(map[string]any)
+		if !ok {
+			return dst
+		}
+		merged := make(map[string]any, len(dst)+len(src))
+		for k, v := range dst {
+			merged[k] = v
+		}
+		for k, v := range src {
+			merged[k] = mergeValues(merged[k], v)
+		}
+		return merged
+	case []any:
+		src, ok := from.([]any)
+		if !ok {
+			return dst
+		}
+		return append(append([]any{}, dst...), src...)
+	default:
+		return dst
+	}
+}
@@ -0,0 +1,27 @@
+package extract
+
+import "github.com/JaimeStill/agent-lab/internal/profiles"
+
+// ExtractionSystemPrompt instructs the vision model to pull structured data
+// from a document page. The JSON schema and the user's instructions are
+// supplied in each page prompt.
+const ExtractionSystemPrompt = `You are a document data extraction specialist. Read the provided document page image and extract the requested fields.
+
+INSTRUCTIONS:
+- Follow the extraction instructions in the request
+- Respond with ONLY a JSON value matching the JSON schema given in the request
+- Copy values exactly as they appear on the page; do not infer or invent values
+- Use null for fields that do not appear on this page
+- JSON response only; no preamble or dialog`
+
+// DefaultProfile returns the hardcoded default profile for the extract-fields
+// workflow. It defines stages for init (no LLM) and extract (vision analysis).
+func DefaultProfile() *profiles.ProfileWithStages {
+	initPrompt := ""
+	extractPrompt := ExtractionSystemPrompt
+
+	return profiles.NewProfileWithStages(
+		profiles.ProfileStage{StageName: "init", SystemPrompt: &initPrompt},
+		profiles.ProfileStage{StageName: "extract", SystemPrompt: &extractPrompt},
+	)
+}
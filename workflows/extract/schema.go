@@ -0,0 +1,204 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Schema is the subset of JSON Schema used to validate extracted data: type,
+// properties, required, additionalProperties (as a boolean), items, and enum.
+// Other keywords, such as description, are carried to the model in the prompt
+// but not enforced.
+type Schema struct {
+	Type                 schemaType         `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+
+	source []byte
+}
+
+var schemaTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// schemaType holds the JSON Schema type keyword, which may be a single type
+// name or a list of them.
+type schemaType []string
+
+func (t *schemaType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = schemaType{name}
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("type must be a string or array of strings")
+	}
+	*t = names
+	return nil
+}
+
+// ParseSchema reads a schema parameter, given either as a JSON string or as
+// a decoded JSON object, and checks that its type keywords are known.
+func ParseSchema(value any) (*Schema, error) {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil, fmt.Errorf("%w: schema is required", ErrInvalidSchema)
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+		}
+		data = b
+	}
+
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	if err := s.check("$"); err != nil {
+		return nil, err
+	}
+
+	s.source = data
+	return &s, nil
+}
+
+// String returns the schema JSON as supplied, for inclusion in prompts.
+func (s *Schema) String() string {
+	return string(s.source)
+}
+
+func (s *Schema) check(path string) error {
+	for _, t := range s.Type {
+		if !slices.Contains(schemaTypes, t) {
+			return fmt.Errorf("%w: %s: unknown type %q", ErrInvalidSchema, path, t)
+		}
+	}
+
+	for name, prop := range s.Properties {
+		if prop == nil {
+			return fmt.Errorf("%w: %s.%s: property schema must be an object", ErrInvalidSchema, path, name)
+		}
+		if err := prop.check(path + "." + name); err != nil {
+			return err
+		}
+	}
+
+	if s.Items != nil {
+		return s.Items.check(path + "[]")
+	}
+
+	return nil
+}
+
+// Validate checks v, a value decoded from JSON, against the schema and
+// returns a description of each violation. An empty result means v is valid.
+func (s *Schema) Validate(v any) []string {
+	var errs []string
+	s.validate("$", v, &errs)
+	return errs
+}
+
+func (s *Schema) validate(path string, v any, errs *[]string) {
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return matchesType(t, v) }) {
+		*errs = append(*errs, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(s.Type, " or "), typeOf(v)))
+		return
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+		*errs = append(*errs, fmt.Sprintf("%s: value not in enum", path))
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				*errs = append(*errs, fmt.Sprintf("%s: missing required property %q", path, name))
+			}
+		}
+
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			prop, ok := s.Properties[k]
+			if !ok {
+				if strings.TrimSpace(string(s.AdditionalProperties)) == "false" {
+					*errs = append(*errs, fmt.Sprintf("%s: unexpected property %q", path, k))
+				}
+				continue
+			}
+			prop.validate(path+"."+k, val[k], errs)
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range val {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	}
+}
+
+func matchesType(t string, v any) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
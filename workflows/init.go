@@ -2,6 +2,7 @@ package workflows
 
 import (
 	_ "github.com/JaimeStill/agent-lab/workflows/classify"
+	_ "github.com/JaimeStill/agent-lab/workflows/extract"
 	_ "github.com/JaimeStill/agent-lab/workflows/reasoning"
 	_ "github.com/JaimeStill/agent-lab/workflows/summarize"
 )
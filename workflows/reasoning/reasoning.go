@@ -6,11 +6,20 @@ import (
 
 	"github.com/JaimeStill/agent-lab/internal/profiles"
 	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
 )
 
 func init() {
 	workflows.Register("reasoning", factory, "Multi-step reasoning workflow that analyzes problems")
+	workflows.RegisterParams("reasoning", workflows.ProfileParams(map[string]*openapi.Schema{
+		"problem": {Type: "string", Description: "Problem to reason about"},
+	}, "problem"))
+	workflows.RegisterStages("reasoning", DefaultProfile,
+		workflows.AgentStage{Name: "analyze"},
+		workflows.AgentStage{Name: "reason"},
+		workflows.AgentStage{Name: "conclude"},
+	)
 }
 
 func factory(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {
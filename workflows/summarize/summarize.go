@@ -6,11 +6,16 @@ import (
 
 	"github.com/JaimeStill/agent-lab/internal/profiles"
 	"github.com/JaimeStill/agent-lab/internal/workflows"
+	"github.com/JaimeStill/agent-lab/pkg/openapi"
 	"github.com/JaimeStill/go-agents-orchestration/pkg/state"
 )
 
 func init() {
 	workflows.Register("summarize", factory, "Summarizes input text using an AI agent")
+	workflows.RegisterParams("summarize", workflows.ProfileParams(map[string]*openapi.Schema{
+		"text": {Type: "string", Description: "Text to summarize"},
+	}, "text"))
+	workflows.RegisterStages("summarize", DefaultProfile, workflows.AgentStage{Name: "summarize"})
 }
 
 func factory(ctx context.Context, graph state.StateGraph, runtime *workflows.Runtime, params map[string]any) (state.State, error) {